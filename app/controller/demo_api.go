@@ -8,6 +8,9 @@ package controller
 //   - request.BindUri()  —— 一步完成路径参数绑定 + 校验
 //   - request.BindQuery()—— 一步完成 Query 参数绑定 + 校验
 //   - middleware.GetLogEntry().AddField() —— 在 handler 里追加字段到当前请求日志
+//   - service.UserService —— 控制器依赖服务接口，FX 注入实现，单元测试可替换为 mock
+//   - request.Context()  —— 向服务层传递请求上下文，客户端断开或超时后查询随之取消
+//   - resource.New()     —— 显式声明输出字段，不直接输出服务层/模型对象
 //
 // 路由：
 //   GET    /demo/api/users       列表（支持 ?keyword= 过滤）
@@ -17,42 +20,22 @@ package controller
 
 import (
 	"fmt"
-	"sync"
-	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/app/service"
 	"github.com/gorilla-go/go-framework/pkg/middleware"
 	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/resource"
 	"github.com/gorilla-go/go-framework/pkg/response"
 	"github.com/gorilla-go/go-framework/pkg/router"
 	"go.uber.org/fx"
 )
 
-// ---- 演示用内存数据 ----
-
-type demoUser struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
-}
-
-var (
-	demoStore   sync.Map
-	demoIDSeq   uint64 = 2
-	demoStoreMu sync.Mutex
-)
-
-func init() {
-	demoStore.Store(uint(1), &demoUser{ID: 1, Name: "张三", Email: "zhangsan@example.com", Role: "user"})
-	demoStore.Store(uint(2), &demoUser{ID: 2, Name: "李四", Email: "lisi@example.com", Role: "admin"})
-}
-
 // ---- 控制器 ----
 
 type DemoAPIController struct {
 	fx.In
+	Users service.UserService // 依赖接口而非具体实现，测试时可注入替身
 }
 
 func (d *DemoAPIController) Annotation(rb *router.RouteBuilder) {
@@ -61,9 +44,19 @@ func (d *DemoAPIController) Annotation(rb *router.RouteBuilder) {
 	api.GET("/users", d.ListUsers, "demo@listUsers")
 	api.GET("/users/:id", d.GetUser, "demo@getUser")
 	api.POST("/users", d.CreateUser, "demo@createUser")
-	api.DELETE("/users/:id", d.DeleteUser, "demo@deleteUser")
+	api.DELETE("/users/:id", d.DeleteUser, "demo@deleteUser", router.Rules{"id": "uint|min:1"})
 }
 
+// userResource 用户的 API 输出字段
+var userResource = resource.New(func(_ *gin.Context, u *service.User) resource.Fields {
+	return resource.Fields{
+		"id":    u.ID,
+		"name":  u.Name,
+		"email": u.Email,
+		"role":  u.Role,
+	}
+})
+
 // ---- ListUsers: 演示 BindQuery ----
 
 type listUsersQuery struct {
@@ -79,20 +72,16 @@ func (d *DemoAPIController) ListUsers(c *gin.Context) error {
 		return err
 	}
 
-	var result []*demoUser
-	demoStore.Range(func(_, v any) bool {
-		u := v.(*demoUser)
-		if query.Keyword != "" && u.Name != query.Keyword {
-			return true
-		}
-		if query.Role != "" && u.Role != query.Role {
-			return true
-		}
-		result = append(result, u)
-		return true
-	})
-
-	response.SuccessD(c, fmt.Sprintf("共 %d 条", len(result)), result)
+	result, err := d.Users.List(request.Context(c), service.UserFilter{Keyword: query.Keyword, Role: query.Role})
+	if err != nil {
+		return err
+	}
+
+	data, err := userResource.Collection(c, result)
+	if err != nil {
+		return err
+	}
+	response.SuccessD(c, fmt.Sprintf("共 %d 条", len(result)), data)
 	return nil
 }
 
@@ -111,16 +100,20 @@ func (d *DemoAPIController) GetUser(c *gin.Context) error {
 		return err
 	}
 
-	val, ok := demoStore.Load(uri.ID)
-	if !ok {
-		// 直接 return error，H() 会自动调用 Fail()
-		return errors.NewNotFound(fmt.Sprintf("用户 %d 不存在", uri.ID), nil)
+	user, err := d.Users.Get(request.Context(c), uri.ID)
+	if err != nil {
+		// 直接 return error（用户不存在时为 404 AppError），H() 会自动调用 Fail()
+		return err
 	}
 
 	// 向当前请求日志追加业务字段，无需修改 Logger 中间件
 	middleware.GetLogEntry(c).AddField("queried_user_id", uri.ID)
 
-	response.Success(c, val)
+	data, err := userResource.Item(c, user)
+	if err != nil {
+		return err
+	}
+	response.Success(c, data)
 	return nil
 }
 
@@ -140,44 +133,30 @@ func (d *DemoAPIController) CreateUser(c *gin.Context) error {
 		return err
 	}
 
-	if req.Role == "" {
-		req.Role = "user"
-	}
-
-	newID := uint(atomic.AddUint64(&demoIDSeq, 1))
-	user := &demoUser{
-		ID:    newID,
-		Name:  req.Name,
-		Email: req.Email,
-		Role:  req.Role,
+	user, err := d.Users.Create(request.Context(c), req.Name, req.Email, req.Role)
+	if err != nil {
+		return err
 	}
-	demoStore.Store(newID, user)
 
-	middleware.GetLogEntry(c).AddField("created_user_id", newID)
+	middleware.GetLogEntry(c).AddField("created_user_id", user.ID)
 
 	response.SuccessD(c, "用户创建成功", user)
 	return nil
 }
 
-// ---- DeleteUser: 演示 H() + BindUri ----
-
-type deleteUserUri struct {
-	ID uint `uri:"id" binding:"required"`
-}
+// ---- DeleteUser: 演示 H() + router.Rules ----
 
 // DeleteUser DELETE /demo/api/users/:id
+// 演示 router.Rules —— 路由声明 "uint|min:1"，非法 id 在进入处理器前即返回 400，处理器直接读取已转换的值
 func (d *DemoAPIController) DeleteUser(c *gin.Context) error {
-	var uri deleteUserUri
-	if err := request.BindUri(c, &uri); err != nil {
-		return err
-	}
+	id, _ := router.Param[uint](c, "id")
 
-	if _, ok := demoStore.LoadAndDelete(uri.ID); !ok {
-		return errors.NewNotFound(fmt.Sprintf("用户 %d 不存在", uri.ID), nil)
+	if err := d.Users.Delete(request.Context(c), id); err != nil {
+		return err
 	}
 
-	middleware.GetLogEntry(c).AddField("deleted_user_id", uri.ID)
+	middleware.GetLogEntry(c).AddField("deleted_user_id", id)
 
-	response.SuccessD(c, "删除成功", gin.H{"id": uri.ID})
+	response.SuccessD(c, "删除成功", gin.H{"id": id})
 	return nil
 }
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/app/service"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/router"
+)
+
+// fakeUserService 手写的 UserService 替身，控制器测试无需数据库
+type fakeUserService struct {
+	users   map[uint]*service.User
+	deleted []uint
+}
+
+func (f *fakeUserService) Get(_ context.Context, id uint) (*service.User, error) {
+	if u, ok := f.users[id]; ok {
+		return u, nil
+	}
+	return nil, errors.NewNotFound(fmt.Sprintf("用户 %d 不存在", id), nil)
+}
+
+func (f *fakeUserService) List(context.Context, service.UserFilter) ([]*service.User, error) {
+	var list []*service.User
+	for _, u := range f.users {
+		list = append(list, u)
+	}
+	return list, nil
+}
+
+func (f *fakeUserService) Create(_ context.Context, name, email, role string) (*service.User, error) {
+	return &service.User{ID: 99, Name: name, Email: email, Role: role}, nil
+}
+
+func (f *fakeUserService) Delete(_ context.Context, id uint) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func newDemoAPIEngine(users service.UserService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	ctl := &DemoAPIController{Users: users}
+	ctl.Annotation(router.NewRouteBuilder(engine))
+	return engine
+}
+
+// TestDemoAPIGetUser 控制器通过注入的服务读取用户
+func TestDemoAPIGetUser(t *testing.T) {
+	fake := &fakeUserService{users: map[uint]*service.User{7: {ID: 7, Name: "王五"}}}
+	engine := newDemoAPIEngine(fake)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/demo/api/users/7", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "王五") {
+		t.Errorf("期望返回用户，得到 %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/demo/api/users/8", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期望 404，得到 %d", w.Code)
+	}
+}
+
+// TestDemoAPIDeleteUser 删除请求的 id 经路由规则转换后传给服务
+func TestDemoAPIDeleteUser(t *testing.T) {
+	fake := &fakeUserService{}
+	engine := newDemoAPIEngine(fake)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/demo/api/users/3", nil))
+	if w.Code != http.StatusOK || len(fake.deleted) != 1 || fake.deleted[0] != 3 {
+		t.Errorf("code=%d deleted=%v", w.Code, fake.deleted)
+	}
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/demo/api/users/0", nil))
+	if w.Code != http.StatusBadRequest || len(fake.deleted) != 1 {
+		t.Errorf("非法 id 不应调用服务: code=%d deleted=%v", w.Code, fake.deleted)
+	}
+}
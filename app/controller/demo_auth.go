@@ -8,6 +8,7 @@ package controller
 //   - middleware.RoleMiddleware()   —— 角色验证，可叠加在 JWT 中间件之后
 //   - middleware.GenerateToken()   —— 生成 HS256 JWT Token
 //   - middleware.GetClaimsFromContext() / GetUserIDFromContext() —— 从 Context 读用户信息
+//   - controller.Base               —— 嵌入基类，自动注入 Config，d.JSON() 返回统一响应
 //
 // 路由：
 //   POST /demo/auth/login       登录，返回 JWT Token（不需要认证）
@@ -16,18 +17,16 @@ package controller
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/controller"
 	"github.com/gorilla-go/go-framework/pkg/errors"
 	"github.com/gorilla-go/go-framework/pkg/middleware"
 	"github.com/gorilla-go/go-framework/pkg/request"
 	"github.com/gorilla-go/go-framework/pkg/response"
 	"github.com/gorilla-go/go-framework/pkg/router"
-	"go.uber.org/fx"
 )
 
 type DemoAuthController struct {
-	fx.In
-	Config *config.Config
+	controller.Base
 }
 
 func (d *DemoAuthController) Annotation(rb *router.RouteBuilder) {
@@ -104,13 +103,12 @@ func (d *DemoAuthController) Profile(c *gin.Context) error {
 		return errors.NewUnauthorized("无法获取用户信息", nil)
 	}
 
-	response.Success(c, gin.H{
+	return d.JSON(c, gin.H{
 		"user_id":  claims.UserID,
 		"username": claims.Username,
 		"role":     claims.Role,
 		"expires":  claims.ExpiresAt,
 	})
-	return nil
 }
 
 // AdminOnly GET /demo/auth/admin-only
@@ -118,10 +116,9 @@ func (d *DemoAuthController) Profile(c *gin.Context) error {
 func (d *DemoAuthController) AdminOnly(c *gin.Context) error {
 	userID, _ := middleware.GetUserIDFromContext(c)
 
-	response.Success(c, gin.H{
+	return d.JSON(c, gin.H{
 		"message": "欢迎，管理员！",
 		"user_id": userID,
 		"tip":     "只有 role=admin 的 Token 才能访问此接口",
 	})
-	return nil
 }
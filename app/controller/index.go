@@ -2,13 +2,12 @@ package controller
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/controller"
 	"github.com/gorilla-go/go-framework/pkg/router"
-	"github.com/gorilla-go/go-framework/pkg/template"
-	"go.uber.org/fx"
 )
 
 type IndexController struct {
-	fx.In
+	controller.Base
 }
 
 func (i *IndexController) Annotation(rb *router.RouteBuilder) {
@@ -257,6 +256,5 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 		},
 	}
 
-	template.RenderL(ctx.Writer, "index", data)
-	return nil
+	return i.View(ctx, "index", data)
 }
@@ -0,0 +1,87 @@
+package controller
+
+// SessionAdminController 登录会话管理接口（需启用 session.index）
+//
+// 路由（需要 JWT + role=admin）：
+//   GET    /admin/sessions/:user_id      列出用户的活跃会话
+//   DELETE /admin/sessions/:user_id      撤销用户的全部会话（在所有设备上强制下线）
+//   DELETE /admin/sessions/:user_id/:id  撤销指定会话（不存在时返回 404）
+//
+// 会话由 session.Login 登记，被撤销的会话在下一次请求时由 Track 中间件清空。
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/controller"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/router"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+type SessionAdminController struct {
+	controller.Base
+	Sessions session.Index // 未启用 session.index 时为 nil
+}
+
+func (s *SessionAdminController) Annotation(rb *router.RouteBuilder) {
+	admin := rb.Group("/admin/sessions",
+		middleware.JWTMiddleware(&s.Config.JWT),
+		middleware.RoleMiddleware("admin"),
+	)
+	rules := router.Rules{"user_id": "uint|min:1"}
+	admin.GET("/:user_id", s.List, "session@list", rules)
+	admin.DELETE("/:user_id", s.RevokeAll, "session@revokeAll", rules)
+	admin.DELETE("/:user_id/:id", s.Revoke, "session@revoke", rules)
+}
+
+// List GET /admin/sessions/:user_id
+func (s *SessionAdminController) List(c *gin.Context) error {
+	if err := s.enabled(); err != nil {
+		return err
+	}
+	userID, _ := router.Param[uint](c, "user_id")
+	list, err := s.Sessions.List(s.Context(c), userID)
+	if err != nil {
+		return errors.NewInternalServerError("读取会话失败", err)
+	}
+	return s.JSON(c, list)
+}
+
+// Revoke DELETE /admin/sessions/:user_id/:id
+func (s *SessionAdminController) Revoke(c *gin.Context) error {
+	if err := s.enabled(); err != nil {
+		return err
+	}
+	userID, _ := router.Param[uint](c, "user_id")
+	ok, err := s.Sessions.Revoke(s.Context(c), userID, c.Param("id"))
+	if err != nil {
+		return errors.NewInternalServerError("撤销会话失败", err)
+	}
+	if !ok {
+		return errors.NewNotFound("会话不存在或已撤销", nil)
+	}
+	s.Logger(c).Infow("撤销会话", "user_id", userID, "session_id", c.Param("id"))
+	return s.JSON(c, gin.H{"revoked": 1})
+}
+
+// RevokeAll DELETE /admin/sessions/:user_id
+func (s *SessionAdminController) RevokeAll(c *gin.Context) error {
+	if err := s.enabled(); err != nil {
+		return err
+	}
+	userID, _ := router.Param[uint](c, "user_id")
+	n, err := s.Sessions.RevokeAll(s.Context(c), userID)
+	if err != nil {
+		return errors.NewInternalServerError("撤销会话失败", err)
+	}
+	s.Logger(c).Infow("撤销用户全部会话", "user_id", userID, "count", n)
+	return s.JSON(c, gin.H{"revoked": n})
+}
+
+// enabled 未启用会话索引时返回错误
+func (s *SessionAdminController) enabled() error {
+	if s.Sessions == nil {
+		return errors.New(errors.ServiceUnavailable, "会话索引未启用（session.index: false）", nil)
+	}
+	return nil
+}
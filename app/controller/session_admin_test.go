@@ -0,0 +1,48 @@
+package controller_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/testkit"
+)
+
+// TestSessionAdmin 管理员查看与撤销用户会话
+func TestSessionAdmin(t *testing.T) {
+	app := testkit.New(t, testkit.WithConfig(func(cfg *config.Config) { cfg.Session.Index = true }))
+	idx := session.DefaultIndex()
+	if idx == nil {
+		t.Fatal("启用 session.index 后应设置全局索引")
+	}
+	ctx := context.Background()
+	_ = idx.Add(ctx, session.Info{ID: "s1", UserID: 7})
+	_ = idx.Add(ctx, session.Info{ID: "s2", UserID: 7})
+
+	admin := app.ActingAs(1, "root", "admin")
+	app.ActingAs(2, "bob", "user").GET("/admin/sessions/7").AssertStatus(http.StatusForbidden)
+	admin.GET("/admin/sessions/0").AssertStatus(http.StatusBadRequest)
+
+	admin.GET("/admin/sessions/7").
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.0.id", "s1").
+		AssertJSON("data.1.id", "s2")
+
+	admin.DELETE("/admin/sessions/7/s1").AssertStatus(http.StatusOK).AssertJSON("data.revoked", 1)
+	admin.DELETE("/admin/sessions/7/s1").AssertStatus(http.StatusNotFound)
+	admin.DELETE("/admin/sessions/8/s2").AssertStatus(http.StatusNotFound)
+	admin.GET("/admin/sessions/7").AssertJSON("data.0.id", "s2")
+
+	admin.DELETE("/admin/sessions/7").AssertStatus(http.StatusOK).AssertJSON("data.revoked", 1)
+	if list, _ := idx.List(ctx, 7); len(list) != 0 {
+		t.Errorf("撤销全部后仍有会话 %v", list)
+	}
+}
+
+// TestSessionAdminDisabled 未启用会话索引时返回 503
+func TestSessionAdminDisabled(t *testing.T) {
+	app := testkit.New(t)
+	app.ActingAs(1, "root", "admin").GET("/admin/sessions/7").AssertStatus(http.StatusServiceUnavailable)
+}
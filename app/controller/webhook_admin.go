@@ -0,0 +1,274 @@
+package controller
+
+// WebhookAdminController 出站 Webhook 管理接口（需启用 webhook.enabled）
+//
+// 路由（需要 JWT + role=admin）：
+//   GET    /admin/webhooks/endpoints                列出端点
+//   POST   /admin/webhooks/endpoints                注册端点，响应中包含签名密钥（仅此一次）
+//   PUT    /admin/webhooks/endpoints/:id/active     启用或停用端点
+//   DELETE /admin/webhooks/endpoints/:id            删除端点
+//   GET    /admin/webhooks/deliveries               投递日志（filter[status]、filter[endpoint_id]、filter[event]、q、分页）
+//   GET    /admin/webhooks/deliveries/:id           投递详情与每次请求的记录
+//   POST   /admin/webhooks/deliveries/:id/replay    重放失败的投递，返回重放后的状态
+//   POST   /admin/webhooks/deliveries/replay        重放全部失败的投递
+
+import (
+	stderrors "errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/controller"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/resource"
+	"github.com/gorilla-go/go-framework/pkg/router"
+	"github.com/gorilla-go/go-framework/pkg/webhook"
+)
+
+type WebhookAdminController struct {
+	controller.Base
+}
+
+func (w *WebhookAdminController) Annotation(rb *router.RouteBuilder) {
+	admin := rb.Group("/admin/webhooks",
+		middleware.JWTMiddleware(&w.Config.JWT),
+		middleware.RoleMiddleware("admin"),
+	)
+	rules := router.Rules{"id": "uint|min:1"}
+	admin.GET("/endpoints", w.Endpoints, "webhook@endpoints")
+	admin.POST("/endpoints", w.CreateEndpoint, "webhook@createEndpoint")
+	admin.PUT("/endpoints/:id/active", w.SetActive, "webhook@setActive", rules)
+	admin.DELETE("/endpoints/:id", w.DeleteEndpoint, "webhook@deleteEndpoint", rules)
+	admin.GET("/deliveries", w.Deliveries, "webhook@deliveries")
+	admin.POST("/deliveries/replay", w.ReplayAll, "webhook@replayAll")
+	admin.GET("/deliveries/:id", w.Delivery, "webhook@delivery", rules)
+	admin.POST("/deliveries/:id/replay", w.Replay, "webhook@replay", rules)
+}
+
+// endpointResource 端点的 API 输出字段（不含签名密钥）
+var endpointResource = resource.New(func(_ *gin.Context, e *webhook.Endpoint) resource.Fields {
+	return resource.Fields{
+		"id":          e.ID,
+		"url":         e.URL,
+		"events":      e.Events,
+		"description": e.Description,
+		"active":      e.Active,
+		"created_at":  e.CreatedAt,
+	}
+})
+
+// deliveryResource 投递日志的 API 输出字段
+var deliveryResource = resource.New(func(_ *gin.Context, d *webhook.Delivery) resource.Fields {
+	return resource.Fields{
+		"id":              d.ID,
+		"endpoint_id":     d.EndpointID,
+		"event":           d.Event,
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"response_status": d.ResponseStatus,
+		"last_error":      d.LastError,
+		"next_attempt_at": d.NextAttemptAt,
+		"created_at":      d.CreatedAt,
+		"delivered_at":    d.DeliveredAt,
+	}
+})
+
+// createEndpointRequest 注册端点的请求体
+type createEndpointRequest struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required"`
+	Secret      string   `json:"secret" binding:"omitempty,min=16"`
+	Description string   `json:"description" binding:"max=255"`
+}
+
+// Endpoints GET /admin/webhooks/endpoints
+func (w *WebhookAdminController) Endpoints(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	list, err := m.Endpoints(w.Context(c))
+	if err != nil {
+		return errors.NewInternalServerError("读取 webhook 端点失败", err)
+	}
+	items := make([]*webhook.Endpoint, len(list))
+	for i := range list {
+		items[i] = &list[i]
+	}
+	out, err := endpointResource.Collection(c, items)
+	if err != nil {
+		return err
+	}
+	return w.JSON(c, out)
+}
+
+// CreateEndpoint POST /admin/webhooks/endpoints
+func (w *WebhookAdminController) CreateEndpoint(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	var req createEndpointRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		return err
+	}
+	ep := &webhook.Endpoint{URL: req.URL, Events: req.Events, Secret: req.Secret, Description: req.Description}
+	if err := m.Register(w.Context(c), ep); err != nil {
+		return errors.NewValidationError(err.Error(), err)
+	}
+	out, err := endpointResource.Item(c, ep)
+	if err != nil {
+		return err
+	}
+	out["secret"] = ep.Secret
+	w.Logger(c).Infow("注册 webhook 端点", "id", ep.ID, "url", ep.URL, "events", ep.Events)
+	return w.JSON(c, out)
+}
+
+// SetActive PUT /admin/webhooks/endpoints/:id/active
+func (w *WebhookAdminController) SetActive(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	var req struct {
+		Active *bool `json:"active" binding:"required"`
+	}
+	if err := request.BindJSON(c, &req); err != nil {
+		return err
+	}
+	id := paramID(c)
+	if err := m.SetActive(w.Context(c), id, *req.Active); err != nil {
+		return notFoundOr(err, "更新 webhook 端点失败")
+	}
+	w.Logger(c).Infow("更新 webhook 端点状态", "id", id, "active", *req.Active)
+	return w.JSON(c, gin.H{"id": id, "active": *req.Active})
+}
+
+// DeleteEndpoint DELETE /admin/webhooks/endpoints/:id
+func (w *WebhookAdminController) DeleteEndpoint(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	id := paramID(c)
+	if err := m.RemoveEndpoint(w.Context(c), id); err != nil {
+		return notFoundOr(err, "删除 webhook 端点失败")
+	}
+	w.Logger(c).Infow("删除 webhook 端点", "id", id)
+	return w.JSON(c, gin.H{"deleted": 1})
+}
+
+// Deliveries GET /admin/webhooks/deliveries
+func (w *WebhookAdminController) Deliveries(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	q, err := request.ParseListQuery(c,
+		request.WithListSortable("id", "created_at"),
+		request.WithListFilterable("status", "endpoint_id", "event"),
+		request.WithListDefaultSort("-id"),
+	)
+	if err != nil {
+		return err
+	}
+	list, total, err := m.Deliveries(w.Context(c), q)
+	if err != nil {
+		return errors.NewInternalServerError("读取 webhook 投递失败", err)
+	}
+	items := make([]*webhook.Delivery, len(list))
+	for i := range list {
+		items[i] = &list[i]
+	}
+	page, err := deliveryResource.Paginate(c, items, resource.Page{Page: q.Page, PerPage: q.PerPage, Total: total})
+	if err != nil {
+		return err
+	}
+	return w.JSON(c, page)
+}
+
+// Delivery GET /admin/webhooks/deliveries/:id
+func (w *WebhookAdminController) Delivery(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	id := paramID(c)
+	d, attempts, err := m.Delivery(w.Context(c), id)
+	if err != nil {
+		return notFoundOr(err, "读取 webhook 投递失败")
+	}
+	out, err := deliveryResource.Item(c, d)
+	if err != nil {
+		return err
+	}
+	out["payload"] = d.Payload
+	out["attempt_log"] = attempts
+	return w.JSON(c, out)
+}
+
+// Replay POST /admin/webhooks/deliveries/:id/replay
+func (w *WebhookAdminController) Replay(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	id := paramID(c)
+	d, _, err := m.Delivery(w.Context(c), id)
+	if err != nil {
+		return notFoundOr(err, "读取 webhook 投递失败")
+	}
+	if d.Status != webhook.StatusFailed {
+		return errors.New(errors.Conflict, "只能重放失败的投递", nil)
+	}
+	if _, err := m.Replay(w.Context(c), id); err != nil {
+		return errors.NewInternalServerError("重放 webhook 投递失败", err)
+	}
+	if d, _, err = m.Delivery(w.Context(c), id); err != nil {
+		return notFoundOr(err, "读取 webhook 投递失败")
+	}
+	w.Logger(c).Infow("重放 webhook 投递", "id", id, "status", d.Status)
+	out, err := deliveryResource.Item(c, d)
+	if err != nil {
+		return err
+	}
+	return w.JSON(c, out)
+}
+
+// ReplayAll POST /admin/webhooks/deliveries/replay
+func (w *WebhookAdminController) ReplayAll(c *gin.Context) error {
+	m, err := w.manager()
+	if err != nil {
+		return err
+	}
+	n, err := m.Replay(w.Context(c))
+	if err != nil {
+		return errors.NewInternalServerError("重放 webhook 投递失败", err)
+	}
+	w.Logger(c).Infow("重放全部失败的 webhook 投递", "delivered", n)
+	return w.JSON(c, gin.H{"delivered": n})
+}
+
+// manager 返回全局 Webhook 管理器，未启用时返回错误
+func (w *WebhookAdminController) manager() (*webhook.Manager, error) {
+	m := webhook.Default()
+	if m == nil {
+		return nil, errors.New(errors.ServiceUnavailable, "Webhook 未启用（webhook.enabled: false）", nil)
+	}
+	return m, nil
+}
+
+// paramID 读取经 Rules 校验的路径参数 id
+func paramID(c *gin.Context) uint64 {
+	id, _ := router.Param[uint](c, "id")
+	return uint64(id)
+}
+
+// notFoundOr 记录不存在时返回 404，否则返回 500
+func notFoundOr(err error, message string) error {
+	if stderrors.Is(err, webhook.ErrNotFound) {
+		return errors.NewNotFound("记录不存在", err)
+	}
+	return errors.NewInternalServerError(message, err)
+}
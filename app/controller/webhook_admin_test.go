@@ -0,0 +1,77 @@
+package controller_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/testkit"
+	"github.com/gorilla-go/go-framework/pkg/webhook"
+)
+
+// TestWebhookAdmin 管理员注册端点、查看投递日志并重放失败的投递
+func TestWebhookAdmin(t *testing.T) {
+	app := testkit.New(t)
+	m := webhook.New(app.DB, eventbus.New(), webhook.WithMaxAttempts(1))
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	webhook.SetDefault(m)
+	t.Cleanup(func() { webhook.SetDefault(nil) })
+
+	status := http.StatusBadGateway
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	admin := app.ActingAs(1, "root", "admin")
+	app.ActingAs(2, "bob", "user").GET("/admin/webhooks/endpoints").AssertStatus(http.StatusForbidden)
+	admin.POSTJSON("/admin/webhooks/endpoints", map[string]any{"url": srv.URL}).AssertStatus(http.StatusBadRequest)
+
+	res := admin.POSTJSON("/admin/webhooks/endpoints", map[string]any{"url": srv.URL, "events": []string{"order.*"}}).
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.id", 1)
+	if secret, _ := res.JSONPath("data.secret"); secret == "" || secret == nil {
+		t.Error("注册响应应包含签名密钥")
+	}
+	admin.GET("/admin/webhooks/endpoints").
+		AssertJSON("data.0.url", srv.URL).
+		AssertJSON("data.0.events.0", "order.*")
+
+	ctx := context.Background()
+	if err := m.Emit(nil, "order.created", map[string]int{"id": 7}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = m.Process(ctx)
+
+	admin.GET("/admin/webhooks/deliveries?filter[status]=failed").
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.meta.total", 1).
+		AssertJSON("data.items.0.event", "order.created").
+		AssertJSON("data.items.0.response_status", 502)
+	admin.GET("/admin/webhooks/deliveries?filter[secret]=x").AssertStatus(http.StatusBadRequest)
+	admin.GET("/admin/webhooks/deliveries/1").
+		AssertJSON("data.payload", `{"id":7}`).
+		AssertJSON("data.attempt_log.0.status_code", 502)
+	admin.GET("/admin/webhooks/deliveries/9").AssertStatus(http.StatusNotFound)
+
+	status = http.StatusOK
+	admin.POSTJSON("/admin/webhooks/deliveries/1/replay", nil).
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.status", webhook.StatusSuccess)
+	admin.POSTJSON("/admin/webhooks/deliveries/1/replay", nil).AssertStatus(http.StatusConflict)
+	admin.POSTJSON("/admin/webhooks/deliveries/replay", nil).AssertJSON("data.delivered", 0)
+
+	admin.PUTJSON("/admin/webhooks/endpoints/1/active", map[string]any{"active": false}).AssertJSON("data.active", false)
+	admin.DELETE("/admin/webhooks/endpoints/1").AssertStatus(http.StatusOK)
+	admin.DELETE("/admin/webhooks/endpoints/1").AssertStatus(http.StatusNotFound)
+}
+
+// TestWebhookAdminDisabled 未启用 Webhook 时返回 503
+func TestWebhookAdminDisabled(t *testing.T) {
+	app := testkit.New(t)
+	app.ActingAs(1, "root", "admin").GET("/admin/webhooks/endpoints").AssertStatus(http.StatusServiceUnavailable)
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/gorilla-go/go-framework/pkg/provider"
+	"github.com/gorilla-go/go-framework/pkg/template"
+)
+
+// DemoProvider 演示提供者：在模板引擎初始化前注册模板函数，只实现 Register 阶段
+type DemoProvider struct {
+	provider.Base
+}
+
+// Register 实现 provider.Provider
+func (p *DemoProvider) Register(c *provider.Container) {
+	template.AddFuncs(map[string]any{
+		"maskEmail": MaskEmail,
+	})
+}
+
+// MaskEmail 隐藏邮箱用户名中间部分，用于列表页展示
+//
+// 模板使用示例:
+// {{ maskEmail .Email }} <!-- 输入: "zhangsan@example.com"; 输出: "zh****an@example.com" -->
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	name := []rune(local)
+	if !ok || len(name) <= 2 {
+		return email
+	}
+	keep := max(1, min(2, len(name)/3))
+	return string(name[:keep]) + strings.Repeat("*", len(name)-2*keep) + string(name[len(name)-keep:]) + "@" + domain
+}
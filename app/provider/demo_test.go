@@ -0,0 +1,18 @@
+package provider
+
+import "testing"
+
+func TestMaskEmail(t *testing.T) {
+	cases := map[string]string{
+		"zhangsan@example.com": "zh****an@example.com",
+		"bob@example.com":      "b*b@example.com",
+		"张三丰@example.com":      "张*丰@example.com",
+		"ab@example.com":       "ab@example.com",
+		"invalid":              "invalid",
+	}
+	for in, want := range cases {
+		if got := MaskEmail(in); got != want {
+			t.Errorf("MaskEmail(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package provider 应用服务提供者。需要在启动前注册绑定或全局扩展（模板函数、校验规则、事件监听）的模块
+// 实现 provider.Provider，加入 Providers 即可，bootstrap 先依次执行全部 Register，再依次执行 Boot（见 pkg/provider）。
+package provider
+
+import "github.com/gorilla-go/go-framework/pkg/provider"
+
+// Providers 应用服务提供者，按顺序执行
+var Providers = []provider.Provider{
+	&DemoProvider{},
+}
@@ -0,0 +1,8 @@
+package service
+
+// Providers 服务层 FX 提供者。构造函数均返回接口类型，即在此完成“接口 → 实现”的绑定，
+// 由 bootstrap 统一注册；替换实现（如改用 GORM 仓储）只需修改这里
+var Providers = []any{
+	NewMemoryUserRepository,
+	NewUserService,
+}
@@ -0,0 +1,93 @@
+// Package service 业务服务层。服务与仓储均以接口对外，具体实现通过 FX 绑定（见 Providers），
+// 控制器只依赖接口，单元测试时可注入 mockgen 生成的 mock（make mocks）或手写替身，无需数据库。
+package service
+
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=user.go -destination=mock/user_mock.go -package=mock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// User 用户
+type User struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// UserFilter 用户列表过滤条件，字段为空时不过滤
+type UserFilter struct {
+	Keyword string
+	Role    string
+}
+
+// UserRepository 用户仓储
+type UserRepository interface {
+	Find(ctx context.Context, id uint) (*User, error) // 不存在时返回 nil, nil
+	List(ctx context.Context, filter UserFilter) ([]*User, error)
+	Create(ctx context.Context, user *User) error // 成功后回填 user.ID
+	Delete(ctx context.Context, id uint) (bool, error)
+}
+
+// UserService 用户业务服务
+type UserService interface {
+	Get(ctx context.Context, id uint) (*User, error)
+	List(ctx context.Context, filter UserFilter) ([]*User, error)
+	Create(ctx context.Context, name, email, role string) (*User, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// userService UserService 的默认实现
+type userService struct {
+	repo UserRepository
+}
+
+// NewUserService 创建用户服务
+func NewUserService(repo UserRepository) UserService {
+	return &userService{repo: repo}
+}
+
+// Get 实现 UserService，用户不存在时返回 404 错误
+func (s *userService) Get(ctx context.Context, id uint) (*User, error) {
+	user, err := s.repo.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.NewNotFound(fmt.Sprintf("用户 %d 不存在", id), nil)
+	}
+	return user, nil
+}
+
+// List 实现 UserService
+func (s *userService) List(ctx context.Context, filter UserFilter) ([]*User, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Create 实现 UserService，未指定角色时默认为 user
+func (s *userService) Create(ctx context.Context, name, email, role string) (*User, error) {
+	if role == "" {
+		role = "user"
+	}
+	user := &User{Name: name, Email: email, Role: role}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Delete 实现 UserService，用户不存在时返回 404 错误
+func (s *userService) Delete(ctx context.Context, id uint) error {
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.NewNotFound(fmt.Sprintf("用户 %d 不存在", id), nil)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// gormUserRepository 基于 GORM 的用户仓储（user 表）。
+// 所有查询均以 db.WithContext(ctx) 执行：请求取消或超时时 SQL 随之中断，SQL 日志带有请求 ID
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository 创建 GORM 用户仓储，启用时在 Providers 中替换 NewMemoryUserRepository
+func NewGormUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+// Find 实现 UserRepository
+func (r *gormUserRepository) Find(ctx context.Context, id uint) (*User, error) {
+	var user User
+	err := r.db.WithContext(ctx).First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// List 实现 UserRepository，按 ID 升序
+func (r *gormUserRepository) List(ctx context.Context, filter UserFilter) ([]*User, error) {
+	query := r.db.WithContext(ctx).Order("id")
+	if filter.Keyword != "" {
+		query = query.Where("name = ?", filter.Keyword)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	var list []*User
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Create 实现 UserRepository
+func (r *gormUserRepository) Create(ctx context.Context, user *User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// Delete 实现 UserRepository
+func (r *gormUserRepository) Delete(ctx context.Context, id uint) (bool, error) {
+	res := r.db.WithContext(ctx).Delete(&User{}, id)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryUserRepository 基于内存的用户仓储（演示用，重启后数据丢失）
+type memoryUserRepository struct {
+	mu     sync.RWMutex
+	users  map[uint]*User
+	nextID uint
+}
+
+// NewMemoryUserRepository 创建内存用户仓储，预置两个演示用户
+func NewMemoryUserRepository() UserRepository {
+	return &memoryUserRepository{
+		users: map[uint]*User{
+			1: {ID: 1, Name: "张三", Email: "zhangsan@example.com", Role: "user"},
+			2: {ID: 2, Name: "李四", Email: "lisi@example.com", Role: "admin"},
+		},
+		nextID: 2,
+	}
+}
+
+// Find 实现 UserRepository
+func (r *memoryUserRepository) Find(_ context.Context, id uint) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.users[id], nil
+}
+
+// List 实现 UserRepository，按 ID 升序
+func (r *memoryUserRepository) List(_ context.Context, filter UserFilter) ([]*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []*User
+	for _, u := range r.users {
+		if filter.Keyword != "" && u.Name != filter.Keyword {
+			continue
+		}
+		if filter.Role != "" && u.Role != filter.Role {
+			continue
+		}
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// Create 实现 UserRepository
+func (r *memoryUserRepository) Create(_ context.Context, user *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	user.ID = r.nextID
+	r.users[user.ID] = user
+	return nil
+}
+
+// Delete 实现 UserRepository
+func (r *memoryUserRepository) Delete(_ context.Context, id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return false, nil
+	}
+	delete(r.users, id)
+	return true, nil
+}
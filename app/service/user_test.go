@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	apperrors "github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// TestUserService 服务层以内存仓储测试业务规则
+func TestUserService(t *testing.T) {
+	ctx := context.Background()
+	svc := NewUserService(NewMemoryUserRepository())
+
+	u, err := svc.Create(ctx, "王五", "wangwu@example.com", "")
+	if err != nil || u.ID != 3 || u.Role != "user" {
+		t.Fatalf("Create = %+v, %v", u, err)
+	}
+
+	admins, _ := svc.List(ctx, UserFilter{Role: "admin"})
+	if len(admins) != 1 || admins[0].Name != "李四" {
+		t.Errorf("List(admin) = %v", admins)
+	}
+
+	if err := svc.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	var appErr *apperrors.AppError
+	if _, err := svc.Get(ctx, 3); !errors.As(err, &appErr) || appErr.Code != apperrors.NotFound {
+		t.Errorf("已删除用户应返回 404，得到 %v", err)
+	}
+	if err := svc.Delete(ctx, 3); !errors.As(err, &appErr) {
+		t.Errorf("重复删除应返回 404，得到 %v", err)
+	}
+}
+
+// TestGormUserRepository GORM 仓储与内存仓储行为一致，且随上下文取消中断查询
+func TestGormUserRepository(t *testing.T) {
+	db, err := database.Open(&config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:", MaxIdleConns: 1, MaxOpenConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&User{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	svc := NewUserService(NewGormUserRepository(db))
+
+	for _, name := range []string{"张三", "李四"} {
+		if _, err := svc.Create(ctx, name, name+"@example.com", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if u, err := svc.Get(ctx, 2); err != nil || u.Name != "李四" || u.Role != "user" {
+		t.Errorf("Get = %+v, %v", u, err)
+	}
+	if list, _ := svc.List(ctx, UserFilter{Keyword: "张三"}); len(list) != 1 || list[0].ID != 1 {
+		t.Errorf("List = %v", list)
+	}
+
+	var appErr *apperrors.AppError
+	if err := svc.Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Delete(ctx, 2); !errors.As(err, &appErr) || appErr.Code != apperrors.NotFound {
+		t.Errorf("重复删除应返回 404，得到 %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := svc.List(canceled, UserFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("已取消的上下文应中断查询，得到 %v", err)
+	}
+}
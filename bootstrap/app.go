@@ -5,13 +5,33 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	appprovider "github.com/gorilla-go/go-framework/app/provider"
+	"github.com/gorilla-go/go-framework/app/service"
+	"github.com/gorilla-go/go-framework/pkg/banner"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
 	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/crypto"
+	"github.com/gorilla-go/go-framework/pkg/hash"
+	"github.com/gorilla-go/go-framework/pkg/lock"
 	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/notify"
+	"github.com/gorilla-go/go-framework/pkg/outbox"
+	"github.com/gorilla-go/go-framework/pkg/pdf"
+	"github.com/gorilla-go/go-framework/pkg/provider"
+	"github.com/gorilla-go/go-framework/pkg/resilience"
 	"github.com/gorilla-go/go-framework/pkg/router"
+	"github.com/gorilla-go/go-framework/pkg/search"
+	"github.com/gorilla-go/go-framework/pkg/settings"
+	"github.com/gorilla-go/go-framework/pkg/sms"
 	"github.com/gorilla-go/go-framework/pkg/template"
+	"github.com/gorilla-go/go-framework/pkg/webhook"
 	"go.uber.org/fx"
 )
 
@@ -25,37 +45,40 @@ var (
 	httpServer *http.Server
 )
 
-// printStartupBanner 打印启动 Logo 和服务信息
-func printStartupBanner(cfg *config.Config) {
-	banner := `
-   ____           _____                                        __
-  / ___| ___     |  ___| __ __ _ _ __ ___   _____      _____ _ __ | | __
- | |  _ / _ \    | |_ | '__/ _' | '_ ' _ \ / _ \ \ /\ / / _ \ '__|| |/ /
- | |_| | (_) |   |  _|| | | (_| | | | | | |  __/\ V  V / (_) | |   |   <
-  \____|\___/    |_|  |_|  \__,_|_| |_| |_|\___| \_/\_/ \___/|_|   |_|\_\
-`
-	// ANSI 颜色代码
-	const (
-		colorReset  = "\033[0m"
-		colorCyan   = "\033[36m"
-		colorGreen  = "\033[32m"
-		colorYellow = "\033[33m"
-		colorBlue   = "\033[34m"
-		colorPurple = "\033[35m"
-		colorBold   = "\033[1m"
-	)
-
-	fmt.Println(colorCyan + banner + colorReset)
-	fmt.Printf("%s%s🚀 Server is running!%s\n\n", colorBold, colorGreen, colorReset)
-	fmt.Printf("  %s➜%s Local:    %shttp://0.0.0.0:%d%s\n", colorGreen, colorReset, colorCyan, cfg.Server.Port, colorReset)
-	fmt.Printf("  %s➜%s Mode:     %s%s%s\n", colorGreen, colorReset, colorYellow, cfg.Server.Mode, colorReset)
-	fmt.Printf("  %s➜%s PID:      %s%d%s\n\n", colorGreen, colorReset, colorBlue, os.Getpid(), colorReset)
-
-	if cfg.Server.EnableRateLimit {
-		fmt.Printf("  %s⚡ Rate Limit:%s %d req/s (burst: %d)\n", colorPurple, colorReset, cfg.Server.RateLimit, cfg.Server.RateBurst)
-	}
-
-	fmt.Printf("\n  %sPress Ctrl+C to stop%s\n\n", colorYellow, colorReset)
+// 注册框架内置的启动摘要行
+func init() {
+	banner.Register(func(cfg *config.Config) []banner.Line {
+		driver := cfg.Database.Driver
+		if driver == "" {
+			driver = "mysql"
+		}
+		store := cfg.Session.Store
+		if store == "" {
+			store = "cookie"
+		}
+		lines := []banner.Line{
+			{Label: "Database", Value: driver},
+			{Label: "Session", Value: store},
+			{Label: "Cache", Value: strings.TrimPrefix(fmt.Sprintf("%T", cache.Default()), "*")},
+			{Label: "Routes", Value: fmt.Sprintf("%d named, %d controllers", len(router.Routes()), len(router.Controllers))},
+		}
+		if cfg.Server.EnableRateLimit {
+			lines = append(lines, banner.Line{Label: "Rate Limit", Value: fmt.Sprintf("%d req/s (burst: %d)", cfg.Server.RateLimit, cfg.Server.RateBurst)})
+		}
+		if cfg.Tenant.Enabled {
+			lines = append(lines, banner.Line{Label: "Tenancy", Value: cfg.Tenant.Resolver})
+		}
+		if cfg.GeoIP.Database != "" {
+			lines = append(lines, banner.Line{Label: "GeoIP", Value: cfg.GeoIP.Database})
+		}
+		if cfg.Image.Enabled {
+			lines = append(lines, banner.Line{Label: "Images", Value: fmt.Sprintf("%s (cache: %s)", cfg.Image.Route, cfg.Image.Cache)})
+		}
+		if cfg.Captcha.Enabled {
+			lines = append(lines, banner.Line{Label: "Captcha", Value: fmt.Sprintf("%s (store: %s)", cfg.Captcha.Route, cfg.Captcha.Store)})
+		}
+		return lines
+	})
 }
 
 // weakSecrets 已知的占位/弱密钥集合
@@ -67,10 +90,10 @@ var weakSecrets = map[string]bool{
 	"change-me":          true,
 }
 
-// warnInsecureConfig 在生产（release）模式下检测 JWT/Session 密钥是否为空或默认占位值，
+// warnInsecureConfig 在预发布与生产环境下检测 JWT/Session 密钥是否为空或默认占位值，
 // 若是则发出安全告警，提示通过配置或环境变量设置强随机密钥。
 func warnInsecureConfig(cfg *config.Config) {
-	if cfg.IsDebug() {
+	if cfg.IsDevelopment() || cfg.IsTesting() {
 		return
 	}
 	if weakSecrets[cfg.JWT.Secret] {
@@ -79,19 +102,31 @@ func warnInsecureConfig(cfg *config.Config) {
 	if weakSecrets[cfg.Session.Secret] {
 		logger.Warn("⚠️  安全告警: session.secret 为空或使用默认占位密钥，生产环境请通过环境变量 SESSION_SECRET 设置强随机值")
 	}
+	if cfg.Image.Enabled && weakSecrets[cfg.Image.Secret] {
+		logger.Warn("⚠️  安全告警: image.secret 为空或使用默认占位密钥，生产环境请通过环境变量 IMAGE_SECRET 设置强随机值")
+	}
 }
 
 // RegisterHooks 注册应用程序钩子
-func RegisterHooks(lifecycle fx.Lifecycle, router *gin.Engine, cfg *config.Config) {
+func RegisterHooks(lifecycle fx.Lifecycle, router *gin.Engine, cfg *config.Config, hub *notify.Hub) {
 	lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			// 表单请求方法伪造需在 gin 匹配路由之前改写方法
+			var handler http.Handler = router
+			if cfg.Server.MethodOverride {
+				handler = middleware.MethodOverride(router)
+			}
 			httpServer = &http.Server{
 				Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-				Handler:      router,
+				Handler:      handler,
 				ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
 				WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
 				IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
 			}
+			// Shutdown 会等待处理中的请求，先结束 SSE 长连接
+			if hub != nil {
+				httpServer.RegisterOnShutdown(hub.Shutdown)
+			}
 
 			go func() {
 				if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -99,8 +134,8 @@ func RegisterHooks(lifecycle fx.Lifecycle, router *gin.Engine, cfg *config.Confi
 				}
 			}()
 
-			// 打印启动 Logo
-			printStartupBanner(cfg)
+			// 打印启动 Logo 与服务摘要
+			banner.Print(os.Stdout, cfg)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -124,25 +159,24 @@ func RegisterHooks(lifecycle fx.Lifecycle, router *gin.Engine, cfg *config.Confi
 	})
 }
 
-// NewApp 创建应用程序
-func NewApp() *fx.App {
-
-	// 根据运行模式设置 FX 选项
-	fxOptions := []fx.Option{
+// Module 应用依赖图：注册所有 provider、执行全局初始化并注入控制器，不含 HTTP 服务钩子。
+// NewApp 在此基础上启动 HTTP 服务；pkg/testkit 在此基础上替换依赖（内存数据库等）用于集成测试
+func Module() fx.Option {
+	return fx.Options(
 		// 注册所有模块
 		fx.Provide(Providers...),
+		fx.Provide(service.Providers...),
+		registerProviders(),
 
 		// 初始化
-		fx.Invoke(func(cfg *config.Config) {
-			// 初始化日志
-			logger.InitLogger(&cfg.Log)
+		fx.Invoke(initialize),
 
-			// 安全检查：生产模式下使用默认/空密钥时发出告警
-			warnInsecureConfig(cfg)
+		// 全局锁服务与工作池在启动阶段按配置创建：lock.WithLock 在多实例部署时使用 Redis，
+		// concurrent.Go 提交的任务在应用停止时执行完毕
+		fx.Invoke(func(*lock.Locker, *concurrent.Pool) {}),
 
-			// 初始化模板引擎
-			template.InitTemplateManager(cfg.Template, Config().IsDebug())
-		}),
+		// 应用服务提供者的启动阶段：在框架全局组件初始化之后、控制器注入之前按顺序执行
+		provider.Boot(appprovider.Providers...),
 
 		// 控制器初始化（FX 注入控制器依赖）
 		fx.Populate(func() []any {
@@ -152,11 +186,44 @@ func NewApp() *fx.App {
 			}
 			return deps
 		}()...),
+	)
+}
 
-		// 注册钩子
-		fx.Invoke(RegisterHooks),
+// registerProviders 执行应用服务提供者的注册阶段并返回收集的选项；每个进程只执行一次，
+// 命令行命令（doctor、templates:lint）同样经此注册模板函数等全局扩展
+var registerProviders = sync.OnceValue(func() fx.Option {
+	return provider.Register(appprovider.Providers...)
+})
+
+// initialize 初始化日志、加密、哈希与模板引擎等全局组件
+func initialize(cfg *config.Config) {
+	// 初始化日志
+	logger.InitLogger(&cfg.Log, logger.DefaultFields(cfg)...)
+
+	// 安全检查：生产模式下使用默认/空密钥时发出告警
+	warnInsecureConfig(cfg)
+
+	// 初始化数据加密（未配置密钥时跳过，使用加密字段会返回 crypto.ErrNoKey）
+	if cfg.Crypto.Key != "" {
+		if err := crypto.Init(&cfg.Crypto); err != nil {
+			panic(fmt.Sprintf("初始化数据加密失败: %v", err))
+		}
+	}
+
+	// 初始化密码哈希
+	if err := hash.Init(&cfg.Hash); err != nil {
+		panic(fmt.Sprintf("初始化密码哈希失败: %v", err))
 	}
 
+	// 初始化模板引擎
+	template.InitTemplateManager(cfg.Template, cfg.IsDevelopment())
+	template.InitVite(cfg.Vite, cfg.IsDevelopment())
+}
+
+// NewApp 创建应用程序
+func NewApp() *fx.App {
+	fxOptions := appOptions()
+
 	// 根据运行模式设置日志级别
 	if !Config().IsDebug() {
 		fxOptions = append(fxOptions, fx.NopLogger)
@@ -164,3 +231,63 @@ func NewApp() *fx.App {
 
 	return fx.New(fxOptions...)
 }
+
+// appOptions 应用的全部 FX 选项：依赖图、按配置启用的组件与 HTTP 服务钩子（di:graph 命令同样使用）
+func appOptions() []fx.Option {
+	// 启用运行时配置时在启动阶段（HTTP 服务启动前）创建配置存储
+	settingsOption := fx.Options()
+	if Config().Settings.Enabled {
+		settingsOption = fx.Invoke(func(*settings.Store) {})
+	}
+
+	// 启用全文检索时在启动阶段创建全局检索引擎（并按 search.sync 订阅模型事件）
+	searchOption := fx.Options()
+	if Config().Search.Enabled {
+		searchOption = fx.Invoke(func(search.Engine) {})
+	}
+
+	// 启用 PDF 生成时在启动阶段创建全局生成器
+	pdfOption := fx.Options()
+	if Config().PDF.Enabled {
+		pdfOption = fx.Invoke(func(*pdf.Generator) {})
+	}
+
+	// 启用短信时在启动阶段创建全局发送器
+	smsOption := fx.Options()
+	if Config().SMS.Enabled {
+		smsOption = fx.Invoke(func(*sms.Sender) {})
+	}
+
+	// 启用 Webhook 时在启动阶段创建管理器并运行后台投递循环
+	webhookOption := fx.Options()
+	if Config().Webhook.Enabled {
+		webhookOption = fx.Invoke(func(*webhook.Manager) {})
+	}
+
+	// 启用事件发件箱时在启动阶段创建并运行后台投递循环
+	outboxOption := fx.Options()
+	if Config().Outbox.Enabled {
+		outboxOption = fx.Invoke(func(*outbox.Outbox) {})
+	}
+
+	// 启用依赖故障降级时在启动阶段创建探测器并开始定时探测
+	resilienceOption := fx.Options()
+	if Config().Resilience.Enabled {
+		resilienceOption = fx.Invoke(func(*resilience.Monitor) {})
+	}
+
+	return []fx.Option{
+		Module(),
+
+		settingsOption,
+		searchOption,
+		pdfOption,
+		smsOption,
+		webhookOption,
+		outboxOption,
+		resilienceOption,
+
+		// 注册钩子
+		fx.Invoke(RegisterHooks),
+	}
+}
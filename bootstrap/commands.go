@@ -0,0 +1,424 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/app/service"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/console"
+	"github.com/gorilla-go/go-framework/pkg/doctor"
+	"github.com/gorilla-go/go-framework/pkg/outbox"
+	"github.com/gorilla-go/go-framework/pkg/routegen"
+	"github.com/gorilla-go/go-framework/pkg/router"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/template"
+	"github.com/gorilla-go/go-framework/pkg/webhook"
+	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
+)
+
+// 注册框架内置命令
+func init() {
+	console.Register(
+		console.Command{
+			Name:        "doctor",
+			Description: "部署前诊断：检查配置、数据库与 Redis 连接、目录可写、模板编译与密钥强度",
+			Run:         runDoctor,
+		},
+		console.Command{
+			Name:        "outbox:replay",
+			Usage:       "[id...]",
+			Description: "重新投递发件箱中处理失败的事件（不指定 id 时重放全部）",
+			Run:         replayOutbox,
+		},
+		console.Command{
+			Name:        "route:gen",
+			Usage:       "[dir]",
+			Description: "根据控制器方法上的 @route 注释生成路由注册代码（默认目录 app/controller）",
+			Run:         generateRoutes,
+		},
+		console.Command{
+			Name:        "session:list",
+			Usage:       "<user_id>",
+			Description: "列出用户的活跃登录会话（需启用 session.index 且使用 redis/gorm 存储）",
+			Run:         listSessions,
+		},
+		console.Command{
+			Name:        "session:revoke",
+			Usage:       "<user_id> [session_id...]",
+			Description: "撤销用户的指定会话，不指定会话 ID 时撤销全部（强制下线）",
+			Run:         revokeSessions,
+		},
+		console.Command{
+			Name:        "webhook:replay",
+			Usage:       "[id...]",
+			Description: "重新投递失败的 webhook（不指定 id 时重放全部）",
+			Run:         replayWebhooks,
+		},
+		console.Command{
+			Name:        "config:show",
+			Usage:       "[--yaml] [key]",
+			Description: "输出实际生效的配置（默认值、配置文件与环境变量合并后），密码、密钥、令牌等敏感项脱敏；key 如 redis、server.port",
+			Run:         showConfig,
+		},
+		console.Command{
+			Name:        "config:env",
+			Usage:       "[--dotenv]",
+			Description: "输出全部配置项及对应的环境变量、类型、默认值与说明（Markdown 表格），--dotenv 输出 .env 示例文件",
+			Run:         envReference,
+		},
+		console.Command{
+			Name:        "request:replay",
+			Usage:       "[--target url] [-H header] <path>...",
+			Description: "将 record 录制的 HAR 文件重放到本地实例（默认 http://127.0.0.1:<server.port>），输出响应状态与录制时的状态",
+			Run:         replayRequests,
+		},
+		console.Command{
+			Name:        "di:graph",
+			Description: "以 DOT 格式输出依赖注入图（provider、invoke 执行顺序与生命周期钩子），缺失的依赖标红",
+			Run:         diGraph,
+		},
+		console.Command{
+			Name:        "templates:lint",
+			Description: "检查全部模板的语法、未定义的函数，以及 url / include / render 引用的路由、模板与块是否存在",
+			Run:         lintTemplates,
+		},
+	)
+}
+
+// runDoctor 执行诊断并打印报告，存在失败项时返回错误（退出码为 1）
+func runDoctor(args []string) error {
+	cfg, err := config.Fetch()
+	if err != nil {
+		doctor.Report(console.Output, []doctor.Result{{Name: "配置加载", Status: doctor.Fail, Message: err.Error()}})
+		return fmt.Errorf("诊断未通过")
+	}
+	registerProviders() // 模板编译检查需要应用注册的模板函数
+	if n := doctor.Report(console.Output, doctor.Run(context.Background(), cfg)); n > 0 {
+		return fmt.Errorf("诊断未通过：%d 项失败", n)
+	}
+	return nil
+}
+
+// showConfig 以 JSON（默认）或 YAML 输出脱敏后的生效配置，可按点分隔的键只输出其中一部分
+func showConfig(args []string) error {
+	asYAML := false
+	var key string
+	for _, arg := range args {
+		switch {
+		case arg == "--yaml":
+			asYAML = true
+		case arg == "--json":
+			asYAML = false
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("未知参数: %s", arg)
+		default:
+			key = arg
+		}
+	}
+
+	cfg, err := config.Fetch()
+	if err != nil {
+		return err
+	}
+	var out any = config.Dump(cfg)
+	if key != "" {
+		for _, part := range strings.Split(key, ".") {
+			m, ok := out.(map[string]any)
+			if !ok {
+				return fmt.Errorf("配置项不存在: %s", key)
+			}
+			if out, ok = m[part]; !ok {
+				return fmt.Errorf("配置项不存在: %s", key)
+			}
+		}
+	}
+
+	if asYAML {
+		enc := yaml.NewEncoder(console.Output)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(out)
+	}
+	enc := json.NewEncoder(console.Output)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(out)
+}
+
+// envReference 输出配置项与环境变量对照表，用于保持部署文档与代码一致：
+//
+//	app config:env > docs/configuration.md
+//	app config:env --dotenv > .env.example
+func envReference(args []string) error {
+	dotenv := false
+	for _, arg := range args {
+		switch arg {
+		case "--dotenv":
+			dotenv = true
+		case "--markdown":
+			dotenv = false
+		default:
+			return fmt.Errorf("未知参数: %s", arg)
+		}
+	}
+
+	entries, err := config.Reference()
+	if err != nil {
+		return err
+	}
+	out := console.Output
+	if dotenv {
+		for _, e := range entries {
+			if e.Env == "" {
+				continue
+			}
+			fmt.Fprintf(out, "# %s (%s) %s\n", e.Key, e.Type, e.Description)
+			// 敏感项不输出默认值，须在部署时填写
+			value := ""
+			if !e.Sensitive {
+				value = formatDefault(e.Default, ",")
+			}
+			fmt.Fprintf(out, "%s=%s\n\n", e.Env, value)
+		}
+		return nil
+	}
+
+	fmt.Fprintln(out, "| 配置项 | 环境变量 | 类型 | 默认值 | 说明 |")
+	fmt.Fprintln(out, "| --- | --- | --- | --- | --- |")
+	for _, e := range entries {
+		env := "（仅配置文件）"
+		if e.Env != "" {
+			env = "`" + e.Env + "`"
+		}
+		def := formatDefault(e.Default, ", ")
+		if def != "" {
+			def = "`" + def + "`"
+		}
+		desc := e.Description
+		if e.Sensitive {
+			desc = strings.TrimSpace("**敏感** " + desc)
+		}
+		if e.Env != "" && strings.HasPrefix(e.Type, "[]") {
+			desc += "（环境变量以逗号分隔）"
+		}
+		fmt.Fprintf(out, "| `%s` | %s | `%s` | %s | %s |\n", e.Key, env, e.Type, def, strings.ReplaceAll(desc, "|", "\\|"))
+	}
+	return nil
+}
+
+// formatDefault 格式化默认值：列表以 sep 连接，map 以 JSON 输出
+func formatDefault(v any, sep string) string {
+	switch d := v.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(d, sep)
+	case map[string]any, []map[string]any, map[string][]string:
+		b, _ := json.Marshal(d)
+		if s := string(b); s != "{}" && s != "[]" {
+			return s
+		}
+		return ""
+	default:
+		return fmt.Sprint(d)
+	}
+}
+
+// parseIDs 解析命令行中的记录 ID
+func parseIDs(args []string, kind string) ([]uint64, error) {
+	ids := make([]uint64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的%s ID: %s", kind, arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// replayOutbox 重放失败的发件箱事件
+func replayOutbox(args []string) error {
+	ids, err := parseIDs(args, "事件")
+	if err != nil {
+		return err
+	}
+
+	cfg := Config()
+	box := outbox.New(Database(cfg), EventBus(), outboxOptions(&cfg.Outbox)...)
+	if err := box.Migrate(); err != nil {
+		return err
+	}
+	n, err := box.Replay(context.Background(), ids...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(console.Output, "已重新投递 %d 个事件\n", n)
+	return nil
+}
+
+// replayWebhooks 重放失败的 webhook 投递
+func replayWebhooks(args []string) error {
+	ids, err := parseIDs(args, "投递")
+	if err != nil {
+		return err
+	}
+	cfg := Config()
+	m := webhook.NewFromConfig(&cfg.Webhook, Database(cfg), EventBus())
+	if err := m.Migrate(); err != nil {
+		return err
+	}
+	n, err := m.Replay(context.Background(), ids...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(console.Output, "已成功投递 %d 个 webhook\n", n)
+	return nil
+}
+
+// generateRoutes 生成 @route 注释对应的路由注册代码
+func generateRoutes(args []string) error {
+	dir := "app/controller"
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	out, err := routegen.Write(dir)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		fmt.Fprintf(console.Output, "%s 下没有 @route 注释，未生成文件\n", dir)
+		return nil
+	}
+	fmt.Fprintf(console.Output, "已生成 %s\n", out)
+	return nil
+}
+
+// sessionIndexForCommand 创建命令行使用的会话索引，进程内索引无法跨进程管理
+func sessionIndexForCommand(args []string) (session.Index, uint, error) {
+	if len(args) == 0 {
+		return nil, 0, fmt.Errorf("缺少用户 ID")
+	}
+	userID, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil || userID == 0 {
+		return nil, 0, fmt.Errorf("无效的用户 ID: %s", args[0])
+	}
+	cfg := Config()
+	if !cfg.Session.Index {
+		return nil, 0, fmt.Errorf("会话索引未启用（session.index: false）")
+	}
+	if cfg.Session.Store != "redis" && cfg.Session.Store != "gorm" {
+		return nil, 0, fmt.Errorf("%s 存储下会话索引位于应用进程内，无法通过命令行管理", cfg.Session.Store)
+	}
+	idx, err := session.NewIndex(session.StoreOptions{Session: &cfg.Session, Redis: &cfg.Redis, Database: &cfg.Database})
+	if err != nil {
+		return nil, 0, err
+	}
+	if m, ok := idx.(interface{ Migrate() error }); ok {
+		if err := m.Migrate(); err != nil {
+			return nil, 0, err
+		}
+	}
+	return idx, uint(userID), nil
+}
+
+// listSessions 列出用户的活跃会话
+func listSessions(args []string) error {
+	idx, userID, err := sessionIndexForCommand(args)
+	if err != nil {
+		return err
+	}
+	list, err := idx.List(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Fprintf(console.Output, "用户 %d 没有活跃会话\n", userID)
+		return nil
+	}
+	for _, info := range list {
+		fmt.Fprintf(console.Output, "%s  %s  %-15s  %s\n",
+			info.ID, info.CreatedAt.Format("2006-01-02 15:04:05"), info.IP, info.UserAgent)
+	}
+	return nil
+}
+
+// revokeSessions 撤销用户的指定会话或全部会话
+func revokeSessions(args []string) error {
+	idx, userID, err := sessionIndexForCommand(args)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if len(args) == 1 {
+		n, err := idx.RevokeAll(ctx, userID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(console.Output, "已撤销用户 %d 的 %d 个会话\n", userID, n)
+		return nil
+	}
+	n := 0
+	for _, id := range args[1:] {
+		ok, err := idx.Revoke(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			n++
+		}
+	}
+	fmt.Fprintf(console.Output, "已撤销用户 %d 的 %d 个会话\n", userID, n)
+	return nil
+}
+
+// lintTemplates 检查全部模板，存在问题时返回错误（退出码为 1，供 CI 使用）
+func lintTemplates(args []string) error {
+	cfg := Config()
+	names, err := routeNames()
+	if err != nil {
+		return fmt.Errorf("注册路由失败: %w", err)
+	}
+	issues := template.NewTemplateManager(cfg.Template, false).Lint(template.WithLintRoutes(names...))
+	for _, issue := range issues {
+		fmt.Fprintln(console.Output, issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("模板检查未通过：%d 个问题", len(issues))
+	}
+	fmt.Fprintln(console.Output, "模板检查通过")
+	return nil
+}
+
+// routeNames 注入控制器依赖并执行路由注册（不启动服务），返回全部命名路由；
+// 只构造控制器实际依赖的组件
+func routeNames() ([]string, error) {
+	deps := make([]any, len(router.Controllers))
+	for i, c := range router.Controllers {
+		deps[i] = c
+	}
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(Providers...),
+		fx.Provide(service.Providers...),
+		registerProviders(),
+		fx.Populate(deps...),
+	)
+	if err := app.Err(); err != nil {
+		return nil, err
+	}
+	gin.SetMode(gin.ReleaseMode)
+	router.NewRouteBuilder(gin.New()).Register(router.Controllers...)
+	routes := router.Routes()
+	names := make([]string, len(routes))
+	for i, r := range routes {
+		names[i] = r.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,33 @@
+package bootstrap
+
+import (
+	"expvar"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+)
+
+// registerDebugRoutes 注册 /debug/pprof 与 /debug/vars 性能诊断端点。
+// debug 模式下直接可访问；生产环境仅在配置了 server.debug_token 时注册，并要求携带该令牌。
+//
+// 注意 /debug/pprof/profile 与 /debug/pprof/trace 默认采样 30 秒，
+// 需小于 server.write_timeout，或通过 ?seconds=N 缩短采样时间
+func registerDebugRoutes(r *gin.Engine, cfg *config.Config) {
+	if !cfg.IsDebug() && cfg.Server.DebugToken == "" {
+		return
+	}
+
+	g := r.Group("/debug", middleware.DebugAuth(cfg.Server.DebugToken, cfg.IsDebug()))
+	g.GET("/vars", gin.WrapH(expvar.Handler()))
+
+	g.GET("/pprof/", gin.WrapF(pprof.Index))
+	g.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	g.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	g.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	g.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	g.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	// heap、goroutine、allocs、block、mutex、threadcreate 等由 pprof.Index 按路径分发
+	g.GET("/pprof/:name", gin.WrapF(pprof.Index))
+}
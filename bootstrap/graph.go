@@ -0,0 +1,170 @@
+package bootstrap
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla-go/go-framework/pkg/console"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+// diGraph 构造应用依赖图（执行构造函数与 invoke，但不启动 HTTP 服务），以 DOT 格式输出到标准输出：
+// provider 及其依赖的类型、按执行顺序排列的 invoke、按注册顺序排列的生命周期钩子（OnStart 顺序执行，OnStop 逆序）。
+// 构造失败时同样输出依赖图，缺失的类型与失败的 invoke 标红，并返回错误（退出码为 1）：
+//
+//	app di:graph | dot -Tsvg > graph.svg
+func diGraph(args []string) error {
+	dot, err := buildGraph(appOptions())
+	fmt.Fprint(console.Output, dot)
+	if err != nil {
+		return fmt.Errorf("构造依赖图失败: %w", err)
+	}
+	return nil
+}
+
+// buildGraph 以 opts 构造应用并返回 DOT 依赖图与构造错误，无法取得依赖图时返回空字符串
+func buildGraph(opts []fx.Option) (string, error) {
+	rec := &graphRecorder{}
+	opts = append([]fx.Option{
+		fx.WithLogger(func() fxevent.Logger { return rec }),
+		fx.RecoverFromPanics(),
+		fx.Decorate(rec.lifecycle),
+		// 先于应用的 invoke 取得依赖图，后续 invoke 失败时仍可输出
+		fx.Invoke(rec.capture),
+	}, opts...)
+
+	err := fx.New(opts...).Err()
+	if err != nil {
+		if dot, verr := fx.VisualizeError(err); verr == nil {
+			rec.graph = dot
+		}
+	}
+	if rec.graph == "" {
+		return "", err
+	}
+	return rec.render(err), err
+}
+
+// graphInvoke 执行过的 invoke
+type graphInvoke struct {
+	name string
+	err  error
+}
+
+// graphHook 注册的生命周期钩子
+type graphHook struct {
+	method string // OnStart 或 OnStop
+	name   string
+}
+
+// graphRecorder 经 fx 事件记录 invoke，经包装的 fx.Lifecycle 记录钩子
+type graphRecorder struct {
+	graph   string
+	invokes []graphInvoke
+	hooks   []graphHook
+}
+
+// LogEvent 实现 fxevent.Logger
+func (r *graphRecorder) LogEvent(event fxevent.Event) {
+	if e, ok := event.(*fxevent.Invoked); ok && !strings.Contains(e.FunctionName, "graphRecorder") {
+		name := shortName(e.FunctionName)
+		if name == "reflect.makeFuncStub()" {
+			name = "fx.Populate" // Populate 以 reflect.MakeFunc 生成的函数执行
+		}
+		r.invokes = append(r.invokes, graphInvoke{name: name, err: e.Err})
+	}
+}
+
+// capture 取得 fx 生成的依赖图
+func (r *graphRecorder) capture(g fx.DotGraph) {
+	r.graph = string(g)
+}
+
+// lifecycle 包装 fx.Lifecycle，记录各组件注册的钩子
+func (r *graphRecorder) lifecycle(lc fx.Lifecycle) fx.Lifecycle {
+	return recordingLifecycle{Lifecycle: lc, rec: r}
+}
+
+// recordingLifecycle 记录 Append 的钩子后交给原 Lifecycle
+type recordingLifecycle struct {
+	fx.Lifecycle
+	rec *graphRecorder
+}
+
+func (l recordingLifecycle) Append(h fx.Hook) {
+	if h.OnStart != nil {
+		l.rec.hooks = append(l.rec.hooks, graphHook{method: "OnStart", name: funcName(h.OnStart)})
+	}
+	if h.OnStop != nil {
+		l.rec.hooks = append(l.rec.hooks, graphHook{method: "OnStop", name: funcName(h.OnStop)})
+	}
+	l.Lifecycle.Append(h)
+}
+
+// funcName 返回函数名（去掉导入路径），如 bootstrap.Redis.func1
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "?"
+	}
+	return shortName(f.Name())
+}
+
+// shortName 去掉函数名中的导入路径
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// missingTypes 匹配 dig 错误中缺失的类型
+var missingTypes = regexp.MustCompile(`missing types?: ([^\n]+)`)
+
+// render 在 fx 依赖图末尾追加 invoke 与钩子子图，标出 err 中缺失的类型
+func (r *graphRecorder) render(err error) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(strings.TrimSpace(r.graph), "}"))
+
+	if len(r.invokes) > 0 {
+		b.WriteString("\tsubgraph cluster_invokes {\n\t\tlabel = \"invoke（按执行顺序）\";\n\t\tstyle = dashed;\n")
+		for i, inv := range r.invokes {
+			attrs := ""
+			if inv.err != nil {
+				attrs = ", color=red, fontcolor=red"
+			}
+			fmt.Fprintf(&b, "\t\tinvoke_%d [shape=box, label=%s%s];\n", i, strconv.Quote(fmt.Sprintf("%d. %s", i+1, inv.name)), attrs)
+		}
+		for i := 1; i < len(r.invokes); i++ {
+			fmt.Fprintf(&b, "\t\tinvoke_%d -> invoke_%d [style=dashed];\n", i-1, i)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	if len(r.hooks) > 0 {
+		b.WriteString("\tsubgraph cluster_hooks {\n\t\tlabel = \"生命周期钩子（OnStart 按此顺序执行，OnStop 逆序）\";\n\t\tstyle = dashed;\n")
+		for i, h := range r.hooks {
+			fmt.Fprintf(&b, "\t\thook_%d [shape=note, label=%s];\n", i, strconv.Quote(fmt.Sprintf("%d. %s %s", i+1, h.method, h.name)))
+		}
+		for i := 1; i < len(r.hooks); i++ {
+			fmt.Fprintf(&b, "\t\thook_%d -> hook_%d [style=dashed];\n", i-1, i)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	if err != nil {
+		for _, m := range missingTypes.FindAllStringSubmatch(err.Error(), -1) {
+			for _, typ := range strings.Split(m[1], "; ") {
+				typ = strings.TrimSpace(strings.SplitN(typ, " (", 2)[0])
+				fmt.Fprintf(&b, "\t%s [color=red, fontcolor=red, label=%s];\n", strconv.Quote(typ), strconv.Quote(typ+"（缺失）"))
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
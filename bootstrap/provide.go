@@ -1,13 +1,35 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/broadcast"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
 	"github.com/gorilla-go/go-framework/pkg/config"
 	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/errorrate"
 	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/lock"
+	"github.com/gorilla-go/go-framework/pkg/notify"
+	"github.com/gorilla-go/go-framework/pkg/outbox"
+	"github.com/gorilla-go/go-framework/pkg/pdf"
+	"github.com/gorilla-go/go-framework/pkg/redis"
+	"github.com/gorilla-go/go-framework/pkg/resilience"
 	"github.com/gorilla-go/go-framework/pkg/router"
+	"github.com/gorilla-go/go-framework/pkg/search"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/settings"
+	"github.com/gorilla-go/go-framework/pkg/sms"
+	"github.com/gorilla-go/go-framework/pkg/storage"
+	"github.com/gorilla-go/go-framework/pkg/template"
+	"github.com/gorilla-go/go-framework/pkg/tenant"
+	"github.com/gorilla-go/go-framework/pkg/webhook"
+	"go.uber.org/fx"
 	"gorm.io/gorm"
 
 	_ "github.com/gorilla-go/go-framework/routes"
@@ -16,8 +38,25 @@ import (
 // 全局注册器
 var Providers = []any{
 	Config,
+	Clock,
 	EventBus,
 	Database,
+	Outbox,
+	Tenants,
+	Settings,
+	Search,
+	PDF,
+	SMS,
+	Webhook,
+	Broadcast,
+	Notify,
+	Resilience,
+	ErrorRate,
+	SessionIndex,
+	Storage,
+	Locker,
+	Redis,
+	WorkerPool,
 	Controllers,
 	Router,
 }
@@ -31,22 +70,124 @@ func Config() *config.Config {
 	return cfg
 }
 
+// 提供时钟（全局时钟，测试中可通过 clock.SetDefault 或 fx.Replace 替换）
+func Clock() clock.Clock {
+	return clock.Default()
+}
+
 // 提供数据库连接
 func Database(cfg *config.Config) *gorm.DB {
 	db, err := database.Init(&cfg.Database)
 	if err != nil {
 		panic(fmt.Sprintf("初始化数据库失败: %v", err))
 	}
+	if cfg.Database.ModelEvents {
+		if err := db.Use(database.NewModelEvents(eventbus.Default())); err != nil {
+			panic(fmt.Sprintf("注册模型事件插件失败: %v", err))
+		}
+	}
+	// 多租户共享连接下按租户切换 schema
+	if cfg.Tenant.Enabled {
+		if err := db.Use(tenant.NewSchemaPlugin()); err != nil {
+			panic(fmt.Sprintf("注册租户 schema 插件失败: %v", err))
+		}
+	}
+
+	// 开发模式下记录请求内的 SQL，供调试工具栏展示
+	if cfg.Server.DebugToolbar && cfg.IsDebug() {
+		if err := db.Use(database.NewQueryLogger()); err != nil {
+			panic(fmt.Sprintf("注册 SQL 记录插件失败: %v", err))
+		}
+	}
 	return db
 }
 
+// 提供文件存储管理器
+// 按 storage.disks 创建全部存储并设为全局实例（storage.Disk 与图片处理的 image.disk 使用）
+func Storage(cfg *config.Config) *storage.Manager {
+	m, err := storage.NewManager(&cfg.Storage)
+	if err != nil {
+		panic(fmt.Sprintf("初始化文件存储失败: %v", err))
+	}
+	storage.SetDefault(m)
+	return m
+}
+
+// 提供锁服务
+// 按 lock.driver 创建并设为全局实例（lock.WithLock 使用），redis 驱动使用共享 Redis 客户端
+func Locker(lc fx.Lifecycle, cfg *config.Config, client redis.Client) *lock.Locker {
+	l, err := lock.NewFromConfig(&cfg.Lock, client)
+	if err != nil {
+		panic(fmt.Sprintf("初始化锁服务失败: %v", err))
+	}
+	lock.SetDefault(l)
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			lock.SetDefault(nil)
+			return l.Close()
+		},
+	})
+	return l
+}
+
+// 提供共享 Redis 客户端
+// 按 redis.mode 连接单机、哨兵或集群，首次执行命令时才建立连接（lock.driver 为 memory 时不会连接），设为全局实例，应用停止时关闭连接池
+func Redis(lc fx.Lifecycle, cfg *config.Config) redis.Client {
+	client, err := redis.New(&cfg.Redis)
+	if err != nil {
+		panic(fmt.Sprintf("初始化 Redis 客户端失败: %v", err))
+	}
+	redis.SetDefault(client)
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			redis.SetDefault(nil)
+			return client.Close()
+		},
+	})
+	return client
+}
+
+// 提供全局工作池
+// 按 concurrent.* 创建并设为全局实例（concurrent.Go 使用），应用停止时等待已提交的任务执行完毕
+func WorkerPool(lc fx.Lifecycle, cfg *config.Config) *concurrent.Pool {
+	p := concurrent.NewPoolFromConfig(&cfg.Concurrent)
+	concurrent.SetDefault(p)
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return p.Stop(ctx)
+		},
+	})
+	return p
+}
+
 // 提供路由器
-func Router(controllers []router.IController, cfg *config.Config) *gin.Engine {
+// 依赖 *storage.Manager 以保证注册路由（本地存储的静态访问、图片处理）前全局存储已初始化
+func Router(controllers []router.IController, cfg *config.Config, _ *storage.Manager, b *broadcast.Broadcaster, hub *notify.Hub, er *errorrate.Monitor) *gin.Engine {
 	r := &router.Router{
 		Controllers: controllers,
 		Cfg:         cfg,
+		ErrorRate:   er,
+	}
+	engine := r.Route()
+
+	// 实时广播 WebSocket 路由（经过全局中间件，私有频道授权回调可读取会话）
+	if b != nil {
+		b.Mount(engine, cfg.Broadcast.Path)
+	}
+
+	// 通知推送 SSE 路由
+	if hub != nil {
+		hub.Mount(engine, cfg.Notify.Path)
+	}
+
+	// 性能诊断端点（pprof / expvar）
+	registerDebugRoutes(engine, cfg)
+
+	// 开发模式下同源代理 Vite 开发服务器（含 HMR WebSocket）
+	if v := template.GetVite(); v != nil && v.ProxyEnabled() {
+		engine.Any(template.ViteProxyPrefix+"/*path", v.ProxyHandler())
 	}
-	return r.Route()
+	return engine
 }
 
 // 提供控制器列表
@@ -59,3 +200,256 @@ func Controllers() []router.IController {
 func EventBus() *eventbus.EventBus {
 	return eventbus.Default()
 }
+
+// 提供运行时配置存储
+// 启动时创建配置表并设为全局实例（模板函数 setting 使用），settings.enabled 为 true 时随应用启动创建
+func Settings(lc fx.Lifecycle, cfg *config.Config, db *gorm.DB, bus *eventbus.EventBus) *settings.Store {
+	s := settings.New(db, bus, settings.WithCacheTTL(time.Duration(cfg.Settings.CacheTTL)*time.Second))
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := s.Migrate(); err != nil {
+				return fmt.Errorf("初始化配置表失败: %w", err)
+			}
+			settings.SetDefault(s)
+			return nil
+		},
+	})
+	return s
+}
+
+// 提供会话索引
+// session.index 为 true 时按会话存储创建索引，启动时设为全局实例（session.Login 与 Track 中间件使用）；未启用时返回 nil
+func SessionIndex(lc fx.Lifecycle, cfg *config.Config) session.Index {
+	if !cfg.Session.Index {
+		return nil
+	}
+	idx, err := session.NewIndex(session.StoreOptions{Session: &cfg.Session, Redis: &cfg.Redis, Database: &cfg.Database})
+	if err != nil {
+		panic(err.Error())
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if m, ok := idx.(interface{ Migrate() error }); ok {
+				if err := m.Migrate(); err != nil {
+					return fmt.Errorf("迁移会话索引表失败: %w", err)
+				}
+			}
+			session.SetIndex(idx)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			session.SetIndex(nil)
+			if c, ok := idx.(io.Closer); ok {
+				return c.Close()
+			}
+			return nil
+		},
+	})
+	return idx
+}
+
+// 提供租户数据库连接管理器
+// 仅在有组件依赖 *tenant.Manager 时才会创建，应用停止时关闭各租户独立连接
+func Tenants(lc fx.Lifecycle, db *gorm.DB) *tenant.Manager {
+	m := tenant.NewManager(db)
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return m.Close()
+		},
+	})
+	return m
+}
+
+// 提供全文检索引擎
+// 按 search.* 创建并设为全局实例（search.Search 使用），search.enabled 为 true 时随应用启动创建；
+// 开启 search.sync 时订阅模型事件同步索引
+func Search(cfg *config.Config, db *gorm.DB, bus *eventbus.EventBus, pool *concurrent.Pool) search.Engine {
+	engine, err := search.NewFromConfig(&cfg.Search, db)
+	if err != nil {
+		panic(fmt.Sprintf("初始化检索引擎失败: %v", err))
+	}
+	search.SetDefault(engine)
+	if cfg.Search.Sync {
+		opts := []search.SyncOption{search.WithSyncReload(db)}
+		if cfg.Search.Queue {
+			opts = append(opts, search.WithSyncQueue(pool))
+		}
+		search.Sync(bus, engine, opts...)
+	}
+	return engine
+}
+
+// 提供 PDF 生成器
+// 按 pdf.* 创建并设为全局实例（pdf.Render 使用），页面与页眉页脚通过全局模板管理器渲染；
+// pdf.enabled 为 true 时随应用启动创建，应用停止时结束本机启动的 Chrome 进程
+func PDF(lc fx.Lifecycle, cfg *config.Config) *pdf.Generator {
+	g, err := pdf.NewFromConfig(&cfg.PDF, pdf.RendererFunc(template.RenderToString))
+	if err != nil {
+		panic(fmt.Sprintf("初始化 PDF 生成器失败: %v", err))
+	}
+	pdf.SetDefault(g)
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return g.Close()
+		},
+	})
+	return g
+}
+
+// 提供短信发送器
+// 按 sms.* 创建并设为全局实例（sms.Send、sms.SendCode 使用），事件发布到全局事件总线；
+// sms.enabled 为 true 时随应用启动创建
+func SMS(cfg *config.Config, bus *eventbus.EventBus) *sms.Sender {
+	s, err := sms.NewFromConfig(&cfg.SMS, &cfg.Redis, bus)
+	if err != nil {
+		panic(fmt.Sprintf("初始化短信发送器失败: %v", err))
+	}
+	sms.SetDefault(s)
+	return s
+}
+
+// 提供 Webhook 管理器
+// 按 webhook.* 创建，启动时迁移表、设为全局实例（管理接口与 webhook.Default 使用）并运行后台投递循环，
+// 转发 webhook.events 中的事件；webhook.enabled 为 true 时随应用启动创建
+func Webhook(lc fx.Lifecycle, cfg *config.Config, db *gorm.DB, bus *eventbus.EventBus) *webhook.Manager {
+	m := webhook.NewFromConfig(&cfg.Webhook, db, bus)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := m.Migrate(); err != nil {
+				return fmt.Errorf("迁移 webhook 表失败: %w", err)
+			}
+			m.Listen(bus, cfg.Webhook.Events...)
+			webhook.SetDefault(m)
+			m.Start(context.Background())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			webhook.SetDefault(nil)
+			m.Stop()
+			return nil
+		},
+	})
+	return m
+}
+
+// 提供实时广播器
+// broadcast.enabled 为 true 时按 broadcast.* 创建，启动时设为全局实例（broadcast.Broadcast 使用）并运行背板接收循环，
+// 停止时断开全部 WebSocket 连接；未启用时返回 nil，Router 不注册 WebSocket 路由
+func Broadcast(lc fx.Lifecycle, cfg *config.Config) *broadcast.Broadcaster {
+	if !cfg.Broadcast.Enabled {
+		return nil
+	}
+	b := broadcast.NewFromConfig(&cfg.Broadcast, &cfg.Redis)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			broadcast.SetDefault(b)
+			b.Start(context.Background())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			broadcast.SetDefault(nil)
+			return b.Stop()
+		},
+	})
+	return b
+}
+
+// 提供通知中心
+// notify.enabled 为 true 时按 notify.* 创建，启动时设为全局实例（notify.Push 使用）并运行背板接收循环；
+// 未启用时返回 nil，Router 不注册 SSE 路由
+func Notify(lc fx.Lifecycle, cfg *config.Config) *notify.Hub {
+	if !cfg.Notify.Enabled {
+		return nil
+	}
+	hub := notify.NewFromConfig(&cfg.Notify, &cfg.Redis)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			notify.SetDefault(hub)
+			hub.Start(context.Background())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			notify.SetDefault(nil)
+			return hub.Stop()
+		},
+	})
+	return hub
+}
+
+// 提供依赖故障探测器
+// resilience.enabled 为 true 时按 resilience.* 创建，启动时设为全局实例（resilience.Down、StalePages 使用）并开始定时探测；
+// session.fallback 开启时会话存储的后端（redis 或 gorm 存储所用的数据库）不可用后降级为 Cookie 存储
+func Resilience(lc fx.Lifecycle, cfg *config.Config, db *gorm.DB, bus *eventbus.EventBus) *resilience.Monitor {
+	if !cfg.Resilience.Enabled {
+		return nil
+	}
+	m := resilience.NewFromConfig(&cfg.Resilience, &cfg.Redis, db, bus)
+	if cfg.Session.Fallback {
+		switch cfg.Session.Store {
+		case "redis":
+			m.OnDown("redis", resilience.SessionFallback())
+		case "gorm":
+			m.OnDown("db", resilience.SessionFallback())
+		}
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			resilience.SetDefault(m)
+			m.Start(context.Background())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			resilience.SetDefault(nil)
+			m.Stop()
+			return nil
+		},
+	})
+	return m
+}
+
+// 提供路由错误率监控
+// error_rate.enabled 为 true 时按 error_rate.* 创建并设为全局实例（/debug/vars 中的 errorrate），由路由器挂载统计中间件
+func ErrorRate(cfg *config.Config, bus *eventbus.EventBus) *errorrate.Monitor {
+	if !cfg.ErrorRate.Enabled {
+		return nil
+	}
+	m := errorrate.NewFromConfig(&cfg.ErrorRate, bus)
+	errorrate.SetDefault(m)
+	return m
+}
+
+// 提供事件发件箱
+// outbox.enabled 开启时在启动阶段创建（见 appOptions），否则仅在有组件依赖 *outbox.Outbox 时创建，
+// 随应用启动/停止后台投递循环
+func Outbox(lc fx.Lifecycle, cfg *config.Config, db *gorm.DB, bus *eventbus.EventBus) *outbox.Outbox {
+	box := outbox.New(db, bus, outboxOptions(&cfg.Outbox)...)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := box.Migrate(); err != nil {
+				return fmt.Errorf("迁移事件发件箱失败: %w", err)
+			}
+			box.Start(context.Background())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			box.Stop()
+			return nil
+		},
+	})
+	return box
+}
+
+// outboxOptions 将 outbox.* 配置转换为发件箱选项，未配置的项保留包内默认值
+func outboxOptions(cfg *config.OutboxConfig) []outbox.Option {
+	var opts []outbox.Option
+	if cfg.MaxAttempts > 0 {
+		opts = append(opts, outbox.WithMaxAttempts(cfg.MaxAttempts))
+	}
+	if cfg.Interval > 0 {
+		opts = append(opts, outbox.WithInterval(time.Duration(cfg.Interval)*time.Second))
+	}
+	if cfg.BatchSize > 0 {
+		opts = append(opts, outbox.WithBatchSize(cfg.BatchSize))
+	}
+	return opts
+}
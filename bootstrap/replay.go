@@ -0,0 +1,126 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/console"
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"github.com/gorilla-go/go-framework/pkg/recorder"
+)
+
+// replayRequests 将录制的 HAR 文件（见 record.* 配置）按文件名顺序重放到本地实例，逐条输出响应状态与录制时的状态。
+// 目标地址默认为 http://127.0.0.1:<server.port>，被脱敏的请求头不发送，可用 -H 重新指定：
+//
+//	app request:replay storage/records
+//	app request:replay --target http://127.0.0.1:8081 -H "Authorization: Bearer dev-token" storage/records/20260101-*.har
+func replayRequests(args []string) error {
+	var (
+		target string
+		paths  []string
+		header = http.Header{}
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--target" || arg == "-H":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s 缺少参数值", arg)
+			}
+			i++
+			if arg == "--target" {
+				target = args[i]
+				continue
+			}
+			name, value, ok := strings.Cut(args[i], ":")
+			if !ok {
+				return fmt.Errorf("无效的请求头: %s", args[i])
+			}
+			header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("未知参数: %s", arg)
+		default:
+			paths = append(paths, arg)
+		}
+	}
+	if len(paths) == 0 {
+		return errors.New("请指定 HAR 文件或目录")
+	}
+	if target == "" {
+		cfg, err := config.Fetch()
+		if err != nil {
+			return err
+		}
+		target = fmt.Sprintf("http://127.0.0.1:%d", cfg.Server.Port)
+	}
+
+	files, err := harFiles(paths)
+	if err != nil {
+		return err
+	}
+	client := httpclient.New(30 * time.Second)
+	// 重定向原样输出，不跟随
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	out := console.Output
+	failed := 0
+	for _, file := range files {
+		h, err := recorder.Load(file)
+		if err != nil {
+			fmt.Fprintf(out, "✗ %v\n", err)
+			failed++
+			continue
+		}
+		for i := range h.Log.Entries {
+			e := &h.Log.Entries[i]
+			path := e.Request.URL
+			if u, err := url.Parse(path); err == nil {
+				path = u.RequestURI()
+			}
+			resp, err := recorder.Replay(context.Background(), client, target, e, header)
+			if err != nil {
+				fmt.Fprintf(out, "✗ %s %s: %v\n", e.Request.Method, path, err)
+				failed++
+				continue
+			}
+			resp.Body.Close()
+			fmt.Fprintf(out, "%s %s → %d（录制时 %d）\n", e.Request.Method, path, resp.StatusCode, e.Response.Status)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 个请求重放失败", failed)
+	}
+	return nil
+}
+
+// harFiles 展开参数中的目录（其中的 .har 文件按文件名即录制时间排序）
+func harFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(p, "*"+recorder.Ext))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("没有找到 HAR 文件")
+	}
+	return files, nil
+}
@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/gorilla-go/go-framework/bootstrap"
+	"github.com/gorilla-go/go-framework/pkg/console"
 	"github.com/gorilla-go/go-framework/pkg/logger"
 )
 
 func main() {
+	// 携带子命令时执行命令后退出，如: app outbox:replay 12 13
+	if len(os.Args) > 1 {
+		if err := console.Run(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app := bootstrap.NewApp()
 
 	sigCh := make(chan os.Signal, 1)
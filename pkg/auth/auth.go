@@ -0,0 +1,28 @@
+// Package auth 提供当前登录用户的读取入口，用户由 middleware.LoadUser 在请求开始时解析并写入上下文
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// SessionKeyUserID 会话中保存登录用户 ID 的键名（登录时写入，LoadUser 读取）
+const SessionKeyUserID = "user_id"
+
+// User 获取当前登录用户，未登录或未启用 LoadUser 时返回 nil
+func User(c *gin.Context) any {
+	v, _ := c.Get(request.KeyCurrentUser)
+	return v
+}
+
+// UserAs 按具体模型类型获取当前登录用户
+//
+//	user, ok := auth.UserAs[*model.User](c)
+func UserAs[T any](c *gin.Context) (T, bool) {
+	return request.Get[T](c, request.KeyCurrentUser)
+}
+
+// Check 判断当前请求是否已解析到登录用户
+func Check(c *gin.Context) bool {
+	return User(c) != nil
+}
@@ -0,0 +1,104 @@
+// Package banner 打印启动 Logo 与服务摘要。框架与应用模块可通过 Register 向摘要追加行，
+// 如数据库驱动、缓存后端、队列 worker 数：
+//
+//	func init() {
+//	    banner.Register(func(cfg *config.Config) []banner.Line {
+//	        return []banner.Line{{Label: "Queue", Value: "4 workers"}}
+//	    })
+//	}
+//
+// 容器日志中可通过 server.banner: false 关闭 Logo、server.banner_color: false（或环境变量 NO_COLOR）关闭颜色。
+package banner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// logo 启动 Logo
+const logo = `
+   ____           _____                                        __
+  / ___| ___     |  ___| __ __ _ _ __ ___   _____      _____ _ __ | | __
+ | |  _ / _ \    | |_ | '__/ _' | '_ ' _ \ / _ \ \ /\ / / _ \ '__|| |/ /
+ | |_| | (_) |   |  _|| | | (_| | | | | | |  __/\ V  V / (_) | |   |   <
+  \____|\___/    |_|  |_|  \__,_|_| |_| |_|\___| \_/\_/ \___/|_|   |_|\_\
+`
+
+// ANSI 颜色代码
+const (
+	colorReset  = "\033[0m"
+	colorCyan   = "\033[36m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorBold   = "\033[1m"
+)
+
+// Line 摘要中的一行
+type Line struct {
+	Label string
+	Value string
+}
+
+// Contributor 返回追加到摘要的行，不适用时返回空
+type Contributor func(cfg *config.Config) []Line
+
+var (
+	mu           sync.RWMutex
+	contributors []Contributor
+)
+
+// Register 注册摘要行，按注册顺序输出在内置行（地址、运行环境、模式与 PID）之后
+func Register(fn Contributor) {
+	mu.Lock()
+	defer mu.Unlock()
+	contributors = append(contributors, fn)
+}
+
+// Lines 返回摘要的全部行：地址、运行环境、gin 模式、PID 与已注册的行
+func Lines(cfg *config.Config) []Line {
+	lines := []Line{
+		{Label: "Local", Value: fmt.Sprintf("http://0.0.0.0:%d", cfg.Server.Port)},
+		{Label: "Env", Value: cfg.Env()},
+		{Label: "Mode", Value: cfg.Server.Mode},
+		{Label: "PID", Value: fmt.Sprint(os.Getpid())},
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, fn := range contributors {
+		lines = append(lines, fn(cfg)...)
+	}
+	return lines
+}
+
+// Print 输出 Logo（server.banner）与摘要，server.banner_color 关闭或设置了 NO_COLOR 时不输出颜色
+func Print(w io.Writer, cfg *config.Config) {
+	color := cfg.Server.BannerColor && os.Getenv("NO_COLOR") == ""
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + colorReset
+	}
+
+	if cfg.Server.Banner {
+		fmt.Fprintln(w, paint(colorCyan, logo))
+	}
+	fmt.Fprintf(w, "%s\n\n", paint(colorBold+colorGreen, "🚀 Server is running!"))
+
+	lines := Lines(cfg)
+	width := 0
+	for _, l := range lines {
+		width = max(width, len(l.Label)+1)
+	}
+	for _, l := range lines {
+		fmt.Fprintf(w, "  %s %-*s %s\n", paint(colorGreen, "➜"), width, l.Label+":", paint(colorCyan, l.Value))
+	}
+
+	if cfg.Server.Banner {
+		fmt.Fprintf(w, "\n  %s\n\n", paint(colorYellow, "Press Ctrl+C to stop"))
+	}
+}
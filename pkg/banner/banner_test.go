@@ -0,0 +1,59 @@
+package banner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// TestPrint 注册的行追加在内置行之后，关闭 Logo 与颜色时输出纯文本摘要
+func TestPrint(t *testing.T) {
+	mu.Lock()
+	saved := contributors
+	contributors = nil
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		contributors = saved
+		mu.Unlock()
+	}()
+
+	Register(func(*config.Config) []Line { return []Line{{Label: "Queue", Value: "4 workers"}} })
+	Register(func(*config.Config) []Line { return nil })
+
+	cfg := &config.Config{}
+	cfg.Server.Port = 8080
+	cfg.Server.Mode = "release"
+
+	var out bytes.Buffer
+	Print(&out, cfg)
+	text := out.String()
+	if strings.Contains(text, "\033[") || strings.Contains(text, "____") || strings.Contains(text, "Ctrl+C") {
+		t.Errorf("关闭 Logo 与颜色时应只输出纯文本摘要:\n%s", text)
+	}
+	for _, want := range []string{"Local: http://0.0.0.0:8080", "Mode:  release", "Queue: 4 workers"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("缺少 %q:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "PID:") > strings.Index(text, "Queue:") {
+		t.Error("注册的行应位于内置行之后")
+	}
+
+	cfg.Server.Banner, cfg.Server.BannerColor = true, true
+	t.Setenv("NO_COLOR", "")
+	out.Reset()
+	Print(&out, cfg)
+	if !strings.Contains(out.String(), "____") || !strings.Contains(out.String(), colorCyan) {
+		t.Error("开启时应输出带颜色的 Logo")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	out.Reset()
+	Print(&out, cfg)
+	if strings.Contains(out.String(), "\033[") {
+		t.Error("NO_COLOR 应关闭颜色")
+	}
+}
@@ -0,0 +1,332 @@
+// Package broadcast 提供基于 WebSocket 的实时广播：浏览器连接后订阅频道，服务端以 Broadcast 向频道推送事件。
+// 私有频道（private- 前缀）在订阅时调用 Channel 注册的授权回调；配置 Redis 背板（backplane）后，
+// 任一实例上的 Broadcast 经 Redis pub/sub 送达所有实例上订阅该频道的连接。
+//
+//	broadcast.Channel("private-orders.{id}", func(c *gin.Context, p map[string]string) bool { ... })
+//	_ = broadcast.Broadcast(ctx, "private-orders.42", "order.shipped", gin.H{"no": "A100"})
+//
+// 消息为 JSON 文本帧（见 Message），static/js/broadcast.js 为对应的浏览器客户端。
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"golang.org/x/net/websocket"
+)
+
+// 消息类型
+const (
+	TypeConnected    = "connected"    // 服务端 → 客户端：连接建立，data 为 {"socket_id"}
+	TypeSubscribe    = "subscribe"    // 客户端 → 服务端：订阅 channel
+	TypeUnsubscribe  = "unsubscribe"  // 客户端 → 服务端：退订 channel
+	TypeSubscribed   = "subscribed"   // 服务端 → 客户端：订阅成功
+	TypeUnsubscribed = "unsubscribed" // 服务端 → 客户端：退订成功
+	TypeEvent        = "event"        // 服务端 → 客户端：频道事件
+	TypeError        = "error"        // 服务端 → 客户端：请求失败，data 为错误信息
+	TypePing         = "ping"         // 客户端 → 服务端：心跳
+	TypePong         = "pong"         // 服务端 → 客户端：心跳响应
+)
+
+// Message 客户端与服务端之间的消息：
+//
+//	{"type": "event", "channel": "private-orders.42", "event": "order.shipped", "data": {...}}
+type Message struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Backplane 多实例间转发广播消息的背板
+type Backplane interface {
+	// Publish 发布消息（编码后的 Message），所有实例（含自身）的 Run 都会收到
+	Publish(ctx context.Context, payload []byte) error
+	// Run 接收其他实例发布的消息并交给 deliver，阻塞直到 ctx 结束
+	Run(ctx context.Context, deliver func(payload []byte))
+	// Close 释放连接
+	Close() error
+}
+
+// ErrNotConfigured 未设置全局广播器
+var ErrNotConfigured = errors.New("实时广播未启用")
+
+// DefaultPath 默认的 WebSocket 路由
+const DefaultPath = "/broadcast"
+
+// Broadcaster 频道订阅管理与消息分发
+type Broadcaster struct {
+	channels    *Channels
+	backplane   Backplane
+	origins     []string
+	pingTimeout time.Duration
+	sendBuffer  int
+	maxChannels int
+
+	mu    sync.RWMutex
+	subs  map[string]map[*Conn]struct{}
+	conns map[*Conn]struct{}
+	seq   atomic.Uint64
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// Option 广播器选项
+type Option func(*Broadcaster)
+
+// WithChannels 私有频道授权规则（默认为包级 Channel 注册的全局规则）
+func WithChannels(cs *Channels) Option {
+	return func(b *Broadcaster) { b.channels = cs }
+}
+
+// WithBackplane 多实例背板，未设置时只分发到本实例的连接
+func WithBackplane(bp Backplane) Option {
+	return func(b *Broadcaster) { b.backplane = bp }
+}
+
+// WithOrigins 允许建立连接的页面来源（如 https://example.com），"*" 表示全部；默认只允许同源。
+// 不带 Origin 头的非浏览器客户端总是允许
+func WithOrigins(origins ...string) Option {
+	return func(b *Broadcaster) { b.origins = origins }
+}
+
+// WithPingTimeout 连接在该时间内没有收到任何消息（含 ping）即断开（默认 60s）
+func WithPingTimeout(d time.Duration) Option {
+	return func(b *Broadcaster) {
+		if d > 0 {
+			b.pingTimeout = d
+		}
+	}
+}
+
+// WithSendBuffer 每个连接待发送消息的缓冲数（默认 64），缓冲已满的慢连接会被断开
+func WithSendBuffer(n int) Option {
+	return func(b *Broadcaster) {
+		if n > 0 {
+			b.sendBuffer = n
+		}
+	}
+}
+
+// New 创建广播器，配置背板时需调用 Start 接收其他实例的消息
+func New(opts ...Option) *Broadcaster {
+	b := &Broadcaster{
+		channels:    defaultChannels,
+		pingTimeout: time.Minute,
+		sendBuffer:  64,
+		maxChannels: 100,
+		subs:        make(map[string]map[*Conn]struct{}),
+		conns:       make(map[*Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Broadcast 向频道推送事件，data 序列化为 JSON；配置背板时经背板送达所有实例
+func (b *Broadcaster) Broadcast(ctx context.Context, channel, event string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("序列化广播数据失败: %w", err)
+	}
+	payload, _ := json.Marshal(Message{Type: TypeEvent, Channel: channel, Event: event, Data: raw})
+	if b.backplane == nil {
+		b.deliver(channel, payload)
+		return nil
+	}
+	if err := b.backplane.Publish(ctx, payload); err != nil {
+		return fmt.Errorf("发布广播消息失败: %w", err)
+	}
+	return nil
+}
+
+// receive 处理背板收到的消息
+func (b *Broadcaster) receive(payload []byte) {
+	var msg struct {
+		Channel string `json:"channel"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Channel == "" {
+		logErrorf("无效的广播消息: %s", payload)
+		return
+	}
+	b.deliver(msg.Channel, payload)
+}
+
+// deliver 发送到本实例订阅 channel 的连接
+func (b *Broadcaster) deliver(channel string, payload []byte) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for c := range b.subs[channel] {
+		c.enqueue(payload)
+	}
+}
+
+// Subscribers 本实例订阅 channel 的连接数
+func (b *Broadcaster) Subscribers(channel string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs[channel])
+}
+
+// Connections 本实例的连接数
+func (b *Broadcaster) Connections() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.conns)
+}
+
+// Mount 注册 WebSocket 路由（GET path），handlers 在握手前执行，可用于加载登录用户等
+func (b *Broadcaster) Mount(r gin.IRoutes, path string, handlers ...gin.HandlerFunc) {
+	r.GET(path, append(handlers, b.Handler())...)
+}
+
+// Handler 处理 WebSocket 握手与连接
+func (b *Broadcaster) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		srv := websocket.Server{
+			Handshake: func(cfg *websocket.Config, r *http.Request) error {
+				if !b.allowOrigin(r) {
+					return errors.New("origin not allowed")
+				}
+				return nil
+			},
+			Handler: func(ws *websocket.Conn) { b.serve(c, ws) },
+		}
+		srv.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// allowOrigin 校验页面来源
+func (b *Broadcaster) allowOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if len(b.origins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && u.Host == r.Host
+	}
+	for _, o := range b.origins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe 处理订阅请求
+func (b *Broadcaster) subscribe(c *Conn, channel string) {
+	switch {
+	case !validName.MatchString(channel):
+		c.reply(TypeError, channel, "无效的频道名称")
+		return
+	case len(c.channels) >= b.maxChannels:
+		c.reply(TypeError, channel, "订阅的频道过多")
+		return
+	case !b.channels.Authorize(c.ctx, channel):
+		c.reply(TypeError, channel, "无权订阅该频道")
+		return
+	}
+	b.mu.Lock()
+	if b.subs[channel] == nil {
+		b.subs[channel] = make(map[*Conn]struct{})
+	}
+	b.subs[channel][c] = struct{}{}
+	b.mu.Unlock()
+	c.channels[channel] = struct{}{}
+	c.reply(TypeSubscribed, channel, nil)
+}
+
+// unsubscribe 处理退订请求
+func (b *Broadcaster) unsubscribe(c *Conn, channel string) {
+	b.mu.Lock()
+	b.remove(c, channel)
+	b.mu.Unlock()
+	delete(c.channels, channel)
+	c.reply(TypeUnsubscribed, channel, nil)
+}
+
+// remove 从频道中移除连接，调用方持有写锁
+func (b *Broadcaster) remove(c *Conn, channel string) {
+	if set := b.subs[channel]; set != nil {
+		delete(set, c)
+		if len(set) == 0 {
+			delete(b.subs, channel)
+		}
+	}
+}
+
+// Start 启动背板接收循环，未配置背板时无操作
+func (b *Broadcaster) Start(ctx context.Context) {
+	if b.backplane == nil {
+		return
+	}
+	ctx, b.stop = context.WithCancel(ctx)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.backplane.Run(ctx, b.receive)
+	}()
+}
+
+// Stop 停止背板接收循环并断开全部连接（HTTP 服务关闭时不会断开已升级的 WebSocket 连接）
+func (b *Broadcaster) Stop() error {
+	if b.stop != nil {
+		b.stop()
+	}
+	b.wg.Wait()
+	b.mu.RLock()
+	for c := range b.conns {
+		c.close()
+	}
+	b.mu.RUnlock()
+	if b.backplane != nil {
+		return b.backplane.Close()
+	}
+	return nil
+}
+
+// nextID 连接 ID
+func (b *Broadcaster) nextID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + "." + strconv.FormatUint(b.seq.Add(1), 36)
+}
+
+var defaultBroadcaster atomic.Pointer[Broadcaster]
+
+// SetDefault 设置全局广播器（由 bootstrap 在 broadcast.enabled 为 true 时调用）
+func SetDefault(b *Broadcaster) {
+	defaultBroadcaster.Store(b)
+}
+
+// Default 返回全局广播器，未启用时返回 nil
+func Default() *Broadcaster {
+	return defaultBroadcaster.Load()
+}
+
+// Broadcast 使用全局广播器向频道推送事件
+func Broadcast(ctx context.Context, channel, event string, data any) error {
+	b := Default()
+	if b == nil {
+		return ErrNotConfigured
+	}
+	return b.Broadcast(ctx, channel, event, data)
+}
+
+// logErrorf 写入错误日志（日志未初始化时忽略）
+func logErrorf(format string, args ...any) {
+	if logger.SugarLogger != nil {
+		logger.Errorf(format, args...)
+	}
+}
@@ -0,0 +1,264 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"golang.org/x/net/websocket"
+)
+
+// memoryBackplane 进程内背板，模拟多个实例共享的 Redis 频道
+type memoryBackplane struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+func (m *memoryBackplane) Publish(ctx context.Context, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.subs {
+		ch <- payload
+	}
+	return nil
+}
+
+func (m *memoryBackplane) Run(ctx context.Context, deliver func([]byte)) {
+	ch := make(chan []byte, 16)
+	m.mu.Lock()
+	m.subs = append(m.subs, ch)
+	m.mu.Unlock()
+	for {
+		select {
+		case payload := <-ch:
+			deliver(payload)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *memoryBackplane) Close() error { return nil }
+
+func (m *memoryBackplane) running() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.subs)
+}
+
+// newServer 挂载广播器，请求头 X-User 作为登录用户
+func newServer(t *testing.T, b *Broadcaster) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	b.Mount(r, DefaultPath, func(c *gin.Context) {
+		c.Set("user", c.GetHeader("X-User"))
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(func() {
+		_ = b.Stop()
+		srv.Close()
+	})
+	return srv
+}
+
+// client 测试用 WebSocket 客户端
+type client struct {
+	t  *testing.T
+	ws *websocket.Conn
+}
+
+func dial(t *testing.T, srv *httptest.Server, user string) *client {
+	t.Helper()
+	cfg, err := websocket.NewConfig("ws"+strings.TrimPrefix(srv.URL, "http")+DefaultPath, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Header.Set("X-User", user)
+	ws, err := websocket.DialConfig(cfg)
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	c := &client{t: t, ws: ws}
+	if msg := c.read(); msg.Type != TypeConnected || !strings.Contains(string(msg.Data), "socket_id") {
+		t.Fatalf("首条消息 = %+v", msg)
+	}
+	return c
+}
+
+func (c *client) send(typ, channel string) {
+	c.t.Helper()
+	if err := websocket.JSON.Send(c.ws, Message{Type: typ, Channel: channel}); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *client) read() Message {
+	c.t.Helper()
+	_ = c.ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg Message
+	if err := websocket.JSON.Receive(c.ws, &msg); err != nil {
+		c.t.Fatalf("读取消息失败: %v", err)
+	}
+	return msg
+}
+
+// subscribe 订阅并返回服务端的响应类型
+func (c *client) subscribe(channel string) string {
+	c.t.Helper()
+	c.send(TypeSubscribe, channel)
+	msg := c.read()
+	if msg.Channel != channel {
+		c.t.Fatalf("响应 = %+v", msg)
+	}
+	return msg.Type
+}
+
+func TestBroadcast(t *testing.T) {
+	b := New(WithChannels(NewChannels()))
+	srv := newServer(t, b)
+	ctx := context.Background()
+
+	alice, bob := dial(t, srv, "alice"), dial(t, srv, "bob")
+	if typ := alice.subscribe("news"); typ != TypeSubscribed {
+		t.Fatalf("订阅 = %s", typ)
+	}
+	if typ := alice.subscribe("bad channel!"); typ != TypeError {
+		t.Errorf("无效名称 = %s", typ)
+	}
+	if err := b.Broadcast(ctx, "news", "posted", map[string]int{"id": 1}); err != nil {
+		t.Fatal(err)
+	}
+	msg := alice.read()
+	if msg.Type != TypeEvent || msg.Channel != "news" || msg.Event != "posted" || string(msg.Data) != `{"id":1}` {
+		t.Errorf("event = %+v", msg)
+	}
+
+	bob.send(TypePing, "")
+	if msg := bob.read(); msg.Type != TypePong {
+		t.Errorf("ping 响应 = %+v", msg)
+	}
+	if b.Subscribers("news") != 1 || b.Connections() != 2 {
+		t.Errorf("subscribers = %d connections = %d", b.Subscribers("news"), b.Connections())
+	}
+
+	alice.send(TypeUnsubscribe, "news")
+	if msg := alice.read(); msg.Type != TypeUnsubscribed {
+		t.Errorf("退订响应 = %+v", msg)
+	}
+	if b.Subscribers("news") != 0 {
+		t.Error("退订后仍有订阅")
+	}
+
+	// 断开连接后移除全部订阅
+	bob.subscribe("news")
+	bob.ws.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for b.Connections() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if b.Connections() != 1 || b.Subscribers("news") != 0 {
+		t.Errorf("断开后 connections = %d subscribers = %d", b.Connections(), b.Subscribers("news"))
+	}
+}
+
+func TestPrivateChannel(t *testing.T) {
+	cs := NewChannels()
+	cs.Channel("private-users.{name}", func(c *gin.Context, p map[string]string) bool {
+		return c.GetString("user") == p["name"]
+	})
+	srv := newServer(t, New(WithChannels(cs)))
+
+	alice := dial(t, srv, "alice")
+	if typ := alice.subscribe("private-users.alice"); typ != TypeSubscribed {
+		t.Errorf("本人频道 = %s", typ)
+	}
+	if typ := alice.subscribe("private-users.bob"); typ != TypeError {
+		t.Errorf("他人频道 = %s", typ)
+	}
+	if typ := alice.subscribe("private-admin"); typ != TypeError {
+		t.Errorf("未注册授权回调的私有频道 = %s", typ)
+	}
+}
+
+func TestBackplane(t *testing.T) {
+	bp := &memoryBackplane{}
+	ctx := context.Background()
+	b1, b2 := New(WithBackplane(bp)), New(WithBackplane(bp))
+	b1.Start(ctx)
+	b2.Start(ctx)
+	srv1, srv2 := newServer(t, b1), newServer(t, b2)
+
+	c1, c2 := dial(t, srv1, ""), dial(t, srv2, "")
+	c1.subscribe("orders")
+	c2.subscribe("orders")
+	for bp.running() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// 实例 1 上的广播经背板送达两个实例的连接
+	if err := b1.Broadcast(ctx, "orders", "created", "A100"); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range []*client{c1, c2} {
+		if msg := c.read(); msg.Event != "created" || string(msg.Data) != `"A100"` {
+			t.Errorf("实例 %d 收到 %+v", i+1, msg)
+		}
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	srv := newServer(t, New(WithOrigins("https://app.example.com")))
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + DefaultPath
+	if _, err := websocket.Dial(url, "", "https://evil.example.com"); err == nil {
+		t.Error("不允许的来源应握手失败")
+	}
+	ws, err := websocket.Dial(url, "", "https://app.example.com")
+	if err != nil {
+		t.Fatalf("允许的来源握手失败: %v", err)
+	}
+	ws.Close()
+
+	same := newServer(t, New())
+	if _, err := websocket.Dial("ws"+strings.TrimPrefix(same.URL, "http")+DefaultPath, "", "https://app.example.com"); err == nil {
+		t.Error("默认只允许同源")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if err := Broadcast(context.Background(), "news", "x", nil); err != ErrNotConfigured {
+		t.Errorf("未启用 err = %v", err)
+	}
+	b := New()
+	SetDefault(b)
+	defer SetDefault(nil)
+	if err := Broadcast(context.Background(), "news", "x", nil); err != nil {
+		t.Error(err)
+	}
+	if err := b.Broadcast(context.Background(), "news", "x", func() {}); err == nil {
+		t.Error("无法序列化的数据应返回错误")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	b := NewFromConfig(&config.BroadcastConfig{Backplane: "redis", RedisChannel: "bc", PingTimeout: 30, SendBuffer: 8, AllowedOrigins: []string{"*"}},
+		&config.RedisConfig{Host: "127.0.0.1", Port: 6379})
+	rd, ok := b.backplane.(*Redis)
+	if !ok || rd.channel != "bc" || b.pingTimeout != 30*time.Second || b.sendBuffer != 8 || b.origins[0] != "*" {
+		t.Errorf("broadcaster = %+v", b)
+	}
+	if b := NewFromConfig(&config.BroadcastConfig{Backplane: "memory"}, nil); b.backplane != nil || b.pingTimeout != time.Minute {
+		t.Errorf("memory broadcaster = %+v", b)
+	}
+	msg, _ := json.Marshal(Message{Type: TypePong})
+	if string(msg) != `{"type":"pong"}` {
+		t.Errorf("envelope = %s", msg)
+	}
+}
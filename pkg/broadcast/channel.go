@@ -0,0 +1,93 @@
+package broadcast
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrivatePrefix 私有频道的名称前缀，订阅私有频道须通过 Channel 注册的授权回调
+const PrivatePrefix = "private-"
+
+// validName 频道名称：字母、数字与 _ - . = @，最长 200
+var validName = regexp.MustCompile(`^[A-Za-z0-9_\-.=@]{1,200}$`)
+
+// IsPrivate 是否为私有频道
+func IsPrivate(channel string) bool {
+	return strings.HasPrefix(channel, PrivatePrefix)
+}
+
+// AuthFunc 私有频道授权回调，c 为建立 WebSocket 连接的请求（会话、登录用户等由挂载的中间件准备），
+// params 为频道模式中 {name} 占位符对应的值
+type AuthFunc func(c *gin.Context, params map[string]string) bool
+
+// channelRule 频道模式与授权回调
+type channelRule struct {
+	segments []string
+	auth     AuthFunc
+}
+
+// Channels 私有频道授权规则
+type Channels struct {
+	mu    sync.RWMutex
+	rules []channelRule
+}
+
+// NewChannels 创建授权规则集合
+func NewChannels() *Channels {
+	return &Channels{}
+}
+
+// Channel 注册授权回调，pattern 以 "." 分段，{name} 匹配一段：
+//
+//	channels.Channel("private-orders.{id}", func(c *gin.Context, p map[string]string) bool {
+//	    userID, ok := sessions.Default(c).Get(auth.SessionKeyUserID).(uint)
+//	    return ok && orders.OwnedBy(p["id"], userID)
+//	})
+func (cs *Channels) Channel(pattern string, fn AuthFunc) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.rules = append(cs.rules, channelRule{segments: strings.Split(pattern, "."), auth: fn})
+}
+
+// Authorize 判断请求是否可以订阅 channel：公开频道总是允许，私有频道按注册顺序使用第一个匹配的回调，没有匹配时拒绝
+func (cs *Channels) Authorize(c *gin.Context, channel string) bool {
+	if !IsPrivate(channel) {
+		return true
+	}
+	segments := strings.Split(channel, ".")
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	for _, rule := range cs.rules {
+		if params, ok := match(rule.segments, segments); ok {
+			return rule.auth(c, params)
+		}
+	}
+	return false
+}
+
+// match 按段匹配频道名称，返回占位符的值
+func match(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			params[p[1:len(p)-1]] = segments[i]
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// defaultChannels 包级 Channel 注册的规则，New 未指定 WithChannels 时使用
+var defaultChannels = NewChannels()
+
+// Channel 在全局规则中注册私有频道授权回调，通常在 init 或路由注册时调用
+func Channel(pattern string, fn AuthFunc) {
+	defaultChannels.Channel(pattern, fn)
+}
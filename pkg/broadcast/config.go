@@ -0,0 +1,20 @@
+package broadcast
+
+import (
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// NewFromConfig 按 broadcast.* 配置创建广播器，backplane 为 redis 时使用全局 Redis 配置
+func NewFromConfig(cfg *config.BroadcastConfig, redisCfg *config.RedisConfig) *Broadcaster {
+	opts := []Option{
+		WithOrigins(cfg.AllowedOrigins...),
+		WithPingTimeout(time.Duration(cfg.PingTimeout) * time.Second),
+		WithSendBuffer(cfg.SendBuffer),
+	}
+	if cfg.Backplane == "redis" {
+		opts = append(opts, WithBackplane(NewRedisFromConfig(redisCfg, cfg.RedisChannel)))
+	}
+	return New(opts...)
+}
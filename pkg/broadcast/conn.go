@@ -0,0 +1,118 @@
+package broadcast
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// writeTimeout 单条消息的写超时
+const writeTimeout = 10 * time.Second
+
+// Conn 一个 WebSocket 连接
+type Conn struct {
+	ID string
+
+	ctx      *gin.Context
+	ws       *websocket.Conn
+	send     chan []byte
+	channels map[string]struct{} // 只在读循环中访问
+	done     chan struct{}
+	once     sync.Once
+}
+
+// serve 运行连接：登记后循环读取客户端消息，断开时退订全部频道
+func (b *Broadcaster) serve(ctx *gin.Context, ws *websocket.Conn) {
+	c := &Conn{
+		ID:       b.nextID(),
+		ctx:      ctx,
+		ws:       ws,
+		send:     make(chan []byte, b.sendBuffer),
+		channels: make(map[string]struct{}),
+		done:     make(chan struct{}),
+	}
+	b.mu.Lock()
+	b.conns[c] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		for ch := range c.channels {
+			b.remove(c, ch)
+		}
+		delete(b.conns, c)
+		b.mu.Unlock()
+		c.close()
+	}()
+
+	go c.writeLoop()
+	c.reply(TypeConnected, "", map[string]string{"socket_id": c.ID})
+
+	for {
+		_ = ws.SetReadDeadline(time.Now().Add(b.pingTimeout))
+		var msg Message
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				c.reply(TypeError, "", "无效的消息")
+				continue
+			}
+			return
+		}
+		switch msg.Type {
+		case TypeSubscribe:
+			b.subscribe(c, msg.Channel)
+		case TypeUnsubscribe:
+			b.unsubscribe(c, msg.Channel)
+		case TypePing:
+			c.reply(TypePong, "", nil)
+		default:
+			c.reply(TypeError, msg.Channel, "未知的消息类型")
+		}
+	}
+}
+
+// reply 向客户端发送控制消息
+func (c *Conn) reply(typ, channel string, data any) {
+	msg := Message{Type: typ, Channel: channel}
+	if data != nil {
+		msg.Data, _ = json.Marshal(data)
+	}
+	payload, _ := json.Marshal(msg)
+	c.enqueue(payload)
+}
+
+// enqueue 放入发送缓冲，缓冲已满时断开连接（客户端读取过慢）
+func (c *Conn) enqueue(payload []byte) {
+	select {
+	case c.send <- payload:
+	case <-c.done:
+	default:
+		c.close()
+	}
+}
+
+// writeLoop 依次写出缓冲中的消息，写失败时断开连接
+func (c *Conn) writeLoop() {
+	for {
+		select {
+		case payload := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := websocket.Message.Send(c.ws, string(payload)); err != nil {
+				c.close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// close 关闭连接，读循环随之退出
+func (c *Conn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		_ = c.ws.Close()
+	})
+}
@@ -0,0 +1,90 @@
+package broadcast
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// reconnectDelay 订阅连接断开后的重连间隔
+const reconnectDelay = time.Second
+
+// Redis 基于 Redis pub/sub 的背板，所有实例订阅同一个 Redis 频道
+type Redis struct {
+	pool    *redis.Pool
+	channel string
+}
+
+// NewRedis 创建 Redis 背板，channel 为 Redis 中的 pub/sub 频道名
+func NewRedis(pool *redis.Pool, channel string) *Redis {
+	return &Redis{pool: pool, channel: channel}
+}
+
+// NewRedisFromConfig 按全局 Redis 配置创建连接池与背板
+func NewRedisFromConfig(cfg *config.RedisConfig, channel string) *Redis {
+	addr := cfg.Host + ":" + strconv.Itoa(cfg.Port)
+	return NewRedis(&redis.Pool{
+		MaxIdle:     5,
+		IdleTimeout: 240 * time.Second,
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", addr, redis.DialPassword(cfg.Password), redis.DialDatabase(cfg.DB))
+		},
+	}, channel)
+}
+
+// Publish 实现 Backplane
+func (r *Redis) Publish(ctx context.Context, payload []byte) error {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = redis.DoContext(conn, ctx, "PUBLISH", r.channel, payload)
+	return err
+}
+
+// Run 实现 Backplane，订阅使用独立连接（不归还连接池），断开后每隔 1s 重连
+func (r *Redis) Run(ctx context.Context, deliver func(payload []byte)) {
+	for ctx.Err() == nil {
+		if err := r.subscribe(ctx, deliver); err != nil && ctx.Err() == nil {
+			logErrorf("广播背板订阅失败: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(reconnectDelay):
+			}
+		}
+	}
+}
+
+// subscribe 订阅并接收消息，直到连接出错或 ctx 结束
+func (r *Redis) subscribe(ctx context.Context, deliver func(payload []byte)) error {
+	c, err := r.pool.Dial()
+	if err != nil {
+		return err
+	}
+	psc := redis.PubSubConn{Conn: c}
+	defer psc.Close()
+	if err := psc.Subscribe(r.channel); err != nil {
+		return err
+	}
+	for {
+		switch v := psc.ReceiveContext(ctx).(type) {
+		case redis.Message:
+			deliver(v.Data)
+		case error:
+			return v
+		}
+	}
+}
+
+// Close 关闭连接池
+func (r *Redis) Close() error {
+	return r.pool.Close()
+}
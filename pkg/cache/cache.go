@@ -0,0 +1,59 @@
+// Package cache 提供键值缓存后端抽象，供响应缓存、配置存储等组件共用。
+// 默认使用进程内内存实现，多实例部署时可替换为 Redis 等共享后端。
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store 缓存后端
+type Store interface {
+	// Get 读取缓存，不存在或已过期时 ok 为 false
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set 写入缓存，ttl <= 0 表示永不过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete 删除缓存
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Taker 支持原子地读取并删除的缓存后端，用于一次性令牌、验证码等只能使用一次的值。内置后端均已实现
+type Taker interface {
+	// Take 读取并删除缓存，并发调用时只有一个调用者得到值
+	Take(ctx context.Context, key string) (value []byte, ok bool, err error)
+}
+
+// Take 读取并删除缓存。后端实现 Taker 时为原子操作，否则依次 Get、Delete（并发调用可能读到同一个值）
+func Take(ctx context.Context, s Store, key string) ([]byte, bool, error) {
+	if t, ok := s.(Taker); ok {
+		return t.Take(ctx, key)
+	}
+	v, ok, err := s.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+var (
+	defaultStore Store = NewMemory()
+	defaultMu    sync.RWMutex
+)
+
+// Default 获取全局缓存后端
+func Default() Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultStore
+}
+
+// SetDefault 替换全局缓存后端
+func SetDefault(s Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultStore = s
+}
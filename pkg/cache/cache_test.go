@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gomodule/redigo/redis"
+)
+
+// TestTake 并发读取并删除同一个键时只有一个调用者得到值
+func TestTake(t *testing.T) {
+	mr := miniredis.RunT(t)
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) { return redis.Dial("tcp", mr.Addr()) }}
+	defer pool.Close()
+
+	stores := map[string]Store{
+		"memory": NewMemory(),
+		"disk":   NewDisk(t.TempDir()),
+		"redis":  NewRedis(pool, "cache:"),
+	}
+	ctx := context.Background()
+	for name, s := range stores {
+		if err := s.Set(ctx, "token", []byte("v"), 0); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var got atomic.Int32
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, ok, err := Take(ctx, s, "token")
+				if err != nil {
+					t.Errorf("%s: %v", name, err)
+				}
+				if ok && string(v) == "v" {
+					got.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if got.Load() != 1 {
+			t.Errorf("%s: %d 个调用者取到值，want 1", name, got.Load())
+		}
+		if _, ok, _ := s.Get(ctx, "token"); ok {
+			t.Errorf("%s: Take 后键应已删除", name)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+)
+
+// Disk 本地磁盘缓存，每个键一个文件（文件名为键的 SHA-256），适合图片等体积较大的值。
+// 文件头 8 字节为过期时间（Unix 纳秒，0 表示永不过期），过期文件在读取时删除
+type Disk struct {
+	dir string
+}
+
+// NewDisk 创建磁盘缓存，目录不存在时在首次写入时创建
+func NewDisk(dir string) *Disk {
+	return &Disk{dir: dir}
+}
+
+// path 返回键对应的文件路径，按哈希前两位分子目录，避免单个目录文件过多
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(d.dir, name[:2], name)
+}
+
+// Get 实现 Store
+func (d *Disk) Get(_ context.Context, key string) ([]byte, bool, error) {
+	p := d.path(key)
+	b, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := decode(b)
+	if !ok {
+		_ = os.Remove(p)
+	}
+	return v, ok, nil
+}
+
+// decode 解析文件内容，内容不完整或已过期时 ok 为 false
+func decode(b []byte) ([]byte, bool) {
+	if len(b) < 8 {
+		return nil, false
+	}
+	if exp := int64(binary.BigEndian.Uint64(b)); exp > 0 && clock.Now().UnixNano() > exp {
+		return nil, false
+	}
+	return b[8:], true
+}
+
+// Take 实现 Taker：先将文件重命名为临时文件（并发调用中只有一个能成功），再读取并删除
+func (d *Disk) Take(_ context.Context, key string) ([]byte, bool, error) {
+	p := d.path(key)
+	f, err := os.CreateTemp(filepath.Dir(p), ".take-*")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	tmp := f.Name()
+	_ = f.Close()
+	defer os.Remove(tmp)
+	if err := os.Rename(p, tmp); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	b, err := os.ReadFile(tmp)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := decode(b)
+	return v, ok, nil
+}
+
+// Set 实现 Store，先写临时文件再重命名，并发读取不会读到写了一半的文件
+func (d *Disk) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	p := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	var exp int64
+	if ttl > 0 {
+		exp = clock.Now().Add(ttl).UnixNano()
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	header := binary.BigEndian.AppendUint64(nil, uint64(exp))
+	_, err = f.Write(append(header, value...))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), p)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// Delete 实现 Store
+func (d *Disk) Delete(_ context.Context, keys ...string) error {
+	for _, k := range keys {
+		if err := os.Remove(d.path(k)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDisk(t *testing.T) {
+	ctx := context.Background()
+	d := NewDisk(t.TempDir())
+
+	if err := d.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatal(err)
+	}
+	d.Set(ctx, "b", []byte("2"), 10*time.Millisecond)
+
+	if v, ok, err := d.Get(ctx, "a"); err != nil || !ok || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v, %v", v, ok, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := d.Get(ctx, "b"); ok {
+		t.Error("b should have expired")
+	}
+
+	d.Delete(ctx, "a", "missing")
+	if _, ok, _ := d.Get(ctx, "a"); ok {
+		t.Error("a should have been deleted")
+	}
+}
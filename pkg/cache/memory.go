@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+)
+
+// memoryItem 内存缓存条目
+type memoryItem struct {
+	value    []byte
+	expireAt time.Time // 零值表示永不过期
+}
+
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expireAt.IsZero() && now.After(i.expireAt)
+}
+
+// Memory 进程内内存缓存，过期条目在读取时或定期清理时删除
+type Memory struct {
+	mu        sync.RWMutex
+	items     map[string]*memoryItem
+	lastSweep time.Time
+}
+
+// sweepInterval 过期条目的清理间隔（在写入时顺带触发，无需后台协程）
+const sweepInterval = time.Minute
+
+// NewMemory 创建内存缓存
+func NewMemory() *Memory {
+	return &Memory{items: make(map[string]*memoryItem), lastSweep: clock.Now()}
+}
+
+// Get 实现 Store
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	item, ok := m.items[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	if item.expired(clock.Now()) {
+		m.mu.Lock()
+		delete(m.items, key)
+		m.mu.Unlock()
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+// Take 实现 Taker
+func (m *Memory) Take(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	delete(m.items, key)
+	if item.expired(clock.Now()) {
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+// Set 实现 Store
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	item := &memoryItem{value: value}
+	now := clock.Now()
+	if ttl > 0 {
+		item.expireAt = now.Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[key] = item
+	if now.Sub(m.lastSweep) > sweepInterval {
+		m.lastSweep = now
+		for k, it := range m.items {
+			if it.expired(now) {
+				delete(m.items, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Delete 实现 Store
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		delete(m.items, k)
+	}
+	return nil
+}
+
+// Len 返回当前条目数（含尚未清理的过期条目）
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	m.Set(ctx, "a", []byte("1"), 0)
+	m.Set(ctx, "b", []byte("2"), 10*time.Millisecond)
+
+	if v, ok, _ := m.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v", v, ok)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := m.Get(ctx, "b"); ok {
+		t.Error("b should have expired")
+	}
+
+	m.Delete(ctx, "a")
+	if _, ok, _ := m.Get(ctx, "a"); ok {
+		t.Error("a should have been deleted")
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len = %d, want 0", m.Len())
+	}
+}
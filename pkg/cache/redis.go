@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// Redis 基于 Redis 的缓存，多实例部署时共享
+type Redis struct {
+	pool   *redis.Pool
+	prefix string
+}
+
+// NewRedis 创建 Redis 缓存，prefix 为所有键的前缀（如 "cache:"）
+func NewRedis(pool *redis.Pool, prefix string) *Redis {
+	return &Redis{pool: pool, prefix: prefix}
+}
+
+// NewRedisFromConfig 按全局 Redis 配置创建连接池与缓存
+func NewRedisFromConfig(cfg *config.RedisConfig, prefix string) *Redis {
+	addr := cfg.Host + ":" + strconv.Itoa(cfg.Port)
+	return NewRedis(&redis.Pool{
+		MaxIdle:     5,
+		IdleTimeout: 240 * time.Second,
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", addr, redis.DialPassword(cfg.Password), redis.DialDatabase(cfg.DB))
+		},
+	}, prefix)
+}
+
+// Get 实现 Store
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer conn.Close()
+	b, err := redis.Bytes(conn.Do("GET", r.prefix+key))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// takeScript 读取并删除键（兼容不支持 GETDEL 的 Redis 6.2 以下版本）
+var takeScript = redis.NewScript(1, `local v = redis.call('GET', KEYS[1])
+if v then redis.call('DEL', KEYS[1]) end
+return v`)
+
+// Take 实现 Taker
+func (r *Redis) Take(ctx context.Context, key string) ([]byte, bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer conn.Close()
+	b, err := redis.Bytes(takeScript.Do(conn, r.prefix+key))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// Set 实现 Store
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if ttl > 0 {
+		_, err = conn.Do("SET", r.prefix+key, value, "PX", ttl.Milliseconds())
+	} else {
+		_, err = conn.Do("SET", r.prefix+key, value)
+	}
+	return err
+}
+
+// Delete 实现 Store
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	args := make([]any, len(keys))
+	for i, k := range keys {
+		args[i] = r.prefix + k
+	}
+	_, err = conn.Do("DEL", args...)
+	return err
+}
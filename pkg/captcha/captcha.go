@@ -0,0 +1,325 @@
+// Package captcha 提供图形验证码与滑块（行为）验证码：生成、一次性校验，以及按失败次数启用验证码的
+// 登录、注册防护中间件 Guard。答案保存在 Store 中（内存、Redis 或会话），每个验证码只能校验一次。
+//
+// 图形验证码的图片地址即生成入口：页面以随机 ID 引用 {prefix}/{id}.png，请求图片时生成答案并保存，
+// 再次请求同一地址（如点击刷新）会换一个答案。模板中 {{ captcha }} 输出图片与隐藏的 ID 字段：
+//
+//	<form method="POST" action="/login">
+//	    {{ captcha }}
+//	    <input name="captcha">
+//	</form>
+//
+//	if !captcha.VerifyRequest(c) { ... }
+//
+// 滑块验证码由 GET {prefix}/slider 返回背景图、拼图块与拼图块的纵坐标，前端拖动后将横坐标作为答案提交。
+package captcha
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// FieldID 表单中验证码 ID 的字段名
+	FieldID = "captcha_id"
+	// FieldAnswer 表单中验证码答案的字段名
+	FieldAnswer = "captcha"
+	// HeaderID JSON 等非表单请求中验证码 ID 的请求头
+	HeaderID = "X-Captcha-Id"
+	// HeaderAnswer JSON 等非表单请求中验证码答案的请求头
+	HeaderAnswer = "X-Captcha"
+
+	// DefaultChars 默认字符集，不含易混淆的 0/O、1/I/L
+	DefaultChars = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+	// DefaultTTL 验证码默认有效期
+	DefaultTTL = 5 * time.Minute
+)
+
+// idLength 验证码 ID 的长度
+const idLength = 20
+
+// idChars 验证码 ID 的字符集
+const idChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// 答案的类型前缀，区分图形验证码与滑块验证码
+const (
+	kindText   = "t:"
+	kindSlider = "s:"
+)
+
+// ErrInvalidID 验证码 ID 格式错误
+var ErrInvalidID = errors.New("验证码 ID 格式错误")
+
+// Store 验证码答案存储，Take 读取后即删除（一次性）
+type Store interface {
+	Set(c *gin.Context, id, answer string, ttl time.Duration) error
+	Take(c *gin.Context, id string) (answer string, ok bool, err error)
+}
+
+// Captcha 验证码服务
+type Captcha struct {
+	store     Store
+	prefix    string
+	length    int
+	chars     string
+	width     int
+	height    int
+	ttl       time.Duration
+	tolerance int
+	guard     []GuardOption
+}
+
+// Option 验证码服务配置选项
+type Option func(*Captcha)
+
+// WithPrefix 路由前缀（默认 /captcha），图片地址与 Mount 共用
+func WithPrefix(prefix string) Option {
+	return func(m *Captcha) {
+		if p := strings.Trim(prefix, "/"); p != "" {
+			m.prefix = "/" + p
+		}
+	}
+}
+
+// WithLength 图形验证码的字符数（默认 4）
+func WithLength(n int) Option {
+	return func(m *Captcha) {
+		if n > 0 {
+			m.length = n
+		}
+	}
+}
+
+// WithChars 图形验证码的字符集（默认 DefaultChars），字母校验时不区分大小写
+func WithChars(chars string) Option {
+	return func(m *Captcha) {
+		if chars != "" {
+			m.chars = strings.ToUpper(chars)
+		}
+	}
+}
+
+// WithSize 图形验证码的图片尺寸（默认 120x40）
+func WithSize(width, height int) Option {
+	return func(m *Captcha) {
+		if width > 0 && height > 0 {
+			m.width, m.height = width, height
+		}
+	}
+}
+
+// WithTTL 验证码有效期（默认 DefaultTTL）
+func WithTTL(d time.Duration) Option {
+	return func(m *Captcha) {
+		if d > 0 {
+			m.ttl = d
+		}
+	}
+}
+
+// WithSliderTolerance 滑块验证码允许的横坐标误差（像素，默认 5）
+func WithSliderTolerance(px int) Option {
+	return func(m *Captcha) {
+		if px > 0 {
+			m.tolerance = px
+		}
+	}
+}
+
+// WithGuardDefaults Guard 中间件的默认选项（如失败次数阈值），可被调用 Guard 时的选项覆盖
+func WithGuardDefaults(opts ...GuardOption) Option {
+	return func(m *Captcha) { m.guard = append(m.guard, opts...) }
+}
+
+// New 创建验证码服务
+func New(store Store, opts ...Option) *Captcha {
+	m := &Captcha{
+		store:     store,
+		prefix:    "/captcha",
+		length:    4,
+		chars:     DefaultChars,
+		width:     120,
+		height:    40,
+		ttl:       DefaultTTL,
+		tolerance: 5,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewID 生成随机的验证码 ID
+func NewID() string {
+	return randomString(idChars, idLength)
+}
+
+// validID 是否为 NewID 生成的格式，拒绝任意内容作为存储键
+func validID(id string) bool {
+	if len(id) != idLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !strings.ContainsRune(idChars, rune(id[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// URL 图形验证码的图片地址
+func (m *Captcha) URL(id string) string {
+	return m.prefix + "/" + id + ".png"
+}
+
+// Image 为 id 生成新的图形验证码答案并保存，返回 PNG 图片
+func (m *Captcha) Image(c *gin.Context, id string) ([]byte, error) {
+	if !validID(id) {
+		return nil, ErrInvalidID
+	}
+	answer := randomString(m.chars, m.length)
+	if err := m.store.Set(c, id, kindText+answer, m.ttl); err != nil {
+		return nil, err
+	}
+	return drawText(answer, m.width, m.height)
+}
+
+// Verify 校验答案，验证码无论是否通过都会失效；图形验证码不区分大小写
+func (m *Captcha) Verify(c *gin.Context, id, answer string) bool {
+	answer = strings.TrimSpace(answer)
+	if !validID(id) || answer == "" {
+		return false
+	}
+	stored, ok, err := m.store.Take(c, id)
+	if err != nil || !ok {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(stored, kindText):
+		return subtle.ConstantTimeCompare([]byte(stored[len(kindText):]), []byte(strings.ToUpper(answer))) == 1
+	case strings.HasPrefix(stored, kindSlider):
+		want, err1 := strconv.Atoi(stored[len(kindSlider):])
+		got, err2 := strconv.ParseFloat(answer, 64)
+		return err1 == nil && err2 == nil && math.Abs(got-float64(want)) <= float64(m.tolerance)
+	}
+	return false
+}
+
+// VerifyRequest 从表单字段 captcha_id、captcha（或请求头 X-Captcha-Id、X-Captcha）读取并校验验证码
+func (m *Captcha) VerifyRequest(c *gin.Context) bool {
+	id, answer := c.PostForm(FieldID), c.PostForm(FieldAnswer)
+	if id == "" {
+		id, answer = c.GetHeader(HeaderID), c.GetHeader(HeaderAnswer)
+	}
+	return m.Verify(c, id, answer)
+}
+
+// Mount 注册验证码路由，handlers 在各路由的处理器之前执行（如按 IP 限流）：
+//   - GET {prefix}：返回新的图形验证码 {"id", "image"}，供前后端分离的页面使用
+//   - GET {prefix}/{id}.png：生成并输出图形验证码
+//   - GET {prefix}/slider：返回新的滑块验证码，见 Puzzle
+func (m *Captcha) Mount(r gin.IRoutes, handlers ...gin.HandlerFunc) {
+	r.GET(m.prefix, append(handlers, m.handleNew)...)
+	r.GET(m.prefix+"/slider", append(handlers, m.handleSlider)...)
+	r.GET(m.prefix+"/:file", append(handlers, m.handleImage)...)
+}
+
+// handleNew 返回新的图形验证码 ID 与图片地址
+func (m *Captcha) handleNew(c *gin.Context) {
+	id := NewID()
+	noStore(c)
+	c.JSON(http.StatusOK, gin.H{"id": id, "image": m.URL(id)})
+}
+
+// handleImage 输出图形验证码图片
+func (m *Captcha) handleImage(c *gin.Context) {
+	id, ok := strings.CutSuffix(c.Param("file"), ".png")
+	if !ok || !validID(id) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	img, err := m.Image(c, id)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	noStore(c)
+	c.Data(http.StatusOK, "image/png", img)
+}
+
+// handleSlider 返回新的滑块验证码
+func (m *Captcha) handleSlider(c *gin.Context) {
+	p, err := m.Slider(c)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	noStore(c)
+	c.JSON(http.StatusOK, p)
+}
+
+// noStore 验证码每次请求都不同，禁止缓存
+func noStore(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+}
+
+// randomString 从字符集中随机选取 n 个字符
+func randomString(chars string, n int) string {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = chars[randInt(len(chars))]
+	}
+	return string(buf)
+}
+
+// randInt 返回 [0, n) 内的随机数（crypto/rand）
+func randInt(n int) int {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	v := uint64(0)
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return int(v % uint64(n))
+}
+
+// dataURI PNG 图片的 data URI
+func dataURI(png []byte) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+}
+
+var defaultCaptcha atomic.Pointer[Captcha]
+
+// SetDefault 设置全局验证码服务（由路由按 captcha.* 配置调用）
+func SetDefault(m *Captcha) {
+	defaultCaptcha.Store(m)
+}
+
+// Default 返回全局验证码服务，未启用时返回 nil
+func Default() *Captcha {
+	return defaultCaptcha.Load()
+}
+
+// Verify 使用全局验证码服务校验答案，未启用时返回 false
+func Verify(c *gin.Context, id, answer string) bool {
+	m := Default()
+	return m != nil && m.Verify(c, id, answer)
+}
+
+// VerifyRequest 使用全局验证码服务校验请求中的验证码，未启用时返回 false
+func VerifyRequest(c *gin.Context) bool {
+	m := Default()
+	return m != nil && m.VerifyRequest(c)
+}
@@ -0,0 +1,296 @@
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// client 携带会话 Cookie 的测试客户端
+type client struct {
+	r      *gin.Engine
+	cookie string
+}
+
+func (cl *client) do(method, path string, form url.Values, headers ...string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if form != nil {
+		req = httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	if cl.cookie != "" {
+		req.Header.Set("Cookie", cl.cookie)
+	}
+	w := httptest.NewRecorder()
+	cl.r.ServeHTTP(w, req)
+	if c := w.Result().Cookies(); len(c) > 0 {
+		cl.cookie = c[0].Name + "=" + c[0].Value
+	}
+	return w
+}
+
+// newEngine 挂载会话中间件与验证码路由
+func newEngine(m *Captcha) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(sessions.Sessions("s", memstore.NewStore([]byte("secret"))))
+	m.Mount(r)
+	return r
+}
+
+// stored 读取缓存存储中的答案（不删除）
+func stored(t *testing.T, mem *cache.Memory, id string) string {
+	t.Helper()
+	v, ok, _ := mem.Get(context.Background(), "captcha:"+id)
+	if !ok {
+		t.Fatalf("验证码 %s 未保存", id)
+	}
+	return string(v)
+}
+
+// TestImage 生成图片、刷新换答案、一次性校验且不区分大小写
+func TestImage(t *testing.T) {
+	mem := cache.NewMemory()
+	m := New(NewCacheStore(mem), WithLength(5), WithChars("abc"))
+	cl := &client{r: newEngine(m)}
+
+	w := cl.do(http.MethodGet, "/captcha", nil)
+	var created struct{ ID, Image string }
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || !validID(created.ID) || created.Image != "/captcha/"+created.ID+".png" {
+		t.Fatalf("新建验证码 = %s", w.Body)
+	}
+
+	w = cl.do(http.MethodGet, created.Image, nil)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || w.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("图片响应 %d %v", w.Code, w.Header())
+	}
+	img, err := png.Decode(w.Body)
+	if err != nil || img.Bounds().Dx() != 120 || img.Bounds().Dy() != 40 {
+		t.Fatalf("图片 %v %v", img.Bounds(), err)
+	}
+	answer := strings.TrimPrefix(stored(t, mem, created.ID), kindText)
+	if len(answer) != 5 || strings.Trim(answer, "ABC") != "" {
+		t.Fatalf("答案 %q", answer)
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	if m.Verify(c, created.ID, "x") {
+		t.Error("错误答案不应通过")
+	}
+	if m.Verify(c, created.ID, strings.ToLower(answer)) {
+		t.Error("校验失败后验证码应失效")
+	}
+
+	cl.do(http.MethodGet, created.Image, nil)
+	answer = strings.TrimPrefix(stored(t, mem, created.ID), kindText)
+	if !m.Verify(c, created.ID, " "+strings.ToLower(answer)+" ") {
+		t.Error("正确答案（忽略大小写与空白）应通过")
+	}
+	if m.Verify(c, created.ID, answer) {
+		t.Error("验证码只能校验一次")
+	}
+
+	for _, path := range []string{"/captcha/short.png", "/captcha/" + created.ID + ".gif", "/captcha/" + strings.Repeat("-", idLength) + ".png"} {
+		if w := cl.do(http.MethodGet, path, nil); w.Code != http.StatusNotFound {
+			t.Errorf("%s 状态码 %d", path, w.Code)
+		}
+	}
+}
+
+// TestSlider 滑块验证码：拼图块尺寸、纵坐标与误差范围内的横坐标
+func TestSlider(t *testing.T) {
+	mem := cache.NewMemory()
+	m := New(NewCacheStore(mem), WithSliderTolerance(3))
+	cl := &client{r: newEngine(m)}
+
+	puzzle := func() (Puzzle, int) {
+		w := cl.do(http.MethodGet, "/captcha/slider", nil)
+		var p Puzzle
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Fatal(err)
+		}
+		x, err := strconv.Atoi(strings.TrimPrefix(stored(t, mem, p.ID), kindSlider))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p, x
+	}
+	decode := func(uri string) (int, int) {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, "data:image/png;base64,"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img.Bounds().Dx(), img.Bounds().Dy()
+	}
+
+	p, x := puzzle()
+	if w, h := decode(p.Background); w != p.Width || h != p.Height {
+		t.Errorf("背景 %dx%d", w, h)
+	}
+	if w, h := decode(p.Piece); w != pieceSize+knobRadius || h != pieceSize {
+		t.Errorf("拼图块 %dx%d", w, h)
+	}
+	if x < pieceSize || x+pieceSize+knobRadius > p.Width || p.Y < 0 || p.Y+pieceSize > p.Height {
+		t.Errorf("缺口位置 (%d, %d) 超出背景", x, p.Y)
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	if !m.Verify(c, p.ID, strconv.FormatFloat(float64(x)+2.5, 'f', 1, 64)) {
+		t.Error("误差范围内应通过")
+	}
+	p, x = puzzle()
+	if m.Verify(c, p.ID, strconv.Itoa(x+4)) {
+		t.Error("超出误差不应通过")
+	}
+}
+
+// TestSessionStore 会话存储：请求表单字段校验，超出条目上限时丢弃最早的
+func TestSessionStore(t *testing.T) {
+	m := New(NewSessionStore())
+	r := newEngine(m)
+	var answers = map[string]string{}
+	r.GET("/answer/:id", func(c *gin.Context) {
+		if _, err := m.Image(c, c.Param("id")); err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		entries, _ := m.store.(*SessionStore).entries(c)
+		_, answer, _ := strings.Cut(entries[c.Param("id")], "|")
+		answers[c.Param("id")] = strings.TrimPrefix(answer, kindText)
+	})
+	r.POST("/verify", func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.FormatBool(m.VerifyRequest(c)))
+	})
+	cl := &client{r: r}
+
+	var ids []string
+	for range maxSessionEntries + 1 {
+		id := NewID()
+		ids = append(ids, id)
+		if w := cl.do(http.MethodGet, "/answer/"+id, nil); w.Code != http.StatusOK {
+			t.Fatalf("生成失败 %s", w.Body)
+		}
+	}
+	verify := func(id string) string {
+		return cl.do(http.MethodPost, "/verify", url.Values{FieldID: {id}, FieldAnswer: {answers[id]}}).Body.String()
+	}
+	if got := verify(ids[0]); got != "false" {
+		t.Error("超出上限时最早的验证码应被丢弃")
+	}
+	if got := verify(ids[1]); got != "true" {
+		t.Errorf("表单校验 = %s", got)
+	}
+	if got := verify(ids[1]); got != "false" {
+		t.Error("验证码只能校验一次")
+	}
+	w := cl.do(http.MethodPost, "/verify", nil, HeaderID, ids[2], HeaderAnswer, answers[ids[2]])
+	if w.Body.String() != "true" {
+		t.Errorf("请求头校验 = %s", w.Body)
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := m.Image(c, NewID()); err != errNoSession {
+		t.Errorf("未挂载会话中间件: %v", err)
+	}
+}
+
+// TestGuard 失败达到阈值后要求验证码，通过后清零；页面请求验证码错误时重定向回来源页
+func TestGuard(t *testing.T) {
+	mem := cache.NewMemory()
+	m := New(NewCacheStore(mem), WithGuardDefaults(WithGuardThreshold(5)))
+	r := newEngine(m)
+	guard := m.Guard(WithGuardThreshold(2))
+	r.GET("/login", guard, func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.FormatBool(request.Shared(c)[ViewKeyRequired] == true))
+	})
+	r.POST("/login", guard, func(c *gin.Context) {
+		switch c.PostForm("password") {
+		case "ok":
+			c.String(http.StatusOK, "ok")
+		case "back":
+			Failed(c)
+			c.Redirect(http.StatusFound, "/login")
+		default:
+			c.String(http.StatusUnauthorized, "no")
+		}
+	})
+	cl := &client{r: r}
+	login := func(password string, headers ...string) int {
+		return cl.do(http.MethodPost, "/login", url.Values{"password": {password}}, append([]string{"X-Requested-With", "XMLHttpRequest"}, headers...)...).Code
+	}
+	newCaptcha := func() (string, string) {
+		id := NewID()
+		cl.do(http.MethodGet, m.URL(id), nil)
+		return id, strings.TrimPrefix(stored(t, mem, id), kindText)
+	}
+
+	if login("bad") != http.StatusUnauthorized || login("back") != http.StatusFound {
+		t.Fatal("阈值内不要求验证码")
+	}
+	if got := cl.do(http.MethodGet, "/login", nil).Body.String(); got != "true" {
+		t.Errorf("达到阈值后页面应显示验证码: %s", got)
+	}
+	if code := login("ok"); code != http.StatusBadRequest {
+		t.Errorf("缺少验证码应返回 400，得到 %d", code)
+	}
+	id, answer := newCaptcha()
+	if code := login("ok", HeaderID, id, HeaderAnswer, answer); code != http.StatusOK {
+		t.Errorf("验证码正确应通过，得到 %d", code)
+	}
+	if code := login("ok"); code != http.StatusOK {
+		t.Errorf("成功后应清零计数，得到 %d", code)
+	}
+
+	// 页面请求：闪存错误后重定向回来源页
+	login("bad")
+	login("bad")
+	w := cl.do(http.MethodPost, "/login", url.Values{"password": {"ok"}}, "Referer", "http://example.com/login?next=/")
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/login?next=/" {
+		t.Errorf("页面请求 %d %s", w.Code, w.Header().Get("Location"))
+	}
+}
+
+// TestNewFromConfig 按配置选择存储与默认选项
+func TestNewFromConfig(t *testing.T) {
+	server := &config.SessionConfig{Store: "redis"}
+	m, err := NewFromConfig(&config.CaptchaConfig{Route: "verify/", Store: "session", Length: 6, TTL: 60, Threshold: 0, Window: 60}, &config.RedisConfig{}, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.store.(*SessionStore); !ok || m.prefix != "/verify" || m.length != 6 || m.URL("x") != "/verify/x.png" {
+		t.Errorf("captcha = %+v", m)
+	}
+	if _, err := NewFromConfig(&config.CaptchaConfig{Store: "file"}, &config.RedisConfig{}, server); err == nil {
+		t.Error("未知存储应返回错误")
+	}
+	if _, err := NewFromConfig(&config.CaptchaConfig{Store: "session"}, &config.RedisConfig{}, &config.SessionConfig{Store: "cookie"}); err == nil {
+		t.Error("cookie 会话下使用 session 存储应返回错误")
+	}
+}
@@ -0,0 +1,49 @@
+package captcha
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// NewFromConfig 按 captcha.* 配置创建验证码服务，redis 存储使用全局 Redis 配置；
+// 使用 redis 存储时 Guard 的失败计数同样保存在 Redis 中。
+// session 存储要求服务端会话（session.store 不为 cookie）：cookie 会话中删除答案只改变新下发的 Cookie，
+// 重新提交旧 Cookie 即可重复使用同一个答案
+func NewFromConfig(cfg *config.CaptchaConfig, redisCfg *config.RedisConfig, sessionCfg *config.SessionConfig) (*Captcha, error) {
+	var (
+		store   Store
+		counter cache.Store
+	)
+	switch cfg.Store {
+	case "", "memory":
+		store = NewCacheStore(cache.NewMemory())
+	case "redis":
+		counter = cache.NewRedisFromConfig(redisCfg, "")
+		store = NewCacheStore(counter)
+	case "session":
+		if sessionCfg.Store == "" || sessionCfg.Store == "cookie" {
+			return nil, errors.New("验证码 session 存储需要服务端会话（session.store 为 redis、gorm、memory 等），cookie 会话无法保证验证码只校验一次")
+		}
+		store = NewSessionStore()
+	default:
+		return nil, fmt.Errorf("未知的验证码存储: %s", cfg.Store)
+	}
+
+	guard := []GuardOption{WithGuardThreshold(cfg.Threshold), WithGuardWindow(time.Duration(cfg.Window) * time.Second)}
+	if counter != nil {
+		guard = append(guard, WithGuardCounter(counter))
+	}
+	return New(store,
+		WithPrefix(cfg.Route),
+		WithLength(cfg.Length),
+		WithChars(cfg.Chars),
+		WithSize(cfg.Width, cfg.Height),
+		WithTTL(time.Duration(cfg.TTL)*time.Second),
+		WithSliderTolerance(cfg.Tolerance),
+		WithGuardDefaults(guard...),
+	), nil
+}
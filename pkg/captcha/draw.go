@@ -0,0 +1,105 @@
+package captcha
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+)
+
+// boldFont 绘制字符使用的字体（Go Bold，随 x/image 分发，无需系统字体）
+var boldFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(gobold.TTF)
+})
+
+// drawText 绘制图形验证码：浅色背景上逐个绘制随机旋转、缩放与颜色的字符，再叠加干扰线与噪点
+func drawText(text string, width, height int) ([]byte, error) {
+	f, err := boldFont()
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(randColor(225, 255)), image.Point{}, draw.Src)
+	noise(img, width*height/25)
+
+	step := float64(width) / float64(len(text)+1)
+	for i, r := range text {
+		size := float64(height) * (0.6 + float64(randInt(20))/100)
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+		if err != nil {
+			return nil, err
+		}
+		tile := glyph(face, r, randColor(20, 120))
+		_ = face.Close()
+
+		angle := float64(randInt(61)-30) * math.Pi / 180
+		cx := step * (float64(i) + 1 + float64(randInt(21)-10)/40)
+		cy := float64(height)/2 + float64(randInt(height/5+1)-height/10)
+		rotate(img, tile, cx, cy, angle)
+	}
+
+	for range 2 {
+		curve(img, randColor(40, 160))
+	}
+	return encodePNG(img)
+}
+
+// glyph 将单个字符绘制到透明的小图上
+func glyph(face font.Face, r rune, c color.Color) *image.RGBA {
+	adv, _ := face.GlyphAdvance(r)
+	m := face.Metrics()
+	w, h := adv.Ceil()+2, (m.Ascent+m.Descent).Ceil()+2
+	tile := image.NewRGBA(image.Rect(0, 0, w, h))
+	d := &font.Drawer{Dst: tile, Src: image.NewUniform(c), Face: face, Dot: fixed.P(1, m.Ascent.Ceil()+1)}
+	d.DrawString(string(r))
+	return tile
+}
+
+// rotate 将 src 以 (cx, cy) 为中心旋转 angle（弧度）后叠加到 dst
+func rotate(dst draw.Image, src *image.RGBA, cx, cy, angle float64) {
+	b := src.Bounds()
+	sin, cos := math.Sincos(angle)
+	hw, hh := float64(b.Dx())/2, float64(b.Dy())/2
+	// 源图坐标 → 目标坐标：平移到原点、旋转、平移到 (cx, cy)
+	m := f64.Aff3{
+		cos, -sin, cx - cos*hw + sin*hh,
+		sin, cos, cy - sin*hw - cos*hh,
+	}
+	draw.BiLinear.Transform(dst, m, src, b, draw.Over, nil)
+}
+
+// noise 随机噪点
+func noise(img *image.RGBA, n int) {
+	b := img.Bounds()
+	for range n {
+		img.Set(randInt(b.Dx()), randInt(b.Dy()), randColor(100, 220))
+	}
+}
+
+// curve 横贯图片的正弦干扰线
+func curve(img *image.RGBA, c color.Color) {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	amp := h * (0.1 + float64(randInt(20))/100)
+	period := w * (0.5 + float64(randInt(100))/100)
+	phase := float64(randInt(628)) / 100
+	base := h * (0.3 + float64(randInt(40))/100)
+	for x := 0; x < b.Dx(); x++ {
+		y := int(base + amp*math.Sin(2*math.Pi*float64(x)/period+phase))
+		img.Set(x, y, c)
+		img.Set(x, y+1, c)
+	}
+}
+
+// randColor 各通道在 [lo, hi) 内的随机不透明颜色
+func randColor(lo, hi int) color.RGBA {
+	ch := func() uint8 { return uint8(lo + randInt(hi-lo)) }
+	return color.RGBA{ch(), ch(), ch(), 255}
+}
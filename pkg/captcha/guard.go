@@ -0,0 +1,146 @@
+package captcha
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/response"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+// ViewKeyRequired Guard 共享给视图的键名，需要验证码时为 true：{{ if .CaptchaRequired }}{{ captcha }}{{ end }}
+const ViewKeyRequired = "CaptchaRequired"
+
+// ctxKeyFailed 处理器标记本次请求失败的 gin.Context 键
+const ctxKeyFailed = "captcha.failed"
+
+// guardConfig Guard 中间件配置
+type guardConfig struct {
+	threshold int
+	window    time.Duration
+	key       func(*gin.Context) string
+	counter   cache.Store
+	message   string
+}
+
+// GuardOption Guard 中间件选项
+type GuardOption func(*guardConfig)
+
+// WithGuardThreshold 同一来源失败 n 次后要求验证码（默认 3），0 表示始终要求
+func WithGuardThreshold(n int) GuardOption {
+	return func(g *guardConfig) {
+		if n >= 0 {
+			g.threshold = n
+		}
+	}
+}
+
+// WithGuardWindow 失败计数的有效期（默认 15 分钟），最后一次失败后经过该时间清零
+func WithGuardWindow(d time.Duration) GuardOption {
+	return func(g *guardConfig) {
+		if d > 0 {
+			g.window = d
+		}
+	}
+}
+
+// WithGuardKey 失败计数的维度（默认客户端 IP），如按 IP 与用户名组合
+func WithGuardKey(fn func(*gin.Context) string) GuardOption {
+	return func(g *guardConfig) { g.key = fn }
+}
+
+// WithGuardCounter 失败计数的存储（默认进程内内存），多实例部署时使用 Redis 缓存
+func WithGuardCounter(s cache.Store) GuardOption {
+	return func(g *guardConfig) { g.counter = s }
+}
+
+// WithGuardMessage 验证码错误时的提示（默认"验证码错误"）
+func WithGuardMessage(msg string) GuardOption {
+	return func(g *guardConfig) { g.message = msg }
+}
+
+// Guard 登录、注册等路由的验证码防护，与按 IP 限流配合使用：同一来源在有效期内失败达到阈值后，
+// 提交须携带正确的验证码（见 VerifyRequest）。GET 等安全方法只检查是否需要验证码，
+// 并以 CaptchaRequired 共享给视图，页面据此决定是否显示验证码：
+//
+//	auth := rb.Group("/login", captcha.Default().Guard(captcha.WithGuardThreshold(3)))
+//	auth.GET("", c.ShowLogin, "login")
+//	auth.POST("", c.Login, "login.submit")
+//
+// 处理器返回 4xx/5xx 状态或记录了错误（c.Error）时计为一次失败，其余清零计数；
+// 以重定向回表单的方式报告失败时，需调用 Failed 标记。验证码错误时，页面请求闪存输入与错误后重定向回来源页，
+// 其他请求返回校验错误
+func (m *Captcha) Guard(opts ...GuardOption) gin.HandlerFunc {
+	cfg := &guardConfig{threshold: 3, window: 15 * time.Minute, key: (*gin.Context).ClientIP, message: "验证码错误"}
+	for _, opt := range append(m.guard[:len(m.guard):len(m.guard)], opts...) {
+		opt(cfg)
+	}
+	if cfg.counter == nil {
+		cfg.counter = cache.NewMemory()
+	}
+
+	return func(c *gin.Context) {
+		key := "captcha:guard:" + cfg.key(c)
+		failures := cfg.failures(c, key)
+		required := cfg.threshold == 0 || failures >= cfg.threshold
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if required {
+				request.Share(c, ViewKeyRequired, true)
+			}
+			c.Next()
+			return
+		}
+
+		if required && !m.VerifyRequest(c) {
+			cfg.record(c, key, failures+1)
+			reject(c, cfg.message)
+			return
+		}
+		c.Next()
+		if c.GetBool(ctxKeyFailed) || c.Writer.Status() >= http.StatusBadRequest || len(c.Errors) > 0 {
+			cfg.record(c, key, failures+1)
+		} else if failures > 0 {
+			_ = cfg.counter.Delete(c.Request.Context(), key)
+		}
+	}
+}
+
+// Failed 标记本次请求失败（如登录密码错误后重定向回表单），由 Guard 计入失败次数
+func Failed(c *gin.Context) {
+	c.Set(ctxKeyFailed, true)
+}
+
+// failures 读取失败次数
+func (g *guardConfig) failures(c *gin.Context, key string) int {
+	v, ok, err := g.counter.Get(c.Request.Context(), key)
+	if err != nil || !ok {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(v))
+	return n
+}
+
+// record 保存失败次数，有效期从本次失败起重新计算
+func (g *guardConfig) record(c *gin.Context, key string, n int) {
+	_ = g.counter.Set(c.Request.Context(), key, []byte(strconv.Itoa(n)), g.window)
+}
+
+// reject 验证码错误：页面请求闪存输入与字段错误后重定向回来源页，其他请求返回校验错误
+func reject(c *gin.Context, msg string) {
+	errs := validator.FieldErrors{{Field: FieldAnswer, Key: FieldAnswer, Tag: "captcha", Message: msg}}
+	if _, ok := c.Get(sessions.DefaultKey); ok && !request.IsAjax(c) {
+		_ = response.WithInput(c)
+		_ = response.WithErrors(c, errs)
+		response.RedirectBack(c)
+		return
+	}
+	response.Fail(c, errors.NewValidationError(msg, errs))
+}
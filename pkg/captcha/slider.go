@@ -0,0 +1,164 @@
+package captcha
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/image/draw"
+)
+
+// 滑块验证码的尺寸（像素）
+const (
+	sliderWidth  = 300
+	sliderHeight = 150
+	pieceSize    = 44 // 拼图块主体边长
+	knobRadius   = 8  // 拼图块右侧凸起的半径
+)
+
+// Puzzle 滑块验证码：前端将 Piece 放在纵坐标 Y 处，用户水平拖动到与 Background 中缺口重合的位置，
+// 提交拼图块左边缘的横坐标（像素，可为小数）作为答案
+type Puzzle struct {
+	ID         string `json:"id"`
+	Background string `json:"background"` // 带缺口的背景图（PNG data URI）
+	Piece      string `json:"piece"`      // 拼图块（PNG data URI，透明背景）
+	Y          int    `json:"y"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+// Slider 生成滑块验证码并保存缺口的横坐标
+func (m *Captcha) Slider(c *gin.Context) (*Puzzle, error) {
+	pieceW := pieceSize + knobRadius
+	x := pieceW + 10 + randInt(sliderWidth-2*pieceW-20)
+	y := 5 + randInt(sliderHeight-pieceSize-10)
+
+	bg := scenery(sliderWidth, sliderHeight)
+	piece := image.NewRGBA(image.Rect(0, 0, pieceW, pieceSize))
+	for py := 0; py < pieceSize; py++ {
+		for px := 0; px < pieceW; px++ {
+			switch {
+			case inPiece(px, py):
+				piece.Set(px, py, bg.At(x+px, y+py))
+			case onPieceEdge(px, py):
+				piece.Set(px, py, color.RGBA{255, 255, 255, 230})
+			}
+		}
+	}
+	// 缺口：压暗并描白边
+	for py := 0; py < pieceSize; py++ {
+		for px := 0; px < pieceW; px++ {
+			switch {
+			case inPiece(px, py):
+				r, g, b, _ := bg.At(x+px, y+py).RGBA()
+				bg.Set(x+px, y+py, color.RGBA{uint8(r >> 9), uint8(g >> 9), uint8(b >> 9), 255})
+			case onPieceEdge(px, py):
+				bg.Set(x+px, y+py, color.RGBA{255, 255, 255, 255})
+			}
+		}
+	}
+
+	bgPNG, err := encodePNG(bg)
+	if err != nil {
+		return nil, err
+	}
+	piecePNG, err := encodePNG(piece)
+	if err != nil {
+		return nil, err
+	}
+	id := NewID()
+	if err := m.store.Set(c, id, kindSlider+strconv.Itoa(x), m.ttl); err != nil {
+		return nil, err
+	}
+	return &Puzzle{
+		ID:         id,
+		Background: dataURI(bgPNG),
+		Piece:      dataURI(piecePNG),
+		Y:          y,
+		Width:      sliderWidth,
+		Height:     sliderHeight,
+	}, nil
+}
+
+// inPiece 点是否在拼图块内：正方形主体加右侧半圆凸起
+func inPiece(px, py int) bool {
+	if px < pieceSize && py < pieceSize {
+		return true
+	}
+	dx, dy := float64(px-pieceSize), float64(py-pieceSize/2)
+	return math.Hypot(dx, dy) <= knobRadius
+}
+
+// onPieceEdge 点是否在拼图块外侧一像素的边框上
+func onPieceEdge(px, py int) bool {
+	if inPiece(px, py) {
+		return false
+	}
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		if x, y := px+d[0], py+d[1]; x >= 0 && y >= 0 && inPiece(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// scenery 随机生成的背景：渐变底色上叠加半透明的圆与噪点，使缺口位置无法从背景本身推断
+func scenery(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	from, to := randColor(60, 200), randColor(60, 200)
+	for x := 0; x < width; x++ {
+		t := float64(x) / float64(width-1)
+		c := color.RGBA{mix(from.R, to.R, t), mix(from.G, to.G, t), mix(from.B, to.B, t), 255}
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	for range 12 {
+		c := randColor(30, 255)
+		c.A = 150
+		cx, cy, r := randInt(width), randInt(height), 10+randInt(40)
+		disc := image.NewUniform(c)
+		mask := &circle{image.Pt(cx, cy), r}
+		draw.DrawMask(img, img.Bounds(), disc, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	noise(img, width*height/20)
+	return img
+}
+
+// mix 线性插值
+func mix(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t)
+}
+
+// circle 圆形遮罩
+type circle struct {
+	p image.Point
+	r int
+}
+
+func (c *circle) ColorModel() color.Model { return color.AlphaModel }
+
+func (c *circle) Bounds() image.Rectangle {
+	return image.Rect(c.p.X-c.r, c.p.Y-c.r, c.p.X+c.r, c.p.Y+c.r)
+}
+
+func (c *circle) At(x, y int) color.Color {
+	dx, dy := x-c.p.X, y-c.p.Y
+	if dx*dx+dy*dy <= c.r*c.r {
+		return color.Alpha{255}
+	}
+	return color.Alpha{0}
+}
+
+// encodePNG 编码 PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,121 @@
+package captcha
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// CacheStore 以缓存后端保存答案，多实例部署时使用 Redis
+type CacheStore struct {
+	cache  cache.Store
+	prefix string
+}
+
+// NewCacheStore 创建缓存存储，键名前缀为 captcha:
+func NewCacheStore(s cache.Store) *CacheStore {
+	return &CacheStore{cache: s, prefix: "captcha:"}
+}
+
+// Set 实现 Store
+func (s *CacheStore) Set(c *gin.Context, id, answer string, ttl time.Duration) error {
+	return s.cache.Set(c.Request.Context(), s.prefix+id, []byte(answer), ttl)
+}
+
+// Take 实现 Store
+func (s *CacheStore) Take(c *gin.Context, id string) (string, bool, error) {
+	// 原子地读取并删除，并发提交同一个验证码时只有一个请求能取到答案
+	v, ok, err := cache.Take(c.Request.Context(), s.cache, s.prefix+id)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return string(v), true, nil
+}
+
+// sessionKey 会话中保存验证码答案的键名
+const sessionKey = "_captcha"
+
+// maxSessionEntries 每个会话最多保存的验证码数，超出时丢弃最早过期的
+const maxSessionEntries = 5
+
+// SessionStore 以会话保存答案（ID → "过期时间|答案"），无需额外的存储服务；需挂载会话中间件。
+// 只应与服务端会话（redis、gorm、memory 等）一起使用：cookie 会话中答案保存在客户端，删除只改变新下发的 Cookie，
+// 重新提交旧 Cookie 可在有效期内重复使用同一个答案（NewFromConfig 因此拒绝该组合）
+type SessionStore struct{}
+
+// NewSessionStore 创建会话存储
+func NewSessionStore() *SessionStore {
+	return &SessionStore{}
+}
+
+// errNoSession 未挂载会话中间件
+var errNoSession = errors.New("验证码会话存储需要会话中间件")
+
+// Set 实现 Store
+func (s *SessionStore) Set(c *gin.Context, id, answer string, ttl time.Duration) error {
+	entries, err := s.entries(c)
+	if err != nil {
+		return err
+	}
+	for len(entries) >= maxSessionEntries {
+		oldest, at := "", int64(0)
+		for k, v := range entries {
+			if exp := expiry(v); oldest == "" || exp < at {
+				oldest, at = k, exp
+			}
+		}
+		delete(entries, oldest)
+	}
+	entries[id] = strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10) + "|" + answer
+	return session.Set(c, sessionKey, entries)
+}
+
+// Take 实现 Store
+func (s *SessionStore) Take(c *gin.Context, id string) (string, bool, error) {
+	entries, err := s.entries(c)
+	if err != nil {
+		return "", false, err
+	}
+	v, ok := entries[id]
+	if !ok {
+		return "", false, nil
+	}
+	delete(entries, id)
+	if err := session.Set(c, sessionKey, entries); err != nil {
+		return "", false, err
+	}
+	if expiry(v) < time.Now().UnixNano() {
+		return "", false, nil
+	}
+	_, answer, _ := strings.Cut(v, "|")
+	return answer, true, nil
+}
+
+// entries 读取会话中未过期的验证码
+func (s *SessionStore) entries(c *gin.Context) (map[string]string, error) {
+	if _, ok := c.Get(sessions.DefaultKey); !ok {
+		return nil, errNoSession
+	}
+	stored, _ := session.GetValue(c, sessionKey).(map[string]string)
+	now := time.Now().UnixNano()
+	entries := make(map[string]string, len(stored)+1)
+	for k, v := range stored {
+		if expiry(v) >= now {
+			entries[k] = v
+		}
+	}
+	return entries, nil
+}
+
+// expiry 解析条目的过期时间（Unix 纳秒）
+func expiry(entry string) int64 {
+	exp, _, _ := strings.Cut(entry, "|")
+	n, _ := strconv.ParseInt(exp, 10, 64)
+	return n
+}
@@ -0,0 +1,36 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFake 手动拨动的时钟
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	if !f.Now().Equal(start) {
+		t.Fatalf("Now = %v", f.Now())
+	}
+	f.Advance(90 * time.Minute)
+	if got := f.Since(start); got != 90*time.Minute {
+		t.Errorf("Since = %v", got)
+	}
+	f.Set(start)
+	if got := f.Since(start); got != 0 {
+		t.Errorf("Set 后 Since = %v", got)
+	}
+}
+
+// TestSetDefault 替换与恢复全局时钟
+func TestSetDefault(t *testing.T) {
+	f := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetDefault(f)
+	if Default() != Clock(f) || !Now().Equal(f.Now()) {
+		t.Error("全局时钟未替换")
+	}
+	SetDefault(nil)
+	if _, ok := Default().(Real); !ok {
+		t.Errorf("传入 nil 应恢复系统时钟，得到 %T", Default())
+	}
+}
@@ -0,0 +1,181 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	var errs []error
+	var mu sync.Mutex
+	p := NewPool(WithWorkers(2), WithQueueSize(10), WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}))
+
+	var n atomic.Int32
+	for range 5 {
+		if err := p.Go(func(context.Context) error { n.Add(1); return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Go(func(context.Context) error { return errors.New("failed") })
+	p.Go(func(context.Context) error { panic("boom") })
+
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n.Load() != 5 {
+		t.Errorf("停止前应执行完已提交的任务: %d", n.Load())
+	}
+	s := p.Stats()
+	if s.Completed != 5 || s.Failed != 2 || s.Panics != 1 || s.Running != 0 {
+		t.Errorf("Stats = %+v", s)
+	}
+	var pe *PanicError
+	if len(errs) != 2 || !errors.As(errors.Join(errs...), &pe) || pe.Value != "boom" || len(pe.Stack) == 0 {
+		t.Errorf("错误处理: %v", errs)
+	}
+
+	if err := p.Go(func(context.Context) error { return nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("停止后提交: %v", err)
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Errorf("重复停止: %v", err)
+	}
+}
+
+// TestPoolBackpressure 队列已满时 Go 立即拒绝，Submit 等待空位或 ctx 结束
+func TestPoolBackpressure(t *testing.T) {
+	p := NewPool(WithWorkers(1), WithQueueSize(1))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.Go(func(context.Context) error { close(started); <-release; return nil })
+	<-started
+	p.Go(func(context.Context) error { return nil }) // 占满队列
+
+	if err := p.Go(func(context.Context) error { return nil }); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("队列已满: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, func(context.Context) error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit 超时: %v", err)
+	}
+	if s := p.Stats(); s.Rejected != 2 || s.Queued != 1 || s.Running != 1 {
+		t.Errorf("Stats = %+v", s)
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	if err := p.Submit(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Errorf("Submit 应等待到空位: %v", err)
+	}
+	p.Stop(context.Background())
+}
+
+// TestPoolStopTimeout 等待超时后取消任务的 ctx
+func TestPoolStopTimeout(t *testing.T) {
+	p := NewPool(WithWorkers(1))
+	cancelled := make(chan struct{})
+	started := make(chan struct{})
+	p.Go(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop = %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("超时后任务的 ctx 应被取消")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var g Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	shared := make([]bool, 5)
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _, shared[i] = g.Do("k", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn 应只执行一次: %d", calls.Load())
+	}
+	for i := range 5 {
+		if results[i] != 42 || !shared[i] {
+			t.Errorf("调用 %d: %d, shared=%v", i, results[i], shared[i])
+		}
+	}
+
+	// 调用结束后再次执行
+	if v, _, s := g.Do("k", func() (int, error) { return 7, nil }); v != 7 || s {
+		t.Errorf("再次调用: %d, shared=%v", v, s)
+	}
+
+	var pe *PanicError
+	if _, err, _ := g.Do("p", func() (int, error) { panic("boom") }); !errors.As(err, &pe) {
+		t.Errorf("panic: %v", err)
+	}
+}
+
+// TestGroupDoContext 调用者取消时立即返回，fn 继续执行并把结果交给其他等待者
+func TestGroupDoContext(t *testing.T) {
+	var g Group[string, string]
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		<-release
+		return "done", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		_, err := g.DoContext(ctx, "k", fn)
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	resc := make(chan string)
+	go func() {
+		v, _ := g.DoContext(context.Background(), "k", fn)
+		resc <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("取消的调用者: %v", err)
+	}
+	close(release)
+	if v := <-resc; v != "done" {
+		t.Errorf("其他等待者: %q", v)
+	}
+}
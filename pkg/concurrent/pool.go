@@ -0,0 +1,277 @@
+// Package concurrent 提供进程内的并发工具：有界工作池与 singleflight 合并调用。
+//
+// HTTP 处理函数中需要异步执行的任务（发送通知、刷新缓存等）提交到工作池，而不是直接 go func()：
+// 并发数与排队数有上限，任务 panic 被恢复并记录，应用停止时等待已提交的任务执行完毕。
+//
+//	if err := concurrent.Go(func(ctx context.Context) error {
+//	    return mailer.SendWelcome(ctx, user)
+//	}); err != nil {
+//	    // 队列已满或应用正在停止
+//	}
+//
+// 全局工作池按 concurrent.* 配置创建，由 bootstrap 随应用启动与停止。
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+)
+
+var (
+	// ErrQueueFull 等待队列已满（Go 不阻塞等待）
+	ErrQueueFull = errors.New("任务队列已满")
+	// ErrPoolClosed 工作池已停止，不再接受任务
+	ErrPoolClosed = errors.New("工作池已停止")
+)
+
+// Task 工作池执行的任务，ctx 在工作池停止且等待超时后取消
+type Task func(ctx context.Context) error
+
+// PanicError 任务或合并调用 panic 时的错误
+type PanicError struct {
+	Value any    // recover() 得到的值
+	Stack []byte // panic 时的调用栈
+}
+
+// Error 实现 error
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("任务 panic: %v", e.Value)
+}
+
+// Stats 工作池运行统计
+type Stats struct {
+	Workers   int   `json:"workers"`   // 工作协程数
+	Queued    int   `json:"queued"`    // 等待执行的任务数
+	Running   int64 `json:"running"`   // 正在执行的任务数
+	Completed int64 `json:"completed"` // 已成功完成的任务数
+	Failed    int64 `json:"failed"`    // 返回错误的任务数（含 panic）
+	Panics    int64 `json:"panics"`    // panic 的任务数
+	Rejected  int64 `json:"rejected"`  // 因队列已满或已停止被拒绝的任务数
+}
+
+// Pool 有界工作池：固定数量的工作协程从有界队列中取任务执行
+type Pool struct {
+	name      string
+	workers   int
+	queue     chan Task
+	onError   func(error)
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	mu        sync.RWMutex // 保护 closed 与向 queue 发送，避免向已关闭的 channel 发送
+	closed    bool
+	running   atomic.Int64
+	completed atomic.Int64
+	failed    atomic.Int64
+	panics    atomic.Int64
+	rejected  atomic.Int64
+}
+
+// PoolOption 工作池选项
+type PoolOption func(*Pool)
+
+// WithWorkers 设置工作协程数，默认 CPU 核数
+func WithWorkers(n int) PoolOption {
+	return func(p *Pool) {
+		if n > 0 {
+			p.workers = n
+		}
+	}
+}
+
+// WithQueueSize 设置等待队列长度，默认 1024
+func WithQueueSize(n int) PoolOption {
+	return func(p *Pool) {
+		if n >= 0 {
+			p.queue = make(chan Task, n)
+		}
+	}
+}
+
+// WithName 设置工作池名称：用于日志，并将 Stats 发布到 expvar（/debug/vars 中的 pool.<name>）
+func WithName(name string) PoolOption {
+	return func(p *Pool) { p.name = name }
+}
+
+// WithErrorHandler 设置任务返回错误或 panic 时的处理函数，默认写错误日志
+func WithErrorHandler(fn func(error)) PoolOption {
+	return func(p *Pool) { p.onError = fn }
+}
+
+// NewPool 创建并启动工作池
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{workers: runtime.NumCPU(), queue: make(chan Task, 1024)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.onError == nil {
+		p.onError = p.logError
+	}
+	if p.name != "" && expvar.Get("pool."+p.name) == nil {
+		expvar.Publish("pool."+p.name, expvar.Func(func() any { return p.Stats() }))
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.wg.Add(p.workers)
+	for range p.workers {
+		go p.work()
+	}
+	return p
+}
+
+// NewPoolFromConfig 按 concurrent.* 配置创建工作池
+func NewPoolFromConfig(cfg *config.ConcurrentConfig) *Pool {
+	return NewPool(WithName("default"), WithWorkers(cfg.Workers), WithQueueSize(cfg.QueueSize))
+}
+
+// work 工作协程：执行任务直到队列关闭且取空
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.queue {
+		p.run(task)
+	}
+}
+
+// run 执行单个任务并恢复 panic
+func (p *Pool) run(task Task) {
+	p.running.Add(1)
+	defer p.running.Add(-1)
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				p.panics.Add(1)
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		return task(p.ctx)
+	}()
+	if err != nil {
+		p.failed.Add(1)
+		p.onError(err)
+		return
+	}
+	p.completed.Add(1)
+}
+
+// logError 默认的错误处理：写错误日志，panic 附带调用栈
+func (p *Pool) logError(err error) {
+	if logger.SugarLogger == nil {
+		return
+	}
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		logger.SugarLogger.Errorw("工作池任务 panic", "pool", p.name, "panic", pe.Value, "stack", string(pe.Stack))
+		return
+	}
+	logger.SugarLogger.Errorw("工作池任务失败", "pool", p.name, "error", err)
+}
+
+// Submit 提交任务，队列已满时等待空位，直到 ctx 结束
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.rejected.Add(1)
+		return ErrPoolClosed
+	}
+	select {
+	case p.queue <- task:
+		return nil
+	case <-ctx.Done():
+		p.rejected.Add(1)
+		return ctx.Err()
+	}
+}
+
+// Go 提交任务，不等待：队列已满时返回 ErrQueueFull，适合在 HTTP 处理函数中调用
+func (p *Pool) Go(task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.rejected.Add(1)
+		return ErrPoolClosed
+	}
+	select {
+	case p.queue <- task:
+		return nil
+	default:
+		p.rejected.Add(1)
+		return ErrQueueFull
+	}
+}
+
+// Stop 停止接受任务并等待已提交的任务执行完毕；ctx 结束时取消任务的 ctx 并返回 ctx.Err()，可重复调用
+func (p *Pool) Stop(ctx context.Context) error {
+	// 写锁等待阻塞中的 Submit 入队后再关闭队列（工作协程持续取任务，Submit 总会返回）
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats 返回运行统计
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Workers:   p.workers,
+		Queued:    len(p.queue),
+		Running:   p.running.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Panics:    p.panics.Load(),
+		Rejected:  p.rejected.Load(),
+	}
+}
+
+var defaultPool atomic.Pointer[Pool]
+
+// SetDefault 设置全局工作池（由 bootstrap 调用）
+func SetDefault(p *Pool) {
+	defaultPool.Store(p)
+}
+
+// Default 返回全局工作池，未初始化时返回 nil
+func Default() *Pool {
+	return defaultPool.Load()
+}
+
+// Go 向全局工作池提交任务，见 Pool.Go；未初始化时返回 ErrPoolClosed
+func Go(task Task) error {
+	p := Default()
+	if p == nil {
+		return ErrPoolClosed
+	}
+	return p.Go(task)
+}
+
+// Submit 向全局工作池提交任务，见 Pool.Submit；未初始化时返回 ErrPoolClosed
+func Submit(ctx context.Context, task Task) error {
+	p := Default()
+	if p == nil {
+		return ErrPoolClosed
+	}
+	return p.Submit(ctx, task)
+}
@@ -0,0 +1,98 @@
+package concurrent
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Group 合并相同 key 的并发调用：同一时刻只执行一次 fn，其余调用者等待并共享结果，
+// 避免缓存失效时大量请求同时回源（缓存击穿）
+//
+//	var users concurrent.Group[int64, *User]
+//	u, err, _ := users.Do(id, func() (*User, error) { return repo.Find(id) })
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// call 进行中的调用
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+	dups int
+}
+
+// Do 执行并返回 fn 的结果，shared 表示结果是否由多个调用者共享；fn panic 时所有调用者得到 *PanicError
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	c, leader := g.join(key)
+	if leader {
+		g.execute(key, c, fn)
+	}
+	<-c.done
+	return c.val, c.err, g.shared(c)
+}
+
+// DoContext 同 Do，但等待可被 ctx 取消：调用者取消时立即返回 ctx.Err()，
+// fn 以不随调用者取消的 ctx 继续执行，结果仍交给其他等待者
+func (g *Group[K, V]) DoContext(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error) {
+	c, leader := g.join(key)
+	if leader {
+		detached := context.WithoutCancel(ctx)
+		go g.execute(key, c, func() (V, error) { return fn(detached) })
+	}
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Forget 使 key 的下一次调用重新执行 fn，而不是等待进行中的调用
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
+
+// join 加入 key 的进行中调用，没有时创建并返回 leader=true
+func (g *Group[K, V]) join(key K) (*call[V], bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		return c, false
+	}
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	return c, true
+}
+
+// execute 执行 fn 并唤醒等待者
+func (g *Group[K, V]) execute(key K, c *call[V], fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+}
+
+// shared 结果是否被多个调用者共享
+func (g *Group[K, V]) shared(c *call[V]) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return c.dups > 0
+}
@@ -9,107 +9,529 @@ import (
 	"github.com/spf13/viper"
 )
 
-// Config 应用配置结构
+// Config 应用配置结构。新增配置项时在 setDefaults 中注册默认值，并在 config.yaml 中以行尾注释
+// 或在字段的 desc 标签中说明（Reference 据此生成配置项与环境变量对照表）；业务模块自己的配置段通过 Register 注册
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Log      LogConfig      `mapstructure:"log"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Template TemplateConfig `mapstructure:"template"`
-	Static   StaticConfig   `mapstructure:"static"`
-	Session  SessionConfig  `mapstructure:"session"`
+	App        AppConfig        `mapstructure:"app"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Log        LogConfig        `mapstructure:"log"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	Template   TemplateConfig   `mapstructure:"template"`
+	Static     StaticConfig     `mapstructure:"static"`
+	Vite       ViteConfig       `mapstructure:"vite"`
+	Session    SessionConfig    `mapstructure:"session"`
+	Crypto     CryptoConfig     `mapstructure:"crypto"`
+	Hash       HashConfig       `mapstructure:"hash"`
+	Tenant     TenantConfig     `mapstructure:"tenant"`
+	GeoIP      GeoIPConfig      `mapstructure:"geoip"`
+	SEO        SEOConfig        `mapstructure:"seo"`
+	Image      ImageConfig      `mapstructure:"image"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Lock       LockConfig       `mapstructure:"lock"`
+	Concurrent ConcurrentConfig `mapstructure:"concurrent"`
+	Settings   SettingsConfig   `mapstructure:"settings"`
+	Search     SearchConfig     `mapstructure:"search"`
+	PDF        PDFConfig        `mapstructure:"pdf"`
+	Captcha    CaptchaConfig    `mapstructure:"captcha"`
+	SMS        SMSConfig        `mapstructure:"sms"`
+	Webhook    WebhookConfig    `mapstructure:"webhook"`
+	Outbox     OutboxConfig     `mapstructure:"outbox"`
+	Broadcast  BroadcastConfig  `mapstructure:"broadcast"`
+	Notify     NotifyConfig     `mapstructure:"notify"`
+	Resilience ResilienceConfig `mapstructure:"resilience"`
+	ErrorRate  ErrorRateConfig  `mapstructure:"error_rate"`
+	Record     RecordConfig     `mapstructure:"record"`
+}
+
+// 运行环境，与 gin 运行模式（server.mode）相互独立
+const (
+	EnvDevelopment = "development"
+	EnvTesting     = "testing"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// envAliases 运行环境的常见简写
+var envAliases = map[string]string{
+	"dev":   EnvDevelopment,
+	"test":  EnvTesting,
+	"stage": EnvStaging,
+	"prod":  EnvProduction,
+}
+
+// AppConfig 应用配置
+type AppConfig struct {
+	// 运行环境: development, testing, staging, production（支持 dev/test/stage/prod 简写）。
+	// 为空时按 server.mode 推断：debug → development，test → testing，其他 → production
+	Env string `mapstructure:"env"`
+	// 应用名称与版本，写入每条文件日志的 app、version 字段（见 logger.DefaultFields），版本通常在部署时经 APP_VERSION 注入
+	Name    string `mapstructure:"name"`
+	Version string `mapstructure:"version"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port            int    `mapstructure:"port"`
+	Port            int    `mapstructure:"port" desc:"监听端口"`
 	Mode            string `mapstructure:"mode"`
-	ReadTimeout     int    `mapstructure:"read_timeout"`
-	WriteTimeout    int    `mapstructure:"write_timeout"`
-	IdleTimeout     int    `mapstructure:"idle_timeout"`
+	ReadTimeout     int    `mapstructure:"read_timeout" desc:"读取请求的超时（秒）"`
+	WriteTimeout    int    `mapstructure:"write_timeout" desc:"写出响应的超时（秒）"`
+	IdleTimeout     int    `mapstructure:"idle_timeout" desc:"keep-alive 连接的空闲超时（秒）"`
 	EnableRateLimit bool   `mapstructure:"enable_rate_limit"`
-	RateLimit       int    `mapstructure:"rate_limit"` // 每秒请求数
-	RateBurst       int    `mapstructure:"rate_burst"` // 突发请求数
+	RateLimit       int    `mapstructure:"rate_limit"`    // 每秒请求数
+	RateBurst       int    `mapstructure:"rate_burst"`    // 突发请求数
+	MinifyHTML      bool   `mapstructure:"minify_html"`   // 非 debug 模式下压缩 HTML 输出
+	DebugToolbar    bool   `mapstructure:"debug_toolbar"` // debug 模式下在 HTML 页面注入调试工具栏
+	// gzip 压缩响应（见 middleware.Compress），路由可用 NoCompress、Compress(level) 单独设置
+	Compress bool `mapstructure:"compress"`
+	// gzip 压缩级别：1（最快）~ 9（压缩率最高）
+	CompressLevel int `mapstructure:"compress_level"`
+	// 不压缩的路径前缀
+	CompressExcept []string `mapstructure:"compress_except"`
+	// 同时处理的请求数上限（见 middleware.MaxInFlight），0 表示不限制
+	MaxInFlight int `mapstructure:"max_in_flight"`
+	// 并发已满时请求排队等待的秒数，超时返回 503，0 表示不排队
+	QueueTimeout int `mapstructure:"queue_timeout"`
+	// 为请求上下文设置比 write_timeout 略早的截止时间（见 middleware.Deadline），到期后取消下游 SQL、Redis 与 HTTP 调用
+	RequestDeadline bool `mapstructure:"request_deadline"`
+	// 统一响应按 Accept 头以 XML 或 MessagePack 输出，关闭时始终输出 JSON
+	ContentNegotiation bool `mapstructure:"content_negotiation"`
+	// 统一响应与模板 dump 使用的 JSON 引擎：std、go-json、sonic，为空时按构建标签选择（见 jsonx）
+	JSONEngine string `mapstructure:"json_engine"`
+	// request.RawBody 读取请求体的上限（字节）
+	MaxBodySize int64 `mapstructure:"max_body_size"`
+	// 启动时打印 Logo 与 "Press Ctrl+C" 提示（容器日志中可关闭，仅保留服务摘要）
+	Banner bool `mapstructure:"banner"`
+	// 启动摘要使用 ANSI 颜色（设置环境变量 NO_COLOR 时同样关闭）
+	BannerColor bool `mapstructure:"banner_color"`
+	// 开发错误页中文件链接打开的编辑器：vscode、vscode-insiders、cursor、goland、idea、sublime，
+	// 或含 {file}、{line} 占位符的自定义 URL 模板，为空或 none 时不生成链接
+	Editor string `mapstructure:"editor"`
+	// 非 debug 模式下访问 /debug/pprof、/debug/vars 所需的管理令牌，为空时不注册这些端点
+	DebugToken string `mapstructure:"debug_token"`
 	// 可信代理列表（IP 或 CIDR）。仅当请求的直接来源在此列表内时，
 	// 才信任 X-Forwarded-For/X-Real-IP 解析真实客户端 IP，防止伪造头绕过 IP 限流。
-	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	TrustedProxies []string `mapstructure:"trusted_proxies" desc:"可信代理列表（IP 或 CIDR），仅信任来自这些地址的 X-Forwarded-For 等转发头"`
+	// POST 表单的 _method 字段（或 X-HTTP-Method-Override 头）伪造为 PUT、PATCH、DELETE
+	MethodOverride bool `mapstructure:"method_override"`
+	// 对 POST、PUT、PATCH、DELETE 请求校验 CSRF 令牌（表单字段 _csrf 或 X-CSRF-Token 头）
+	CSRF bool `mapstructure:"csrf"`
+	// 跳过 CSRF 校验的路径前缀，如使用 JWT 认证的 /api/
+	CSRFExcept []string `mapstructure:"csrf_except"`
 }
 
 // LogConfig 日志配置
 type LogConfig struct {
 	Level      string `mapstructure:"level"`
-	Filename   string `mapstructure:"filename"`
+	Filename   string `mapstructure:"filename" desc:"日志文件路径"`
 	MaxSize    int    `mapstructure:"max_size"`
-	MaxBackups int    `mapstructure:"max_backups"`
+	MaxBackups int    `mapstructure:"max_backups" desc:"保留的旧日志文件数"`
 	MaxAge     int    `mapstructure:"max_age"`
-	Compress   bool   `mapstructure:"compress"`
+	Compress   bool   `mapstructure:"compress" desc:"是否压缩轮转后的旧日志"`
 	Format     string `mapstructure:"format"`
 	Stdout     bool   `mapstructure:"stdout"` // 是否同时输出到控制台
+	// 写入每条文件日志的自定义全局字段，如 {region: cn-east-1}
+	Fields map[string]string `mapstructure:"fields"`
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
 	Driver          string `mapstructure:"driver"`
-	Host            string `mapstructure:"host"`
-	Port            int    `mapstructure:"port"`
-	Username        string `mapstructure:"username"`
-	Password        string `mapstructure:"password"`
-	DBName          string `mapstructure:"dbname"`
-	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
-	MaxOpenConns    int    `mapstructure:"max_open_conns"`
+	Host            string `mapstructure:"host" desc:"数据库主机"`
+	Port            int    `mapstructure:"port" desc:"数据库端口"`
+	Username        string `mapstructure:"username" desc:"用户名"`
+	Password        string `mapstructure:"password" desc:"密码"`
+	DBName          string `mapstructure:"dbname" desc:"数据库名"`
+	MaxIdleConns    int    `mapstructure:"max_idle_conns" desc:"连接池最大空闲连接数"`
+	MaxOpenConns    int    `mapstructure:"max_open_conns" desc:"连接池最大打开连接数"`
 	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+	ModelEvents     bool   `mapstructure:"model_events"` // 是否将模型创建/更新/删除桥接到事件总线
+	BatchSize       int    `mapstructure:"batch_size"`   // 批量插入每批行数（db.Create 切片与 database.BulkInsert 等）
 }
 
 // RedisConfig Redis配置
 type RedisConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
-	PoolSize int    `mapstructure:"pool_size"`
+	Host     string `mapstructure:"host" desc:"主机"`
+	Port     int    `mapstructure:"port" desc:"端口"`
+	Password string `mapstructure:"password" desc:"密码"`
+	DB       int    `mapstructure:"db" desc:"数据库编号"`
+	PoolSize int    `mapstructure:"pool_size" desc:"连接池大小"`
+	// 以下仅用于 pkg/redis 的共享客户端
+	Mode             string   `mapstructure:"mode"`              // standalone / sentinel / cluster
+	Addrs            []string `mapstructure:"addrs"`             // 哨兵地址或集群节点地址，单机模式下为空时使用 host:port
+	MasterName       string   `mapstructure:"master_name"`       // 哨兵模式的主节点名称
+	SentinelPassword string   `mapstructure:"sentinel_password"` // 哨兵的密码
+	Username         string   `mapstructure:"username"`          // ACL 用户名（Redis 6+）
+	MinIdleConns     int      `mapstructure:"min_idle_conns"`    // 最少空闲连接数
+	DialTimeout      int      `mapstructure:"dial_timeout"`      // 连接超时（秒）
+	ReadTimeout      int      `mapstructure:"read_timeout"`      // 读超时（秒）
+	WriteTimeout     int      `mapstructure:"write_timeout"`     // 写超时（秒）
+	TLS              bool     `mapstructure:"tls"`               // 使用 TLS 连接（云服务商托管的 Redis）
 }
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret string `mapstructure:"secret"`
+	Secret string `mapstructure:"secret" desc:"签名密钥"`
 	Expire int    `mapstructure:"expire"`
-	Issuer string `mapstructure:"issuer"`
+	Issuer string `mapstructure:"issuer" desc:"签发者（iss）"`
 }
 
 // TemplateConfig 模板配置
 type TemplateConfig struct {
-	Path          string `mapstructure:"path"`
-	LayoutDir     string `mapstructure:"layout_dir"`
-	Extension     string `mapstructure:"extension"`
-	DefaultLayout string `mapstructure:"default_layout"`
+	Path          string `mapstructure:"path" desc:"模板根目录"`
+	LayoutDir     string `mapstructure:"layout_dir" desc:"布局模板目录（相对模板根目录）"`
+	PartialDir    string `mapstructure:"partial_dir"` // include 找不到模板时查找的局部模板目录
+	Extension     string `mapstructure:"extension" desc:"模板文件扩展名"`
+	DefaultLayout string `mapstructure:"default_layout" desc:"默认布局"`
+	// 额外模板根目录，在 path 之后依次查找（如第三方包自带模板）
+	Roots []string `mapstructure:"roots"`
+	// 主题目录（相对各根目录）、默认主题与主题回退链
+	ThemeDir       string   `mapstructure:"theme_dir"`
+	Theme          string   `mapstructure:"theme"`
+	ThemeFallbacks []string `mapstructure:"theme_fallbacks"`
+	// 模板函数 asset 生成的静态资源 URL 前缀
+	AssetPrefix string `mapstructure:"asset_prefix"`
+	// 模板缓存上限（条目数 / 按源文件估算的字节数），0 表示不限
+	CacheMaxEntries int   `mapstructure:"cache_max_entries"`
+	CacheMaxBytes   int64 `mapstructure:"cache_max_bytes"`
+	// 严格模式：访问不存在的变量时渲染失败（missingkey=error）而不是输出空值；开发模式下始终启用
+	Strict bool `mapstructure:"strict"`
 }
 
 // StaticConfig 静态文件配置
 type StaticConfig struct {
-	Path string `mapstructure:"path"`
+	Path string `mapstructure:"path" desc:"静态文件目录"`
+}
+
+// ViteConfig 前端资源构建（Vite）配置
+type ViteConfig struct {
+	// 开发服务器地址，如 http://localhost:5173；为空或非 debug 模式时读取构建产物 manifest
+	DevServer string `mapstructure:"dev_server"`
+	// 是否通过 /__vite 同源代理开发服务器（浏览器无法直接访问开发服务器端口时开启）
+	Proxy bool `mapstructure:"proxy"`
+	// 构建产物 manifest 路径（vite build --manifest）
+	Manifest string `mapstructure:"manifest"`
+	// 构建产物 URL 前缀，需与 vite.config 中的 base 一致
+	Base string `mapstructure:"base"`
 }
 
 // SessionConfig 会话配置
 type SessionConfig struct {
-	// 存储类型: cookie, redis
+	// 存储类型: cookie, redis, gorm, memory, memcached, mongo 或通过 session.RegisterStore 注册的自定义存储
 	Store string `mapstructure:"store"`
 	// 会话名称
-	Name string `mapstructure:"name"`
+	Name string `mapstructure:"name" desc:"会话名称"`
 	// 密钥
-	Secret string `mapstructure:"secret"`
+	Secret string `mapstructure:"secret" desc:"密钥"`
 	// 过期时间（分钟）
 	MaxAge int `mapstructure:"max_age"`
 	// 是否只在HTTPS下发送Cookie
-	Secure bool `mapstructure:"secure"`
+	Secure bool `mapstructure:"secure" desc:"是否只在HTTPS下发送Cookie"`
 	// 是否禁止JavaScript访问Cookie
-	HttpOnly bool `mapstructure:"http_only"`
+	HttpOnly bool `mapstructure:"http_only" desc:"是否禁止JavaScript访问Cookie"`
 	// Cookie路径
-	Path string `mapstructure:"path"`
+	Path string `mapstructure:"path" desc:"Cookie路径"`
 	// Cookie域
-	Domain string `mapstructure:"domain"`
+	Domain string `mapstructure:"domain" desc:"Cookie域"`
 	// SameSite策略
 	SameSite string `mapstructure:"same_site"`
+	// 是否按用户索引登录会话（支持查看活跃会话、强制下线），redis/gorm 存储下索引与会话共用后端
+	Index bool `mapstructure:"index"`
+	// 存储后端不可用时降级为 Cookie 存储（需启用 resilience，见 session.FallbackStore），store 为 cookie 时无效
+	Fallback bool `mapstructure:"fallback"`
+	// Memcached 存储配置（store: memcached，需以 -tags memcached 构建）
+	Memcached SessionMemcachedConfig `mapstructure:"memcached"`
+	// MongoDB 存储配置（store: mongo，需以 -tags mongo 构建）
+	Mongo SessionMongoConfig `mapstructure:"mongo"`
+}
+
+// SessionMemcachedConfig Memcached 会话存储配置
+type SessionMemcachedConfig struct {
+	Addrs  []string `mapstructure:"addrs" desc:"服务器地址列表，如 127.0.0.1:11211"`
+	Prefix string   `mapstructure:"prefix" desc:"键前缀"`
+}
+
+// SessionMongoConfig MongoDB 会话存储配置
+type SessionMongoConfig struct {
+	URI        string `mapstructure:"uri" desc:"连接地址，如 mongodb://127.0.0.1:27017"`
+	Database   string `mapstructure:"database" desc:"数据库名"`
+	Collection string `mapstructure:"collection" desc:"集合名"`
+}
+
+// CryptoConfig 数据加密配置
+type CryptoConfig struct {
+	// 当前密钥（16/24/32 字节，或 "base64:" 前缀的 Base64 编码）
+	Key string `mapstructure:"key"`
+	// 轮换前的旧密钥，仅用于解密历史数据
+	PreviousKeys []string `mapstructure:"previous_keys"`
+}
+
+// HashConfig 密码哈希配置
+type HashConfig struct {
+	Driver        string `mapstructure:"driver"` // bcrypt / argon2id
+	BcryptCost    int    `mapstructure:"bcrypt_cost" desc:"bcrypt 代价（4-31）"`
+	Argon2Memory  uint32 `mapstructure:"argon2_memory"` // argon2id 内存（KiB）
+	Argon2Time    uint32 `mapstructure:"argon2_time" desc:"argon2id 迭代次数"`
+	Argon2Threads uint8  `mapstructure:"argon2_threads" desc:"argon2id 并行度"`
+}
+
+// SettingsConfig 运行时配置（数据库键值存储）
+type SettingsConfig struct {
+	Enabled  bool `mapstructure:"enabled"`   // 启动时初始化配置表，使模板函数 setting 可用
+	CacheTTL int  `mapstructure:"cache_ttl"` // 缓存有效期（秒）
+}
+
+// SearchConfig 全文检索配置
+type SearchConfig struct {
+	Enabled bool `mapstructure:"enabled"` // 启动时按 driver 创建全局检索引擎（search.Search 使用）
+	// 驱动：memory（开发、测试）、mysql（FULLTEXT 索引）、meilisearch、elasticsearch
+	Driver   string `mapstructure:"driver"`
+	URL      string `mapstructure:"url"`      // meilisearch / elasticsearch 服务地址
+	APIKey   string `mapstructure:"api_key"`  // meilisearch 主密钥或 elasticsearch API Key
+	Username string `mapstructure:"username"` // elasticsearch Basic 鉴权（未设置 api_key 时）
+	Password string `mapstructure:"password" desc:"elasticsearch Basic 鉴权密码"`
+	Prefix   string `mapstructure:"prefix"`  // 索引名前缀，多个环境共用检索服务时区分
+	Timeout  int    `mapstructure:"timeout"` // 请求检索服务的超时（秒）
+	// mysql：表名 → FULLTEXT 索引包含的列
+	Columns     map[string][]string `mapstructure:"columns"`
+	BooleanMode bool                `mapstructure:"boolean_mode"` // mysql：以 IN BOOLEAN MODE 检索
+	Sync        bool                `mapstructure:"sync"`         // 模型创建/更新/删除后同步索引（需开启 database.model_events）
+	Queue       bool                `mapstructure:"queue"`        // 同步索引提交到全局工作池异步执行，不阻塞数据库写操作
+}
+
+// PDFConfig PDF 生成配置
+type PDFConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // 启动时创建全局 PDF 生成器（pdf.Render 使用）
+	Driver  string `mapstructure:"driver"`  // 驱动：chrome、wkhtmltopdf
+	Binary  string `mapstructure:"binary"`  // 可执行文件路径，为空时在 PATH 中查找
+	URL     string `mapstructure:"url"`     // chrome：已运行实例的 DevTools 地址，如 http://chrome:9222，设置后不启动本机进程
+	// 默认纸张规格：A3、A4、A5、B5、Letter、Legal、Tabloid
+	PageSize  string  `mapstructure:"page_size"`
+	Landscape bool    `mapstructure:"landscape"` // 默认横向输出
+	Margin    float64 `mapstructure:"margin"`    // 默认页边距（毫米，四边相同）
+	Layout    string  `mapstructure:"layout"`    // 页面模板的默认布局，为空时不使用布局
+	Header    string  `mapstructure:"header"`    // 默认页眉模板，为空时无页眉
+	Footer    string  `mapstructure:"footer"`    // 默认页脚模板，为空时无页脚
+	Timeout   int     `mapstructure:"timeout"`   // 单个文档的转换超时（秒）
+}
+
+// CaptchaConfig 验证码配置
+type CaptchaConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`    // 注册验证码路由并设置全局验证码服务
+	Route     string `mapstructure:"route"`      // 路由前缀，如 /captcha
+	Store     string `mapstructure:"store"`      // 答案存储：memory、redis（使用 redis 配置）、session
+	Length    int    `mapstructure:"length"`     // 图形验证码字符数
+	Chars     string `mapstructure:"chars"`      // 图形验证码字符集，为空时使用不含易混淆字符的数字与字母
+	Width     int    `mapstructure:"width"`      // 图形验证码宽度（像素）
+	Height    int    `mapstructure:"height"`     // 图形验证码高度（像素）
+	TTL       int    `mapstructure:"ttl"`        // 有效期（秒）
+	Tolerance int    `mapstructure:"tolerance"`  // 滑块验证码允许的横坐标误差（像素）
+	RateLimit int    `mapstructure:"rate_limit"` // 每个 IP 每秒可生成的验证码数，0 表示不限流
+	Threshold int    `mapstructure:"threshold"`  // Guard 默认的失败次数阈值，达到后要求验证码，0 表示始终要求
+	Window    int    `mapstructure:"window"`     // Guard 失败计数的有效期（秒）
+}
+
+// SMSConfig 短信配置
+type SMSConfig struct {
+	Enabled   bool                         `mapstructure:"enabled"`   // 启动时创建全局短信发送器（sms.Send 使用）
+	Driver    string                       `mapstructure:"driver"`    // 驱动：log（仅写日志）、aliyun、twilio
+	Store     string                       `mapstructure:"store"`     // 验证码状态存储：memory、redis（使用 redis 配置）
+	Aliyun    SMSAliyunConfig              `mapstructure:"aliyun"`    // 阿里云短信
+	Twilio    SMSTwilioConfig              `mapstructure:"twilio"`    // Twilio
+	Templates map[string]SMSTemplateConfig `mapstructure:"templates"` // 模板名称 → 模板
+	Code      SMSCodeConfig                `mapstructure:"code"`      // 验证码规则
+}
+
+// SMSAliyunConfig 阿里云短信配置
+type SMSAliyunConfig struct {
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	AccessKeySecret string `mapstructure:"access_key_secret"`
+	SignName        string `mapstructure:"sign_name"` // 短信签名
+	Region          string `mapstructure:"region" desc:"地域，为空时为 cn-hangzhou"`
+	Endpoint        string `mapstructure:"endpoint"` // 接口地址，为空时为 https://dysmsapi.aliyuncs.com
+}
+
+// SMSTwilioConfig Twilio 配置
+type SMSTwilioConfig struct {
+	AccountSID string `mapstructure:"account_sid" desc:"Twilio 账号 SID"`
+	AuthToken  string `mapstructure:"auth_token"`
+	From       string `mapstructure:"from"` // 发送号码（E.164）或 Messaging Service SID
+}
+
+// SMSTemplateConfig 短信模板配置
+type SMSTemplateConfig struct {
+	Code    string `mapstructure:"code"`    // 服务商模板编号（阿里云）
+	Content string `mapstructure:"content"` // 正文，${name} 以参数替换（Twilio、log）
+}
+
+// SMSCodeConfig 短信验证码配置
+type SMSCodeConfig struct {
+	Template    string `mapstructure:"template"`     // 发送验证码使用的模板，参数为 code 与 minutes
+	Length      int    `mapstructure:"length"`       // 位数
+	TTL         int    `mapstructure:"ttl"`          // 有效期（秒）
+	Cooldown    int    `mapstructure:"cooldown"`     // 同一手机号同一用途两次发送的最小间隔（秒）
+	MaxAttempts int    `mapstructure:"max_attempts"` // 每个验证码允许的错误次数
+	DailyLimit  int    `mapstructure:"daily_limit"`  // 同一手机号每日最多发送数，0 表示不限制
+}
+
+// WebhookConfig 出站 Webhook 配置
+type WebhookConfig struct {
+	Enabled     bool     `mapstructure:"enabled"`      // 启动时创建端点与投递表并运行后台投递循环
+	Events      []string `mapstructure:"events"`       // 自动转发为 Webhook 的事件总线事件，支持通配符
+	MaxAttempts int      `mapstructure:"max_attempts"` // 单个投递的最大请求次数，超过后标记为 failed
+	Timeout     int      `mapstructure:"timeout"`      // 请求端点的超时（秒）
+	Interval    int      `mapstructure:"interval"`     // 后台轮询间隔（秒）
+	BatchSize   int      `mapstructure:"batch_size"`   // 每次轮询最多投递的记录数
+	Backoff     int      `mapstructure:"backoff"`      // 首次重试间隔（秒），之后每次翻倍
+	MaxBackoff  int      `mapstructure:"max_backoff"`  // 重试间隔上限（秒）
+}
+
+// OutboxConfig 事件发件箱配置
+type OutboxConfig struct {
+	Enabled     bool `mapstructure:"enabled"`      // 启动时创建发件箱表并运行后台投递循环
+	MaxAttempts int  `mapstructure:"max_attempts"` // 单个事件的最大投递次数，超过后标记为 failed
+	Interval    int  `mapstructure:"interval"`     // 后台轮询间隔（秒）
+	BatchSize   int  `mapstructure:"batch_size"`   // 每次轮询最多投递的事件数
+}
+
+// BroadcastConfig 实时广播配置
+type BroadcastConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`         // 注册 WebSocket 路由并设置全局广播器
+	Path           string   `mapstructure:"path"`            // WebSocket 路由
+	Backplane      string   `mapstructure:"backplane"`       // memory / redis，多实例部署使用 redis
+	RedisChannel   string   `mapstructure:"redis_channel"`   // redis 背板使用的 pub/sub 频道
+	AllowedOrigins []string `mapstructure:"allowed_origins"` // 允许连接的页面来源，为空时只允许同源，"*" 表示全部
+	PingTimeout    int      `mapstructure:"ping_timeout"`    // 连接无消息的超时（秒）
+	SendBuffer     int      `mapstructure:"send_buffer"`     // 每个连接待发送消息的缓冲数
+}
+
+// NotifyConfig 通知推送配置
+type NotifyConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`       // 注册 SSE 路由、共享闪存通知给视图并设置全局通知中心
+	Path         string `mapstructure:"path"`          // SSE 路由
+	Backplane    string `mapstructure:"backplane"`     // memory / redis，多实例或独立任务进程使用 redis
+	RedisChannel string `mapstructure:"redis_channel"` // redis 背板使用的 pub/sub 频道
+	Heartbeat    int    `mapstructure:"heartbeat"`     // SSE 心跳间隔（秒）
+	PendingLimit int    `mapstructure:"pending_limit"` // 用户离线时每人最多暂存的通知数，0 表示不暂存
+	PendingTTL   int    `mapstructure:"pending_ttl"`   // 离线通知的暂存时长（秒）
+}
+
+// ResilienceConfig 依赖故障降级配置
+type ResilienceConfig struct {
+	Enabled      bool     `mapstructure:"enabled" desc:"定时探测依赖并设置全局探测器"`
+	Dependencies []string `mapstructure:"dependencies"` // 探测的内置依赖：db、redis
+	Interval     int      `mapstructure:"interval"`     // 探测间隔（秒）
+	Timeout      int      `mapstructure:"timeout"`      // 单次探测超时（秒）
+	Threshold    int      `mapstructure:"threshold"`    // 标记为不可用所需的连续失败次数
+	// 依赖不可用时关闭的功能，如 {db: [checkout]}，见 resilience.RequireFeature
+	DisableFeatures map[string][]string `mapstructure:"disable_features"`
+}
+
+// ErrorRateConfig 路由 5xx 比例告警配置
+type ErrorRateConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`      // 统计各路由的 5xx 比例，超过阈值时触发 alert.error_rate 事件
+	Window      int     `mapstructure:"window"`       // 滚动统计窗口（秒）
+	Threshold   float64 `mapstructure:"threshold"`    // 告警阈值（窗口内 5xx 响应占比）
+	MinRequests int     `mapstructure:"min_requests"` // 窗口内请求数达到该值才判断，避免低流量误报
+	WebhookURL  string  `mapstructure:"webhook_url"`  // 告警与恢复时 POST JSON 的地址，为空时只触发事件
+}
+
+// RecordConfig 请求录制配置
+type RecordConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`        // 将请求（含请求体）写为 HAR 文件，供 request:replay 重放
+	Dir           string   `mapstructure:"dir"`            // HAR 文件目录
+	Paths         []string `mapstructure:"paths"`          // 只录制这些路径前缀，为空时录制全部
+	MinStatus     int      `mapstructure:"min_status"`     // 只录制响应状态码不小于该值的请求，0 表示全部
+	RedactHeaders []string `mapstructure:"redact_headers"` // 写入前脱敏的请求头，重放时不发送
+}
+
+// TenantConfig 多租户配置
+type TenantConfig struct {
+	Enabled    bool   `mapstructure:"enabled" desc:"启用多租户解析"`
+	Resolver   string `mapstructure:"resolver"`    // subdomain / header / path，可用逗号组合，按顺序尝试
+	Header     string `mapstructure:"header"`      // header 解析使用的请求头
+	BaseDomain string `mapstructure:"base_domain"` // subdomain 解析的主域名，如 example.com
+	PathParam  string `mapstructure:"path_param"`  // path 解析使用的路由参数名
+	Required   bool   `mapstructure:"required"`    // 无法解析租户时是否返回 404
+	// 静态租户列表，租户数据保存在数据库中时可改用自定义 tenant.Store
+	Tenants []TenantEntry `mapstructure:"tenants"`
+}
+
+// GeoIPConfig IP 地区解析配置
+type GeoIPConfig struct {
+	Database  string   `mapstructure:"database"`   // MaxMind mmdb 文件路径（GeoLite2-City/Country），为空时不启用
+	CacheSize int      `mapstructure:"cache_size"` // 查询结果 LRU 缓存的 IP 数量
+	Allow     []string `mapstructure:"allow"`      // 仅允许的国家代码，为空时不限制
+	Deny      []string `mapstructure:"deny"`       // 拒绝的国家代码
+}
+
+// SEOConfig robots.txt 与站点地图配置
+type SEOConfig struct {
+	Enabled            bool     `mapstructure:"enabled"`              // 注册 /robots.txt 与 /sitemap.xml 路由
+	BaseURL            string   `mapstructure:"base_url"`             // 站点地址，如 https://example.com，为空时取请求的协议与主机
+	Allow              []string `mapstructure:"allow"`                // robots.txt 的 Allow 路径
+	Disallow           []string `mapstructure:"disallow"`             // robots.txt 的 Disallow 路径
+	BlockNonProduction bool     `mapstructure:"block_non_production"` // 非生产环境 robots.txt 禁止抓取全站
+	SitemapTTL         int      `mapstructure:"sitemap_ttl"`          // 站点地图缓存时间（秒），0 表示每次请求重新构建
+}
+
+// ImageConfig 图片处理配置
+type ImageConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`     // 注册图片处理路由
+	Route       string `mapstructure:"route"`       // 路由前缀，如 /img
+	Source      string `mapstructure:"source"`      // 源图片目录（上传目录），设置 disk 时忽略
+	Disk        string `mapstructure:"disk"`        // 从 storage.disks 中的存储读取源图片，为空时使用 source 目录
+	Secret      string `mapstructure:"secret"`      // URL 签名密钥，启用时必填
+	Cache       string `mapstructure:"cache"`       // 处理结果缓存：disk、redis、memory，为空时不缓存
+	CacheDir    string `mapstructure:"cache_dir"`   // disk 缓存目录
+	CacheTTL    int    `mapstructure:"cache_ttl"`   // 缓存有效期（秒），0 表示永不过期
+	Concurrency int    `mapstructure:"concurrency"` // 同时处理的图片数，0 表示 CPU 核数
+	MaxAge      int    `mapstructure:"max_age"`     // 响应的 Cache-Control max-age（秒）
+}
+
+// StorageConfig 文件存储配置
+type StorageConfig struct {
+	Default string                `mapstructure:"default" desc:"默认存储名称"`
+	Disks   map[string]DiskConfig `mapstructure:"disks" desc:"存储名称 → 配置"`
+}
+
+// LockConfig 锁配置
+type LockConfig struct {
+	Driver string `mapstructure:"driver"` // memory（单实例）、redis（多实例部署，使用 redis 配置）
+	Prefix string `mapstructure:"prefix"` // redis 键前缀
+	TTL    int    `mapstructure:"ttl"`    // 锁的有效期（秒），持有期间自动续期，进程崩溃后最多 ttl 秒释放
+}
+
+// ConcurrentConfig 全局工作池配置
+type ConcurrentConfig struct {
+	Workers   int `mapstructure:"workers"`    // 工作协程数，0 表示 CPU 核数
+	QueueSize int `mapstructure:"queue_size"` // 等待队列长度，队列已满时 concurrent.Go 返回 ErrQueueFull
+}
+
+// DiskConfig 单个存储的配置
+type DiskConfig struct {
+	// 驱动：local、s3（AWS S3、MinIO 等兼容服务）、oss（阿里云 OSS），或通过 storage.RegisterDriver 注册的驱动
+	Driver string `mapstructure:"driver"`
+	Root   string `mapstructure:"root"` // local：根目录；s3/oss：对象键前缀
+	// 公开访问地址前缀，如 /uploads 或 CDN 地址；local 存储以 / 开头时由路由提供静态访问
+	URL       string `mapstructure:"url"`
+	Bucket    string `mapstructure:"bucket"`
+	Region    string `mapstructure:"region"`
+	Endpoint  string `mapstructure:"endpoint"` // 如 https://oss-cn-hangzhou.aliyuncs.com，为空时按 region 使用 AWS 地址
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"` // 使用 endpoint/bucket/key 形式的地址（MinIO 通常需要开启）
+	PartSize  int64  `mapstructure:"part_size"`  // 分片上传的分片大小（字节），不足 5 MB 时按 5 MB
+}
+
+// TenantEntry 租户定义
+type TenantEntry struct {
+	ID     string `mapstructure:"id"`
+	Name   string `mapstructure:"name"`
+	Schema string `mapstructure:"schema"` // 共享连接下使用的库/schema，表名自动加前缀
+	// 独立数据库连接，设置后优先于 schema
+	Database *DatabaseConfig `mapstructure:"database"`
 }
 
 const defaultCfg = "config/config.yaml"
@@ -156,19 +578,63 @@ func load(path string) (*Config, error) {
 		return nil, fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
-	// 4. 解析到结构体
+	// 4. 解析到结构体（含模块注册的配置段）
 	config := &Config{}
 	if err := v.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
+	if err := loadSections(v); err != nil {
+		return nil, err
+	}
+
+	// 5. 确定运行环境，并补全依赖运行环境的默认值
+	if err := resolveEnv(config); err != nil {
+		return nil, err
+	}
 
 	return config, nil
 }
 
+// resolveEnv 规范化 app.env（为空时按 server.mode 推断），并补全依赖运行环境的默认值：
+// 未配置 log.level 时开发与测试环境为 debug，其他环境为 info
+func resolveEnv(c *Config) error {
+	env := strings.ToLower(strings.TrimSpace(c.App.Env))
+	if alias, ok := envAliases[env]; ok {
+		env = alias
+	}
+	switch env {
+	case EnvDevelopment, EnvTesting, EnvStaging, EnvProduction:
+	case "":
+		switch c.Server.Mode {
+		case "debug":
+			env = EnvDevelopment
+		case "test":
+			env = EnvTesting
+		default:
+			env = EnvProduction
+		}
+	default:
+		return fmt.Errorf("无效的运行环境 app.env: %q（支持 development、testing、staging、production）", c.App.Env)
+	}
+	c.App.Env = env
+
+	if c.Log.Level == "" {
+		c.Log.Level = "info"
+		if env == EnvDevelopment || env == EnvTesting {
+			c.Log.Level = "debug"
+		}
+	}
+	return nil
+}
+
 // setDefaults 为所有配置项注册默认值。
 // 这些默认值同时承担两个作用：配置文件缺字段时的兜底，以及向 viper 注册 key 供 BindEnv 使用。
 func setDefaults(v *viper.Viper) {
 	// server
+	v.SetDefault("app.env", "")
+	v.SetDefault("app.name", "go-framework")
+	v.SetDefault("app.version", "")
+
 	v.SetDefault("server.port", 8080)
 	v.SetDefault("server.mode", "release")
 	v.SetDefault("server.read_timeout", 60)
@@ -177,11 +643,29 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.enable_rate_limit", false)
 	v.SetDefault("server.rate_limit", 100)
 	v.SetDefault("server.rate_burst", 200)
+	v.SetDefault("server.max_in_flight", 0)
+	v.SetDefault("server.queue_timeout", 3)
+	v.SetDefault("server.request_deadline", true)
+	v.SetDefault("server.minify_html", false)
+	v.SetDefault("server.compress", false)
+	v.SetDefault("server.compress_level", 6)
+	v.SetDefault("server.compress_except", []string{})
+	v.SetDefault("server.debug_toolbar", true)
+	v.SetDefault("server.content_negotiation", false)
+	v.SetDefault("server.json_engine", "")
+	v.SetDefault("server.max_body_size", 10<<20)
+	v.SetDefault("server.banner", true)
+	v.SetDefault("server.banner_color", true)
+	v.SetDefault("server.editor", "vscode")
+	v.SetDefault("server.debug_token", "")
 	// 默认仅信任本机回环代理（同机反向代理场景），外部直连无法伪造转发头
 	v.SetDefault("server.trusted_proxies", []string{"127.0.0.1", "::1"})
+	v.SetDefault("server.method_override", true)
+	v.SetDefault("server.csrf", false)
+	v.SetDefault("server.csrf_except", []string{"/api/"})
 
 	// log
-	v.SetDefault("log.level", "info")
+	v.SetDefault("log.level", "") // 为空时按运行环境确定，见 resolveEnv
 	v.SetDefault("log.filename", "logs/app.log")
 	v.SetDefault("log.max_size", 100)
 	v.SetDefault("log.max_backups", 10)
@@ -189,6 +673,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("log.compress", true)
 	v.SetDefault("log.format", "json")
 	v.SetDefault("log.stdout", false)
+	v.SetDefault("log.fields", map[string]string{})
 
 	// database
 	v.SetDefault("database.driver", "mysql")
@@ -200,6 +685,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_idle_conns", 10)
 	v.SetDefault("database.max_open_conns", 100)
 	v.SetDefault("database.conn_max_lifetime", 3600)
+	v.SetDefault("database.model_events", false)
+	v.SetDefault("database.batch_size", 500)
 
 	// redis
 	v.SetDefault("redis.host", "localhost")
@@ -207,6 +694,16 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("redis.password", "")
 	v.SetDefault("redis.db", 0)
 	v.SetDefault("redis.pool_size", 10)
+	v.SetDefault("redis.mode", "standalone")
+	v.SetDefault("redis.addrs", []string{})
+	v.SetDefault("redis.master_name", "")
+	v.SetDefault("redis.sentinel_password", "")
+	v.SetDefault("redis.username", "")
+	v.SetDefault("redis.min_idle_conns", 0)
+	v.SetDefault("redis.dial_timeout", 5)
+	v.SetDefault("redis.read_timeout", 3)
+	v.SetDefault("redis.write_timeout", 3)
+	v.SetDefault("redis.tls", false)
 
 	// jwt
 	v.SetDefault("jwt.secret", "")
@@ -216,12 +713,27 @@ func setDefaults(v *viper.Viper) {
 	// template
 	v.SetDefault("template.path", "templates")
 	v.SetDefault("template.layout_dir", "layouts")
+	v.SetDefault("template.partial_dir", "partials")
 	v.SetDefault("template.extension", "html")
 	v.SetDefault("template.default_layout", "main")
+	v.SetDefault("template.roots", []string{})
+	v.SetDefault("template.theme_dir", "themes")
+	v.SetDefault("template.theme", "")
+	v.SetDefault("template.theme_fallbacks", []string{})
+	v.SetDefault("template.asset_prefix", "/static")
+	v.SetDefault("template.cache_max_entries", 256)
+	v.SetDefault("template.cache_max_bytes", 32<<20)
+	v.SetDefault("template.strict", true)
 
 	// static
 	v.SetDefault("static.path", "./static/dist")
 
+	// vite
+	v.SetDefault("vite.dev_server", "")
+	v.SetDefault("vite.proxy", false)
+	v.SetDefault("vite.manifest", "./static/dist/.vite/manifest.json")
+	v.SetDefault("vite.base", "/static/")
+
 	// session
 	v.SetDefault("session.store", "cookie")
 	v.SetDefault("session.name", "go_session")
@@ -232,6 +744,194 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("session.path", "/")
 	v.SetDefault("session.domain", "")
 	v.SetDefault("session.same_site", "lax")
+	v.SetDefault("session.index", false)
+	v.SetDefault("session.fallback", false)
+	v.SetDefault("session.memcached.addrs", []string{"127.0.0.1:11211"})
+	v.SetDefault("session.memcached.prefix", "session_")
+	v.SetDefault("session.mongo.uri", "mongodb://127.0.0.1:27017")
+	v.SetDefault("session.mongo.database", "app")
+	v.SetDefault("session.mongo.collection", "sessions")
+
+	// crypto
+	v.SetDefault("crypto.key", "")
+	v.SetDefault("crypto.previous_keys", []string{})
+
+	// hash
+	v.SetDefault("hash.driver", "bcrypt")
+	v.SetDefault("hash.bcrypt_cost", 10)
+	v.SetDefault("hash.argon2_memory", 65536)
+	v.SetDefault("hash.argon2_time", 3)
+	v.SetDefault("hash.argon2_threads", 2)
+
+	// settings
+	v.SetDefault("settings.enabled", false)
+	v.SetDefault("settings.cache_ttl", 600)
+	v.SetDefault("search.enabled", false)
+	v.SetDefault("search.driver", "memory")
+	v.SetDefault("search.url", "")
+	v.SetDefault("search.api_key", "")
+	v.SetDefault("search.username", "")
+	v.SetDefault("search.password", "")
+	v.SetDefault("search.prefix", "")
+	v.SetDefault("search.timeout", 10)
+	v.SetDefault("search.columns", map[string]any{})
+	v.SetDefault("search.boolean_mode", false)
+	v.SetDefault("search.sync", false)
+	v.SetDefault("search.queue", true)
+
+	// pdf
+	v.SetDefault("pdf.enabled", false)
+	v.SetDefault("pdf.driver", "chrome")
+	v.SetDefault("pdf.binary", "")
+	v.SetDefault("pdf.url", "")
+	v.SetDefault("pdf.page_size", "A4")
+	v.SetDefault("pdf.landscape", false)
+	v.SetDefault("pdf.margin", 10)
+	v.SetDefault("pdf.layout", "")
+	v.SetDefault("pdf.header", "")
+	v.SetDefault("pdf.footer", "")
+	v.SetDefault("pdf.timeout", 60)
+
+	// captcha
+	v.SetDefault("captcha.enabled", false)
+	v.SetDefault("captcha.route", "/captcha")
+	v.SetDefault("captcha.store", "memory")
+	v.SetDefault("captcha.length", 4)
+	v.SetDefault("captcha.chars", "")
+	v.SetDefault("captcha.width", 120)
+	v.SetDefault("captcha.height", 40)
+	v.SetDefault("captcha.ttl", 300)
+	v.SetDefault("captcha.tolerance", 5)
+	v.SetDefault("captcha.rate_limit", 5)
+	v.SetDefault("captcha.threshold", 3)
+	v.SetDefault("captcha.window", 900)
+
+	// sms
+	v.SetDefault("sms.enabled", false)
+	v.SetDefault("sms.driver", "log")
+	v.SetDefault("sms.store", "memory")
+	v.SetDefault("sms.aliyun.access_key_id", "")
+	v.SetDefault("sms.aliyun.access_key_secret", "")
+	v.SetDefault("sms.aliyun.sign_name", "")
+	v.SetDefault("sms.aliyun.region", "cn-hangzhou")
+	v.SetDefault("sms.aliyun.endpoint", "")
+	v.SetDefault("sms.twilio.account_sid", "")
+	v.SetDefault("sms.twilio.auth_token", "")
+	v.SetDefault("sms.twilio.from", "")
+	v.SetDefault("sms.templates", map[string]any{})
+	v.SetDefault("sms.code.template", "verify")
+	v.SetDefault("sms.code.length", 6)
+	v.SetDefault("sms.code.ttl", 300)
+	v.SetDefault("sms.code.cooldown", 60)
+	v.SetDefault("sms.code.max_attempts", 5)
+	v.SetDefault("sms.code.daily_limit", 10)
+
+	// webhook
+	v.SetDefault("webhook.enabled", false)
+	v.SetDefault("webhook.events", []string{})
+	v.SetDefault("webhook.max_attempts", 6)
+	v.SetDefault("webhook.timeout", 10)
+	v.SetDefault("webhook.interval", 1)
+	v.SetDefault("webhook.batch_size", 100)
+	v.SetDefault("webhook.backoff", 30)
+	v.SetDefault("webhook.max_backoff", 3600)
+
+	// outbox
+	v.SetDefault("outbox.enabled", false)
+	v.SetDefault("outbox.max_attempts", 5)
+	v.SetDefault("outbox.interval", 1)
+	v.SetDefault("outbox.batch_size", 100)
+
+	// broadcast
+	v.SetDefault("broadcast.enabled", false)
+	v.SetDefault("broadcast.path", "/broadcast")
+	v.SetDefault("broadcast.backplane", "memory")
+	v.SetDefault("broadcast.redis_channel", "broadcast")
+	v.SetDefault("broadcast.allowed_origins", []string{})
+	v.SetDefault("broadcast.ping_timeout", 60)
+	v.SetDefault("broadcast.send_buffer", 64)
+
+	// notify
+	v.SetDefault("notify.enabled", false)
+	v.SetDefault("notify.path", "/notifications/stream")
+	v.SetDefault("notify.backplane", "memory")
+	v.SetDefault("notify.redis_channel", "notify")
+	v.SetDefault("notify.heartbeat", 25)
+	v.SetDefault("notify.pending_limit", 20)
+	v.SetDefault("notify.pending_ttl", 300)
+
+	// resilience
+	v.SetDefault("resilience.enabled", false)
+	v.SetDefault("resilience.dependencies", []string{"db"})
+	v.SetDefault("resilience.interval", 10)
+	v.SetDefault("resilience.timeout", 2)
+	v.SetDefault("resilience.threshold", 2)
+	v.SetDefault("resilience.disable_features", map[string][]string{})
+
+	v.SetDefault("error_rate.enabled", false)
+	v.SetDefault("error_rate.window", 60)
+	v.SetDefault("error_rate.threshold", 0.05)
+	v.SetDefault("error_rate.min_requests", 20)
+	v.SetDefault("error_rate.webhook_url", "")
+
+	v.SetDefault("record.enabled", false)
+	v.SetDefault("record.dir", "storage/records")
+	v.SetDefault("record.paths", []string{})
+	v.SetDefault("record.min_status", 0)
+	v.SetDefault("record.redact_headers", []string{"Authorization", "Cookie", "Proxy-Authorization"})
+
+	// tenant
+	v.SetDefault("tenant.enabled", false)
+	v.SetDefault("tenant.resolver", "subdomain")
+	v.SetDefault("tenant.header", "X-Tenant-ID")
+	v.SetDefault("tenant.base_domain", "")
+	v.SetDefault("tenant.path_param", "tenant")
+	v.SetDefault("tenant.required", true)
+	v.SetDefault("tenant.tenants", []map[string]any{})
+
+	// geoip
+	v.SetDefault("geoip.database", "")
+	v.SetDefault("geoip.cache_size", 10000)
+	v.SetDefault("geoip.allow", []string{})
+	v.SetDefault("geoip.deny", []string{})
+
+	// seo
+	v.SetDefault("seo.enabled", false)
+	v.SetDefault("seo.base_url", "")
+	v.SetDefault("seo.allow", []string{})
+	v.SetDefault("seo.disallow", []string{})
+	v.SetDefault("seo.block_non_production", true)
+	v.SetDefault("seo.sitemap_ttl", 3600)
+
+	// image
+	v.SetDefault("image.enabled", false)
+	v.SetDefault("image.route", "/img")
+	v.SetDefault("image.source", "storage/uploads")
+	v.SetDefault("image.disk", "")
+	v.SetDefault("image.secret", "")
+	v.SetDefault("image.cache", "disk")
+	v.SetDefault("image.cache_dir", "storage/cache/images")
+	v.SetDefault("image.cache_ttl", 0)
+	v.SetDefault("image.concurrency", 0)
+	v.SetDefault("image.max_age", 2592000)
+
+	// storage
+	v.SetDefault("storage.default", "local")
+	v.SetDefault("storage.disks", map[string]any{
+		"local": map[string]any{"driver": "local", "root": "storage/uploads", "url": "/uploads"},
+	})
+
+	// lock
+	v.SetDefault("lock.driver", "memory")
+	v.SetDefault("lock.prefix", "lock:")
+	v.SetDefault("lock.ttl", 30)
+
+	// concurrent
+	v.SetDefault("concurrent.workers", 0)
+	v.SetDefault("concurrent.queue_size", 1024)
+
+	// 模块注册的配置段（见 Register）
+	setSectionDefaults(v)
 }
 
 func MustFetch() *Config {
@@ -245,3 +945,33 @@ func MustFetch() *Config {
 func (c *Config) IsDebug() bool {
 	return c.Server.Mode == "debug"
 }
+
+// Env 返回运行环境，未经 load 解析（如测试中手工构造）时按 server.mode 推断
+func (c *Config) Env() string {
+	if c.App.Env != "" {
+		return c.App.Env
+	}
+	tmp := Config{Server: c.Server}
+	_ = resolveEnv(&tmp)
+	return tmp.App.Env
+}
+
+// IsDevelopment 是否为开发环境：模板不缓存、渲染详细错误页
+func (c *Config) IsDevelopment() bool {
+	return c.Env() == EnvDevelopment
+}
+
+// IsTesting 是否为测试环境
+func (c *Config) IsTesting() bool {
+	return c.Env() == EnvTesting
+}
+
+// IsStaging 是否为预发布环境
+func (c *Config) IsStaging() bool {
+	return c.Env() == EnvStaging
+}
+
+// IsProduction 是否为生产环境
+func (c *Config) IsProduction() bool {
+	return c.Env() == EnvProduction
+}
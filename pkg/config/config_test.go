@@ -102,3 +102,51 @@ func TestLoadMissingFile(t *testing.T) {
 		t.Fatal("配置文件不存在时应返回错误")
 	}
 }
+
+// TestLoadEnv app.env 未设置时按 server.mode 推断，简写被规范化，日志级别默认值随运行环境变化
+func TestLoadEnv(t *testing.T) {
+	cases := []struct {
+		content, env, level string
+	}{
+		{"server:\n  mode: debug\n", EnvDevelopment, "debug"},
+		{"server:\n  mode: test\n", EnvTesting, "debug"},
+		{"server:\n  mode: release\n", EnvProduction, "info"},
+		{"app:\n  env: stage\nserver:\n  mode: debug\n", EnvStaging, "info"},
+		{"app:\n  env: prod\nlog:\n  level: warn\n", EnvProduction, "warn"},
+	}
+	for _, tc := range cases {
+		cfg, err := load(writeTempConfig(t, tc.content))
+		if err != nil {
+			t.Fatalf("load 失败: %v", err)
+		}
+		if cfg.Env() != tc.env || cfg.Log.Level != tc.level {
+			t.Errorf("%q: 期望 %s/%s，得到 %s/%s", tc.content, tc.env, tc.level, cfg.Env(), cfg.Log.Level)
+		}
+	}
+
+	t.Setenv("APP_ENV", "testing")
+	cfg, err := load(writeTempConfig(t, "server:\n  mode: release\n"))
+	if err != nil {
+		t.Fatalf("load 失败: %v", err)
+	}
+	if !cfg.IsTesting() || cfg.IsProduction() {
+		t.Errorf("APP_ENV 应覆盖推断值，得到 %s", cfg.Env())
+	}
+
+	t.Setenv("APP_ENV", "qa")
+	if _, err := load(writeTempConfig(t, "")); err == nil {
+		t.Error("无效的运行环境应返回错误")
+	}
+}
+
+// TestEnvHelpers 手工构造的配置按 server.mode 推断运行环境
+func TestEnvHelpers(t *testing.T) {
+	cfg := &Config{Server: ServerConfig{Mode: "debug"}}
+	if !cfg.IsDevelopment() || cfg.IsProduction() || cfg.IsStaging() {
+		t.Errorf("debug 模式应为开发环境，得到 %s", cfg.Env())
+	}
+	cfg.App.Env = EnvStaging
+	if !cfg.IsStaging() || cfg.IsDevelopment() {
+		t.Errorf("显式设置的运行环境优先，得到 %s", cfg.Env())
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Entry 配置项说明，用于生成部署文档中的配置项与环境变量对照表
+type Entry struct {
+	Key         string // 配置键，如 server.port
+	Env         string // 覆盖该项的环境变量，如 SERVER_PORT；map 与结构体列表只能在配置文件中设置，为空
+	Type        string // 值类型，如 int、string、[]string（环境变量中以逗号分隔）
+	Default     any    // setDefaults 注册的默认值，未注册时为 nil
+	Description string // 字段的 desc 标签，未设置时取 config.yaml 中该项的行尾注释
+	Sensitive   bool   // 是否为敏感项（见 IsSensitive），应通过环境变量或密钥管理注入
+}
+
+// Reference 反射 Config 结构与已注册的配置段（见 Register），按字段顺序返回全部配置项的说明，描述取自字段的 desc 标签
+// 与默认配置文件（config/config.yaml）中的注释，配置文件不存在时仅使用 desc 标签
+func Reference() ([]Entry, error) {
+	doc, err := os.ReadFile(defaultCfg)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return reference(doc)
+}
+
+// reference 以 doc（config.yaml 内容，可为空）中的注释生成配置项说明
+func reference(doc []byte) ([]Entry, error) {
+	comments := make(map[string]string)
+	if len(doc) > 0 {
+		var root yaml.Node
+		if err := yaml.Unmarshal(doc, &root); err != nil {
+			return nil, err
+		}
+		collectComments(&root, "", comments)
+	}
+
+	v := viper.New()
+	setDefaults(v)
+
+	var entries []Entry
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			key := prefix + name
+			if f.Type.Kind() == reflect.Struct {
+				walk(f.Type, key+".")
+				continue
+			}
+
+			e := Entry{
+				Key:         key,
+				Env:         strings.ToUpper(strings.ReplaceAll(key, ".", "_")),
+				Type:        strings.ReplaceAll(f.Type.String(), "config.", ""),
+				Default:     v.Get(key),
+				Description: f.Tag.Get("desc"),
+				Sensitive:   IsSensitive(name),
+			}
+			// map 与结构体列表无法由单个环境变量表示
+			if f.Type.Kind() == reflect.Map || (f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct) {
+				e.Env = ""
+			}
+			if e.Description == "" {
+				e.Description = comments[key]
+			}
+			entries = append(entries, e)
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	for _, name := range Sections() {
+		walk(section(name).defaults.Type(), name+".")
+	}
+	return entries, nil
+}
+
+// collectComments 收集 YAML 映射中各键的行尾注释（如 "port: 8080 # 端口"），键为以 "." 连接的完整路径
+func collectComments(n *yaml.Node, prefix string, comments map[string]string) {
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		collectComments(n.Content[0], prefix, comments)
+		return
+	}
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, val := n.Content[i], n.Content[i+1]
+		comment := val.LineComment
+		if comment == "" {
+			comment = k.LineComment
+		}
+		if comment = strings.TrimSpace(strings.TrimPrefix(comment, "#")); comment != "" {
+			comments[prefix+k.Value] = comment
+		}
+		collectComments(val, prefix+k.Value+".", comments)
+	}
+}
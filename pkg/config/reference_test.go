@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// TestReference 配置项的环境变量、类型与默认值，描述优先取 desc 标签，其次取 config.yaml 的行尾注释
+func TestReference(t *testing.T) {
+	entries, err := reference([]byte("server:\n  port: 8081 # 被 desc 标签覆盖\n  mode: debug # debug, release\n" +
+		"search:\n  columns: {} # 表名 → 列\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byKey := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		byKey[e.Key] = e
+	}
+	if len(entries) == 0 || entries[0].Key != "app.env" {
+		t.Fatalf("应按字段顺序输出，以 app.env 开头")
+	}
+
+	port := byKey["server.port"]
+	if port.Env != "SERVER_PORT" || port.Type != "int" || port.Default != 8080 || port.Description != "监听端口" {
+		t.Errorf("server.port = %+v", port)
+	}
+	if mode := byKey["server.mode"]; mode.Description != "debug, release" || mode.Default != "release" {
+		t.Errorf("server.mode = %+v", mode)
+	}
+	if e := byKey["session.memcached.addrs"]; e.Env != "SESSION_MEMCACHED_ADDRS" || e.Type != "[]string" {
+		t.Errorf("嵌套结构应展开，得到 %+v", e)
+	}
+	if e := byKey["search.columns"]; e.Env != "" || e.Type != "map[string][]string" || e.Description != "表名 → 列" {
+		t.Errorf("map 配置项不能由环境变量设置，得到 %+v", e)
+	}
+	if e := byKey["tenant.tenants"]; e.Env != "" || e.Type != "[]TenantEntry" {
+		t.Errorf("结构体列表不能由环境变量设置，得到 %+v", e)
+	}
+	if !byKey["jwt.secret"].Sensitive || byKey["jwt.issuer"].Sensitive {
+		t.Error("敏感项标记错误")
+	}
+}
+
+// TestReferenceDocumented 每个配置项都注册了默认值并有描述（desc 标签或 config.yaml 注释）
+func TestReferenceDocumented(t *testing.T) {
+	doc, err := os.ReadFile("../../config/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := reference(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Default == nil {
+			t.Errorf("%s 未在 setDefaults 中注册默认值", e.Key)
+		}
+		if e.Description == "" {
+			t.Errorf("%s 缺少描述：在 config.yaml 中添加行尾注释或为字段添加 desc 标签", e.Key)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Validator 配置段可实现的校验接口，加载配置时在解析后调用，返回错误时加载失败
+type Validator interface {
+	Validate() error
+}
+
+// registered 已注册的配置段
+type registered struct {
+	ptr      any           // 模块持有的结构体指针，加载时解析到其中
+	defaults reflect.Value // 注册时的初始值副本，作为默认值
+}
+
+var (
+	sections   = map[string]registered{}
+	sectionsMu sync.RWMutex
+)
+
+// Register 注册模块自己的配置段，无需修改 Config 结构。section 为结构体指针，其初始值即该段的默认值，
+// 加载配置时从同一配置文件的 name 段（及 NAME_KEY 形式的环境变量）解析到 section 中，
+// 实现 Validator 时随后校验。应在 init 中、配置加载之前调用：
+//
+//	type PaymentConfig struct {
+//	    Gateway string `mapstructure:"gateway"` // 支付网关
+//	    Timeout int    `mapstructure:"timeout"` // 请求超时（秒）
+//	}
+//
+//	var paymentConfig = &PaymentConfig{Gateway: "alipay", Timeout: 10}
+//
+//	func init() { config.Register("payment", paymentConfig) }
+//
+// name 与内置配置段或已注册的配置段重名、section 不是结构体指针时 panic
+func Register(name string, section any) {
+	if t := reflect.TypeOf(section); t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("config: 配置段 %s 必须是结构体指针，得到 %T", name, section))
+	}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ","); tag == name {
+			panic("config: 配置段与内置配置重名: " + name)
+		}
+	}
+	sectionsMu.Lock()
+	defer sectionsMu.Unlock()
+	if _, ok := sections[name]; ok {
+		panic("config: 配置段重复注册: " + name)
+	}
+	defaults := reflect.New(reflect.TypeOf(section).Elem()).Elem()
+	defaults.Set(reflect.ValueOf(section).Elem())
+	sections[name] = registered{ptr: section, defaults: defaults}
+}
+
+// Sections 返回已注册的配置段名称（已排序）
+func Sections() []string {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// section 返回已注册的配置段
+func section(name string) registered {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+	return sections[name]
+}
+
+// setSectionDefaults 以各配置段的初始值注册默认值（同时向 viper 注册 key 供 BindEnv 使用）
+func setSectionDefaults(v *viper.Viper) {
+	for _, name := range Sections() {
+		setStructDefaults(v, name+".", section(name).defaults)
+	}
+}
+
+// setStructDefaults 按 mapstructure 名称递归注册结构体各字段的当前值
+func setStructDefaults(v *viper.Viper, prefix string, rv reflect.Value) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if f.Type.Kind() == reflect.Struct {
+			setStructDefaults(v, prefix+name+".", rv.Field(i))
+			continue
+		}
+		v.SetDefault(prefix+name, rv.Field(i).Interface())
+	}
+}
+
+// loadSections 将配置解析到各配置段并校验
+func loadSections(v *viper.Viper) error {
+	for _, name := range Sections() {
+		r := section(name)
+		s := r.ptr
+		reflect.ValueOf(s).Elem().Set(r.defaults) // 重新加载时不保留上一次的值
+		// UnmarshalKey 不应用嵌套 key 的环境变量覆盖，改为以只含该段的包装结构整体 Unmarshal
+		wrapper := reflect.New(reflect.StructOf([]reflect.StructField{{
+			Name: "Section",
+			Type: reflect.TypeOf(s),
+			Tag:  reflect.StructTag(`mapstructure:"` + name + `"`),
+		}}))
+		wrapper.Elem().Field(0).Set(reflect.ValueOf(s))
+		if err := v.Unmarshal(wrapper.Interface()); err != nil {
+			return fmt.Errorf("解析配置段 %s 失败: %w", name, err)
+		}
+		if val, ok := s.(Validator); ok {
+			if err := val.Validate(); err != nil {
+				return fmt.Errorf("配置段 %s 校验失败: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type paymentConfig struct {
+	Gateway string `mapstructure:"gateway" desc:"支付网关"`
+	Timeout int    `mapstructure:"timeout" desc:"请求超时（秒）"`
+	Secret  string `mapstructure:"secret" desc:"签名密钥"`
+	Notify  struct {
+		URL string `mapstructure:"url" desc:"回调地址"`
+	} `mapstructure:"notify"`
+}
+
+func (p *paymentConfig) Validate() error {
+	if p.Timeout <= 0 {
+		return errors.New("timeout 必须大于 0")
+	}
+	return nil
+}
+
+var testPayment = &paymentConfig{Gateway: "alipay", Timeout: 10}
+
+func init() {
+	Register("payment", testPayment)
+}
+
+// TestRegister 注册的配置段从同一配置文件与环境变量解析，未配置的字段保留初始值，并参与校验、Dump 与 Reference
+func TestRegister(t *testing.T) {
+	t.Setenv("PAYMENT_TIMEOUT", "30")
+	path := writeTempConfig(t, "payment:\n  secret: s3cr3t\n  notify:\n    url: https://example.com/notify\n")
+	cfg, err := load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := paymentConfig{Gateway: "alipay", Timeout: 30, Secret: "s3cr3t"}
+	want.Notify.URL = "https://example.com/notify"
+	if *testPayment != want {
+		t.Errorf("payment = %+v, want %+v", *testPayment, want)
+	}
+	if m, _ := Dump(cfg)["payment"].(map[string]any); m["secret"] != Mask || m["gateway"] != "alipay" {
+		t.Errorf("Dump payment = %v", m)
+	}
+
+	entries, err := reference(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Key, "payment.") {
+			keys = append(keys, e.Key+"="+e.Env)
+		}
+	}
+	if got := strings.Join(keys, " "); got != "payment.gateway=PAYMENT_GATEWAY payment.timeout=PAYMENT_TIMEOUT payment.secret=PAYMENT_SECRET payment.notify.url=PAYMENT_NOTIFY_URL" {
+		t.Errorf("Reference = %s", got)
+	}
+
+	t.Setenv("PAYMENT_TIMEOUT", "0")
+	if _, err := load(path); err == nil || !strings.Contains(err.Error(), "payment") {
+		t.Errorf("期望校验失败，得到 %v", err)
+	}
+}
+
+// TestRegisterConflict 与内置配置段或已注册的配置段重名时 panic
+func TestRegisterConflict(t *testing.T) {
+	for _, name := range []string{"server", "payment"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("重名配置段 %s 应 panic", name)
+				}
+			}()
+			Register(name, &paymentConfig{})
+		}()
+	}
+}
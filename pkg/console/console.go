@@ -0,0 +1,84 @@
+// Package console 提供命令行子命令的注册与分发，如 `bin/app outbox:replay`
+// 未携带子命令时由 cmd/main.go 正常启动 HTTP 服务
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Command 子命令
+type Command struct {
+	Name        string                    // 命令名，约定使用 "模块:动作" 形式
+	Usage       string                    // 参数说明，如 "[id...]"
+	Description string                    // 一句话描述
+	Run         func(args []string) error // 执行函数，args 不含命令名本身
+}
+
+var (
+	commands = make(map[string]Command)
+	mu       sync.RWMutex
+
+	// Output 命令输出目标，测试中可替换
+	Output io.Writer = os.Stdout
+)
+
+// Register 注册子命令，重名时后注册的覆盖先注册的
+func Register(cmds ...Command) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, cmd := range cmds {
+		commands[cmd.Name] = cmd
+	}
+}
+
+// Lookup 按名称查找子命令
+func Lookup(name string) (Command, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	cmd, ok := commands[name]
+	return cmd, ok
+}
+
+// Commands 返回按名称排序的全部子命令
+func Commands() []Command {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Command, 0, len(commands))
+	for _, cmd := range commands {
+		list = append(list, cmd)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Run 分发执行子命令，args[0] 为命令名；"help" / "list" 打印命令列表
+func Run(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "list" {
+		PrintUsage()
+		return nil
+	}
+
+	cmd, ok := Lookup(args[0])
+	if !ok {
+		PrintUsage()
+		return fmt.Errorf("未知命令: %s", args[0])
+	}
+	return cmd.Run(args[1:])
+}
+
+// PrintUsage 打印可用命令列表
+func PrintUsage() {
+	fmt.Fprintln(Output, "用法: app [命令] [参数]（不带命令时启动 HTTP 服务）")
+	fmt.Fprintln(Output, "\n可用命令:")
+	for _, cmd := range Commands() {
+		name := cmd.Name
+		if cmd.Usage != "" {
+			name += " " + cmd.Usage
+		}
+		fmt.Fprintf(Output, "  %-28s %s\n", name, cmd.Description)
+	}
+}
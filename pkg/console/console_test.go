@@ -0,0 +1,40 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var buf bytes.Buffer
+	Output = &buf
+
+	var got []string
+	Register(Command{
+		Name:        "test:echo",
+		Description: "echo args",
+		Run: func(args []string) error {
+			got = args
+			return nil
+		},
+	})
+
+	if err := Run([]string{"test:echo", "a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "a,b" {
+		t.Errorf("args = %v", got)
+	}
+
+	if err := Run(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "test:echo") {
+		t.Errorf("usage missing command: %q", buf.String())
+	}
+
+	if err := Run([]string{"missing"}); err == nil {
+		t.Error("expected error for unknown command")
+	}
+}
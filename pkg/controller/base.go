@@ -0,0 +1,90 @@
+// Package controller 提供控制器基类 Base，汇集控制器常用的配置、日志、当前用户、校验与响应辅助方法，
+// 业务控制器嵌入后即可通过 FX 自动注入公共依赖，无需各自导入多个包：
+//
+//	type PostController struct {
+//	    controller.Base
+//	    DB *gorm.DB
+//	}
+//
+//	func (p *PostController) Show(c *gin.Context) error {
+//	    id, _ := router.Param[int](c, "id")
+//	    p.Logger(c).Infow("查看文章", "id", id)
+//	    return p.View(c, "post/show", gin.H{"ID": id})
+//	}
+package controller
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/response"
+	"github.com/gorilla-go/go-framework/pkg/template"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Base 控制器基类。本身是 fx.In 参数对象，嵌入到控制器后 Config 等公共依赖由 FX 自动注入
+type Base struct {
+	fx.In
+
+	Config *config.Config
+}
+
+// Logger 返回带当前请求字段（请求 ID、方法、路径、客户端 IP 与作用域字段）的日志记录器，见 logger.WithScope；
+// 日志未初始化时返回空记录器
+func (b *Base) Logger(c *gin.Context) *zap.SugaredLogger {
+	return logger.WithScope(c)
+}
+
+// Context 返回传给服务层与仓储的请求上下文，随请求取消，见 request.Context
+func (b *Base) Context(c *gin.Context) context.Context {
+	return request.Context(c)
+}
+
+// User 获取当前登录用户，未登录时返回 nil；需要具体类型时使用 auth.UserAs
+func (b *Base) User(c *gin.Context) any {
+	return auth.User(c)
+}
+
+// Authenticated 判断当前请求是否已登录
+func (b *Base) Authenticated(c *gin.Context) bool {
+	return auth.Check(c)
+}
+
+// Bind 绑定请求数据并校验，失败时返回校验错误（可直接 return 给路由层）
+func (b *Base) Bind(c *gin.Context, v any) error {
+	return request.Bind(c, v)
+}
+
+// Validate 使用全局校验器校验数据，失败时返回校验错误
+func (b *Base) Validate(v any) error {
+	if err := validator.Validate(v); err != nil {
+		return errors.NewValidationError(err.Error(), err)
+	}
+	return nil
+}
+
+// View 按当前请求主题、使用默认布局渲染模板，并注入 CurrentUser 等请求级数据。
+// 模板错误由模板引擎直接渲染为错误页，因此总是返回 nil，便于在处理器中 return
+func (b *Base) View(c *gin.Context, name string, data any) error {
+	template.RenderThemeL(c, name, data)
+	return nil
+}
+
+// JSON 返回统一格式的成功响应
+func (b *Base) JSON(c *gin.Context, data any) error {
+	response.Success(c, data)
+	return nil
+}
+
+// Redirect 重定向到指定 URL，可选传入状态码（默认 302）
+func (b *Base) Redirect(c *gin.Context, url string, status ...int) error {
+	response.Redirect(c, url, status...)
+	return nil
+}
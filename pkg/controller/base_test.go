@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	apperrors "github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+	"go.uber.org/fx"
+)
+
+type postController struct {
+	Base
+	Name string `name:"controller_name"`
+}
+
+// TestBaseInjectedByFX 嵌入 Base 的控制器通过 fx.Populate 自动注入公共依赖
+func TestBaseInjectedByFX(t *testing.T) {
+	cfg := &config.Config{}
+	var ctl postController
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(cfg),
+		fx.Supply(fx.Annotate("posts", fx.ResultTags(`name:"controller_name"`))),
+		fx.Populate(&ctl),
+	)
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.Config != cfg {
+		t.Error("Config 未注入")
+	}
+	if ctl.Name != "posts" {
+		t.Errorf("控制器自有字段未注入: %q", ctl.Name)
+	}
+}
+
+// TestBaseHelpers JSON、User、Logger 辅助方法
+func TestBaseHelpers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var b Base
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := b.JSON(c, gin.H{"id": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":1`) {
+		t.Errorf("JSON 响应 %d %s", w.Code, w.Body.String())
+	}
+
+	if b.Authenticated(c) || b.User(c) != nil {
+		t.Error("未登录时不应返回用户")
+	}
+	c.Set(request.KeyCurrentUser, "alice")
+	if !b.Authenticated(c) || b.User(c) != "alice" {
+		t.Error("应返回当前用户")
+	}
+
+	// 日志未初始化时返回空记录器，不应 panic
+	b.Logger(c).Infow("test")
+}
+
+type rejectValidator struct{}
+
+func (rejectValidator) Validate(any) error { return errors.New("invalid") }
+
+// TestBaseValidate 校验失败返回 AppError
+func TestBaseValidate(t *testing.T) {
+	var b Base
+	validator.Register(rejectValidator{})
+	defer validator.Register(nil)
+
+	err := b.Validate(struct{}{})
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.ValidationError {
+		t.Errorf("期望校验错误，得到 %v", err)
+	}
+}
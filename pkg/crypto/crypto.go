@@ -0,0 +1,193 @@
+// Package crypto 提供基于 AES-GCM 的数据加解密，支持密钥轮换。
+//
+// 密文格式为 "<密钥ID>:<base64(nonce|密文)>"，密钥 ID 由密钥摘要生成，
+// 轮换时将旧密钥放入 previous_keys 即可继续解密历史数据，新数据始终使用当前密钥加密。
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+var (
+	// ErrNoKey 未配置加密密钥
+	ErrNoKey = errors.New("加密密钥未配置（crypto.key）")
+	// ErrInvalidCiphertext 密文格式错误或被篡改
+	ErrInvalidCiphertext = errors.New("密文无效")
+	// ErrUnknownKey 密文使用的密钥不在当前密钥环中
+	ErrUnknownKey = errors.New("密文使用的密钥未配置")
+)
+
+// Cipher AES-GCM 加解密器，持有当前密钥与历史密钥
+type Cipher struct {
+	primary string                 // 当前密钥 ID
+	keys    map[string]cipher.AEAD // 密钥 ID → AEAD
+}
+
+// New 创建加解密器，key 为当前密钥，previous 为轮换前的旧密钥；密钥长度须为 16/24/32 字节
+func New(key []byte, previous ...[]byte) (*Cipher, error) {
+	c := &Cipher{keys: make(map[string]cipher.AEAD)}
+	for i, k := range append([][]byte{key}, previous...) {
+		aead, err := newAEAD(k)
+		if err != nil {
+			return nil, err
+		}
+		id := keyID(k)
+		if i == 0 {
+			c.primary = id
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+// newAEAD 由密钥创建 AES-GCM
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("无效的加密密钥: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyID 密钥标识：SHA-256 摘要前 4 字节的十六进制
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// Encrypt 使用当前密钥加密
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	aead := c.keys[c.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return c.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密，按密文中的密钥 ID 选择对应密钥
+func (c *Cipher) Decrypt(ciphertext string) ([]byte, error) {
+	id, data, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return nil, ErrInvalidCiphertext
+	}
+	aead, ok := c.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// EncryptString 加密字符串
+func (c *Cipher) EncryptString(s string) (string, error) {
+	return c.Encrypt([]byte(s))
+}
+
+// DecryptString 解密为字符串
+func (c *Cipher) DecryptString(s string) (string, error) {
+	b, err := c.Decrypt(s)
+	return string(b), err
+}
+
+// NeedsReencrypt 判断密文是否由旧密钥加密，用于轮换后逐步迁移历史数据
+func (c *Cipher) NeedsReencrypt(ciphertext string) bool {
+	id, _, _ := strings.Cut(ciphertext, ":")
+	return id != c.primary
+}
+
+// ParseKey 解析配置中的密钥：支持 "base64:" 前缀的 Base64 编码，否则按原始字节使用
+func ParseKey(s string) ([]byte, error) {
+	if encoded, ok := strings.CutPrefix(s, "base64:"); ok {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("解析 base64 密钥失败: %w", err)
+		}
+		return key, nil
+	}
+	return []byte(s), nil
+}
+
+// ---- 全局加解密器 ----
+
+var (
+	defaultCipher *Cipher
+	defaultMu     sync.RWMutex
+)
+
+// Init 根据配置初始化全局加解密器
+func Init(cfg *config.CryptoConfig) error {
+	if cfg.Key == "" {
+		return ErrNoKey
+	}
+	key, err := ParseKey(cfg.Key)
+	if err != nil {
+		return err
+	}
+	previous := make([][]byte, 0, len(cfg.PreviousKeys))
+	for _, s := range cfg.PreviousKeys {
+		k, err := ParseKey(s)
+		if err != nil {
+			return err
+		}
+		previous = append(previous, k)
+	}
+	c, err := New(key, previous...)
+	if err != nil {
+		return err
+	}
+	SetDefault(c)
+	return nil
+}
+
+// SetDefault 设置全局加解密器
+func SetDefault(c *Cipher) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultCipher = c
+}
+
+// Default 获取全局加解密器，未初始化时返回 nil
+func Default() *Cipher {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultCipher
+}
+
+// Encrypt 使用全局加解密器加密字符串
+func Encrypt(s string) (string, error) {
+	c := Default()
+	if c == nil {
+		return "", ErrNoKey
+	}
+	return c.EncryptString(s)
+}
+
+// Decrypt 使用全局加解密器解密字符串
+func Decrypt(s string) (string, error) {
+	c := Default()
+	if c == nil {
+		return "", ErrNoKey
+	}
+	return c.DecryptString(s)
+}
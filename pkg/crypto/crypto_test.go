@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	oldKey = bytes.Repeat([]byte("o"), 32)
+	newKey = bytes.Repeat([]byte("n"), 32)
+)
+
+func TestCipherRoundTripAndRotation(t *testing.T) {
+	oldCipher, err := New(oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, _ := oldCipher.EncryptString("13800138000")
+
+	c, err := New(newKey, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.DecryptString(legacy)
+	if err != nil || got != "13800138000" {
+		t.Fatalf("decrypt legacy = %q, %v", got, err)
+	}
+	if !c.NeedsReencrypt(legacy) {
+		t.Error("legacy ciphertext should need re-encryption")
+	}
+
+	fresh, _ := c.EncryptString("13800138000")
+	if c.NeedsReencrypt(fresh) {
+		t.Error("fresh ciphertext should not need re-encryption")
+	}
+	if _, err := oldCipher.Decrypt(fresh); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("old cipher decrypt fresh: err = %v, want ErrUnknownKey", err)
+	}
+
+	tampered := fresh[:len(fresh)-2] + "AA"
+	if _, err := c.Decrypt(tampered); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("tampered: err = %v, want ErrInvalidCiphertext", err)
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	if _, err := New([]byte("short")); err == nil {
+		t.Error("expected error for invalid key length")
+	}
+	if err := Init(&config.CryptoConfig{}); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Init without key: err = %v", err)
+	}
+}
+
+type secretUser struct {
+	ID    uint
+	Phone EncryptedString
+}
+
+func TestEncryptedStringField(t *testing.T) {
+	if err := Init(&config.CryptoConfig{Key: "base64:bmJubmJubmJubmJubmJubmJubmJubmJubmJubmJubm4="}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetDefault(nil) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&secretUser{})
+	db.Create(&secretUser{Phone: "13800138000"})
+
+	var raw string
+	db.Raw("SELECT phone FROM secret_users").Scan(&raw)
+	if raw == "" || raw == "13800138000" {
+		t.Fatalf("column should be encrypted at rest, got %q", raw)
+	}
+
+	var u secretUser
+	db.First(&u)
+	if u.Phone != "13800138000" {
+		t.Errorf("Phone = %q", u.Phone)
+	}
+}
@@ -0,0 +1,56 @@
+package crypto
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// EncryptedString 落库时自动加密、读取时自动解密的字符串字段，适用于手机号、身份证号等敏感信息。
+// 使用全局加解密器（crypto.key），列类型应足够容纳密文（约为明文长度的 4/3 + 40 字节）。
+//
+//	type User struct {
+//	    ID    uint
+//	    Phone crypto.EncryptedString `gorm:"type:varchar(255)"`
+//	}
+//
+// 注意：密文随机化，无法按明文对该列做等值查询或建立唯一索引。
+type EncryptedString string
+
+// String 返回明文
+func (s EncryptedString) String() string {
+	return string(s)
+}
+
+// Value 实现 driver.Valuer，写入时加密
+func (s EncryptedString) Value() (driver.Value, error) {
+	if s == "" {
+		return "", nil
+	}
+	return Encrypt(string(s))
+}
+
+// Scan 实现 sql.Scanner，读取时解密
+func (s *EncryptedString) Scan(value any) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("EncryptedString 不支持的数据库类型: %T", value)
+	}
+	if raw == "" {
+		*s = ""
+		return nil
+	}
+	plain, err := Decrypt(raw)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plain)
+	return nil
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultBatchSize 未配置 database.batch_size（gorm.Config.CreateBatchSize）时每批写入的行数
+const DefaultBatchSize = 500
+
+// BulkProgress 批量操作进度回调，每批执行成功后调用，done 为已处理行数，total 为总行数
+type BulkProgress func(done, total int)
+
+// bulkOptions 批量操作配置
+type bulkOptions struct {
+	batchSize int
+	progress  BulkProgress
+	conflict  []string // 冲突判断列，为空时使用主键
+	update    []string // 冲突时更新的列，为空时更新除主键与创建时间外的全部列
+	ignore    bool     // 冲突时忽略该行
+	tx        bool
+}
+
+// BulkOption 批量操作选项
+type BulkOption func(*bulkOptions)
+
+// WithBulkBatchSize 每批的行数，默认取 database.batch_size 配置，未配置时为 DefaultBatchSize。
+// MySQL 单条语句的占位符上限为 65535，列较多的表应适当调小
+func WithBulkBatchSize(n int) BulkOption {
+	return func(o *bulkOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithBulkProgress 每批执行成功后回调进度，用于导入任务展示进度或记录日志
+func WithBulkProgress(fn BulkProgress) BulkOption {
+	return func(o *bulkOptions) {
+		o.progress = fn
+	}
+}
+
+// WithBulkConflict BulkUpsert 判断冲突的列（唯一索引），默认为主键；
+// MySQL 的 ON DUPLICATE KEY UPDATE 按表上全部唯一索引判断，忽略该设置
+func WithBulkConflict(columns ...string) BulkOption {
+	return func(o *bulkOptions) {
+		o.conflict = columns
+	}
+}
+
+// WithBulkUpdate BulkUpsert 冲突时更新的列，默认更新除主键与创建时间外的全部列
+func WithBulkUpdate(columns ...string) BulkOption {
+	return func(o *bulkOptions) {
+		o.update = columns
+	}
+}
+
+// WithBulkIgnore BulkUpsert 冲突时保留已有行、忽略新行（MySQL 为 ON DUPLICATE KEY UPDATE 主键不变，
+// SQLite / PostgreSQL 为 ON CONFLICT DO NOTHING）
+func WithBulkIgnore() BulkOption {
+	return func(o *bulkOptions) {
+		o.ignore = true
+	}
+}
+
+// WithBulkTransaction 在同一事务中执行全部批次，任一批失败时整体回滚；默认每批独立提交，
+// 失败时已成功的批次保留（可根据进度回调的 done 断点续导）
+func WithBulkTransaction() BulkOption {
+	return func(o *bulkOptions) {
+		o.tx = true
+	}
+}
+
+// BulkInsert 分批插入 rows，返回插入的行数。逐行 db.Create 每行一次往返，导入大量数据时应使用本函数：
+//
+//	n, err := database.BulkInsert(db.WithContext(ctx), users,
+//	    database.WithBulkBatchSize(1000),
+//	    database.WithBulkProgress(func(done, total int) { log.Printf("%d/%d", done, total) }))
+//
+// rows 的元素为模型值或指针，插入后自增主键回填到元素中（元素为值时回填到 rows 切片本身）
+func BulkInsert[T any](db *gorm.DB, rows []T, opts ...BulkOption) (int64, error) {
+	return bulkCreate(db, rows, nil, opts)
+}
+
+// BulkUpsert 分批插入 rows，与已有行冲突时更新（或按 WithBulkIgnore 忽略），返回受影响的行数。
+// 冲突语句由 GORM 按驱动生成：MySQL 为 ON DUPLICATE KEY UPDATE，SQLite / PostgreSQL 为 ON CONFLICT ... DO UPDATE：
+//
+//	database.BulkUpsert(db, products, database.WithBulkConflict("sku"), database.WithBulkUpdate("price", "stock"))
+//
+// 注意 MySQL 对更新的行计为 2 行受影响，返回值不等于新插入的行数
+func BulkUpsert[T any](db *gorm.DB, rows []T, opts ...BulkOption) (int64, error) {
+	return bulkCreate(db, rows, func(o *bulkOptions) clause.OnConflict {
+		conflict := make([]clause.Column, 0, len(o.conflict))
+		for _, col := range o.conflict {
+			conflict = append(conflict, clause.Column{Name: col})
+		}
+		switch {
+		case o.ignore:
+			return clause.OnConflict{Columns: conflict, DoNothing: true}
+		case len(o.update) > 0:
+			return clause.OnConflict{Columns: conflict, DoUpdates: clause.AssignmentColumns(o.update)}
+		default:
+			return clause.OnConflict{Columns: conflict, UpdateAll: true}
+		}
+	}, opts)
+}
+
+// BulkDelete 按主键分批删除模型 T 的记录，返回删除的行数；模型含 gorm.DeletedAt 时为软删除：
+//
+//	database.BulkDelete[model.User](db, ids)
+func BulkDelete[T any, K comparable](db *gorm.DB, ids []K, opts ...BulkOption) (int64, error) {
+	o := newBulkOptions(db, opts)
+	var affected int64
+	err := o.run(db, len(ids), func(tx *gorm.DB, start, end int) error {
+		res := tx.Delete(new(T), ids[start:end])
+		affected += res.RowsAffected
+		return res.Error
+	})
+	return affected, err
+}
+
+// bulkCreate 分批执行 INSERT，onConflict 不为 nil 时附加冲突处理子句
+func bulkCreate[T any](db *gorm.DB, rows []T, onConflict func(*bulkOptions) clause.OnConflict, opts []BulkOption) (int64, error) {
+	o := newBulkOptions(db, opts)
+	var affected int64
+	err := o.run(db, len(rows), func(tx *gorm.DB, start, end int) error {
+		if onConflict != nil {
+			tx = tx.Clauses(onConflict(o))
+		}
+		res := tx.Create(rows[start:end])
+		affected += res.RowsAffected
+		return res.Error
+	})
+	return affected, err
+}
+
+// newBulkOptions 应用选项，批大小默认取连接的 CreateBatchSize（database.batch_size）
+func newBulkOptions(db *gorm.DB, opts []BulkOption) *bulkOptions {
+	o := &bulkOptions{batchSize: DefaultBatchSize}
+	if db.Config != nil && db.CreateBatchSize > 0 {
+		o.batchSize = db.CreateBatchSize
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// run 将 [0, total) 按批大小切分后逐批执行 fn，按选项包裹事务并回调进度
+func (o *bulkOptions) run(db *gorm.DB, total int, fn func(tx *gorm.DB, start, end int) error) error {
+	if total == 0 {
+		return nil
+	}
+	exec := func(tx *gorm.DB) error {
+		for start := 0; start < total; start += o.batchSize {
+			end := min(start+o.batchSize, total)
+			// 每批使用新会话（配置为副本），避免上一批的子句累积；关闭 GORM 自身的分批，由这里统一切分
+			batch := tx.Session(&gorm.Session{})
+			batch.CreateBatchSize = 0
+			if err := fn(batch, start, end); err != nil {
+				return err
+			}
+			if o.progress != nil {
+				o.progress(end, total)
+			}
+		}
+		return nil
+	}
+	if o.tx {
+		return db.Transaction(exec)
+	}
+	return exec(db)
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type bulkProduct struct {
+	ID    uint
+	SKU   string `gorm:"uniqueIndex"`
+	Name  string
+	Price int
+}
+
+func newBulkDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&bulkProduct{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// TestBulkInsert 按批大小切分并回调进度，主键回填
+func TestBulkInsert(t *testing.T) {
+	db := newBulkDB(t)
+	rows := make([]*bulkProduct, 7)
+	for i := range rows {
+		rows[i] = &bulkProduct{SKU: string(rune('a' + i)), Price: i}
+	}
+
+	var progress []int
+	n, err := BulkInsert(db, rows, WithBulkBatchSize(3), WithBulkProgress(func(done, total int) {
+		if total != 7 {
+			t.Errorf("total = %d", total)
+		}
+		progress = append(progress, done)
+	}))
+	if err != nil || n != 7 {
+		t.Fatalf("BulkInsert = %d, %v", n, err)
+	}
+	if len(progress) != 3 || progress[0] != 3 || progress[2] != 7 {
+		t.Errorf("进度回调 %v，期望 [3 6 7]", progress)
+	}
+	if rows[6].ID == 0 {
+		t.Error("主键应回填")
+	}
+}
+
+// TestBulkUpsert 按唯一列冲突时更新指定列，WithBulkIgnore 保留已有行
+func TestBulkUpsert(t *testing.T) {
+	db := newBulkDB(t)
+	db.Create(&bulkProduct{SKU: "a", Name: "旧", Price: 1})
+
+	rows := []bulkProduct{{SKU: "a", Name: "新", Price: 2}, {SKU: "b", Name: "乙", Price: 3}}
+	if _, err := BulkUpsert(db, rows, WithBulkConflict("sku"), WithBulkUpdate("price")); err != nil {
+		t.Fatal(err)
+	}
+	var a bulkProduct
+	db.Where("sku = ?", "a").First(&a)
+	if a.Price != 2 || a.Name != "旧" {
+		t.Errorf("冲突行应只更新 price: %+v", a)
+	}
+
+	rows = []bulkProduct{{SKU: "b", Name: "丙", Price: 9}}
+	if _, err := BulkUpsert(db, rows, WithBulkConflict("sku"), WithBulkIgnore()); err != nil {
+		t.Fatal(err)
+	}
+	var b bulkProduct
+	db.Where("sku = ?", "b").First(&b)
+	if b.Price != 3 {
+		t.Errorf("WithBulkIgnore 应保留已有行: %+v", b)
+	}
+
+	var count int64
+	db.Model(&bulkProduct{}).Count(&count)
+	if count != 2 {
+		t.Errorf("count = %d", count)
+	}
+}
+
+// TestBulkDelete 按主键分批删除；事务模式下失败整体回滚
+func TestBulkDelete(t *testing.T) {
+	db := newBulkDB(t)
+	rows := []*bulkProduct{{SKU: "a"}, {SKU: "b"}, {SKU: "c"}}
+	db.Create(rows)
+
+	n, err := BulkDelete[bulkProduct](db, []uint{rows[0].ID, rows[1].ID, 999}, WithBulkBatchSize(2))
+	if err != nil || n != 2 {
+		t.Fatalf("BulkDelete = %d, %v", n, err)
+	}
+
+	// 第二批违反唯一约束，事务模式下第一批同样回滚
+	dup := []bulkProduct{{SKU: "x"}, {SKU: "c"}}
+	if _, err := BulkInsert(db, dup, WithBulkBatchSize(1), WithBulkTransaction()); err == nil {
+		t.Fatal("应返回唯一约束错误")
+	}
+	if err := db.Where("sku = ?", "x").First(&bulkProduct{}).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("事务应回滚第一批: %v", err)
+	}
+}
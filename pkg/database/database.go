@@ -27,6 +27,11 @@ func Init(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	return dbInstance, dbError
 }
 
+// Open 按配置创建一个新的数据库连接（不影响全局单例），供多租户独立库等场景使用
+func Open(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	return initDB(cfg)
+}
+
 // initDB 内部初始化函数
 func initDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	// 根据配置的 driver 选择数据库方言
@@ -40,6 +45,8 @@ func initDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: true, // 使用单数表名
 		},
+		Logger:          NewGormLogger(), // SQL 日志写入应用日志并附带请求 ID
+		CreateBatchSize: cfg.BatchSize,   // 插入切片时分批，批量辅助函数（BulkInsert 等）的默认批大小
 	})
 	if err != nil {
 		return nil, fmt.Errorf("连接数据库失败: %w", err)
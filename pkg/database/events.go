@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// 模型生命周期事件名
+const (
+	EventModelCreated = "model.created"
+	EventModelUpdated = "model.updated"
+	EventModelDeleted = "model.deleted"
+)
+
+// changesKey 在 before/after 回调之间传递变更字段的实例键
+const changesKey = "model_events:changes"
+
+// ModelEvent 模型生命周期事件载荷，作为事件的唯一参数传递
+//
+//	eventbus.On(database.EventModelUpdated, func(args ...interface{}) {
+//	    e := args[0].(*database.ModelEvent)
+//	    if e.Table == "user" {
+//	        cache.Delete(e.Changes["email"])
+//	    }
+//	})
+type ModelEvent struct {
+	Action  string         // created / updated / deleted
+	Table   string         // 表名
+	Model   any            // 模型实例（即传给 GORM 的 Dest）
+	Changes map[string]any // 更新时变更的字段（列名 → 新值），创建/删除时为 nil
+}
+
+// modelEventsConfig 模型事件插件配置
+type modelEventsConfig struct {
+	only   map[reflect.Type]bool
+	except map[reflect.Type]bool
+}
+
+// ModelEventsOption 模型事件插件配置选项
+type ModelEventsOption func(*modelEventsConfig)
+
+// WithModelEventsOnly 仅为指定模型触发事件（默认全部模型）
+func WithModelEventsOnly(models ...any) ModelEventsOption {
+	return func(c *modelEventsConfig) {
+		for _, m := range models {
+			c.only[modelType(m)] = true
+		}
+	}
+}
+
+// WithModelEventsExcept 不为指定模型触发事件，如审计日志表自身
+func WithModelEventsExcept(models ...any) ModelEventsOption {
+	return func(c *modelEventsConfig) {
+		for _, m := range models {
+			c.except[modelType(m)] = true
+		}
+	}
+}
+
+// ModelEvents 将 GORM 模型的创建/更新/删除桥接到事件总线的插件。
+// 事件在写操作成功后、事务提交前同步触发，需要事务提交后才执行的副作用请配合 outbox 使用。
+//
+//	db.Use(database.NewModelEvents(eventbus.Default(), database.WithModelEventsExcept(&AuditLog{})))
+type ModelEvents struct {
+	bus *eventbus.EventBus
+	cfg modelEventsConfig
+}
+
+// NewModelEvents 创建模型事件插件，bus 为 nil 时使用全局事件总线
+func NewModelEvents(bus *eventbus.EventBus, opts ...ModelEventsOption) *ModelEvents {
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	cfg := modelEventsConfig{only: map[reflect.Type]bool{}, except: map[reflect.Type]bool{}}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return &ModelEvents{bus: bus, cfg: cfg}
+}
+
+// Name 实现 gorm.Plugin
+func (p *ModelEvents) Name() string {
+	return "model_events"
+}
+
+// Initialize 实现 gorm.Plugin，注册回调
+func (p *ModelEvents) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("model_events:created", p.emit(EventModelCreated, "created")); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("model_events:changes", p.collectChanges); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("model_events:updated", p.emit(EventModelUpdated, "updated")); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("model_events:deleted", p.emit(EventModelDeleted, "deleted"))
+}
+
+// enabled 判断当前语句的模型是否需要触发事件
+func (p *ModelEvents) enabled(db *gorm.DB) bool {
+	if db.Statement.Schema == nil {
+		return false
+	}
+	t := db.Statement.Schema.ModelType
+	if p.cfg.except[t] {
+		return false
+	}
+	return len(p.cfg.only) == 0 || p.cfg.only[t]
+}
+
+// collectChanges 在更新前记录变更字段（此时模型中仍为旧值，便于比较）
+func (p *ModelEvents) collectChanges(db *gorm.DB) {
+	if db.Error != nil || !p.enabled(db) {
+		return
+	}
+	stmt := db.Statement
+	changes := make(map[string]any)
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.PrimaryKey || !stmt.Changed(field.Name) {
+			continue
+		}
+		changes[field.DBName] = newValue(stmt.Context, stmt.Dest, field)
+	}
+	db.InstanceSet(changesKey, changes)
+}
+
+// emit 生成写操作成功后触发事件的回调
+func (p *ModelEvents) emit(event, action string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.RowsAffected == 0 || !p.enabled(db) {
+			return
+		}
+		e := &ModelEvent{Action: action, Table: db.Statement.Table, Model: db.Statement.Dest}
+		if action == "updated" {
+			if changes, ok := db.InstanceGet(changesKey); ok {
+				e.Changes = changes.(map[string]any)
+			}
+			// 以 map 更新时 Dest 不是模型实例，改用 Model
+			if _, isMap := e.Model.(map[string]any); isMap {
+				e.Model = db.Statement.Model
+			}
+		}
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		// 处理器错误由事件总线的 ErrorHandler 记录，不影响数据库操作结果
+		_ = p.bus.EmitCtx(ctx, event, e)
+	}
+}
+
+// newValue 从更新目标中读取字段的新值
+func newValue(ctx context.Context, dest any, field *schema.Field) any {
+	if m, ok := dest.(map[string]any); ok {
+		if v, ok := m[field.Name]; ok {
+			return v
+		}
+		return m[field.DBName]
+	}
+	rv := reflect.ValueOf(dest)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	v, _ := field.ValueOf(ctx, rv)
+	return v
+}
+
+// modelType 取模型的结构体类型
+func modelType(model any) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
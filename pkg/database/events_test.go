@@ -0,0 +1,83 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type eventUser struct {
+	ID    uint
+	Name  string
+	Email string
+}
+
+type eventAudit struct {
+	ID     uint
+	Action string
+}
+
+func newEventsDB(t *testing.T, bus *eventbus.EventBus, opts ...ModelEventsOption) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&eventUser{}, &eventAudit{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(NewModelEvents(bus, opts...)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestModelEvents(t *testing.T) {
+	bus := eventbus.New()
+	var got []*ModelEvent
+	bus.On("model.*", func(args ...interface{}) {
+		got = append(got, args[1].(*ModelEvent))
+	})
+
+	db := newEventsDB(t, bus, WithModelEventsExcept(&eventAudit{}))
+
+	u := &eventUser{Name: "alice", Email: "a@example.com"}
+	db.Create(u)
+	db.Create(&eventAudit{Action: "ignored"})
+	db.Model(u).Updates(map[string]any{"email": "new@example.com", "name": "alice"})
+	db.Delete(u)
+
+	if len(got) != 3 {
+		t.Fatalf("got %d events, want 3", len(got))
+	}
+	if got[0].Action != "created" || got[0].Model != u {
+		t.Errorf("created event = %+v", got[0])
+	}
+	updated := got[1]
+	if updated.Action != "updated" || updated.Model != u {
+		t.Errorf("updated event = %+v", updated)
+	}
+	if len(updated.Changes) != 1 || updated.Changes["email"] != "new@example.com" {
+		t.Errorf("changes = %v, want only email", updated.Changes)
+	}
+	if got[2].Action != "deleted" || got[2].Table != "event_users" {
+		t.Errorf("deleted event = %+v", got[2])
+	}
+}
+
+func TestModelEventsOnly(t *testing.T) {
+	bus := eventbus.New()
+	count := 0
+	bus.On(EventModelCreated, func(args ...interface{}) { count++ })
+
+	db := newEventsDB(t, bus, WithModelEventsOnly(&eventAudit{}))
+	db.Create(&eventUser{Name: "bob"})
+	db.Create(&eventAudit{Action: "login"})
+
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// slowQueryThreshold 慢查询阈值，与 GORM 默认日志器一致
+const slowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger 将 GORM 日志写入应用日志（pkg/logger），并附带上下文中的请求 ID。
+// 以 db.WithContext(c.Request.Context()) 或 db.WithContext(c) 执行的 SQL 日志带有 request_id 字段，
+// 可与请求日志关联。应用日志未初始化时回退到 GORM 默认日志器
+type GormLogger struct {
+	level gormlogger.LogLevel
+	slow  time.Duration
+}
+
+// NewGormLogger 创建 GORM 日志器，默认仅记录错误与慢查询（Warn 级别）
+func NewGormLogger() *GormLogger {
+	return &GormLogger{level: gormlogger.Warn, slow: slowQueryThreshold}
+}
+
+// LogMode 实现 gormlogger.Interface
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	c := *l
+	c.level = level
+	return &c
+}
+
+// Info 实现 gormlogger.Interface
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...any) {
+	if l.level >= gormlogger.Info {
+		l.log(ctx, zap.InfoLevel, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn 实现 gormlogger.Interface
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	if l.level >= gormlogger.Warn {
+		l.log(ctx, zap.WarnLevel, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error 实现 gormlogger.Interface
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...any) {
+	if l.level >= gormlogger.Error {
+		l.log(ctx, zap.ErrorLevel, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace 实现 gormlogger.Interface：记录失败的 SQL（忽略 ErrRecordNotFound）、慢查询，Info 级别下记录全部 SQL
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	if logger.ZapLogger == nil {
+		gormlogger.Default.LogMode(l.level).Trace(ctx, begin, fc, err)
+		return
+	}
+
+	elapsed := time.Since(begin)
+	var (
+		level zapcore.Level
+		msg   string
+	)
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gormlogger.ErrRecordNotFound):
+		level, msg = zap.ErrorLevel, "SQL 执行失败"
+	case l.slow > 0 && elapsed > l.slow && l.level >= gormlogger.Warn:
+		level, msg = zap.WarnLevel, "慢查询"
+	case l.level >= gormlogger.Info:
+		level, msg = zap.InfoLevel, "SQL"
+	default:
+		return
+	}
+
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	l.write(ctx, level, msg, fields)
+}
+
+// log 写入非 SQL 的 GORM 日志
+func (l *GormLogger) log(ctx context.Context, level zapcore.Level, msg string) {
+	if logger.ZapLogger == nil {
+		fallback := gormlogger.Default.LogMode(l.level)
+		switch level {
+		case zap.InfoLevel:
+			fallback.Info(ctx, msg)
+		case zap.WarnLevel:
+			fallback.Warn(ctx, msg)
+		default:
+			fallback.Error(ctx, msg)
+		}
+		return
+	}
+	l.write(ctx, level, msg, nil)
+}
+
+// write 附加请求 ID 后写入应用日志
+func (l *GormLogger) write(ctx context.Context, level zapcore.Level, msg string, fields []zap.Field) {
+	if id := request.RequestIDFromContext(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	// caller 为 GORM 内部位置没有意义，改为记录发起查询的业务代码位置
+	fields = append(fields, zap.String("source", utils.FileWithLineNum()))
+	if ce := logger.ZapLogger.WithOptions(zap.WithCaller(false)).Check(level, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
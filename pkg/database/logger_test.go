@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	applogger "github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGormLoggerRequestID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	prev := applogger.ZapLogger
+	applogger.ZapLogger = zap.New(core)
+	defer func() { applogger.ZapLogger = prev }()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: NewGormLogger()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := request.WithRequestID(context.Background(), "req-1")
+	db.WithContext(ctx).Exec("SELECT * FROM missing_table")
+	entries := logs.FilterMessage("SQL 执行失败").All()
+	if len(entries) != 1 {
+		t.Fatalf("期望 1 条失败日志，得到 %d", logs.Len())
+	}
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "req-1" || fields["sql"] != "SELECT * FROM missing_table" {
+		t.Errorf("日志字段 %v", fields)
+	}
+
+	// 默认 Warn 级别不记录成功的 SQL，记录不存在不视为错误
+	logs.TakeAll()
+	db.WithContext(ctx).Exec("SELECT 1")
+	var row struct{ Name string }
+	err = db.WithContext(ctx).Table("sqlite_master").Where("name = ?", "none").First(&row).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("期望 ErrRecordNotFound，得到 %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("不应记录日志，得到 %v", logs.All())
+	}
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Paginate 按列表查询参数过滤、排序并查询一页数据到 dest，返回过滤后的总条数。
+// 搜索关键字以 LIKE 匹配 searchColumns 中任一列，列名均已由 ParseListQuery 的白名单校验：
+//
+//	q, err := request.ParseListQuery(c, request.WithListSortable("id", "name"), request.WithListFilterable("role"))
+//	var users []*model.User
+//	total, err := database.Paginate(db.WithContext(c).Model(&model.User{}), q, &users, "name", "email")
+//	page, err := UserResource.Paginate(c, users, resource.Page{Page: q.Page, PerPage: q.PerPage, Total: total})
+func Paginate(db *gorm.DB, q *request.ListQuery, dest any, searchColumns ...string) (int64, error) {
+	// 新会话：计数与查询各自复制语句，互不影响
+	db = db.Session(&gorm.Session{}).Scopes(ListScope(q, searchColumns...))
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, nil
+	}
+
+	tx := db.Offset(q.Offset()).Limit(q.PerPage)
+	if len(q.Fields) > 0 {
+		tx = tx.Select(q.Fields)
+	}
+	for _, s := range q.Sort {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Field}, Desc: s.Desc})
+	}
+	return total, tx.Find(dest).Error
+}
+
+// ListScope 返回应用搜索与过滤条件的作用域（不含排序与分页），供自定义查询复用
+func ListScope(q *request.ListQuery, searchColumns ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for k, v := range q.Filters {
+			db = db.Where(clause.Eq{Column: clause.Column{Name: k}, Value: v})
+		}
+		if q.Search != "" && len(searchColumns) > 0 {
+			like := make([]clause.Expression, 0, len(searchColumns))
+			for _, col := range searchColumns {
+				like = append(like, clause.Like{Column: clause.Column{Name: col}, Value: "%" + q.Search + "%"})
+			}
+			db = db.Where(clause.Or(like...))
+		}
+		return db
+	}
+}
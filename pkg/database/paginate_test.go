@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type pageUser struct {
+	ID    uint
+	Name  string
+	Email string
+	Role  string
+}
+
+// TestPaginate 过滤、搜索、排序、字段选择与分页
+func TestPaginate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&pageUser{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create([]*pageUser{
+		{Name: "alice", Email: "alice@example.com", Role: "admin"},
+		{Name: "bob", Email: "bob@example.com", Role: "user"},
+		{Name: "carol", Email: "carol@example.com", Role: "admin"},
+		{Name: "dave", Email: "dave@test.com", Role: "admin"},
+	})
+
+	q := &request.ListQuery{
+		Search:  "example",
+		Sort:    []request.SortField{{Field: "name", Desc: true}},
+		Fields:  []string{"id", "name"},
+		Filters: map[string]string{"role": "admin"},
+		Page:    2,
+		PerPage: 1,
+	}
+	var users []pageUser
+	total, err := Paginate(db.Model(&pageUser{}), q, &users, "name", "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(users) != 1 || users[0].Name != "alice" || users[0].Email != "" {
+		t.Errorf("total=%d users=%+v", total, users)
+	}
+
+	q = &request.ListQuery{Search: "nobody", Page: 1, PerPage: 10, Filters: map[string]string{}}
+	users = nil
+	if total, err := Paginate(db.Model(&pageUser{}), q, &users, "name"); err != nil || total != 0 || len(users) != 0 {
+		t.Errorf("无匹配: total=%d users=%v err=%v", total, users, err)
+	}
+}
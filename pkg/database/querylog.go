@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryLogStartKey 在 before/after 回调之间传递开始时间的实例键
+const queryLogStartKey = "query_log:start"
+
+// Query 一条已执行的 SQL
+type Query struct {
+	SQL      string        // 已代入参数的 SQL，仅用于展示
+	Duration time.Duration // 执行耗时
+	Rows     int64         // 影响/返回的行数
+	Error    string        // 执行错误，成功时为空
+}
+
+// QueryLog 请求级 SQL 记录，并发安全
+type QueryLog struct {
+	mu      sync.Mutex
+	queries []Query
+}
+
+// Queries 返回已记录 SQL 的副本
+func (l *QueryLog) Queries() []Query {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Query(nil), l.queries...)
+}
+
+// Total 返回 SQL 总耗时
+func (l *QueryLog) Total() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var total time.Duration
+	for _, q := range l.queries {
+		total += q.Duration
+	}
+	return total
+}
+
+func (l *QueryLog) add(q Query) {
+	l.mu.Lock()
+	l.queries = append(l.queries, q)
+	l.mu.Unlock()
+}
+
+type queryLogKey struct{}
+
+// QueryLogKey gin.Context 中存放 *QueryLog 的键名。
+// 未开启 ContextWithFallback 时 gin.Context 不会读取请求上下文中的值，
+// 以 c.Set(QueryLogKey, log) 存放后 db.WithContext(c) 同样能被记录
+const QueryLogKey = "query_log"
+
+// WithQueryLog 返回挂载了 SQL 记录的上下文。
+// 之后以 db.WithContext(ctx) 执行的查询都会被 QueryLogger 插件记录到返回的 QueryLog 中
+func WithQueryLog(ctx context.Context) (context.Context, *QueryLog) {
+	log := &QueryLog{}
+	return context.WithValue(ctx, queryLogKey{}, log), log
+}
+
+// QueryLogFromContext 获取上下文中的 SQL 记录，不存在时返回 nil
+func QueryLogFromContext(ctx context.Context) *QueryLog {
+	if ctx == nil {
+		return nil
+	}
+	if log, ok := ctx.Value(queryLogKey{}).(*QueryLog); ok {
+		return log
+	}
+	log, _ := ctx.Value(QueryLogKey).(*QueryLog)
+	return log
+}
+
+// QueryLogger 将 SQL 记录到上下文 QueryLog 的插件，上下文未挂载 QueryLog 时不做任何事。
+// 仅用于开发调试（调试工具栏、测试断言），会为每条 SQL 生成带参数的文本，生产环境不建议启用。
+//
+//	db.Use(database.NewQueryLogger())
+//	ctx, log := database.WithQueryLog(ctx)
+//	db.WithContext(ctx).Find(&users)
+//	log.Queries()
+type QueryLogger struct{}
+
+// NewQueryLogger 创建 SQL 记录插件
+func NewQueryLogger() *QueryLogger {
+	return &QueryLogger{}
+}
+
+// Name 实现 gorm.Plugin
+func (p *QueryLogger) Name() string {
+	return "query_log"
+}
+
+// Initialize 实现 gorm.Plugin，为所有操作注册前后回调
+func (p *QueryLogger) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	type registrar interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+	processors := []struct {
+		name          string
+		before, after registrar
+	}{
+		{"create", cb.Create().Before("*"), cb.Create().After("*")},
+		{"query", cb.Query().Before("*"), cb.Query().After("*")},
+		{"update", cb.Update().Before("*"), cb.Update().After("*")},
+		{"delete", cb.Delete().Before("*"), cb.Delete().After("*")},
+		{"row", cb.Row().Before("*"), cb.Row().After("*")},
+		{"raw", cb.Raw().Before("*"), cb.Raw().After("*")},
+	}
+	for _, proc := range processors {
+		if err := proc.before.Register("query_log:before_"+proc.name, p.before); err != nil {
+			return err
+		}
+		if err := proc.after.Register("query_log:after_"+proc.name, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *QueryLogger) before(db *gorm.DB) {
+	if QueryLogFromContext(db.Statement.Context) != nil {
+		db.InstanceSet(queryLogStartKey, time.Now())
+	}
+}
+
+func (p *QueryLogger) after(db *gorm.DB) {
+	log := QueryLogFromContext(db.Statement.Context)
+	if log == nil {
+		return
+	}
+	v, ok := db.InstanceGet(queryLogStartKey)
+	if !ok {
+		return
+	}
+	q := Query{
+		SQL:      db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...),
+		Duration: time.Since(v.(time.Time)),
+		Rows:     db.RowsAffected,
+	}
+	if db.Error != nil {
+		q.Error = db.Error.Error()
+	}
+	log.add(q)
+}
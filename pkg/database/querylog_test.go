@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestQueryLogger(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&eventUser{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(NewQueryLogger()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未挂载 QueryLog 的查询不记录
+	db.Create(&eventUser{Name: "bob"})
+
+	ctx, log := WithQueryLog(context.Background())
+	db.WithContext(ctx).Create(&eventUser{Name: "alice"})
+	var users []eventUser
+	db.WithContext(ctx).Where("name = ?", "alice").Find(&users)
+
+	queries := log.Queries()
+	if len(queries) != 2 {
+		t.Fatalf("got %d queries, want 2: %+v", len(queries), queries)
+	}
+	if !strings.HasPrefix(queries[0].SQL, "INSERT") {
+		t.Errorf("first query = %q", queries[0].SQL)
+	}
+	if !strings.Contains(queries[1].SQL, `"alice"`) || queries[1].Rows != 1 {
+		t.Errorf("second query = %+v", queries[1])
+	}
+	if QueryLogFromContext(context.Background()) != nil {
+		t.Error("expected nil log for bare context")
+	}
+}
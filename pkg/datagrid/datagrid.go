@@ -0,0 +1,284 @@
+// Package datagrid 根据列定义与分页查询结果生成数据表格的视图数据，配合 templates/partials/datagrid.html
+// 渲染可排序表头、过滤表单、每页条数选择与分页链接，省去后台列表页重复的模板代码。
+//
+//	var orderGrid = datagrid.New(
+//	    datagrid.Col("no", "order.no", func(o *model.Order) any { return o.No }),
+//	    datagrid.Column[*model.Order]{Key: "status", Label: "order.status", Value: func(o *model.Order) any { return o.Status },
+//	        Sortable: true, Filterable: true, Options: []datagrid.Option{{Value: "paid", Label: "order.paid"}}},
+//	    datagrid.Column[*model.Order]{Key: "created_at", Label: "order.created_at", Value: func(o *model.Order) any { return o.CreatedAt }, Sortable: true},
+//	).Searchable("order.search")
+//
+//	q, err := request.ParseListQuery(c, orderGrid.ListOptions(request.WithListDefaultSort("-id"))...)
+//	var orders []*model.Order
+//	total, err := database.Paginate(db.WithContext(c).Model(&model.Order{}), q, &orders, "no")
+//	return o.View(c, "admin/orders", gin.H{"Grid": orderGrid.View(c, q, orders, total)})
+//
+// 模板中 {{ include "partials/datagrid" .Grid }}；表头、选项与搜索提示作为 i18n key 按请求语言翻译，未注册时原样输出。
+package datagrid
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// DefaultPageSizes 默认的每页条数选项
+var DefaultPageSizes = []int{20, 50, 100}
+
+// pageWindow 分页链接中当前页两侧显示的页数
+const pageWindow = 2
+
+// Option 过滤下拉选项
+type Option struct {
+	Value string
+	Label string // 作为 i18n key 翻译
+}
+
+// Column 列定义
+type Column[T any] struct {
+	Key        string      // 列名，排序与过滤参数使用的字段（须为数据库列名）
+	Label      string      // 表头，作为 i18n key 翻译
+	Value      func(T) any // 单元格内容，返回 template.HTML 时不转义（如链接、按钮）
+	Sortable   bool        // 允许按该列排序
+	Filterable bool        // 允许按该列过滤
+	Options    []Option    // 过滤下拉选项，为空时为文本输入
+}
+
+// Col 创建列定义
+func Col[T any](key, label string, value func(T) any) Column[T] {
+	return Column[T]{Key: key, Label: label, Value: value}
+}
+
+// Grid 数据表格定义，通常声明为包级变量在多个请求间复用
+type Grid[T any] struct {
+	columns   []Column[T]
+	pageSizes []int
+	search    string
+	searching bool
+}
+
+// New 创建数据表格
+func New[T any](columns ...Column[T]) *Grid[T] {
+	return &Grid[T]{columns: columns, pageSizes: DefaultPageSizes}
+}
+
+// PageSizes 设置每页条数选项，第一项为默认值，最大项为上限
+func (g *Grid[T]) PageSizes(sizes ...int) *Grid[T] {
+	if len(sizes) > 0 {
+		g.pageSizes = sizes
+	}
+	return g
+}
+
+// Searchable 显示搜索框，placeholder 为输入框提示（i18n key）；搜索的列由 database.Paginate 的 searchColumns 指定
+func (g *Grid[T]) Searchable(placeholder string) *Grid[T] {
+	g.searching, g.search = true, placeholder
+	return g
+}
+
+// ListOptions 返回与列定义一致的 ParseListQuery 选项（排序、过滤白名单与每页条数），opts 追加在其后
+func (g *Grid[T]) ListOptions(opts ...request.ListOption) []request.ListOption {
+	var sortable, filterable []string
+	for _, col := range g.columns {
+		if col.Sortable {
+			sortable = append(sortable, col.Key)
+		}
+		if col.Filterable {
+			filterable = append(filterable, col.Key)
+		}
+	}
+	return append([]request.ListOption{
+		request.WithListSortable(sortable...),
+		request.WithListFilterable(filterable...),
+		request.WithListPerPage(g.pageSizes[0], slices.Max(g.pageSizes)),
+	}, opts...)
+}
+
+// Header 表头单元格
+type Header struct {
+	Key      string
+	Label    string
+	Sortable bool
+	Sort     string // 当前排序方向：asc、desc，未按该列排序时为空
+	URL      string // 点击切换排序的链接
+}
+
+// Filter 过滤表单项
+type Filter struct {
+	Name    string // 参数名，如 filter[status]
+	Label   string
+	Value   string
+	Options []SelectOption // 为空时为文本输入
+}
+
+// SelectOption 下拉选项
+type SelectOption struct {
+	Value    string
+	Label    string
+	Selected bool
+}
+
+// Search 搜索框
+type Search struct {
+	Value       string
+	Placeholder string
+}
+
+// Param 表单中保留的查询参数
+type Param struct {
+	Name  string
+	Value string
+}
+
+// PageSize 每页条数选项
+type PageSize struct {
+	Size   int
+	URL    string
+	Active bool
+}
+
+// Link 分页链接，Gap 为省略号占位
+type Link struct {
+	Page   int
+	URL    string
+	Active bool
+	Gap    bool
+}
+
+// View 数据表格的视图数据
+type View struct {
+	Action    string   // 当前路径，过滤表单的提交地址
+	Columns   []Header // 表头
+	Rows      [][]any  // 单元格内容
+	Search    *Search  // 未启用搜索时为 nil
+	Filters   []Filter // 过滤表单项
+	Hidden    []Param  // 过滤表单提交时保留的参数（排序、每页条数）
+	PageSizes []PageSize
+	Links     []Link
+	Prev      string // 上一页链接，无上一页时为空
+	Next      string // 下一页链接，无下一页时为空
+	Page      int
+	PerPage   int
+	Pages     int
+	Total     int64
+}
+
+// View 按当前请求与查询结果生成视图数据，链接保留请求中的其他查询参数；
+// 切换排序、过滤或每页条数时回到第一页
+func (g *Grid[T]) View(c *gin.Context, q *request.ListQuery, rows []T, total int64) View {
+	locale := request.Locale(c)
+	query := c.Request.URL.Query()
+	link := func(set map[string]string) string {
+		v := url.Values{}
+		for k, vs := range query {
+			v[k] = vs
+		}
+		for k, val := range set {
+			if val == "" {
+				v.Del(k)
+			} else {
+				v.Set(k, val)
+			}
+		}
+		if len(v) == 0 {
+			return c.Request.URL.Path
+		}
+		return c.Request.URL.Path + "?" + v.Encode()
+	}
+
+	pages := int((total + int64(q.PerPage) - 1) / int64(q.PerPage))
+	view := View{
+		Action:  c.Request.URL.Path,
+		Rows:    make([][]any, len(rows)),
+		Page:    q.Page,
+		PerPage: q.PerPage,
+		Pages:   pages,
+		Total:   total,
+	}
+
+	var sorted request.SortField
+	if len(q.Sort) > 0 {
+		sorted = q.Sort[0]
+	}
+	for _, col := range g.columns {
+		h := Header{Key: col.Key, Label: i18n.T(locale, col.Label), Sortable: col.Sortable}
+		if col.Sortable {
+			next := col.Key
+			if sorted.Field == col.Key {
+				h.Sort = "asc"
+				if sorted.Desc {
+					h.Sort = "desc"
+				} else {
+					next = "-" + col.Key
+				}
+			}
+			h.URL = link(map[string]string{"sort": next, "page": ""})
+		}
+		view.Columns = append(view.Columns, h)
+
+		if col.Filterable {
+			f := Filter{Name: "filter[" + col.Key + "]", Label: h.Label, Value: q.Filters[col.Key]}
+			for _, opt := range col.Options {
+				f.Options = append(f.Options, SelectOption{Value: opt.Value, Label: i18n.T(locale, opt.Label), Selected: opt.Value == f.Value})
+			}
+			view.Filters = append(view.Filters, f)
+		}
+	}
+
+	for i, row := range rows {
+		cells := make([]any, len(g.columns))
+		for j, col := range g.columns {
+			cells[j] = col.Value(row)
+		}
+		view.Rows[i] = cells
+	}
+
+	if g.searching {
+		view.Search = &Search{Value: q.Search, Placeholder: i18n.T(locale, g.search)}
+	}
+	for _, name := range []string{"sort", "per_page"} {
+		if v := query.Get(name); v != "" {
+			view.Hidden = append(view.Hidden, Param{Name: name, Value: v})
+		}
+	}
+
+	for _, size := range g.pageSizes {
+		view.PageSizes = append(view.PageSizes, PageSize{
+			Size:   size,
+			URL:    link(map[string]string{"per_page": strconv.Itoa(size), "page": ""}),
+			Active: size == q.PerPage,
+		})
+	}
+
+	pageURL := func(p int) string {
+		if p == 1 {
+			return link(map[string]string{"page": ""})
+		}
+		return link(map[string]string{"page": strconv.Itoa(p)})
+	}
+	if q.Page > 1 && q.Page <= pages {
+		view.Prev = pageURL(q.Page - 1)
+	}
+	if q.Page < pages {
+		view.Next = pageURL(q.Page + 1)
+	}
+	// 首页、末页与当前页附近的页码，不相邻处以省略号分隔
+	last := 0
+	for p := 1; p <= pages; p++ {
+		if p > 1 && p < q.Page-pageWindow {
+			p = min(q.Page-pageWindow, pages)
+		} else if p > q.Page+pageWindow && p < pages {
+			p = pages
+		}
+		if p > last+1 {
+			view.Links = append(view.Links, Link{Gap: true})
+		}
+		view.Links = append(view.Links, Link{Page: p, URL: pageURL(p), Active: p == q.Page})
+		last = p
+	}
+	return view
+}
@@ -0,0 +1,140 @@
+package datagrid
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+type order struct {
+	ID     int
+	No     string
+	Status string
+}
+
+var grid = New(
+	Column[order]{Key: "id", Label: "ID", Value: func(o order) any { return o.ID }, Sortable: true},
+	Col("no", "单号", func(o order) any { return template.HTML(`<a href="/orders/` + o.No + `">` + o.No + `</a>`) }),
+	Column[order]{Key: "status", Label: "状态", Value: func(o order) any { return o.Status },
+		Sortable: true, Filterable: true, Options: []Option{{Value: "paid", Label: "已支付"}, {Value: "new", Label: "新建"}}},
+).Searchable("搜索单号")
+
+// build 以 target 请求解析列表参数并生成视图数据
+func build(t *testing.T, target string, total int64, rows ...order) View {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	q, err := request.ParseListQuery(c, grid.ListOptions(request.WithListDefaultSort("-id"))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return grid.View(c, q, rows, total)
+}
+
+func TestColumns(t *testing.T) {
+	v := build(t, "/orders?sort=-id&filter[status]=paid&q=A1&page=2&x=1", 120, order{ID: 1, No: "A1", Status: "paid"})
+	id, no, status := v.Columns[0], v.Columns[1], v.Columns[2]
+	// 当前降序，点击切换为升序并回到第一页，保留其他参数
+	if id.Sort != "desc" || id.URL != "/orders?filter%5Bstatus%5D=paid&q=A1&sort=id&x=1" {
+		t.Errorf("id = %+v", id)
+	}
+	if no.Sortable || no.URL != "" || status.Sort != "" || status.URL != "/orders?filter%5Bstatus%5D=paid&q=A1&sort=status&x=1" {
+		t.Errorf("no = %+v status = %+v", no, status)
+	}
+	if len(v.Filters) != 1 || v.Filters[0].Name != "filter[status]" || v.Filters[0].Value != "paid" ||
+		!v.Filters[0].Options[0].Selected || v.Filters[0].Options[1].Selected {
+		t.Errorf("filters = %+v", v.Filters)
+	}
+	if v.Search == nil || v.Search.Value != "A1" || v.Search.Placeholder != "搜索单号" {
+		t.Errorf("search = %+v", v.Search)
+	}
+	if len(v.Hidden) != 1 || v.Hidden[0] != (Param{Name: "sort", Value: "-id"}) {
+		t.Errorf("hidden = %+v", v.Hidden)
+	}
+	if len(v.Rows) != 1 || v.Rows[0][0] != 1 || v.Rows[0][2] != "paid" {
+		t.Errorf("rows = %+v", v.Rows)
+	}
+
+	// 升序时点击切换为降序
+	if v := build(t, "/orders?sort=status", 0); v.Columns[2].Sort != "asc" || v.Columns[2].URL != "/orders?sort=-status" {
+		t.Errorf("status = %+v", v.Columns[2])
+	}
+
+	// 不在列定义中的排序与过滤由 ParseListQuery 拒绝
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/orders?sort=no", nil)
+	if _, err := request.ParseListQuery(c, grid.ListOptions()...); err == nil {
+		t.Error("不可排序的列应返回校验错误")
+	}
+}
+
+func TestPagination(t *testing.T) {
+	v := build(t, "/orders?page=5", 200)
+	if v.Pages != 10 || v.PerPage != 20 || v.Prev != "/orders?page=4" || v.Next != "/orders?page=6" {
+		t.Errorf("view = %+v", v)
+	}
+	var pages []string
+	for _, l := range v.Links {
+		switch {
+		case l.Gap:
+			pages = append(pages, "…")
+		case l.Active:
+			pages = append(pages, "["+strconv.Itoa(l.Page)+"]")
+		default:
+			pages = append(pages, strconv.Itoa(l.Page))
+		}
+	}
+	if got := strings.Join(pages, " "); got != "1 … 3 4 [5] 6 7 … 10" {
+		t.Errorf("links = %s", got)
+	}
+	if v.Links[0].URL != "/orders" {
+		t.Errorf("第一页链接 = %s", v.Links[0].URL)
+	}
+
+	if v := build(t, "/orders", 30); v.Prev != "" || v.Next != "/orders?page=2" || len(v.Links) != 2 {
+		t.Errorf("first page = %+v", v)
+	}
+
+	// 每页条数：切换时回到第一页，超出上限时取最大项
+	v = build(t, "/orders?per_page=50&page=3", 200)
+	if !v.PageSizes[1].Active || v.PageSizes[0].URL != "/orders?per_page=20" {
+		t.Errorf("page sizes = %+v", v.PageSizes)
+	}
+	if v := build(t, "/orders?per_page=500", 200); v.PerPage != 100 {
+		t.Errorf("per_page = %d", v.PerPage)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	tmpl := template.Must(template.ParseFiles("../../templates/partials/datagrid.html"))
+	var b strings.Builder
+	v := build(t, "/orders?sort=-id", 45, order{ID: 1, No: "A1", Status: "paid"})
+	if err := tmpl.Execute(&b, v); err != nil {
+		t.Fatal(err)
+	}
+	html := b.String()
+	for _, want := range []string{
+		`<th class="sorted-desc" aria-sort="descending">`,
+		`<a href="/orders?sort=id">ID</a>`,
+		`<td><a href="/orders/A1">A1</a></td>`,
+		`<option value="paid">已支付</option>`,
+		`<input type="hidden" name="sort" value="-id">`,
+		`<a href="/orders?page=2&amp;sort=-id" rel="next">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("缺少 %s", want)
+		}
+	}
+
+	b.Reset()
+	if err := tmpl.Execute(&b, build(t, "/orders", 0)); err != nil || !strings.Contains(b.String(), `colspan="3"`) {
+		t.Errorf("空表格: %v", err)
+	}
+}
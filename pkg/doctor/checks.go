@@ -0,0 +1,211 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/crypto"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/geoip"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/storage"
+	"github.com/gorilla-go/go-framework/pkg/template"
+)
+
+// minSecretLength JWT、会话密钥的最小长度（字节）
+const minSecretLength = 32
+
+// weakSecrets 示例配置与常见的弱密钥
+var weakSecrets = []string{"your-secret-key", "session-secret-key", "secret", "changeme", "change-me", "password", "123456"}
+
+// builtin 内置诊断项
+func builtin() []Check {
+	return []Check{
+		{Name: "配置校验", Run: checkConfig},
+		{Name: "数据库连接", Run: checkDatabase},
+		{Name: "Redis 连接", Run: checkRedis},
+		{Name: "日志目录可写", Run: func(_ context.Context, cfg *config.Config) error {
+			return writable(filepath.Dir(cfg.Log.Filename))
+		}},
+		{Name: "模板编译", Run: checkTemplates},
+		{Name: "GeoIP 数据库", Run: checkGeoIP},
+		{Name: "文件存储", Run: checkStorage},
+		{Name: "图片处理", Run: checkImage},
+		{Name: "密钥强度", Run: checkSecrets},
+	}
+}
+
+// checkConfig 校验取值范围与枚举项
+func checkConfig(_ context.Context, cfg *config.Config) error {
+	var errs []error
+	if !slices.Contains([]string{"debug", "release", "test"}, cfg.Server.Mode) {
+		errs = append(errs, fmt.Errorf("server.mode 应为 debug、release 或 test: %q", cfg.Server.Mode))
+	}
+	if cfg.Server.Port <= 0 || cfg.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port 超出范围: %d", cfg.Server.Port))
+	}
+	for _, p := range cfg.Server.TrustedProxies {
+		if net.ParseIP(p) == nil {
+			if _, _, err := net.ParseCIDR(p); err != nil {
+				errs = append(errs, fmt.Errorf("server.trusted_proxies 含非法 IP/CIDR: %q", p))
+			}
+		}
+	}
+	if !slices.Contains([]string{"debug", "info", "warn", "error", "fatal", "panic"}, cfg.Log.Level) {
+		errs = append(errs, fmt.Errorf("log.level 无效: %q", cfg.Log.Level))
+	}
+	if !slices.Contains([]string{"", "mysql", "sqlite", "sqlite3"}, strings.ToLower(cfg.Database.Driver)) {
+		errs = append(errs, fmt.Errorf("database.driver 不支持: %q（支持 mysql、sqlite）", cfg.Database.Driver))
+	}
+	if store := cfg.Session.Store; store != "" && !slices.Contains(session.Stores(), store) {
+		errs = append(errs, fmt.Errorf("session.store 未注册: %q（已注册: %v）", store, session.Stores()))
+	}
+	if cfg.Template.Extension == "" {
+		errs = append(errs, errors.New("template.extension 不能为空"))
+	}
+	return errors.Join(errs...)
+}
+
+// checkDatabase 按配置连接数据库并 Ping
+func checkDatabase(ctx context.Context, cfg *config.Config) error {
+	db, err := database.Open(&cfg.Database)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	return sqlDB.PingContext(ctx)
+}
+
+// checkRedis 会话或锁使用 Redis 时检查连通性
+func checkRedis(ctx context.Context, cfg *config.Config) error {
+	if cfg.Session.Store != "redis" && cfg.Lock.Driver != "redis" {
+		return Skipped("未使用 Redis（session.store: %s，lock.driver: %s）", cfg.Session.Store, cfg.Lock.Driver)
+	}
+	addr := net.JoinHostPort(cfg.Redis.Host, strconv.Itoa(cfg.Redis.Port))
+	conn, err := redis.DialContext(ctx, "tcp", addr, redis.DialPassword(cfg.Redis.Password), redis.DialDatabase(cfg.Redis.DB))
+	if err != nil {
+		return fmt.Errorf("连接 %s 失败: %w", addr, err)
+	}
+	defer conn.Close()
+	if _, err := redis.DoContext(conn, ctx, "PING"); err != nil {
+		return fmt.Errorf("PING %s 失败: %w", addr, err)
+	}
+	return nil
+}
+
+// checkGeoIP 配置了 geoip.database 时检查数据库可打开
+func checkGeoIP(_ context.Context, cfg *config.Config) error {
+	if cfg.GeoIP.Database == "" {
+		return Skipped("未配置 geoip.database")
+	}
+	db, err := geoip.Open(cfg.GeoIP.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return nil
+}
+
+// checkStorage 按配置创建全部存储（校验驱动与必填项），并检查本地存储的根目录可写
+func checkStorage(_ context.Context, cfg *config.Config) error {
+	m, err := storage.NewManager(&cfg.Storage)
+	if err != nil {
+		return err
+	}
+	if _, err := m.Disk(""); err != nil {
+		return fmt.Errorf("storage.default: %w", err)
+	}
+	for _, name := range m.Names() {
+		if disk := cfg.Storage.Disks[name]; disk.Driver == "local" {
+			if err := writable(disk.Root); err != nil {
+				return fmt.Errorf("存储 %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkImage 启用 image.enabled 时检查签名密钥、源目录（或 image.disk 引用的存储）与磁盘缓存目录
+func checkImage(_ context.Context, cfg *config.Config) error {
+	if !cfg.Image.Enabled {
+		return Skipped("未启用 image.enabled")
+	}
+	if cfg.Image.Secret == "" {
+		return errors.New("image.secret 未配置")
+	}
+	if cfg.Image.Disk != "" {
+		if _, ok := cfg.Storage.Disks[cfg.Image.Disk]; !ok {
+			return fmt.Errorf("image.disk 引用了未配置的存储 %s", cfg.Image.Disk)
+		}
+	} else if info, err := os.Stat(cfg.Image.Source); err != nil || !info.IsDir() {
+		return fmt.Errorf("源图片目录 %s 不存在", cfg.Image.Source)
+	}
+	if cfg.Image.Cache == "disk" {
+		return writable(cfg.Image.CacheDir)
+	}
+	return nil
+}
+
+// checkTemplates 解析全部模板，报告语法错误
+func checkTemplates(_ context.Context, cfg *config.Config) error {
+	return template.NewTemplateManager(cfg.Template, false).Compile()
+}
+
+// checkSecrets 检查 JWT、会话与加密密钥；开发与测试环境下弱密钥仅警告
+func checkSecrets(_ context.Context, cfg *config.Config) error {
+	var problems []string
+	for _, s := range []struct{ key, value string }{
+		{"jwt.secret", cfg.JWT.Secret},
+		{"session.secret", cfg.Session.Secret},
+		{"image.secret", cfg.Image.Secret},
+	} {
+		if s.key == "image.secret" && !cfg.Image.Enabled {
+			continue
+		}
+		if problem := secretProblem(s.value); problem != "" {
+			problems = append(problems, s.key+" "+problem)
+		}
+	}
+	if cfg.Crypto.Key != "" {
+		key, err := crypto.ParseKey(cfg.Crypto.Key)
+		if err != nil {
+			problems = append(problems, "crypto.key "+err.Error())
+		} else if n := len(key); n != 16 && n != 24 && n != 32 {
+			problems = append(problems, fmt.Sprintf("crypto.key 长度应为 16/24/32 字节，当前 %d", n))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	msg := strings.Join(problems, "；")
+	if cfg.IsDevelopment() || cfg.IsTesting() {
+		return Warning("%s", msg)
+	}
+	return errors.New(msg)
+}
+
+// secretProblem 返回密钥的问题描述，合格时为空
+func secretProblem(secret string) string {
+	switch {
+	case secret == "":
+		return "未设置"
+	case slices.Contains(weakSecrets, strings.ToLower(secret)):
+		return "使用了示例或常见弱密钥"
+	case len(secret) < minSecretLength:
+		return fmt.Sprintf("长度不足 %d 字节", minSecretLength)
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+// Package doctor 部署前诊断：检查配置、数据库与 Redis 连通性、目录可写、模板编译与密钥强度，
+// 由 `app doctor` 命令调用并打印通过/失败报告。
+//
+// 应用可注册自己的诊断项，如上传目录：
+//
+//	func init() {
+//	    doctor.Register(doctor.WritableDir("上传目录", "storage/uploads"))
+//	}
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// Status 诊断结果状态
+type Status int
+
+const (
+	Pass Status = iota // 通过
+	Warn               // 警告：不影响启动，但上线前应处理
+	Fail               // 失败
+	Skip               // 跳过：当前配置下不适用
+)
+
+// String 返回报告中显示的状态标签
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "PASS"
+	case Warn:
+		return "WARN"
+	case Fail:
+		return "FAIL"
+	default:
+		return "SKIP"
+	}
+}
+
+// Check 诊断项，Run 返回 nil 表示通过；返回 Warning、Skipped 创建的错误时分别记为警告与跳过，其他错误记为失败
+type Check struct {
+	Name string
+	Run  func(ctx context.Context, cfg *config.Config) error
+}
+
+// Result 诊断结果
+type Result struct {
+	Name    string
+	Status  Status
+	Message string
+}
+
+// statusError 携带非失败状态的诊断错误
+type statusError struct {
+	status Status
+	msg    string
+}
+
+func (e *statusError) Error() string { return e.msg }
+
+// Warning 返回记为警告的诊断错误
+func Warning(format string, args ...any) error {
+	return &statusError{status: Warn, msg: fmt.Sprintf(format, args...)}
+}
+
+// Skipped 返回记为跳过的诊断错误
+func Skipped(format string, args ...any) error {
+	return &statusError{status: Skip, msg: fmt.Sprintf(format, args...)}
+}
+
+// Timeout 单个诊断项的超时时间
+var Timeout = 5 * time.Second
+
+var (
+	mu     sync.RWMutex
+	custom []Check
+)
+
+// Register 追加应用自定义的诊断项，在内置诊断项之后按注册顺序执行
+func Register(checks ...Check) {
+	mu.Lock()
+	defer mu.Unlock()
+	custom = append(custom, checks...)
+}
+
+// Checks 返回内置与已注册的全部诊断项
+func Checks() []Check {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append(builtin(), custom...)
+}
+
+// Run 依次执行诊断项，每项受 Timeout 限制；checks 为空时执行 Checks()
+func Run(ctx context.Context, cfg *config.Config, checks ...Check) []Result {
+	if len(checks) == 0 {
+		checks = Checks()
+	}
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, runCheck(ctx, cfg, check))
+	}
+	return results
+}
+
+// runCheck 执行单个诊断项，panic 记为失败
+func runCheck(ctx context.Context, cfg *config.Config, check Check) (result Result) {
+	result = Result{Name: check.Name, Status: Pass}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Status, result.Message = Fail, fmt.Sprintf("panic: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	err := check.Run(ctx, cfg)
+	if err == nil {
+		return result
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		result.Status = se.status
+	} else {
+		result.Status = Fail
+	}
+	result.Message = err.Error()
+	return result
+}
+
+// Report 打印诊断报告，返回失败项数量
+func Report(w io.Writer, results []Result) int {
+	counts := make(map[Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(w, "[%s] %s", r.Status, r.Name)
+		if r.Message != "" {
+			fmt.Fprintf(w, ": %s", r.Message)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "\n通过 %d，警告 %d，失败 %d，跳过 %d\n", counts[Pass], counts[Warn], counts[Fail], counts[Skip])
+	return counts[Fail]
+}
+
+// WritableDir 返回检查目录可写的诊断项，目录不存在时尝试创建
+func WritableDir(name, dir string) Check {
+	return Check{Name: name, Run: func(context.Context, *config.Config) error {
+		return writable(dir)
+	}}
+}
+
+// writable 在目录中创建并删除临时文件，确认进程有写权限
+func writable(dir string) error {
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建目录 %s 失败: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fmt.Errorf("目录 %s 不可写: %w", dir, err)
+	}
+	f.Close()
+	return os.Remove(filepath.Clean(f.Name()))
+}
@@ -0,0 +1,179 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// TestRunStatuses 诊断项按返回值记为通过、警告、跳过或失败，panic 记为失败
+func TestRunStatuses(t *testing.T) {
+	checks := []Check{
+		{Name: "ok", Run: func(context.Context, *config.Config) error { return nil }},
+		{Name: "warn", Run: func(context.Context, *config.Config) error { return Warning("弱密钥") }},
+		{Name: "skip", Run: func(context.Context, *config.Config) error { return Skipped("未启用") }},
+		{Name: "fail", Run: func(context.Context, *config.Config) error { return errors.New("连接失败") }},
+		{Name: "panic", Run: func(context.Context, *config.Config) error { panic("boom") }},
+	}
+	results := Run(context.Background(), &config.Config{}, checks...)
+	want := []Status{Pass, Warn, Skip, Fail, Fail}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: 期望 %s，得到 %s（%s）", r.Name, want[i], r.Status, r.Message)
+		}
+	}
+
+	var out bytes.Buffer
+	if n := Report(&out, results); n != 2 {
+		t.Errorf("失败项数量 %d", n)
+	}
+	for _, line := range []string{"[PASS] ok", "[WARN] warn: 弱密钥", "[FAIL] fail: 连接失败", "通过 1，警告 1，失败 2，跳过 1"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("报告缺少 %q:\n%s", line, out.String())
+		}
+	}
+}
+
+// TestWritableDir 目录不存在时创建，不可写时失败，且不留下临时文件
+func TestWritableDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "uploads")
+	if err := WritableDir("上传目录", dir).Run(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("不应留下临时文件: %v", entries)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writable(file); err == nil {
+		t.Error("普通文件路径应检查失败")
+	}
+}
+
+// TestCheckConfig 报告全部非法配置项
+func TestCheckConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Server.Mode = "prod"
+	cfg.Server.Port = 70000
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "bad"}
+	cfg.Log.Level = "verbose"
+	cfg.Database.Driver = "oracle"
+	cfg.Session.Store = "nope"
+	cfg.Template.Extension = "html"
+
+	err := checkConfig(context.Background(), cfg)
+	if err == nil {
+		t.Fatal("期望配置校验失败")
+	}
+	for _, key := range []string{"server.mode", "server.port", `"bad"`, "log.level", "database.driver", "session.store"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("缺少 %s: %v", key, err)
+		}
+	}
+	if strings.Contains(err.Error(), "10.0.0.0/8") {
+		t.Errorf("合法 CIDR 不应报错: %v", err)
+	}
+}
+
+// TestCheckSecrets 开发环境下弱密钥警告，其他环境失败
+func TestCheckSecrets(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Server.Mode = "release"
+	cfg.JWT.Secret = "your-secret-key"
+	cfg.Session.Secret = strings.Repeat("s", 40)
+	cfg.Crypto.Key = "short"
+
+	r := runCheck(context.Background(), cfg, Check{Name: "密钥强度", Run: checkSecrets})
+	if r.Status != Fail || !strings.Contains(r.Message, "jwt.secret") || !strings.Contains(r.Message, "crypto.key") {
+		t.Errorf("release: %s %s", r.Status, r.Message)
+	}
+	if strings.Contains(r.Message, "session.secret") {
+		t.Errorf("合格密钥不应报告: %s", r.Message)
+	}
+
+	cfg.Server.Mode = "debug"
+	if r := runCheck(context.Background(), cfg, Check{Name: "密钥强度", Run: checkSecrets}); r.Status != Warn {
+		t.Errorf("开发环境应为警告: %s", r.Status)
+	}
+
+	cfg.JWT.Secret = strings.Repeat("j", 32)
+	cfg.Crypto.Key = strings.Repeat("k", 32)
+	if err := checkSecrets(context.Background(), cfg); err != nil {
+		t.Errorf("合格密钥: %v", err)
+	}
+}
+
+// TestCheckConnections sqlite 数据库连接通过，未使用 Redis、未配置 GeoIP 时跳过
+func TestCheckConnections(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Database = config.DatabaseConfig{Driver: "sqlite", DBName: ":memory:", MaxIdleConns: 1, MaxOpenConns: 1}
+	cfg.Session.Store = "cookie"
+
+	results := Run(context.Background(), cfg,
+		Check{Name: "数据库连接", Run: checkDatabase},
+		Check{Name: "Redis 连接", Run: checkRedis},
+		Check{Name: "GeoIP 数据库", Run: checkGeoIP},
+	)
+	if results[0].Status != Pass {
+		t.Errorf("数据库: %s %s", results[0].Status, results[0].Message)
+	}
+	if results[1].Status != Skip {
+		t.Errorf("Redis: %s %s", results[1].Status, results[1].Message)
+	}
+	if results[2].Status != Skip {
+		t.Errorf("GeoIP: %s %s", results[2].Status, results[2].Message)
+	}
+}
+
+// TestCheckImage 未启用时跳过，启用后需配置密钥与存在的源目录
+func TestCheckImage(t *testing.T) {
+	cfg := &config.Config{}
+	if r := runCheck(context.Background(), cfg, Check{Name: "图片处理", Run: checkImage}); r.Status != Skip {
+		t.Errorf("未启用: %s %s", r.Status, r.Message)
+	}
+
+	cfg.Image = config.ImageConfig{Enabled: true, Source: t.TempDir(), Cache: "disk", CacheDir: filepath.Join(t.TempDir(), "images")}
+	if err := checkImage(context.Background(), cfg); err == nil {
+		t.Error("未配置密钥应失败")
+	}
+	cfg.Image.Secret = strings.Repeat("s", 32)
+	if err := checkImage(context.Background(), cfg); err != nil {
+		t.Errorf("合格配置: %v", err)
+	}
+	cfg.Image.Source = filepath.Join(cfg.Image.Source, "missing")
+	if err := checkImage(context.Background(), cfg); err == nil {
+		t.Error("源目录不存在应失败")
+	}
+}
+
+// TestCheckStorage 未注册的驱动与缺少默认存储失败，本地存储根目录可写时通过
+func TestCheckStorage(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Storage = config.StorageConfig{
+		Default: "local",
+		Disks:   map[string]config.DiskConfig{"local": {Driver: "local", Root: t.TempDir()}},
+	}
+	if err := checkStorage(context.Background(), cfg); err != nil {
+		t.Errorf("合格配置: %v", err)
+	}
+
+	cfg.Storage.Default = "s3"
+	if err := checkStorage(context.Background(), cfg); err == nil {
+		t.Error("默认存储未配置应失败")
+	}
+	cfg.Storage.Default = "local"
+	cfg.Storage.Disks["s3"] = config.DiskConfig{Driver: "ftp"}
+	if err := checkStorage(context.Background(), cfg); err == nil {
+		t.Error("未注册的驱动应失败")
+	}
+}
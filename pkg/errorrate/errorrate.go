@@ -0,0 +1,285 @@
+// Package errorrate 按路由统计滚动窗口内的 5xx 比例（错误预算），超过阈值时触发 alert.error_rate 事件，
+// 回落到阈值以下时再次触发（Resolved 为 true），可选同时 POST 到告警 Webhook：
+//
+//	m := errorrate.New(eventbus.Default(), errorrate.WithThreshold(0.05), errorrate.WithWindow(time.Minute))
+//	r.Use(m.Middleware()) // 挂在 Recovery 之前，panic 恢复后的 500 同样计入
+//	eventbus.On(errorrate.EventAlert, func(args ...any) { alert := args[0].(*errorrate.Alert) })
+//
+// 告警按状态变化触发：持续超过阈值期间不会重复触发。各路由的统计发布到 /debug/vars 中的 errorrate。
+package errorrate
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+)
+
+// EventAlert 路由 5xx 比例超过阈值或恢复，参数为 *Alert
+const EventAlert = "alert.error_rate"
+
+// buckets 滚动窗口划分的桶数
+const buckets = 10
+
+// Alert 错误率告警
+type Alert struct {
+	Route     string    `json:"route"`     // 路由，如 GET /api/orders/:id
+	Resolved  bool      `json:"resolved"`  // false 为超过阈值，true 为已恢复
+	Rate      float64   `json:"rate"`      // 窗口内的 5xx 比例
+	Errors    int       `json:"errors"`    // 窗口内的 5xx 响应数
+	Total     int       `json:"total"`     // 窗口内的请求数
+	Threshold float64   `json:"threshold"` // 告警阈值
+	Window    int       `json:"window"`    // 统计窗口（秒）
+	Time      time.Time `json:"time"`
+}
+
+// RouteStatus 路由在当前窗口内的统计
+type RouteStatus struct {
+	Route  string  `json:"route"`
+	Total  int     `json:"total"`
+	Errors int     `json:"errors"`
+	Rate   float64 `json:"rate"`
+	Firing bool    `json:"firing"` // 是否处于告警状态
+}
+
+// bucket 窗口中的一段时间
+type bucket struct {
+	slot   int64 // 所属时间段编号，用于判断是否过期
+	total  int
+	errors int
+}
+
+// routeWindow 单个路由的滚动窗口
+type routeWindow struct {
+	buckets [buckets]bucket
+	firing  bool
+}
+
+// Monitor 错误率监控
+type Monitor struct {
+	bus         *eventbus.EventBus
+	clock       clock.Clock
+	window      time.Duration
+	threshold   float64
+	minRequests int
+	webhookURL  string
+	client      *http.Client
+
+	mu     sync.Mutex
+	routes map[string]*routeWindow
+}
+
+// Option 配置选项
+type Option func(*Monitor)
+
+// WithWindow 设置统计窗口（默认 1 分钟）
+func WithWindow(d time.Duration) Option {
+	return func(m *Monitor) {
+		if d > 0 {
+			m.window = d
+		}
+	}
+}
+
+// WithThreshold 设置告警阈值，即窗口内 5xx 响应占比（默认 0.05）
+func WithThreshold(rate float64) Option {
+	return func(m *Monitor) {
+		if rate > 0 {
+			m.threshold = rate
+		}
+	}
+}
+
+// WithMinRequests 设置计算比例所需的最少请求数（默认 20），避免低流量时一两次失败即告警
+func WithMinRequests(n int) Option {
+	return func(m *Monitor) { m.minRequests = max(n, 1) }
+}
+
+// WithWebhook 设置告警 Webhook，触发与恢复时以 JSON 格式 POST Alert（异步发送，失败时写入日志）
+func WithWebhook(url string) Option {
+	return func(m *Monitor) { m.webhookURL = url }
+}
+
+// WithClock 设置统计使用的时钟（默认全局时钟）
+func WithClock(clk clock.Clock) Option {
+	return func(m *Monitor) { m.clock = clk }
+}
+
+// New 创建错误率监控，bus 为 nil 时使用全局事件总线
+func New(bus *eventbus.EventBus, opts ...Option) *Monitor {
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	m := &Monitor{
+		bus:         bus,
+		clock:       clock.Default(),
+		window:      time.Minute,
+		threshold:   0.05,
+		minRequests: 20,
+		client:      httpclient.New(5 * time.Second),
+		routes:      make(map[string]*routeWindow),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewFromConfig 按 error_rate.* 配置创建错误率监控
+func NewFromConfig(cfg *config.ErrorRateConfig, bus *eventbus.EventBus) *Monitor {
+	return New(bus,
+		WithWindow(time.Duration(cfg.Window)*time.Second),
+		WithThreshold(cfg.Threshold),
+		WithMinRequests(cfg.MinRequests),
+		WithWebhook(cfg.WebhookURL),
+	)
+}
+
+// Middleware 返回记录响应状态的中间件，按 "方法 路由模板" 统计，未匹配路由的请求（404）不计入。
+// 应挂在 Recovery 之前，使 panic 恢复后写出的 500 同样计入
+func (m *Monitor) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if path := c.FullPath(); path != "" {
+			m.Record(c.Request.Method+" "+path, c.Writer.Status())
+		}
+	}
+}
+
+// Record 记录一次响应，状态码 >= 500 计为错误；5xx 比例越过阈值时触发 EventAlert。
+// 中间件之外的入口（如队列消费者）也可按名称记录
+func (m *Monitor) Record(route string, status int) {
+	if alert := m.record(route, status >= http.StatusInternalServerError); alert != nil {
+		_ = m.bus.EmitCtx(context.Background(), EventAlert, alert)
+		if m.webhookURL != "" {
+			go m.post(alert)
+		}
+	}
+}
+
+// record 更新窗口并判断告警状态，状态变化时返回告警
+func (m *Monitor) record(route string, failed bool) *Alert {
+	now := m.clock.Now()
+	slot := now.UnixNano() / int64(m.window/buckets)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := m.routes[route]
+	if w == nil {
+		w = &routeWindow{}
+		m.routes[route] = w
+	}
+	b := &w.buckets[slot%buckets]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+	b.total++
+	if failed {
+		b.errors++
+	}
+
+	total, errs := w.sum(slot)
+	rate := float64(errs) / float64(total)
+	switch {
+	case !w.firing && total >= m.minRequests && rate >= m.threshold:
+		w.firing = true
+	case w.firing && rate < m.threshold:
+		w.firing = false
+	default:
+		return nil
+	}
+	return &Alert{
+		Route: route, Resolved: !w.firing, Rate: rate, Errors: errs, Total: total,
+		Threshold: m.threshold, Window: int(m.window / time.Second), Time: now,
+	}
+}
+
+// sum 返回窗口内（当前时间段 slot 及之前的 buckets-1 段）的请求数与错误数
+func (w *routeWindow) sum(slot int64) (total, errs int) {
+	for _, b := range w.buckets {
+		if b.slot > slot-buckets {
+			total += b.total
+			errs += b.errors
+		}
+	}
+	return total, errs
+}
+
+// Status 返回当前窗口内有请求的路由统计，按路由排序
+func (m *Monitor) Status() []RouteStatus {
+	slot := m.clock.Now().UnixNano() / int64(m.window/buckets)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]RouteStatus, 0, len(m.routes))
+	for route, w := range m.routes {
+		total, errs := w.sum(slot)
+		if total == 0 && !w.firing {
+			continue
+		}
+		s := RouteStatus{Route: route, Total: total, Errors: errs, Firing: w.firing}
+		if total > 0 {
+			s.Rate = float64(errs) / float64(total)
+		}
+		list = append(list, s)
+	}
+	slices.SortFunc(list, func(a, b RouteStatus) int { return cmp.Compare(a.Route, b.Route) })
+	return list
+}
+
+// post 将告警 POST 到 Webhook
+func (m *Monitor) post(alert *Alert) {
+	body, _ := json.Marshal(struct {
+		Event string `json:"event"`
+		*Alert
+	}{EventAlert, alert})
+	err := func() error {
+		resp, err := m.client.Post(m.webhookURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("响应状态 %d", resp.StatusCode)
+		}
+		return nil
+	}()
+	if err != nil && logger.SugarLogger != nil {
+		logger.SugarLogger.Warnw("发送错误率告警失败", "route", alert.Route, "url", m.webhookURL, "error", err)
+	}
+}
+
+var defaultMonitor atomic.Pointer[Monitor]
+
+// SetDefault 设置全局错误率监控（/debug/vars 读取），传入 nil 时清除
+func SetDefault(m *Monitor) {
+	defaultMonitor.Store(m)
+}
+
+// Default 返回全局错误率监控，未设置时为 nil
+func Default() *Monitor {
+	return defaultMonitor.Load()
+}
+
+func init() {
+	// 各路由的统计发布到 /debug/vars 中的 errorrate
+	expvar.Publish("errorrate", expvar.Func(func() any {
+		if m := Default(); m != nil {
+			return m.Status()
+		}
+		return nil
+	}))
+}
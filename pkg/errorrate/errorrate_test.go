@@ -0,0 +1,79 @@
+package errorrate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+// TestMonitor 越过阈值时触发一次告警，持续超过阈值不重复触发，回落后触发恢复
+func TestMonitor(t *testing.T) {
+	bus := eventbus.New()
+	var alerts []*Alert
+	bus.On(EventAlert, func(args ...interface{}) { alerts = append(alerts, args[0].(*Alert)) })
+
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := New(bus, WithThreshold(0.5), WithMinRequests(4), WithWindow(10*time.Second), WithClock(clk))
+
+	for range 3 {
+		m.Record("GET /orders", http.StatusInternalServerError)
+	}
+	if len(alerts) != 0 {
+		t.Fatal("未达到最少请求数时不应告警")
+	}
+	m.Record("GET /orders", http.StatusOK)
+	m.Record("GET /orders", http.StatusBadGateway)
+	if len(alerts) != 1 || alerts[0].Resolved || alerts[0].Route != "GET /orders" || alerts[0].Total != 4 || alerts[0].Errors != 3 {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+	m.Record("GET /users", http.StatusOK)
+	if st := m.Status(); len(st) != 2 || !st[0].Firing || st[0].Total != 5 || st[1].Firing {
+		t.Errorf("status = %+v", st)
+	}
+
+	// 窗口滑过后旧的失败不再计入
+	clk.Advance(11 * time.Second)
+	m.Record("GET /orders", http.StatusOK)
+	if len(alerts) != 2 || !alerts[1].Resolved || alerts[1].Total != 1 || alerts[1].Rate != 0 {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+}
+
+// TestMiddleware 按路由模板统计，告警同时 POST 到 Webhook
+func TestMiddleware(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received <- body
+	}))
+	defer hook.Close()
+
+	m := New(eventbus.New(), WithMinRequests(2), WithWebhook(hook.URL))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(m.Middleware(), gin.CustomRecovery(func(c *gin.Context, _ any) { c.AbortWithStatus(http.StatusInternalServerError) }))
+	r.GET("/orders/:id", func(c *gin.Context) { panic("boom") })
+
+	for _, path := range []string{"/orders/1", "/orders/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if st := m.Status(); len(st) != 1 || st[0].Route != "GET /orders/:id" || st[0].Errors != 2 || !st[0].Firing {
+		t.Errorf("status = %+v", st)
+	}
+
+	select {
+	case body := <-received:
+		if body["event"] != EventAlert || body["route"] != "GET /orders/:id" || body["resolved"] != false {
+			t.Errorf("webhook body = %v", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("未收到告警 Webhook")
+	}
+}
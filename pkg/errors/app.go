@@ -3,6 +3,8 @@ package errors
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
 )
 
 // 定义错误码常量
@@ -17,8 +19,10 @@ const (
 	Forbidden        = 403
 	NotFound         = 404
 	MethodNotAllowed = 405
+	NotAcceptable    = 406
 	RequestTimeout   = 408
 	Conflict         = 409
+	EntityTooLarge   = 413
 	TooManyRequests  = 429
 
 	// 服务器错误
@@ -44,8 +48,10 @@ var ErrMsg = map[int]string{
 	Forbidden:           "拒绝访问",
 	NotFound:            "资源不存在",
 	MethodNotAllowed:    "方法不允许",
+	NotAcceptable:       "无法接受的请求",
 	RequestTimeout:      "请求超时",
 	Conflict:            "资源冲突",
+	EntityTooLarge:      "请求体过大",
 	TooManyRequests:     "请求过多",
 	InternalServerError: "服务器内部错误",
 	ServiceUnavailable:  "服务不可用",
@@ -58,12 +64,82 @@ var ErrMsg = map[int]string{
 	AuthorizationError:  "授权错误",
 }
 
+// MessageKeys 错误码对应的消息 key，消息按请求语言在 i18n 目录中查找（见 AppError.Localize）
+var MessageKeys = map[int]string{
+	Success:             "error.success",
+	NoContent:           "error.no_content",
+	BadRequest:          "error.bad_request",
+	Unauthorized:        "error.unauthorized",
+	Forbidden:           "error.forbidden",
+	NotFound:            "error.not_found",
+	MethodNotAllowed:    "error.method_not_allowed",
+	NotAcceptable:       "error.not_acceptable",
+	RequestTimeout:      "error.request_timeout",
+	Conflict:            "error.conflict",
+	EntityTooLarge:      "error.entity_too_large",
+	TooManyRequests:     "error.too_many_requests",
+	InternalServerError: "error.internal_server_error",
+	ServiceUnavailable:  "error.service_unavailable",
+	GatewayTimeout:      "error.gateway_timeout",
+	ValidationError:     "error.validation",
+	DatabaseError:       "error.database",
+	CacheError:          "error.cache",
+	ConfigError:         "error.config",
+	AuthenticationError: "error.authentication",
+	AuthorizationError:  "error.authorization",
+}
+
+// 默认语言的消息即 ErrMsg，另内置英文消息
+func init() {
+	zh := make(map[string]string, len(MessageKeys))
+	for code, key := range MessageKeys {
+		zh[key] = ErrMsg[code]
+	}
+	i18n.Register(i18n.DefaultLocale, zh)
+	i18n.Register("en", map[string]string{
+		"error.success":               "OK",
+		"error.no_content":            "No content",
+		"error.bad_request":           "Bad request",
+		"error.unauthorized":          "Unauthorized",
+		"error.forbidden":             "Forbidden",
+		"error.not_found":             "Not found",
+		"error.method_not_allowed":    "Method not allowed",
+		"error.not_acceptable":        "Not acceptable",
+		"error.request_timeout":       "Request timeout",
+		"error.conflict":              "Conflict",
+		"error.entity_too_large":      "Request entity too large",
+		"error.too_many_requests":     "Too many requests",
+		"error.internal_server_error": "Internal server error",
+		"error.service_unavailable":   "Service unavailable",
+		"error.gateway_timeout":       "Gateway timeout",
+		"error.validation":            "Validation failed",
+		"error.database":              "Database error",
+		"error.cache":                 "Cache error",
+		"error.config":                "Configuration error",
+		"error.authentication":        "Authentication failed",
+		"error.authorization":         "Authorization failed",
+	})
+}
+
 // AppError 应用错误
 type AppError struct {
 	Code    int    `json:"code"`    // 错误码
-	Message string `json:"message"` // 错误消息
+	Message string `json:"message"` // 错误消息（默认语言）
 	Detail  string `json:"detail"`  // 详细错误信息
 	Err     error  `json:"-"`       // 原始错误
+	Key     string `json:"-"`       // 消息 key，为空时不翻译
+}
+
+// Localize 返回指定语言的错误消息，沿 i18n 回退链查找 Key；
+// locale 为空、未设置 Key 或均未命中时返回 Message，与未接入多语言时一致
+func (e *AppError) Localize(locale string) string {
+	if locale == "" || e.Key == "" {
+		return e.Message
+	}
+	if msg, ok := i18n.Lookup(locale, e.Key); ok {
+		return msg
+	}
+	return e.Message
 }
 
 // Error 实现error接口
@@ -79,8 +155,11 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
-// HTTPStatus 根据错误码返回HTTP状态码
+// HTTPStatus 根据错误码返回HTTP状态码，应用注册的错误码（RegisterCode）按注册时的状态码返回
 func (e *AppError) HTTPStatus() int {
+	if status, ok := registeredStatus(e.Code); ok {
+		return status
+	}
 	switch {
 	case e.Code >= 400 && e.Code < 500:
 		return e.Code
@@ -110,7 +189,21 @@ func New(code int, detail string, err error) *AppError {
 		Message: msg,
 		Detail:  detail,
 		Err:     err,
+		Key:     MessageKeys[code],
+	}
+}
+
+// NewWithKey 创建使用自定义消息 key 的错误，Message 取默认语言的翻译：
+//
+//	i18n.Register("en", map[string]string{"order.paid": "Order already paid"})
+//	return errors.NewWithKey(errors.Conflict, "order.paid", "", nil)
+func NewWithKey(code int, key, detail string, err error) *AppError {
+	e := New(code, detail, err)
+	e.Key = key
+	if msg, ok := i18n.Lookup(i18n.DefaultLocale, key); ok {
+		e.Message = msg
 	}
+	return e
 }
 
 // NewBadRequest 创建无效请求错误
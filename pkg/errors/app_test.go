@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+)
+
+// TestLocalize 按语言翻译错误消息，未指定语言时保持默认中文消息
+func TestLocalize(t *testing.T) {
+	err := NewNotFound("用户不存在", nil)
+	if err.Message != "资源不存在" || err.Localize("") != "资源不存在" {
+		t.Errorf("默认消息 %q", err.Message)
+	}
+	if got := err.Localize("en-US"); got != "Not found" {
+		t.Errorf("en-US: %q", got)
+	}
+	if got := err.Localize("fr"); got != "资源不存在" {
+		t.Errorf("未注册语言应回退默认语言: %q", got)
+	}
+
+	i18n.Register(i18n.DefaultLocale, map[string]string{"test.order_paid": "订单已支付"})
+	i18n.Register("en", map[string]string{"test.order_paid": "Order already paid"})
+	custom := NewWithKey(Conflict, "test.order_paid", "", nil)
+	if custom.Message != "订单已支付" || custom.Localize("en") != "Order already paid" {
+		t.Errorf("自定义 key: %q %q", custom.Message, custom.Localize("en"))
+	}
+
+	// 手工构造（无 Key）的错误不翻译
+	manual := &AppError{Code: NotFound, Message: "自定义"}
+	if got := manual.Localize("en"); got != "自定义" {
+		t.Errorf("无 Key: %q", got)
+	}
+}
@@ -0,0 +1,252 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// editors 内置编辑器的链接模板，{file} 为文件绝对路径、{line} 为行号
+var editors = map[string]string{
+	"vscode":          "vscode://file/{file}:{line}",
+	"vscode-insiders": "vscode-insiders://file/{file}:{line}",
+	"cursor":          "cursor://file/{file}:{line}",
+	"goland":          "goland://open?file={file}&line={line}",
+	"idea":            "idea://open?file={file}&line={line}",
+	"sublime":         "subl://open?url=file://{file}&line={line}",
+}
+
+// DefaultEditor 未调用 SetEditor 时使用的编辑器
+const DefaultEditor = "vscode"
+
+var editorTemplate atomic.Value // string
+
+// SetEditor 设置开发错误页中文件链接打开的编辑器：内置名称（vscode、vscode-insiders、cursor、goland、idea、sublime）
+// 或含 {file}、{line} 占位符的自定义模板（如 "nvim://open?file={file}&line={line}"），空字符串或 "none" 不生成链接
+func SetEditor(editor string) {
+	tmpl, ok := editors[strings.ToLower(editor)]
+	if !ok {
+		tmpl = ""
+		if strings.Contains(editor, "{file}") {
+			tmpl = editor
+		}
+	}
+	editorTemplate.Store(tmpl)
+}
+
+// EditorURL 返回在编辑器中打开 file 第 line 行的链接，未配置编辑器或文件为空时返回空字符串
+func EditorURL(file string, line int) string {
+	tmpl, ok := editorTemplate.Load().(string)
+	if !ok {
+		tmpl = editors[DefaultEditor]
+	}
+	if tmpl == "" || file == "" {
+		return ""
+	}
+	path := (&url.URL{Path: filepath.ToSlash(file)}).EscapedPath()
+	return strings.NewReplacer("{file}", path, "{line}", strconv.Itoa(max(line, 1))).Replace(tmpl)
+}
+
+// KV 错误页展示的键值对
+type KV struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// RequestContext 开发错误页展示的请求上下文，由中间件从 gin.Context 构建（见 middleware.ErrorContext）
+type RequestContext struct {
+	Request *http.Request
+	Params  []KV // 路由参数
+	Session []KV // 会话数据
+}
+
+// sensitiveHeaders 错误页中以掩码展示的请求头
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+	"X-Csrf-Token":        true,
+}
+
+// DevError 开发错误页的结构化数据，请求带 ?format=json 时以 JSON 输出
+type DevError struct {
+	Type    string       `json:"type"`
+	Message string       `json:"message"`
+	File    string       `json:"file,omitempty"`
+	Line    int          `json:"line,omitempty"`
+	Code    []CodeLine   `json:"code,omitempty"`
+	Stack   []StackFrame `json:"stack"`
+	Request *RequestInfo `json:"request,omitempty"`
+}
+
+// RequestInfo DevError 中的请求上下文（敏感请求头已掩码）
+type RequestInfo struct {
+	Method  string `json:"method"`
+	URL     string `json:"url"`
+	Params  []KV   `json:"params,omitempty"`
+	Headers []KV   `json:"headers,omitempty"`
+	Session []KV   `json:"session,omitempty"`
+}
+
+// wantsJSON 请求是否要求以 JSON 输出开发错误页（?format=json）
+func (rc *RequestContext) wantsJSON() bool {
+	return rc != nil && rc.Request != nil && rc.Request.URL.Query().Get("format") == "json"
+}
+
+// info 转换为 DevError 中的请求上下文
+func (rc *RequestContext) info() *RequestInfo {
+	if rc == nil || rc.Request == nil {
+		return nil
+	}
+	return &RequestInfo{
+		Method:  rc.Request.Method,
+		URL:     rc.Request.URL.String(),
+		Params:  rc.Params,
+		Headers: rc.headers(),
+		Session: rc.Session,
+	}
+}
+
+// writeDevErrorJSON 以 500 状态码输出结构化错误
+func writeDevErrorJSON(w http.ResponseWriter, e *DevError) {
+	if e.Stack == nil {
+		e.Stack = []StackFrame{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(e)
+}
+
+// headers 返回按名称排序的请求头，敏感头以掩码代替
+func (rc *RequestContext) headers() []KV {
+	if rc == nil || rc.Request == nil {
+		return nil
+	}
+	kvs := make([]KV, 0, len(rc.Request.Header))
+	for k, v := range rc.Request.Header {
+		value := strings.Join(v, ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			value = "******"
+		}
+		kvs = append(kvs, KV{Key: k, Value: value})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+// StackFrame 堆栈帧
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	// Vendor 为 true 表示运行时、标准库或第三方依赖的帧（不在应用目录下），错误页默认折叠
+	Vendor bool `json:"vendor"`
+}
+
+// ParseStack 解析 debug.Stack() 格式的堆栈为帧列表
+func ParseStack(stack string) []StackFrame {
+	root, _ := os.Getwd()
+	var frames []StackFrame
+	lines := strings.Split(stack, "\n")
+	for i := 0; i < len(lines); i++ {
+		fn := strings.TrimSpace(lines[i])
+		if fn == "" || strings.HasPrefix(fn, "goroutine ") || strings.HasPrefix(lines[i], "\t") {
+			continue
+		}
+		frame := StackFrame{Function: fn}
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			i++
+			loc := strings.TrimSpace(lines[i])
+			if j := strings.LastIndex(loc, " +0x"); j >= 0 {
+				loc = loc[:j]
+			}
+			if j := strings.LastIndex(loc, ":"); j >= 0 {
+				frame.File = loc[:j]
+				frame.Line, _ = strconv.Atoi(loc[j+1:])
+			} else {
+				frame.File = loc
+			}
+		}
+		frame.Vendor = isVendorFrame(root, frame)
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// isVendorFrame 不在应用目录下（运行时、标准库、模块缓存）或位于 vendor 目录的帧视为依赖帧
+func isVendorFrame(root string, frame StackFrame) bool {
+	if frame.File == "" || strings.Contains(frame.File, "/vendor/") || strings.Contains(frame.File, "/pkg/mod/") {
+		return true
+	}
+	if root == "" {
+		return strings.HasPrefix(frame.Function, "runtime.") || strings.HasPrefix(frame.Function, "panic(")
+	}
+	return !strings.HasPrefix(frame.File, root+string(filepath.Separator))
+}
+
+// fileLink 输出文件位置，配置了编辑器时包装为可点击链接
+func fileLink(file string, line int) string {
+	text := html.EscapeString(fmt.Sprintf("%s:%d", file, line))
+	if href := EditorURL(file, line); href != "" {
+		return fmt.Sprintf(`<a class="editor-link" href="%s">%s</a>`, html.EscapeString(href), text)
+	}
+	return text
+}
+
+// formatStackFrames 渲染堆栈帧，依赖帧带 vendor 类名（默认隐藏，由页面开关展开），返回 HTML 与依赖帧数量
+func formatStackFrames(frames []StackFrame) (string, int) {
+	var b strings.Builder
+	vendor := 0
+	for _, f := range frames {
+		class := "stack-frame"
+		if f.Vendor {
+			class += " vendor"
+			vendor++
+		}
+		fmt.Fprintf(&b, `<div class="%s"><div class="stack-function">%s</div>`, class, html.EscapeString(f.Function))
+		if f.File != "" {
+			fmt.Fprintf(&b, `<div class="stack-location">%s</div>`, fileLink(f.File, f.Line))
+		}
+		b.WriteString("</div>\n")
+	}
+	return b.String(), vendor
+}
+
+// formatRequestContext 渲染请求上下文（方法、URL、路由参数、请求头、会话）
+func formatRequestContext(rc *RequestContext) string {
+	if rc == nil || rc.Request == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="error-section"><div class="section-title">🌐 请求上下文</div>`)
+	fmt.Fprintf(&b, `<div class="request-line"><span class="method">%s</span> %s</div>`,
+		html.EscapeString(rc.Request.Method), html.EscapeString(rc.Request.URL.String()))
+	writeKVTable(&b, "路由参数", rc.Params)
+	writeKVTable(&b, "请求头", rc.headers())
+	writeKVTable(&b, "会话", rc.Session)
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+// writeKVTable 输出键值表，为空时省略
+func writeKVTable(b *strings.Builder, title string, kvs []KV) {
+	if len(kvs) == 0 {
+		return
+	}
+	fmt.Fprintf(b, `<div class="kv-title">%s</div><table class="kv-table">`, html.EscapeString(title))
+	for _, kv := range kvs {
+		fmt.Fprintf(b, `<tr><td>%s</td><td>%s</td></tr>`, html.EscapeString(kv.Key), html.EscapeString(kv.Value))
+	}
+	b.WriteString(`</table>`)
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+)
+
+// codeRange 预留的错误码区间
+type codeRange struct {
+	owner    string
+	from, to int
+}
+
+var (
+	registryMu sync.Mutex
+	// codeStatus 应用注册的错误码对应的 HTTP 状态码
+	codeStatus = make(map[int]int)
+	// ranges 已预留的错误码区间，10000-19999 为框架保留
+	ranges = []codeRange{{owner: "framework", from: 10000, to: 19999}}
+)
+
+// RegisterRange 为模块预留错误码区间 [from, to]，与已预留区间重叠时 panic，用于在启动时发现多个模块的编码冲突：
+//
+//	func init() { errors.RegisterRange("order", 20000, 20999) }
+func RegisterRange(owner string, from, to int) {
+	if from > to {
+		panic(fmt.Sprintf("errors: 无效的错误码区间 %s [%d, %d]", owner, from, to))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, r := range ranges {
+		if from <= r.to && r.from <= to {
+			panic(fmt.Sprintf("errors: 错误码区间 %s [%d, %d] 与 %s [%d, %d] 重叠", owner, from, to, r.owner, r.from, r.to))
+		}
+	}
+	ranges = append(ranges, codeRange{owner: owner, from: from, to: to})
+}
+
+// RegisterCode 注册应用错误码的消息与 HTTP 状态码，注册后 New、HTTPStatus 与 ErrMsg 均可识别该错误码；
+// 消息同时以 "error.<code>" 注册为默认语言的翻译，其他语言可另行 i18n.Register。
+// 错误码已存在（内置或重复注册）、位于框架保留区间或状态码非法时 panic，须在启动阶段（init）调用：
+//
+//	const OutOfStock = 20001
+//	func init() { errors.RegisterCode(OutOfStock, "库存不足", http.StatusConflict) }
+//
+//	return errors.New(OutOfStock, "SKU-1 剩余 0 件", nil) // HTTP 409，"message": "库存不足"
+func RegisterCode(code int, message string, status int) {
+	if http.StatusText(status) == "" {
+		panic(fmt.Sprintf("errors: 错误码 %d 的 HTTP 状态码 %d 无效", code, status))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := ErrMsg[code]; ok {
+		panic(fmt.Sprintf("errors: 错误码 %d 已注册（%s）", code, ErrMsg[code]))
+	}
+	if r := ranges[0]; code >= r.from && code <= r.to {
+		panic(fmt.Sprintf("errors: 错误码 %d 位于框架保留区间 [%d, %d]", code, r.from, r.to))
+	}
+	key := "error." + strconv.Itoa(code)
+	ErrMsg[code] = message
+	MessageKeys[code] = key
+	codeStatus[code] = status
+	i18n.Register(i18n.DefaultLocale, map[string]string{key: message})
+}
+
+// registeredStatus 返回应用注册的错误码对应的 HTTP 状态码
+func registeredStatus(code int) (int, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	status, ok := codeStatus[code]
+	return status, ok
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+)
+
+func mustPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s 应 panic", name)
+		}
+	}()
+	fn()
+}
+
+// TestRegisterCode 注册后 New、HTTPStatus 与翻译均可识别，冲突时 panic
+func TestRegisterCode(t *testing.T) {
+	const outOfStock = 20001
+	RegisterCode(outOfStock, "库存不足", http.StatusConflict)
+	i18n.Register("en", map[string]string{"error.20001": "Out of stock"})
+
+	err := New(outOfStock, "SKU-1", nil)
+	if err.Message != "库存不足" || err.HTTPStatus() != http.StatusConflict {
+		t.Errorf("注册的错误码 %q %d", err.Message, err.HTTPStatus())
+	}
+	if got := err.Localize("en"); got != "Out of stock" {
+		t.Errorf("翻译 %q", got)
+	}
+
+	mustPanic(t, "重复注册", func() { RegisterCode(outOfStock, "x", http.StatusConflict) })
+	mustPanic(t, "与内置错误码冲突", func() { RegisterCode(NotFound, "x", http.StatusNotFound) })
+	mustPanic(t, "框架保留区间", func() { RegisterCode(10100, "x", http.StatusBadRequest) })
+	mustPanic(t, "非法状态码", func() { RegisterCode(20002, "x", 999) })
+}
+
+// TestRegisterRange 区间重叠时 panic
+func TestRegisterRange(t *testing.T) {
+	RegisterRange("order", 30000, 30999)
+	RegisterRange("payment", 31000, 31999)
+	mustPanic(t, "重叠区间", func() { RegisterRange("stock", 30500, 31500) })
+	mustPanic(t, "与框架区间重叠", func() { RegisterRange("bad", 9000, 10000) })
+	mustPanic(t, "无效区间", func() { RegisterRange("bad", 2, 1) })
+}
@@ -16,13 +16,18 @@ import (
 
 // CodeLine 代码行
 type CodeLine struct {
-	Number  int
-	Content string
-	IsError bool
+	Number  int    `json:"number"`
+	Content string `json:"content"`
+	IsError bool   `json:"is_error"`
 }
 
 // RenderError 渲染 HTTP 错误到浏览器（用于 Recovery 中间件）
 func RenderError(w http.ResponseWriter, err error, stack string, isDevelopment bool) {
+	RenderErrorWithContext(w, err, stack, isDevelopment, nil)
+}
+
+// RenderErrorWithContext 与 RenderError 相同，开发模式下在堆栈旁额外展示请求上下文（rc 可为 nil）
+func RenderErrorWithContext(w http.ResponseWriter, err error, stack string, isDevelopment bool, rc *RequestContext) {
 	// 若响应体已部分写出（如处理器先写了内容再 panic / 返回错误），再写状态码或 HTML
 	// 会触发 "superfluous WriteHeader" 并把错误页拼到已发送内容后造成页面错乱。
 	// 此时放弃错误页渲染（panic 与堆栈已由上层日志留痕）。
@@ -37,7 +42,7 @@ func RenderError(w http.ResponseWriter, err error, stack string, isDevelopment b
 	}
 
 	// 开发模式：显示详细错误信息
-	renderDevelopmentError(w, err, stack)
+	renderDevelopmentError(w, err, stack, rc)
 }
 
 // ExtractFileAndLine 从错误中提取文件和行号
@@ -212,38 +217,8 @@ func extractFromTemplateError(err error) (string, int) {
 	return "", 0
 }
 
-// formatStackTrace 格式化堆栈跟踪，使其更易读
-func formatStackTrace(stack string) string {
-	lines := strings.Split(stack, "\n")
-	var formatted strings.Builder
-
-	for i := range len(lines) {
-		line := lines[i]
-		trimmed := strings.TrimSpace(line)
-
-		if trimmed == "" {
-			continue
-		}
-
-		// 函数调用行（不以/开头，不以+开头）
-		if !strings.HasPrefix(trimmed, "/") && !strings.HasPrefix(trimmed, "+") && trimmed != "" {
-			formatted.WriteString(`<div class="stack-function">`)
-			formatted.WriteString(html.EscapeString(trimmed))
-			formatted.WriteString("</div>\n")
-		} else if strings.HasPrefix(trimmed, "/") || strings.Contains(trimmed, ".go:") {
-			// 文件位置行
-			formatted.WriteString(`<div class="stack-location">`)
-			formatted.WriteString(`  `)
-			formatted.WriteString(html.EscapeString(trimmed))
-			formatted.WriteString("</div>\n")
-		}
-	}
-
-	return formatted.String()
-}
-
 // renderDevelopmentError 渲染开发模式错误页面
-func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
+func renderDevelopmentError(w http.ResponseWriter, err error, stack string, rc *RequestContext) {
 	// 解析错误信息
 	errorType := "Runtime Error"
 	errorMessage := err.Error()
@@ -263,8 +238,24 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
 		codeContext = ReadCodeContext(fileName, line, 5)
 	}
 
-	// 格式化堆栈跟踪
-	formattedStack := formatStackTrace(stack)
+	frames := ParseStack(stack)
+
+	// ?format=json：输出结构化错误供工具读取
+	if rc.wantsJSON() {
+		writeDevErrorJSON(w, &DevError{
+			Type:    errorType,
+			Message: errorMessage,
+			File:    fileName,
+			Line:    line,
+			Code:    codeContext,
+			Stack:   frames,
+			Request: rc.info(),
+		})
+		return
+	}
+
+	// 格式化堆栈跟踪（依赖帧默认折叠）
+	formattedStack, vendorFrames := formatStackFrames(frames)
 
 	// 构建代码上下文的 HTML
 	codeContextHTML := ""
@@ -299,21 +290,57 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>Application Error - Development Mode</title>
     <style>
+        /* 默认深色，系统偏好浅色时切换为浅色主题 */
+        :root {
+            color-scheme: dark light;
+            --bg: #1e1e1e;
+            --surface: #252526;
+            --panel: #2d2d30;
+            --border: #3e3e42;
+            --border-strong: #4e4e52;
+            --text: #d4d4d4;
+            --muted: #858585;
+            --accent: #4ec9b0;
+            --link: #9cdcfe;
+            --function: #dcdcaa;
+            --string: #ce9178;
+            --warning: #ffa500;
+            --shadow: rgba(0, 0, 0, 0.5);
+            --hover: rgba(255, 255, 255, 0.05);
+        }
+        @media (prefers-color-scheme: light) {
+            :root {
+                --bg: #f5f6f8;
+                --surface: #ffffff;
+                --panel: #f3f4f6;
+                --border: #e1e4e8;
+                --border-strong: #c8ccd1;
+                --text: #24292e;
+                --muted: #6a737d;
+                --accent: #0e7c6b;
+                --link: #0451a5;
+                --function: #795e26;
+                --string: #a31515;
+                --warning: #b26a00;
+                --shadow: rgba(0, 0, 0, 0.1);
+                --hover: rgba(0, 0, 0, 0.04);
+            }
+        }
         * { margin: 0; padding: 0; box-sizing: border-box; }
         body {
             font-family: 'Segoe UI', -apple-system, BlinkMacSystemFont, 'Microsoft YaHei', sans-serif;
-            background: #1e1e1e;
-            color: #d4d4d4;
+            background: var(--bg);
+            color: var(--text);
             padding: 20px;
             line-height: 1.6;
         }
         .error-container {
             max-width: 1200px;
             margin: 0 auto;
-            background: #252526;
+            background: var(--surface);
             border-radius: 8px;
             overflow: hidden;
-            box-shadow: 0 4px 20px rgba(0, 0, 0, 0.5);
+            box-shadow: 0 4px 20px var(--shadow);
         }
         .error-header {
             background: linear-gradient(135deg, #f14c4c 0%%, #c92a2a 100%%);
@@ -340,25 +367,25 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
             margin-bottom: 25px;
         }
         .section-title {
-            color: #4ec9b0;
+            color: var(--accent);
             font-size: 16px;
             font-weight: 600;
             margin-bottom: 10px;
             padding-bottom: 8px;
-            border-bottom: 2px solid #3e3e42;
+            border-bottom: 2px solid var(--border);
         }
         .error-message {
-            background: #2d2d30;
+            background: var(--panel);
             padding: 15px;
             border-radius: 5px;
             border-left: 4px solid #f14c4c;
-            color: #ce9178;
+            color: var(--string);
             font-family: 'Consolas', 'Monaco', monospace;
             font-size: 14px;
             word-break: break-word;
         }
         .stack-trace {
-            background: #1e1e1e;
+            background: var(--bg);
             padding: 15px;
             border-radius: 5px;
             overflow-x: auto;
@@ -373,30 +400,86 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
             height: 10px;
         }
         .stack-trace::-webkit-scrollbar-track {
-            background: #2d2d30;
+            background: var(--panel);
         }
         .stack-trace::-webkit-scrollbar-thumb {
-            background: #3e3e42;
+            background: var(--border);
             border-radius: 5px;
         }
         .stack-trace::-webkit-scrollbar-thumb:hover {
-            background: #4e4e52;
+            background: var(--border-strong);
         }
         .stack-function {
-            color: #dcdcaa;
+            color: var(--function);
             margin-top: 10px;
             padding: 4px 0;
             font-weight: 500;
         }
         .stack-location {
-            color: #858585;
+            color: var(--muted);
             padding-left: 20px;
             font-size: 11px;
             margin: 2px 0;
         }
         .stack-location:hover {
-            color: #9cdcfe;
-            background: rgba(255, 255, 255, 0.05);
+            color: var(--link);
+            background: var(--hover);
+        }
+        .stack-frame.vendor {
+            display: none;
+            opacity: 0.6;
+        }
+        #show-vendor:checked ~ .stack-trace .stack-frame.vendor {
+            display: block;
+        }
+        .vendor-toggle {
+            display: inline-block;
+            color: var(--muted);
+            font-size: 12px;
+            margin-bottom: 8px;
+            cursor: pointer;
+            user-select: none;
+        }
+        .editor-link {
+            color: inherit;
+            text-decoration: none;
+        }
+        .editor-link:hover {
+            color: var(--link);
+            text-decoration: underline;
+        }
+        .request-line {
+            font-family: 'Consolas', 'Monaco', monospace;
+            font-size: 14px;
+            color: var(--function);
+            margin-bottom: 10px;
+            word-break: break-all;
+        }
+        .request-line .method {
+            color: var(--accent);
+            font-weight: 600;
+        }
+        .kv-title {
+            color: var(--muted);
+            font-size: 12px;
+            text-transform: uppercase;
+            margin: 12px 0 6px;
+        }
+        .kv-table {
+            width: 100%%;
+            border-collapse: collapse;
+            font-family: 'Consolas', 'Monaco', monospace;
+            font-size: 12px;
+        }
+        .kv-table td {
+            padding: 4px 10px;
+            border-bottom: 1px solid var(--border);
+            vertical-align: top;
+            word-break: break-all;
+        }
+        .kv-table td:first-child {
+            color: var(--link);
+            width: 30%%;
         }
         .badge {
             display: inline-block;
@@ -409,25 +492,25 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
             margin-bottom: 15px;
         }
         .help-text {
-            background: #2d2d30;
+            background: var(--panel);
             padding: 15px;
             border-radius: 5px;
-            border-left: 4px solid #4ec9b0;
-            color: #9cdcfe;
+            border-left: 4px solid var(--accent);
+            color: var(--link);
             font-size: 13px;
         }
         .help-text strong {
-            color: #4ec9b0;
+            color: var(--accent);
         }
         .file-location {
-            background: #2d2d30;
+            background: var(--panel);
             padding: 15px;
             border-radius: 5px;
-            border-left: 4px solid #ffa500;
+            border-left: 4px solid var(--warning);
             margin-bottom: 15px;
         }
         .file-location .label {
-            color: #ffa500;
+            color: var(--warning);
             font-weight: 600;
             font-size: 12px;
             text-transform: uppercase;
@@ -436,11 +519,11 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
         .file-location .path {
             font-family: 'Consolas', 'Monaco', monospace;
             font-size: 14px;
-            color: #dcdcaa;
+            color: var(--function);
             word-break: break-all;
         }
         .code-context {
-            background: #1e1e1e;
+            background: var(--bg);
             border-radius: 5px;
             overflow: hidden;
             font-family: 'Consolas', 'Monaco', monospace;
@@ -453,7 +536,7 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
             border-left: 3px solid transparent;
         }
         .code-line:hover {
-            background: #2d2d30;
+            background: var(--panel);
         }
         .code-line.error-line {
             background: rgba(255, 76, 76, 0.15);
@@ -464,7 +547,7 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
             font-weight: bold;
         }
         .line-number {
-            color: #858585;
+            color: var(--muted);
             padding: 0 15px;
             text-align: right;
             user-select: none;
@@ -473,7 +556,7 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
         .line-content {
             flex: 1;
             padding-right: 15px;
-            color: #d4d4d4;
+            color: var(--text);
             white-space: pre;
         }
     </style>
@@ -500,9 +583,12 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
 
             <div class="error-section">
                 <div class="section-title">🔍 完整堆栈跟踪</div>
+                %s
                 <div class="stack-trace">%s</div>
             </div>
 
+            %s
+
             <div class="error-section">
                 <div class="help-text">
                     <strong>💡 提示:</strong> 此错误页面仅在开发模式下显示。 生产模式不可见。
@@ -519,12 +605,20 @@ func renderDevelopmentError(w http.ResponseWriter, err error, stack string) {
 				return fmt.Sprintf(`<div class="file-location">
 					<div class="label">📂 错误位置</div>
 					<div class="path">%s</div>
-				</div>`, html.EscapeString(lineInfo))
+				</div>`, fileLink(fileName, line))
 			}
 			return ""
 		}(),
 		codeContextHTML,
+		func() string {
+			if vendorFrames == 0 {
+				return ""
+			}
+			return fmt.Sprintf(`<input type="checkbox" id="show-vendor" hidden>
+				<label class="vendor-toggle" for="show-vendor">▸ 显示/隐藏 %d 个运行时与依赖帧</label>`, vendorFrames)
+		}(),
 		formattedStack,
+		formatRequestContext(rc),
 	)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// TestMain 切换到仓库根目录，使 config.MustFetch() 能定位到 config/config.yaml
+// （开发错误页提取文件位置时依赖全局配置）。
+func TestMain(m *testing.M) {
+	dir, _ := os.Getwd()
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "config", "config.yaml")); err == nil {
+			_ = os.Chdir(dir)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	os.Exit(m.Run())
+}
+
+// TestEditorURL 内置编辑器、自定义模板与关闭链接
+func TestEditorURL(t *testing.T) {
+	defer SetEditor(DefaultEditor)
+
+	if got := EditorURL("/app/main.go", 12); got != "vscode://file//app/main.go:12" {
+		t.Errorf("默认编辑器: %q", got)
+	}
+	SetEditor("goland")
+	if got := EditorURL("/app/my file.go", 3); got != "goland://open?file=/app/my%20file.go&line=3" {
+		t.Errorf("goland: %q", got)
+	}
+	SetEditor("nvim://open?file={file}&line={line}")
+	if got := EditorURL("/app/main.go", 7); got != "nvim://open?file=/app/main.go&line=7" {
+		t.Errorf("自定义模板: %q", got)
+	}
+	for _, editor := range []string{"", "none", "notepad"} {
+		SetEditor(editor)
+		if got := EditorURL("/app/main.go", 1); got != "" {
+			t.Errorf("%q 不应生成链接: %q", editor, got)
+		}
+	}
+}
+
+// TestParseStack 解析函数与位置，应用目录外的帧标记为依赖帧
+func TestParseStack(t *testing.T) {
+	root, _ := os.Getwd()
+	stack := fmt.Sprintf(`goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+github.com/gin-gonic/gin.(*Context).Next(...)
+	/root/go/pkg/mod/github.com/gin-gonic/gin@v1.10.1/context.go:185
+app/controller.(*Home).Index(0xc000010000)
+	%s/app/controller/home.go:42 +0x1f
+`, root)
+
+	frames := ParseStack(stack)
+	if len(frames) != 3 {
+		t.Fatalf("期望 3 帧，得到 %d: %+v", len(frames), frames)
+	}
+	if f := frames[0]; f.Function != "runtime/debug.Stack()" || f.Line != 26 || !f.Vendor {
+		t.Errorf("运行时帧: %+v", f)
+	}
+	if f := frames[1]; f.Line != 185 || !f.Vendor {
+		t.Errorf("依赖帧: %+v", f)
+	}
+	if f := frames[2]; f.File != root+"/app/controller/home.go" || f.Line != 42 || f.Vendor {
+		t.Errorf("应用帧: %+v", f)
+	}
+}
+
+// TestRenderErrorWithContext 开发错误页包含编辑器链接、折叠开关与请求上下文，敏感请求头被掩码
+func TestRenderErrorWithContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/users/7?tab=posts", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("X-Trace", "abc")
+	rc := &RequestContext{
+		Request: req,
+		Params:  []KV{{Key: "id", Value: "7"}},
+		Session: []KV{{Key: "user_id", Value: "42"}},
+	}
+
+	w := httptest.NewRecorder()
+	RenderErrorWithContext(w, fmt.Errorf("boom"), string(debug.Stack()), true, rc)
+	body := w.Body.String()
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("状态码 %d", w.Code)
+	}
+	for _, want := range []string{
+		`href="vscode://file/`,
+		`id="show-vendor"`,
+		`class="stack-frame vendor"`,
+		`<span class="method">POST</span> /users/7?tab=posts`,
+		`<td>id</td><td>7</td>`,
+		`<td>X-Trace</td><td>abc</td>`,
+		`<td>user_id</td><td>42</td>`,
+		`<td>Authorization</td><td>******</td>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("错误页缺少 %q", want)
+		}
+	}
+	if strings.Contains(body, "secret-token") {
+		t.Error("敏感请求头不应输出")
+	}
+
+	// 生产模式不输出任何请求上下文
+	w = httptest.NewRecorder()
+	RenderErrorWithContext(w, fmt.Errorf("boom"), "", false, rc)
+	if strings.Contains(w.Body.String(), "请求上下文") {
+		t.Error("生产模式不应展示请求上下文")
+	}
+}
+
+// TestRenderErrorJSON ?format=json 时输出结构化错误，HTML 版本支持浅色主题
+func TestRenderErrorJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/boom?format=json", nil)
+	req.Header.Set("Cookie", "session=secret")
+
+	w := httptest.NewRecorder()
+	RenderErrorWithContext(w, fmt.Errorf("boom"), string(debug.Stack()), true, &RequestContext{Request: req})
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("Content-Type %q", ct)
+	}
+	var got DevError
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "boom" || got.File == "" || got.Line == 0 || len(got.Code) == 0 || len(got.Stack) == 0 {
+		t.Errorf("结构化错误不完整: %+v", got)
+	}
+	if got.Request == nil || got.Request.URL != "/boom?format=json" {
+		t.Fatalf("请求上下文: %+v", got.Request)
+	}
+	for _, h := range got.Request.Headers {
+		if h.Key == "Cookie" && h.Value != "******" {
+			t.Errorf("敏感请求头不应输出: %q", h.Value)
+		}
+	}
+
+	// 无 format 参数时输出 HTML
+	w = httptest.NewRecorder()
+	RenderErrorWithContext(w, fmt.Errorf("boom"), "", true, &RequestContext{Request: httptest.NewRequest(http.MethodGet, "/boom", nil)})
+	if !strings.Contains(w.Body.String(), "prefers-color-scheme: light") {
+		t.Error("HTML 错误页应适配浅色主题")
+	}
+}
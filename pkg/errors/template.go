@@ -81,6 +81,9 @@ func NewParseError(templateName string, cause error) *TemplateError {
 
 // NewRenderError 创建渲染错误
 func NewRenderError(templateName string, cause error) *TemplateError {
+	if missing := NewMissingKeyError(templateName, cause); missing != nil {
+		return missing
+	}
 	renderErr := NewTemplateError("RENDER_ERROR", "模板渲染失败", templateName, cause)
 
 	// 尝试从错误信息中提取文件名和行号
@@ -93,6 +96,47 @@ func NewRenderError(templateName string, cause error) *TemplateError {
 	return renderErr
 }
 
+// 模板执行时访问不存在的变量产生的错误，如:
+// template: hello.html:3:12: executing "content" at <.User.Nmae>: can't evaluate field Nmae in type *model.User
+// template: hello.html:3:12: executing "content" at <.Titel>: map has no entry for key "Titel"
+var (
+	missingKeyRe   = regexp.MustCompile(`at <([^>]*)>: map has no entry for key "([^"]*)"`)
+	missingFieldRe = regexp.MustCompile(`at <([^>]*)>: can't evaluate field (\w+) in type (\S+)`)
+)
+
+// NewMissingKeyError 将访问不存在变量的执行错误转换为 MISSING_KEY 错误，消息指出变量名，
+// 文件名与行号取最内层的模板（include 的局部模板出错时指向局部模板）；cause 不是此类错误时返回 nil
+func NewMissingKeyError(templateName string, cause error) *TemplateError {
+	if cause == nil {
+		return nil
+	}
+	msg := cause.Error()
+	var message string
+	if m := lastSubmatch(missingKeyRe, msg); m != nil {
+		message = fmt.Sprintf("模板变量 %s 不存在（数据中没有键 %q）", m[1], m[2])
+	} else if m := lastSubmatch(missingFieldRe, msg); m != nil {
+		message = fmt.Sprintf("模板变量 %s 不存在（类型 %s 没有字段 %s）", m[1], m[3], m[2])
+	} else {
+		return nil
+	}
+
+	te := NewTemplateError("MISSING_KEY", message, templateName, cause)
+	if m := lastSubmatch(getTemplateErrorRegex(), msg); m != nil {
+		te.FileName = resolveTemplateFilePath(m[1])
+		te.LineNumber, _ = strconv.Atoi(m[2])
+	}
+	return te
+}
+
+// lastSubmatch 返回最后一处匹配的分组，未匹配时返回 nil
+func lastSubmatch(re *regexp.Regexp, s string) []string {
+	all := re.FindAllStringSubmatch(s, -1)
+	if len(all) == 0 {
+		return nil
+	}
+	return all[len(all)-1]
+}
+
 // NewNotFoundError 创建未找到错误
 func NewNotFoundError(templateName string) *TemplateError {
 	return NewTemplateError("NOT_FOUND", "模板文件未找到", templateName, ErrTemplateNotFound)
@@ -112,7 +156,7 @@ func IsTemplateError(err error) bool {
 }
 
 // IsTemplateErrorType 检查是否为特定类型的模板错误
-// errorType 可以是: "NOT_FOUND", "PARSE_ERROR", "RENDER_ERROR", "BLOCK_NOT_FOUND", "VALIDATION_ERROR"
+// errorType 可以是: "NOT_FOUND", "PARSE_ERROR", "RENDER_ERROR", "MISSING_KEY", "BLOCK_NOT_FOUND", "VALIDATION_ERROR"
 func IsTemplateErrorType(err error, errorType string) bool {
 	if te, ok := err.(*TemplateError); ok {
 		return te.Type == errorType
@@ -148,6 +192,11 @@ func IsTemplateRenderError(err error) bool {
 	return IsTemplateErrorType(err, "RENDER_ERROR")
 }
 
+// IsTemplateMissingKeyError 检查是否为访问不存在变量的模板错误
+func IsTemplateMissingKeyError(err error) bool {
+	return IsTemplateErrorType(err, "MISSING_KEY")
+}
+
 // ValidateTemplateName 验证模板名称
 func ValidateTemplateName(name string) error {
 	if name == "" {
@@ -1,112 +1,458 @@
 package eventbus
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
 )
 
 // EventHandler 事件处理函数类型
 type EventHandler func(args ...interface{})
 
+// ContextHandler 支持上下文与错误返回的事件处理函数类型（通过 OnE/OnceE 注册）
+// 处理函数应在 ctx 取消或超时时尽快返回；返回的错误由 EmitCtx 汇总并交给错误处理器上报。
+type ContextHandler func(ctx context.Context, args ...interface{}) error
+
+// ErrorHandler 事件处理失败时的回调，默认写入日志
+type ErrorHandler func(event string, err error)
+
+// ErrStopPropagation ContextHandler 返回该错误（或包装了它的错误）时，后续监听器不再执行。
+// 用于 "user.deleting" 这类可被否决的前置事件：
+//
+//	eventbus.OnE("user.deleting", func(ctx context.Context, args ...interface{}) error {
+//	    if args[0].(*User).IsAdmin {
+//	        return eventbus.Veto("管理员账号不可删除")
+//	    }
+//	    return nil
+//	}, eventbus.WithPriority(100))
+//
+//	if err := eventbus.EmitCtx(ctx, "user.deleting", user); eventbus.IsStopped(err) {
+//	    return errors.NewForbidden(err.Error(), err)
+//	}
+var ErrStopPropagation = errors.New("事件传播已停止")
+
+// PanicError 处理函数 panic 时 EmitCtx 返回（并上报）的错误，其余处理函数照常执行
+type PanicError struct {
+	Value any    // recover() 得到的值
+	Stack []byte // panic 时的调用栈
+}
+
+// Error 实现 error
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("处理函数 panic: %v", e.Value)
+}
+
+// Veto 返回一个携带原因的停止传播错误
+func Veto(reason string) error {
+	return fmt.Errorf("%w: %s", ErrStopPropagation, reason)
+}
+
+// IsStopped 判断 EmitCtx 的结果是否因监听器停止传播而提前结束
+func IsStopped(err error) bool {
+	return errors.Is(err, ErrStopPropagation)
+}
+
+// EventMeta 通配订阅收到事件时附带的元信息，作为处理函数的第一个参数传入
+//
+//	eventbus.On("user.*", func(args ...interface{}) {
+//	    meta := args[0].(eventbus.EventMeta) // meta.Name == "user.created"
+//	})
+type EventMeta struct {
+	Name      string // 实际触发的事件名
+	Pattern   string // 命中的订阅模式
+	RequestID string // 触发事件的请求 ID（EmitCtx 传入的上下文携带时）
+}
+
+// eventMetaKey 上下文中保存 EventMeta 的键
+type eventMetaKey struct{}
+
+// EventFromContext 从 ContextHandler 收到的 ctx 中获取当前事件的元信息
+func EventFromContext(ctx context.Context) (EventMeta, bool) {
+	meta, ok := ctx.Value(eventMetaKey{}).(EventMeta)
+	return meta, ok
+}
+
 // handlerEntry 内部处理函数条目，区分普通和 once 监听器
 type handlerEntry struct {
-	handler EventHandler
-	once    bool
-	called  bool // once 监听器是否已执行
+	handler    EventHandler
+	ctxHandler ContextHandler
+	timeout    time.Duration // 仅对 ctxHandler 生效，0 表示不限时
+	priority   int           // 优先级，数值越大越先执行
+	once       bool
+	called     bool   // once 监听器是否已被认领（执行中或已执行）
+	seq        uint64 // 注册序号，同优先级下按注册顺序执行
+}
+
+// ListenerOption 监听器注册选项
+type ListenerOption func(*handlerEntry)
+
+// WithTimeout 为 ContextHandler 设置单次执行超时，超时后 ctx 被取消且该次执行记为失败
+func WithTimeout(d time.Duration) ListenerOption {
+	return func(e *handlerEntry) { e.timeout = d }
+}
+
+// WithPriority 设置监听器优先级（默认 0），数值越大越先执行，同优先级按注册顺序执行
+func WithPriority(p int) ListenerOption {
+	return func(e *handlerEntry) { e.priority = p }
 }
 
 // EventBus 事件总线结构体
 type EventBus struct {
-	mu        sync.RWMutex
-	listeners map[string][]*handlerEntry
+	mu           sync.RWMutex
+	listeners    map[string][]*handlerEntry
+	wildcards    map[string]bool // 含通配符的订阅模式
+	seq          uint64
+	errorHandler ErrorHandler
 }
 
 // New 创建新的事件总线实例
 func New() *EventBus {
 	return &EventBus{
 		listeners: make(map[string][]*handlerEntry),
+		wildcards: make(map[string]bool),
 	}
 }
 
 // On 注册事件监听器
-func (eb *EventBus) On(event string, handler EventHandler) {
+//
+// event 支持以 "." 分隔的命名空间通配：
+//   - "*"  匹配单个层级，如 "user.*" 匹配 "user.created"，不匹配 "user.profile.updated"
+//   - "**" 匹配零或多个层级，如 "user.**" 匹配 "user" 下任意深度的事件，"**" 匹配全部事件
+//
+// 通配订阅的处理函数第一个参数为 EventMeta，其后才是 Emit 传入的参数。
+// 可通过 WithPriority 调整执行顺序。返回的 Subscription 可精确移除该监听器。
+func (eb *EventBus) On(event string, handler EventHandler, opts ...ListenerOption) *Subscription {
+	return eb.add(event, &handlerEntry{handler: handler}, opts)
+}
+
+// Once 注册一次性事件监听器（触发后自动移除），同样支持通配模式
+func (eb *EventBus) Once(event string, handler EventHandler, opts ...ListenerOption) *Subscription {
+	return eb.add(event, &handlerEntry{handler: handler, once: true}, opts)
+}
+
+// OnE 注册支持上下文与错误返回的事件监听器，通配规则同 On。
+// 与 On 不同，通配订阅不会在参数前插入 EventMeta，可通过 EventFromContext(ctx) 获取。
+//
+//	eb.OnE("order.paid", func(ctx context.Context, args ...interface{}) error {
+//	    return notifyWarehouse(ctx, args[0].(*Order))
+//	}, eventbus.WithTimeout(3*time.Second))
+func (eb *EventBus) OnE(event string, handler ContextHandler, opts ...ListenerOption) *Subscription {
+	return eb.add(event, &handlerEntry{ctxHandler: handler}, opts)
+}
+
+// OnceE 注册一次性的 ContextHandler
+func (eb *EventBus) OnceE(event string, handler ContextHandler, opts ...ListenerOption) *Subscription {
+	return eb.add(event, &handlerEntry{ctxHandler: handler, once: true}, opts)
+}
+
+// SetErrorHandler 设置处理失败的上报回调，传 nil 恢复为默认的日志上报
+func (eb *EventBus) SetErrorHandler(h ErrorHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.errorHandler = h
+}
+
+// Subscription 一次监听器注册的句柄，由 On/Once/OnE/OnceE 返回。
+// 以条目身份而非函数指针定位监听器，匿名闭包、同一函数的多次注册都能被准确移除：
+//
+//	sub := eventbus.On("user.created", func(args ...interface{}) { ... })
+//	defer sub.Unsubscribe()
+type Subscription struct {
+	bus   *EventBus
+	event string
+	entry *handlerEntry
+}
+
+// Event 返回订阅的事件名（或通配模式）
+func (s *Subscription) Event() string {
+	return s.event
+}
+
+// Unsubscribe 移除该监听器，可重复调用；once 监听器已执行过时无操作
+func (s *Subscription) Unsubscribe() {
+	s.bus.remove(s.event, s.entry)
+}
+
+// add 注册监听器条目（内部方法）
+func (eb *EventBus) add(event string, entry *handlerEntry, opts []ListenerOption) *Subscription {
+	for _, o := range opts {
+		o(entry)
+	}
+
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
-	eb.listeners[event] = append(eb.listeners[event], &handlerEntry{handler: handler})
+	eb.seq++
+	entry.seq = eb.seq
+	eb.listeners[event] = append(eb.listeners[event], entry)
+	if isPattern(event) {
+		eb.wildcards[event] = true
+	}
+	return &Subscription{bus: eb, event: event, entry: entry}
 }
 
-// Once 注册一次性事件监听器（触发后自动移除）
-func (eb *EventBus) Once(event string, handler EventHandler) {
+// remove 移除 event 下的指定条目
+func (eb *EventBus) remove(event string, target *handlerEntry) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
-	eb.listeners[event] = append(eb.listeners[event], &handlerEntry{handler: handler, once: true})
+	eb.setEntries(event, without(eb.listeners[event], func(e *handlerEntry) bool { return e == target }))
 }
 
-// Emit 触发事件
-//
-// 在锁内完成两件事：认领待执行的处理函数、移除已认领的 once 监听器；
-// 随后在锁外执行处理函数，避免 handler 内部再调用 On/Off/Emit 造成死锁。
-// once 监听器通过 called 标志在锁的保护下"认领"，保证并发 Emit 下也只执行一次。
+// without 返回剔除满足条件的条目后的新列表，不修改原列表（原列表可能正被其他 Emit 的快照引用）
+func without(entries []*handlerEntry, drop func(*handlerEntry) bool) []*handlerEntry {
+	kept := make([]*handlerEntry, 0, len(entries))
+	for _, e := range entries {
+		if !drop(e) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// pendingCall 一次 Emit 中待执行的处理函数
+type pendingCall struct {
+	entry   *handlerEntry
+	pattern string // 通配订阅命中的模式，精确订阅为空
+}
+
+// Emit 触发事件，处理失败会交给错误处理器上报但不返回给调用方（需要结果时使用 EmitCtx）
 func (eb *EventBus) Emit(event string, args ...interface{}) {
+	_ = eb.EmitCtx(context.Background(), event, args...)
+}
+
+// EmitCtx 携带上下文触发事件，返回所有 ContextHandler 失败的汇总错误（errors.Join）。
+//
+// 在锁内认领待执行的处理函数（含命中的通配订阅），随后在锁外按优先级（高→低）与注册顺序执行，
+// 避免 handler 内部再调用 On/Off/Emit 造成死锁。
+// 某个处理函数返回 ErrStopPropagation 时停止执行后续处理函数，该错误原样包含在结果中（不视为失败上报）。
+// once 监听器通过 called 标志在锁的保护下"认领"，保证并发 Emit 下也只执行一次；执行后才从监听器列表中移除，
+// 因拦截或 ctx 取消而未执行的 once 监听器释放认领，留待下一次 Emit。
+// ctx 被取消后不再执行剩余处理函数，并在结果中附带 ctx.Err()。
+// 任一处理函数（含 EventHandler）panic 时恢复为 *PanicError，连同调用栈写入日志并上报，其余处理函数照常执行。
+func (eb *EventBus) EmitCtx(ctx context.Context, event string, args ...interface{}) error {
 	eb.mu.Lock()
-	entries := eb.listeners[event]
+	toRun := eb.claim(event, "")
+	for pattern := range eb.wildcards {
+		if matchPattern(pattern, event) {
+			toRun = append(toRun, eb.claim(pattern, pattern)...)
+		}
+	}
+	custom := eb.errorHandler
+	eb.mu.Unlock()
+
+	if len(toRun) == 0 {
+		return nil
+	}
+	sort.Slice(toRun, func(i, j int) bool {
+		a, b := toRun[i].entry, toRun[j].entry
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		return a.seq < b.seq
+	})
+	report := custom
+	if report == nil {
+		report = logError
+	}
+
+	// 锁外执行处理函数，ran 为已执行的数量
+	var errs []error
+	ran := 0
+	defer func() { eb.settleOnce(event, toRun, ran) }()
+	for _, call := range toRun {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		err := call.invoke(ctx, event, args)
+		ran++
+		if err == nil {
+			continue
+		}
+		if IsStopped(err) {
+			errs = append(errs, err)
+			break
+		}
+		var panicErr *PanicError
+		isPanic := errors.As(err, &panicErr)
+		if isPanic {
+			logPanic(event, panicErr)
+		}
+		err = fmt.Errorf("事件 %s 处理失败: %w", event, err)
+		// 默认上报即写日志，panic 已带调用栈记录过，不再重复
+		if !isPanic || custom != nil {
+			report(event, err)
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// invoke 执行单个处理函数，处理函数 panic 时恢复并返回 *PanicError，不影响触发事件的协程
+func (call pendingCall) invoke(ctx context.Context, event string, args []interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	meta := EventMeta{Name: event, Pattern: call.pattern, RequestID: request.RequestIDFromContext(ctx)}
+	entry := call.entry
+
+	if entry.ctxHandler == nil {
+		if call.pattern != "" {
+			args = append([]interface{}{meta}, args...)
+		}
+		entry.handler(args...)
+		return nil
+	}
+
+	ctx = context.WithValue(ctx, eventMetaKey{}, meta)
+	if entry.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, entry.timeout)
+		defer cancel()
+	}
+
+	err = entry.ctxHandler(ctx, args...)
+	if err == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// logError 默认错误处理器：写入日志（日志未初始化时忽略）
+func logError(event string, err error) {
+	if logger.SugarLogger != nil {
+		logger.SugarLogger.Errorw("事件处理失败", "event", event, "error", err)
+	}
+}
+
+// logPanic 记录处理函数 panic 及调用栈（日志未初始化时忽略）
+func logPanic(event string, err *PanicError) {
+	if logger.SugarLogger != nil {
+		logger.SugarLogger.Errorw("事件处理函数 panic", "event", event, "panic", err.Value, "stack", string(err.Stack))
+	}
+}
+
+// claim 认领 key 下待执行的监听器，调用方需持有写锁。
+// once 监听器只能被认领一次，已被其他 Emit 认领的跳过；认领后仍保留在列表中，执行后由 settleOnce 移除
+func (eb *EventBus) claim(key, pattern string) []pendingCall {
+	entries := eb.listeners[key]
 	if len(entries) == 0 {
-		eb.mu.Unlock()
-		return
+		return nil
 	}
 
-	toRun := make([]EventHandler, 0, len(entries))
-	var remaining []*handlerEntry
+	toRun := make([]pendingCall, 0, len(entries))
 	for _, entry := range entries {
 		if entry.once {
-			// once 监听器只能被认领一次；已被其他 Emit 认领则跳过，且不保留
 			if entry.called {
 				continue
 			}
 			entry.called = true
-			toRun = append(toRun, entry.handler)
-			continue
 		}
-		toRun = append(toRun, entry.handler)
-		remaining = append(remaining, entry)
+		toRun = append(toRun, pendingCall{entry: entry, pattern: pattern})
 	}
+	return toRun
+}
 
-	// 更新监听器列表：移除已认领的 once 监听器
-	if len(remaining) != len(entries) {
-		if len(remaining) == 0 {
-			delete(eb.listeners, event)
-		} else {
-			eb.listeners[event] = remaining
+// settleOnce 移除前 ran 个处理函数中已执行的 once 监听器，之后未执行的释放认领
+func (eb *EventBus) settleOnce(event string, toRun []pendingCall, ran int) {
+	locked := false
+	for i, call := range toRun {
+		if !call.entry.once {
+			continue
 		}
+		if !locked {
+			eb.mu.Lock()
+			defer eb.mu.Unlock()
+			locked = true
+		}
+		if i >= ran {
+			call.entry.called = false
+			continue
+		}
+		key := event
+		if call.pattern != "" {
+			key = call.pattern
+		}
+		eb.setEntries(key, without(eb.listeners[key], func(e *handlerEntry) bool { return e == call.entry }))
 	}
-	eb.mu.Unlock()
+}
+
+// setEntries 更新 key 下的监听器列表，列表为空时一并清理通配索引，调用方需持有写锁
+func (eb *EventBus) setEntries(key string, entries []*handlerEntry) {
+	if len(entries) == 0 {
+		delete(eb.listeners, key)
+		delete(eb.wildcards, key)
+		return
+	}
+	eb.listeners[key] = entries
+}
+
+// isPattern 判断事件名是否包含通配符
+func isPattern(event string) bool {
+	return strings.Contains(event, "*")
+}
+
+// matchPattern 判断事件名是否匹配通配模式（按 "." 分段匹配）
+func matchPattern(pattern, event string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(event, "."))
+}
 
-	// 锁外执行处理函数
-	for _, handler := range toRun {
-		handler(args...)
+func matchSegments(pattern, event []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "**":
+			// ** 可吞掉零或多个层级
+			for i := 0; i <= len(event); i++ {
+				if matchSegments(pattern[1:], event[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(event) == 0 {
+				return false
+			}
+		default:
+			if len(event) == 0 || pattern[0] != event[0] {
+				return false
+			}
+		}
+		pattern, event = pattern[1:], event[1:]
 	}
+	return len(event) == 0
 }
 
-// Off 移除事件监听器
+// Off 移除事件监听器，不传 handler 时移除该事件的全部监听器。
+//
+// 按函数指针匹配 EventHandler：同一函数字面量创建的多个闭包指针相同会被一并移除，ContextHandler 无法以此移除。
+// 需要精确移除单个监听器时使用注册返回的 Subscription.Unsubscribe。
 func (eb *EventBus) Off(event string, handler ...EventHandler) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
 	if len(handler) == 0 {
-		delete(eb.listeners, event)
+		eb.setEntries(event, nil)
 		return
 	}
 
-	entries := eb.listeners[event]
+	ptrs := make(map[uintptr]bool, len(handler))
 	for _, h := range handler {
-		hPtr := reflect.ValueOf(h).Pointer()
-		for i := len(entries) - 1; i >= 0; i-- {
-			if reflect.ValueOf(entries[i].handler).Pointer() == hPtr {
-				entries = append(entries[:i], entries[i+1:]...)
-			}
-		}
+		ptrs[reflect.ValueOf(h).Pointer()] = true
 	}
-	eb.listeners[event] = entries
+	eb.setEntries(event, without(eb.listeners[event], func(e *handlerEntry) bool {
+		return e.handler != nil && ptrs[reflect.ValueOf(e.handler).Pointer()]
+	}))
 }
 
 // ListenerCount 获取指定事件的监听器数量
@@ -133,4 +479,5 @@ func (eb *EventBus) Clear() {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 	eb.listeners = make(map[string][]*handlerEntry)
+	eb.wildcards = make(map[string]bool)
 }
@@ -1,8 +1,13 @@
 package eventbus
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/request"
 )
 
 func TestEventBus_On(t *testing.T) {
@@ -270,3 +275,274 @@ func BenchmarkEventBus_On(b *testing.B) {
 		eb.On("benchmark", func(args ...interface{}) {})
 	}
 }
+
+func TestEventBus_Wildcard(t *testing.T) {
+	eb := New()
+	var got []string
+
+	eb.On("user.*", func(args ...interface{}) {
+		meta := args[0].(EventMeta)
+		got = append(got, "single:"+meta.Name+":"+args[1].(string))
+	})
+	eb.On("user.**", func(args ...interface{}) {
+		got = append(got, "deep:"+args[0].(EventMeta).Name)
+	})
+	eb.On("user.created", func(args ...interface{}) {
+		got = append(got, "exact:"+args[0].(string))
+	})
+
+	eb.Emit("user.created", "alice")
+	eb.Emit("user.profile.updated")
+	eb.Emit("order.created")
+
+	want := []string{
+		"single:user.created:alice", // 按注册顺序执行
+		"deep:user.created",
+		"exact:alice",
+		"deep:user.profile.updated", // * 只匹配单层
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected call %d to be %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestEventBus_WildcardOnceAndOff(t *testing.T) {
+	eb := New()
+	callCount := 0
+
+	eb.Once("order.*", func(args ...interface{}) { callCount++ })
+	eb.Emit("order.paid")
+	eb.Emit("order.paid")
+	if callCount != 1 {
+		t.Errorf("Expected wildcard once call count to be 1, got %d", callCount)
+	}
+
+	eb.On("**", func(args ...interface{}) { callCount++ })
+	eb.Off("**")
+	eb.Emit("anything")
+	if callCount != 1 {
+		t.Errorf("Expected removed wildcard listener not to be called, got %d", callCount)
+	}
+}
+
+func TestEventBus_OnEErrorsAndTimeout(t *testing.T) {
+	eb := New()
+	var reported []string
+	eb.SetErrorHandler(func(event string, err error) { reported = append(reported, event) })
+
+	errBoom := errors.New("boom")
+	eb.OnE("job.run", func(ctx context.Context, args ...interface{}) error { return errBoom })
+	eb.OnE("job.run", func(ctx context.Context, args ...interface{}) error {
+		<-ctx.Done() // 遵守超时取消
+		return ctx.Err()
+	}, WithTimeout(10*time.Millisecond))
+	eb.OnE("job.*", func(ctx context.Context, args ...interface{}) error {
+		if meta, ok := EventFromContext(ctx); !ok || meta.Name != "job.run" || len(args) != 1 {
+			t.Errorf("Expected event meta in ctx and raw args, got %v %v", meta, args)
+		}
+		return nil
+	})
+
+	err := eb.EmitCtx(context.Background(), "job.run", 1)
+	if !errors.Is(err, errBoom) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected joined errors to contain boom and deadline, got %v", err)
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected 2 reported failures, got %d", len(reported))
+	}
+}
+
+func TestEventBus_EmitCtxCanceled(t *testing.T) {
+	eb := New()
+	called := false
+	eb.OnE("test", func(ctx context.Context, args ...interface{}) error {
+		called = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := eb.EmitCtx(ctx, "test"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if called {
+		t.Error("Handler should not run after context is canceled")
+	}
+}
+
+func TestEventBus_PriorityAndVeto(t *testing.T) {
+	eb := New()
+	var order []string
+
+	eb.On("user.deleting", func(args ...interface{}) { order = append(order, "low") }, WithPriority(-1))
+	eb.On("user.deleting", func(args ...interface{}) { order = append(order, "default") })
+	eb.OnE("user.deleting", func(ctx context.Context, args ...interface{}) error {
+		order = append(order, "guard")
+		if args[0] == "admin" {
+			return Veto("管理员不可删除")
+		}
+		return nil
+	}, WithPriority(10))
+
+	if err := eb.EmitCtx(context.Background(), "user.deleting", "alice"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(order) != 3 || order[0] != "guard" || order[1] != "default" || order[2] != "low" {
+		t.Errorf("Expected priority order [guard default low], got %v", order)
+	}
+
+	order = nil
+	err := eb.EmitCtx(context.Background(), "user.deleting", "admin")
+	if !IsStopped(err) {
+		t.Errorf("Expected veto error, got %v", err)
+	}
+	if len(order) != 1 {
+		t.Errorf("Expected propagation to stop after guard, got %v", order)
+	}
+}
+
+// TestEventBus_VetoKeepsOnce 被拦截而未执行的 once 监听器保留到下一次 Emit
+func TestEventBus_VetoKeepsOnce(t *testing.T) {
+	eb := New()
+	calls := 0
+	eb.Once("user.deleting", func(args ...interface{}) { calls++ })
+	eb.Once("user.*", func(args ...interface{}) { calls++ })
+	eb.OnE("user.deleting", func(ctx context.Context, args ...interface{}) error {
+		if args[0] == "admin" {
+			return Veto("管理员不可删除")
+		}
+		return nil
+	}, WithPriority(10))
+
+	if err := eb.EmitCtx(context.Background(), "user.deleting", "admin"); !IsStopped(err) || calls != 0 {
+		t.Fatalf("err = %v, calls = %d", err, calls)
+	}
+	if eb.ListenerCount("user.deleting") != 2 || eb.ListenerCount("user.*") != 1 {
+		t.Fatalf("未执行的 once 监听器不应移除，剩余 %d、%d", eb.ListenerCount("user.deleting"), eb.ListenerCount("user.*"))
+	}
+
+	_ = eb.EmitCtx(context.Background(), "user.deleting", "alice")
+	_ = eb.EmitCtx(context.Background(), "user.deleting", "bob")
+	if calls != 2 || eb.ListenerCount("user.deleting") != 1 || eb.ListenerCount("user.*") != 0 {
+		t.Errorf("once 监听器应各执行一次后移除，calls = %d", calls)
+	}
+}
+
+func TestEventBus_RequestIDMeta(t *testing.T) {
+	eb := New()
+	var wildcard, withCtx string
+
+	eb.On("order.*", func(args ...interface{}) {
+		wildcard = args[0].(EventMeta).RequestID
+	})
+	eb.OnE("order.paid", func(ctx context.Context, args ...interface{}) error {
+		meta, _ := EventFromContext(ctx)
+		withCtx = meta.RequestID
+		return nil
+	})
+
+	ctx := request.WithRequestID(context.Background(), "req-1")
+	if err := eb.EmitCtx(ctx, "order.paid"); err != nil {
+		t.Fatal(err)
+	}
+	if wildcard != "req-1" || withCtx != "req-1" {
+		t.Errorf("Expected request id req-1, got %q and %q", wildcard, withCtx)
+	}
+}
+
+func TestEventBus_PanicRecovered(t *testing.T) {
+	eb := New()
+	var reported []error
+	eb.SetErrorHandler(func(event string, err error) { reported = append(reported, err) })
+
+	var ran []string
+	eb.On("order.paid", func(args ...interface{}) { panic("boom") }, WithPriority(10))
+	eb.OnE("order.paid", func(ctx context.Context, args ...interface{}) error {
+		var m map[string]int
+		m["x"] = 1 // nil map 写入 panic
+		return nil
+	}, WithPriority(5))
+	eb.On("order.paid", func(args ...interface{}) { ran = append(ran, "after") })
+
+	err := eb.EmitCtx(context.Background(), "order.paid")
+
+	if len(ran) != 1 {
+		t.Errorf("Expected remaining handler to run, got %v", ran)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Errorf("Expected PanicError with stack, got %v", err)
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected 2 reported panics, got %v", reported)
+	}
+
+	// Emit 不返回错误，同样不应让调用方崩溃
+	eb.Emit("order.paid")
+	if len(ran) != 2 {
+		t.Errorf("Expected handlers to keep running on Emit, got %v", ran)
+	}
+}
+
+func TestEventBus_SubscriptionUnsubscribe(t *testing.T) {
+	eb := New()
+	var got []int
+
+	// 同一函数字面量创建的闭包函数指针相同，Off 无法区分，Subscription 可以
+	subs := make([]*Subscription, 3)
+	for i := range subs {
+		n := i
+		subs[i] = eb.On("test", func(args ...interface{}) { got = append(got, n) })
+	}
+	once := eb.Once("test", func(args ...interface{}) { got = append(got, 100) })
+	ctxSub := eb.OnE("test", func(ctx context.Context, args ...interface{}) error {
+		got = append(got, 200)
+		return nil
+	})
+
+	subs[1].Unsubscribe()
+	subs[1].Unsubscribe() // 重复调用无副作用
+	ctxSub.Unsubscribe()
+	eb.Emit("test")
+	if len(got) != 3 || got[0] != 0 || got[1] != 2 || got[2] != 100 {
+		t.Errorf("Expected [0 2 100], got %v", got)
+	}
+
+	// once 监听器已执行后 Unsubscribe 不影响其他监听器
+	once.Unsubscribe()
+	got = nil
+	eb.Emit("test")
+	if len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("Expected [0 2], got %v", got)
+	}
+
+	subs[0].Unsubscribe()
+	subs[2].Unsubscribe()
+	if eb.ListenerCount("test") != 0 || len(eb.Events()) != 0 {
+		t.Errorf("Expected no listeners left, got %d", eb.ListenerCount("test"))
+	}
+	if subs[0].Event() != "test" {
+		t.Errorf("Expected event name test, got %q", subs[0].Event())
+	}
+}
+
+func TestEventBus_UnsubscribeOnceBeforeEmit(t *testing.T) {
+	eb := New()
+	var got []string
+
+	eb.Once("test", func(args ...interface{}) { got = append(got, "a") })
+	b := eb.Once("test", func(args ...interface{}) { got = append(got, "b") })
+	eb.Once("test", func(args ...interface{}) { got = append(got, "c") })
+
+	b.Unsubscribe()
+	eb.Emit("test")
+	eb.Emit("test")
+	if len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("Expected [a c], got %v", got)
+	}
+}
@@ -1,5 +1,7 @@
 package eventbus
 
+import "context"
+
 // 全局事件总线实例
 var defaultEventBus = New()
 
@@ -11,13 +13,33 @@ func Default() *EventBus {
 }
 
 // On 在全局事件总线上注册事件监听器
-func On(event string, handler EventHandler) {
-	defaultEventBus.On(event, handler)
+func On(event string, handler EventHandler, opts ...ListenerOption) *Subscription {
+	return defaultEventBus.On(event, handler, opts...)
 }
 
 // Once 在全局事件总线上注册一次性事件监听器
-func Once(event string, handler EventHandler) {
-	defaultEventBus.Once(event, handler)
+func Once(event string, handler EventHandler, opts ...ListenerOption) *Subscription {
+	return defaultEventBus.Once(event, handler, opts...)
+}
+
+// OnE 在全局事件总线上注册支持上下文与错误返回的事件监听器
+func OnE(event string, handler ContextHandler, opts ...ListenerOption) *Subscription {
+	return defaultEventBus.OnE(event, handler, opts...)
+}
+
+// OnceE 在全局事件总线上注册一次性的 ContextHandler
+func OnceE(event string, handler ContextHandler, opts ...ListenerOption) *Subscription {
+	return defaultEventBus.OnceE(event, handler, opts...)
+}
+
+// EmitCtx 在全局事件总线上携带上下文触发事件，返回处理失败的汇总错误
+func EmitCtx(ctx context.Context, event string, args ...interface{}) error {
+	return defaultEventBus.EmitCtx(ctx, event, args...)
+}
+
+// SetErrorHandler 设置全局事件总线的处理失败上报回调
+func SetErrorHandler(h ErrorHandler) {
+	defaultEventBus.SetErrorHandler(h)
 }
 
 // Emit 在全局事件总线上触发事件
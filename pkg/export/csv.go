@@ -0,0 +1,138 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// utf8BOM Excel 依据 BOM 识别 UTF-8 编码的 CSV，缺少时中文显示为乱码
+const utf8BOM = "\ufeff"
+
+// CSVWriter CSV 表格写出
+type CSVWriter struct {
+	w        io.Writer
+	csv      *csv.Writer
+	bom      bool
+	sanitize bool
+	record   []string
+	started  bool
+}
+
+// CSVOption CSV 写出选项
+type CSVOption func(*CSVWriter)
+
+// WithCSVBOM 是否在开头写出 UTF-8 BOM，默认写出（便于 Excel 直接打开）
+func WithCSVBOM(on bool) CSVOption {
+	return func(w *CSVWriter) { w.bom = on }
+}
+
+// WithCSVComma 设置分隔符，默认逗号
+func WithCSVComma(comma rune) CSVOption {
+	return func(w *CSVWriter) { w.csv.Comma = comma }
+}
+
+// WithCSVSanitize 是否防御公式注入，默认开启：以 = + - @ 制表符或回车开头的文本前加单引号，
+// 防止用户提交的内容在 Excel 中被当作公式执行（数字类型的值不受影响）
+func WithCSVSanitize(on bool) CSVOption {
+	return func(w *CSVWriter) { w.sanitize = on }
+}
+
+// NewCSVWriter 创建 CSV 写出，写出内容经过缓冲，Close 时刷新
+func NewCSVWriter(w io.Writer, opts ...CSVOption) *CSVWriter {
+	cw := &CSVWriter{w: w, csv: csv.NewWriter(w), bom: true, sanitize: true}
+	for _, opt := range opts {
+		opt(cw)
+	}
+	return cw
+}
+
+// start 首次写出前写入 BOM
+func (w *CSVWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if w.bom {
+		_, err := io.WriteString(w.w, utf8BOM)
+		return err
+	}
+	return nil
+}
+
+// WriteHeader 实现 Writer
+func (w *CSVWriter) WriteHeader(headers []Header) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	record := make([]string, len(headers))
+	for i, h := range headers {
+		record[i] = w.text(h.Title)
+	}
+	return w.csv.Write(record)
+}
+
+// WriteRow 实现 Writer
+func (w *CSVWriter) WriteRow(values []any) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.record = w.record[:0]
+	for _, v := range values {
+		w.record = append(w.record, w.cell(v))
+	}
+	return w.csv.Write(w.record)
+}
+
+// Close 实现 Writer，刷新缓冲
+func (w *CSVWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// cell 格式化单元格
+func (w *CSVWriter) cell(v any) string {
+	switch x := Normalize(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return w.text(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(DateTimeLayout)
+	}
+	return ""
+}
+
+// text 按需转义可能被当作公式的文本
+func (w *CSVWriter) text(s string) string {
+	if w.sanitize && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// CSV 将 e 以 CSV 写出到 w
+func CSV(ctx context.Context, w io.Writer, e Exporter, locale string, opts ...CSVOption) error {
+	cw := NewCSVWriter(w, opts...)
+	if err := e.Export(ctx, cw, locale); err != nil {
+		return err
+	}
+	return cw.Close()
+}
@@ -0,0 +1,184 @@
+// Package export 提供 CSV 与 XLSX 的流式导出：数据源为逐行产出的迭代器（如 Query 逐行读取的查询结果），
+// 写出一行释放一行，导出百万行也不会将数据整体载入内存。
+//
+//	table := export.New(export.Query[model.Order](db.WithContext(ctx).Where("status = ?", "paid")),
+//	    export.Col("order.no", func(o model.Order) any { return o.No }),
+//	    export.Col("order.amount", func(o model.Order) any { return o.Amount }),
+//	    export.Column[model.Order]{Header: "order.created_at", Value: func(o model.Order) any { return o.CreatedAt }, Width: 20},
+//	)
+//	return response.ExportXLSX(c, "订单.xlsx", table) // 表头按请求语言以 i18n 翻译
+package export
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"iter"
+	"reflect"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"gorm.io/gorm"
+)
+
+// DateTimeLayout CSV 中时间值的格式
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// Header 表头单元格
+type Header struct {
+	Title string
+	Width float64 // XLSX 列宽（字符数），0 为默认宽度
+}
+
+// Writer 按行写出表格，由 CSVWriter、XLSXWriter 实现
+type Writer interface {
+	// WriteHeader 写出表头，须在 WriteRow 之前调用且只调用一次
+	WriteHeader(headers []Header) error
+	// WriteRow 写出一行，值的类型见 Normalize
+	WriteRow(values []any) error
+	// Close 写出剩余内容（XLSX 的结尾部分），不关闭底层 io.Writer
+	Close() error
+}
+
+// Exporter 将数据写出到 Writer，locale 用于翻译表头
+type Exporter interface {
+	Export(ctx context.Context, w Writer, locale string) error
+}
+
+// Column 列定义
+type Column[T any] struct {
+	Header string // 表头，作为 i18n key 按语言翻译，未注册时原样输出
+	Value  func(T) any
+	Width  float64 // XLSX 列宽（字符数），0 为默认宽度
+}
+
+// Col 创建列定义
+func Col[T any](header string, value func(T) any) Column[T] {
+	return Column[T]{Header: header, Value: value}
+}
+
+// Table 按列定义导出数据源的 Exporter
+type Table[T any] struct {
+	rows    iter.Seq2[T, error]
+	columns []Column[T]
+}
+
+// New 创建导出表，rows 产出的错误会中止导出并原样返回
+func New[T any](rows iter.Seq2[T, error], columns ...Column[T]) *Table[T] {
+	return &Table[T]{rows: rows, columns: columns}
+}
+
+// Export 实现 Exporter：写出翻译后的表头，再逐行写出各列的值；ctx 取消（如客户端断开）时停止
+func (t *Table[T]) Export(ctx context.Context, w Writer, locale string) error {
+	headers := make([]Header, len(t.columns))
+	for i, col := range t.columns {
+		headers[i] = Header{Title: i18n.T(locale, col.Header), Width: col.Width}
+	}
+	if err := w.WriteHeader(headers); err != nil {
+		return err
+	}
+	values := make([]any, len(t.columns))
+	for row, err := range t.rows {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i, col := range t.columns {
+			values[i] = col.Value(row)
+		}
+		if err := w.WriteRow(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Values 将切片包装为数据源
+func Values[T any](items []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Query 逐行读取查询结果（db.Rows + ScanRows）作为数据源，不会将结果集整体载入内存。
+// T 为模型结构体（非指针）；db 未指定 Model / Table 时使用 T 的表。读取期间占用一个数据库连接
+func Query[T any](db *gorm.DB) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if db.Statement.Model == nil && db.Statement.Table == "" {
+			db = db.Model(new(T))
+		}
+		rows, err := db.Rows()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var v T
+			if err := db.ScanRows(rows, &v); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// Normalize 将单元格的值归一为 nil、string、bool、int64、uint64、float64 或 time.Time：
+// 解引用指针，driver.Valuer（如 sql.NullString）取其值，fmt.Stringer 与其他类型转为字符串
+func Normalize(v any) any {
+	switch x := v.(type) {
+	case nil, string, bool, int64, uint64, float64, time.Time:
+		return x
+	case []byte:
+		return string(x)
+	case driver.Valuer:
+		rv := reflect.ValueOf(x)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil
+		}
+		val, err := x.Value()
+		if err != nil {
+			return nil
+		}
+		return Normalize(val)
+	case fmt.Stringer:
+		rv := reflect.ValueOf(x)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil
+		}
+		return x.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return Normalize(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type order struct {
+	ID        uint
+	No        string
+	Amount    float64
+	Paid      bool
+	Note      sql.NullString
+	CreatedAt time.Time
+}
+
+func orderTable(rows iter.Seq2[order, error]) *Table[order] {
+	return New(rows,
+		Col("export_test.no", func(o order) any { return o.No }),
+		Col("金额", func(o order) any { return o.Amount }),
+		Col("已支付", func(o order) any { return o.Paid }),
+		Col("备注", func(o order) any { return o.Note }),
+		Column[order]{Header: "时间", Value: func(o order) any { return o.CreatedAt }, Width: 20},
+	)
+}
+
+var createdAt = time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+// TestCSV 写出 BOM、翻译表头、各类型值的格式化与公式注入防御
+func TestCSV(t *testing.T) {
+	i18n.Register("en", map[string]string{"export_test.no": "Order No"})
+	rows := []order{
+		{No: "A1", Amount: 9.5, Paid: true, Note: sql.NullString{String: "=1+1", Valid: true}, CreatedAt: createdAt},
+		{No: "A2", Amount: -3},
+	}
+	var buf bytes.Buffer
+	if err := CSV(context.Background(), &buf, orderTable(Values(rows)), "en"); err != nil {
+		t.Fatal(err)
+	}
+	want := "\ufeffOrder No,金额,已支付,备注,时间\n" +
+		"A1,9.5,true,'=1+1,2024-03-01 12:30:00\n" +
+		"A2,-3,false,,\n"
+	if buf.String() != want {
+		t.Errorf("CSV:\n%q\n期望:\n%q", buf.String(), want)
+	}
+
+	buf.Reset()
+	_ = CSV(context.Background(), &buf, orderTable(Values(rows[:1])), "zh-CN", WithCSVBOM(false), WithCSVComma(';'), WithCSVSanitize(false))
+	if !strings.HasPrefix(buf.String(), "export_test.no;") || !strings.Contains(buf.String(), ";=1+1;") {
+		t.Errorf("选项未生效: %q", buf.String())
+	}
+}
+
+// TestExportError 数据源错误中止导出并原样返回
+func TestExportError(t *testing.T) {
+	boom := errors.New("boom")
+	rows := func(yield func(order, error) bool) {
+		if yield(order{No: "A1"}, nil) {
+			yield(order{}, boom)
+		}
+	}
+	if err := CSV(context.Background(), io.Discard, orderTable(rows), ""); !errors.Is(err, boom) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestQuery 逐行读取查询结果作为数据源
+func TestQuery(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&order{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create([]*order{{No: "A1"}, {No: "A2"}, {No: "A3"}})
+
+	var got []string
+	for o, err := range Query[order](db.Where("no <> ?", "A2").Order("id")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, o.No)
+	}
+	if strings.Join(got, ",") != "A1,A3" {
+		t.Errorf("Query = %v", got)
+	}
+}
+
+// TestXLSX 生成合法的 zip 包，单元格保留数字、布尔与时间类型，文本转义
+func TestXLSX(t *testing.T) {
+	rows := []order{{No: "<A&1>", Amount: 9.5, Paid: true, CreatedAt: createdAt}}
+	var buf bytes.Buffer
+	if err := XLSX(context.Background(), &buf, orderTable(Values(rows)), "", WithXLSXSheet("订单/2024")); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if files[name] == "" {
+			t.Errorf("缺少 %s", name)
+		}
+	}
+	if !strings.Contains(files["xl/workbook.xml"], `name="订单_2024"`) {
+		t.Errorf("工作表名称: %s", files["xl/workbook.xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`,
+		`<col min="5" max="5" width="20" customWidth="1"/>`,
+		`<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">export_test.no</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">&lt;A&amp;1&gt;</t></is></c>`,
+		`<c r="B2"><v>9.5</v></c>`,
+		`<c r="C2" t="b"><v>1</v></c>`,
+		`<c r="E2" s="2"><v>45352.520833333336</v></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("工作表缺少 %s\n%s", want, sheet)
+		}
+	}
+	if strings.Contains(sheet, `r="D2"`) {
+		t.Error("空值不应输出单元格")
+	}
+}
+
+// TestColumnName 列序号转列名
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s，期望 %s", i, got, want)
+		}
+	}
+}
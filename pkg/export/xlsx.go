@@ -0,0 +1,285 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// XLSX 单表的行数与单元格文本长度上限
+const (
+	MaxXLSXRows     = 1 << 20
+	maxXLSXCellText = 32767
+)
+
+// ErrTooManyRows 超出 XLSX 单表的行数上限
+var ErrTooManyRows = errors.New("超出 XLSX 单表行数上限（1048576 行）")
+
+// 样式表中的样式序号
+const (
+	styleHeader   = 1 // 表头：粗体
+	styleDateTime = 2 // 时间：yyyy-mm-dd hh:mm:ss
+)
+
+// excelEpoch Excel 日期序列号的起点
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// XLSXWriter 流式写出单个工作表的 XLSX：行数据直接压缩写入 zip，不在内存中保留；
+// 文本以内联字符串存储（不生成共享字符串表），数字、布尔与时间保留类型，表头粗体并冻结首行
+type XLSXWriter struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	name    string
+	row     int
+	started bool
+	buf     bytes.Buffer
+}
+
+// XLSXOption XLSX 写出选项
+type XLSXOption func(*XLSXWriter)
+
+// WithXLSXSheet 设置工作表名称，默认 Sheet1；超过 31 个字符时截断，Excel 不允许的字符替换为下划线
+func WithXLSXSheet(name string) XLSXOption {
+	return func(w *XLSXWriter) { w.name = sheetName(name) }
+}
+
+// NewXLSXWriter 创建 XLSX 写出，Close 时写出文件结尾
+func NewXLSXWriter(w io.Writer, opts ...XLSXOption) *XLSXWriter {
+	xw := &XLSXWriter{zw: zip.NewWriter(w), name: "Sheet1"}
+	for _, opt := range opts {
+		opt(xw)
+	}
+	return xw
+}
+
+// sheetName 规范化工作表名称
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if utf8.RuneCountInString(name) > 31 {
+		name = string([]rune(name)[:31])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// start 写出固定部件并开始工作表，headers 为 nil 时不冻结首行
+func (w *XLSXWriter) start(headers []Header) error {
+	w.started = true
+	var workbook strings.Builder
+	_ = xml.EscapeText(&workbook, []byte(w.name))
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, workbook.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, p := range parts {
+		f, err := w.zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header+p.body); err != nil {
+			return err
+		}
+	}
+
+	f, err := w.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.sheet = bufio.NewWriterSize(f, 32<<10)
+	w.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if headers != nil {
+		w.sheet.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+		var cols strings.Builder
+		for i, h := range headers {
+			if h.Width > 0 {
+				fmt.Fprintf(&cols, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, i+1, i+1, strconv.FormatFloat(h.Width, 'f', -1, 64))
+			}
+		}
+		if cols.Len() > 0 {
+			w.sheet.WriteString("<cols>" + cols.String() + "</cols>")
+		}
+	}
+	_, err = w.sheet.WriteString("<sheetData>")
+	return err
+}
+
+// WriteHeader 实现 Writer
+func (w *XLSXWriter) WriteHeader(headers []Header) error {
+	if w.started {
+		return errors.New("表头须在数据行之前写出")
+	}
+	if headers == nil {
+		headers = []Header{}
+	}
+	if err := w.start(headers); err != nil {
+		return err
+	}
+	values := make([]any, len(headers))
+	for i, h := range headers {
+		values[i] = h.Title
+	}
+	return w.writeRow(values, styleHeader)
+}
+
+// WriteRow 实现 Writer
+func (w *XLSXWriter) WriteRow(values []any) error {
+	if !w.started {
+		if err := w.start(nil); err != nil {
+			return err
+		}
+	}
+	return w.writeRow(values, 0)
+}
+
+// writeRow 写出一行，style 为文本单元格的样式
+func (w *XLSXWriter) writeRow(values []any, style int) error {
+	if w.row >= MaxXLSXRows {
+		return ErrTooManyRows
+	}
+	w.row++
+	b := &w.buf
+	b.Reset()
+	fmt.Fprintf(b, `<row r="%d">`, w.row)
+	for i, v := range values {
+		ref := columnName(i) + strconv.Itoa(w.row)
+		switch x := Normalize(v).(type) {
+		case nil:
+			continue
+		case bool:
+			v := "0"
+			if x {
+				v = "1"
+			}
+			fmt.Fprintf(b, `<c r="%s" t="b"><v>%s</v></c>`, ref, v)
+		case int64:
+			fmt.Fprintf(b, `<c r="%s"><v>%d</v></c>`, ref, x)
+		case uint64:
+			fmt.Fprintf(b, `<c r="%s"><v>%d</v></c>`, ref, x)
+		case float64:
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				writeText(b, ref, strconv.FormatFloat(x, 'f', -1, 64), style)
+				continue
+			}
+			fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(x, 'f', -1, 64))
+		case time.Time:
+			if x.IsZero() {
+				continue
+			}
+			fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDateTime, strconv.FormatFloat(excelTime(x), 'f', -1, 64))
+		case string:
+			writeText(b, ref, x, style)
+		}
+	}
+	b.WriteString("</row>")
+	_, err := w.sheet.Write(b.Bytes())
+	return err
+}
+
+// writeText 写出内联字符串单元格，超长文本截断到 Excel 上限
+func writeText(b *bytes.Buffer, ref, s string, style int) {
+	if utf8.RuneCountInString(s) > maxXLSXCellText {
+		s = string([]rune(s)[:maxXLSXCellText])
+	}
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+	if style != 0 {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">`)
+	_ = xml.EscapeText(b, []byte(s))
+	b.WriteString(`</t></is></c>`)
+}
+
+// Close 实现 Writer，写出工作表结尾与 zip 目录
+func (w *XLSXWriter) Close() error {
+	if !w.started {
+		if err := w.start(nil); err != nil {
+			return err
+		}
+	}
+	w.sheet.WriteString("</sheetData></worksheet>")
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// columnName 列序号（从 0 开始）转为列名：0 → A，26 → AA
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// excelTime 将时间转为 Excel 日期序列号，按时间自身时区的钟面时间（Excel 不含时区）
+func excelTime(t time.Time) float64 {
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	wall := time.Date(y, mo, d, h, mi, s, t.Nanosecond(), time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+// XLSX 将 e 以 XLSX 写出到 w
+func XLSX(ctx context.Context, w io.Writer, e Exporter, locale string, opts ...XLSXOption) error {
+	xw := NewXLSXWriter(w, opts...)
+	if err := e.Export(ctx, xw, locale); err != nil {
+		return err
+	}
+	return xw.Close()
+}
+
+// XLSX 固定部件
+const (
+	xlsxContentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	xlsxStyles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+)
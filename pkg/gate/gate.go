@@ -0,0 +1,175 @@
+// Package gate 提供基于策略的对象级授权：按模型类型注册能力（update、delete 等）的判断函数，
+// 在处理器、中间件与模板中检查当前用户能否对某个对象执行操作。
+// 角色 / 权限（RBAC，见 middleware.RoleMiddleware、nav.Can）回答“能否编辑文章”，
+// 策略回答“能否编辑这篇文章”（如只有作者本人可编辑）：
+//
+//	gate.Define("update", func(u *model.User, a *model.Article) bool { return a.AuthorID == u.ID })
+//	gate.Before(func(c *gin.Context, ability string) (bool, bool) {
+//	    u, _ := auth.UserAs[*model.User](c)
+//	    return true, u != nil && u.IsAdmin // 管理员跳过策略
+//	})
+//
+//	if err := gate.Authorize(c, "update", article); err != nil {
+//	    return err // 403
+//	}
+//
+// 模板中: {{ if can "update" .Article $ }}<a href="...">编辑</a>{{ end }}
+package gate
+
+import (
+	stderrors "errors"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// ErrDenied 策略拒绝访问
+var ErrDenied = stderrors.New("无权执行该操作")
+
+// BeforeFunc 在策略之前执行的判断，decided 为 true 时以 allowed 作为结果、不再执行策略
+type BeforeFunc func(c *gin.Context, ability string) (allowed, decided bool)
+
+// rule 已注册的策略判断，subject 已确认为注册的模型类型
+type rule func(c *gin.Context, subject any) bool
+
+// policyKey 策略键：模型类型 + 能力
+type policyKey struct {
+	typ     reflect.Type
+	ability string
+}
+
+// Gate 策略注册表
+type Gate struct {
+	mu       sync.RWMutex
+	policies map[policyKey]rule
+	before   []BeforeFunc
+}
+
+// New 创建空的策略注册表
+func New() *Gate {
+	return &Gate{policies: make(map[policyKey]rule)}
+}
+
+// DefineOn 在 g 上为模型类型 T 注册能力 ability 的判断；当前用户不是 U 类型（含未登录）时拒绝。
+// 同一类型与能力重复注册时后者覆盖前者
+func DefineOn[U, T any](g *Gate, ability string, fn func(user U, subject T) bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.policies[policyKey{reflect.TypeFor[T](), ability}] = func(c *gin.Context, subject any) bool {
+		user, ok := auth.UserAs[U](c)
+		if !ok {
+			return false
+		}
+		return fn(user, subject.(T))
+	}
+}
+
+// Before 注册在所有策略之前执行的判断（如管理员放行、封禁用户拒绝），按注册顺序执行
+func (g *Gate) Before(fn BeforeFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.before = append(g.before, fn)
+}
+
+// Allows 判断当前用户能否对 subject 执行 ability：先执行 Before，未决定时按 subject 的类型查找策略，
+// 指针类型未注册时再查找其元素类型；没有对应策略时拒绝
+func (g *Gate) Allows(c *gin.Context, ability string, subject any) bool {
+	g.mu.RLock()
+	before := g.before
+	g.mu.RUnlock()
+	for _, fn := range before {
+		if allowed, decided := fn(c, ability); decided {
+			return allowed
+		}
+	}
+	if subject == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(subject)
+	g.mu.RLock()
+	check, ok := g.policies[policyKey{v.Type(), ability}]
+	if !ok && v.Kind() == reflect.Pointer && !v.IsNil() {
+		if check, ok = g.policies[policyKey{v.Type().Elem(), ability}]; ok {
+			subject = v.Elem().Interface()
+		}
+	}
+	g.mu.RUnlock()
+	return ok && check(c, subject)
+}
+
+// Denies Allows 的否定
+func (g *Gate) Denies(c *gin.Context, ability string, subject any) bool {
+	return !g.Allows(c, ability, subject)
+}
+
+// Authorize 不允许时返回 403 业务错误（*errors.AppError），可直接 return 给路由层
+func (g *Gate) Authorize(c *gin.Context, ability string, subject any) error {
+	if g.Allows(c, ability, subject) {
+		return nil
+	}
+	return errors.NewForbidden(ErrDenied.Error(), ErrDenied)
+}
+
+var (
+	defaultMu   sync.RWMutex
+	defaultGate = New()
+)
+
+// SetDefault 替换全局策略注册表（测试中隔离策略时使用）
+func SetDefault(g *Gate) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultGate = g
+}
+
+// Default 返回全局策略注册表
+func Default() *Gate {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultGate
+}
+
+// Define 在全局注册表上为模型类型 T 注册能力 ability 的判断，见 DefineOn
+func Define[U, T any](ability string, fn func(user U, subject T) bool) {
+	DefineOn(Default(), ability, fn)
+}
+
+// Before 在全局注册表上注册前置判断，见 Gate.Before
+func Before(fn BeforeFunc) {
+	Default().Before(fn)
+}
+
+// Allows 使用全局注册表判断，见 Gate.Allows
+func Allows(c *gin.Context, ability string, subject any) bool {
+	return Default().Allows(c, ability, subject)
+}
+
+// Denies 使用全局注册表判断，见 Gate.Denies
+func Denies(c *gin.Context, ability string, subject any) bool {
+	return Default().Denies(c, ability, subject)
+}
+
+// Authorize 使用全局注册表判断，见 Gate.Authorize
+func Authorize(c *gin.Context, ability string, subject any) error {
+	return Default().Authorize(c, ability, subject)
+}
+
+// Checker 绑定当前请求的策略判断，由 template.WithContext 以 .Gate 注入视图，
+// 模板中可写作 {{ if .Gate.Allows "delete" .Comment }} 或 {{ if can "delete" .Comment $ }}
+type Checker struct {
+	c *gin.Context
+}
+
+// For 返回绑定请求的策略判断（使用全局注册表）
+func For(c *gin.Context) Checker {
+	return Checker{c: c}
+}
+
+// Allows 判断当前用户能否对 subject 执行 ability，见 Gate.Allows
+func (ch Checker) Allows(ability string, subject any) bool {
+	return ch.c != nil && Allows(ch.c, ability, subject)
+}
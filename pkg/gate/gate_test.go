@@ -0,0 +1,128 @@
+package gate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// TestMain 切换到仓库根目录，使 config.MustFetch() 能定位到 config/config.yaml（错误响应依赖全局配置）
+func TestMain(m *testing.M) {
+	dir, _ := os.Getwd()
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "config", "config.yaml")); err == nil {
+			_ = os.Chdir(dir)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	os.Exit(m.Run())
+}
+
+type user struct {
+	ID    uint
+	Admin bool
+}
+
+type article struct {
+	AuthorID uint
+}
+
+// newCtx 创建以 u 为当前用户的请求上下文，u 为 nil 时未登录
+func newCtx(u *user) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if u != nil {
+		request.Set(c, request.KeyCurrentUser, u)
+	}
+	return c
+}
+
+func TestAllows(t *testing.T) {
+	g := New()
+	DefineOn(g, "update", func(u *user, a article) bool { return a.AuthorID == u.ID })
+	DefineOn(g, "delete", func(u *user, a *article) bool { return false })
+
+	own, other := &article{AuthorID: 1}, &article{AuthorID: 2}
+	author := newCtx(&user{ID: 1})
+
+	if !g.Allows(author, "update", own) || !g.Allows(author, "update", *own) {
+		t.Error("作者应能编辑自己的文章（指针与值均可）")
+	}
+	if g.Allows(author, "update", other) {
+		t.Error("不应能编辑他人的文章")
+	}
+	if g.Allows(author, "delete", own) || g.Allows(author, "publish", own) || g.Allows(author, "update", nil) {
+		t.Error("策略拒绝、未注册的能力与 nil 对象均应拒绝")
+	}
+	if g.Allows(newCtx(nil), "update", own) {
+		t.Error("未登录时应拒绝")
+	}
+
+	err := g.Authorize(author, "update", other)
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.HTTPStatus() != http.StatusForbidden {
+		t.Errorf("Authorize 应返回 403 错误: %v", err)
+	}
+	if err := g.Authorize(author, "update", own); err != nil {
+		t.Errorf("允许时应返回 nil: %v", err)
+	}
+
+	// Before 决定时不再执行策略
+	g.Before(func(c *gin.Context, ability string) (bool, bool) {
+		u, _ := request.Get[*user](c, request.KeyCurrentUser)
+		return true, u != nil && u.Admin
+	})
+	admin := newCtx(&user{ID: 9, Admin: true})
+	if !g.Allows(admin, "delete", other) || g.Allows(author, "delete", own) {
+		t.Error("Before 应只放行管理员")
+	}
+}
+
+func TestRequire(t *testing.T) {
+	g := New()
+	DefineOn(g, "update", func(u *user, a *article) bool { return a.AuthorID == u.ID })
+	SetDefault(g)
+	defer SetDefault(New())
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		request.Set(c, request.KeyCurrentUser, &user{ID: 1})
+	})
+	load := func(c *gin.Context) (*article, error) {
+		switch c.Param("id") {
+		case "1":
+			return &article{AuthorID: 1}, nil
+		case "2":
+			return &article{AuthorID: 2}, nil
+		}
+		return nil, errors.NewNotFound("文章不存在", nil)
+	}
+	r.PUT("/articles/:id", Require("update", load), func(c *gin.Context) {
+		a, ok := Subject[*article](c)
+		if !ok || a.AuthorID != 1 {
+			t.Error("处理器应能读取已授权的对象")
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	for id, want := range map[string]int{"1": http.StatusNoContent, "2": http.StatusForbidden, "3": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/articles/"+id, nil)
+		req.Header.Set("Accept", "application/json")
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("id=%s: status = %d, want %d", id, w.Code, want)
+		}
+	}
+}
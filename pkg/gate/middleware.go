@@ -0,0 +1,38 @@
+package gate
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// keySubject 上下文中 Require 加载的对象
+const keySubject = "gate_subject"
+
+// Require 返回授权中间件：load 从请求中加载对象（如按路由参数查询文章），返回的错误交由 middleware.WriteError 输出（如 404）；
+// 全局注册表不允许时返回 403 并中止。加载的对象可在处理器中通过 Subject 读取，无需重复查询
+//
+//	rb.Group("/articles/:id", gate.Require("update", loadArticle)).PUT("", a.Update, "article@update")
+//	article, _ := gate.Subject[*model.Article](c)
+func Require[T any](ability string, load func(c *gin.Context) (T, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject, err := load(c)
+		if err != nil {
+			middleware.WriteError(c, err)
+			c.Abort()
+			return
+		}
+		if err := Authorize(c, ability, subject); err != nil {
+			middleware.WriteError(c, err)
+			c.Abort()
+			return
+		}
+		c.Set(keySubject, subject)
+		c.Next()
+	}
+}
+
+// Subject 返回 Require 加载并已授权的对象
+func Subject[T any](c *gin.Context) (T, bool) {
+	return request.Get[T](c, keySubject)
+}
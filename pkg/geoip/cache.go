@@ -0,0 +1,80 @@
+package geoip
+
+import (
+	"container/list"
+	"net"
+	"sync"
+)
+
+// DefaultCacheSize NewCached 默认缓存的 IP 数量
+const DefaultCacheSize = 10000
+
+// cacheEntry 缓存条目，loc 为 nil 表示该 IP 未收录
+type cacheEntry struct {
+	key string
+	loc *Location
+}
+
+// Cached 带 LRU 缓存的 Resolver，重复访问的 IP 不再查询数据库。
+// 未收录的 IP 同样缓存，查询出错时不缓存。
+type Cached struct {
+	resolver Resolver
+	size     int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// NewCached 为 resolver 包装 LRU 缓存，size <= 0 时使用 DefaultCacheSize
+func NewCached(resolver Resolver, size int) *Cached {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &Cached{
+		resolver: resolver,
+		size:     size,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Lookup 实现 Resolver
+func (c *Cached) Lookup(ip net.IP) (*Location, error) {
+	key := ip.String()
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		loc := el.Value.(*cacheEntry).loc
+		c.mu.Unlock()
+		return loc, nil
+	}
+	c.mu.Unlock()
+
+	loc, err := c.resolver.Lookup(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		// 并发查询同一 IP 时已由其他请求写入
+		c.ll.MoveToFront(el)
+		return loc, nil
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, loc: loc})
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+	return loc, nil
+}
+
+// Len 返回当前缓存的 IP 数量
+func (c *Cached) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,113 @@
+// Package geoip 按客户端 IP 解析国家与城市，供语言默认值、地区访问限制与审计日志使用。
+//
+// 典型用法（配置 geoip.database 后由路由自动注册，也可手动挂载）：
+//
+//	db, err := geoip.Open("storage/GeoLite2-City.mmdb")
+//	r.Use(middleware.GeoIP(geoip.NewCached(db, 10000)))
+//
+//	func (ctl *HomeController) Index(c *gin.Context) error {
+//	    if loc := geoip.Current(c); loc != nil && loc.CountryCode == "CN" { ... }
+//	}
+//
+// 模板中可使用 {{ .Geo.CountryCode }}、{{ .Geo.City }}。
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location IP 所属地区，名称为英文
+type Location struct {
+	CountryCode string  `json:"country_code"` // ISO 3166-1 国家代码，如 CN、US
+	Country     string  `json:"country"`      // 国家名称
+	City        string  `json:"city"`         // 城市名称，Country 数据库中为空
+	TimeZone    string  `json:"time_zone"`    // IANA 时区，如 Asia/Shanghai
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+}
+
+// Resolver IP 地区查询，未收录的 IP（含内网地址）返回 nil, nil
+type Resolver interface {
+	Lookup(ip net.IP) (*Location, error)
+}
+
+// DB MaxMind mmdb 数据库（GeoLite2/GeoIP2 的 City 或 Country 库），并发安全
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+// Open 打开 MaxMind mmdb 数据库文件
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开 GeoIP 数据库 %s 失败: %w", path, err)
+	}
+	return &DB{reader: reader}, nil
+}
+
+// record mmdb 记录中使用到的字段
+type record struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		TimeZone  string  `maxminddb:"time_zone"`
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// Lookup 实现 Resolver
+func (db *DB) Lookup(ip net.IP) (*Location, error) {
+	var rec record
+	_, ok, err := db.reader.LookupNetwork(ip, &rec)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &Location{
+		CountryCode: rec.Country.ISOCode,
+		Country:     rec.Country.Names["en"],
+		City:        rec.City.Names["en"],
+		TimeZone:    rec.Location.TimeZone,
+		Latitude:    rec.Location.Latitude,
+		Longitude:   rec.Location.Longitude,
+	}, nil
+}
+
+// Type 返回数据库类型，如 GeoLite2-City
+func (db *DB) Type() string {
+	return db.reader.Metadata.DatabaseType
+}
+
+// Close 关闭数据库
+func (db *DB) Close() error {
+	return db.reader.Close()
+}
+
+// Current 返回当前请求的地区（由 middleware.GeoIP 写入），未解析时返回 nil
+func Current(c *gin.Context) *Location {
+	return request.GetOr[*Location](c, request.KeyGeo, nil)
+}
+
+type locationKey struct{}
+
+// WithLocation 返回携带地区的 context，供服务层与审计日志读取
+func WithLocation(ctx context.Context, loc *Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// FromContext 从 context 读取地区，未设置时返回 nil
+func FromContext(ctx context.Context) *Location {
+	loc, _ := ctx.Value(locationKey{}).(*Location)
+	return loc
+}
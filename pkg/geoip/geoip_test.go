@@ -0,0 +1,64 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+// countingResolver 记录查询次数
+type countingResolver struct {
+	calls int
+	err   error
+}
+
+func (r *countingResolver) Lookup(ip net.IP) (*Location, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	if ip.IsPrivate() {
+		return nil, nil
+	}
+	return &Location{CountryCode: "US"}, nil
+}
+
+// TestCached 命中缓存不再查询，未收录的 IP 同样缓存，超出容量淘汰最久未使用的条目
+func TestCached(t *testing.T) {
+	r := &countingResolver{}
+	c := NewCached(r, 2)
+
+	a, b, private := net.ParseIP("8.8.8.8"), net.ParseIP("1.1.1.1"), net.ParseIP("10.0.0.1")
+	for range 2 {
+		if loc, err := c.Lookup(a); err != nil || loc.CountryCode != "US" {
+			t.Fatalf("Lookup: %+v %v", loc, err)
+		}
+		if loc, _ := c.Lookup(private); loc != nil {
+			t.Fatalf("内网地址应未收录: %+v", loc)
+		}
+	}
+	if r.calls != 2 {
+		t.Errorf("重复查询应命中缓存，实际查询 %d 次", r.calls)
+	}
+
+	c.Lookup(b) // 淘汰最久未使用的 8.8.8.8
+	if c.Len() != 2 {
+		t.Errorf("缓存数量 %d", c.Len())
+	}
+	c.Lookup(a)
+	if r.calls != 4 {
+		t.Errorf("被淘汰的 IP 应重新查询，实际查询 %d 次", r.calls)
+	}
+
+	r.err = errors.New("boom")
+	if _, err := NewCached(r, 0).Lookup(a); err == nil {
+		t.Error("查询错误应返回")
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.mmdb")); err == nil {
+		t.Error("数据库不存在时应返回错误")
+	}
+}
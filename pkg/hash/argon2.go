@@ -0,0 +1,109 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id 默认参数（参考 OWASP 推荐值）
+const (
+	defaultArgon2Memory  = 64 * 1024 // KiB
+	defaultArgon2Time    = 3
+	defaultArgon2Threads = 2
+	argon2SaltLen        = 16
+	argon2KeyLen         = 32
+)
+
+// Argon2id argon2id 哈希器，输出 PHC 格式：$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+type Argon2id struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+// NewArgon2id 创建 argon2id 哈希器，参数为 0 时使用默认值
+func NewArgon2id(memory, time uint32, threads uint8) *Argon2id {
+	if memory == 0 {
+		memory = defaultArgon2Memory
+	}
+	if time == 0 {
+		time = defaultArgon2Time
+	}
+	if threads == 0 {
+		threads = defaultArgon2Threads
+	}
+	return &Argon2id{memory: memory, time: time, threads: threads}
+}
+
+// argon2Params 解析后的哈希
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// Hash 实现 Hasher
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("生成盐值失败: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, a.time, a.memory, a.threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.memory, a.time, a.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify 实现 Hasher，使用哈希中记录的参数重新计算并恒定时间比较
+func (a *Argon2id) Verify(password, hashed string) bool {
+	p, err := parseArgon2(hashed)
+	if err != nil {
+		return false
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+	return subtle.ConstantTimeCompare(key, p.key) == 1
+}
+
+// Supports 实现 Hasher
+func (a *Argon2id) Supports(hashed string) bool {
+	return strings.HasPrefix(hashed, "$argon2id$")
+}
+
+// NeedsRehash 实现 Hasher：参数与当前配置不同时需要升级
+func (a *Argon2id) NeedsRehash(hashed string) bool {
+	p, err := parseArgon2(hashed)
+	return err != nil || p.memory != a.memory || p.time != a.time || p.threads != a.threads
+}
+
+// parseArgon2 解析 PHC 格式的 argon2id 哈希
+func parseArgon2(hashed string) (*argon2Params, error) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ErrUnknownAlgorithm
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("不支持的 argon2 版本: %s", parts[2])
+	}
+	p := &argon2Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, fmt.Errorf("解析 argon2 参数失败: %w", err)
+	}
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("解析 argon2 盐值失败: %w", err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf("解析 argon2 哈希失败: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,162 @@
+// Package hash 提供密码哈希门面，支持 bcrypt 与 argon2id。
+//
+// 校验时按哈希前缀自动识别算法，因此切换默认算法或调高代价后，
+// 旧哈希仍可校验，并可在登录成功时通过 CheckAndRehash 透明升级：
+//
+//	ok, upgraded, err := hash.CheckAndRehash(form.Password, user.Password)
+//	if ok && upgraded != "" {
+//	    userRepo.UpdatePassword(user.ID, upgraded)
+//	}
+package hash
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// 支持的算法
+const (
+	DriverBcrypt   = "bcrypt"
+	DriverArgon2id = "argon2id"
+)
+
+// ErrUnknownAlgorithm 无法识别的哈希格式
+var ErrUnknownAlgorithm = errors.New("无法识别的密码哈希算法")
+
+// Hasher 单一算法的密码哈希器
+type Hasher interface {
+	// Hash 生成密码哈希
+	Hash(password string) (string, error)
+	// Verify 校验密码与哈希是否匹配（恒定时间比较）
+	Verify(password, hashed string) bool
+	// Supports 判断哈希是否由本算法生成
+	Supports(hashed string) bool
+	// NeedsRehash 判断哈希参数是否与当前配置不一致
+	NeedsRehash(hashed string) bool
+}
+
+// Manager 密码哈希管理器：使用默认算法生成哈希，按前缀选择算法校验
+type Manager struct {
+	driver  Hasher
+	hashers []Hasher
+}
+
+// NewManager 创建管理器，driver 为生成新哈希使用的算法，others 为仅用于校验的历史算法
+func NewManager(driver Hasher, others ...Hasher) *Manager {
+	return &Manager{driver: driver, hashers: append([]Hasher{driver}, others...)}
+}
+
+// NewFromConfig 根据配置创建管理器，bcrypt 与 argon2id 哈希均可校验
+func NewFromConfig(cfg *config.HashConfig) (*Manager, error) {
+	bc := NewBcrypt(cfg.BcryptCost)
+	ar := NewArgon2id(cfg.Argon2Memory, cfg.Argon2Time, cfg.Argon2Threads)
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverBcrypt:
+		return NewManager(bc, ar), nil
+	case DriverArgon2id:
+		return NewManager(ar, bc), nil
+	default:
+		return nil, errors.New("不支持的密码哈希算法: " + cfg.Driver + "（支持 bcrypt、argon2id）")
+	}
+}
+
+// Make 使用默认算法生成密码哈希
+func (m *Manager) Make(password string) (string, error) {
+	return m.driver.Hash(password)
+}
+
+// Check 校验密码，自动识别哈希算法
+func (m *Manager) Check(password, hashed string) bool {
+	h := m.hasherFor(hashed)
+	return h != nil && h.Verify(password, hashed)
+}
+
+// NeedsRehash 判断哈希是否需要以当前默认算法/参数重新生成
+func (m *Manager) NeedsRehash(hashed string) bool {
+	if !m.driver.Supports(hashed) {
+		return true
+	}
+	return m.driver.NeedsRehash(hashed)
+}
+
+// CheckAndRehash 校验密码，通过且哈希需要升级时返回新哈希（否则 rehashed 为空），调用方负责持久化
+func (m *Manager) CheckAndRehash(password, hashed string) (ok bool, rehashed string, err error) {
+	if !m.Check(password, hashed) {
+		return false, "", nil
+	}
+	if !m.NeedsRehash(hashed) {
+		return true, "", nil
+	}
+	rehashed, err = m.Make(password)
+	return true, rehashed, err
+}
+
+// hasherFor 根据哈希前缀选择算法
+func (m *Manager) hasherFor(hashed string) Hasher {
+	for _, h := range m.hashers {
+		if h.Supports(hashed) {
+			return h
+		}
+	}
+	return nil
+}
+
+// Equal 恒定时间比较两个字符串，用于校验令牌、签名等，避免时序攻击
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// ---- 全局管理器 ----
+
+var (
+	defaultManager = NewManager(NewBcrypt(0), NewArgon2id(0, 0, 0))
+	defaultMu      sync.RWMutex
+)
+
+// Init 根据配置初始化全局管理器（未调用时使用默认代价的 bcrypt）
+func Init(cfg *config.HashConfig) error {
+	m, err := NewFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	SetDefault(m)
+	return nil
+}
+
+// SetDefault 设置全局管理器
+func SetDefault(m *Manager) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultManager = m
+}
+
+// Default 获取全局管理器
+func Default() *Manager {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultManager
+}
+
+// Make 使用全局管理器生成密码哈希
+func Make(password string) (string, error) {
+	return Default().Make(password)
+}
+
+// Check 使用全局管理器校验密码
+func Check(password, hashed string) bool {
+	return Default().Check(password, hashed)
+}
+
+// NeedsRehash 使用全局管理器判断哈希是否需要升级
+func NeedsRehash(hashed string) bool {
+	return Default().NeedsRehash(hashed)
+}
+
+// CheckAndRehash 使用全局管理器校验密码并在需要时生成升级后的哈希
+func CheckAndRehash(password, hashed string) (bool, string, error) {
+	return Default().CheckAndRehash(password, hashed)
+}
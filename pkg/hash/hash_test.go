@@ -0,0 +1,87 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// 测试使用低代价参数以加快速度
+func testArgon2() *Argon2id { return NewArgon2id(1024, 1, 1) }
+
+func TestMakeAndCheck(t *testing.T) {
+	for name, m := range map[string]*Manager{
+		"bcrypt":   NewManager(NewBcrypt(4)),
+		"argon2id": NewManager(testArgon2()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := m.Make("secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !m.Check("secret", h) {
+				t.Error("Check(correct) = false")
+			}
+			if m.Check("wrong", h) {
+				t.Error("Check(wrong) = true")
+			}
+			if m.NeedsRehash(h) {
+				t.Error("fresh hash should not need rehash")
+			}
+		})
+	}
+}
+
+func TestCheckAndRehashUpgradesAlgorithm(t *testing.T) {
+	legacy, _ := NewBcrypt(4).Hash("secret")
+
+	m := NewManager(testArgon2(), NewBcrypt(4))
+	ok, upgraded, err := m.CheckAndRehash("secret", legacy)
+	if err != nil || !ok {
+		t.Fatalf("CheckAndRehash = %v, %v", ok, err)
+	}
+	if !strings.HasPrefix(upgraded, "$argon2id$") {
+		t.Fatalf("upgraded = %q, want argon2id hash", upgraded)
+	}
+	if !m.Check("secret", upgraded) {
+		t.Error("upgraded hash does not verify")
+	}
+
+	ok, upgraded, _ = m.CheckAndRehash("wrong", legacy)
+	if ok || upgraded != "" {
+		t.Error("wrong password must not be upgraded")
+	}
+}
+
+func TestNeedsRehashOnCostChange(t *testing.T) {
+	h, _ := NewBcrypt(4).Hash("secret")
+	if !NewManager(NewBcrypt(5)).NeedsRehash(h) {
+		t.Error("bcrypt cost change should require rehash")
+	}
+
+	a, _ := testArgon2().Hash("secret")
+	if !NewManager(NewArgon2id(2048, 1, 1)).NeedsRehash(a) {
+		t.Error("argon2 memory change should require rehash")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	if _, err := NewFromConfig(&config.HashConfig{Driver: "md5"}); err == nil {
+		t.Error("expected error for unsupported driver")
+	}
+	m, err := NewFromConfig(&config.HashConfig{Driver: "argon2id", Argon2Memory: 1024, Argon2Time: 1, Argon2Threads: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := m.Make("secret")
+	if !strings.HasPrefix(h, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("hash = %q", h)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal("token", "token") || Equal("token", "tokem") || Equal("a", "ab") {
+		t.Error("Equal mismatch")
+	}
+}
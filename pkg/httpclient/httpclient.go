@@ -0,0 +1,41 @@
+// Package httpclient 提供调用外部服务的 HTTP 客户端：自动将请求上下文中的请求 ID
+// 以 X-Request-ID 头传给下游，使同一个 ID 贯穿整条调用链。
+//
+//	client := httpclient.New(5 * time.Second)
+//	req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, url, nil)
+//	resp, err := client.Do(req)
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// Transport 为出站请求附加 X-Request-ID 的 RoundTripper，请求已显式设置该头时不覆盖
+type Transport struct {
+	// Base 实际发送请求的 RoundTripper，为 nil 时使用 http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	id := request.RequestIDFromContext(req.Context())
+	if id == "" || req.Header.Get(request.HeaderRequestID) != "" {
+		return base.RoundTrip(req)
+	}
+	// RoundTripper 不应修改原请求
+	req = req.Clone(req.Context())
+	req.Header.Set(request.HeaderRequestID, id)
+	return base.RoundTrip(req)
+}
+
+// New 创建传递请求 ID 的 HTTP 客户端，timeout 为 0 表示不限时
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: &Transport{}, Timeout: timeout}
+}
@@ -0,0 +1,45 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// TestPropagateRequestID 上下文中的请求 ID 以请求头传给下游
+func TestPropagateRequestID(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(request.HeaderRequestID)
+	}))
+	defer srv.Close()
+
+	client := New(0)
+	do := func(ctx context.Context, header string) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if header != "" {
+			req.Header.Set(request.HeaderRequestID, header)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	do(request.WithRequestID(context.Background(), "req-1"), "")
+	if got != "req-1" {
+		t.Errorf("X-Request-ID = %q", got)
+	}
+	do(request.WithRequestID(context.Background(), "req-1"), "explicit")
+	if got != "explicit" {
+		t.Errorf("显式设置的请求头不应被覆盖，得到 %q", got)
+	}
+	do(context.Background(), "")
+	if got != "" {
+		t.Errorf("无请求 ID 时不应附加请求头，得到 %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+// Package i18n 提供轻量的多语言消息目录：按语言注册 key → 消息，查找时沿
+// "zh-TW → zh → 默认语言" 的回退链取第一条命中的翻译。
+//
+//	i18n.Register("en", map[string]string{"user.not_found": "user %d not found"})
+//	i18n.T(request.Locale(c), "user.not_found", id)
+//
+// 当前请求语言由 middleware.Locale 写入上下文（request.KeyLocale）。
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale 默认语言，回退链的最后一环
+const DefaultLocale = "zh-CN"
+
+var (
+	mu       sync.RWMutex
+	catalogs = make(map[string]map[string]string)
+)
+
+// Register 注册（合并）某语言的消息，同名 key 后注册的覆盖先注册的
+func Register(locale string, messages map[string]string) {
+	locale = Normalize(locale)
+	mu.Lock()
+	defer mu.Unlock()
+	catalog, ok := catalogs[locale]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		catalogs[locale] = catalog
+	}
+	for k, v := range messages {
+		catalog[k] = v
+	}
+}
+
+// Locales 已注册的语言，按名称排序
+func Locales() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		list = append(list, locale)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Has 是否注册了该语言（不含回退）
+func Has(locale string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalogs[Normalize(locale)]
+	return ok
+}
+
+// Lookup 沿回退链查找消息，均未命中时 ok 为 false
+func Lookup(locale, key string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, l := range Fallbacks(locale) {
+		if msg, ok := catalogs[l][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// T 翻译消息，args 非空时按 fmt.Sprintf 格式化；均未命中时返回 key 本身
+func T(locale, key string, args ...any) string {
+	msg, ok := Lookup(locale, key)
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Fallbacks 返回语言的回退链，如 "zh-TW" → [zh-TW zh zh-CN]，空语言只返回默认语言
+func Fallbacks(locale string) []string {
+	locale = Normalize(locale)
+	var chain []string
+	add := func(l string) {
+		for _, c := range chain {
+			if c == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+	if locale != "" {
+		add(locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			add(base)
+		}
+	}
+	add(DefaultLocale)
+	return chain
+}
+
+// Normalize 规范化语言标签：下划线转为连字符，语言小写、地区大写，如 "zh_cn" → "zh-CN"
+func Normalize(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	lang, region, ok := strings.Cut(locale, "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
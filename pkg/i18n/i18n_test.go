@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFallbacks 回退链：自身 → 基础语言 → 默认语言
+func TestFallbacks(t *testing.T) {
+	tests := map[string][]string{
+		"":      {DefaultLocale},
+		"en_us": {"en-US", "en", DefaultLocale},
+		"en":    {"en", DefaultLocale},
+		"zh-cn": {DefaultLocale, "zh"},
+		"zh-TW": {"zh-TW", "zh", DefaultLocale},
+	}
+	for locale, want := range tests {
+		if got := Fallbacks(locale); !reflect.DeepEqual(got, want) {
+			t.Errorf("Fallbacks(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+// TestT 沿回退链翻译并格式化，均未命中时返回 key
+func TestT(t *testing.T) {
+	Register(DefaultLocale, map[string]string{"test.hello": "你好，%s", "test.only_zh": "仅中文"})
+	Register("en", map[string]string{"test.hello": "Hello, %s"})
+	Register("en-GB", map[string]string{"test.hello": "Hiya, %s"})
+
+	tests := []struct{ locale, key, want string }{
+		{"en-GB", "test.hello", "Hiya, bob"},
+		{"en-US", "test.hello", "Hello, bob"},
+		{"fr", "test.hello", "你好，bob"},
+		{"en", "test.only_zh", "仅中文"},
+		{"en", "test.missing", "test.missing"},
+	}
+	for _, tt := range tests {
+		var got string
+		if tt.key == "test.hello" {
+			got = T(tt.locale, tt.key, "bob")
+		} else {
+			got = T(tt.locale, tt.key)
+		}
+		if got != tt.want {
+			t.Errorf("T(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+	if !Has("en-gb") || Has("fr") {
+		t.Error("Has 结果不符")
+	}
+}
@@ -0,0 +1,285 @@
+// Package image 提供图片的即时缩放、裁剪与格式转换：通过签名 URL 访问，处理结果写入缓存（磁盘或 Redis），
+// 并发处理数受限，避免大量缩略图请求耗尽 CPU 与内存。
+//
+//	<img src="{{ imageURL "avatars/1.jpg" "w" 96 "h" 96 "fit" "cover" }}">
+//	<!-- 输出: /img/avatars/1.jpg?fit=cover&h=96&w=96&s=... -->
+//
+// 内置 JPEG、PNG、GIF 编码，WebP 仅支持解码；WebP、AVIF 输出需通过 RegisterEncoder 接入编码器（通常依赖 cgo），
+// 注册后未指定格式的请求按 Accept 头自动选用。配置 image.enabled 后由路由注册处理器（见 Server）。
+package image
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码
+)
+
+// 处理限制，防止超大图片或构造的“解压炸弹”耗尽内存
+var (
+	MaxDimension    = 4096       // 输出宽高上限
+	MaxSourcePixels = 50_000_000 // 源图像素上限（约 7000×7000）
+	MaxSourceBytes  = 32 << 20   // 源文件大小上限
+)
+
+// DefaultQuality 未指定质量时的编码质量
+const DefaultQuality = 82
+
+var (
+	ErrInvalidOptions    = errors.New("图片处理参数无效")
+	ErrUnsupportedFormat = errors.New("不支持的图片格式")
+	ErrTooLarge          = errors.New("源图片过大")
+)
+
+// Fit 缩放方式
+type Fit string
+
+const (
+	FitContain Fit = "contain" // 等比缩放至完全放入目标尺寸（默认）
+	FitCover   Fit = "cover"   // 等比缩放至铺满目标尺寸，居中裁剪超出部分，适合缩略图
+)
+
+// Options 处理参数，宽高均为 0 时保持原尺寸（仅转换格式或质量），只给出一边时按比例计算另一边；不放大图片
+type Options struct {
+	Width   int
+	Height  int
+	Fit     Fit
+	Format  string // jpeg、png、gif、webp、avif，为空时按 Accept 头协商或保持原格式
+	Quality int    // 1-100，0 时使用 DefaultQuality
+}
+
+// ParseOptions 解析查询参数 w、h、fit、fm、q
+func ParseOptions(q url.Values) (Options, error) {
+	var o Options
+	var err error
+	if o.Width, err = parseInt(q.Get("w")); err != nil {
+		return o, err
+	}
+	if o.Height, err = parseInt(q.Get("h")); err != nil {
+		return o, err
+	}
+	if o.Quality, err = parseInt(q.Get("q")); err != nil {
+		return o, err
+	}
+	o.Fit = Fit(q.Get("fit"))
+	o.Format = normalizeFormat(q.Get("fm"))
+	return o, o.validate()
+}
+
+// parseInt 解析非负整数，空字符串为 0
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q 不是有效的数值", ErrInvalidOptions, s)
+	}
+	return n, nil
+}
+
+// validate 检查参数范围
+func (o Options) validate() error {
+	switch {
+	case o.Width > MaxDimension || o.Height > MaxDimension:
+		return fmt.Errorf("%w: 宽高不能超过 %d", ErrInvalidOptions, MaxDimension)
+	case o.Quality > 100:
+		return fmt.Errorf("%w: 质量须在 1-100 之间", ErrInvalidOptions)
+	case o.Fit != "" && o.Fit != FitContain && o.Fit != FitCover:
+		return fmt.Errorf("%w: 未知的缩放方式 %q", ErrInvalidOptions, o.Fit)
+	}
+	return nil
+}
+
+// Values 返回规范化的查询参数（省略默认值），用于生成 URL 与签名
+func (o Options) Values() url.Values {
+	q := url.Values{}
+	if o.Width > 0 {
+		q.Set("w", strconv.Itoa(o.Width))
+	}
+	if o.Height > 0 {
+		q.Set("h", strconv.Itoa(o.Height))
+	}
+	if o.Fit != "" && o.Fit != FitContain {
+		q.Set("fit", string(o.Fit))
+	}
+	if o.Format != "" {
+		q.Set("fm", normalizeFormat(o.Format))
+	}
+	if o.Quality > 0 {
+		q.Set("q", strconv.Itoa(o.Quality))
+	}
+	return q
+}
+
+// normalizeFormat 统一格式名称
+func normalizeFormat(f string) string {
+	f = strings.ToLower(f)
+	if f == "jpg" {
+		return "jpeg"
+	}
+	return f
+}
+
+// Encoder 图片编码器
+type Encoder func(w io.Writer, img image.Image, quality int) error
+
+type encoderEntry struct {
+	contentType string
+	encode      Encoder
+}
+
+var (
+	encMu    sync.RWMutex
+	encoders = map[string]encoderEntry{
+		"jpeg": {"image/jpeg", encodeJPEG},
+		"png":  {"image/png", func(w io.Writer, img image.Image, _ int) error { return png.Encode(w, img) }},
+		"gif":  {"image/gif", func(w io.Writer, img image.Image, _ int) error { return gif.Encode(w, img, nil) }},
+	}
+)
+
+// RegisterEncoder 注册（或替换）输出格式的编码器，如接入 WebP、AVIF 编码库：
+//
+//	image.RegisterEncoder("webp", "image/webp", func(w io.Writer, img stdimage.Image, q int) error {
+//	    return webp.Encode(w, img, &webp.Options{Quality: float32(q)})
+//	})
+func RegisterEncoder(format, contentType string, enc Encoder) {
+	encMu.Lock()
+	defer encMu.Unlock()
+	encoders[normalizeFormat(format)] = encoderEntry{contentType, enc}
+}
+
+// encoder 返回输出格式的编码器
+func encoder(format string) (encoderEntry, bool) {
+	encMu.RLock()
+	defer encMu.RUnlock()
+	e, ok := encoders[format]
+	return e, ok
+}
+
+// Supports 是否可以输出该格式
+func Supports(format string) bool {
+	_, ok := encoder(normalizeFormat(format))
+	return ok
+}
+
+// ContentType 返回格式对应的 MIME 类型，不支持时返回空字符串
+func ContentType(format string) string {
+	e, _ := encoder(normalizeFormat(format))
+	return e.contentType
+}
+
+// encodeJPEG JPEG 不支持透明，带透明通道的图片先合成到白色背景
+func encodeJPEG(w io.Writer, img image.Image, quality int) error {
+	if o, ok := img.(interface{ Opaque() bool }); !ok || !o.Opaque() {
+		bg := image.NewRGBA(img.Bounds())
+		draw.Draw(bg, bg.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(bg, bg.Bounds(), img, img.Bounds().Min, draw.Over)
+		img = bg
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// Process 读取源图片，按参数缩放、裁剪并编码，返回结果与 MIME 类型；
+// Format 为空时保持源格式（源格式无法编码时输出 JPEG）
+func Process(r io.Reader, opts Options) ([]byte, string, error) {
+	if err := opts.validate(); err != nil {
+		return nil, "", err
+	}
+	src, err := io.ReadAll(io.LimitReader(r, int64(MaxSourceBytes)+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(src) > MaxSourceBytes {
+		return nil, "", ErrTooLarge
+	}
+	cfg, srcFormat, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if cfg.Width*cfg.Height > MaxSourcePixels {
+		return nil, "", ErrTooLarge
+	}
+
+	format := normalizeFormat(opts.Format)
+	if format == "" {
+		format = srcFormat
+		if !Supports(format) {
+			format = "jpeg"
+		}
+	}
+	enc, ok := encoder(format)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	img = resize(img, opts)
+
+	quality := opts.Quality
+	if quality == 0 {
+		quality = DefaultQuality
+	}
+	var buf bytes.Buffer
+	if err := enc.encode(&buf, img, quality); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), enc.contentType, nil
+}
+
+// resize 按参数缩放与裁剪，尺寸不变时返回原图
+func resize(img image.Image, opts Options) image.Image {
+	b := img.Bounds()
+	sw, sh := float64(b.Dx()), float64(b.Dy())
+	w, h := float64(opts.Width), float64(opts.Height)
+	if (w == 0 && h == 0) || sw == 0 || sh == 0 {
+		return img
+	}
+	if w == 0 {
+		w = sw * h / sh
+	}
+	if h == 0 {
+		h = sh * w / sw
+	}
+
+	crop := b
+	var scale float64
+	if opts.Fit == FitCover {
+		scale = math.Min(1, math.Max(w/sw, h/sh))
+		// 目标框大于缩放后的图片时缩小目标框（不放大），保持目标宽高比
+		if k := math.Min(sw*scale/w, sh*scale/h); k < 1 {
+			w, h = w*k, h*k
+		}
+		cw, ch := int(math.Round(w/scale)), int(math.Round(h/scale))
+		x := b.Min.X + (b.Dx()-cw)/2
+		y := b.Min.Y + (b.Dy()-ch)/2
+		crop = image.Rect(x, y, x+cw, y+ch)
+	} else {
+		scale = math.Min(1, math.Min(w/sw, h/sh))
+		w, h = sw*scale, sh*scale
+	}
+
+	dw, dh := max(1, int(math.Round(w))), max(1, int(math.Round(h)))
+	if dw == b.Dx() && dh == b.Dy() && crop == b {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+	return dst
+}
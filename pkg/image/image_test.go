@@ -0,0 +1,130 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+)
+
+// newPNG 生成 w×h 的纯色 PNG
+func newPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestProcess 缩放方式、不放大与格式转换
+func TestProcess(t *testing.T) {
+	src := newPNG(t, 200, 100)
+	tests := []struct {
+		name       string
+		opts       Options
+		wantW      int
+		wantH      int
+		wantFormat string
+	}{
+		{"contain", Options{Width: 100, Height: 100}, 100, 50, "png"},
+		{"width only", Options{Width: 50}, 50, 25, "png"},
+		{"cover", Options{Width: 96, Height: 96, Fit: FitCover}, 96, 96, "png"},
+		{"no upscale", Options{Width: 400, Height: 400}, 200, 100, "png"},
+		{"cover no upscale", Options{Width: 300, Height: 150, Fit: FitCover}, 200, 100, "png"},
+		{"to jpeg", Options{Width: 20, Format: "jpg", Quality: 70}, 20, 10, "jpeg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, ct, err := Process(bytes.NewReader(src), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Width != tt.wantW || cfg.Height != tt.wantH || format != tt.wantFormat || ct != "image/"+tt.wantFormat {
+				t.Errorf("got %dx%d %s (%s), want %dx%d %s", cfg.Width, cfg.Height, format, ct, tt.wantW, tt.wantH, tt.wantFormat)
+			}
+		})
+	}
+
+	if _, _, err := Process(bytes.NewReader(src), Options{Format: "avif"}); err == nil {
+		t.Error("未注册 AVIF 编码器时应返回错误")
+	}
+	if _, _, err := Process(bytes.NewReader([]byte("not an image")), Options{}); err == nil {
+		t.Error("无法解析的源文件应返回错误")
+	}
+}
+
+// TestParseOptions 参数校验与规范化
+func TestParseOptions(t *testing.T) {
+	o, err := ParseOptions(url.Values{"w": {"300"}, "fit": {"contain"}, "fm": {"JPG"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := o.Values().Encode(); got != "fm=jpeg&w=300" {
+		t.Errorf("Values = %s", got)
+	}
+	for _, q := range []url.Values{{"w": {"-1"}}, {"w": {"99999"}}, {"q": {"101"}}, {"fit": {"stretch"}}} {
+		if _, err := ParseOptions(q); err == nil {
+			t.Errorf("%v 应返回错误", q)
+		}
+	}
+}
+
+// TestServer 签名校验、缓存命中与目录逃逸
+func TestServer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.png"), newPNG(t, 40, 20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(Dir(dir), "secret", WithCache(cache.NewMemory(), 0), WithConcurrency(1))
+	r := gin.New()
+	s.Mount(r)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	u := s.URL("/a.png", Options{Width: 10})
+	w := get(u)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || w.Header().Get("X-Image-Cache") != "MISS" {
+		t.Fatalf("%s: %d %v", u, w.Code, w.Header())
+	}
+	if cfg, _, _ := image.DecodeConfig(w.Body); cfg.Width != 10 {
+		t.Errorf("宽度 = %d", cfg.Width)
+	}
+	if w = get(u); w.Header().Get("X-Image-Cache") != "HIT" || w.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("第二次请求应命中缓存: %v", w.Header())
+	}
+
+	if w = get("/img/a.png?w=20&s=" + url.QueryEscape(s.sign("a.png", url.Values{"w": {"10"}}))); w.Code != http.StatusForbidden {
+		t.Errorf("篡改参数: %d", w.Code)
+	}
+	if w = get(s.URL("missing.png", Options{})); w.Code != http.StatusNotFound {
+		t.Errorf("源文件不存在: %d", w.Code)
+	}
+	if w = get(s.URL("../a.png", Options{})); w.Code == http.StatusOK {
+		t.Errorf("目录逃逸: %d", w.Code)
+	}
+}
@@ -0,0 +1,267 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/storage"
+)
+
+// Source 源图片读取，path 为相对路径（已去除路由前缀）；文件不存在时返回 fs.ErrNotExist
+type Source interface {
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+}
+
+// SourceFunc 函数形式的 Source，便于接入对象存储等自定义存储
+type SourceFunc func(ctx context.Context, path string) (io.ReadCloser, error)
+
+// Open 实现 Source
+func (f SourceFunc) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	return f(ctx, path)
+}
+
+// Dir 以本地目录（如上传目录 storage/uploads）作为源，路径不能逃出该目录
+func Dir(root string) Source {
+	return SourceFunc(func(_ context.Context, path string) (io.ReadCloser, error) {
+		return os.OpenInRoot(root, strings.TrimPrefix(path, "/"))
+	})
+}
+
+// Server 图片处理服务：校验签名、读取缓存，未命中时限流处理并写入缓存
+type Server struct {
+	source  Source
+	secret  []byte
+	prefix  string
+	cache   cache.Store
+	ttl     time.Duration
+	maxAge  int
+	limiter chan struct{}
+}
+
+// Option 图片处理服务配置选项
+type Option func(*Server)
+
+// WithPrefix 路由前缀（默认 /img），URL 生成与 Mount 共用
+func WithPrefix(prefix string) Option {
+	return func(s *Server) { s.prefix = "/" + strings.Trim(prefix, "/") }
+}
+
+// WithCache 处理结果的缓存与有效期，ttl <= 0 表示永不过期；未设置时不缓存
+func WithCache(store cache.Store, ttl time.Duration) Option {
+	return func(s *Server) { s.cache, s.ttl = store, ttl }
+}
+
+// WithConcurrency 同时处理的图片数上限（默认为 CPU 核数），超出的请求排队等待
+func WithConcurrency(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.limiter = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithMaxAge 响应的 Cache-Control max-age（秒，默认 30 天）
+func WithMaxAge(seconds int) Option {
+	return func(s *Server) { s.maxAge = seconds }
+}
+
+// NewServer 创建图片处理服务，secret 用于 URL 签名
+func NewServer(source Source, secret string, opts ...Option) *Server {
+	s := &Server{
+		source:  source,
+		secret:  []byte(secret),
+		prefix:  "/img",
+		maxAge:  30 * 24 * 3600,
+		limiter: make(chan struct{}, runtime.NumCPU()),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Mount 注册 GET <prefix>/*path
+func (s *Server) Mount(r gin.IRoutes) {
+	r.GET(s.prefix+"/*path", s.Handle)
+}
+
+// sign 计算路径与参数的签名
+func (s *Server) sign(path string, q url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.TrimPrefix(path, "/") + "?" + q.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// URL 生成带签名的图片地址，path 为源图片的相对路径
+func (s *Server) URL(path string, opts Options) string {
+	path = strings.TrimPrefix(path, "/")
+	q := opts.Values()
+	sig := s.sign(path, q)
+	q.Set("s", sig)
+	return s.prefix + "/" + (&url.URL{Path: path}).EscapedPath() + "?" + q.Encode()
+}
+
+// Handle 处理图片请求：签名无效返回 403，参数无效或源文件无法解析返回 400，源文件不存在返回 404
+func (s *Server) Handle(c *gin.Context) {
+	path := strings.TrimPrefix(c.Param("path"), "/")
+	q := c.Request.URL.Query()
+	sig := q.Get("s")
+	q.Del("s")
+	if path == "" || !hmac.Equal([]byte(sig), []byte(s.sign(path, q))) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	opts, err := ParseOptions(q)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	// 未指定格式时按 Accept 协商已注册的现代格式，结果随 Accept 变化
+	if opts.Format == "" {
+		c.Header("Vary", "Accept")
+		opts.Format = negotiate(c.GetHeader("Accept"))
+	}
+
+	key := "image:" + path + "?" + opts.Values().Encode()
+	if s.cache != nil {
+		b, ok, err := s.cache.Get(c.Request.Context(), key)
+		if err != nil {
+			logger.Warnf("读取图片缓存失败: %v", err)
+		}
+		if ct, data, found := bytes.Cut(b, []byte{0}); ok && found {
+			s.write(c, string(ct), data, "HIT")
+			return
+		}
+	}
+
+	b, ct, err := s.process(c.Request.Context(), path, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			c.AbortWithStatus(http.StatusNotFound)
+		case errors.Is(err, ErrInvalidOptions), errors.Is(err, ErrUnsupportedFormat), errors.Is(err, ErrTooLarge):
+			c.AbortWithStatus(http.StatusBadRequest)
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			c.AbortWithStatus(http.StatusServiceUnavailable)
+		default:
+			_ = c.Error(err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}
+		return
+	}
+	if s.cache != nil {
+		// 缓存值为 "<MIME 类型>\x00<图片数据>"
+		if err := s.cache.Set(c.Request.Context(), key, append([]byte(ct+"\x00"), b...), s.ttl); err != nil {
+			logger.Warnf("写入图片缓存失败: %v", err)
+		}
+	}
+	s.write(c, ct, b, "MISS")
+}
+
+// process 在并发限制内读取并处理源图片
+func (s *Server) process(ctx context.Context, path string, opts Options) ([]byte, string, error) {
+	select {
+	case s.limiter <- struct{}{}:
+		defer func() { <-s.limiter }()
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	}
+	rc, err := s.source.Open(ctx, path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rc.Close()
+	return Process(rc, opts)
+}
+
+// write 输出图片，X-Image-Cache 标明是否命中缓存
+func (s *Server) write(c *gin.Context, contentType string, b []byte, status string) {
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(s.maxAge))
+	c.Header("X-Image-Cache", status)
+	c.Data(http.StatusOK, contentType, b)
+}
+
+// negotiate 按 Accept 头选择已注册编码器的 AVIF 或 WebP，否则返回空（保持源格式）
+func negotiate(accept string) string {
+	for _, f := range []string{"avif", "webp"} {
+		if strings.Contains(accept, "image/"+f) && Supports(f) {
+			return f
+		}
+	}
+	return ""
+}
+
+var defaultServer atomic.Pointer[Server]
+
+// SetDefault 设置 URL 使用的全局服务（由路由在启用 image.enabled 时调用）
+func SetDefault(s *Server) {
+	defaultServer.Store(s)
+}
+
+// Default 返回全局服务，未启用时返回 nil
+func Default() *Server {
+	return defaultServer.Load()
+}
+
+// URL 使用全局服务生成签名图片地址，未启用时返回源路径
+func URL(path string, opts Options) string {
+	s := Default()
+	if s == nil {
+		return path
+	}
+	return s.URL(path, opts)
+}
+
+// NewServerFromConfig 按 image.* 配置创建服务，redis 缓存使用全局 Redis 配置；
+// 配置了 image.disk 时从该存储读取源图片（需先初始化全局存储），否则读取 image.source 目录
+func NewServerFromConfig(cfg *config.ImageConfig, redisCfg *config.RedisConfig) (*Server, error) {
+	if cfg.Secret == "" {
+		return nil, errors.New("启用图片处理需要配置 image.secret")
+	}
+	opts := []Option{
+		WithPrefix(cfg.Route),
+		WithConcurrency(cfg.Concurrency),
+		WithMaxAge(cfg.MaxAge),
+	}
+	ttl := time.Duration(cfg.CacheTTL) * time.Second
+	switch cfg.Cache {
+	case "":
+	case "disk":
+		opts = append(opts, WithCache(cache.NewDisk(cfg.CacheDir), ttl))
+	case "redis":
+		opts = append(opts, WithCache(cache.NewRedisFromConfig(redisCfg, ""), ttl))
+	case "memory":
+		opts = append(opts, WithCache(cache.NewMemory(), ttl))
+	default:
+		return nil, fmt.Errorf("未知的图片缓存类型: %s", cfg.Cache)
+	}
+	source := Dir(cfg.Source)
+	if cfg.Disk != "" {
+		disk, err := storage.Disk(cfg.Disk)
+		if err != nil {
+			return nil, err
+		}
+		source = SourceFunc(disk.Get)
+	}
+	return NewServer(source, cfg.Secret, opts...), nil
+}
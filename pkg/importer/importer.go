@@ -0,0 +1,393 @@
+// Package importer 提供 CSV 与 XLSX 的数据导入：按表头将各列映射到结构体字段，逐行转换并校验，
+// 通过校验的行分批交给处理函数写入，失败的行连同原因汇总为可下载的错误报告。
+// 包名不使用 import（Go 关键字）。
+//
+//	type OrderRow struct {
+//	    No     string  `import:"订单号|no,required" validate:"required"`
+//	    Amount float64 `import:"金额|amount"`
+//	    Paid   bool    `import:"已支付"`
+//	}
+//
+//	im := importer.New(func(ctx context.Context, rows []OrderRow) error {
+//	    return database.BulkInsert(db.WithContext(ctx), toOrders(rows))
+//	}, importer.WithDryRun(c.Query("dry_run") == "1"))
+//	report, err := im.RunUpload(ctx, fh)
+//
+// 表头匹配 import 标签中以 | 分隔的任一名称（同时按 WithLocale 的语言以 i18n 翻译后匹配），未设置标签时匹配
+// json 标签名与字段名；匹配忽略大小写、空白以及 _、- 分隔符。标签中的 required 表示文件必须包含该列，
+// import:"-" 的字段不参与导入。大文件通过 Go 提交到工作池后台执行，以 Lookup 查询进度与报告。
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/export"
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+// DefaultMaxErrors 报告中保留的失败行数上限的默认值
+const DefaultMaxErrors = 1000
+
+var (
+	// ErrEmpty 文件中没有表头
+	ErrEmpty = errors.New("导入文件为空")
+	// ErrMissingColumn 文件缺少必需的列
+	ErrMissingColumn = errors.New("导入文件缺少必需的列")
+)
+
+// Handler 处理一批通过校验的行，返回错误时该批各行均记为失败
+type Handler[T any] func(ctx context.Context, rows []T) error
+
+// Progress 导入进度
+type Progress struct {
+	Processed int `json:"processed"` // 已处理的数据行数
+	Succeeded int `json:"succeeded"` // 成功的行数
+	Failed    int `json:"failed"`    // 失败的行数
+}
+
+// options 导入选项
+type options struct {
+	batchSize int
+	dryRun    bool
+	maxErrors int
+	locale    string
+	location  *time.Location
+	progress  func(Progress)
+}
+
+// Option 导入选项
+type Option func(*options)
+
+// WithBatchSize 每批交给处理函数的行数，默认 database.DefaultBatchSize
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithDryRun 试运行：只转换与校验，不调用处理函数，报告中的成功行数为可导入的行数
+func WithDryRun(on bool) Option {
+	return func(o *options) { o.dryRun = on }
+}
+
+// WithMaxErrors 报告中保留的失败行数上限，默认 DefaultMaxErrors；超出后仍计入失败数，但不再保留明细
+func WithMaxErrors(n int) Option {
+	return func(o *options) { o.maxErrors = n }
+}
+
+// WithLocale 表头与错误报告使用的语言，import 标签中的名称按该语言以 i18n 翻译后匹配
+func WithLocale(locale string) Option {
+	return func(o *options) { o.locale = locale }
+}
+
+// WithLocation 不含时区的时间值所在的时区，默认 time.Local
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) { o.location = loc }
+}
+
+// WithProgress 每处理完一批后回调进度
+func WithProgress(fn func(Progress)) Option {
+	return func(o *options) { o.progress = fn }
+}
+
+// Importer 将表格导入为 T（结构体）的导入器，可重复使用
+type Importer[T any] struct {
+	handler Handler[T]
+	opts    options
+}
+
+// New 创建导入器，handler 为 nil 时等同于试运行
+func New[T any](handler Handler[T], opts ...Option) *Importer[T] {
+	o := options{batchSize: database.DefaultBatchSize, maxErrors: DefaultMaxErrors, location: time.Local}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Importer[T]{handler: handler, opts: o}
+}
+
+// RowError 单元格或行的错误，Column 为空时表示整行的错误（如写入失败）
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error 实现 error
+func (e RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("第 %d 行: %s", e.Row, e.Message)
+	}
+	return fmt.Sprintf("第 %d 行 %s: %s", e.Row, e.Column, e.Message)
+}
+
+// Report 导入结果
+type Report struct {
+	Total     int        `json:"total"`     // 数据行数（不含表头与空行）
+	Succeeded int        `json:"succeeded"` // 成功的行数，试运行时为可导入的行数
+	Failed    int        `json:"failed"`    // 失败的行数
+	DryRun    bool       `json:"dry_run"`
+	Errors    []RowError `json:"errors"`              // 失败明细，最多保留 WithMaxErrors 行
+	Truncated bool       `json:"truncated,omitempty"` // 失败行超出上限，明细不完整
+
+	locale string
+	header []string
+	failed []failedRow
+}
+
+// failedRow 失败行的原始内容
+type failedRow struct {
+	line   int
+	record []string
+}
+
+// OK 是否全部成功
+func (r *Report) OK() bool {
+	return r.Failed == 0
+}
+
+// pending 通过校验、等待写入的行
+type pending[T any] struct {
+	line   int
+	record []string
+	value  T
+}
+
+// RunUpload 打开上传的文件并导入
+func (im *Importer[T]) RunUpload(ctx context.Context, fh *multipart.FileHeader) (*Report, error) {
+	r, err := Open(fh)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return im.Run(ctx, r)
+}
+
+// Run 读取 r 导入。第一个非空行为表头；返回的 error 表示文件本身的问题（格式错误、缺少必需列）
+// 或 ctx 已取消，行的错误记录在报告中
+func (im *Importer[T]) Run(ctx context.Context, r Reader) (*Report, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("导入目标须为结构体，得到 %s", t)
+	}
+	report := &Report{DryRun: im.opts.dryRun || im.handler == nil, locale: im.opts.locale}
+
+	var cols []column
+	for cols == nil {
+		_, record, err := r.Read()
+		if err == io.EOF {
+			return nil, ErrEmpty
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(record) {
+			continue
+		}
+		report.header = slices.Clone(record)
+		var missing []string
+		cols, missing = mapColumns(report.header, fields(t, nil, ""), im.opts.locale)
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, strings.Join(missing, "、"))
+		}
+	}
+
+	batch := make([]pending[T], 0, im.opts.batchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		line, record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		if blank(record) {
+			continue
+		}
+		report.Total++
+		record = slices.Clone(record)
+
+		var v T
+		if errs := im.decode(&v, cols, record, line); len(errs) > 0 {
+			im.fail(report, line, record, errs)
+			continue
+		}
+		batch = append(batch, pending[T]{line: line, record: record, value: v})
+		if len(batch) == im.opts.batchSize {
+			im.flush(ctx, report, batch)
+			im.notify(report)
+			batch = batch[:0]
+		}
+	}
+	im.flush(ctx, report, batch)
+	im.notify(report)
+	return report, nil
+}
+
+// decode 转换一行并校验，返回该行的错误
+func (im *Importer[T]) decode(v *T, cols []column, record []string, line int) []RowError {
+	var errs []RowError
+	rv := reflect.ValueOf(v).Elem()
+	for i, col := range cols {
+		if col.field == nil || i >= len(record) {
+			continue
+		}
+		if err := setValue(fieldValue(rv, col.field.index), record[i], im.opts.location); err != nil {
+			errs = append(errs, RowError{Row: line, Column: col.title, Message: err.Error()})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	err := validator.Validate(v)
+	if err == nil {
+		return nil
+	}
+	var fes validator.FieldErrors
+	if !errors.As(err, &fes) {
+		return []RowError{{Row: line, Message: err.Error()}}
+	}
+	for _, fe := range fes {
+		e := RowError{Row: line, Message: fe.Message}
+		for _, col := range cols {
+			if col.field != nil && col.field.name == fe.Field {
+				e.Column = col.title
+				break
+			}
+		}
+		if e.Column == "" {
+			e.Message = fe.Error()
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// flush 将一批行交给处理函数
+func (im *Importer[T]) flush(ctx context.Context, report *Report, batch []pending[T]) {
+	if len(batch) == 0 {
+		return
+	}
+	if !report.DryRun {
+		rows := make([]T, len(batch))
+		for i, p := range batch {
+			rows[i] = p.value
+		}
+		if err := im.handler(ctx, rows); err != nil {
+			for _, p := range batch {
+				im.fail(report, p.line, p.record, []RowError{{Row: p.line, Message: err.Error()}})
+			}
+			return
+		}
+	}
+	report.Succeeded += len(batch)
+}
+
+// notify 回调进度
+func (im *Importer[T]) notify(report *Report) {
+	if im.opts.progress != nil {
+		im.opts.progress(Progress{Processed: report.Succeeded + report.Failed, Succeeded: report.Succeeded, Failed: report.Failed})
+	}
+}
+
+// fail 记录失败行，超出上限时只计数
+func (im *Importer[T]) fail(report *Report, line int, record []string, errs []RowError) {
+	report.Failed++
+	if im.opts.maxErrors >= 0 && len(report.failed) >= im.opts.maxErrors {
+		report.Truncated = true
+		return
+	}
+	report.Errors = append(report.Errors, errs...)
+	report.failed = append(report.failed, failedRow{line: line, record: record})
+}
+
+// blank 是否为空行
+func blank(record []string) bool {
+	for _, s := range record {
+		if strings.TrimSpace(s) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrorReport 失败行的错误报告：原表头之后追加行号与错误原因两列，各失败行保留原始内容，
+// 修正后可直接重新导入。通过 response.ExportCSV / ExportXLSX 下载：
+//
+//	return response.ExportXLSX(c, "导入错误.xlsx", report.ErrorReport())
+func (r *Report) ErrorReport() export.Exporter {
+	return errorReport{r}
+}
+
+// errorReport 错误报告导出
+type errorReport struct {
+	r *Report
+}
+
+// Export 实现 export.Exporter，locale 为空时使用导入时的语言
+func (e errorReport) Export(ctx context.Context, w export.Writer, locale string) error {
+	if locale == "" {
+		locale = e.r.locale
+	}
+	headers := make([]export.Header, 0, len(e.r.header)+2)
+	for _, title := range e.r.header {
+		headers = append(headers, export.Header{Title: title})
+	}
+	headers = append(headers,
+		export.Header{Title: translate(locale, "import.row", "行号")},
+		export.Header{Title: translate(locale, "import.error", "错误原因"), Width: 60},
+	)
+	if err := w.WriteHeader(headers); err != nil {
+		return err
+	}
+
+	messages := make(map[int][]string, len(e.r.failed))
+	for _, re := range e.r.Errors {
+		msg := re.Message
+		if re.Column != "" {
+			msg = re.Column + ": " + msg
+		}
+		messages[re.Row] = append(messages[re.Row], msg)
+	}
+	values := make([]any, len(e.r.header)+2)
+	for _, f := range e.r.failed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		clear(values)
+		for i := range e.r.header {
+			if i < len(f.record) {
+				values[i] = f.record[i]
+			}
+		}
+		values[len(e.r.header)] = f.line
+		values[len(e.r.header)+1] = strings.Join(messages[f.line], "; ")
+		if err := w.WriteRow(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translate 按语言翻译，未注册时使用默认文案
+func translate(locale, key, fallback string) string {
+	if s, ok := i18n.Lookup(locale, key); ok {
+		return s
+	}
+	return fallback
+}
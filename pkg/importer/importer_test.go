@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
+	"github.com/gorilla-go/go-framework/pkg/export"
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+type orderRow struct {
+	No       string     `import:"订单号|no,required"`
+	Amount   float64    `import:"金额"`
+	Quantity int        `json:"quantity"`
+	Paid     bool       `import:"已支付"`
+	PaidAt   *time.Time `import:"支付时间"`
+	Internal string     `import:"-"`
+}
+
+type validatorFunc func(any) error
+
+func (f validatorFunc) Validate(i any) error { return f(i) }
+
+// collect 返回收集各批数据的处理函数
+func collect(out *[]orderRow) Handler[orderRow] {
+	return func(_ context.Context, rows []orderRow) error {
+		*out = append(*out, rows...)
+		return nil
+	}
+}
+
+func csvReader(s string) Reader {
+	return NewCSVReader(strings.NewReader(s))
+}
+
+// TestRunCSV 表头匹配别名、json 名与忽略大小写，转换各类型，跳过空行，去除 BOM
+func TestRunCSV(t *testing.T) {
+	data := "\ufeff订单号,金额, QUANTITY ,已支付,支付时间,备注\n" +
+		"A001,12.5,3,是,2024-03-01 12:30:00,x\n" +
+		",,,,,\n" +
+		"A002,8,1.0E+1,0,45352.5208333333,\n"
+	var rows []orderRow
+	report, err := New(collect(&rows), WithBatchSize(1), WithLocation(time.UTC)).Run(context.Background(), csvReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 2 || report.Succeeded != 2 || !report.OK() || len(rows) != 2 {
+		t.Fatalf("report = %+v, rows = %+v", report, rows)
+	}
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	if r := rows[0]; r.No != "A001" || r.Amount != 12.5 || r.Quantity != 3 || !r.Paid || r.PaidAt == nil || !r.PaidAt.Equal(want) {
+		t.Errorf("rows[0] = %+v", r)
+	}
+	if r := rows[1]; r.Quantity != 10 || r.Paid || r.PaidAt == nil || !r.PaidAt.Equal(want) {
+		t.Errorf("rows[1] = %+v（Excel 日期序列号应转为时间）", r)
+	}
+}
+
+// TestRunErrors 转换错误、校验错误与处理函数错误记入报告，错误报告保留原始内容
+func TestRunErrors(t *testing.T) {
+	validator.Register(validatorFunc(func(v any) error {
+		if r := v.(*orderRow); r.Amount < 0 {
+			return validator.FieldErrors{{Field: "Amount", Tag: "min", Message: "不能为负数"}}
+		}
+		return nil
+	}))
+	defer validator.Register(nil)
+
+	data := "no,金额,quantity\n" +
+		"A001,abc,x\n" +
+		"A002,-1,1\n" +
+		"A003,1,1\n" +
+		"FAIL,1,1\n"
+	handler := func(_ context.Context, rows []orderRow) error {
+		for _, r := range rows {
+			if r.No == "FAIL" {
+				return errors.New("写入失败")
+			}
+		}
+		return nil
+	}
+	report, err := New(handler, WithBatchSize(1)).Run(context.Background(), csvReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 4 || report.Succeeded != 1 || report.Failed != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+	want := []RowError{
+		{Row: 2, Column: "金额", Message: "应为数字"},
+		{Row: 2, Column: "quantity", Message: "应为整数"},
+		{Row: 3, Column: "金额", Message: "不能为负数"},
+		{Row: 5, Message: "写入失败"},
+	}
+	if len(report.Errors) != len(want) {
+		t.Fatalf("Errors = %+v", report.Errors)
+	}
+	for i, e := range want {
+		if report.Errors[i] != e {
+			t.Errorf("Errors[%d] = %+v, want %+v", i, report.Errors[i], e)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := export.CSV(context.Background(), &buf, report.ErrorReport(), "", export.WithCSVBOM(false)); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "no,金额,quantity,行号,错误原因\n" +
+		"A001,abc,x,2,金额: 应为数字; quantity: 应为整数\n" +
+		"A002,'-1,1,3,金额: 不能为负数\n" +
+		"FAIL,1,1,5,写入失败\n"
+	if buf.String() != wantCSV {
+		t.Errorf("错误报告 =\n%s\nwant\n%s", buf.String(), wantCSV)
+	}
+
+	// 导出时为防御公式注入加上的单引号在重新导入时去除
+	r := csvReader(buf.String())
+	r.Read()
+	r.Read()
+	if _, record, _ := r.Read(); record[1] != "-1" {
+		t.Errorf("重新导入 = %q", record)
+	}
+}
+
+// TestRunDryRun 试运行只校验不写入
+func TestRunDryRun(t *testing.T) {
+	called := false
+	handler := func(context.Context, []orderRow) error { called = true; return nil }
+	report, err := New(handler, WithDryRun(true)).Run(context.Background(), csvReader("no\nA001\nA002\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called || !report.DryRun || report.Succeeded != 2 {
+		t.Errorf("report = %+v, called = %v", report, called)
+	}
+}
+
+// TestRunFileErrors 空文件与缺少必需列返回错误，失败明细超出上限时截断
+func TestRunFileErrors(t *testing.T) {
+	im := New[orderRow](nil)
+	if _, err := im.Run(context.Background(), csvReader("\n\n")); !errors.Is(err, ErrEmpty) {
+		t.Errorf("空文件: %v", err)
+	}
+	if _, err := im.Run(context.Background(), csvReader("金额\n1\n")); !errors.Is(err, ErrMissingColumn) || !strings.Contains(err.Error(), "订单号") {
+		t.Errorf("缺少必需列: %v", err)
+	}
+
+	report, err := New[orderRow](nil, WithMaxErrors(1)).Run(context.Background(), csvReader("no,金额\nA,x\nB,y\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Failed != 2 || len(report.Errors) != 1 || !report.Truncated {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+// TestLocale 表头按语言翻译后匹配
+func TestLocale(t *testing.T) {
+	i18n.Register("en", map[string]string{"订单号": "Order No"})
+	var rows []orderRow
+	report, err := New(collect(&rows), WithLocale("en")).Run(context.Background(), csvReader("order no\nA001\n"))
+	if err != nil || report.Succeeded != 1 || rows[0].No != "A001" {
+		t.Errorf("report = %+v, rows = %+v, err = %v", report, rows, err)
+	}
+}
+
+// TestXLSX 读取 export 写出的 XLSX：内联字符串、数字、布尔与日期
+func TestXLSX(t *testing.T) {
+	paidAt := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	type src struct {
+		No     string
+		Amount float64
+		Paid   bool
+		PaidAt time.Time
+	}
+	table := export.New(export.Values([]src{{"A001", 12.5, true, paidAt}, {"A002", 3, false, time.Time{}}}),
+		export.Col("订单号", func(s src) any { return s.No }),
+		export.Col("金额", func(s src) any { return s.Amount }),
+		export.Col("已支付", func(s src) any { return s.Paid }),
+		export.Col("支付时间", func(s src) any { return s.PaidAt }),
+	)
+	var buf bytes.Buffer
+	if err := export.XLSX(context.Background(), &buf, table, ""); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewXLSXReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []orderRow
+	report, err := New(collect(&rows), WithLocation(time.UTC)).Run(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Succeeded != 2 || len(rows) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if r := rows[0]; r.No != "A001" || r.Amount != 12.5 || !r.Paid || r.PaidAt == nil || !r.PaidAt.Equal(paidAt) {
+		t.Errorf("rows[0] = %+v", r)
+	}
+	if r := rows[1]; r.No != "A002" || r.Paid || r.PaidAt != nil {
+		t.Errorf("rows[1] = %+v", r)
+	}
+}
+
+// TestXLSXSharedStrings 共享字符串（含富文本）与跳过的空单元格
+func TestXLSXSharedStrings(t *testing.T) {
+	if got := columnIndex("AA3"); got != 26 {
+		t.Errorf("columnIndex(AA3) = %d", got)
+	}
+	dec := xml.NewDecoder(strings.NewReader(`<sst><si><t>订单号</t></si><si><r><t>A</t></r><r><t>001</t></r><rPh><t>x</t></rPh></si></sst>`))
+	var got []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "si" {
+			s, err := richText(dec, "si")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, s)
+		}
+	}
+	if strings.Join(got, ",") != "订单号,A001" {
+		t.Errorf("共享字符串 = %q", got)
+	}
+
+	xr := &XLSXReader{strings: got}
+	xr.dec = xml.NewDecoder(strings.NewReader(`<sheetData><row r="4"><c r="B4" t="s"><v>1</v></c><c r="D4"><v>7</v></c></row></sheetData>`))
+	line, record, err := xr.Read()
+	if err != nil || line != 4 || strings.Join(record, "|") != "|A001||7" {
+		t.Errorf("Read = %d %q %v", line, record, err)
+	}
+}
+
+// upload 构造上传文件
+func upload(t *testing.T, name, content string) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile("file", name)
+	part.Write([]byte(content))
+	w.Close()
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	return req.MultipartForm.File["file"][0]
+}
+
+// TestGo 后台导入：提交到工作池，通过 Lookup 查询进度与报告
+func TestGo(t *testing.T) {
+	pool := concurrent.NewPool(concurrent.WithWorkers(1))
+	defer pool.Stop(context.Background())
+
+	var (
+		rows     []orderRow
+		progress []Progress
+	)
+	im := New(collect(&rows), WithBatchSize(2), WithProgress(func(p Progress) { progress = append(progress, p) }))
+	if _, err := im.Go(pool, upload(t, "orders.txt", "")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("不支持的格式: %v", err)
+	}
+	job, err := im.Go(pool, upload(t, "orders.csv", "no\nA\nB\nC\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-job.Done()
+	found, ok := Lookup(job.ID)
+	if !ok || found != job {
+		t.Fatal("Lookup 未找到任务")
+	}
+	state := job.State()
+	if state.Status != StatusSucceeded || state.Report == nil || state.Report.Succeeded != 3 || state.Progress.Processed != 3 {
+		t.Errorf("state = %+v", state)
+	}
+	if len(progress) != 2 || progress[0].Processed != 2 || len(rows) != 3 {
+		t.Errorf("progress = %+v, rows = %d", progress, len(rows))
+	}
+
+	job, err = im.Go(pool, upload(t, "bad.csv", "金额\n1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-job.Done()
+	if s := job.State(); s.Status != StatusFailed || !errors.Is(job.Err(), ErrMissingColumn) {
+		t.Errorf("state = %+v", s)
+	}
+}
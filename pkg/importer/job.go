@@ -0,0 +1,216 @@
+package importer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
+)
+
+// JobTTL 已结束的任务保留的时长，超过后 Lookup 不再返回
+const JobTTL = time.Hour
+
+// Status 后台导入任务的状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 排队中
+	StatusRunning   Status = "running"   // 执行中
+	StatusSucceeded Status = "succeeded" // 已完成（行的错误见报告）
+	StatusFailed    Status = "failed"    // 文件本身的问题或执行中断
+)
+
+// Job 后台导入任务
+type Job struct {
+	ID string
+
+	mu       sync.RWMutex
+	status   Status
+	progress Progress
+	report   *Report
+	err      error
+	finished time.Time
+	done     chan struct{}
+}
+
+// JobState 任务状态快照，可直接作为查询接口的响应
+type JobState struct {
+	ID       string   `json:"id"`
+	Status   Status   `json:"status"`
+	Progress Progress `json:"progress"`
+	Report   *Report  `json:"report,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// State 返回任务状态快照
+func (j *Job) State() JobState {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	s := JobState{ID: j.ID, Status: j.status, Progress: j.progress, Report: j.report}
+	if j.err != nil {
+		s.Error = j.err.Error()
+	}
+	return s
+}
+
+// Report 返回导入结果，未结束时为 nil
+func (j *Job) Report() *Report {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.report
+}
+
+// Err 返回任务失败的原因
+func (j *Job) Err() error {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.err
+}
+
+// Done 任务结束时关闭
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// setProgress 更新进度
+func (j *Job) setProgress(p Progress) {
+	j.mu.Lock()
+	j.status, j.progress = StatusRunning, p
+	j.mu.Unlock()
+}
+
+// finish 记录结果并标记结束
+func (j *Job) finish(report *Report, err error) {
+	j.mu.Lock()
+	j.report, j.err, j.finished = report, err, time.Now()
+	j.status = StatusSucceeded
+	if err != nil {
+		j.status = StatusFailed
+	}
+	j.mu.Unlock()
+	close(j.done)
+}
+
+var (
+	jobsMu sync.Mutex
+	jobs   = make(map[string]*Job)
+)
+
+// Lookup 按 ID 查找后台导入任务
+func Lookup(id string) (*Job, bool) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	j, ok := jobs[id]
+	return j, ok
+}
+
+// register 登记任务，并清理超过 JobTTL 的已结束任务
+func register(j *Job) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	for id, old := range jobs {
+		old.mu.RLock()
+		expired := !old.finished.IsZero() && time.Since(old.finished) > JobTTL
+		old.mu.RUnlock()
+		if expired {
+			delete(jobs, id)
+		}
+	}
+	jobs[j.ID] = j
+}
+
+// unregister 移除任务
+func unregister(j *Job) {
+	jobsMu.Lock()
+	delete(jobs, j.ID)
+	jobsMu.Unlock()
+}
+
+// Go 将上传的文件转存到临时目录后提交到工作池（nil 时使用全局工作池）后台导入，立即返回任务。
+// 请求结束后上传的临时文件即被删除，因此须先转存；导入结束后转存的文件随之删除。
+// 工作池队列已满或已停止时返回 concurrent.ErrQueueFull / ErrPoolClosed：
+//
+//	job, err := im.Go(nil, fh)
+//	// 之后在查询接口中：
+//	job, ok := importer.Lookup(id)
+//	response.Success(c, job.State())
+func (im *Importer[T]) Go(pool *concurrent.Pool, fh *multipart.FileHeader) (*Job, error) {
+	if pool == nil {
+		if pool = concurrent.Default(); pool == nil {
+			return nil, concurrent.ErrPoolClosed
+		}
+	}
+	if _, err := FormatOf(fh.Filename); err != nil {
+		return nil, err
+	}
+	name, err := spool(fh)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{ID: newJobID(), status: StatusPending, done: make(chan struct{})}
+	register(job)
+	run := *im
+	run.opts.progress = func(p Progress) {
+		job.setProgress(p)
+		if im.opts.progress != nil {
+			im.opts.progress(p)
+		}
+	}
+	err = pool.Go(func(ctx context.Context) error {
+		defer os.Remove(name)
+		job.setProgress(Progress{})
+		r, err := OpenFile(name)
+		if err != nil {
+			job.finish(nil, err)
+			return err
+		}
+		defer r.Close()
+		report, err := run.Run(ctx, r)
+		job.finish(report, err)
+		return err
+	})
+	if err != nil {
+		unregister(job)
+		os.Remove(name)
+		return nil, err
+	}
+	return job, nil
+}
+
+// spool 将上传的文件复制到临时文件，保留扩展名以便识别格式
+func spool(fh *multipart.FileHeader) (string, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	dst, err := os.CreateTemp("", "import-*"+filepath.Ext(fh.Filename))
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(dst, src)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("转存上传文件失败: %w", err)
+	}
+	return dst.Name(), nil
+}
+
+// newJobID 生成随机的任务 ID
+func newJobID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
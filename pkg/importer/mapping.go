@@ -0,0 +1,266 @@
+package importer
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+)
+
+// field 可导入的结构体字段
+type field struct {
+	index    []int
+	name     string   // 字段路径，与 validator.FieldError.Field 对应
+	aliases  []string // 可匹配的表头
+	required bool
+}
+
+// column 表头中的一列及其映射的字段
+type column struct {
+	title string
+	field *field
+}
+
+// fields 解析结构体的可导入字段，嵌入的匿名结构体展开
+func fields(t reflect.Type, index []int, prefix string) []*field {
+	var out []*field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("import")
+		if tag == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		if sf.Anonymous && !hasTag {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				out = append(out, fields(ft, idx, prefix)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		f := &field{index: idx, name: prefix + sf.Name}
+		names, opts, _ := strings.Cut(tag, ",")
+		for _, alias := range strings.Split(names, "|") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				f.aliases = append(f.aliases, alias)
+			}
+		}
+		if len(f.aliases) == 0 {
+			if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name != "" && name != "-" {
+				f.aliases = append(f.aliases, name)
+			}
+			f.aliases = append(f.aliases, sf.Name)
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			if strings.TrimSpace(opt) == "required" {
+				f.required = true
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// normalizeTitle 表头匹配时忽略大小写、空白以及 _、- 分隔符
+func normalizeTitle(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '　', '_', '-':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
+}
+
+// mapColumns 将表头各列映射到字段，别名同时按 locale 翻译后匹配；返回缺失的必需列
+func mapColumns(header []string, fs []*field, locale string) ([]column, []string) {
+	lookup := make(map[string]*field)
+	for _, f := range fs {
+		for _, alias := range f.aliases {
+			for _, name := range []string{alias, i18n.T(locale, alias)} {
+				if key := normalizeTitle(name); key != "" {
+					if _, dup := lookup[key]; !dup {
+						lookup[key] = f
+					}
+				}
+			}
+		}
+	}
+
+	cols := make([]column, len(header))
+	seen := make(map[*field]bool)
+	for i, title := range header {
+		cols[i].title = strings.TrimSpace(title)
+		if f, ok := lookup[normalizeTitle(title)]; ok && !seen[f] {
+			cols[i].field = f
+			seen[f] = true
+		}
+	}
+	var missing []string
+	for _, f := range fs {
+		if f.required && !seen[f] {
+			missing = append(missing, i18n.T(locale, f.aliases[0]))
+		}
+	}
+	return cols, missing
+}
+
+// fieldValue 取字段的值，沿途为 nil 的嵌入指针自动分配
+func fieldValue(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// timeLayouts 时间列可接受的格式（按顺序尝试）
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/1/2",
+	"2006/01/02",
+	"2006年1月2日",
+}
+
+// excelEpoch Excel 日期序列号的起点
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// setValue 将单元格文本转换后写入字段，空文本保留零值
+func setValue(v reflect.Value, s string, loc *time.Location) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		nv := reflect.New(v.Type().Elem())
+		if err := setValue(nv.Elem(), s, loc); err != nil {
+			return err
+		}
+		v.Set(nv)
+		return nil
+	}
+	if v.Type() == timeType {
+		t, err := parseTime(s, loc)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("应为时长，如 1h30m")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := parseBool(s)
+		if !ok {
+			return errors.New("应为是/否")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			// XLSX 中的整数可能以 1.2E+3 等浮点形式存储
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return errors.New("应为整数")
+			}
+			n = int64(f)
+		}
+		if v.OverflowInt(n) {
+			return errors.New("超出取值范围")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return errors.New("应为非负整数")
+			}
+			n = uint64(f)
+		}
+		if v.OverflowUint(n) {
+			return errors.New("超出取值范围")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.New("应为数字")
+		}
+		if v.OverflowFloat(f) {
+			return errors.New("超出取值范围")
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", v.Type())
+	}
+	return nil
+}
+
+// parseBool 解析布尔值，支持 true/false、1/0、yes/no、y/n、是/否
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes", "y", "是", "t":
+		return true, true
+	case "false", "0", "no", "n", "否", "f":
+		return false, true
+	}
+	return false, false
+}
+
+// parseTime 按 timeLayouts 解析时间（不含时区的按 loc），纯数字按 Excel 日期序列号
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f > 0 && f < 2958466 {
+		wall := excelEpoch.Add(time.Duration(math.Round(f*86400*float64(time.Second/time.Millisecond))) * time.Millisecond)
+		y, mo, d := wall.Date()
+		h, mi, sec := wall.Clock()
+		return time.Date(y, mo, d, h, mi, sec, wall.Nanosecond(), loc), nil
+	}
+	return time.Time{}, errors.New("应为日期时间，如 2006-01-02 15:04:05")
+}
@@ -0,0 +1,447 @@
+package importer
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format 导入文件格式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ErrUnsupportedFormat 文件扩展名既不是 .csv 也不是 .xlsx
+var ErrUnsupportedFormat = errors.New("不支持的导入文件格式，仅支持 .csv 与 .xlsx")
+
+// FormatOf 按文件名的扩展名判断格式
+func FormatOf(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Reader 逐行读取表格
+type Reader interface {
+	// Read 读取下一行，返回行号（从 1 开始，与表格软件中显示的一致）与各单元格文本，读完返回 io.EOF
+	Read() (line int, record []string, err error)
+	// Close 关闭底层文件
+	Close() error
+}
+
+// Open 按扩展名打开上传的文件
+func Open(fh *multipart.FileHeader) (Reader, error) {
+	format, err := FormatOf(fh.Filename)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	return open(f, fh.Size, format)
+}
+
+// OpenFile 按扩展名打开本地文件
+func OpenFile(name string) (Reader, error) {
+	format, err := FormatOf(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return open(f, st.Size(), format)
+}
+
+// file 可随机读取的文件，multipart.File 与 *os.File 均满足
+type file interface {
+	io.Reader
+	io.ReaderAt
+	io.Closer
+}
+
+// open 按格式创建 Reader，创建失败时关闭文件
+func open(f file, size int64, format Format) (Reader, error) {
+	if format == FormatCSV {
+		return NewCSVReader(f), nil
+	}
+	r, err := NewXLSXReader(f, size)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// utf8BOM 以 Excel 另存的 UTF-8 CSV 开头带有 BOM
+const utf8BOM = "\ufeff"
+
+// CSVReader CSV 表格读取：去除开头的 UTF-8 BOM，允许各行列数不一致
+type CSVReader struct {
+	csv    *csv.Reader
+	closer io.Closer
+}
+
+// CSVOption CSV 读取选项
+type CSVOption func(*CSVReader)
+
+// WithCSVComma 设置分隔符，默认逗号
+func WithCSVComma(comma rune) CSVOption {
+	return func(r *CSVReader) { r.csv.Comma = comma }
+}
+
+// NewCSVReader 创建 CSV 读取，r 实现 io.Closer 时 Close 一并关闭
+func NewCSVReader(r io.Reader, opts ...CSVOption) *CSVReader {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(utf8BOM)); string(head) == utf8BOM {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	cr := &CSVReader{csv: csv.NewReader(br)}
+	cr.csv.FieldsPerRecord = -1
+	cr.csv.LazyQuotes = true
+	cr.csv.ReuseRecord = true
+	if c, ok := r.(io.Closer); ok {
+		cr.closer = c
+	}
+	for _, opt := range opts {
+		opt(cr)
+	}
+	return cr
+}
+
+// Read 实现 Reader。导出时为防御公式注入加在文本前的单引号（见 export.WithCSVSanitize）会被去除
+func (r *CSVReader) Read() (int, []string, error) {
+	record, err := r.csv.Read()
+	if err != nil {
+		return 0, nil, err
+	}
+	line, _ := r.csv.FieldPos(0)
+	for i, s := range record {
+		if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+			record[i] = s[1:]
+		}
+	}
+	return line, record, nil
+}
+
+// Close 实现 Reader
+func (r *CSVReader) Close() error {
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+// XLSXReader XLSX 表格读取：共享字符串表载入内存，工作表以 XML 流式解析，不整体载入。
+// 单元格取其存储的值，日期为 Excel 日期序列号（映射到 time.Time 字段时自动转换）
+type XLSXReader struct {
+	closer  io.Closer
+	strings []string
+	sheet   io.ReadCloser
+	dec     *xml.Decoder
+}
+
+// NewXLSXReader 读取 r 中的第一个工作表，r 实现 io.Closer 时 Close 一并关闭
+func NewXLSXReader(r io.ReaderAt, size int64) (*XLSXReader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("读取 XLSX 失败: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	name, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+	sheet, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("读取 XLSX 失败: 缺少工作表 %s", name)
+	}
+
+	xr := &XLSXReader{}
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if xr.strings, err = sharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	if xr.sheet, err = sheet.Open(); err != nil {
+		return nil, err
+	}
+	xr.dec = xml.NewDecoder(xr.sheet)
+	if c, ok := r.(io.Closer); ok {
+		xr.closer = c
+	}
+	return xr, nil
+}
+
+// firstSheet 由 workbook.xml 与其关系文件解析第一个工作表的路径
+func firstSheet(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("读取 XLSX 失败: 没有工作表")
+	}
+	if err := decodePart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Items {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+// decodePart 解析 zip 中的 XML 部件
+func decodePart(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("读取 XLSX 失败: 缺少 %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("读取 XLSX 失败: %s: %w", name, err)
+	}
+	return nil
+}
+
+// sharedStrings 读取共享字符串表，富文本拼接各段文本，忽略注音（rPh）
+func sharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var (
+		out []string
+		dec = xml.NewDecoder(rc)
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 XLSX 失败: 共享字符串表: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "si" {
+			s, err := richText(dec, se.Name.Local)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s)
+		}
+	}
+}
+
+// richText 读取到 end 元素结束，拼接其中 t 元素的文本
+func richText(dec *xml.Decoder, end string) (string, error) {
+	var (
+		b     strings.Builder
+		depth int
+		inT   bool
+		skip  int
+	)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case t.Name.Local == "rPh" && skip == 0:
+				skip = depth
+			case t.Name.Local == "t":
+				inT = true
+			}
+		case xml.EndElement:
+			if depth == 0 && t.Name.Local == end {
+				return b.String(), nil
+			}
+			if t.Name.Local == "t" {
+				inT = false
+			}
+			if skip == depth {
+				skip = 0
+			}
+			depth--
+		case xml.CharData:
+			if inT && skip == 0 {
+				b.Write(t)
+			}
+		}
+	}
+}
+
+// Read 实现 Reader，空单元格以空字符串补齐
+func (r *XLSXReader) Read() (int, []string, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return 0, nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Local == "row" {
+			return r.row(se)
+		}
+	}
+}
+
+// row 读取一行的各单元格
+func (r *XLSXReader) row(se xml.StartElement) (int, []string, error) {
+	line, _ := strconv.Atoi(attr(se, "r"))
+	var record []string
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "c" {
+				continue
+			}
+			col := len(record)
+			if ref := attr(t, "r"); ref != "" {
+				col = columnIndex(ref)
+			}
+			value, err := r.cell(t)
+			if err != nil {
+				return 0, nil, err
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			record[col] = value
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				return line, record, nil
+			}
+		}
+	}
+}
+
+// cell 读取单元格的值
+func (r *XLSXReader) cell(se xml.StartElement) (string, error) {
+	typ := attr(se, "t")
+	var value string
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "v":
+				if err := r.dec.DecodeElement(&value, &t); err != nil {
+					return "", err
+				}
+			case "is":
+				if value, err = richText(r.dec, "is"); err != nil {
+					return "", err
+				}
+			default:
+				if err := r.dec.Skip(); err != nil {
+					return "", err
+				}
+			}
+		case xml.EndElement:
+			switch typ {
+			case "s":
+				i, err := strconv.Atoi(value)
+				if err != nil || i < 0 || i >= len(r.strings) {
+					return "", nil
+				}
+				return r.strings[i], nil
+			case "b":
+				return strconv.FormatBool(value == "1"), nil
+			}
+			return value, nil
+		}
+	}
+}
+
+// attr 取元素的属性值
+func attr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// columnIndex 单元格引用转为列序号（从 0 开始）：A1 → 0，AA3 → 26
+func columnIndex(ref string) int {
+	n := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		n = n*26 + int(c-'A') + 1
+	}
+	return n - 1
+}
+
+// Close 实现 Reader
+func (r *XLSXReader) Close() error {
+	err := r.sheet.Close()
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
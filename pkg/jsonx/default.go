@@ -0,0 +1,6 @@
+//go:build !go_json && !(sonic && (linux || windows || darwin) && amd64)
+
+package jsonx
+
+// tagDefault 未指定构建标签时默认使用标准库
+const tagDefault = EngineStd
@@ -0,0 +1,6 @@
+//go:build go_json && !(sonic && (linux || windows || darwin) && amd64)
+
+package jsonx
+
+// tagDefault 使用 -tags go_json 构建时默认使用 go-json
+const tagDefault = EngineGoJSON
@@ -0,0 +1,21 @@
+package jsonx
+
+import (
+	"io"
+
+	json "github.com/goccy/go-json"
+)
+
+// goJSONEngine goccy/go-json，与标准库完全兼容，编码速度约为标准库的 2~3 倍
+type goJSONEngine struct{}
+
+func init() { Register(goJSONEngine{}) }
+
+func (goJSONEngine) Name() string                  { return EngineGoJSON }
+func (goJSONEngine) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+func (goJSONEngine) MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+func (goJSONEngine) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (goJSONEngine) NewEncoder(w io.Writer) Encoder     { return json.NewEncoder(w) }
+func (goJSONEngine) NewDecoder(r io.Reader) Decoder     { return json.NewDecoder(r) }
@@ -0,0 +1,145 @@
+// Package jsonx 提供可替换的 JSON 编解码引擎。统一响应（response）与模板 dump 函数通过本包编码，
+// 可在标准库 encoding/json、goccy/go-json 与 bytedance/sonic 之间切换：
+//
+//   - 构建标签：与 Gin 一致，go build -tags go_json 默认使用 go-json，-tags sonic 默认使用 sonic
+//     （sonic 仅在 linux/windows/darwin 的 amd64 平台编译），同时作用于 Gin 的请求绑定
+//   - 配置：server.json_engine 设置为 std、go-json 或 sonic 时覆盖构建标签的默认值
+//
+// 各引擎的输出与标准库保持一致（HTML 字符转义、map 键排序），切换引擎不会改变接口响应。
+package jsonx
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// 内置引擎名称
+const (
+	EngineStd    = "std"
+	EngineGoJSON = "go-json"
+	EngineSonic  = "sonic"
+)
+
+// Encoder 流式编码器，各引擎的编码器均实现该接口
+type Encoder interface {
+	Encode(v any) error
+	SetIndent(prefix, indent string)
+	SetEscapeHTML(on bool)
+}
+
+// Decoder 流式解码器，各引擎的解码器均实现该接口
+type Decoder interface {
+	Decode(v any) error
+	UseNumber()
+	DisallowUnknownFields()
+}
+
+// Engine JSON 编解码引擎
+type Engine interface {
+	Name() string
+	Marshal(v any) ([]byte, error)
+	MarshalIndent(v any, prefix, indent string) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+var (
+	enginesMu sync.RWMutex
+	engines   = make(map[string]Engine)
+	current   atomic.Pointer[Engine]
+)
+
+// Register 注册引擎，同名引擎后者覆盖前者
+func Register(e Engine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	engines[e.Name()] = e
+}
+
+// Engines 返回已注册（当前构建可用）的引擎名称，按名称排序
+func Engines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup 按名称查找已注册的引擎
+func Lookup(name string) (Engine, bool) {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	e, ok := engines[name]
+	return e, ok
+}
+
+// Use 切换全局引擎，name 为空时恢复构建标签决定的默认引擎；
+// 引擎未注册（如未使用 -tags sonic 构建时选择 sonic）时返回错误。路由初始化时按 server.json_engine 配置设置
+func Use(name string) error {
+	if name == "" {
+		name = tagDefault
+	}
+	e, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("JSON 引擎 %q 不可用，可选: %v", name, Engines())
+	}
+	SetDefault(e)
+	return nil
+}
+
+// SetDefault 替换全局引擎，传入 nil 恢复构建标签决定的默认引擎
+func SetDefault(e Engine) {
+	if e == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&e)
+}
+
+// Default 返回全局引擎
+func Default() Engine {
+	if e := current.Load(); e != nil {
+		return *e
+	}
+	if e, ok := Lookup(tagDefault); ok {
+		return e
+	}
+	return stdEngine{}
+}
+
+// Name 返回全局引擎的名称
+func Name() string {
+	return Default().Name()
+}
+
+// Marshal 使用全局引擎编码
+func Marshal(v any) ([]byte, error) {
+	return Default().Marshal(v)
+}
+
+// MarshalIndent 使用全局引擎缩进编码
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return Default().MarshalIndent(v, prefix, indent)
+}
+
+// Unmarshal 使用全局引擎解码
+func Unmarshal(data []byte, v any) error {
+	return Default().Unmarshal(data, v)
+}
+
+// NewEncoder 使用全局引擎创建流式编码器
+func NewEncoder(w io.Writer) Encoder {
+	return Default().NewEncoder(w)
+}
+
+// NewDecoder 使用全局引擎创建流式解码器
+func NewDecoder(r io.Reader) Decoder {
+	return Default().NewDecoder(r)
+}
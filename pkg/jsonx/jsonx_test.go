@@ -0,0 +1,51 @@
+package jsonx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type sample struct {
+	Name string         `json:"name"`
+	Tags map[string]int `json:"tags,omitempty"`
+	HTML string         `json:"html"`
+}
+
+// TestEnginesCompatible 各可用引擎的编码输出与标准库一致（HTML 转义、map 键排序），且可往返解码
+func TestEnginesCompatible(t *testing.T) {
+	v := sample{Name: "张三", Tags: map[string]int{"b": 2, "a": 1}, HTML: "<a>&"}
+	want, _ := stdEngine{}.Marshal(v)
+	for _, name := range Engines() {
+		e, _ := Lookup(name)
+		got, err := e.Marshal(v)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%s: Marshal = %s, %v，期望 %s", name, got, err, want)
+		}
+
+		var buf bytes.Buffer
+		if err := e.NewEncoder(&buf).Encode(v); err != nil || buf.String() != string(want)+"\n" {
+			t.Errorf("%s: Encoder 输出 %q, %v", name, buf.String(), err)
+		}
+
+		var back sample
+		if err := e.NewDecoder(strings.NewReader(buf.String())).Decode(&back); err != nil || back.Name != v.Name || back.Tags["b"] != 2 {
+			t.Errorf("%s: Decode = %+v, %v", name, back, err)
+		}
+	}
+}
+
+// TestUse 切换引擎，空名称恢复构建默认，未注册的引擎返回错误且不改变当前引擎
+func TestUse(t *testing.T) {
+	defer SetDefault(nil)
+
+	if err := Use(EngineGoJSON); err != nil || Name() != EngineGoJSON {
+		t.Fatalf("Use(go-json) = %v，当前 %s", err, Name())
+	}
+	if err := Use("fastjson"); err == nil || Name() != EngineGoJSON {
+		t.Errorf("未注册的引擎应报错，当前 %s", Name())
+	}
+	if err := Use(""); err != nil || Name() != tagDefault {
+		t.Errorf("Use(\"\") = %v，当前 %s，期望 %s", err, Name(), tagDefault)
+	}
+}
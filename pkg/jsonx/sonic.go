@@ -0,0 +1,29 @@
+//go:build sonic && (linux || windows || darwin) && amd64
+
+package jsonx
+
+import (
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+// tagDefault 使用 -tags sonic 构建时默认使用 sonic
+const tagDefault = EngineSonic
+
+// sonicAPI 与标准库行为一致的配置（HTML 转义、map 键排序、校验字符串）
+var sonicAPI = sonic.ConfigStd
+
+// sonicEngine bytedance/sonic，基于 JIT 与 SIMD，大对象编解码最快，仅支持 amd64
+type sonicEngine struct{}
+
+func init() { Register(sonicEngine{}) }
+
+func (sonicEngine) Name() string                  { return EngineSonic }
+func (sonicEngine) Marshal(v any) ([]byte, error) { return sonicAPI.Marshal(v) }
+func (sonicEngine) MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return sonicAPI.MarshalIndent(v, prefix, indent)
+}
+func (sonicEngine) Unmarshal(data []byte, v any) error { return sonicAPI.Unmarshal(data, v) }
+func (sonicEngine) NewEncoder(w io.Writer) Encoder     { return sonicAPI.NewEncoder(w) }
+func (sonicEngine) NewDecoder(r io.Reader) Decoder     { return sonicAPI.NewDecoder(r) }
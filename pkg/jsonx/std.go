@@ -0,0 +1,20 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// stdEngine 标准库 encoding/json
+type stdEngine struct{}
+
+func init() { Register(stdEngine{}) }
+
+func (stdEngine) Name() string                  { return EngineStd }
+func (stdEngine) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+func (stdEngine) MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+func (stdEngine) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (stdEngine) NewEncoder(w io.Writer) Encoder     { return json.NewEncoder(w) }
+func (stdEngine) NewDecoder(r io.Reader) Decoder     { return json.NewDecoder(r) }
@@ -0,0 +1,252 @@
+// Package lock 提供互斥锁服务：多实例部署时以 Redis 实现分布式锁，单实例时使用进程内存。
+// 持有期间自动续期，续期失败（锁已过期被他人获取）时取消持有者的 context；
+// 每次获取返回单调递增的 fencing token，写入外部资源时携带以拒绝已失去锁的旧持有者。
+//
+//	err := lock.WithLock(ctx, "report:daily", func(ctx context.Context) error {
+//	    return buildDailyReport(ctx) // ctx 在锁丢失时被取消
+//	})
+//	if errors.Is(err, lock.ErrNotAcquired) { ... } // 已有其他实例在执行
+//
+// 锁后端按 lock.driver 配置创建（见 NewFromConfig），由 bootstrap 设为全局实例；未初始化时使用内存锁。
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/redis"
+)
+
+var (
+	// ErrNotAcquired 锁已被其他持有者占用
+	ErrNotAcquired = errors.New("锁已被占用")
+	// ErrLost 持有期间锁已过期或被他人获取（续期失败）
+	ErrLost = errors.New("锁已丢失")
+)
+
+// 默认参数
+const (
+	DefaultTTL           = 30 * time.Second
+	DefaultRetryInterval = 100 * time.Millisecond
+)
+
+// Store 锁的存储后端，owner 为每次获取生成的随机标识，只有持有者能续期与释放
+type Store interface {
+	// Acquire key 未被占用时以 owner 持有 ttl，成功时返回单调递增的 fencing token
+	Acquire(ctx context.Context, key, owner string, ttl time.Duration) (token int64, ok bool, err error)
+	// Refresh 将 owner 持有的锁延长至 ttl，锁已过期或被他人持有时返回 false
+	Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	// Release 释放 owner 持有的锁，未持有时不报错
+	Release(ctx context.Context, key, owner string) error
+}
+
+// Locker 锁服务
+type Locker struct {
+	store         Store
+	ttl           time.Duration
+	retryInterval time.Duration
+}
+
+// Option Locker 选项
+type Option func(*Locker)
+
+// WithTTL 设置锁的有效期，持有期间每 ttl/3 续期一次
+func WithTTL(ttl time.Duration) Option {
+	return func(l *Locker) {
+		if ttl > 0 {
+			l.ttl = ttl
+		}
+	}
+}
+
+// WithRetryInterval 设置 Lock 等待时的重试间隔
+func WithRetryInterval(d time.Duration) Option {
+	return func(l *Locker) {
+		if d > 0 {
+			l.retryInterval = d
+		}
+	}
+}
+
+// New 创建锁服务
+func New(store Store, opts ...Option) *Locker {
+	l := &Locker{store: store, ttl: DefaultTTL, retryInterval: DefaultRetryInterval}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// NewFromConfig 按 lock.* 配置创建锁服务，redis 后端使用共享的 Redis 客户端（见 pkg/redis）
+func NewFromConfig(cfg *config.LockConfig, client redis.Client) (*Locker, error) {
+	var store Store
+	switch cfg.Driver {
+	case "", "memory":
+		store = NewMemory()
+	case "redis":
+		if client == nil {
+			return nil, errors.New("redis 锁驱动需要 Redis 客户端")
+		}
+		store = NewRedis(client, cfg.Prefix)
+	default:
+		return nil, fmt.Errorf("未知的锁驱动: %s", cfg.Driver)
+	}
+	return New(store, WithTTL(time.Duration(cfg.TTL)*time.Second)), nil
+}
+
+// Close 关闭后端持有的资源（实现 io.Closer 的后端）；共享的 Redis 客户端由其提供者关闭
+func (lk *Locker) Close() error {
+	if c, ok := lk.store.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Lock 已获取的锁
+type Lock struct {
+	key    string
+	owner  string
+	token  int64
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+	once   sync.Once
+	locker *Locker
+}
+
+// Key 返回锁的键
+func (l *Lock) Key() string { return l.key }
+
+// Token 返回本次获取的 fencing token，后获取者的 token 总是更大
+func (l *Lock) Token() int64 { return l.token }
+
+// Context 返回持有期间有效的 context：调用方 context 结束、锁丢失（context.Cause 为 ErrLost）或释放后取消
+func (l *Lock) Context() context.Context { return l.ctx }
+
+// Unlock 停止续期并释放锁，可重复调用
+func (l *Lock) Unlock(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		l.cancel(context.Canceled)
+		<-l.done
+		err = l.locker.store.Release(ctx, l.key, l.owner)
+	})
+	return err
+}
+
+// TryLock 尝试获取锁，已被占用时立即返回 ErrNotAcquired
+func (lk *Locker) TryLock(ctx context.Context, key string) (*Lock, error) {
+	owner := newOwner()
+	token, ok, err := lk.store.Acquire(ctx, key, owner, lk.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotAcquired
+	}
+	lctx, cancel := context.WithCancelCause(ctx)
+	l := &Lock{key: key, owner: owner, token: token, ctx: lctx, cancel: cancel, done: make(chan struct{}), locker: lk}
+	go lk.renew(l)
+	return l, nil
+}
+
+// Lock 获取锁，已被占用时按重试间隔等待，直到获取成功或 ctx 结束
+func (lk *Locker) Lock(ctx context.Context, key string) (*Lock, error) {
+	for {
+		l, err := lk.TryLock(ctx, key)
+		if !errors.Is(err, ErrNotAcquired) {
+			return l, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lk.retryInterval):
+		}
+	}
+}
+
+// WithLock 获取锁后执行 fn 并释放，已被占用时返回 ErrNotAcquired（不等待）；
+// fn 收到的 context 在锁丢失时取消，此时即使 fn 返回 nil 也返回 ErrLost
+func (lk *Locker) WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	l, err := lk.TryLock(ctx, key)
+	if err != nil {
+		return err
+	}
+	fnErr := fn(l.Context())
+	lost := errors.Is(context.Cause(l.Context()), ErrLost)
+	// 调用方 context 可能已结束，释放锁不应因此失败
+	unlockErr := l.Unlock(context.WithoutCancel(ctx))
+	if fnErr != nil {
+		return fnErr
+	}
+	if lost {
+		return ErrLost
+	}
+	return unlockErr
+}
+
+// renew 每 ttl/3 续期一次；锁已被他人持有，或持续失败到有效期耗尽时视为丢失
+func (lk *Locker) renew(l *Lock) {
+	defer close(l.done)
+	interval := lk.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(lk.ttl)
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := lk.store.Refresh(l.ctx, l.key, l.owner, lk.ttl)
+			switch {
+			case err == nil && ok:
+				deadline = time.Now().Add(lk.ttl)
+			case err == nil || time.Now().Add(interval).After(deadline):
+				l.cancel(ErrLost)
+				return
+			}
+		}
+	}
+}
+
+// newOwner 生成随机的持有者标识
+func newOwner() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLocker *Locker
+	memoryLocker  = New(NewMemory())
+)
+
+// SetDefault 设置全局锁服务（由 bootstrap 调用）
+func SetDefault(l *Locker) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLocker = l
+}
+
+// Default 返回全局锁服务，未初始化时返回进程内的内存锁
+func Default() *Locker {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultLocker == nil {
+		return memoryLocker
+	}
+	return defaultLocker
+}
+
+// WithLock 使用全局锁服务执行 fn，见 Locker.WithLock
+func WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	return Default().WithLock(ctx, key, fn)
+}
@@ -0,0 +1,187 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// TestMemory 互斥、fencing token 递增、过期后可重新获取
+func TestMemory(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	clock.SetDefault(fake)
+	defer clock.SetDefault(nil)
+
+	ctx := context.Background()
+	m := NewMemory()
+	token, ok, _ := m.Acquire(ctx, "k", "a", time.Second)
+	if !ok || token != 1 {
+		t.Fatalf("首次获取: %d, %v", token, ok)
+	}
+	if _, ok, _ := m.Acquire(ctx, "k", "b", time.Second); ok {
+		t.Error("已被持有时不应获取成功")
+	}
+	if ok, _ := m.Refresh(ctx, "k", "b", time.Second); ok {
+		t.Error("非持有者不应续期成功")
+	}
+	m.Release(ctx, "k", "b")
+	if ok, _ := m.Refresh(ctx, "k", "a", time.Second); !ok {
+		t.Error("非持有者的释放不应生效")
+	}
+
+	fake.Advance(2 * time.Second)
+	token, ok, _ = m.Acquire(ctx, "k", "b", time.Second)
+	if !ok || token != 2 {
+		t.Errorf("过期后获取: %d, %v", token, ok)
+	}
+	if ok, _ := m.Refresh(ctx, "k", "a", time.Second); ok {
+		t.Error("锁过期后原持有者不应续期成功")
+	}
+}
+
+// TestRedis 共享客户端上的 Redis 后端：互斥、fencing token 递增、过期后可重新获取
+func TestRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	r := NewRedis(client, "lock:")
+	token, ok, err := r.Acquire(ctx, "k", "a", time.Second)
+	if err != nil || !ok || token != 1 {
+		t.Fatalf("首次获取: %d, %v, %v", token, ok, err)
+	}
+	if _, ok, _ := r.Acquire(ctx, "k", "b", time.Second); ok {
+		t.Error("已被持有时不应获取成功")
+	}
+	if ok, _ := r.Refresh(ctx, "k", "b", time.Second); ok {
+		t.Error("非持有者不应续期成功")
+	}
+	if err := r.Release(ctx, "k", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := r.Refresh(ctx, "k", "a", time.Second); !ok {
+		t.Error("非持有者的释放不应生效")
+	}
+
+	mr.FastForward(2 * time.Second)
+	token, ok, _ = r.Acquire(ctx, "k", "b", time.Second)
+	if !ok || token != 2 {
+		t.Errorf("过期后获取: %d, %v", token, ok)
+	}
+	if ok, _ := r.Refresh(ctx, "k", "a", time.Second); ok {
+		t.Error("锁过期后原持有者不应续期成功")
+	}
+	if err := r.Release(ctx, "k", "b"); err != nil || mr.Exists("lock:{k}") {
+		t.Errorf("释放后锁键应已删除: %v", err)
+	}
+}
+
+func TestWithLock(t *testing.T) {
+	ctx := context.Background()
+	lk := New(NewMemory())
+
+	err := lk.WithLock(ctx, "job", func(ctx context.Context) error {
+		if err := lk.WithLock(ctx, "job", func(context.Context) error { return nil }); !errors.Is(err, ErrNotAcquired) {
+			t.Errorf("重复获取: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := errors.New("boom")
+	if err := lk.WithLock(ctx, "job", func(context.Context) error { return want }); err != want {
+		t.Errorf("应返回 fn 的错误: %v", err)
+	}
+	if err := lk.WithLock(ctx, "job", func(context.Context) error { return nil }); err != nil {
+		t.Errorf("释放后应可再次获取: %v", err)
+	}
+}
+
+// TestLockWait Lock 等待释放后获取，ctx 结束时放弃
+func TestLockWait(t *testing.T) {
+	ctx := context.Background()
+	lk := New(NewMemory(), WithRetryInterval(5*time.Millisecond))
+	first, err := lk.TryLock(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := lk.Lock(short, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("超时: %v", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { first.Unlock(ctx) })
+	second, err := lk.Lock(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Unlock(ctx)
+	if second.Token() <= first.Token() {
+		t.Errorf("token 应递增: %d <= %d", second.Token(), first.Token())
+	}
+	if first.Context().Err() == nil {
+		t.Error("释放后 Context 应已取消")
+	}
+}
+
+// TestRenew 持有期间自动续期；锁被他人获取后取消 Context 并返回 ErrLost
+func TestRenew(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemory()
+	lk := New(store, WithTTL(30*time.Millisecond))
+
+	err := lk.WithLock(ctx, "k", func(ctx context.Context) error {
+		time.Sleep(60 * time.Millisecond) // 超过 ttl，依赖续期保持持有
+		if _, ok, _ := store.Acquire(ctx, "k", "other", time.Second); ok {
+			t.Error("续期后锁不应被他人获取")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lk.WithLock(ctx, "k", func(ctx context.Context) error {
+		// 模拟锁过期后被其他实例获取
+		store.mu.Lock()
+		store.locks["k"] = memoryEntry{owner: "other", expires: time.Now().Add(time.Minute)}
+		store.mu.Unlock()
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Error("锁丢失后 Context 应被取消")
+		}
+		return nil
+	})
+	if !errors.Is(err, ErrLost) {
+		t.Errorf("应返回 ErrLost: %v", err)
+	}
+	if ok, _ := store.Refresh(ctx, "k", "other", time.Minute); !ok {
+		t.Error("不应释放他人持有的锁")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if Default() != memoryLocker {
+		t.Error("未初始化时应使用内存锁")
+	}
+	lk := New(NewMemory())
+	SetDefault(lk)
+	defer SetDefault(nil)
+	if Default() != lk {
+		t.Error("全局锁服务未替换")
+	}
+	if err := WithLock(context.Background(), "k", func(context.Context) error { return nil }); err != nil {
+		t.Error(err)
+	}
+}
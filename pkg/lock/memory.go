@@ -0,0 +1,69 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+)
+
+// Memory 进程内存锁后端，仅适用于单实例部署
+type Memory struct {
+	mu     sync.Mutex
+	locks  map[string]memoryEntry
+	tokens map[string]int64
+}
+
+type memoryEntry struct {
+	owner   string
+	expires time.Time
+}
+
+// NewMemory 创建内存锁后端
+func NewMemory() *Memory {
+	return &Memory{locks: make(map[string]memoryEntry), tokens: make(map[string]int64)}
+}
+
+// held 返回 key 当前未过期的持有者
+func (m *Memory) held(key string) (memoryEntry, bool) {
+	e, ok := m.locks[key]
+	if ok && !clock.Now().Before(e.expires) {
+		delete(m.locks, key)
+		return e, false
+	}
+	return e, ok
+}
+
+// Acquire 实现 Store
+func (m *Memory) Acquire(_ context.Context, key, owner string, ttl time.Duration) (int64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.held(key); ok {
+		return 0, false, nil
+	}
+	m.locks[key] = memoryEntry{owner: owner, expires: clock.Now().Add(ttl)}
+	m.tokens[key]++
+	return m.tokens[key], true, nil
+}
+
+// Refresh 实现 Store
+func (m *Memory) Refresh(_ context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.held(key); !ok || e.owner != owner {
+		return false, nil
+	}
+	m.locks[key] = memoryEntry{owner: owner, expires: clock.Now().Add(ttl)}
+	return true, nil
+}
+
+// Release 实现 Store
+func (m *Memory) Release(_ context.Context, key, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.locks[key]; ok && e.owner == owner {
+		delete(m.locks, key)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 脚本保证“检查持有者 + 修改”的原子性；fencing token 保存在 <key>:token 中，不随锁过期
+var (
+	acquireScript = goredis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+	refreshScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Redis 基于 Redis 的分布式锁后端（单节点 SET NX PX），使用共享的 redis.Client
+type Redis struct {
+	client redis.Client
+	prefix string
+}
+
+// NewRedis 创建 Redis 锁后端，prefix 为所有键的前缀（如 "lock:"）。
+// 客户端由调用方管理（通常为 bootstrap 提供的共享客户端），锁后端不负责关闭
+func NewRedis(client redis.Client, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// lockKey 返回锁键与 fencing token 键；键名以 {} 包住，集群模式下两者落在同一个槽
+func (r *Redis) lockKey(key string) []string {
+	k := r.prefix + "{" + key + "}"
+	return []string{k, k + ":token"}
+}
+
+// Acquire 实现 Store
+func (r *Redis) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (int64, bool, error) {
+	token, err := acquireScript.Run(ctx, r.client, r.lockKey(key), owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	return token, token > 0, nil
+}
+
+// Refresh 实现 Store
+func (r *Redis) Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	n, err := refreshScript.Run(ctx, r.client, r.lockKey(key)[:1], owner, ttl.Milliseconds()).Int()
+	return n == 1, err
+}
+
+// Release 实现 Store
+func (r *Redis) Release(ctx context.Context, key, owner string) error {
+	return releaseScript.Run(ctx, r.client, r.lockKey(key)[:1], owner).Err()
+}
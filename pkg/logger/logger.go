@@ -28,8 +28,9 @@ var (
 	SugarLogger *zap.SugaredLogger
 )
 
-// InitLogger 初始化日志
-func InitLogger(cfg *config.LogConfig) error {
+// InitLogger 初始化日志。fields 为写入每条文件日志的全局字段（交替的键值对，见 DefaultFields），
+// 便于日志平台按应用、版本与主机筛选；控制台输出不带全局字段，保持开发时可读
+func InitLogger(cfg *config.LogConfig, fields ...any) error {
 	// 创建日志目录
 	logDir := filepath.Dir(cfg.Filename)
 	if err := os.MkdirAll(logDir, 0755); err != nil {
@@ -37,7 +38,7 @@ func InitLogger(cfg *config.LogConfig) error {
 	}
 
 	// 初始化zap
-	if err := initZap(cfg); err != nil {
+	if err := initZap(cfg, fields); err != nil {
 		panic(err)
 	}
 
@@ -45,7 +46,7 @@ func InitLogger(cfg *config.LogConfig) error {
 }
 
 // initZap 初始化zap
-func initZap(cfg *config.LogConfig) error {
+func initZap(cfg *config.LogConfig, fields []any) error {
 	// 定义日志级别
 	var level zapcore.Level
 	switch cfg.Level {
@@ -95,6 +96,9 @@ func initZap(cfg *config.LogConfig) error {
 
 	// 文件 Core
 	fileCore := zapcore.NewCore(fileEncoder, zapcore.AddSync(logWriter), atomicLevel)
+	if len(fields) > 0 {
+		fileCore = zap.New(fileCore).Sugar().With(fields...).Desugar().Core()
+	}
 
 	// 根据配置决定是否同时输出到控制台
 	var core zapcore.Core
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"go.uber.org/zap"
+)
+
+// scopeKey 请求日志作用域字段在 gin.Context 中的键，经 request.Context 传给服务层
+const scopeKey = "log_scope"
+
+// DefaultFields 返回写入每条文件日志的全局字段：app（app.name）、version（app.version）、host（主机名）
+// 与 log.fields 中的自定义字段，值为空的项省略。传给 InitLogger：
+//
+//	logger.InitLogger(&cfg.Log, logger.DefaultFields(cfg)...)
+func DefaultFields(cfg *config.Config) []any {
+	var fields []any
+	if cfg.App.Name != "" {
+		fields = append(fields, "app", cfg.App.Name)
+	}
+	if cfg.App.Version != "" {
+		fields = append(fields, "version", cfg.App.Version)
+	}
+	if host, err := os.Hostname(); err == nil {
+		fields = append(fields, "host", host)
+	}
+	keys := make([]string, 0, len(cfg.Log.Fields))
+	for k := range cfg.Log.Fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fields = append(fields, k, cfg.Log.Fields[k])
+	}
+	return fields
+}
+
+// With 返回附加 args 字段的日志记录器，args 为交替的键值对（与 zap.SugaredLogger.With 相同）。
+// 日志未初始化时返回空记录器：
+//
+//	log := logger.With("job", "report", "tenant", id)
+//	log.Infow("开始生成", "rows", n)
+func With(args ...any) *zap.SugaredLogger {
+	if SugarLogger == nil {
+		return zap.NewNop().Sugar()
+	}
+	return SugarLogger.With(args...)
+}
+
+// WithScope 返回附加当前请求字段的日志记录器：request_id、method、path、client_ip，
+// 以及中间件与处理器经 AddScope 追加的字段
+func WithScope(c *gin.Context) *zap.SugaredLogger {
+	args := []any{"method", c.Request.Method, "path", c.Request.URL.Path, "client_ip", c.ClientIP()}
+	if id := request.RequestID(c); id != "" {
+		args = append([]any{"request_id", id}, args...)
+	}
+	return With(append(args, Scope(c)...)...)
+}
+
+// AddScope 向当前请求的日志作用域追加字段（交替的键值对），之后的 WithScope、FromContext 与访问日志都会带上，
+// 如认证中间件追加 user_id：
+//
+//	logger.AddScope(c, "user_id", user.ID)
+func AddScope(c *gin.Context, args ...any) {
+	scope := Scope(c)
+	c.Set(scopeKey, append(scope[:len(scope):len(scope)], args...))
+}
+
+// Scope 返回当前请求经 AddScope 追加的字段，未追加时返回 nil；调用方不得修改
+func Scope(c *gin.Context) []any {
+	scope, _ := request.Get[[]any](c, scopeKey)
+	return scope
+}
+
+// FromContext 返回附加上下文中请求 ID 与作用域字段的日志记录器，供服务层使用。
+// ctx 应来自 request.Context(c)，其中的作用域为调用时的快照：
+//
+//	func (s *OrderService) Pay(ctx context.Context, id uint) error {
+//	    logger.FromContext(ctx).Infow("支付", "order_id", id)
+//	}
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	var args []any
+	if id := request.RequestIDFromContext(ctx); id != "" {
+		args = append(args, "request_id", id)
+	}
+	if ctx != nil {
+		if scope, ok := ctx.Value(scopeKey).([]any); ok {
+			args = append(args, scope...)
+		}
+	}
+	return With(args...)
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestScope WithScope 带上请求字段与作用域字段，FromContext 经 request.Context 取得同样的字段
+func TestScope(t *testing.T) {
+	prev := SugarLogger
+	defer func() { SugarLogger = prev }()
+	SugarLogger = nil
+	With("k", "v").Info("日志未初始化时不应 panic")
+
+	core, logs := observer.New(zap.InfoLevel)
+	SugarLogger = zap.New(core).Sugar()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders", nil)
+	c.Set(request.KeyRequestID, "req-1")
+	AddScope(c, "user_id", 7)
+	AddScope(c, "tenant", "acme")
+
+	WithScope(c).Info("scope")
+	FromContext(request.Context(c)).Info("ctx")
+	With("job", "report").Info("with")
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("entries = %v", entries)
+	}
+	scope := entries[0].ContextMap()
+	if scope["request_id"] != "req-1" || scope["method"] != http.MethodPost || scope["path"] != "/orders" ||
+		scope["user_id"] != int64(7) || scope["tenant"] != "acme" {
+		t.Errorf("WithScope 字段 = %v", scope)
+	}
+	ctx := entries[1].ContextMap()
+	if ctx["request_id"] != "req-1" || ctx["user_id"] != int64(7) || ctx["tenant"] != "acme" {
+		t.Errorf("FromContext 字段 = %v", ctx)
+	}
+	if with := entries[2].ContextMap(); len(with) != 1 || with["job"] != "report" {
+		t.Errorf("With 字段 = %v", with)
+	}
+}
+
+// TestDefaultFields 全局字段写入文件日志
+func TestDefaultFields(t *testing.T) {
+	cfg := &config.Config{
+		App: config.AppConfig{Name: "shop", Version: "1.2.0"},
+		Log: config.LogConfig{Level: "info", Filename: filepath.Join(t.TempDir(), "app.log"), MaxSize: 1, Fields: map[string]string{"region": "cn-east-1"}},
+	}
+	fields := DefaultFields(cfg)
+	if len(fields) != 8 || fields[0] != "app" || fields[1] != "shop" || fields[3] != "1.2.0" || fields[4] != "host" || fields[6] != "region" {
+		t.Fatalf("DefaultFields = %v", fields)
+	}
+
+	prevZap, prevSugar := ZapLogger, SugarLogger
+	defer func() { ZapLogger, SugarLogger = prevZap, prevSugar }()
+	if err := InitLogger(&cfg.Log, fields...); err != nil {
+		t.Fatal(err)
+	}
+	Info("hello")
+	_ = ZapLogger.Sync()
+
+	b, err := os.ReadFile(cfg.Log.Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"app":"shop"`, `"version":"1.2.0"`, `"host":`, `"region":"cn-east-1"`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("日志缺少 %s: %s", want, b)
+		}
+	}
+}
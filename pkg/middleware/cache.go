@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+const (
+	// HeaderCacheBypass 请求携带该头（任意非空值）时跳过响应缓存，直接执行处理器
+	HeaderCacheBypass = "X-Cache-Bypass"
+	// HeaderCacheStatus 响应缓存状态：HIT / MISS / BYPASS
+	HeaderCacheStatus = "X-Cache"
+
+	cacheKeyPrefix = "resp:"
+	cacheTagPrefix = "resp:tag:"
+)
+
+// cachedResponse 缓存的完整响应
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// responseCacheConfig 响应缓存配置
+type responseCacheConfig struct {
+	store  cache.Store
+	vary   []string
+	tags   []string
+	bypass string
+}
+
+// CacheOption 响应缓存配置选项
+type CacheOption func(*responseCacheConfig)
+
+// WithCacheStore 指定缓存后端（默认 cache.Default()）
+func WithCacheStore(store cache.Store) CacheOption {
+	return func(c *responseCacheConfig) { c.store = store }
+}
+
+// WithCacheVary 指定参与缓存键计算的请求头（默认 Accept、Accept-Language）
+func WithCacheVary(headers ...string) CacheOption {
+	return func(c *responseCacheConfig) { c.vary = headers }
+}
+
+// WithCacheTags 为缓存条目打标签，PurgeCacheTags 按标签批量失效。
+// 配合 PurgeCacheOnModelEvents 时标签应使用表名，如 "post"
+func WithCacheTags(tags ...string) CacheOption {
+	return func(c *responseCacheConfig) { c.tags = tags }
+}
+
+// WithCacheBypassHeader 自定义跳过缓存的请求头名称（默认 X-Cache-Bypass）
+func WithCacheBypassHeader(name string) CacheOption {
+	return func(c *responseCacheConfig) { c.bypass = name }
+}
+
+// CacheResponse 响应缓存中间件，缓存幂等 GET/HEAD 请求的完整响应（状态码、响应头、响应体）。
+//
+// 缓存键由路由、规范化后的查询参数、Vary 请求头以及标签版本组成；
+// 仅缓存 200 且未设置 Set-Cookie 的响应，避免把用户私有数据写入共享缓存。
+//
+//	rb.GET("/posts", ctrl.List, middleware.CacheResponse(time.Minute, middleware.WithCacheTags("post")))
+func CacheResponse(ttl time.Duration, opts ...CacheOption) gin.HandlerFunc {
+	cfg := &responseCacheConfig{
+		vary:   []string{"Accept", "Accept-Language"},
+		bypass: HeaderCacheBypass,
+	}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		if c.GetHeader(cfg.bypass) != "" {
+			c.Header(HeaderCacheStatus, "BYPASS")
+			c.Next()
+			return
+		}
+
+		store := cfg.store
+		if store == nil {
+			store = cache.Default()
+		}
+		ctx := c.Request.Context()
+		key := responseCacheKey(ctx, c, store, cfg)
+
+		if raw, ok, _ := store.Get(ctx, key); ok {
+			var cached cachedResponse
+			if json.Unmarshal(raw, &cached) == nil {
+				header := c.Writer.Header()
+				for k, v := range cached.Header {
+					header[k] = v
+				}
+				c.Header(HeaderCacheStatus, "HIT")
+				c.Data(cached.Status, header.Get("Content-Type"), cached.Body)
+				c.Abort()
+				return
+			}
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Header(HeaderCacheStatus, "MISS")
+		c.Next()
+
+		if w.Status() != http.StatusOK || w.Header().Get("Set-Cookie") != "" || len(c.Errors) > 0 {
+			return
+		}
+		header := w.Header().Clone()
+		header.Del(HeaderCacheStatus)
+		header.Del("Content-Length")
+		raw, err := json.Marshal(cachedResponse{Status: w.Status(), Header: header, Body: w.body.Bytes()})
+		if err == nil {
+			_ = store.Set(ctx, key, raw, ttl)
+		}
+	}
+}
+
+// responseCacheKey 计算缓存键
+func responseCacheKey(ctx context.Context, c *gin.Context, store cache.Store, cfg *responseCacheConfig) string {
+	h := sha256.New()
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	h.Write([]byte(c.Request.Method + " " + route + "\n" + c.Request.URL.Path + "\n"))
+	// url.Values.Encode 按键排序，?a=1&b=2 与 ?b=2&a=1 命中同一条目
+	h.Write([]byte(c.Request.URL.Query().Encode() + "\n"))
+	for _, name := range cfg.vary {
+		h.Write([]byte(name + ":" + c.GetHeader(name) + "\n"))
+	}
+	for _, tag := range cfg.tags {
+		h.Write([]byte("tag:" + tag + "=" + tagVersion(ctx, store, tag) + "\n"))
+	}
+	return cacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// tagVersion 读取标签当前版本，标签从未失效过时为 "0"
+func tagVersion(ctx context.Context, store cache.Store, tag string) string {
+	if v, ok, _ := store.Get(ctx, cacheTagPrefix+tag); ok {
+		return string(v)
+	}
+	return "0"
+}
+
+// PurgeCacheTags 使带有指定标签的缓存响应失效。
+// 通过递增标签版本实现，旧条目不再被命中并随 TTL 自然过期，适用于任意键值后端。
+func PurgeCacheTags(ctx context.Context, store cache.Store, tags ...string) error {
+	if store == nil {
+		store = cache.Default()
+	}
+	version := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	for _, tag := range tags {
+		if err := store.Set(ctx, cacheTagPrefix+tag, version, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeCacheOnModelEvents 订阅模型生命周期事件（需启用 database.model_events），
+// 模型创建/更新/删除时自动失效以其表名为标签的缓存响应
+func PurgeCacheOnModelEvents(bus *eventbus.EventBus, store cache.Store) {
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	bus.OnE("model.*", func(ctx context.Context, args ...interface{}) error {
+		if len(args) == 0 {
+			return nil
+		}
+		e, ok := args[0].(*database.ModelEvent)
+		if !ok {
+			return nil
+		}
+		return PurgeCacheTags(ctx, store, e.Table)
+	})
+}
+
+// captureWriter 在写出响应的同时保留一份响应体副本
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Unwrap 返回被包装的写入器
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+func newCacheRouter(store cache.Store, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/posts", CacheResponse(time.Minute, WithCacheStore(store), WithCacheTags("post")), func(c *gin.Context) {
+		*calls++
+		c.Header("X-Custom", "yes")
+		c.String(http.StatusOK, "page=%s", c.Query("page"))
+	})
+	r.GET("/private", CacheResponse(time.Minute, WithCacheStore(store)), func(c *gin.Context) {
+		*calls++
+		c.SetCookie("sid", "1", 0, "/", "", false, true)
+		c.String(http.StatusOK, "private")
+	})
+	return r
+}
+
+func doGet(r *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCacheResponse(t *testing.T) {
+	store := cache.NewMemory()
+	calls := 0
+	r := newCacheRouter(store, &calls)
+
+	w := doGet(r, "/posts?page=1&sort=new", nil)
+	if w.Header().Get(HeaderCacheStatus) != "MISS" || calls != 1 {
+		t.Fatalf("first request: X-Cache=%q calls=%d", w.Header().Get(HeaderCacheStatus), calls)
+	}
+
+	// 查询参数顺序不同仍命中
+	w = doGet(r, "/posts?sort=new&page=1", nil)
+	if w.Header().Get(HeaderCacheStatus) != "HIT" || calls != 1 {
+		t.Fatalf("second request: X-Cache=%q calls=%d", w.Header().Get(HeaderCacheStatus), calls)
+	}
+	if w.Body.String() != "page=1" || w.Header().Get("X-Custom") != "yes" {
+		t.Errorf("cached response mismatch: body=%q header=%v", w.Body.String(), w.Header())
+	}
+
+	// Vary 请求头不同则不命中
+	doGet(r, "/posts?page=1&sort=new", map[string]string{"Accept-Language": "en"})
+	if calls != 2 {
+		t.Errorf("vary: calls = %d, want 2", calls)
+	}
+
+	// 跳过缓存
+	w = doGet(r, "/posts?page=1&sort=new", map[string]string{HeaderCacheBypass: "1"})
+	if w.Header().Get(HeaderCacheStatus) != "BYPASS" || calls != 3 {
+		t.Errorf("bypass: X-Cache=%q calls=%d", w.Header().Get(HeaderCacheStatus), calls)
+	}
+
+	// 按标签失效
+	PurgeCacheTags(context.Background(), store, "post")
+	doGet(r, "/posts?page=1&sort=new", nil)
+	if calls != 4 {
+		t.Errorf("after purge: calls = %d, want 4", calls)
+	}
+}
+
+func TestCacheResponseSkipsCookies(t *testing.T) {
+	calls := 0
+	r := newCacheRouter(cache.NewMemory(), &calls)
+	doGet(r, "/private", nil)
+	doGet(r, "/private", nil)
+	if calls != 2 {
+		t.Errorf("responses with Set-Cookie must not be cached, calls = %d", calls)
+	}
+}
+
+func TestPurgeCacheOnModelEvents(t *testing.T) {
+	store := cache.NewMemory()
+	bus := eventbus.New()
+	PurgeCacheOnModelEvents(bus, store)
+
+	calls := 0
+	r := newCacheRouter(store, &calls)
+	doGet(r, "/posts", nil)
+	doGet(r, "/posts", nil)
+	bus.Emit(database.EventModelUpdated, &database.ModelEvent{Action: "updated", Table: "post"})
+	doGet(r, "/posts", nil)
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
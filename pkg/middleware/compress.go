@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// compressConfig 响应压缩配置
+type compressConfig struct {
+	level    int
+	excludes []string
+	skipper  func(*gin.Context) bool
+	route    func(name string) (level int, ok bool)
+}
+
+// CompressOption 响应压缩配置选项
+type CompressOption func(*compressConfig)
+
+// WithCompressLevel 设置 gzip 压缩级别：gzip.BestSpeed（1）~ gzip.BestCompression（9），默认 gzip.DefaultCompression
+func WithCompressLevel(level int) CompressOption {
+	return func(c *compressConfig) { c.level = level }
+}
+
+// WithCompressExclude 排除指定路径前缀，如 "/downloads/"
+func WithCompressExclude(prefixes ...string) CompressOption {
+	return func(c *compressConfig) { c.excludes = append(c.excludes, prefixes...) }
+}
+
+// WithCompressSkipper 自定义跳过规则，返回 true 时不压缩
+func WithCompressSkipper(fn func(*gin.Context) bool) CompressOption {
+	return func(c *compressConfig) { c.skipper = fn }
+}
+
+// WithCompressRoute 按路由设置压缩级别：fn 以当前请求的路由名（request.RouteName）返回该路由注册时设置的级别，
+// ok 为 false 时使用默认级别，级别为 gzip.NoCompression 时不压缩。路由名在处理器执行前写入，
+// 因此在首次写出响应时才查询。框架路由传入 router.RouteCompression（见 Route.NoCompress、Route.Compress）
+func WithCompressRoute(fn func(name string) (level int, ok bool)) CompressOption {
+	return func(c *compressConfig) { c.route = fn }
+}
+
+// compressibleTypes 压缩的 Content-Type 前缀，图片、压缩包等已压缩的内容不再压缩
+var compressibleTypes = []string{
+	"text/html", "text/plain", "text/css", "text/xml", "text/csv", "text/javascript",
+	"application/json", "application/javascript", "application/xml", "application/rss+xml",
+	"application/atom+xml", "application/problem+json", "application/x-ndjson", "image/svg+xml",
+}
+
+// gzipPools 按压缩级别复用 gzip.Writer，下标为级别 + 1（gzip.DefaultCompression 为 -1）
+var gzipPools [gzip.BestCompression + 2]sync.Pool
+
+// Compress gzip 响应压缩中间件：客户端 Accept-Encoding 含 gzip 且 Content-Type 为文本类时压缩，
+// HEAD 请求、204/206/304 响应、已设置 Content-Encoding 的响应、text/event-stream 与 WebSocket 升级请求不压缩。
+//
+// 在首次写出响应时决定是否压缩，流式输出（c.Stream、Flush）按块压缩并立即发送；
+// 应注册在 MinifyHTML 之前（即先压缩 HTML 再 gzip）：
+//
+//	r.Use(middleware.Compress(middleware.WithCompressExclude("/downloads/"), middleware.WithCompressRoute(router.RouteCompression)))
+func Compress(opts ...CompressOption) gin.HandlerFunc {
+	cfg := &compressConfig{level: gzip.DefaultCompression}
+	for _, o := range opts {
+		o(cfg)
+	}
+	cfg.level = validLevel(cfg.level)
+
+	return func(c *gin.Context) {
+		r := c.Request
+		if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" ||
+			!strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			c.Next()
+			return
+		}
+		for _, prefix := range cfg.excludes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		if cfg.skipper != nil && cfg.skipper(c) {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: c.Writer, c: c, cfg: cfg}
+		c.Writer = w
+		defer w.close()
+		c.Next()
+	}
+}
+
+// validLevel 非法级别按 gzip.DefaultCompression 处理
+func validLevel(level int) int {
+	if level < gzip.NoCompression || level > gzip.BestCompression {
+		return gzip.DefaultCompression
+	}
+	return level
+}
+
+// compressWriter 在首次写入时根据路由设置与响应头决定是否压缩
+type compressWriter struct {
+	gin.ResponseWriter
+	c       *gin.Context
+	cfg     *compressConfig
+	decided bool
+	level   int
+	gz      *gzip.Writer
+}
+
+// Unwrap 返回被包装的写入器
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	level := w.cfg.level
+	if w.cfg.route != nil {
+		if l, ok := w.cfg.route(request.RouteName(w.c)); ok {
+			level = validLevel(l)
+		}
+	}
+	h := w.Header()
+	status := w.Status()
+	// 响应头已写出（如绕过本写入器直接发送）时无法再声明 Content-Encoding；
+	// 206 分段响应的 Content-Range 按未压缩的字节计算，压缩后断点续传会出错
+	if level == gzip.NoCompression || w.Written() || status == http.StatusNoContent || status == http.StatusNotModified ||
+		status == http.StatusPartialContent || status < http.StatusOK || h.Get("Content-Range") != "" ||
+		h.Get("Content-Encoding") != "" || !compressible(h.Get("Content-Type")) {
+		return
+	}
+	h.Set("Content-Encoding", "gzip")
+	h.Add("Vary", "Accept-Encoding")
+	// 压缩后长度变化，交由底层按分块输出
+	h.Del("Content-Length")
+
+	w.level = level
+	pool := &gzipPools[level+1]
+	if gz, ok := pool.Get().(*gzip.Writer); ok {
+		gz.Reset(w.ResponseWriter)
+		w.gz = gz
+		return
+	}
+	w.gz, _ = gzip.NewWriterLevel(w.ResponseWriter, level)
+}
+
+// compressible Content-Type 是否为可压缩的文本类内容
+func compressible(contentType string) bool {
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.decide()
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.gz.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow 在写出响应头前决定是否压缩，使 Content-Encoding 随响应头发送
+func (w *compressWriter) WriteHeaderNow() {
+	w.decide()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush 先输出 gzip 缓冲的数据，使流式响应的每一块及时发送；首次写入前调用时同样先决定是否压缩
+func (w *compressWriter) Flush() {
+	w.decide()
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(io.Discard)
+	gzipPools[w.level+1].Put(w.gz)
+	w.gz = nil
+}
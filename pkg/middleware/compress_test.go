@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat(`{"id":1,"name":"alice"}`, 50)
+	r := gin.New()
+	r.Use(Compress(
+		WithCompressExclude("/downloads/"),
+		WithCompressRoute(func(name string) (int, bool) { return gzip.NoCompression, name == "raw" }),
+	))
+	handler := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Set(request.KeyRouteName, name)
+			c.Data(http.StatusOK, "application/json", []byte(body))
+		}
+	}
+	r.GET("/api", handler("api"))
+	r.GET("/raw", handler("raw"))
+	r.GET("/downloads/a", handler("download"))
+	r.GET("/image", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(body)) })
+	r.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	csv := filepath.Join(t.TempDir(), "report.csv")
+	if err := os.WriteFile(csv, []byte(strings.Repeat("id,name\n", 100)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r.GET("/report.csv", func(c *gin.Context) { _ = response.Download(c, csv, "") })
+	r.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain")
+		c.Writer.Flush() // 首次写入前 Flush，响应头需已带上 Content-Encoding
+		_, _ = c.Writer.WriteString(body)
+	})
+
+	tests := []struct {
+		path       string
+		rangeHdr   string
+		acceptGzip bool
+		compressed bool
+	}{
+		{"/api", "", true, true},
+		{"/api", "", false, false},
+		{"/raw", "", true, false},                  // 路由设置不压缩
+		{"/downloads/a", "", true, false},          // 排除的路径前缀
+		{"/image", "", true, false},                // 非文本内容
+		{"/empty", "", true, false},                // 204 无响应体
+		{"/report.csv", "", true, true},            // 完整下载
+		{"/report.csv", "bytes=8-15", true, false}, // 206 分段下载，Content-Range 按未压缩字节计算
+		{"/stream", "", true, true},                // 写入前 Flush
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.acceptGzip {
+			req.Header.Set("Accept-Encoding", "gzip, deflate")
+		}
+		if tt.rangeHdr != "" {
+			req.Header.Set("Range", tt.rangeHdr)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		// 以实际随响应头发送的值判断（w.Header() 在写出后仍可被修改）
+		if got := w.Result().Header.Get("Content-Encoding") == "gzip"; got != tt.compressed {
+			t.Errorf("%s gzip=%v: compressed = %v, want %v", tt.path, tt.acceptGzip, got, tt.compressed)
+			continue
+		}
+		if !tt.compressed {
+			if tt.rangeHdr != "" && (w.Code != http.StatusPartialContent || w.Body.String() != "id,name\n") {
+				t.Errorf("%s Range %s: %d %q", tt.path, tt.rangeHdr, w.Code, w.Body.String())
+			}
+			continue
+		}
+		zr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, _ := io.ReadAll(zr)
+		want := body
+		if tt.path == "/report.csv" {
+			want = strings.Repeat("id,name\n", 100)
+		}
+		if string(plain) != want || w.Result().Header.Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: 解压后内容不一致或缺少 Vary 头", tt.path)
+		}
+	}
+}
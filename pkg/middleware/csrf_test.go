@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// TestCSRFWithMethodOverride 表单以 POST + _method 提交 DELETE，令牌与会话一致时放行
+func TestCSRFWithMethodOverride(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(session.Start(&config.SessionConfig{Store: "cookie", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}, nil, nil))
+	r.Use(CSRF(WithCSRFExcept("/api/")))
+	r.GET("/posts/1", func(c *gin.Context) { c.String(http.StatusOK, session.CSRFToken(c)) })
+	r.DELETE("/posts/1", func(c *gin.Context) { c.String(http.StatusOK, "deleted") })
+	r.POST("/api/posts", func(c *gin.Context) { c.String(http.StatusOK, "api") })
+	h := MethodOverride(r)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	token := w.Body.String()
+	cookies := w.Result().Cookies()
+	if token == "" || len(cookies) == 0 {
+		t.Fatalf("未生成令牌: %q", token)
+	}
+
+	submit := func(path string, form url.Values, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if header != "" {
+			req.Header.Set(CSRFHeader, header)
+		}
+		req.AddCookie(cookies[len(cookies)-1])
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		form     url.Values
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{"form token", "/posts/1", url.Values{"_method": {"delete"}, "_csrf": {token}}, "", http.StatusOK, "deleted"},
+		{"header token", "/posts/1", url.Values{"_method": {"DELETE"}}, token, http.StatusOK, "deleted"},
+		{"missing token", "/posts/1", url.Values{"_method": {"DELETE"}}, "", http.StatusForbidden, "CSRF Token Mismatch"},
+		{"wrong token", "/posts/1", url.Values{"_method": {"DELETE"}, "_csrf": {"nope"}}, "", http.StatusForbidden, "CSRF Token Mismatch"},
+		{"except", "/api/posts", url.Values{}, "", http.StatusOK, "api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := submit(tt.path, tt.form, tt.header)
+			if w.Code != tt.wantCode || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("got %d %q, want %d %q", w.Code, w.Body.String(), tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestMethodOverrideIgnored 仅 POST 请求可伪造方法，且只接受 PUT、PATCH、DELETE
+func TestMethodOverrideIgnored(t *testing.T) {
+	var got string
+	h := MethodOverride(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r.Method }))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("_method=CONNECT"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != http.MethodPost {
+		t.Errorf("不支持的方法被伪造为 %s", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?_method=DELETE", nil)
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != http.MethodGet {
+		t.Errorf("GET 请求被伪造为 %s", got)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"_method":"PUT"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-HTTP-Method-Override", "patch")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != http.MethodPatch {
+		t.Errorf("请求头伪造 = %s", got)
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+// maxDeadlineMargin 截止时间相对 WriteTimeout 提前的最大余量
+const maxDeadlineMargin = time.Second
+
+// deadlineConfig 请求截止时间中间件配置
+type deadlineConfig struct {
+	skipper func(*gin.Context) bool
+}
+
+// DeadlineOption 请求截止时间配置选项
+type DeadlineOption func(*deadlineConfig)
+
+// WithDeadlineSkipper 设置跳过函数，返回 true 时该请求不设截止时间（如 SSE、WebSocket 长连接）
+func WithDeadlineSkipper(fn func(*gin.Context) bool) DeadlineOption {
+	return func(c *deadlineConfig) { c.skipper = fn }
+}
+
+// DeadlineFor 返回与服务端 WriteTimeout 对应的请求截止时长：提前 WriteTimeout 的 1/10（最多 1 秒），
+// 留出写出错误响应的时间。writeTimeout <= 0 时返回 0
+func DeadlineFor(writeTimeout time.Duration) time.Duration {
+	if writeTimeout <= 0 {
+		return 0
+	}
+	return writeTimeout - min(writeTimeout/10, maxDeadlineMargin)
+}
+
+// Deadline 请求截止时间中间件：为请求上下文设置 timeout 后到期的截止时间，写回 c.Request，
+// 之后以 request.Context(c) 或 c.Request.Context() 执行的 SQL、Redis 命令与出站 HTTP 请求到期即取消，
+// 不再为客户端已收不到的响应消耗资源：
+//
+//	r.Use(middleware.Deadline(middleware.DeadlineFor(60 * time.Second)))
+//
+// 到期时处理器尚未写出响应的，返回 503（处理器返回的 context.DeadlineExceeded 同样由 WriteError 转为 503）。
+// timeout <= 0 时不设截止时间
+func Deadline(timeout time.Duration, opts ...DeadlineOption) gin.HandlerFunc {
+	cfg := &deadlineConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		if timeout <= 0 || (cfg.skipper != nil && cfg.skipper(c)) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if stderrors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() && len(c.Errors) == 0 {
+			response.Fail(c, errDeadlineExceeded(ctx.Err()))
+		}
+	}
+}
+
+// errDeadlineExceeded 请求处理超过截止时间时的响应错误
+func errDeadlineExceeded(cause error) *errors.AppError {
+	return errors.New(errors.ServiceUnavailable, "请求处理超时，请稍后再试", cause)
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+func TestDeadlineFor(t *testing.T) {
+	cases := map[time.Duration]time.Duration{
+		0:                0,
+		5 * time.Second:  4500 * time.Millisecond,
+		60 * time.Second: 59 * time.Second,
+	}
+	for in, want := range cases {
+		if got := DeadlineFor(in); got != want {
+			t.Errorf("DeadlineFor(%v) = %v, 期望 %v", in, got, want)
+		}
+	}
+}
+
+// TestDeadline 截止时间写入请求上下文，到期后下游调用取消，未写出响应时返回 503
+func TestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler(), Deadline(20*time.Millisecond, WithDeadlineSkipper(func(c *gin.Context) bool {
+		return c.Request.URL.Path == "/stream"
+	})))
+	r.GET("/fast", func(c *gin.Context) {
+		if _, ok := request.Context(c).Deadline(); !ok {
+			t.Error("请求上下文应带有截止时间")
+		}
+		c.String(http.StatusOK, "ok")
+	})
+	r.GET("/slow", func(c *gin.Context) {
+		<-request.Context(c).Done()
+	})
+	r.GET("/error", func(c *gin.Context) {
+		ctx := request.Context(c)
+		<-ctx.Done()
+		_ = c.Error(ctx.Err())
+	})
+	r.GET("/stream", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			t.Error("跳过的请求不应设置截止时间")
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	do := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := do("/fast"); w.Code != http.StatusOK {
+		t.Errorf("/fast status = %d", w.Code)
+	}
+	for _, path := range []string{"/slow", "/error"} {
+		if w := do(path); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s status = %d body = %s", path, w.Code, w.Body.String())
+		}
+	}
+	if w := do("/stream"); w.Code != http.StatusNoContent {
+		t.Errorf("/stream status = %d", w.Code)
+	}
+}
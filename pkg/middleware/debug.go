@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderDebugToken 访问调试端点时携带管理令牌的请求头
+const HeaderDebugToken = "X-Debug-Token"
+
+// DebugAuth 调试端点（pprof、expvar 等）访问控制：
+// debug 模式下直接放行；否则要求请求携带与 token 一致的管理令牌，token 为空时一律拒绝。
+//
+// 令牌可通过 X-Debug-Token 头、Authorization: Bearer <token> 或查询参数 token 传递，
+// 后者便于直接使用 go tool pprof：
+//
+//	go tool pprof "https://example.com/debug/pprof/heap?token=<token>"
+func DebugAuth(token string, debug bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if debug {
+			c.Next()
+			return
+		}
+		if token == "" || subtle.ConstantTimeCompare([]byte(debugToken(c)), []byte(token)) != 1 {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Next()
+	}
+}
+
+// debugToken 从请求中提取管理令牌
+func debugToken(c *gin.Context) string {
+	if t := c.GetHeader(HeaderDebugToken); t != "" {
+		return t
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return c.Query("token")
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDebugAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(token string, debug bool) *gin.Engine {
+		r := gin.New()
+		r.GET("/debug/vars", DebugAuth(token, debug), func(c *gin.Context) {
+			c.String(http.StatusOK, "ok")
+		})
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		debug  bool
+		header map[string]string
+		query  string
+		want   int
+	}{
+		{"debug mode", "", true, nil, "", http.StatusOK},
+		{"no token configured", "", false, map[string]string{HeaderDebugToken: ""}, "", http.StatusNotFound},
+		{"missing token", "secret", false, nil, "", http.StatusNotFound},
+		{"wrong token", "secret", false, map[string]string{HeaderDebugToken: "guess"}, "", http.StatusNotFound},
+		{"header token", "secret", false, map[string]string{HeaderDebugToken: "secret"}, "", http.StatusOK},
+		{"bearer token", "secret", false, map[string]string{"Authorization": "Bearer secret"}, "", http.StatusOK},
+		{"query token", "secret", false, nil, "?token=secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/debug/vars"+tt.query, nil)
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		newRouter(tt.token, tt.debug).ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
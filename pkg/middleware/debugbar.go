@@ -0,0 +1,313 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	gsessions "github.com/gorilla/sessions"
+)
+
+// DebugToolbarKey gin.Context 中存放 *DebugToolbarData 的键名
+const DebugToolbarKey = "debug_toolbar"
+
+// RenderedTemplate 一次页面渲染记录
+type RenderedTemplate struct {
+	Name     string
+	Cached   bool
+	Duration time.Duration
+}
+
+// EmittedEvent 一次事件触发记录
+type EmittedEvent struct {
+	Name string
+	At   time.Duration // 相对请求开始的时间
+}
+
+// DebugToolbarData 调试工具栏收集的请求数据，并发安全
+type DebugToolbarData struct {
+	start     time.Time
+	queries   *database.QueryLog
+	mu        sync.Mutex
+	templates []RenderedTemplate
+	events    []EmittedEvent
+}
+
+// Templates 返回已渲染的模板
+func (d *DebugToolbarData) Templates() []RenderedTemplate {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]RenderedTemplate(nil), d.templates...)
+}
+
+// Events 返回已触发的事件
+func (d *DebugToolbarData) Events() []EmittedEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]EmittedEvent(nil), d.events...)
+}
+
+// Queries 返回已执行的 SQL
+func (d *DebugToolbarData) Queries() []database.Query {
+	return d.queries.Queries()
+}
+
+func (d *DebugToolbarData) recordEvent(name string) {
+	d.mu.Lock()
+	d.events = append(d.events, EmittedEvent{Name: name, At: time.Since(d.start)})
+	d.mu.Unlock()
+}
+
+// debugToolbarFromContext 从上下文获取工具栏数据，兼容 gin.Context 与请求上下文
+func debugToolbarFromContext(ctx context.Context) *DebugToolbarData {
+	if ctx == nil {
+		return nil
+	}
+	if d, ok := ctx.Value(debugToolbarCtxKey{}).(*DebugToolbarData); ok {
+		return d
+	}
+	d, _ := ctx.Value(DebugToolbarKey).(*DebugToolbarData)
+	return d
+}
+
+type debugToolbarCtxKey struct{}
+
+// debugToolbarConfig 调试工具栏配置
+type debugToolbarConfig struct {
+	bus      *eventbus.EventBus
+	excludes []string
+}
+
+// DebugToolbarOption 调试工具栏配置选项
+type DebugToolbarOption func(*debugToolbarConfig)
+
+// WithDebugToolbarEventBus 指定记录事件的事件总线（默认 eventbus.Default()）
+func WithDebugToolbarEventBus(bus *eventbus.EventBus) DebugToolbarOption {
+	return func(c *debugToolbarConfig) { c.bus = bus }
+}
+
+// WithDebugToolbarExclude 排除指定路径前缀，如 "/api"
+func WithDebugToolbarExclude(prefixes ...string) DebugToolbarOption {
+	return func(c *debugToolbarConfig) { c.excludes = append(c.excludes, prefixes...) }
+}
+
+// DebugToolbar 开发模式调试工具栏：在 HTML 响应的 </body> 前注入一个工具栏，展示
+// 请求耗时、SQL 及耗时、渲染的模板与缓存命中、会话数据、触发的事件以及内存统计。
+//
+// 数据来源：
+//   - SQL：需为数据库注册 database.NewQueryLogger() 插件，并以 db.WithContext(c) 执行查询
+//   - 模板：template 包渲染到 c.Writer 时自动上报（c.Writer 被其他中间件再次包装时无法统计）
+//   - 事件：以 EmitCtx(c, ...) 触发的事件；不带上下文的 Emit 无法归属到请求，不会显示
+//
+// 仅用于开发环境，需注册在 SessionStart 之后
+func DebugToolbar(opts ...DebugToolbarOption) gin.HandlerFunc {
+	cfg := &debugToolbarConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	if cfg.bus == nil {
+		cfg.bus = eventbus.Default()
+	}
+
+	// 以最高优先级订阅全部事件，保证被中途停止传播的事件也会被记录
+	cfg.bus.OnE("**", func(ctx context.Context, args ...interface{}) error {
+		if d := debugToolbarFromContext(ctx); d != nil {
+			if meta, ok := eventbus.EventFromContext(ctx); ok {
+				d.recordEvent(meta.Name)
+			}
+		}
+		return nil
+	}, eventbus.WithPriority(math.MaxInt))
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range cfg.excludes {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		ctx, queries := database.WithQueryLog(c.Request.Context())
+		data := &DebugToolbarData{start: time.Now(), queries: queries}
+		c.Request = c.Request.WithContext(context.WithValue(ctx, debugToolbarCtxKey{}, data))
+		c.Set(DebugToolbarKey, data)
+		c.Set(database.QueryLogKey, queries)
+
+		w := &debugToolbarWriter{ResponseWriter: c.Writer, data: data}
+		c.Writer = w
+		c.Next()
+
+		if w.buf == nil {
+			return
+		}
+		body := w.buf.Bytes()
+		if idx := bytes.LastIndex(body, []byte("</body>")); idx >= 0 {
+			var bar bytes.Buffer
+			if err := debugToolbarTemplate.Execute(&bar, buildDebugToolbarView(c, data)); err == nil {
+				body = append(body[:idx:idx], append(bar.Bytes(), body[idx:]...)...)
+			}
+		}
+		w.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+// debugToolbarWriter 缓冲 HTML 响应以便注入工具栏，其他类型直接写出；
+// 同时实现 template.RenderRecorder 接收模板渲染记录
+type debugToolbarWriter struct {
+	gin.ResponseWriter
+	data    *DebugToolbarData
+	decided bool
+	buf     *bytes.Buffer
+}
+
+// Unwrap 返回被包装的写入器
+func (w *debugToolbarWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RecordRender 实现 template.RenderRecorder
+func (w *debugToolbarWriter) RecordRender(name string, cached bool, elapsed time.Duration) {
+	w.data.mu.Lock()
+	w.data.templates = append(w.data.templates, RenderedTemplate{Name: name, Cached: cached, Duration: elapsed})
+	w.data.mu.Unlock()
+}
+
+func (w *debugToolbarWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		if strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+			w.buf = &bytes.Buffer{}
+		}
+	}
+	if w.buf == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+func (w *debugToolbarWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// debugToolbarView 工具栏模板数据
+type debugToolbarView struct {
+	Method     string
+	Path       string
+	Status     int
+	Duration   time.Duration
+	Queries    []database.Query
+	QueryTime  time.Duration
+	Templates  []RenderedTemplate
+	Session    []errors.KV
+	Events     []EmittedEvent
+	HeapAlloc  string
+	Sys        string
+	NumGC      uint32
+	Goroutines int
+}
+
+func buildDebugToolbarView(c *gin.Context, data *DebugToolbarData) debugToolbarView {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return debugToolbarView{
+		Method:     c.Request.Method,
+		Path:       c.Request.URL.Path,
+		Status:     c.Writer.Status(),
+		Duration:   time.Since(data.start),
+		Queries:    data.Queries(),
+		QueryTime:  data.queries.Total(),
+		Templates:  data.Templates(),
+		Session:    sessionValues(c),
+		Events:     data.Events(),
+		HeapAlloc:  formatBytes(mem.HeapAlloc),
+		Sys:        formatBytes(mem.Sys),
+		NumGC:      mem.NumGC,
+		Goroutines: runtime.NumGoroutine(),
+	}
+}
+
+// sessionValues 读取当前会话的全部键值（未启用会话中间件时为空）
+func sessionValues(c *gin.Context) []errors.KV {
+	if _, exists := c.Get(sessions.DefaultKey); !exists {
+		return nil
+	}
+	s, ok := sessions.Default(c).(interface{ Session() *gsessions.Session })
+	if !ok {
+		return nil
+	}
+	gs := s.Session()
+	if gs == nil {
+		return nil
+	}
+	kvs := make([]errors.KV, 0, len(gs.Values))
+	for k, v := range gs.Values {
+		kvs = append(kvs, errors.KV{Key: fmt.Sprint(k), Value: fmt.Sprintf("%+v", v)})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// debugToolbarTemplate 工具栏 HTML，样式与脚本均内联且以 gdt- 前缀隔离，避免影响页面
+var debugToolbarTemplate = template.Must(template.New("debug_toolbar").Parse(`
+<div id="gdt" style="position:fixed;left:0;right:0;bottom:0;z-index:2147483647;font:12px/1.5 Menlo,Consolas,monospace;color:#e5e7eb">
+<style>
+#gdt .gdt-bar{display:flex;gap:16px;align-items:center;background:#111827;padding:4px 12px;border-top:2px solid #6366f1}
+#gdt .gdt-tab{cursor:pointer;color:#a5b4fc}
+#gdt .gdt-panel{display:none;max-height:40vh;overflow:auto;background:#1f2937;padding:8px 12px}
+#gdt .gdt-panel.gdt-open{display:block}
+#gdt table{border-collapse:collapse;width:100%}
+#gdt td{padding:2px 8px;border-bottom:1px solid #374151;vertical-align:top;word-break:break-all}
+</style>
+<div class="gdt-panel" id="gdt-sql"><table>{{ range .Queries }}<tr><td>{{ .Duration }}</td><td>{{ .Rows }} rows</td><td>{{ .SQL }}{{ if .Error }} <b style="color:#f87171">{{ .Error }}</b>{{ end }}</td></tr>{{ else }}<tr><td>无 SQL（需注册 database.NewQueryLogger 并使用 db.WithContext(c)）</td></tr>{{ end }}</table></div>
+<div class="gdt-panel" id="gdt-tpl"><table>{{ range .Templates }}<tr><td>{{ .Name }}</td><td>{{ if .Cached }}cache hit{{ else }}loaded{{ end }}</td><td>{{ .Duration }}</td></tr>{{ else }}<tr><td>未渲染模板</td></tr>{{ end }}</table></div>
+<div class="gdt-panel" id="gdt-session"><table>{{ range .Session }}<tr><td>{{ .Key }}</td><td>{{ .Value }}</td></tr>{{ else }}<tr><td>会话为空</td></tr>{{ end }}</table></div>
+<div class="gdt-panel" id="gdt-events"><table>{{ range .Events }}<tr><td>+{{ .At }}</td><td>{{ .Name }}</td></tr>{{ else }}<tr><td>未触发事件</td></tr>{{ end }}</table></div>
+<div class="gdt-bar">
+<b>{{ .Method }} {{ .Path }}</b><span>{{ .Status }}</span><span>{{ .Duration }}</span>
+<span class="gdt-tab" data-gdt="gdt-sql">SQL {{ len .Queries }} ({{ .QueryTime }})</span>
+<span class="gdt-tab" data-gdt="gdt-tpl">模板 {{ len .Templates }}</span>
+<span class="gdt-tab" data-gdt="gdt-session">会话 {{ len .Session }}</span>
+<span class="gdt-tab" data-gdt="gdt-events">事件 {{ len .Events }}</span>
+<span>内存 {{ .HeapAlloc }} / {{ .Sys }} · GC {{ .NumGC }} · goroutines {{ .Goroutines }}</span>
+<span class="gdt-tab" style="margin-left:auto" onclick="document.getElementById('gdt').remove()">✕</span>
+</div>
+<script>
+document.querySelectorAll('#gdt .gdt-tab[data-gdt]').forEach(function (t) {
+  t.addEventListener('click', function () {
+    document.querySelectorAll('#gdt .gdt-panel').forEach(function (p) {
+      p.classList.toggle('gdt-open', p.id === t.dataset.gdt && !p.classList.contains('gdt-open'));
+    });
+  });
+});
+</script>
+</div>
+`))
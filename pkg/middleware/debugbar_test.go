@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+func TestDebugToolbar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	bus := eventbus.New()
+	r := gin.New()
+	r.Use(DebugToolbar(WithDebugToolbarEventBus(bus), WithDebugToolbarExclude("/skip")))
+	r.GET("/page", func(c *gin.Context) {
+		_ = bus.EmitCtx(c, "user.viewed")
+		c.Writer.(interface {
+			RecordRender(string, bool, time.Duration)
+		}).RecordRender("layouts/main:index", true, time.Millisecond)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<html><body><p>hi</p></body></html>"))
+	})
+	r.GET("/json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	r.GET("/skip", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html", []byte("<body></body>"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/page", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, `id="gdt"`) || !strings.HasSuffix(body, "</body></html>") {
+		t.Fatalf("toolbar not injected before </body>: %s", body)
+	}
+	for _, want := range []string{"user.viewed", "layouts/main:index", "cache hit", "GET /page"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("toolbar missing %q", want)
+		}
+	}
+	if w.Header().Get("Content-Length") != strconv.Itoa(len(body)) {
+		t.Errorf("Content-Length = %s, body = %d", w.Header().Get("Content-Length"), len(body))
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/json", nil))
+	if strings.Contains(w.Body.String(), "gdt") {
+		t.Error("toolbar injected into JSON response")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/skip", nil))
+	if w.Body.String() != "<body></body>" {
+		t.Errorf("excluded path modified: %s", w.Body.String())
+	}
+}
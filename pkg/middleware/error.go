@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+// ErrorPageRenderer 以模板渲染错误页，模板不存在或渲染失败时返回 false，由调用方回退为默认输出
+type ErrorPageRenderer func(c *gin.Context, status int, data map[string]any) bool
+
+var errorPageRenderer atomic.Value // ErrorPageRenderer
+
+// SetErrorPageRenderer 设置页面请求的错误页渲染函数，template 包已自动注册（渲染 errors/<状态码> 模板）
+func SetErrorPageRenderer(fn ErrorPageRenderer) {
+	errorPageRenderer.Store(fn)
+}
+
+// ErrorHandler 统一错误处理中间件：处理器通过 c.Error(err) 记录错误后直接 return，
+// 处理链结束后由本中间件将最后一个错误转换为响应（见 WriteError）；处理器已写出响应时不再处理。
+//
+//	func (u *UserController) Show(c *gin.Context) {
+//	    user, err := u.Users.Get(c, id)
+//	    if err != nil {
+//	        _ = c.Error(err)
+//	        return
+//	    }
+//	    response.Success(c, user)
+//	}
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		WriteError(c, c.Errors.Last().Err)
+	}
+}
+
+// WriteError 按错误类型输出响应，也是返回 error 的处理器（router.HandlerFunc）的统一出口：
+//   - *errors.AppError：页面请求优先渲染 errors/<状态码> 模板，否则输出统一 JSON
+//   - validator.ValidationErrors（绑定校验失败）：转为校验错误（400）后同上
+//   - 请求截止时间（见 Deadline）到期导致的 context.DeadlineExceeded：转为 503 后同上
+//   - 其他错误（含 *errors.TemplateError）：API/AJAX 请求输出 500 JSON；页面请求渲染错误页（debug 模式显示详情）
+func WriteError(c *gin.Context, err error) {
+	var appErr *errors.AppError
+	var verrs validator.ValidationErrors
+	switch {
+	case stderrors.As(err, &appErr):
+	case stderrors.As(err, &verrs):
+		appErr = errors.NewValidationError(verrs.Error(), err)
+	case stderrors.Is(err, context.DeadlineExceeded) && c.Request.Context().Err() != nil:
+		appErr = errDeadlineExceeded(err)
+	}
+
+	if appErr != nil {
+		if !request.IsAjax(c) && renderErrorPage(c, appErr) {
+			c.Abort()
+			return
+		}
+		response.Fail(c, appErr)
+		return
+	}
+
+	// 页面（非 AJAX/JSON）请求：渲染 HTML 错误页，行为与 panic / 模板错误一致
+	if !request.IsAjax(c) {
+		errors.RenderErrorWithContext(c.Writer, err, "", config.MustFetch().IsDevelopment(), ErrorContext(c))
+		c.Abort()
+		return
+	}
+
+	// API 请求：保持统一 JSON 错误响应
+	response.Fail(c, errors.NewInternalServerError(err.Error(), err))
+}
+
+// ErrorContext 构建开发错误页展示的请求上下文（方法、URL、路由参数、请求头与会话）
+func ErrorContext(c *gin.Context) *errors.RequestContext {
+	rc := &errors.RequestContext{Request: c.Request, Session: sessionValues(c)}
+	for _, p := range c.Params {
+		rc.Params = append(rc.Params, errors.KV{Key: p.Key, Value: p.Value})
+	}
+	return rc
+}
+
+// renderErrorPage 以注册的渲染函数输出 AppError 对应的错误页
+func renderErrorPage(c *gin.Context, err *errors.AppError) bool {
+	fn, _ := errorPageRenderer.Load().(ErrorPageRenderer)
+	if fn == nil {
+		return false
+	}
+	status := err.HTTPStatus()
+	return fn(c, status, map[string]any{
+		"Status":     status,
+		"StatusText": http.StatusText(status),
+		"Code":       err.Code,
+		"Message":    err.Localize(request.Locale(c)),
+		"Detail":     err.Detail,
+	})
+}
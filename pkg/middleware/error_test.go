@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/missing", func(c *gin.Context) {
+		_ = c.Error(errors.NewNotFound("用户不存在", nil))
+	})
+	r.GET("/invalid", func(c *gin.Context) {
+		var req struct {
+			Name string `form:"name" binding:"required"`
+		}
+		if err := c.ShouldBindWith(&req, binding.Query); err != nil {
+			_ = c.Error(err)
+		}
+	})
+	r.GET("/boom", func(c *gin.Context) {
+		_ = c.Error(stderrors.New("boom"))
+	})
+	r.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "ok")
+		_ = c.Error(stderrors.New("ignored"))
+	})
+
+	tests := []struct {
+		path     string
+		status   int
+		contains string
+	}{
+		{"/missing", http.StatusNotFound, `"data":"用户不存在"`},
+		{"/invalid", http.StatusBadRequest, `"code":10001`},
+		{"/boom", http.StatusInternalServerError, `"data":"boom"`},
+		{"/written", http.StatusAccepted, "ok"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("%s: %d %s", tt.path, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestErrorPageRenderer 页面请求的业务错误交给注册的错误页渲染函数，未渲染时回退为 JSON
+func TestErrorPageRenderer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var rendered map[string]any
+	SetErrorPageRenderer(func(c *gin.Context, status int, data map[string]any) bool {
+		if status != http.StatusNotFound {
+			return false
+		}
+		rendered = data
+		c.String(status, "error page")
+		return true
+	})
+	defer SetErrorPageRenderer(nil)
+
+	r := gin.New()
+	r.GET("/:code", func(c *gin.Context) {
+		if c.Param("code") == "404" {
+			WriteError(c, errors.NewNotFound("", nil))
+		} else {
+			WriteError(c, errors.NewForbidden("", nil))
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/404", nil))
+	if w.Body.String() != "error page" || rendered["Message"] != "资源不存在" {
+		t.Errorf("错误页 %q %v", w.Body.String(), rendered)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/403", nil))
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), `"code":403`) {
+		t.Errorf("无模板时应输出 JSON: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/geoip"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// geoIPConfig 地区解析配置
+type geoIPConfig struct {
+	allow []string
+	deny  []string
+}
+
+// GeoIPOption 地区解析配置选项
+type GeoIPOption func(*geoIPConfig)
+
+// WithGeoAllow 仅允许来自指定国家（ISO 代码，如 CN、US）的请求，其余返回 403；
+// 无法解析地区的请求（含内网地址）同样被拒绝
+func WithGeoAllow(countries ...string) GeoIPOption {
+	return func(c *geoIPConfig) { c.allow = upperAll(countries) }
+}
+
+// WithGeoDeny 拒绝来自指定国家的请求，返回 403
+func WithGeoDeny(countries ...string) GeoIPOption {
+	return func(c *geoIPConfig) { c.deny = upperAll(countries) }
+}
+
+// GeoIP 地区解析中间件：按 c.ClientIP() 查询地区后写入 gin.Context 与请求上下文，
+// 之后可通过 geoip.Current(c) / geoip.FromContext(ctx) 获取，模板中可使用 .Geo，
+// 请求日志追加 country 字段；查询失败时记录警告并按未解析处理，不影响请求。
+// 需注册在 Logger 之后、Locale 之前（Locale 可按国家推断默认语言，见 WithLocaleCountries）。
+//
+//	db, _ := geoip.Open("storage/GeoLite2-City.mmdb")
+//	r.Use(middleware.GeoIP(geoip.NewCached(db, 10000), middleware.WithGeoDeny("KP")))
+func GeoIP(resolver geoip.Resolver, opts ...GeoIPOption) gin.HandlerFunc {
+	cfg := &geoIPConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		var loc *geoip.Location
+		if ip := net.ParseIP(c.ClientIP()); ip != nil {
+			var err error
+			if loc, err = resolver.Lookup(ip); err != nil {
+				logger.Warnf("GeoIP 查询 %s 失败: %v", ip, err)
+			}
+		}
+
+		var country string
+		if loc != nil {
+			country = loc.CountryCode
+			request.Set(c, request.KeyGeo, loc)
+			c.Request = c.Request.WithContext(geoip.WithLocation(c.Request.Context(), loc))
+			GetLogEntry(c).AddField("country", country)
+		}
+
+		if (len(cfg.allow) > 0 && !slices.Contains(cfg.allow, country)) ||
+			(country != "" && slices.Contains(cfg.deny, country)) {
+			abortWithMessage(c, http.StatusForbidden, "Forbidden")
+			return
+		}
+		c.Next()
+	}
+}
+
+// upperAll 将国家代码统一为大写
+func upperAll(codes []string) []string {
+	out := make([]string, len(codes))
+	for i, code := range codes {
+		out[i] = strings.ToUpper(strings.TrimSpace(code))
+	}
+	return out
+}
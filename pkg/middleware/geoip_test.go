@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/geoip"
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// geoStub 按 IP 返回固定地区
+type geoStub map[string]*geoip.Location
+
+func (s geoStub) Lookup(ip net.IP) (*geoip.Location, error) {
+	return s[ip.String()], nil
+}
+
+func TestGeoIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18n.Register("zh-CN", map[string]string{})
+	resolver := geoStub{
+		"1.1.1.1": {CountryCode: "CN", City: "Shanghai"},
+		"2.2.2.2": {CountryCode: "KP"},
+	}
+	newRouter := func(opts ...GeoIPOption) *gin.Engine {
+		r := gin.New()
+		r.Use(GeoIP(resolver, opts...), Locale(WithLocaleCountries(map[string]string{"CN": "zh-CN"})))
+		r.GET("/", func(c *gin.Context) {
+			city := ""
+			if loc := geoip.FromContext(c.Request.Context()); loc != nil {
+				city = loc.City
+			}
+			c.String(http.StatusOK, city+"|"+request.Locale(c))
+		})
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		ip       string
+		opts     []GeoIPOption
+		wantCode int
+		wantBody string
+	}{
+		{"resolved", "1.1.1.1", nil, http.StatusOK, "Shanghai|zh-CN"},
+		{"unknown", "9.9.9.9", nil, http.StatusOK, "|"},
+		{"denied", "2.2.2.2", []GeoIPOption{WithGeoDeny("kp")}, http.StatusForbidden, ""},
+		{"allowed", "1.1.1.1", []GeoIPOption{WithGeoAllow("CN")}, http.StatusOK, "Shanghai|zh-CN"},
+		{"not allowed", "2.2.2.2", []GeoIPOption{WithGeoAllow("CN")}, http.StatusForbidden, ""},
+		{"unknown not allowed", "9.9.9.9", []GeoIPOption{WithGeoAllow("CN")}, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.ip + ":1234"
+		newRouter(tt.opts...).ServeHTTP(w, req)
+		if w.Code != tt.wantCode || (tt.wantCode == http.StatusOK && w.Body.String() != tt.wantBody) {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, w.Code, w.Body.String(), tt.wantCode, tt.wantBody)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"expvar"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+// maxInFlightConfig 并发限制中间件配置
+type maxInFlightConfig struct {
+	skipper    func(*gin.Context) bool
+	name       string
+	retryAfter time.Duration
+}
+
+// MaxInFlightOption 并发限制配置选项
+type MaxInFlightOption func(*maxInFlightConfig)
+
+// WithMaxInFlightSkipper 设置跳过函数，返回 true 时该请求不占用并发名额（如健康检查、SSE 长连接）
+func WithMaxInFlightSkipper(fn func(*gin.Context) bool) MaxInFlightOption {
+	return func(c *maxInFlightConfig) { c.skipper = fn }
+}
+
+// WithMaxInFlightName 设置名称，并将统计（处理中、排队中、放行数、拒绝数）发布到 expvar（/debug/vars 中 inflight 下的 <name>），
+// 同名的实例重复创建时（如测试中重建路由）以最后创建的为准
+func WithMaxInFlightName(name string) MaxInFlightOption {
+	return func(c *maxInFlightConfig) { c.name = name }
+}
+
+// WithMaxInFlightRetryAfter 设置拒绝时 Retry-After 建议的重试间隔（默认 1 秒）
+func WithMaxInFlightRetryAfter(d time.Duration) MaxInFlightOption {
+	return func(c *maxInFlightConfig) { c.retryAfter = d }
+}
+
+// MaxInFlightStats 并发限制统计
+type MaxInFlightStats struct {
+	InFlight int64 `json:"in_flight"` // 处理中的请求数
+	Waiting  int64 `json:"waiting"`   // 排队等待的请求数
+	Allowed  int64 `json:"allowed"`   // 放行的请求数
+	Shed     int64 `json:"shed"`      // 排队超时被拒绝的请求数
+}
+
+// inflightVars /debug/vars 中的 inflight，按名称保存各并发限制中间件的统计
+var inflightVars = expvar.NewMap("inflight")
+
+// maxInFlightStats 并发限制中间件的运行统计
+type maxInFlightStats struct {
+	inFlight atomic.Int64
+	waiting  atomic.Int64
+	allowed  atomic.Int64
+	shed     atomic.Int64
+}
+
+// snapshot 返回统计快照
+func (s *maxInFlightStats) snapshot() MaxInFlightStats {
+	return MaxInFlightStats{InFlight: s.inFlight.Load(), Waiting: s.waiting.Load(), Allowed: s.allowed.Load(), Shed: s.shed.Load()}
+}
+
+// MaxInFlight 并发限制（削峰）中间件：同时进入处理器的请求不超过 n 个，超出的请求排队等待空闲名额，
+// 等待超过 queueTimeout（<= 0 时不排队）仍未轮到时返回 503 与 Retry-After。
+// 与按每秒请求数计的 RateLimit 不同，它按实际占用的处理能力限流，慢查询堆积时能保护数据库：
+//
+//	r.Use(middleware.MaxInFlight(200, 3*time.Second, middleware.WithMaxInFlightName("global")))
+//
+// 排队期间客户端断开时直接中止，不再占用名额；请求截止时间（见 Deadline）到期时同样返回 503
+func MaxInFlight(n int, queueTimeout time.Duration, opts ...MaxInFlightOption) gin.HandlerFunc {
+	cfg := &maxInFlightConfig{retryAfter: time.Second}
+	for _, o := range opts {
+		o(cfg)
+	}
+	stats := &maxInFlightStats{}
+	if cfg.name != "" {
+		inflightVars.Set(cfg.name, expvar.Func(func() any { return stats.snapshot() }))
+	}
+	slots := make(chan struct{}, max(n, 1))
+	retryAfter := strconv.Itoa(max(ceilSeconds(cfg.retryAfter), 1))
+
+	return func(c *gin.Context) {
+		if cfg.skipper != nil && cfg.skipper(c) {
+			c.Next()
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			if !waitSlot(c, slots, queueTimeout, stats) {
+				if stderrors.Is(c.Request.Context().Err(), context.Canceled) {
+					c.Abort()
+					return
+				}
+				stats.shed.Add(1)
+				c.Header("Retry-After", retryAfter)
+				response.Fail(c, errors.New(errors.ServiceUnavailable, "服务繁忙，请稍后再试", stderrors.New("并发请求排队超时")))
+				return
+			}
+		}
+
+		stats.allowed.Add(1)
+		stats.inFlight.Add(1)
+		defer func() {
+			stats.inFlight.Add(-1)
+			<-slots
+		}()
+		c.Next()
+	}
+}
+
+// waitSlot 排队等待空闲名额，超时、客户端断开或请求截止时间到期时返回 false
+func waitSlot(c *gin.Context, slots chan struct{}, timeout time.Duration, stats *maxInFlightStats) bool {
+	if timeout <= 0 {
+		return false
+	}
+	stats.waiting.Add(1)
+	defer stats.waiting.Add(-1)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
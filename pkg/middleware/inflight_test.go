@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestMaxInFlight 名额占满时排队，排队超时返回 503，名额释放后排队的请求继续处理
+func TestMaxInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	release := make(chan struct{})
+	entered := make(chan struct{}, 4)
+
+	r := gin.New()
+	r.Use(MaxInFlight(1, 50*time.Millisecond, WithMaxInFlightName("test"), WithMaxInFlightRetryAfter(2*time.Second),
+		WithMaxInFlightSkipper(func(c *gin.Context) bool { return c.Request.URL.Path == "/health" })))
+	r.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.String(http.StatusOK, "ok")
+	})
+	r.GET("/health", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	do := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if w := do("/slow"); w.Code != http.StatusOK {
+			t.Errorf("第一个请求 status = %d", w.Code)
+		}
+	}()
+	<-entered
+
+	// 排队超时
+	w := do("/slow")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "2" {
+		t.Errorf("status = %d Retry-After = %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	// 跳过的请求不占用名额
+	if w := do("/health"); w.Code != http.StatusOK {
+		t.Errorf("health status = %d", w.Code)
+	}
+
+	// 排队期间名额释放
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if w := do("/slow"); w.Code != http.StatusOK {
+			t.Errorf("排队请求 status = %d", w.Code)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	release <- struct{}{}
+	<-entered
+	release <- struct{}{}
+	wg.Wait()
+
+	stats := inflightVars.Get("test").(expvar.Func)().(MaxInFlightStats)
+	if stats.Allowed != 2 || stats.Shed != 1 || stats.InFlight != 0 || stats.Waiting != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// 同名的新实例替换旧统计
+	MaxInFlight(1, 0, WithMaxInFlightName("test"))
+	if stats := inflightVars.Get("test").(expvar.Func)().(MaxInFlightStats); stats != (MaxInFlightStats{}) {
+		t.Errorf("新实例 stats = %+v", stats)
+	}
+}
+
+// TestMaxInFlightCanceled 排队期间客户端断开时中止，不计入拒绝数；截止时间到期时返回 503
+func TestMaxInFlightCanceled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	release := make(chan struct{})
+	entered := make(chan struct{})
+
+	r := gin.New()
+	r.Use(MaxInFlight(1, time.Minute))
+	r.GET("/", func(c *gin.Context) {
+		close(entered)
+		<-release
+	})
+	go r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	<-entered
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("断开后不应写出响应: status = %d body = %q", w.Code, w.Body.String())
+	}
+
+	// 请求截止时间到期不是客户端断开，仍返回 503
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("截止时间到期 status = %d", w.Code)
+	}
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla-go/go-framework/pkg/clock"
 	"github.com/gorilla-go/go-framework/pkg/config"
 	pkgErrors "github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
 	"github.com/gorilla-go/go-framework/pkg/response"
 )
 
@@ -46,7 +48,7 @@ func GenerateToken(userID uint, username string, role string, cfg *config.JWTCon
 		return "", ErrConfigNotLoaded
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	expireTime := now.Add(time.Duration(cfg.Expire) * time.Hour)
 
 	claims := JWTClaims{
@@ -80,7 +82,7 @@ func ParseToken(tokenString string, cfg *config.JWTConfig) (*JWTClaims, error) {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidSignMethod, token.Header["alg"])
 		}
 		return []byte(cfg.Secret), nil
-	})
+	}, jwt.WithTimeFunc(clock.Now))
 
 	if err != nil {
 		return nil, fmt.Errorf("令牌解析失败: %w", err)
@@ -126,13 +128,7 @@ func JWTMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 // RoleMiddleware 角色验证中间件
 func RoleMiddleware(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get(ContextKeyRole)
-		if !exists {
-			response.Fail(c, pkgErrors.NewUnauthorized("未认证", ErrUserNotAuth))
-			return
-		}
-
-		userRole, ok := role.(string)
+		userRole, ok := request.Get[string](c, ContextKeyRole)
 		if !ok {
 			response.Fail(c, pkgErrors.NewUnauthorized("未认证", ErrUserNotAuth))
 			return
@@ -157,22 +153,10 @@ func RoleMiddleware(roles ...string) gin.HandlerFunc {
 
 // GetClaimsFromContext 从 Gin 上下文中获取 JWT Claims
 func GetClaimsFromContext(c *gin.Context) (*JWTClaims, bool) {
-	claims, exists := c.Get(ContextKeyClaims)
-	if !exists {
-		return nil, false
-	}
-
-	jwtClaims, ok := claims.(*JWTClaims)
-	return jwtClaims, ok
+	return request.Get[*JWTClaims](c, ContextKeyClaims)
 }
 
 // GetUserIDFromContext 从 Gin 上下文中获取用户 ID
 func GetUserIDFromContext(c *gin.Context) (uint, bool) {
-	userID, exists := c.Get(ContextKeyUserID)
-	if !exists {
-		return 0, false
-	}
-
-	id, ok := userID.(uint)
-	return id, ok
+	return request.Get[uint](c, ContextKeyUserID)
 }
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/geoip"
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// localeConfig 语言解析配置
+type localeConfig struct {
+	param     string
+	countries map[string]string
+}
+
+// LocaleOption 语言解析配置选项
+type LocaleOption func(*localeConfig)
+
+// WithLocaleParam 指定语言的查询参数名（默认 lang），为空时不读取查询参数
+func WithLocaleParam(name string) LocaleOption {
+	return func(c *localeConfig) { c.param = name }
+}
+
+// WithLocaleCountries 按客户端所在国家推断默认语言，如 {"CN": "zh-CN", "US": "en"}，
+// 在查询参数与 Accept-Language 均未命中时使用；国家由 GeoIP 中间件解析，需注册在 Locale 之前
+func WithLocaleCountries(countries map[string]string) LocaleOption {
+	return func(c *localeConfig) { c.countries = countries }
+}
+
+// Locale 语言解析中间件，按 查询参数（?lang=en）→ Accept-Language →（可选）所在国家 的顺序选出第一个已在 i18n 注册的语言
+// （"en-US" 未注册时匹配 "en"），写入上下文供 request.Locale 读取；均未命中时不设置，使用默认语言。
+//
+//	r.Use(middleware.Locale())
+//	response.Fail(c, errors.NewNotFound("", nil)) // Accept-Language: en → "message": "Not found"
+func Locale(opts ...LocaleOption) gin.HandlerFunc {
+	cfg := &localeConfig{param: "lang"}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		var candidates []string
+		if cfg.param != "" {
+			candidates = append(candidates, c.Query(cfg.param))
+		}
+		candidates = append(candidates, acceptLanguages(c.GetHeader("Accept-Language"))...)
+		if loc := geoip.Current(c); loc != nil && cfg.countries != nil {
+			candidates = append(candidates, cfg.countries[loc.CountryCode])
+		}
+		for _, l := range candidates {
+			if locale := supportedLocale(l); locale != "" {
+				request.Set(c, request.KeyLocale, locale)
+				break
+			}
+		}
+		c.Next()
+	}
+}
+
+// supportedLocale 返回已注册的语言（自身或其基础语言），均未注册时返回空字符串
+func supportedLocale(locale string) string {
+	locale = i18n.Normalize(locale)
+	if locale == "" {
+		return ""
+	}
+	if i18n.Has(locale) {
+		return locale
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok && i18n.Has(base) {
+		return base
+	}
+	return ""
+}
+
+// acceptLanguages 解析 Accept-Language，按 q 权重从高到低返回语言（忽略 * 与 q=0）
+func acceptLanguages(header string) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			langs = append(langs, lang{tag, q})
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
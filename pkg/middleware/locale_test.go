@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/i18n"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+func TestLocaleResolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18n.Register("en", map[string]string{})
+	i18n.Register("ja-JP", map[string]string{})
+
+	r := gin.New()
+	r.Use(Locale())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, request.Locale(c))
+	})
+
+	tests := []struct {
+		query, accept, want string
+	}{
+		{"", "", ""},
+		{"", "fr-FR, de;q=0.5", ""},
+		{"", "en-US,en;q=0.9", "en"},
+		{"", "en;q=0.5, ja-jp;q=0.8", "ja-JP"},
+		{"", "ja;q=0, en", "en"},
+		{"?lang=ja_jp", "en", "ja-JP"},
+		{"?lang=fr", "en", "en"}, // 未注册的查询参数被忽略
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Language", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("query=%q accept=%q: %q, want %q", tt.query, tt.accept, got, tt.want)
+		}
+	}
+}
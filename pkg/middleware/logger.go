@@ -2,11 +2,12 @@ package middleware
 
 import (
 	"bytes"
-	"io"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
 	"go.uber.org/zap"
 )
 
@@ -50,11 +51,10 @@ func Logger(isDev bool) gin.HandlerFunc {
 		entry := &LogEntry{}
 		c.Set(LogEntryKey, entry)
 
-		// dev 模式下读取请求体（读后需还原）
+		// dev 模式下读取请求体（经 request.RawBody 缓存并还原，下游可再次读取）
 		var reqBody string
 		if isDev && c.Request.Body != nil {
-			raw, _ := io.ReadAll(c.Request.Body)
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(raw))
+			raw, _ := request.RawBody(c)
 			if len(raw) > maxBodyLogSize {
 				reqBody = string(raw[:maxBodyLogSize]) + "..."
 			} else if len(raw) > 0 {
@@ -81,6 +81,9 @@ func Logger(isDev bool) gin.HandlerFunc {
 			zap.Int("status", status),
 			zap.Duration("latency", latency),
 		}
+		if id := request.RequestID(c); id != "" {
+			fields = append(fields, zap.String("request_id", id))
+		}
 		if query != "" {
 			fields = append(fields, zap.String("query", query))
 		}
@@ -107,6 +110,10 @@ func Logger(isDev bool) gin.HandlerFunc {
 
 		msg := c.Request.Method + " " + path
 		log := logger.ZapLogger
+		// 经 logger.AddScope 追加的请求作用域字段
+		if scope := logger.Scope(c); len(scope) > 0 {
+			log = log.Sugar().With(scope...).Desugar()
+		}
 
 		switch {
 		case status >= 500:
@@ -125,6 +132,11 @@ type responseWriter struct {
 	body *bytes.Buffer
 }
 
+// Unwrap 返回被包装的写入器
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (w *responseWriter) Write(b []byte) (int, error) {
 	w.body.Write(b)
 	return w.ResponseWriter.Write(b)
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MethodOverrideField 表单中伪造请求方法的字段名
+const MethodOverrideField = "_method"
+
+// MethodOverride 请求方法伪造：HTML 表单只能提交 GET、POST，POST 请求携带 _method 字段
+// （或 X-HTTP-Method-Override 头）为 PUT、PATCH、DELETE 时按该方法路由。
+// gin 在匹配路由后才执行中间件，因此以 http.Handler 包装整个引擎，由 bootstrap 在 server.method_override 开启时使用：
+//
+//	srv := &http.Server{Handler: middleware.MethodOverride(router)}
+func MethodOverride(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			method := r.Header.Get("X-HTTP-Method-Override")
+			if method == "" && isFormRequest(r) {
+				method = r.PostFormValue(MethodOverrideField)
+			}
+			switch method = strings.ToUpper(method); method {
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				r.Method = method
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isFormRequest 是否为表单提交，仅此时读取请求体（解析结果保留在 r.PostForm 中，后续绑定不受影响）
+func isFormRequest(r *http.Request) bool {
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return ct == "application/x-www-form-urlencoded" || ct == "multipart/form-data"
+}
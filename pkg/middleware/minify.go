@@ -0,0 +1,364 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// minifyConfig HTML 压缩配置
+type minifyConfig struct {
+	excludes []string
+	skipper  func(*gin.Context) bool
+	inline   bool
+}
+
+// MinifyOption HTML 压缩配置选项
+type MinifyOption func(*minifyConfig)
+
+// WithMinifyExclude 排除指定路径前缀，如 "/admin/debug"
+func WithMinifyExclude(prefixes ...string) MinifyOption {
+	return func(c *minifyConfig) { c.excludes = append(c.excludes, prefixes...) }
+}
+
+// WithMinifySkipper 自定义跳过规则，返回 true 时不压缩
+func WithMinifySkipper(fn func(*gin.Context) bool) MinifyOption {
+	return func(c *minifyConfig) { c.skipper = fn }
+}
+
+// WithMinifyInline 是否压缩内联 <script>/<style>（去除行首缩进与空行，默认开启）。
+// 内联脚本包含多行模板字符串且对缩进敏感时应关闭
+func WithMinifyInline(enabled bool) MinifyOption {
+	return func(c *minifyConfig) { c.inline = enabled }
+}
+
+// MinifyHTML HTML 输出压缩中间件：折叠空白、移除注释（保留 <!--[if ...]> 条件注释与 <!--! ...--> 注释），
+// <pre>/<textarea> 内容原样输出，内联 <script>/<style> 仅去除缩进与空行。
+//
+// 采用逐字节状态机流式处理，不缓冲整页，大页面与分块输出不会增加内存占用；
+// 仅处理 Content-Type 为 text/html 的响应，需注册在压缩中间件之后（即先压缩 HTML 再 gzip）。
+func MinifyHTML(opts ...MinifyOption) gin.HandlerFunc {
+	cfg := &minifyConfig{inline: true}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range cfg.excludes {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		if cfg.skipper != nil && cfg.skipper(c) {
+			c.Next()
+			return
+		}
+
+		w := &minifyWriter{ResponseWriter: c.Writer, inline: cfg.inline}
+		c.Writer = w
+		defer w.close()
+		c.Next()
+	}
+}
+
+// minifyWriter 在首次写入时根据 Content-Type 决定是否压缩
+type minifyWriter struct {
+	gin.ResponseWriter
+	inline  bool
+	decided bool
+	m       *htmlMinifier
+}
+
+// Unwrap 返回被包装的写入器
+func (w *minifyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *minifyWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		// 压缩后长度变化，交由底层按分块或实际长度输出
+		w.Header().Del("Content-Length")
+		w.m = newHTMLMinifier(w.ResponseWriter, w.inline)
+	}
+}
+
+func (w *minifyWriter) Write(b []byte) (int, error) {
+	w.decide()
+	if w.m == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.m.Write(b)
+}
+
+func (w *minifyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *minifyWriter) close() {
+	if w.m != nil {
+		_ = w.m.Close()
+	}
+}
+
+// ---- 流式 HTML 压缩状态机 ----
+
+const (
+	minText    = iota // 文本
+	minTagOpen        // 读到 '<'，判断是标签还是注释
+	minTag            // 标签内部
+	minComment        // 注释内部
+	minRaw            // <pre>/<textarea>/<script>/<style> 内容
+)
+
+// rawTags 内容需原样（或仅轻度）处理的标签
+var rawTags = map[string]bool{"pre": true, "textarea": true, "script": true, "style": true}
+
+// htmlMinifier 逐字节处理 HTML，跨 Write 调用保持状态
+type htmlMinifier struct {
+	w      io.Writer
+	inline bool
+	out    []byte
+
+	state int
+	space bool   // 待输出的空白
+	head  []byte // minTagOpen 阶段的前瞻字节
+
+	// 标签
+	quote      byte
+	name       []byte
+	collecting bool
+
+	// 注释
+	keepComment bool
+	dashes      int
+
+	// 原样内容
+	rawName   string
+	rawEnd    string
+	match     []byte
+	lineStart bool
+}
+
+func newHTMLMinifier(w io.Writer, inline bool) *htmlMinifier {
+	return &htmlMinifier{w: w, inline: inline}
+}
+
+// Write 处理一段输入并立即写出可确定的部分
+func (m *htmlMinifier) Write(p []byte) (int, error) {
+	m.out = m.out[:0]
+	for _, b := range p {
+		m.step(b)
+	}
+	if len(m.out) > 0 {
+		if _, err := m.w.Write(m.out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close 输出尚在前瞻缓冲中的字节
+func (m *htmlMinifier) Close() error {
+	m.out = append(m.out[:0], m.head...)
+	m.out = append(m.out, m.match...)
+	m.head, m.match = nil, nil
+	if len(m.out) == 0 {
+		return nil
+	}
+	_, err := m.w.Write(m.out)
+	return err
+}
+
+func (m *htmlMinifier) emit(b byte) {
+	m.out = append(m.out, b)
+}
+
+func (m *htmlMinifier) step(b byte) {
+	switch m.state {
+	case minText:
+		if isHTMLSpace(b) {
+			m.space = true
+			return
+		}
+		if m.space {
+			m.emit(' ')
+			m.space = false
+		}
+		if b == '<' {
+			m.state = minTagOpen
+			m.head = append(m.head[:0], b)
+			return
+		}
+		m.emit(b)
+
+	case minTagOpen:
+		if len(m.head) == 1 && !isTagNameByte(b) && b != '/' && b != '!' && b != '?' {
+			// "a < b" 之类的文本中的 '<'，按文本处理
+			m.head = m.head[:0]
+			m.emit('<')
+			m.state = minText
+			m.step(b)
+			return
+		}
+		m.head = append(m.head, b)
+		const open = "<!--"
+		if len(m.head) <= len(open) && string(m.head) == open[:len(m.head)] {
+			return
+		}
+		if len(m.head) == len(open)+1 && string(m.head[:len(open)]) == open {
+			// 条件注释与 <!--! 注释保留
+			m.keepComment = b == '[' || b == '!'
+			if m.keepComment {
+				m.out = append(m.out, m.head...)
+			}
+			m.dashes = 0
+			if b == '-' {
+				m.dashes = 1
+			}
+			m.head = m.head[:0]
+			m.state = minComment
+			return
+		}
+		// 普通标签：回放前瞻字节
+		head := append([]byte(nil), m.head...)
+		m.head = m.head[:0]
+		m.state = minTag
+		m.quote = 0
+		m.name = m.name[:0]
+		m.collecting = true
+		m.emit('<')
+		for _, hb := range head[1:] {
+			m.tagStep(hb)
+		}
+
+	case minTag:
+		m.tagStep(b)
+
+	case minComment:
+		if m.keepComment {
+			m.emit(b)
+		}
+		switch {
+		case b == '-':
+			m.dashes++
+		case b == '>' && m.dashes >= 2:
+			m.state = minText
+			m.dashes = 0
+		default:
+			m.dashes = 0
+		}
+
+	case minRaw:
+		if toLowerASCII(b) == m.rawEnd[len(m.match)] {
+			m.match = append(m.match, b)
+			if len(m.match) == len(m.rawEnd) {
+				m.out = append(m.out, m.match...)
+				m.match = m.match[:0]
+				m.state = minTag
+				m.quote = 0
+				m.collecting = false
+			}
+			return
+		}
+		if len(m.match) > 0 {
+			pending := append([]byte(nil), m.match...)
+			m.match = m.match[:0]
+			for _, pb := range pending {
+				m.rawEmit(pb)
+			}
+			// 当前字节可能是新一轮匹配的开头
+			m.step(b)
+			return
+		}
+		m.rawEmit(b)
+	}
+}
+
+// tagStep 处理标签内部字节：折叠属性间空白，引号内原样输出
+func (m *htmlMinifier) tagStep(b byte) {
+	if m.quote != 0 {
+		m.emit(b)
+		if b == m.quote {
+			m.quote = 0
+		}
+		return
+	}
+	if m.collecting {
+		if isTagNameByte(b) || (b == '/' && len(m.name) == 0) {
+			m.name = append(m.name, toLowerASCII(b))
+		} else {
+			m.collecting = false
+		}
+	}
+	if isHTMLSpace(b) {
+		m.space = true
+		return
+	}
+	if b == '>' {
+		m.space = false
+		m.emit(b)
+		m.state = minText
+		if name := string(m.name); rawTags[name] {
+			m.state = minRaw
+			m.rawName = name
+			m.rawEnd = "</" + name
+			m.match = m.match[:0]
+			m.lineStart = true
+		}
+		m.name = m.name[:0]
+		return
+	}
+	if m.space {
+		m.emit(' ')
+		m.space = false
+	}
+	if b == '"' || b == '\'' {
+		m.quote = b
+	}
+	m.emit(b)
+}
+
+// rawEmit 输出原样内容；开启内联压缩时去除 script/style 的行首缩进与空行
+func (m *htmlMinifier) rawEmit(b byte) {
+	if !m.inline || (m.rawName != "script" && m.rawName != "style") {
+		m.emit(b)
+		return
+	}
+	switch {
+	case b == '\r':
+		return
+	case b == '\n':
+		if !m.lineStart {
+			m.emit(b)
+		}
+		m.lineStart = true
+	case m.lineStart && (b == ' ' || b == '\t'):
+		return
+	default:
+		m.lineStart = false
+		m.emit(b)
+	}
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\t' || b == '\r' || b == '\f'
+}
+
+func isTagNameByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b == '-'
+}
+
+func toLowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+	return b
+}
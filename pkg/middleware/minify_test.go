@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func minifyString(t *testing.T, in string, chunk int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	m := newHTMLMinifier(&buf, true)
+	for i := 0; i < len(in); i += chunk {
+		end := min(i+chunk, len(in))
+		if _, err := m.Write([]byte(in[i:end])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.Close()
+	return buf.String()
+}
+
+func TestHTMLMinifier(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"collapse whitespace", "<div>\n    <p>  hello   world </p>\n</div>\n", "<div> <p> hello world </p> </div>"},
+		{"tag attributes", `<a   href="/x"   title="a  b" >go</a>`, `<a href="/x" title="a  b">go</a>`},
+		{"strip comment", "<p>a<!-- secret --> b</p>", "<p>a b</p>"},
+		{"keep conditional comment", "<!--[if IE]><p>ie</p><![endif]-->", "<!--[if IE]><p>ie</p><![endif]-->"},
+		{"pre verbatim", "<pre>\n  a\n    b\n</pre>", "<pre>\n  a\n    b\n</pre>"},
+		{"inline script", "<script>\n    var a = 1;\n\n    var b = '<p>  x';\n</script>", "<script>var a = 1;\nvar b = '<p>  x';\n</script>"},
+		{"uppercase close tag", "<style>\n  a { }\n</STYLE>  <b>x</b>", "<style>a { }\n</STYLE> <b>x</b>"},
+		{"less-than in text", "<p>1 < 2</p>", "<p>1 < 2</p>"},
+		{"doctype", "<!DOCTYPE html>\n<html>", "<!DOCTYPE html> <html>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 整体写入与逐字节写入结果应一致，验证跨块状态保持
+			for _, chunk := range []int{len(tt.in), 1, 3} {
+				if got := minifyString(t, tt.in, chunk); got != tt.want {
+					t.Errorf("chunk=%d\n got %q\nwant %q", chunk, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMinifyHTMLMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(MinifyHTML(WithMinifyExclude("/raw")))
+	r.GET("/page", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<p>\n  hi\n</p>"))
+	})
+	r.GET("/raw/page", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<p>\n  hi\n</p>"))
+	})
+	r.GET("/json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte("{\n  \"a\": 1\n}"))
+	})
+
+	for path, want := range map[string]string{
+		"/page":     "<p> hi </p>",
+		"/raw/page": "<p>\n  hi\n</p>",
+		"/json":     "{\n  \"a\": 1\n}",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Body.String() != want {
+			t.Errorf("%s: body = %q, want %q", path, w.Body.String(), want)
+		}
+	}
+}
+
+func BenchmarkHTMLMinifier(b *testing.B) {
+	page := []byte(strings.Repeat("<div class=\"row\">\n    <span>  item  </span>\n    <!-- note -->\n</div>\n", 1000))
+	m := newHTMLMinifier(nopWriter{}, true)
+	b.SetBytes(int64(len(page)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Write(page)
+	}
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
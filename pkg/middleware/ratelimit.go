@@ -2,10 +2,14 @@ package middleware
 
 import (
 	stderrors "errors"
+	"expvar"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/clock"
 	"github.com/gorilla-go/go-framework/pkg/errors"
 	"github.com/gorilla-go/go-framework/pkg/response"
 )
@@ -18,13 +22,19 @@ type RateLimiter struct {
 	tokens     int           // 当前令牌数
 	lastToken  time.Time     // 上次生成令牌时间
 	lastAccess time.Time     // 上次访问时间（用于清理）
+	clock      clock.Clock
 	mu         sync.Mutex
 }
 
-// NewRateLimiter 创建限流器
+// NewRateLimiter 创建限流器，使用全局时钟
 func NewRateLimiter(rate int, capacity int) *RateLimiter {
-	now := time.Now()
+	return newRateLimiter(rate, capacity, clock.Default())
+}
+
+func newRateLimiter(rate int, capacity int, clk clock.Clock) *RateLimiter {
+	now := clk.Now()
 	return &RateLimiter{
+		clock:      clk,
 		rate:       rate,
 		interval:   time.Second,
 		capacity:   capacity,
@@ -34,36 +44,61 @@ func NewRateLimiter(rate int, capacity int) *RateLimiter {
 	}
 }
 
+// RateLimitResult 一次限流判断的结果
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int           // 令牌桶容量
+	Remaining  int           // 本次判断后剩余的令牌数
+	RetryAfter time.Duration // 距下一个令牌生成的时间，仍有令牌时为 0
+	Reset      time.Duration // 距令牌桶回满的时间
+}
+
 // Allow 是否允许请求
 func (r *RateLimiter) Allow() bool {
+	return r.Take().Allowed
+}
+
+// Take 尝试取走一个令牌，返回判断结果与令牌桶状态
+func (r *RateLimiter) Take() RateLimitResult {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	now := time.Now()
+	now := r.clock.Now()
 	r.lastAccess = now
 
+	perToken := r.interval / time.Duration(r.rate)
 	elapsed := now.Sub(r.lastToken)
 	newTokens := int(elapsed.Nanoseconds() * int64(r.rate) / r.interval.Nanoseconds())
 	if newTokens > 0 {
 		r.tokens += newTokens
-		r.lastToken = r.lastToken.Add(time.Duration(newTokens) * r.interval / time.Duration(r.rate))
+		r.lastToken = r.lastToken.Add(time.Duration(newTokens) * perToken)
 		if r.tokens > r.capacity {
 			r.tokens = r.capacity
 		}
 	}
 
+	res := RateLimitResult{Limit: r.capacity}
 	if r.tokens > 0 {
 		r.tokens--
-		return true
+		res.Allowed = true
+	}
+	res.Remaining = r.tokens
+	// 下一个令牌在 lastToken + perToken 生成；桶满时 lastToken 可能早于 now，不需要等待
+	sinceLast := now.Sub(r.lastToken)
+	if r.tokens == 0 {
+		res.RetryAfter = max(perToken-sinceLast, 0)
 	}
-	return false
+	if missing := r.capacity - r.tokens; missing > 0 {
+		res.Reset = max(time.Duration(missing)*perToken-sinceLast, 0)
+	}
+	return res
 }
 
 // IsExpired 检查限流器是否超过 ttl 未使用
 func (r *RateLimiter) IsExpired(ttl time.Duration) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return time.Since(r.lastAccess) > ttl
+	return r.clock.Since(r.lastAccess) > ttl
 }
 
 // ---- Functional Options（参考 Hertz 设计）----
@@ -73,6 +108,9 @@ type rateLimitConfig struct {
 	rate    int
 	burst   int
 	skipper func(*gin.Context) bool // 返回 true 时跳过限流
+	clock   clock.Clock
+	name    string // 统计名称，非空时发布到 expvar
+	headers bool   // 是否输出 X-RateLimit-* 响应头
 }
 
 // RateLimitOption 限流配置选项
@@ -94,45 +132,120 @@ func WithSkipper(fn func(*gin.Context) bool) RateLimitOption {
 	return func(c *rateLimitConfig) { c.skipper = fn }
 }
 
+// WithRateLimitClock 设置令牌生成使用的时钟（默认全局时钟），测试中可传入 clock.Fake 控制令牌恢复
+func WithRateLimitClock(clk clock.Clock) RateLimitOption {
+	return func(c *rateLimitConfig) { c.clock = clk }
+}
+
+// WithRateLimitName 设置限流器名称，并将统计（放行数、限流数、IP 限流器数）发布到 expvar（/debug/vars 中 ratelimit 下的 <name>），
+// 同名的限流器重复创建时以最后创建的为准
+func WithRateLimitName(name string) RateLimitOption {
+	return func(c *rateLimitConfig) { c.name = name }
+}
+
+// WithRateLimitHeaders 设置是否输出 X-RateLimit-Limit / Remaining / Reset 响应头（默认输出）；
+// 被限流时总是输出 Retry-After
+func WithRateLimitHeaders(enabled bool) RateLimitOption {
+	return func(c *rateLimitConfig) { c.headers = enabled }
+}
+
 func newRateLimitConfig(opts []RateLimitOption) *rateLimitConfig {
-	cfg := &rateLimitConfig{rate: 100}
+	cfg := &rateLimitConfig{rate: 100, headers: true}
 	for _, o := range opts {
 		o(cfg)
 	}
+	if cfg.clock == nil {
+		cfg.clock = clock.Default()
+	}
 	if cfg.burst <= 0 {
 		cfg.burst = cfg.rate
 	}
 	return cfg
 }
 
-// RateLimitMiddleware 全局限流中间件
+// RateLimitStats 限流统计
+type RateLimitStats struct {
+	Allowed  int64 `json:"allowed"`  // 放行的请求数
+	Limited  int64 `json:"limited"`  // 被限流的请求数
+	Limiters int64 `json:"limiters"` // 当前的限流器数（IP 限流为活跃 IP 数，全局限流为 1）
+}
+
+// rateLimitStats 限流中间件的运行统计
+type rateLimitStats struct {
+	allowed  atomic.Int64
+	limited  atomic.Int64
+	limiters atomic.Int64
+}
+
+// snapshot 返回统计快照
+func (s *rateLimitStats) snapshot() RateLimitStats {
+	return RateLimitStats{Allowed: s.allowed.Load(), Limited: s.limited.Load(), Limiters: s.limiters.Load()}
+}
+
+// rateLimitVars /debug/vars 中的 ratelimit，按名称保存各限流器的统计
+var rateLimitVars = expvar.NewMap("ratelimit")
+
+// newRateLimitStats 创建统计，配置了名称时发布到 expvar，替换同名的旧统计
+func newRateLimitStats(cfg *rateLimitConfig) *rateLimitStats {
+	stats := &rateLimitStats{}
+	if cfg.name != "" {
+		rateLimitVars.Set(cfg.name, expvar.Func(func() any { return stats.snapshot() }))
+	}
+	return stats
+}
+
+// rateLimit 执行限流判断：输出响应头，被限流时返回 429（带 Retry-After）并中止
+func rateLimit(c *gin.Context, cfg *rateLimitConfig, stats *rateLimitStats, limiter *RateLimiter, cause string) {
+	res := limiter.Take()
+	if cfg.headers {
+		h := c.Writer.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	}
+	if !res.Allowed {
+		stats.limited.Add(1)
+		c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+		response.Fail(c, errors.New(errors.TooManyRequests, "请求过于频繁，请稍后再试", stderrors.New(cause)))
+		return
+	}
+	stats.allowed.Add(1)
+	c.Next()
+}
+
+// ceilSeconds 向上取整的秒数
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// RateLimitMiddleware 全局限流中间件，响应携带 X-RateLimit-Limit / Remaining / Reset（秒）头，
+// 被限流时返回 429 与 Retry-After（秒）
 //
 // 用法（向后兼容旧签名，仍可通过 options 扩展）：
 //
-//	middleware.RateLimitMiddleware(middleware.WithRate(100), middleware.WithBurst(200))
+//	middleware.RateLimitMiddleware(middleware.WithRate(100), middleware.WithBurst(200), middleware.WithRateLimitName("global"))
 //	middleware.RateLimitMiddleware(middleware.WithRate(50), middleware.WithSkipper(func(c *gin.Context) bool {
 //	    return c.Request.URL.Path == "/health"
 //	}))
 func RateLimitMiddleware(opts ...RateLimitOption) gin.HandlerFunc {
 	cfg := newRateLimitConfig(opts)
-	limiter := NewRateLimiter(cfg.rate, cfg.burst)
+	limiter := newRateLimiter(cfg.rate, cfg.burst, cfg.clock)
+	stats := newRateLimitStats(cfg)
+	stats.limiters.Store(1)
 
 	return func(c *gin.Context) {
 		if cfg.skipper != nil && cfg.skipper(c) {
 			c.Next()
 			return
 		}
-		if !limiter.Allow() {
-			response.Fail(c, errors.New(errors.TooManyRequests, "请求过于频繁，请稍后再试", stderrors.New("请求限流")))
-			return
-		}
-		c.Next()
+		rateLimit(c, cfg, stats, limiter, "请求限流")
 	}
 }
 
-// IPRateLimitMiddleware 基于客户端 IP 的限流中间件
+// IPRateLimitMiddleware 基于客户端 IP 的限流中间件，响应头与 RateLimitMiddleware 相同（按当前 IP 的令牌桶计算）
 func IPRateLimitMiddleware(opts ...RateLimitOption) gin.HandlerFunc {
 	cfg := newRateLimitConfig(opts)
+	stats := newRateLimitStats(cfg)
 	limiters := &sync.Map{}
 	cleanupInterval := 10 * time.Minute
 	ttl := 1 * time.Hour
@@ -144,6 +257,7 @@ func IPRateLimitMiddleware(opts ...RateLimitOption) gin.HandlerFunc {
 			limiters.Range(func(key, value any) bool {
 				if value.(*RateLimiter).IsExpired(ttl) {
 					limiters.Delete(key)
+					stats.limiters.Add(-1)
 				}
 				return true
 			})
@@ -156,11 +270,10 @@ func IPRateLimitMiddleware(opts ...RateLimitOption) gin.HandlerFunc {
 			return
 		}
 		ip := c.ClientIP()
-		value, _ := limiters.LoadOrStore(ip, NewRateLimiter(cfg.rate, cfg.burst))
-		if !value.(*RateLimiter).Allow() {
-			response.Fail(c, errors.New(errors.TooManyRequests, "请求过于频繁，请稍后再试", stderrors.New("IP请求限流")))
-			return
+		value, loaded := limiters.LoadOrStore(ip, newRateLimiter(cfg.rate, cfg.burst, cfg.clock))
+		if !loaded {
+			stats.limiters.Add(1)
 		}
-		c.Next()
+		rateLimit(c, cfg, stats, value.(*RateLimiter), "IP请求限流")
 	}
 }
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// TestRateLimitWithFakeClock 令牌耗尽后拨动时钟恢复
+func TestRateLimitWithFakeClock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	r := gin.New()
+	r.Use(RateLimitMiddleware(WithRate(2), WithRateLimitClock(fake)))
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	status := func() int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Code
+	}
+	for i := 0; i < 2; i++ {
+		if code := status(); code != http.StatusOK {
+			t.Fatalf("第 %d 个请求状态码 %d", i+1, code)
+		}
+	}
+	if code := status(); code != http.StatusTooManyRequests {
+		t.Fatalf("令牌耗尽后应限流，得到 %d", code)
+	}
+	fake.Advance(500 * time.Millisecond)
+	if code := status(); code != http.StatusOK {
+		t.Errorf("半秒后应恢复一个令牌，得到 %d", code)
+	}
+}
+
+// TestRateLimitHeaders 限流响应头、Retry-After 与 expvar 统计
+func TestRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	r := gin.New()
+	r.Use(IPRateLimitMiddleware(WithRate(1), WithBurst(2), WithRateLimitClock(fake), WithRateLimitName("test-ip")))
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w
+	}
+	cases := []struct {
+		code                         int
+		remaining, reset, retryAfter string
+	}{
+		{http.StatusOK, "1", "1", ""},
+		{http.StatusOK, "0", "2", ""},
+		{http.StatusTooManyRequests, "0", "2", "1"},
+	}
+	for i, tc := range cases {
+		w := do()
+		h := w.Header()
+		if w.Code != tc.code || h.Get("X-RateLimit-Limit") != "2" || h.Get("X-RateLimit-Remaining") != tc.remaining ||
+			h.Get("X-RateLimit-Reset") != tc.reset || h.Get("Retry-After") != tc.retryAfter {
+			t.Errorf("第 %d 个请求: %d %v", i+1, w.Code, h)
+		}
+	}
+
+	fake.Advance(1500 * time.Millisecond)
+	if w := do(); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "0" || w.Header().Get("X-RateLimit-Reset") != "2" {
+		t.Errorf("1.5 秒后: %d %v", w.Code, w.Header())
+	}
+
+	v := rateLimitVars.Get("test-ip")
+	if v == nil {
+		t.Fatal("统计应发布到 expvar")
+	}
+	if got := v.(expvar.Func)().(RateLimitStats); got != (RateLimitStats{Allowed: 3, Limited: 1, Limiters: 1}) {
+		t.Errorf("stats = %+v", got)
+	}
+
+	// 同名的新限流器替换旧统计
+	IPRateLimitMiddleware(WithRateLimitName("test-ip"))
+	if got := rateLimitVars.Get("test-ip").(expvar.Func)().(RateLimitStats); got != (RateLimitStats{}) {
+		t.Errorf("新限流器 stats = %+v", got)
+	}
+}
+
+// TestTokenExpiryWithFakeClock 令牌签发与过期校验使用全局时钟
+func TestTokenExpiryWithFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	clock.SetDefault(fake)
+	defer clock.SetDefault(nil)
+
+	cfg := &config.JWTConfig{Secret: "secret", Expire: 24}
+	token, err := GenerateToken(1, "alice", "admin", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ParseToken(token, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.IssuedAt.Time.Equal(fake.Now()) {
+		t.Errorf("IssuedAt = %v", claims.IssuedAt.Time)
+	}
+
+	fake.Advance(25 * time.Hour)
+	if _, err := ParseToken(token, cfg); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("期望令牌过期，得到 %v", err)
+	}
+}
@@ -1,8 +1,14 @@
 package middleware
 
 import (
+	stderrors "errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
 	"runtime/debug"
+	"strings"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla-go/go-framework/pkg/config"
@@ -15,6 +21,20 @@ func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if r := recover(); r != nil {
+				// http.ErrAbortHandler 是有意要求 net/http 断开连接的信号（如 httputil.ReverseProxy 复制上游响应失败），
+				// 继续向上 panic，避免被截断的响应当作完整响应结束
+				if err, ok := r.(error); ok && stderrors.Is(err, http.ErrAbortHandler) {
+					c.Abort()
+					panic(r)
+				}
+
+				// 客户端已断开（写入已关闭的连接）：不是服务端故障，无法也无需再输出错误页
+				if brokenConnection(r) {
+					logger.Infof("客户端连接已断开: %s %s: %v", c.Request.Method, c.Request.URL.Path, r)
+					c.Abort()
+					return
+				}
+
 				// 打印堆栈信息
 				stack := debug.Stack()
 				cfg := config.MustFetch()
@@ -22,11 +42,12 @@ func Recovery() gin.HandlerFunc {
 				// 始终记录 panic 与堆栈：debug 模式虽会渲染到页面，但日志同样需要留痕
 				logger.Errorf("panic recovered: %v\n%s", r, string(stack))
 
-				errors.RenderError(
+				errors.RenderErrorWithContext(
 					c.Writer,
 					fmt.Errorf("%v", r),
 					string(stack),
-					cfg.IsDebug(),
+					cfg.IsDevelopment(),
+					ErrorContext(c),
 				)
 				c.Abort()
 				return
@@ -36,3 +57,24 @@ func Recovery() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// brokenConnection 判断 panic 是否由客户端断开连接引起（broken pipe、connection reset）
+func brokenConnection(r any) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	if stderrors.Is(err, syscall.EPIPE) || stderrors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	if !stderrors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if stderrors.As(opErr, &sysErr) {
+		msg := strings.ToLower(sysErr.Error())
+		return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestRecoveryBrokenPipe 客户端断开引起的 panic 以 info 级别记录并静默中止，不输出错误页
+func TestRecoveryBrokenPipe(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	prev := logger.SugarLogger
+	logger.SugarLogger = zap.New(core).Sugar()
+	defer func() { logger.SugarLogger = prev }()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery())
+	r.GET("/", func(c *gin.Context) {
+		panic(&net.OpError{Op: "write", Net: "tcp", Err: &os.SyscallError{Syscall: "write", Err: syscall.EPIPE}})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Body.Len() != 0 {
+		t.Errorf("不应输出错误页: %s", w.Body.String())
+	}
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Level != zap.InfoLevel {
+		t.Errorf("应记录一条 info 日志: %v", entries)
+	}
+}
+
+// TestRecoveryAbortHandler http.ErrAbortHandler 继续向上 panic，由 net/http 断开连接
+func TestRecoveryAbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery())
+	r.GET("/", func(c *gin.Context) { panic(http.ErrAbortHandler) })
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("应重新 panic http.ErrAbortHandler，得到 %v", v)
+		}
+	}()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestBrokenConnection(t *testing.T) {
+	tests := []struct {
+		value any
+		want  bool
+	}{
+		{&net.OpError{Op: "write", Err: &os.SyscallError{Syscall: "write", Err: syscall.EPIPE}}, true},
+		{&net.OpError{Op: "write", Err: &os.SyscallError{Syscall: "write", Err: syscall.ECONNRESET}}, true},
+		{fmt.Errorf("写入失败: %w", syscall.EPIPE), true},
+		{http.ErrAbortHandler, false},
+		{&net.OpError{Op: "dial", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}, false},
+		{fmt.Errorf("nil pointer"), false},
+		{"broken pipe", false},
+	}
+	for _, tt := range tests {
+		if got := brokenConnection(tt.value); got != tt.want {
+			t.Errorf("brokenConnection(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
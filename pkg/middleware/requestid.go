@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// maxRequestIDLength 接受的上游请求 ID 最大长度
+const maxRequestIDLength = 128
+
+// RequestID 请求 ID 中间件：沿用上游传入的 X-Request-ID（格式合法时），否则生成新的 ID。
+// ID 写入 gin.Context（request.RequestID 读取）、请求上下文（request.RequestIDFromContext 读取）与响应头，
+// 之后以 c.Request.Context() 发起的出站请求、SQL 与事件都会带上同一个 ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(request.HeaderRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(request.KeyRequestID, id)
+		c.Request = c.Request.WithContext(request.WithRequestID(c.Request.Context(), id))
+		c.Header(request.HeaderRequestID, id)
+		c.Next()
+	}
+}
+
+// newRequestID 生成 32 位十六进制随机 ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID 仅接受字母、数字与 -_.: 组成的 ID，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// TestRequestID 沿用合法的上游 ID，否则生成新 ID；ID 同时写入 gin.Context 与请求上下文
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	var fromGin, fromCtx string
+	r.GET("/", func(c *gin.Context) {
+		fromGin = request.RequestID(c)
+		fromCtx = request.RequestIDFromContext(c.Request.Context())
+	})
+
+	serve := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(request.HeaderRequestID, header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("upstream-1")
+	if fromGin != "upstream-1" || fromCtx != "upstream-1" || w.Header().Get(request.HeaderRequestID) != "upstream-1" {
+		t.Errorf("应沿用上游 ID，得到 gin=%q ctx=%q header=%q", fromGin, fromCtx, w.Header().Get(request.HeaderRequestID))
+	}
+
+	w = serve("bad id\nforged")
+	id := w.Header().Get(request.HeaderRequestID)
+	if len(id) != 32 || fromGin != id || fromCtx != id {
+		t.Errorf("非法上游 ID 应被替换，得到 header=%q gin=%q ctx=%q", id, fromGin, fromCtx)
+	}
+}
@@ -10,3 +10,8 @@ import (
 func SessionStart(sessionConfig *config.SessionConfig, redisConfig *config.RedisConfig, dbConfig *config.DatabaseConfig) gin.HandlerFunc {
 	return session.Start(sessionConfig, redisConfig, dbConfig)
 }
+
+// SessionTrack 会话撤销检查中间件，须位于 SessionStart 之后，见 session.Track
+func SessionTrack() gin.HandlerFunc {
+	return session.Track()
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/tenant"
+)
+
+// tenantConfig 租户解析配置
+type tenantConfig struct {
+	required bool
+}
+
+// TenantOption 租户解析配置选项
+type TenantOption func(*tenantConfig)
+
+// WithTenantRequired 无法解析租户时是否返回 404（默认 true）；
+// 关闭后未识别租户的请求照常处理，适用于官网首页等公共页面
+func WithTenantRequired(required bool) TenantOption {
+	return func(c *tenantConfig) { c.required = required }
+}
+
+// Tenant 租户解析中间件：通过 resolver 得到租户 ID，从 store 查找租户后写入 gin.Context 与请求上下文，
+// 之后可通过 tenant.Current(c) / tenant.FromContext(ctx) 获取，模板中可使用 .Tenant。
+// 租户不存在时返回 404，查找出错时返回 500。
+//
+//	r.Use(middleware.Tenant(tenant.FromSubdomain("example.com"), tenant.NewStaticStore(tenants...)))
+func Tenant(resolver tenant.Resolver, store tenant.Store, opts ...TenantOption) gin.HandlerFunc {
+	cfg := &tenantConfig{required: true}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		id := resolver(c)
+		if id == "" {
+			if cfg.required {
+				abortWithMessage(c, http.StatusNotFound, "Tenant Not Found")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		t, err := store.Find(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, tenant.ErrNotFound) {
+				abortWithMessage(c, http.StatusNotFound, "Tenant Not Found")
+			} else {
+				_ = c.Error(err)
+				abortWithMessage(c, http.StatusInternalServerError, "Internal Server Error")
+			}
+			return
+		}
+
+		request.Set(c, request.KeyTenant, t)
+		c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), t))
+		c.Next()
+	}
+}
+
+// abortWithMessage 按 Accept 头返回 JSON 或纯状态码
+func abortWithMessage(c *gin.Context, status int, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEJSON {
+		c.AbortWithStatusJSON(status, gin.H{"code": status, "message": message})
+		return
+	}
+	c.AbortWithStatus(status)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/tenant"
+)
+
+func TestTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := tenant.NewStaticStore(&tenant.Tenant{ID: "acme", Name: "Acme"})
+	newRouter := func(opts ...TenantOption) *gin.Engine {
+		r := gin.New()
+		r.Use(Tenant(tenant.FromHeader("X-Tenant-ID"), store, opts...))
+		r.GET("/", func(c *gin.Context) {
+			name := ""
+			if t := tenant.Current(c); t != nil {
+				name = t.Name
+			}
+			// 请求上下文中同样可取到租户，供 service 层使用
+			c.String(http.StatusOK, name+"|"+tenant.ID(c.Request.Context()))
+		})
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		opts     []TenantOption
+		wantCode int
+		wantBody string
+	}{
+		{"resolved", "acme", nil, http.StatusOK, "Acme|acme"},
+		{"unknown", "nope", nil, http.StatusNotFound, ""},
+		{"missing required", "", nil, http.StatusNotFound, ""},
+		{"missing optional", "", []TenantOption{WithTenantRequired(false)}, http.StatusOK, "|"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			req.Header.Set("X-Tenant-ID", tt.header)
+		}
+		newRouter(tt.opts...).ServeHTTP(w, req)
+		if w.Code != tt.wantCode || (tt.wantCode == http.StatusOK && w.Body.String() != tt.wantBody) {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, w.Code, w.Body.String(), tt.wantCode, tt.wantBody)
+		}
+	}
+}
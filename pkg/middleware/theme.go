@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// SessionKeyTheme 会话中保存用户所选主题的键名
+const SessionKeyTheme = "theme"
+
+// themeConfig 主题解析配置
+type themeConfig struct {
+	resolver func(*gin.Context) string
+	domains  map[string]string
+	allowed  map[string]bool
+}
+
+// ThemeOption 主题解析配置选项
+type ThemeOption func(*themeConfig)
+
+// WithThemeResolver 自定义主题解析函数（优先级最高），返回空字符串表示继续按会话/域名解析
+func WithThemeResolver(fn func(*gin.Context) string) ThemeOption {
+	return func(c *themeConfig) { c.resolver = fn }
+}
+
+// WithThemeDomains 按域名指定主题，如 {"a.example.com": "brand-a"}（多品牌部署）
+func WithThemeDomains(domains map[string]string) ThemeOption {
+	return func(c *themeConfig) { c.domains = domains }
+}
+
+// WithThemeAllowed 限定可用主题，会话中的非法值会被忽略
+func WithThemeAllowed(themes ...string) ThemeOption {
+	return func(c *themeConfig) {
+		c.allowed = make(map[string]bool, len(themes))
+		for _, t := range themes {
+			c.allowed[t] = true
+		}
+	}
+}
+
+// Theme 主题解析中间件，按 自定义解析 → 会话（SetTheme 写入）→ 域名 的顺序确定当前请求主题并写入上下文；
+// 均未命中时不设置，渲染时使用配置的默认主题（template.theme）。
+//
+//	r.Use(middleware.Theme(middleware.WithThemeDomains(map[string]string{"b.example.com": "brand-b"})))
+//	template.RenderThemeL(c, "index", data)
+func Theme(opts ...ThemeOption) gin.HandlerFunc {
+	cfg := &themeConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		for _, theme := range themeCandidates(c, cfg) {
+			if theme != "" && (cfg.allowed == nil || cfg.allowed[theme]) {
+				request.Set(c, request.KeyTheme, theme)
+				break
+			}
+		}
+		c.Next()
+	}
+}
+
+// themeCandidates 按优先级返回各来源给出的主题
+func themeCandidates(c *gin.Context, cfg *themeConfig) []string {
+	var candidates []string
+	if cfg.resolver != nil {
+		candidates = append(candidates, cfg.resolver(c))
+	}
+	if _, ok := c.Get(sessions.DefaultKey); ok {
+		theme, _ := sessions.Default(c).Get(SessionKeyTheme).(string)
+		candidates = append(candidates, theme)
+	}
+	return append(candidates, cfg.domains[hostname(c)])
+}
+
+// SetTheme 将用户选择的主题保存到会话并立即应用于当前请求（需启用会话中间件）
+func SetTheme(c *gin.Context, theme string) error {
+	session := sessions.Default(c)
+	if theme == "" {
+		session.Delete(SessionKeyTheme)
+	} else {
+		session.Set(SessionKeyTheme, theme)
+	}
+	request.Set(c, request.KeyTheme, theme)
+	return session.Save()
+}
+
+// hostname 返回不含端口的请求主机名
+func hostname(c *gin.Context) string {
+	host := c.Request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+func TestThemeResolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Theme(
+		WithThemeResolver(func(c *gin.Context) string { return c.Query("theme") }),
+		WithThemeDomains(map[string]string{"b.example.com": "brand-b"}),
+		WithThemeAllowed("brand-a", "brand-b"),
+	))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, request.Theme(c))
+	})
+
+	tests := []struct {
+		host, query, want string
+	}{
+		{"a.example.com", "", ""},
+		{"b.example.com:8080", "", "brand-b"},
+		{"b.example.com", "?theme=brand-a", "brand-a"},
+		{"b.example.com", "?theme=evil", "brand-b"}, // 非法主题被忽略，继续按域名解析
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+		req.Host = tt.host
+		r.ServeHTTP(w, req)
+		if w.Body.String() != tt.want {
+			t.Errorf("host=%s query=%s: theme = %q, want %q", tt.host, tt.query, w.Body.String(), tt.want)
+		}
+	}
+}
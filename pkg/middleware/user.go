@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// UserResolver 根据用户 ID 加载用户模型（通常查询仓储层），用户不存在时返回 nil, nil
+type UserResolver func(ctx context.Context, id uint) (any, error)
+
+// UserCache 跨请求的用户缓存，用于减少 LoadUser 对仓储层的访问
+type UserCache interface {
+	Get(id uint) (any, bool)
+	Set(id uint, user any)
+	Delete(id uint)
+}
+
+// loadUserConfig LoadUser 配置
+type loadUserConfig struct {
+	cache UserCache
+}
+
+// LoadUserOption LoadUser 配置选项
+type LoadUserOption func(*loadUserConfig)
+
+// WithUserCache 为 LoadUser 启用跨请求缓存（默认仅做单请求内记忆化）
+func WithUserCache(cache UserCache) LoadUserOption {
+	return func(c *loadUserConfig) { c.cache = cache }
+}
+
+// LoadUser 当前用户解析中间件。
+//
+// 依次从 JWT Claims（需位于 JWTMiddleware 之后）与会话（auth.SessionKeyUserID）中取得用户 ID，
+// 调用 resolver 加载用户模型并写入上下文；同一请求内只解析一次，之后通过 auth.User(c) 读取，
+// 模板中可经 template.WithContext 以 {{ .CurrentUser }} 访问。
+// 未登录或加载失败时按游客继续处理，是否拒绝访问由后续中间件/处理器决定。
+//
+//	r.Use(middleware.LoadUser(userRepo.FindUser, middleware.WithUserCache(middleware.NewUserCache(time.Minute))))
+func LoadUser(resolver UserResolver, opts ...LoadUserOption) gin.HandlerFunc {
+	cfg := &loadUserConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		if _, loaded := c.Get(request.KeyCurrentUser); loaded {
+			c.Next()
+			return
+		}
+
+		id, ok := ResolveUserID(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if cfg.cache != nil {
+			if user, hit := cfg.cache.Get(id); hit {
+				c.Set(request.KeyCurrentUser, user)
+				c.Next()
+				return
+			}
+		}
+
+		user, err := resolver(c.Request.Context(), id)
+		if err != nil {
+			logger.Warnf("加载当前用户失败 (id=%d): %v", id, err)
+		} else if user != nil {
+			c.Set(request.KeyCurrentUser, user)
+			if cfg.cache != nil {
+				cfg.cache.Set(id, user)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// ResolveUserID 从 JWT Claims（需位于 JWTMiddleware 之后）或会话中取得当前用户 ID，不加载用户模型
+func ResolveUserID(c *gin.Context) (uint, bool) {
+	if id, ok := GetUserIDFromContext(c); ok && id > 0 {
+		return id, true
+	}
+
+	// 未挂载会话中间件时 sessions.Default 会 panic，先确认会话存在
+	if _, ok := c.Get(sessions.DefaultKey); !ok {
+		return 0, false
+	}
+	switch v := sessions.Default(c).Get(auth.SessionKeyUserID).(type) {
+	case uint:
+		return v, v > 0
+	case int:
+		return uint(v), v > 0
+	case int64:
+		return uint(v), v > 0
+	case uint64:
+		return uint(v), v > 0
+	}
+	return 0, false
+}
+
+// ---- 内存用户缓存 ----
+
+type userCacheEntry struct {
+	user     any
+	expireAt time.Time
+}
+
+// MemoryUserCache 基于内存的 UserCache 实现，条目在 ttl 后过期，
+// 后台每隔 ttl（至少 1 分钟）清理一次过期条目，不再访问的用户不会一直占用内存
+type MemoryUserCache struct {
+	ttl     time.Duration
+	entries sync.Map
+}
+
+// NewUserCache 创建内存用户缓存
+func NewUserCache(ttl time.Duration) *MemoryUserCache {
+	m := &MemoryUserCache{ttl: ttl}
+	go func() {
+		ticker := time.NewTicker(max(ttl, time.Minute))
+		defer ticker.Stop()
+		for range ticker.C {
+			m.sweep()
+		}
+	}()
+	return m
+}
+
+// sweep 清理已过期的条目
+func (m *MemoryUserCache) sweep() {
+	now := clock.Now()
+	m.entries.Range(func(key, value any) bool {
+		if now.After(value.(*userCacheEntry).expireAt) {
+			m.entries.CompareAndDelete(key, value)
+		}
+		return true
+	})
+}
+
+// Get 读取未过期的缓存用户
+func (m *MemoryUserCache) Get(id uint) (any, bool) {
+	v, ok := m.entries.Load(id)
+	if !ok {
+		return nil, false
+	}
+	entry := v.(*userCacheEntry)
+	if clock.Now().After(entry.expireAt) {
+		m.entries.Delete(id)
+		return nil, false
+	}
+	return entry.user, true
+}
+
+// Set 写入缓存用户
+func (m *MemoryUserCache) Set(id uint, user any) {
+	m.entries.Store(id, &userCacheEntry{user: user, expireAt: clock.Now().Add(m.ttl)})
+}
+
+// Delete 移除缓存用户（用户资料变更后调用）
+func (m *MemoryUserCache) Delete(id uint) {
+	m.entries.Delete(id)
+}
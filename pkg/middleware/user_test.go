@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+)
+
+// TestLoadUserFromClaimsWithCache 从 JWT Claims 解析用户，命中缓存后不再调用 resolver
+func TestLoadUserFromClaimsWithCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	resolver := func(ctx context.Context, id uint) (any, error) {
+		calls++
+		return fmt.Sprintf("user-%d", id), nil
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(ContextKeyUserID, uint(7)); c.Next() })
+	r.Use(LoadUser(resolver, WithUserCache(NewUserCache(time.Minute))))
+	// 重复挂载时同一请求内不应再次解析
+	r.Use(LoadUser(resolver))
+	r.GET("/", func(c *gin.Context) {
+		name, _ := auth.UserAs[string](c)
+		c.String(http.StatusOK, name)
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Body.String() != "user-7" {
+			t.Fatalf("期望 user-7，得到 %q", w.Body.String())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("resolver 期望只调用 1 次，实际 %d 次", calls)
+	}
+}
+
+// TestUserCacheSweep 清理过期条目，未过期的保留
+func TestUserCacheSweep(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	clock.SetDefault(fake)
+	defer clock.SetDefault(nil)
+
+	m := NewUserCache(time.Minute)
+	m.Set(1, "a")
+	fake.Advance(30 * time.Second)
+	m.Set(2, "b")
+	fake.Advance(40 * time.Second)
+	m.sweep()
+
+	if _, ok := m.entries.Load(uint(1)); ok {
+		t.Error("过期条目应被清理")
+	}
+	if u, ok := m.Get(2); !ok || u != "b" {
+		t.Errorf("未过期条目应保留: %v, %v", u, ok)
+	}
+}
+
+// TestLoadUserGuest 未登录时按游客继续处理
+func TestLoadUserGuest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LoadUser(func(ctx context.Context, id uint) (any, error) {
+		t.Fatal("未登录时不应调用 resolver")
+		return nil, nil
+	}))
+	r.GET("/", func(c *gin.Context) {
+		if auth.Check(c) {
+			t.Error("未登录时 auth.Check 应为 false")
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("期望 200，得到 %d", w.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+// ContextKeyAPIVersion 是存储在 gin.Context 中的 API 版本键名
+const ContextKeyAPIVersion = "api_version"
+
+var (
+	// vendorVersionRegex 匹配厂商媒体类型中的版本，如 application/vnd.app.v2+json 或 application/vnd.app.v2
+	vendorVersionRegex = regexp.MustCompile(`^application/vnd\.[\w.\-]+?\.(v\d+(?:\.\d+)?)(?:\+[\w.\-]+)?$`)
+	// pathVersionRegex 匹配路由路径中的版本段，如 /v1、/v2.1
+	pathVersionRegex = regexp.MustCompile(`^[vV]\d+(?:\.\d+)?$`)
+)
+
+// APIVersion 解析 API 版本的中间件。
+//
+// 优先级：路由路径中的版本段（如 rb.Version("v1") 注册的 /v1/...）最高，
+// 路径已确定版本时不再按请求头协商，也不会因请求头版本不在 supported 内而拒绝；
+// 否则按 ResolveAPIVersion 从请求头解析，均未携带时使用 defaultVersion。
+//
+// 传入 supported 时仅允许列表内的版本，其余版本返回 406；
+// 解析结果写入 Context（见 GetAPIVersion）并通过 API-Version 响应头回显。
+//
+//	r.Use(middleware.APIVersion("v1", "v1", "v2"))
+func APIVersion(defaultVersion string, supported ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(supported))
+	for _, v := range supported {
+		allowed[NormalizeVersion(v)] = true
+	}
+	defaultVersion = NormalizeVersion(defaultVersion)
+
+	return func(c *gin.Context) {
+		version := PathVersion(c)
+		if version == "" {
+			version = ResolveAPIVersion(c)
+			if version == "" {
+				version = defaultVersion
+			}
+
+			if len(allowed) > 0 && !allowed[version] {
+				response.Fail(c, errors.New(errors.NotAcceptable,
+					fmt.Sprintf("不支持的 API 版本: %s", version), nil))
+				return
+			}
+		}
+
+		c.Set(ContextKeyAPIVersion, version)
+		c.Header("API-Version", version)
+		c.Next()
+	}
+}
+
+// PathVersion 从匹配到的路由模板（c.FullPath）中提取版本段，未包含版本段时返回空字符串
+func PathVersion(c *gin.Context) string {
+	for seg := range strings.SplitSeq(c.FullPath(), "/") {
+		if pathVersionRegex.MatchString(seg) {
+			return NormalizeVersion(seg)
+		}
+	}
+	return ""
+}
+
+// ResolveAPIVersion 从请求头中解析客户端请求的 API 版本，未携带时返回空字符串。
+//
+// 解析顺序：
+//  1. Accept 中携带版本的媒体类型，按 q 权重取最高者（同权重取靠前者）：
+//     厂商类型 application/vnd.app.v2[+json]，或参数形式 application/json; version=2
+//  2. 自定义头：Accept-Version: v2 / X-API-Version: v2
+func ResolveAPIVersion(c *gin.Context) string {
+	best, bestQ := "", -1.0
+	for mediaRange := range strings.SplitSeq(c.GetHeader("Accept"), ",") {
+		version, q := parseMediaRangeVersion(mediaRange)
+		if version != "" && q > 0 && q > bestQ {
+			best, bestQ = version, q
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	for _, header := range []string{"Accept-Version", "X-API-Version"} {
+		if v := c.GetHeader(header); v != "" {
+			return NormalizeVersion(v)
+		}
+	}
+
+	return ""
+}
+
+// parseMediaRangeVersion 解析单个媒体范围中的版本与 q 权重（缺省 q=1）
+func parseMediaRangeVersion(mediaRange string) (string, float64) {
+	parts := strings.Split(mediaRange, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(parts[0]))
+
+	version, q := "", 1.0
+	if m := vendorVersionRegex.FindStringSubmatch(mediaType); len(m) == 2 {
+		version = m[1]
+	}
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "version":
+			version = value
+		case "q":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				q = f
+			}
+		}
+	}
+
+	return NormalizeVersion(version), q
+}
+
+// GetAPIVersion 从 Gin 上下文中获取当前请求的 API 版本
+func GetAPIVersion(c *gin.Context) string {
+	return c.GetString(ContextKeyAPIVersion)
+}
+
+// NormalizeVersion 统一版本格式为小写 "v" 前缀形式，如 "2" / "V2" → "v2"
+func NormalizeVersion(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v == "" || strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newVersionEngine(h gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(h)
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, GetAPIVersion(c)) })
+	r.GET("/v1/ping", func(c *gin.Context) { c.String(http.StatusOK, GetAPIVersion(c)) })
+	return r
+}
+
+// TestAPIVersionResolve 覆盖各种请求头形式的版本解析
+func TestAPIVersionResolve(t *testing.T) {
+	r := newVersionEngine(APIVersion("v1"))
+
+	cases := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"默认版本", nil, "v1"},
+		{"厂商类型带后缀", map[string]string{"Accept": "application/vnd.app.v2+json"}, "v2"},
+		{"厂商类型无后缀", map[string]string{"Accept": "application/vnd.app.v3"}, "v3"},
+		{"version 参数", map[string]string{"Accept": "application/json; version=2"}, "v2"},
+		{"参数只属于所在媒体类型", map[string]string{"Accept": "text/html;level=1, application/json; version=2"}, "v2"},
+		{"按 q 权重选择", map[string]string{"Accept": "application/vnd.app.v2+json;q=0.5, application/vnd.app.v3+json;q=0.9"}, "v3"},
+		{"q=0 不参与协商", map[string]string{"Accept": "application/vnd.app.v4+json;q=0"}, "v1"},
+		{"Accept-Version 头", map[string]string{"Accept-Version": "V2"}, "v2"},
+		{"X-API-Version 头", map[string]string{"X-API-Version": "3"}, "v3"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Body.String() != tc.want {
+				t.Errorf("期望版本 %q，得到 %q", tc.want, w.Body.String())
+			}
+			if got := w.Header().Get("API-Version"); got != tc.want {
+				t.Errorf("API-Version 头期望 %q，得到 %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestAPIVersionUnsupported 请求不支持的版本应返回 406
+func TestAPIVersionUnsupported(t *testing.T) {
+	r := newVersionEngine(APIVersion("v1", "v1", "v2"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Version", "v9")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Errorf("不支持的版本期望 406，得到 %d", w.Code)
+	}
+}
+
+// TestAPIVersionPathWins 路径已包含版本段时以路径为准，不因请求头版本不受支持而拒绝
+func TestAPIVersionPathWins(t *testing.T) {
+	r := newVersionEngine(APIVersion("v2", "v2"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/ping", nil)
+	req.Header.Set("Accept-Version", "v3")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "v1" {
+		t.Errorf("路径版本应优先，期望 200 v1，得到 %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// ViewData 返回把 fn(c) 的结果以 key 共享给视图的中间件（见 request.Share），
+// 页面经 template.RenderThemeL / controller.Base.View 渲染时自动合并，处理器无需逐个复制到 gin.H；
+// 多个 ViewData 可按需挂在全局或路由组上，按注册顺序执行
+//
+//	r.Use(middleware.ViewData("Flash", func(c *gin.Context) any {
+//	    msg, _ := session.GetFlash(c, "message")
+//	    return msg
+//	}))
+//	admin.Use(middleware.ViewData("Sidebar", loadAdminSidebar))
+func ViewData(key string, fn func(c *gin.Context) any) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request.Share(c, key, fn(c))
+		c.Next()
+	}
+}
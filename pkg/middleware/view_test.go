@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+func TestViewData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ViewData("Site", func(c *gin.Context) any { return "demo" }))
+	admin := r.Group("/admin", ViewData("Section", func(c *gin.Context) any { return c.Query("s") }))
+	admin.GET("", func(c *gin.Context) {
+		data := request.Shared(c)
+		c.String(http.StatusOK, fmt.Sprintf("%v/%v", data["Site"], data["Section"]))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin?s=users", nil))
+	if w.Body.String() != "demo/users" {
+		t.Errorf("共享数据 = %q", w.Body.String())
+	}
+}
@@ -0,0 +1,226 @@
+// Package nav 在 Go 代码中定义层级菜单与面包屑，按当前请求计算可见项与高亮项，
+// 模板中通过 {{ menu "main" . }}、{{ breadcrumbs . }} 渲染（见 pkg/template）。
+//
+//	func init() {
+//	    nav.Define("main",
+//	        nav.Item{Label: "首页", Route: "home"},
+//	        nav.Item{Label: "文章", Route: "post@index", Children: []nav.Item{
+//	            {Label: "写文章", Route: "post@new", Visible: nav.Can("post.create")},
+//	        }},
+//	        nav.Item{Label: "后台", URL: "/admin", Visible: nav.Authenticated()},
+//	    )
+//	}
+//
+//	func (ctl *PostController) Show(c *gin.Context) error {
+//	    nav.Push(c, "文章", "/posts")
+//	    nav.Push(c, post.Title, "")
+//	    ...
+//	}
+package nav
+
+import (
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/router"
+)
+
+// Visibility 可见性断言，返回 false 时该项（含子项）不显示
+type Visibility func(c *gin.Context) bool
+
+// Item 菜单项定义
+type Item struct {
+	Label    string
+	Route    string         // 命名路由，优先于 URL
+	Params   map[string]any // 命名路由参数
+	URL      string         // 直接链接，Route 为空时使用
+	Icon     string
+	Visible  Visibility // 为空时始终可见
+	Children []Item
+}
+
+// Link 按当前请求解析后的菜单项与面包屑
+type Link struct {
+	Label    string
+	URL      string
+	Icon     string
+	Active   bool // 当前页面（面包屑的最后一项）或其祖先
+	Children []Link
+}
+
+var (
+	mu    sync.RWMutex
+	menus = make(map[string][]Item)
+)
+
+// Define 定义（或替换）命名菜单
+func Define(name string, items ...Item) {
+	mu.Lock()
+	defer mu.Unlock()
+	menus[name] = items
+}
+
+// items 返回命名菜单的定义
+func items(name string) []Item {
+	mu.RLock()
+	defer mu.RUnlock()
+	return menus[name]
+}
+
+// Authorizer 权限判断，由应用接入自己的权限系统
+type Authorizer func(c *gin.Context, permission string) bool
+
+var authorizer atomic.Value // Authorizer
+
+// SetAuthorizer 设置 Can 使用的权限判断
+func SetAuthorizer(fn Authorizer) {
+	authorizer.Store(fn)
+}
+
+// Authenticated 仅登录用户可见
+func Authenticated() Visibility {
+	return auth.Check
+}
+
+// Guest 仅未登录用户可见，如“登录”“注册”
+func Guest() Visibility {
+	return func(c *gin.Context) bool { return !auth.Check(c) }
+}
+
+// Can 拥有指定权限时可见，未设置 Authorizer 时不可见
+func Can(permission string) Visibility {
+	return func(c *gin.Context) bool {
+		fn, _ := authorizer.Load().(Authorizer)
+		return fn != nil && fn(c, permission)
+	}
+}
+
+// Menu 返回当前请求下命名菜单的可见项，链接与当前路径相同的项及其祖先标记为 Active
+func Menu(c *gin.Context, name string) []Link {
+	links, _ := resolve(c, items(name))
+	return links
+}
+
+// resolve 过滤不可见项并解析链接，返回是否含当前页面
+func resolve(c *gin.Context, items []Item) ([]Link, bool) {
+	var (
+		links  []Link
+		active bool
+	)
+	for _, it := range items {
+		if it.Visible != nil && (c == nil || !it.Visible(c)) {
+			continue
+		}
+		link := Link{Label: it.Label, URL: it.href(), Icon: it.Icon}
+		link.Active = c != nil && samePath(link.URL, c.Request.URL.Path)
+		children, childActive := resolve(c, it.Children)
+		link.Children = children
+		link.Active = link.Active || childActive
+		active = active || link.Active
+		links = append(links, link)
+	}
+	return links, active
+}
+
+// href 返回菜单项链接，命名路由无法生成时返回 #
+func (it Item) href() string {
+	if it.Route == "" {
+		return it.URL
+	}
+	u, err := router.BuildUrl(it.Route, it.Params)
+	if err != nil {
+		return "#"
+	}
+	return u
+}
+
+// samePath 比较链接的路径部分与当前请求路径
+func samePath(link, path string) bool {
+	u, err := url.Parse(link)
+	if err != nil || link == "" || link == "#" {
+		return false
+	}
+	return u.Path == path
+}
+
+// keyCrumbs gin.Context 中显式面包屑的键
+const keyCrumbs = "nav_breadcrumbs"
+
+// Push 向当前请求追加一级面包屑，url 为空表示不可点击（通常是当前页）
+func Push(c *gin.Context, label, url string) {
+	crumbs, _ := c.Get(keyCrumbs)
+	list, _ := crumbs.([]Link)
+	c.Set(keyCrumbs, append(list, Link{Label: label, URL: url}))
+}
+
+// Breadcrumbs 返回当前请求的面包屑：优先使用 Push 追加的项，
+// 否则在各菜单中查找当前页面，以其祖先链作为面包屑；最后一项标记为 Active
+func Breadcrumbs(c *gin.Context) []Link {
+	if c == nil {
+		return nil
+	}
+	var trail []Link
+	if v, ok := c.Get(keyCrumbs); ok {
+		trail = append(trail, v.([]Link)...)
+	} else {
+		mu.RLock()
+		names := make([]string, 0, len(menus))
+		for name := range menus {
+			names = append(names, name)
+		}
+		mu.RUnlock()
+		// 按名称排序，多个菜单包含当前页面时结果稳定
+		slices.Sort(names)
+		for _, name := range names {
+			if trail = activeTrail(Menu(c, name)); trail != nil {
+				break
+			}
+		}
+	}
+	if len(trail) > 0 {
+		trail[len(trail)-1].Active = true
+	}
+	return trail
+}
+
+// activeTrail 沿 Active 项向下收集祖先链
+func activeTrail(links []Link) []Link {
+	for _, l := range links {
+		if l.Active {
+			crumb := Link{Label: l.Label, URL: l.URL, Icon: l.Icon}
+			return append([]Link{crumb}, activeTrail(l.Children)...)
+		}
+	}
+	return nil
+}
+
+// View 绑定当前请求的菜单与面包屑，由 template.WithContext 以 .Nav 注入视图数据，
+// 自定义标记时可直接遍历：{{ range .Nav.Menu "main" }}...{{ end }}
+type View struct {
+	c *gin.Context
+}
+
+// For 返回绑定当前请求的 View
+func For(c *gin.Context) *View {
+	return &View{c: c}
+}
+
+// Menu 返回命名菜单，nil View 只包含没有可见性断言的项
+func (v *View) Menu(name string) []Link {
+	if v == nil {
+		return Menu(nil, name)
+	}
+	return Menu(v.c, name)
+}
+
+// Breadcrumbs 返回面包屑，nil View 返回空
+func (v *View) Breadcrumbs() []Link {
+	if v == nil {
+		return nil
+	}
+	return Breadcrumbs(v.c)
+}
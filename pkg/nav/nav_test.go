@@ -0,0 +1,95 @@
+package nav
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/router"
+)
+
+// newCtx 构造指定路径的请求上下文，user 非空时视为已登录
+func newCtx(path string, user any) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+	if user != nil {
+		c.Set(request.KeyCurrentUser, user)
+	}
+	return c
+}
+
+func defineTestMenu() {
+	router.NewRouteBuilder(gin.New()).GET("/posts/:id", func(*gin.Context) error { return nil }, "nav.post")
+	SetAuthorizer(func(c *gin.Context, perm string) bool { return perm == "post.edit" && c.Query("admin") == "1" })
+	Define("main",
+		Item{Label: "首页", URL: "/"},
+		Item{Label: "文章", URL: "/posts", Children: []Item{
+			{Label: "第一篇", Route: "nav.post", Params: map[string]any{"id": 1}},
+			{Label: "编辑", URL: "/posts/1/edit", Visible: Can("post.edit")},
+		}},
+		Item{Label: "登录", URL: "/login", Visible: Guest()},
+		Item{Label: "后台", URL: "/admin", Visible: Authenticated()},
+		Item{Label: "失效", Route: "nav.missing"},
+	)
+}
+
+func labels(links []Link) []string {
+	var out []string
+	for _, l := range links {
+		out = append(out, l.Label)
+	}
+	return out
+}
+
+func TestMenu(t *testing.T) {
+	defineTestMenu()
+
+	links := Menu(newCtx("/posts/1", nil), "main")
+	if got := labels(links); len(got) != 4 || got[2] != "登录" {
+		t.Fatalf("访客菜单: %v", got)
+	}
+	posts := links[1]
+	if !posts.Active || !posts.Children[0].Active || posts.Children[0].URL != "/posts/1" || links[0].Active {
+		t.Errorf("当前页及其祖先应高亮: %+v", links)
+	}
+	if len(posts.Children) != 1 {
+		t.Errorf("无权限的子项应隐藏: %v", labels(posts.Children))
+	}
+	if links[3].URL != "#" {
+		t.Errorf("无法生成的命名路由应为 #: %q", links[3].URL)
+	}
+
+	links = Menu(newCtx("/?admin=1", "alice"), "main")
+	if got := labels(links); got[2] != "后台" || len(links[1].Children) != 2 || !links[0].Active {
+		t.Errorf("登录用户菜单: %v %v", got, labels(links[1].Children))
+	}
+
+	if got := labels((*View)(nil).Menu("main")); len(got) != 3 {
+		t.Errorf("无请求上下文时只显示无断言的项: %v", got)
+	}
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	defineTestMenu()
+
+	trail := Breadcrumbs(newCtx("/posts/1", nil))
+	if got := labels(trail); len(got) != 2 || got[0] != "文章" || got[1] != "第一篇" {
+		t.Fatalf("按菜单推导的面包屑: %v", got)
+	}
+	if trail[0].Active || !trail[1].Active {
+		t.Errorf("仅最后一项为当前页: %+v", trail)
+	}
+
+	c := newCtx("/search", nil)
+	Push(c, "首页", "/")
+	Push(c, "搜索", "")
+	if got := For(c).Breadcrumbs(); len(got) != 2 || got[1].Label != "搜索" || !got[1].Active {
+		t.Errorf("显式面包屑: %+v", got)
+	}
+	if got := Breadcrumbs(newCtx("/unknown", nil)); got != nil {
+		t.Errorf("不在菜单中的页面没有面包屑: %+v", got)
+	}
+}
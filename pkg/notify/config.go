@@ -0,0 +1,20 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/broadcast"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// NewFromConfig 按 notify.* 配置创建通知中心，backplane 为 redis 时使用全局 Redis 配置
+func NewFromConfig(cfg *config.NotifyConfig, redisCfg *config.RedisConfig) *Hub {
+	opts := []Option{
+		WithHeartbeat(time.Duration(cfg.Heartbeat) * time.Second),
+		WithPending(cfg.PendingLimit, time.Duration(cfg.PendingTTL)*time.Second),
+	}
+	if cfg.Backplane == "redis" {
+		opts = append(opts, WithBackplane(broadcast.NewRedisFromConfig(redisCfg, cfg.RedisChannel)))
+	}
+	return New(opts...)
+}
@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// flashKey 保存闪存通知的会话键
+const flashKey = "_notify"
+
+// ViewKey 通知提示共享给视图的数据键（见 ViewData）
+const ViewKey = "Toasts"
+
+// Flash 闪存一条通知，在当前会话渲染的下一个页面展示（通常在重定向前调用）
+func Flash(c *gin.Context, level, msg string) error {
+	if !validLevel(level) {
+		return fmt.Errorf("%w: %s", ErrInvalidLevel, level)
+	}
+	// 以 map[string]string 保存，cookie/redis 等存储的 gob 编码无需额外注册类型
+	return session.SetFlash(c, flashKey, map[string]string{"id": newID(), "level": level, "message": msg})
+}
+
+// ctxKeyFlashes 本次请求已取出的闪存通知在 gin.Context 中的缓存键（闪存只能读取一次）
+const ctxKeyFlashes = "notify_flashes"
+
+// Flashes 取出闪存的全部通知（从会话中清除），同一请求内可多次调用；未挂载会话中间件或没有通知时返回 nil
+func Flashes(c *gin.Context) []Notification {
+	if v, ok := c.Get(ctxKeyFlashes); ok {
+		return v.([]Notification)
+	}
+	var list []Notification
+	// 仅在存在通知时读取闪存，避免每次渲染都保存会话（写出 Set-Cookie）
+	if _, ok := c.Get(sessions.DefaultKey); ok && session.Get(c).Get(flashKey) != nil {
+		s := session.Get(c)
+		flashes := s.Flashes(flashKey)
+		if err := s.Save(); err != nil {
+			logErrorf("读取闪存通知后保存会话失败: %v", err)
+		}
+		now := clock.Default().Now()
+		for _, f := range flashes {
+			if m, ok := f.(map[string]string); ok {
+				list = append(list, Notification{ID: m["id"], Level: m["level"], Message: m["message"], Time: now})
+			}
+		}
+	}
+	c.Set(ctxKeyFlashes, list)
+	return list
+}
+
+// View 通知提示的视图数据，由 templates/partials/toasts.html 渲染
+type View struct {
+	Stream string // SSE 路由，未登录时为空（不建立连接）
+
+	c *gin.Context
+}
+
+// Items 闪存的通知，模板渲染时才从会话中取出，不渲染提示的请求（如 API）不会消耗闪存
+func (v View) Items() []Notification {
+	if v.c == nil {
+		return nil
+	}
+	return Flashes(v.c)
+}
+
+// ViewData 返回把闪存通知与 SSE 路由以 ViewKey 共享给视图的中间件，布局中以
+// {{ include "partials/toasts" .Toasts }} 展示
+func ViewData(path string) gin.HandlerFunc {
+	return middleware.ViewData(ViewKey, func(c *gin.Context) any {
+		v := View{c: c}
+		if _, ok := middleware.ResolveUserID(c); ok {
+			v.Stream = path
+		}
+		return v
+	})
+}
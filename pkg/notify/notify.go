@@ -0,0 +1,296 @@
+// Package notify 向登录用户推送通知提示（toast）：服务、后台任务中调用 Push，
+// 用户打开的页面经 SSE 流（Handler）实时收到；当前请求内调用 Flash，通知随会话闪存在下一个页面渲染。
+// 两者由 templates/partials/toasts.html 统一展示：
+//
+//	_ = notify.Push(order.UserID, notify.LevelSuccess, "订单已发货")
+//	_ = notify.Flash(c, notify.LevelInfo, "资料已保存")
+//
+// 用户没有打开的页面时，Push 的通知暂存在内存中（WithPending），下一次连接时补发。
+// 配置背板（WithBackplane，与 broadcast 共用 Backplane 接口）后，任一实例或独立的任务进程上的 Push 可送达所有实例。
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/broadcast"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+)
+
+// 通知级别，对应提示框的样式
+const (
+	LevelInfo    = "info"
+	LevelSuccess = "success"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+var (
+	// ErrInvalidLevel 未知的通知级别
+	ErrInvalidLevel = errors.New("未知的通知级别")
+	// ErrNotConfigured 未设置全局通知中心
+	ErrNotConfigured = errors.New("通知推送未启用")
+)
+
+// Notification 一条通知
+type Notification struct {
+	ID      string    `json:"id"`
+	UserID  uint      `json:"-"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// validLevel 判断通知级别是否有效
+func validLevel(level string) bool {
+	switch level {
+	case LevelInfo, LevelSuccess, LevelWarning, LevelError:
+		return true
+	}
+	return false
+}
+
+// newID 生成通知 ID，客户端据此去重
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// stream 一个 SSE 连接
+type stream struct {
+	ch chan Notification
+}
+
+// Hub 按用户分发通知
+type Hub struct {
+	backplane    broadcast.Backplane
+	heartbeat    time.Duration
+	pendingLimit int
+	pendingTTL   time.Duration
+	clock        clock.Clock
+
+	mu      sync.Mutex
+	streams map[uint]map[*stream]struct{}
+	pending map[uint][]Notification
+
+	done     chan struct{}
+	stopOnce sync.Once
+	stop     context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Option 通知中心选项
+type Option func(*Hub)
+
+// WithBackplane 多实例背板，未设置时只分发到本实例的连接
+func WithBackplane(bp broadcast.Backplane) Option {
+	return func(h *Hub) { h.backplane = bp }
+}
+
+// WithHeartbeat SSE 心跳间隔（默认 25s），防止代理关闭空闲连接
+func WithHeartbeat(d time.Duration) Option {
+	return func(h *Hub) {
+		if d > 0 {
+			h.heartbeat = d
+		}
+	}
+}
+
+// WithPending 用户没有连接时每人最多暂存的通知数与暂存时长（默认 20 条、5 分钟），limit 为 0 时不暂存
+func WithPending(limit int, ttl time.Duration) Option {
+	return func(h *Hub) {
+		h.pendingLimit = limit
+		if ttl > 0 {
+			h.pendingTTL = ttl
+		}
+	}
+}
+
+// WithClock 通知时间与暂存过期使用的时钟
+func WithClock(c clock.Clock) Option {
+	return func(h *Hub) { h.clock = c }
+}
+
+// New 创建通知中心，配置背板时需调用 Start 接收其他实例的通知
+func New(opts ...Option) *Hub {
+	h := &Hub{
+		heartbeat:    25 * time.Second,
+		pendingLimit: 20,
+		pendingTTL:   5 * time.Minute,
+		clock:        clock.Default(),
+		streams:      make(map[uint]map[*stream]struct{}),
+		pending:      make(map[uint][]Notification),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Push 向用户推送通知；配置背板时经背板送达所有实例
+func (h *Hub) Push(userID uint, level, msg string) error {
+	if !validLevel(level) {
+		return fmt.Errorf("%w: %s", ErrInvalidLevel, level)
+	}
+	n := Notification{ID: newID(), UserID: userID, Level: level, Message: msg, Time: h.clock.Now()}
+	if h.backplane == nil {
+		h.deliver(n)
+		return nil
+	}
+	payload, _ := json.Marshal(envelope{UserID: userID, Notification: n})
+	if err := h.backplane.Publish(context.Background(), payload); err != nil {
+		return fmt.Errorf("发布通知失败: %w", err)
+	}
+	return nil
+}
+
+// envelope 背板消息，Notification 的 UserID 不参与序列化
+type envelope struct {
+	UserID uint `json:"user_id"`
+	Notification
+}
+
+// receive 处理背板收到的通知
+func (h *Hub) receive(payload []byte) {
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil || env.UserID == 0 {
+		logErrorf("无效的通知消息: %s", payload)
+		return
+	}
+	env.Notification.UserID = env.UserID
+	h.deliver(env.Notification)
+}
+
+// deliver 发送到用户在本实例上的连接，没有连接时暂存
+func (h *Hub) deliver(n Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if set := h.streams[n.UserID]; len(set) > 0 {
+		for s := range set {
+			select {
+			case s.ch <- n:
+			default: // 客户端读取过慢，丢弃
+			}
+		}
+		return
+	}
+	if h.pendingLimit <= 0 {
+		return
+	}
+	list := append(h.livePending(n.UserID), n)
+	if len(list) > h.pendingLimit {
+		list = list[len(list)-h.pendingLimit:]
+	}
+	h.pending[n.UserID] = list
+}
+
+// livePending 返回用户未过期的暂存通知，调用方持有锁
+func (h *Hub) livePending(userID uint) []Notification {
+	list := h.pending[userID]
+	cutoff := h.clock.Now().Add(-h.pendingTTL)
+	i := 0
+	for i < len(list) && list[i].Time.Before(cutoff) {
+		i++
+	}
+	return list[i:]
+}
+
+// subscribe 登记连接并取出暂存的通知
+func (h *Hub) subscribe(userID uint) (*stream, []Notification) {
+	s := &stream{ch: make(chan Notification, 16)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[*stream]struct{})
+	}
+	h.streams[userID][s] = struct{}{}
+	backlog := h.livePending(userID)
+	delete(h.pending, userID)
+	return s, backlog
+}
+
+// unsubscribe 移除连接
+func (h *Hub) unsubscribe(userID uint, s *stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.streams[userID], s)
+	if len(h.streams[userID]) == 0 {
+		delete(h.streams, userID)
+	}
+}
+
+// Online 用户在本实例上打开的连接数
+func (h *Hub) Online(userID uint) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.streams[userID])
+}
+
+// Start 启动背板接收循环，未配置背板时无操作
+func (h *Hub) Start(ctx context.Context) {
+	if h.backplane == nil {
+		return
+	}
+	ctx, h.stop = context.WithCancel(ctx)
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.backplane.Run(ctx, h.receive)
+	}()
+}
+
+// Shutdown 结束全部 SSE 连接；HTTP 服务关闭时会等待处理中的请求，需在此之前调用（见 bootstrap.RegisterHooks）
+func (h *Hub) Shutdown() {
+	h.stopOnce.Do(func() { close(h.done) })
+}
+
+// Stop 结束全部连接并停止背板接收循环
+func (h *Hub) Stop() error {
+	h.Shutdown()
+	if h.stop != nil {
+		h.stop()
+	}
+	h.wg.Wait()
+	if h.backplane != nil {
+		return h.backplane.Close()
+	}
+	return nil
+}
+
+var defaultHub atomic.Pointer[Hub]
+
+// SetDefault 设置全局通知中心（由 bootstrap 在 notify.enabled 为 true 时调用）
+func SetDefault(h *Hub) {
+	defaultHub.Store(h)
+}
+
+// Default 返回全局通知中心，未启用时返回 nil
+func Default() *Hub {
+	return defaultHub.Load()
+}
+
+// Push 使用全局通知中心向用户推送通知
+func Push(userID uint, level, msg string) error {
+	h := Default()
+	if h == nil {
+		return ErrNotConfigured
+	}
+	return h.Push(userID, level, msg)
+}
+
+// logErrorf 写入错误日志（日志未初始化时忽略）
+func logErrorf(format string, args ...any) {
+	if logger.SugarLogger != nil {
+		logger.Errorf(format, args...)
+	}
+}
@@ -0,0 +1,275 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// memoryBackplane 进程内背板，模拟多个实例共享的 Redis 频道
+type memoryBackplane struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+func (m *memoryBackplane) Publish(ctx context.Context, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.subs {
+		ch <- payload
+	}
+	return nil
+}
+
+func (m *memoryBackplane) Run(ctx context.Context, deliver func([]byte)) {
+	ch := make(chan []byte, 16)
+	m.mu.Lock()
+	m.subs = append(m.subs, ch)
+	m.mu.Unlock()
+	for {
+		select {
+		case payload := <-ch:
+			deliver(payload)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *memoryBackplane) Close() error { return nil }
+
+func (m *memoryBackplane) running() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.subs)
+}
+
+// newServer 挂载 SSE 路由，查询参数 user 作为登录用户 ID（模拟 JWT 中间件）
+func newServer(t *testing.T, h *Hub) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h.Mount(r, DefaultPath, func(c *gin.Context) {
+		if id, err := strconv.Atoi(c.Query("user")); err == nil {
+			c.Set(middleware.ContextKeyUserID, uint(id))
+		}
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(func() {
+		h.Shutdown()
+		srv.Close()
+	})
+	return srv
+}
+
+// events 连接 SSE 流，返回收到的通知
+func events(t *testing.T, srv *httptest.Server, user int) <-chan Notification {
+	t.Helper()
+	resp, err := http.Get(srv.URL + DefaultPath + "?user=" + strconv.Itoa(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d content-type = %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	ch := make(chan Notification, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(ch)
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+				var n Notification
+				_ = json.Unmarshal([]byte(data), &n)
+				ch <- n
+			}
+		}
+	}()
+	return ch
+}
+
+func next(t *testing.T, ch <-chan Notification) Notification {
+	t.Helper()
+	select {
+	case n := <-ch:
+		return n
+	case <-time.After(2 * time.Second):
+		t.Fatal("未收到通知")
+	}
+	return Notification{}
+}
+
+// waitOnline 等待 SSE 连接登记
+func waitOnline(h *Hub, user uint, n int) {
+	for h.Online(user) < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPush(t *testing.T) {
+	h := New()
+	srv := newServer(t, h)
+
+	ch := events(t, srv, 7)
+	waitOnline(h, 7, 1)
+	if err := h.Push(7, LevelSuccess, "订单已发货"); err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Push(8, LevelInfo, "其他用户")
+	n := next(t, ch)
+	if n.Level != LevelSuccess || n.Message != "订单已发货" || n.ID == "" || n.Time.IsZero() {
+		t.Errorf("notification = %+v", n)
+	}
+	if err := h.Push(7, "fatal", "x"); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("未知级别 err = %v", err)
+	}
+
+	// 关闭时结束连接
+	h.Shutdown()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("关闭后仍收到通知")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Shutdown 未结束 SSE 连接")
+	}
+}
+
+func TestPending(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+	h := New(WithClock(clk), WithPending(2, time.Minute))
+	srv := newServer(t, h)
+
+	for _, msg := range []string{"过期", "一", "二", "三"} {
+		_ = h.Push(7, LevelInfo, msg)
+		if msg == "过期" {
+			clk.Advance(2 * time.Minute)
+		}
+	}
+	// 只保留未过期的最近 2 条，连接后补发
+	ch := events(t, srv, 7)
+	if a, b := next(t, ch), next(t, ch); a.Message != "二" || b.Message != "三" {
+		t.Errorf("补发 = %q %q", a.Message, b.Message)
+	}
+	waitOnline(h, 7, 1)
+	if len(h.pending) != 0 {
+		t.Errorf("补发后仍有暂存: %v", h.pending)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	srv := newServer(t, New())
+	resp, err := http.Get(srv.URL + DefaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("未登录 status = %d", resp.StatusCode)
+	}
+}
+
+func TestBackplane(t *testing.T) {
+	bp := &memoryBackplane{}
+	h1, h2 := New(WithBackplane(bp)), New(WithBackplane(bp))
+	h1.Start(context.Background())
+	h2.Start(context.Background())
+	t.Cleanup(func() {
+		_ = h1.Stop()
+		_ = h2.Stop()
+	})
+	for bp.running() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// 用户连接在实例 2，通知在实例 1（或任务进程）上推送
+	ch := events(t, newServer(t, h2), 7)
+	waitOnline(h2, 7, 1)
+	if err := h1.Push(7, LevelWarning, "库存不足"); err != nil {
+		t.Fatal(err)
+	}
+	if n := next(t, ch); n.Level != LevelWarning || n.Message != "库存不足" {
+		t.Errorf("notification = %+v", n)
+	}
+}
+
+func TestFlash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(session.Start(&config.SessionConfig{Store: "cookie", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}, nil, nil))
+	r.Use(ViewData(DefaultPath))
+	r.POST("/save", func(c *gin.Context) {
+		if err := Flash(c, LevelSuccess, "已保存"); err != nil {
+			t.Error(err)
+		}
+		if err := Flash(c, "bad", "x"); !errors.Is(err, ErrInvalidLevel) {
+			t.Errorf("未知级别 err = %v", err)
+		}
+		c.Redirect(http.StatusFound, "/")
+	})
+	var view View
+	var again []Notification
+	r.GET("/", func(c *gin.Context) {
+		view = request.Shared(c)[ViewKey].(View)
+		_ = view.Items()
+		again = Flashes(c)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/save", nil))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	cookies := w.Result().Cookies()
+	req.AddCookie(cookies[len(cookies)-1])
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	items := view.Items()
+	if len(items) != 1 || items[0].Level != LevelSuccess || items[0].Message != "已保存" || items[0].ID == "" {
+		t.Errorf("items = %+v", items)
+	}
+	if len(again) != 1 {
+		t.Errorf("同一请求内再次读取 = %+v", again)
+	}
+	if view.Stream != "" {
+		t.Errorf("未登录时不应建立 SSE 连接: %q", view.Stream)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if err := Push(1, LevelInfo, "x"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("未启用 err = %v", err)
+	}
+	h := New()
+	SetDefault(h)
+	defer SetDefault(nil)
+	if err := Push(1, LevelInfo, "x"); err != nil || len(h.pending[1]) != 1 {
+		t.Errorf("Push = %v pending = %v", err, h.pending)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	h := NewFromConfig(&config.NotifyConfig{Backplane: "redis", RedisChannel: "n", Heartbeat: 10, PendingLimit: 5, PendingTTL: 60},
+		&config.RedisConfig{Host: "127.0.0.1", Port: 6379})
+	if h.backplane == nil || h.heartbeat != 10*time.Second || h.pendingLimit != 5 || h.pendingTTL != time.Minute {
+		t.Errorf("hub = %+v", h)
+	}
+	if h := NewFromConfig(&config.NotifyConfig{PendingLimit: 0}, nil); h.backplane != nil || h.pendingLimit != 0 || h.heartbeat != 25*time.Second {
+		t.Errorf("memory hub = %+v", h)
+	}
+}
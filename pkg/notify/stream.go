@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+// DefaultPath 默认的 SSE 路由
+const DefaultPath = "/notifications/stream"
+
+// EventName SSE 事件名，客户端以 EventSource.addEventListener("notify", ...) 接收
+const EventName = "notify"
+
+// Mount 注册 SSE 路由（GET path），handlers 在 Handler 之前执行
+func (h *Hub) Mount(r gin.IRoutes, path string, handlers ...gin.HandlerFunc) {
+	r.GET(path, append(handlers, h.Handler())...)
+}
+
+// Handler 以 SSE 向当前登录用户推送通知（用户 ID 由 middleware.ResolveUserID 从 JWT 或会话取得），未登录时返回 401。
+// 连接建立后先补发暂存的通知；每条通知为 event: notify、id 为通知 ID、data 为 Notification 的 JSON
+func (h *Hub) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := middleware.ResolveUserID(c)
+		if !ok {
+			response.Fail(c, errors.NewUnauthorized("未认证", nil))
+			return
+		}
+
+		// 长连接不受 server.write_timeout 限制
+		rc := http.NewResponseController(c.Writer)
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		s, backlog := h.subscribe(userID)
+		defer h.unsubscribe(userID, s)
+
+		header := c.Writer.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+		c.Status(http.StatusOK)
+		// 客户端断线后 3 秒重连
+		fmt.Fprint(c.Writer, "retry: 3000\n\n")
+		for _, n := range backlog {
+			writeEvent(c.Writer, n)
+		}
+		_ = rc.Flush()
+
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case n := <-s.ch:
+				writeEvent(c.Writer, n)
+			case <-ticker.C:
+				fmt.Fprint(c.Writer, ": ping\n\n")
+			case <-c.Request.Context().Done():
+				return
+			case <-h.done:
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent 写出一条 SSE 事件
+func writeEvent(w gin.ResponseWriter, n Notification) {
+	data, _ := json.Marshal(n)
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", n.ID, EventName, data)
+}
@@ -0,0 +1,269 @@
+// Package outbox 提供基于数据库表的事件发件箱（Transactional Outbox）。
+//
+// 业务代码在自身事务中调用 Emit 写入事件，事务提交后由 Dispatcher 轮询投递到 eventbus，
+// 处理失败的事件保留在表中，可通过 `app outbox:replay` 命令重新投递，保证副作用最终执行。
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//	    if err := tx.Create(&order).Error; err != nil {
+//	        return err
+//	    }
+//	    return box.Emit(tx, "order.created", order.ID)
+//	})
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// 事件状态
+const (
+	StatusPending = "pending" // 待投递
+	StatusDone    = "done"    // 已投递
+	StatusFailed  = "failed"  // 超过最大重试次数，等待人工重放
+)
+
+// Event 发件箱中的一条事件记录
+type Event struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string     `gorm:"size:191;not null;index" json:"name"`
+	Payload     string     `gorm:"type:text" json:"payload"` // 事件参数的 JSON 数组
+	Status      string     `gorm:"size:16;not null;index" json:"status"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ProcessedAt *time.Time `json:"processed_at"`
+}
+
+// TableName 表名
+func (Event) TableName() string {
+	return "event_outbox"
+}
+
+// Args 解码事件参数，每个参数保持为 json.RawMessage，由处理器自行反序列化
+func (e *Event) Args() ([]any, error) {
+	var raw []json.RawMessage
+	if e.Payload != "" {
+		if err := json.Unmarshal([]byte(e.Payload), &raw); err != nil {
+			return nil, fmt.Errorf("解析事件参数失败: %w", err)
+		}
+	}
+	args := make([]any, len(raw))
+	for i, r := range raw {
+		args[i] = r
+	}
+	return args, nil
+}
+
+// Decode 将投递给处理器的单个参数解码到 v，供经由发件箱触发的监听器使用
+//
+//	eventbus.OnE("order.created", func(ctx context.Context, args ...any) error {
+//	    var id uint
+//	    if err := outbox.Decode(args[0], &id); err != nil {
+//	        return err
+//	    }
+//	    ...
+//	})
+func Decode(arg any, v any) error {
+	switch raw := arg.(type) {
+	case json.RawMessage:
+		return json.Unmarshal(raw, v)
+	case []byte:
+		return json.Unmarshal(raw, v)
+	default:
+		// 非发件箱来源的参数，经一次 JSON 往返转换为目标类型
+		b, err := json.Marshal(arg)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, v)
+	}
+}
+
+// config 发件箱配置
+type config struct {
+	maxAttempts int
+	interval    time.Duration
+	batchSize   int
+}
+
+// Option 发件箱配置选项
+type Option func(*config)
+
+// WithMaxAttempts 设置单个事件的最大投递次数，超过后标记为 failed（默认 5）
+func WithMaxAttempts(n int) Option {
+	return func(c *config) { c.maxAttempts = n }
+}
+
+// WithInterval 设置后台轮询间隔（默认 1s）
+func WithInterval(d time.Duration) Option {
+	return func(c *config) { c.interval = d }
+}
+
+// WithBatchSize 设置每次轮询最多投递的事件数（默认 100）
+func WithBatchSize(n int) Option {
+	return func(c *config) { c.batchSize = n }
+}
+
+// Outbox 事件发件箱
+type Outbox struct {
+	db  *gorm.DB
+	bus *eventbus.EventBus
+	cfg config
+
+	notify chan struct{}
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建发件箱，bus 为 nil 时使用全局事件总线
+func New(db *gorm.DB, bus *eventbus.EventBus, opts ...Option) *Outbox {
+	cfg := config{maxAttempts: 5, interval: time.Second, batchSize: 100}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	return &Outbox{db: db, bus: bus, cfg: cfg, notify: make(chan struct{}, 1)}
+}
+
+// Migrate 创建/更新发件箱表
+func (o *Outbox) Migrate() error {
+	return o.db.AutoMigrate(&Event{})
+}
+
+// Emit 在 tx 所在事务中写入事件；tx 为 nil 时使用发件箱自身的连接。
+// 事件不会立即投递，而是在事务提交后由 Dispatch / 后台轮询发出。
+func (o *Outbox) Emit(tx *gorm.DB, event string, args ...any) error {
+	if tx == nil {
+		tx = o.db
+	}
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("序列化事件 %s 参数失败: %w", event, err)
+	}
+	record := &Event{Name: event, Payload: string(payload), Status: StatusPending}
+	if err := tx.Create(record).Error; err != nil {
+		return fmt.Errorf("写入事件 %s 失败: %w", event, err)
+	}
+	o.Notify()
+	return nil
+}
+
+// Notify 唤醒后台投递循环，无需等待下一个轮询周期
+func (o *Outbox) Notify() {
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Dispatch 投递一批待处理事件，返回成功投递的数量。
+// 处理器返回错误时事件保持 pending 并累加重试次数，达到上限后标记为 failed。
+func (o *Outbox) Dispatch(ctx context.Context) (int, error) {
+	var events []Event
+	err := o.db.WithContext(ctx).
+		Where("status = ?", StatusPending).
+		Order("id").
+		Limit(o.cfg.batchSize).
+		Find(&events).Error
+	if err != nil {
+		return 0, fmt.Errorf("读取待投递事件失败: %w", err)
+	}
+
+	done := 0
+	for i := range events {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if o.deliver(ctx, &events[i]) {
+			done++
+		}
+	}
+	return done, nil
+}
+
+// deliver 投递单个事件并更新其状态
+func (o *Outbox) deliver(ctx context.Context, e *Event) bool {
+	updates := map[string]any{"attempts": e.Attempts + 1}
+
+	args, err := e.Args()
+	if err == nil {
+		err = o.bus.EmitCtx(ctx, e.Name, args...)
+	}
+
+	if err == nil {
+		now := time.Now()
+		updates["status"] = StatusDone
+		updates["last_error"] = ""
+		updates["processed_at"] = &now
+	} else {
+		updates["last_error"] = err.Error()
+		if e.Attempts+1 >= o.cfg.maxAttempts {
+			updates["status"] = StatusFailed
+		}
+	}
+
+	if uerr := o.db.WithContext(ctx).Model(&Event{}).Where("id = ?", e.ID).Updates(updates).Error; uerr != nil {
+		logErrorf("更新发件箱事件 %d 状态失败: %v", e.ID, uerr)
+	}
+	return err == nil
+}
+
+// Replay 将失败事件重置为待投递并立即投递一轮；未指定 ids 时重放全部失败事件
+func (o *Outbox) Replay(ctx context.Context, ids ...uint64) (int, error) {
+	query := o.db.WithContext(ctx).Model(&Event{}).Where("status = ?", StatusFailed)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	if err := query.Updates(map[string]any{"status": StatusPending, "attempts": 0}).Error; err != nil {
+		return 0, fmt.Errorf("重置失败事件失败: %w", err)
+	}
+	return o.Dispatch(ctx)
+}
+
+// Start 启动后台投递循环，按轮询间隔或 Notify 唤醒时调用 Dispatch
+func (o *Outbox) Start(ctx context.Context) {
+	ctx, o.stop = context.WithCancel(ctx)
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		ticker := time.NewTicker(o.cfg.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-o.notify:
+			}
+			if _, err := o.Dispatch(ctx); err != nil && ctx.Err() == nil {
+				logErrorf("发件箱投递失败: %v", err)
+			}
+		}
+	}()
+}
+
+// logErrorf 写入错误日志（日志未初始化时忽略，便于在命令行与测试中使用）
+func logErrorf(format string, args ...any) {
+	if logger.SugarLogger != nil {
+		logger.Errorf(format, args...)
+	}
+}
+
+// Stop 停止后台投递循环并等待当前批次结束
+func (o *Outbox) Stop() {
+	if o.stop != nil {
+		o.stop()
+	}
+	o.wg.Wait()
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestOutbox(t *testing.T, bus *eventbus.EventBus, opts ...Option) (*Outbox, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	box := New(db, bus, opts...)
+	if err := box.Migrate(); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	return box, db
+}
+
+func TestOutbox_EmitInTransaction(t *testing.T) {
+	bus := eventbus.New()
+	box, db := newTestOutbox(t, bus)
+
+	var got uint
+	bus.OnE("order.created", func(ctx context.Context, args ...interface{}) error {
+		return Decode(args[0], &got)
+	})
+
+	// 回滚的事务不应留下事件
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		if err := box.Emit(tx, "order.created", 1); err != nil {
+			t.Fatal(err)
+		}
+		return errors.New("rollback")
+	})
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return box.Emit(tx, "order.created", 42)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := box.Dispatch(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Dispatch = %d, %v; want 1, nil", n, err)
+	}
+	if got != 42 {
+		t.Errorf("handler got %d, want 42", got)
+	}
+
+	var e Event
+	db.First(&e)
+	if e.Status != StatusDone || e.Attempts != 1 || e.ProcessedAt == nil {
+		t.Errorf("unexpected event state: %+v", e)
+	}
+
+	// 已投递的事件不会重复投递
+	if n, _ := box.Dispatch(context.Background()); n != 0 {
+		t.Errorf("second Dispatch = %d, want 0", n)
+	}
+}
+
+func TestOutbox_FailureAndReplay(t *testing.T) {
+	bus := eventbus.New()
+	bus.SetErrorHandler(func(string, error) {})
+	box, db := newTestOutbox(t, bus, WithMaxAttempts(2))
+
+	fail := true
+	calls := 0
+	bus.OnE("mail.send", func(ctx context.Context, args ...interface{}) error {
+		calls++
+		if fail {
+			return errors.New("smtp down")
+		}
+		return nil
+	})
+
+	if err := box.Emit(nil, "mail.send", map[string]string{"to": "a@b.c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		box.Dispatch(context.Background())
+	}
+	var e Event
+	db.First(&e)
+	if e.Status != StatusFailed || e.Attempts != 2 || e.LastError == "" {
+		t.Fatalf("unexpected event state after failures: %+v", e)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+
+	fail = false
+	n, err := box.Replay(context.Background(), e.ID)
+	if err != nil || n != 1 {
+		t.Fatalf("Replay = %d, %v; want 1, nil", n, err)
+	}
+	db.First(&e, e.ID)
+	if e.Status != StatusDone || e.LastError != "" {
+		t.Errorf("unexpected event state after replay: %+v", e)
+	}
+}
@@ -0,0 +1,403 @@
+package pdf
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"golang.org/x/net/websocket"
+)
+
+// chromeOrigin 连接 DevTools 时使用的 Origin。Chrome 只接受 --remote-allow-origins 允许的来源，
+// .invalid 域名不可解析，任何网页都不会具有该来源，连接远程实例时须以 --remote-allow-origins=http://pdf.invalid 启动
+const chromeOrigin = "http://pdf.invalid"
+
+// chromeBinaries 未指定路径时在 PATH 中依次查找的可执行文件
+var chromeBinaries = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome"}
+
+// chromeReady 等待页面加载与字体就绪的脚本
+const chromeReady = `new Promise(function (resolve) {
+	var done = function () { (document.fonts ? document.fonts.ready : Promise.resolve()).then(function () { resolve(true) }) };
+	document.readyState === "complete" ? done() : window.addEventListener("load", done);
+})`
+
+// mmPerInch 毫米与英寸的换算（DevTools 协议以英寸为单位）
+const mmPerInch = 25.4
+
+// Chrome 通过 DevTools 协议调用无头 Chrome 转换：未指定 URL 时在首次转换时启动本机 Chrome 并复用，
+// 每个文档在独立的标签页中渲染。页眉页脚使用 Chrome 原生的页眉页脚模板
+type Chrome struct {
+	path   string
+	url    string
+	args   []string
+	client *http.Client
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	dir      string
+	endpoint string
+	exited   chan struct{}
+}
+
+// ChromeOption Chrome 驱动选项
+type ChromeOption func(*Chrome)
+
+// WithChromePath 可执行文件路径，默认在 PATH 中查找 chromium、google-chrome 等
+func WithChromePath(path string) ChromeOption {
+	return func(c *Chrome) { c.path = path }
+}
+
+// WithChromeURL 连接已运行的 Chrome（如容器中的 chromedp/headless-shell），DevTools 地址如 http://chrome:9222，
+// 不再启动本机进程
+func WithChromeURL(u string) ChromeOption {
+	return func(c *Chrome) { c.url = strings.TrimSuffix(u, "/") }
+}
+
+// WithChromeArgs 启动本机 Chrome 时追加的命令行参数
+func WithChromeArgs(args ...string) ChromeOption {
+	return func(c *Chrome) { c.args = append(c.args, args...) }
+}
+
+// NewChrome 创建 Chrome 驱动
+func NewChrome(opts ...ChromeOption) *Chrome {
+	c := &Chrome{client: httpclient.New(30 * time.Second)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert 实现 Driver
+func (c *Chrome) Convert(ctx context.Context, html string, opts *Options) ([]byte, error) {
+	doc, err := c.convert(ctx, html, opts)
+	if err != nil && ctx.Err() != nil {
+		// ctx 结束时连接被关闭，返回 ctx 的错误而不是连接错误
+		return nil, ctx.Err()
+	}
+	return doc, err
+}
+
+// convert 在新标签页中载入 HTML 并打印为 PDF
+func (c *Chrome) convert(ctx context.Context, html string, opts *Options) ([]byte, error) {
+	endpoint, err := c.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.newTarget(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeTarget(endpoint, target.ID)
+
+	conn, err := dialCDP(ctx, target.WebSocketDebuggerURL, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var tree struct {
+		FrameTree struct {
+			Frame struct {
+				ID string `json:"id"`
+			} `json:"frame"`
+		} `json:"frameTree"`
+	}
+	if err := conn.call("Page.getFrameTree", nil, &tree); err != nil {
+		return nil, err
+	}
+	if err := conn.call("Page.setDocumentContent", map[string]any{"frameId": tree.FrameTree.Frame.ID, "html": html}, nil); err != nil {
+		return nil, err
+	}
+	if err := conn.call("Runtime.evaluate", map[string]any{"expression": chromeReady, "awaitPromise": true, "returnByValue": true}, nil); err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Data string `json:"data"`
+	}
+	if err := conn.call("Page.printToPDF", printParams(opts), &res); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(res.Data)
+}
+
+// printParams Page.printToPDF 的参数
+func printParams(opts *Options) map[string]any {
+	inch := func(mm float64) float64 { return mm / mmPerInch }
+	params := map[string]any{
+		"landscape":         opts.Landscape,
+		"printBackground":   opts.Background,
+		"paperWidth":        inch(opts.Width),
+		"paperHeight":       inch(opts.Height),
+		"marginTop":         inch(opts.Margin.Top),
+		"marginRight":       inch(opts.Margin.Right),
+		"marginBottom":      inch(opts.Margin.Bottom),
+		"marginLeft":        inch(opts.Margin.Left),
+		"preferCSSPageSize": false,
+	}
+	if opts.Header != "" || opts.Footer != "" {
+		// 只设置其一时，另一项为空元素，否则 Chrome 输出默认的日期与标题
+		header, footer := opts.Header, opts.Footer
+		if header == "" {
+			header = "<span></span>"
+		}
+		if footer == "" {
+			footer = "<span></span>"
+		}
+		params["displayHeaderFooter"] = true
+		params["headerTemplate"] = header
+		params["footerTemplate"] = footer
+	}
+	return params
+}
+
+// start 返回 DevTools 地址，本机 Chrome 未运行时启动
+func (c *Chrome) start(ctx context.Context) (string, error) {
+	if c.url != "" {
+		return c.url, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cmd != nil {
+		select {
+		case <-c.exited:
+			c.cleanup()
+		default:
+			return c.endpoint, nil
+		}
+	}
+
+	path := c.path
+	if path == "" {
+		for _, name := range chromeBinaries {
+			if p, err := exec.LookPath(name); err == nil {
+				path = p
+				break
+			}
+		}
+		if path == "" {
+			return "", errors.New("未找到 Chrome / Chromium，请安装或通过 pdf.binary 指定路径")
+		}
+	}
+	dir, err := os.MkdirTemp("", "pdf-chrome-*")
+	if err != nil {
+		return "", err
+	}
+	args := []string{
+		"--headless=new", "--disable-gpu", "--no-first-run", "--no-default-browser-check",
+		"--hide-scrollbars", "--mute-audio", "--remote-debugging-address=127.0.0.1", "--remote-debugging-port=0",
+		"--remote-allow-origins=" + chromeOrigin, "--user-data-dir=" + dir,
+	}
+	if os.Geteuid() == 0 {
+		// 以 root 运行（如容器中）时 Chrome 要求关闭沙箱
+		args = append(args, "--no-sandbox")
+	}
+	args = append(args, c.args...)
+	args = append(args, "about:blank")
+
+	cmd := exec.Command(path, args...)
+	stderr, w := io.Pipe()
+	cmd.Stderr = w
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("启动 Chrome 失败: %w", err)
+	}
+	c.cmd, c.dir, c.exited = cmd, dir, make(chan struct{})
+
+	found := make(chan string, 1)
+	go func() {
+		sc := bufio.NewScanner(stderr)
+		for sc.Scan() {
+			// DevTools listening on ws://127.0.0.1:39021/devtools/browser/<id>
+			if _, ws, ok := strings.Cut(sc.Text(), "DevTools listening on "); ok {
+				found <- ws
+				break
+			}
+		}
+		_, _ = io.Copy(io.Discard, stderr)
+	}()
+	exited := c.exited
+	go func() {
+		_ = cmd.Wait()
+		w.Close()
+		close(exited)
+	}()
+
+	wait := time.NewTimer(20 * time.Second)
+	defer wait.Stop()
+	select {
+	case ws := <-found:
+		u, err := url.Parse(ws)
+		if err != nil {
+			c.stop()
+			return "", fmt.Errorf("解析 DevTools 地址失败: %w", err)
+		}
+		c.endpoint = "http://" + u.Host
+		return c.endpoint, nil
+	case <-exited:
+		c.cleanup()
+		return "", errors.New("Chrome 启动后立即退出")
+	case <-wait.C:
+		c.stop()
+		return "", errors.New("等待 Chrome 启动超时")
+	case <-ctx.Done():
+		c.stop()
+		return "", ctx.Err()
+	}
+}
+
+// stop 结束本机 Chrome 进程，须持有 c.mu
+func (c *Chrome) stop() {
+	if c.cmd == nil {
+		return
+	}
+	_ = c.cmd.Process.Kill()
+	<-c.exited
+	c.cleanup()
+}
+
+// cleanup 清理已退出进程的状态与用户数据目录，须持有 c.mu
+func (c *Chrome) cleanup() {
+	os.RemoveAll(c.dir)
+	c.cmd, c.dir, c.endpoint = nil, "", ""
+}
+
+// Close 结束本机启动的 Chrome 进程
+func (c *Chrome) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stop()
+	return nil
+}
+
+// chromeTarget 标签页
+type chromeTarget struct {
+	ID                   string `json:"id"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+// newTarget 新建空白标签页
+func (c *Chrome) newTarget(ctx context.Context, endpoint string) (*chromeTarget, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/json/new?about:blank", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("连接 Chrome DevTools 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("新建标签页失败: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var t chromeTarget
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	if t.WebSocketDebuggerURL == "" {
+		return nil, errors.New("新建标签页失败: 缺少 webSocketDebuggerUrl")
+	}
+	return &t, nil
+}
+
+// closeTarget 关闭标签页，不受转换 ctx 取消的影响
+func (c *Chrome) closeTarget(endpoint, id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/json/close/"+id, nil)
+	if err != nil {
+		return
+	}
+	if resp, err := c.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// cdpConn 单个标签页的 DevTools 连接，按顺序发送命令并等待对应的响应（期间的事件被忽略）
+type cdpConn struct {
+	ws   *websocket.Conn
+	id   int64
+	stop func() bool
+}
+
+// dialCDP 连接标签页。wsURL 的主机替换为 endpoint 的主机，使远程实例返回的内部地址可用；
+// ctx 结束时关闭连接以中断等待
+func dialCDP(ctx context.Context, wsURL, endpoint string) (*cdpConn, error) {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, err
+	}
+	if e, err := url.Parse(endpoint); err == nil && e.Host != "" {
+		u.Host = e.Host
+		if e.Scheme == "https" {
+			u.Scheme = "wss"
+		}
+	}
+	cfg, err := websocket.NewConfig(u.String(), chromeOrigin)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := cfg.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("连接 Chrome 标签页失败: %w", err)
+	}
+	ws.MaxPayloadBytes = 512 << 20
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = ws.SetDeadline(deadline)
+	}
+	return &cdpConn{ws: ws, stop: context.AfterFunc(ctx, func() { ws.Close() })}, nil
+}
+
+// call 发送命令并等待响应，result 为 nil 时忽略结果
+func (c *cdpConn) call(method string, params any, result any) error {
+	c.id++
+	msg := map[string]any{"id": c.id, "method": method}
+	if params != nil {
+		msg["params"] = params
+	}
+	if err := websocket.JSON.Send(c.ws, msg); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	for {
+		var resp struct {
+			ID     int64           `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := websocket.JSON.Receive(c.ws, &resp); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		if resp.ID != c.id {
+			continue
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("%s: %s（%d）", method, resp.Error.Message, resp.Error.Code)
+		}
+		if result != nil {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	}
+}
+
+// Close 关闭连接
+func (c *cdpConn) Close() error {
+	c.stop()
+	return c.ws.Close()
+}
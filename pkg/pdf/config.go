@@ -0,0 +1,46 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// NewFromConfig 按 pdf.* 配置创建生成器，renderer 通常为 pdf.RendererFunc(template.RenderToString)
+func NewFromConfig(cfg *config.PDFConfig, renderer Renderer) (*Generator, error) {
+	var driver Driver
+	switch cfg.Driver {
+	case "", "chrome":
+		driver = NewChrome(WithChromePath(cfg.Binary), WithChromeURL(cfg.URL))
+	case "wkhtmltopdf":
+		driver = NewWkhtmltopdf(WithWkhtmltopdfPath(cfg.Binary))
+	default:
+		return nil, fmt.Errorf("未知的 PDF 驱动: %s", cfg.Driver)
+	}
+
+	var defaults []Option
+	if cfg.PageSize != "" {
+		if _, ok := paperSizes[strings.ToUpper(cfg.PageSize)]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPageSize, cfg.PageSize)
+		}
+		defaults = append(defaults, WithPageSize(cfg.PageSize))
+	}
+	if cfg.Landscape {
+		defaults = append(defaults, WithLandscape())
+	}
+	if cfg.Margin >= 0 {
+		defaults = append(defaults, WithMargin(cfg.Margin, cfg.Margin, cfg.Margin, cfg.Margin))
+	}
+	if cfg.Layout != "" {
+		defaults = append(defaults, WithLayout(cfg.Layout))
+	}
+	if cfg.Header != "" {
+		defaults = append(defaults, WithHeader(cfg.Header, nil))
+	}
+	if cfg.Footer != "" {
+		defaults = append(defaults, WithFooter(cfg.Footer, nil))
+	}
+	return New(driver, renderer, WithTimeout(time.Duration(cfg.Timeout)*time.Second), WithDefaults(defaults...)), nil
+}
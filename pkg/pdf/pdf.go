@@ -0,0 +1,330 @@
+// Package pdf 将 HTML 模板渲染为 PDF：页面、页眉与页脚均使用现有的模板，转换由可替换的驱动完成，
+// 内置 Chrome（DevTools 协议，本机启动或连接远程实例）与 wkhtmltopdf 两种驱动。
+//
+//	doc, err := pdf.Render(ctx, "reports/invoice", data,
+//	    pdf.WithLayout("print"),
+//	    pdf.WithPageSize("A4"),
+//	    pdf.WithFooter("reports/footer", data),
+//	)
+//	response.PDF(c, "发票.pdf", doc, false)
+//
+// 页眉页脚模板中 class 为 pageNumber、totalPages、date、title 的元素在每页输出时分别填入当前页码、总页数、
+// 日期与文档标题（两种驱动一致）。Chrome 的页眉页脚不继承页面样式，字号等须写在模板内；
+// 上下边距须足以容纳页眉页脚。
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
+)
+
+// DefaultTimeout 单个文档的默认转换超时
+const DefaultTimeout = time.Minute
+
+var (
+	// ErrNotConfigured 未设置全局生成器
+	ErrNotConfigured = errors.New("PDF 生成器未配置")
+	// ErrPageSize 未知的纸张规格
+	ErrPageSize = errors.New("未知的纸张规格")
+)
+
+// paperSizes 常用纸张规格（毫米，纵向）
+var paperSizes = map[string][2]float64{
+	"A3":      {297, 420},
+	"A4":      {210, 297},
+	"A5":      {148, 210},
+	"B5":      {176, 250},
+	"LETTER":  {215.9, 279.4},
+	"LEGAL":   {215.9, 355.6},
+	"TABLOID": {279.4, 431.8},
+}
+
+// Margin 页边距（毫米）
+type Margin struct {
+	Top, Right, Bottom, Left float64
+}
+
+// Options 转换选项，由驱动读取。页眉页脚为已渲染的 HTML 片段
+type Options struct {
+	PageSize   string  // 纸张规格名称，如 A4、Letter
+	Width      float64 // 纸张宽度（毫米，纵向），由 PageSize 或 WithPaperSize 确定
+	Height     float64 // 纸张高度（毫米，纵向）
+	Landscape  bool
+	Margin     Margin
+	Header     string
+	Footer     string
+	Background bool // 输出背景色与背景图，默认开启
+}
+
+// Driver 将完整的 HTML 文档转换为 PDF
+type Driver interface {
+	Convert(ctx context.Context, html string, opts *Options) ([]byte, error)
+}
+
+// Renderer 渲染模板为 HTML，template.Manager 满足该接口
+type Renderer interface {
+	RenderToString(name string, data any, layout ...string) (string, error)
+}
+
+// RendererFunc 函数形式的 Renderer，如 pdf.RendererFunc(template.RenderToString)
+type RendererFunc func(name string, data any, layout ...string) (string, error)
+
+// RenderToString 实现 Renderer
+func (f RendererFunc) RenderToString(name string, data any, layout ...string) (string, error) {
+	return f(name, data, layout...)
+}
+
+// part 待渲染的页眉或页脚
+type part struct {
+	name string
+	data any
+	html string
+}
+
+// settings 单次生成的设置
+type settings struct {
+	opts   Options
+	layout string
+	header *part
+	footer *part
+	err    error
+}
+
+// Option 生成选项
+type Option func(*settings)
+
+// WithLayout 页面模板使用的布局
+func WithLayout(layout string) Option {
+	return func(c *settings) { c.layout = layout }
+}
+
+// WithPageSize 纸张规格：A3、A4、A5、B5、Letter、Legal、Tabloid（不区分大小写）
+func WithPageSize(size string) Option {
+	return func(c *settings) {
+		wh, ok := paperSizes[strings.ToUpper(size)]
+		if !ok {
+			c.err = fmt.Errorf("%w: %s", ErrPageSize, size)
+			return
+		}
+		c.opts.PageSize = strings.ToUpper(size)
+		c.opts.Width, c.opts.Height = wh[0], wh[1]
+	}
+}
+
+// WithPaperSize 自定义纸张尺寸（毫米，纵向）
+func WithPaperSize(width, height float64) Option {
+	return func(c *settings) {
+		c.opts.PageSize = ""
+		c.opts.Width, c.opts.Height = width, height
+	}
+}
+
+// WithLandscape 横向输出
+func WithLandscape() Option {
+	return func(c *settings) { c.opts.Landscape = true }
+}
+
+// WithMargin 页边距（毫米）
+func WithMargin(top, right, bottom, left float64) Option {
+	return func(c *settings) { c.opts.Margin = Margin{top, right, bottom, left} }
+}
+
+// WithBackground 是否输出背景色与背景图，默认输出
+func WithBackground(on bool) Option {
+	return func(c *settings) { c.opts.Background = on }
+}
+
+// WithHeader 以模板渲染页眉（不使用布局），data 为 nil 时使用页面的数据
+func WithHeader(name string, data any) Option {
+	return func(c *settings) { c.header = &part{name: name, data: data} }
+}
+
+// WithFooter 以模板渲染页脚（不使用布局），data 为 nil 时使用页面的数据
+func WithFooter(name string, data any) Option {
+	return func(c *settings) { c.footer = &part{name: name, data: data} }
+}
+
+// WithHeaderHTML 以 HTML 片段作为页眉
+func WithHeaderHTML(html string) Option {
+	return func(c *settings) { c.header = &part{html: html} }
+}
+
+// WithFooterHTML 以 HTML 片段作为页脚
+func WithFooterHTML(html string) Option {
+	return func(c *settings) { c.footer = &part{html: html} }
+}
+
+// Generator PDF 生成器
+type Generator struct {
+	driver   Driver
+	renderer Renderer
+	timeout  time.Duration
+	defaults []Option
+}
+
+// GeneratorOption 生成器选项
+type GeneratorOption func(*Generator)
+
+// WithTimeout 单个文档的转换超时，默认 DefaultTimeout
+func WithTimeout(d time.Duration) GeneratorOption {
+	return func(g *Generator) {
+		if d > 0 {
+			g.timeout = d
+		}
+	}
+}
+
+// WithDefaults 每次生成的默认选项（如纸张与边距），可被调用时的选项覆盖
+func WithDefaults(opts ...Option) GeneratorOption {
+	return func(g *Generator) { g.defaults = append(g.defaults, opts...) }
+}
+
+// New 创建生成器，默认 A4 纵向、四边 10 毫米
+func New(driver Driver, renderer Renderer, opts ...GeneratorOption) *Generator {
+	g := &Generator{driver: driver, renderer: renderer, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Render 渲染模板并转换为 PDF
+func (g *Generator) Render(ctx context.Context, name string, data any, opts ...Option) ([]byte, error) {
+	cfg, err := g.settings(opts, data)
+	if err != nil {
+		return nil, err
+	}
+	var layout []string
+	if cfg.layout != "" {
+		layout = append(layout, cfg.layout)
+	}
+	html, err := g.render(name, data, layout...)
+	if err != nil {
+		return nil, err
+	}
+	return g.convert(ctx, html, cfg)
+}
+
+// HTML 将完整的 HTML 文档转换为 PDF
+func (g *Generator) HTML(ctx context.Context, html string, opts ...Option) ([]byte, error) {
+	cfg, err := g.settings(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return g.convert(ctx, html, cfg)
+}
+
+// Go 提交到工作池（nil 时使用全局工作池）后台生成，适合耗时的大型报表；完成后以生成结果调用 done，
+// 生成失败时 doc 为 nil。工作池队列已满或已停止时返回 concurrent.ErrQueueFull / ErrPoolClosed：
+//
+//	pdf.Default().Go(nil, "reports/annual", data, func(ctx context.Context, doc []byte, err error) error {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return disk.Put(ctx, "reports/2024.pdf", bytes.NewReader(doc))
+//	})
+func (g *Generator) Go(pool *concurrent.Pool, name string, data any, done func(ctx context.Context, doc []byte, err error) error, opts ...Option) error {
+	if pool == nil {
+		if pool = concurrent.Default(); pool == nil {
+			return concurrent.ErrPoolClosed
+		}
+	}
+	return pool.Go(func(ctx context.Context) error {
+		doc, err := g.Render(ctx, name, data, opts...)
+		return done(ctx, doc, err)
+	})
+}
+
+// settings 合并默认选项与调用选项，并渲染页眉页脚（data 为页面数据）
+func (g *Generator) settings(opts []Option, data any) (*settings, error) {
+	cfg := &settings{opts: Options{Margin: Margin{10, 10, 10, 10}, Background: true}}
+	WithPageSize("A4")(cfg)
+	for _, opt := range g.defaults {
+		opt(cfg)
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+	if cfg.opts.Width <= 0 || cfg.opts.Height <= 0 {
+		return nil, fmt.Errorf("%w: %gx%g", ErrPageSize, cfg.opts.Width, cfg.opts.Height)
+	}
+	var err error
+	if cfg.opts.Header, err = g.part(cfg.header, data); err != nil {
+		return nil, err
+	}
+	if cfg.opts.Footer, err = g.part(cfg.footer, data); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// part 渲染页眉或页脚
+func (g *Generator) part(p *part, data any) (string, error) {
+	if p == nil {
+		return "", nil
+	}
+	if p.name == "" {
+		return p.html, nil
+	}
+	if p.data != nil {
+		data = p.data
+	}
+	return g.render(p.name, data)
+}
+
+// render 渲染模板
+func (g *Generator) render(name string, data any, layout ...string) (string, error) {
+	if g.renderer == nil {
+		return "", errors.New("PDF 生成器未设置模板渲染器")
+	}
+	return g.renderer.RenderToString(name, data, layout...)
+}
+
+// convert 在超时内调用驱动转换
+func (g *Generator) convert(ctx context.Context, html string, cfg *settings) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	doc, err := g.driver.Convert(ctx, html, &cfg.opts)
+	if err != nil {
+		return nil, fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	return doc, nil
+}
+
+// Close 关闭驱动占用的资源（如本机启动的 Chrome 进程）
+func (g *Generator) Close() error {
+	if c, ok := g.driver.(interface{ Close() error }); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+var defaultGenerator atomic.Pointer[Generator]
+
+// SetDefault 设置全局生成器（由 bootstrap 按 pdf.* 配置调用）
+func SetDefault(g *Generator) {
+	defaultGenerator.Store(g)
+}
+
+// Default 返回全局生成器，未设置时返回 nil
+func Default() *Generator {
+	return defaultGenerator.Load()
+}
+
+// Render 使用全局生成器渲染模板为 PDF
+func Render(ctx context.Context, name string, data any, opts ...Option) ([]byte, error) {
+	g := Default()
+	if g == nil {
+		return nil, ErrNotConfigured
+	}
+	return g.Render(ctx, name, data, opts...)
+}
@@ -0,0 +1,320 @@
+package pdf
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"golang.org/x/net/websocket"
+)
+
+// fakeDriver 记录转换的 HTML 与选项
+type fakeDriver struct {
+	html string
+	opts Options
+}
+
+func (d *fakeDriver) Convert(ctx context.Context, html string, opts *Options) ([]byte, error) {
+	if html == "slow" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	d.html, d.opts = html, *opts
+	return []byte("%PDF " + html), nil
+}
+
+// fakeRenderer 以模板名、数据与布局拼接输出
+var fakeRenderer = RendererFunc(func(name string, data any, layout ...string) (string, error) {
+	if name == "missing" {
+		return "", errors.New("模板不存在")
+	}
+	return fmt.Sprintf("%s(%v)%s", name, data, strings.Join(layout, "")), nil
+})
+
+// TestRender 渲染页面与页眉页脚，默认选项可被调用时的选项覆盖
+func TestRender(t *testing.T) {
+	d := &fakeDriver{}
+	g := New(d, fakeRenderer, WithDefaults(WithLayout("print"), WithFooter("footer", nil), WithMargin(20, 10, 20, 10)))
+
+	doc, err := g.Render(context.Background(), "invoice", 7, WithPageSize("letter"), WithLandscape(), WithHeaderHTML(`<span class="pageNumber"></span>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(doc) != "%PDF invoice(7)print" {
+		t.Errorf("doc = %q", doc)
+	}
+	want := Options{
+		PageSize: "LETTER", Width: 215.9, Height: 279.4, Landscape: true,
+		Margin: Margin{20, 10, 20, 10}, Header: `<span class="pageNumber"></span>`, Footer: "footer(7)", Background: true,
+	}
+	if d.opts != want {
+		t.Errorf("opts = %+v\nwant %+v", d.opts, want)
+	}
+
+	if _, err := g.HTML(context.Background(), "<p>x</p>", WithPaperSize(100, 150), WithFooter("footer", "f"), WithBackground(false)); err != nil {
+		t.Fatal(err)
+	}
+	if d.html != "<p>x</p>" || d.opts.PageSize != "" || d.opts.Width != 100 || d.opts.Footer != "footer(f)" || d.opts.Background {
+		t.Errorf("HTML opts = %+v", d.opts)
+	}
+
+	if _, err := g.Render(context.Background(), "invoice", nil, WithPageSize("B0")); !errors.Is(err, ErrPageSize) {
+		t.Errorf("未知纸张: %v", err)
+	}
+	if _, err := g.Render(context.Background(), "missing", nil); err == nil {
+		t.Error("模板错误应返回")
+	}
+}
+
+// TestTimeout 超过转换超时返回 ctx 错误
+func TestTimeout(t *testing.T) {
+	g := New(&fakeDriver{}, fakeRenderer, WithTimeout(10*time.Millisecond))
+	if _, err := g.HTML(context.Background(), "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestDefault 未设置全局生成器时返回 ErrNotConfigured
+func TestDefault(t *testing.T) {
+	SetDefault(nil)
+	if _, err := Render(context.Background(), "invoice", nil); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("err = %v", err)
+	}
+	g := New(&fakeDriver{}, fakeRenderer)
+	SetDefault(g)
+	defer SetDefault(nil)
+	if doc, err := Render(context.Background(), "invoice", 1); err != nil || string(doc) != "%PDF invoice(1)" {
+		t.Errorf("Render = %q, %v", doc, err)
+	}
+}
+
+// TestGo 提交到工作池后台生成
+func TestGo(t *testing.T) {
+	pool := concurrent.NewPool(concurrent.WithWorkers(1))
+	defer pool.Stop(context.Background())
+
+	g := New(&fakeDriver{}, fakeRenderer)
+	done := make(chan string, 1)
+	err := g.Go(pool, "report", 1, func(_ context.Context, doc []byte, err error) error {
+		done <- fmt.Sprint(string(doc), err)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-done; got != "%PDF report(1)<nil>" {
+		t.Errorf("结果 %q", got)
+	}
+}
+
+// TestNewFromConfig 按配置选择驱动并设置默认选项
+func TestNewFromConfig(t *testing.T) {
+	g, err := NewFromConfig(&config.PDFConfig{Driver: "wkhtmltopdf", PageSize: "A5", Landscape: true, Margin: 5, Timeout: 3}, fakeRenderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.driver.(*Wkhtmltopdf); !ok || g.timeout != 3*time.Second {
+		t.Errorf("driver = %T, timeout = %v", g.driver, g.timeout)
+	}
+	cfg, err := g.settings(nil, nil)
+	if err != nil || cfg.opts.PageSize != "A5" || !cfg.opts.Landscape || cfg.opts.Margin != (Margin{5, 5, 5, 5}) {
+		t.Errorf("opts = %+v, %v", cfg, err)
+	}
+
+	if _, err := NewFromConfig(&config.PDFConfig{Driver: "prince"}, fakeRenderer); err == nil {
+		t.Error("未知驱动应返回错误")
+	}
+	if _, err := NewFromConfig(&config.PDFConfig{PageSize: "B0"}, fakeRenderer); !errors.Is(err, ErrPageSize) {
+		t.Errorf("未知纸张: %v", err)
+	}
+}
+
+// fakeWkhtmltopdf 写出记录参数、页脚与标准输入的脚本
+func fakeWkhtmltopdf(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+out="$(dirname "$0")/args"
+: > "$out"
+prev=""
+for a in "$@"; do
+  echo "$a" >> "$out"
+  if [ "$prev" = "--footer-html" ]; then cat "$a" > "$out.footer"; fi
+  prev="$a"
+done
+cat > "$out.stdin"
+if grep -q fail "$out.stdin"; then echo "Exit with code 1" >&2; exit 1; fi
+printf '%%PDF-wk'
+`
+	path := filepath.Join(dir, "wkhtmltopdf")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestWkhtmltopdf 命令行参数、标准输入与页脚文件
+func TestWkhtmltopdf(t *testing.T) {
+	path := fakeWkhtmltopdf(t)
+	w := NewWkhtmltopdf(WithWkhtmltopdfPath(path), WithWkhtmltopdfArgs("--javascript-delay", "500"))
+	g := New(w, fakeRenderer)
+
+	doc, err := g.HTML(context.Background(), "<p>报表</p>", WithPageSize("letter"), WithLandscape(), WithFooterHTML(`<span class="pageNumber"></span>`), WithBackground(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(doc) != "%PDF-wk" {
+		t.Errorf("doc = %q", doc)
+	}
+	args, _ := os.ReadFile(filepath.Join(filepath.Dir(path), "args"))
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	got := strings.Join(lines, " ")
+	for _, want := range []string{
+		"--page-size Letter", "--orientation Landscape", "--margin-top 10mm", "--no-background",
+		"--javascript-delay 500 - -",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("参数缺少 %q: %s", want, got)
+		}
+	}
+	if strings.Contains(got, "--header-html") {
+		t.Errorf("未设置页眉时不应传入 --header-html: %s", got)
+	}
+	footer, _ := os.ReadFile(filepath.Join(filepath.Dir(path), "args.footer"))
+	if !strings.Contains(string(footer), `onload="subst()"`) || !strings.Contains(string(footer), `<span class="pageNumber"></span>`) {
+		t.Errorf("页脚文件 %s", footer)
+	}
+	stdin, _ := os.ReadFile(filepath.Join(filepath.Dir(path), "args.stdin"))
+	if string(stdin) != "<p>报表</p>" {
+		t.Errorf("标准输入 %q", stdin)
+	}
+
+	if _, err := g.HTML(context.Background(), "fail", WithPaperSize(100, 150)); err == nil || !strings.Contains(err.Error(), "Exit with code 1") {
+		t.Errorf("失败时应返回标准错误输出: %v", err)
+	}
+	args, _ = os.ReadFile(filepath.Join(filepath.Dir(path), "args"))
+	if !strings.Contains(string(args), "--page-width\n100mm\n--page-height\n150mm") {
+		t.Errorf("自定义纸张参数 %s", args)
+	}
+}
+
+// devtools 模拟 Chrome DevTools：新建/关闭标签页与页面命令
+type devtools struct {
+	mu      sync.Mutex
+	origin  string
+	html    string
+	params  map[string]any
+	closed  []string
+	methods []string
+}
+
+func (d *devtools) server(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /json/new", func(w http.ResponseWriter, r *http.Request) {
+		// 远程实例返回其内部地址，驱动应替换为连接使用的地址
+		fmt.Fprint(w, `{"id":"T1","webSocketDebuggerUrl":"ws://10.0.0.9:9222/devtools/page/T1"}`)
+	})
+	mux.HandleFunc("GET /json/close/{id}", func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		d.closed = append(d.closed, r.PathValue("id"))
+		d.mu.Unlock()
+	})
+	mux.Handle("/devtools/page/T1", websocket.Handler(func(ws *websocket.Conn) {
+		d.mu.Lock()
+		d.origin = ws.Config().Origin.String()
+		d.mu.Unlock()
+		for {
+			var msg struct {
+				ID     int64          `json:"id"`
+				Method string         `json:"method"`
+				Params map[string]any `json:"params"`
+			}
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				return
+			}
+			d.mu.Lock()
+			d.methods = append(d.methods, msg.Method)
+			d.mu.Unlock()
+			// 命令响应之前的事件应被忽略
+			_ = websocket.JSON.Send(ws, map[string]any{"method": "Page.frameNavigated", "params": map[string]any{}})
+			var result any = map[string]any{}
+			switch msg.Method {
+			case "Page.getFrameTree":
+				result = map[string]any{"frameTree": map[string]any{"frame": map[string]any{"id": "F1"}}}
+			case "Page.setDocumentContent":
+				if msg.Params["frameId"] != "F1" {
+					t.Errorf("frameId = %v", msg.Params["frameId"])
+				}
+				d.mu.Lock()
+				d.html, _ = msg.Params["html"].(string)
+				d.mu.Unlock()
+				if d.html == "fail" {
+					_ = websocket.JSON.Send(ws, map[string]any{"id": msg.ID, "error": map[string]any{"code": -32000, "message": "Invalid HTML"}})
+					continue
+				}
+			case "Page.printToPDF":
+				d.mu.Lock()
+				d.params = msg.Params
+				d.mu.Unlock()
+				result = map[string]any{"data": base64.StdEncoding.EncodeToString([]byte("%PDF-chrome"))}
+			}
+			_ = websocket.JSON.Send(ws, map[string]any{"id": msg.ID, "result": result})
+		}
+	}))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestChrome 通过 DevTools 协议载入 HTML 并打印，结束后关闭标签页
+func TestChrome(t *testing.T) {
+	d := &devtools{}
+	srv := d.server(t)
+	g := New(NewChrome(WithChromeURL(srv.URL+"/")), fakeRenderer)
+
+	doc, err := g.HTML(context.Background(), "<p>x</p>", WithMargin(25.4, 0, 12.7, 0), WithFooterHTML("<span class=totalPages></span>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(doc) != "%PDF-chrome" {
+		t.Errorf("doc = %q", doc)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.html != "<p>x</p>" || d.origin != chromeOrigin {
+		t.Errorf("html = %q, origin = %q", d.html, d.origin)
+	}
+	if got := strings.Join(d.methods, ","); got != "Page.getFrameTree,Page.setDocumentContent,Runtime.evaluate,Page.printToPDF" {
+		t.Errorf("命令 %s", got)
+	}
+	p := d.params
+	if p["marginTop"] != 1.0 || p["marginBottom"] != 0.5 || p["displayHeaderFooter"] != true ||
+		p["headerTemplate"] != "<span></span>" || p["footerTemplate"] != "<span class=totalPages></span>" {
+		t.Errorf("printToPDF 参数 %v", p)
+	}
+	if len(d.closed) != 1 || d.closed[0] != "T1" {
+		t.Errorf("关闭的标签页 %v", d.closed)
+	}
+}
+
+// TestChromeError 命令错误原样返回
+func TestChromeError(t *testing.T) {
+	d := &devtools{}
+	srv := d.server(t)
+	c := NewChrome(WithChromeURL(srv.URL))
+	_, err := c.Convert(context.Background(), "fail", &Options{Width: 210, Height: 297})
+	if err == nil || !strings.Contains(err.Error(), "Page.setDocumentContent: Invalid HTML") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// wkhtmltopdfSubst 页眉页脚中按 class 填入页码等变量，wkhtmltopdf 以查询参数传入各变量
+const wkhtmltopdfSubst = `<script>function subst(){var q={};location.search.substring(1).split("&").forEach(function(p){var kv=p.split("=",2);q[kv[0]]=decodeURIComponent(kv[1]||"")});` +
+	`var m={pageNumber:q.page,totalPages:q.topage,date:q.date,title:q.title};for(var k in m){var es=document.getElementsByClassName(k);for(var i=0;i<es.length;i++)es[i].textContent=m[k]||""}}</script>`
+
+// Wkhtmltopdf 调用 wkhtmltopdf 命令转换，HTML 由标准输入传入，页眉页脚写入临时文件。
+// 出于安全考虑不开启 --enable-local-file-access，页面中引用的本地文件需通过 URL 访问
+type Wkhtmltopdf struct {
+	path string
+	args []string
+}
+
+// WkhtmltopdfOption wkhtmltopdf 驱动选项
+type WkhtmltopdfOption func(*Wkhtmltopdf)
+
+// WithWkhtmltopdfPath 可执行文件路径，默认在 PATH 中查找 wkhtmltopdf
+func WithWkhtmltopdfPath(path string) WkhtmltopdfOption {
+	return func(w *Wkhtmltopdf) {
+		if path != "" {
+			w.path = path
+		}
+	}
+}
+
+// WithWkhtmltopdfArgs 追加命令行参数，如 --javascript-delay 500
+func WithWkhtmltopdfArgs(args ...string) WkhtmltopdfOption {
+	return func(w *Wkhtmltopdf) { w.args = append(w.args, args...) }
+}
+
+// NewWkhtmltopdf 创建 wkhtmltopdf 驱动
+func NewWkhtmltopdf(opts ...WkhtmltopdfOption) *Wkhtmltopdf {
+	w := &Wkhtmltopdf{path: "wkhtmltopdf"}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Convert 实现 Driver
+func (w *Wkhtmltopdf) Convert(ctx context.Context, html string, opts *Options) ([]byte, error) {
+	var header, footer string
+	for _, p := range []struct {
+		html string
+		name *string
+	}{{opts.Header, &header}, {opts.Footer, &footer}} {
+		if p.html == "" {
+			continue
+		}
+		name, err := writeTemp(wrapPart(p.html))
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(name)
+		*p.name = name
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, w.path, w.arguments(opts, header, footer)...)
+	cmd.Stdin = strings.NewReader(html)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("wkhtmltopdf: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// arguments 命令行参数，header / footer 为页眉页脚文件路径
+func (w *Wkhtmltopdf) arguments(opts *Options, header, footer string) []string {
+	mm := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) + "mm" }
+	args := []string{"--quiet", "--encoding", "utf-8", "--print-media-type"}
+	if opts.PageSize != "" {
+		args = append(args, "--page-size", pageSizeName(opts.PageSize))
+	} else {
+		args = append(args, "--page-width", mm(opts.Width), "--page-height", mm(opts.Height))
+	}
+	if opts.Landscape {
+		args = append(args, "--orientation", "Landscape")
+	}
+	args = append(args,
+		"--margin-top", mm(opts.Margin.Top), "--margin-right", mm(opts.Margin.Right),
+		"--margin-bottom", mm(opts.Margin.Bottom), "--margin-left", mm(opts.Margin.Left),
+	)
+	if !opts.Background {
+		args = append(args, "--no-background")
+	}
+	if header != "" {
+		args = append(args, "--header-html", header)
+	}
+	if footer != "" {
+		args = append(args, "--footer-html", footer)
+	}
+	args = append(args, w.args...)
+	return append(args, "-", "-")
+}
+
+// pageSizeName wkhtmltopdf 的纸张名称（首字母大写，如 Letter）
+func pageSizeName(size string) string {
+	if len(size) == 2 {
+		return size
+	}
+	return size[:1] + strings.ToLower(size[1:])
+}
+
+// wrapPart 将页眉页脚片段包装为完整文档，加载后填入页码等变量
+func wrapPart(fragment string) string {
+	return `<!DOCTYPE html><html><head><meta charset="utf-8">` + wkhtmltopdfSubst +
+		`</head><body style="margin:0" onload="subst()">` + fragment + `</body></html>`
+}
+
+// writeTemp 写入临时 HTML 文件（wkhtmltopdf 按扩展名识别页眉页脚文件）
+func writeTemp(content string) (string, error) {
+	f, err := os.CreateTemp("", "pdf-*.html")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
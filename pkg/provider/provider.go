@@ -0,0 +1,97 @@
+// Package provider 应用级服务提供者：模块按 Register → Boot 两个阶段初始化，
+// 全部提供者的 Register 执行完毕后才依次执行 Boot，后启动的模块可依赖先注册的绑定：
+//
+//	type BillingProvider struct {
+//	    provider.Base
+//	    bus *eventbus.EventBus
+//	}
+//
+//	func (p *BillingProvider) Register(c *provider.Container) {
+//	    c.Provide(billing.NewService)
+//	    c.Populate(&p.bus)
+//	    template.AddFuncs(map[string]any{"money": billing.Format}) // 在模板编译前注册
+//	}
+//
+//	func (p *BillingProvider) Boot(lc fx.Lifecycle) error {
+//	    p.bus.On("order.paid", billing.OnPaid)
+//	    return nil
+//	}
+//
+// 应用的提供者列在 app/provider 的 Providers 中，由 bootstrap 按顺序执行。
+package provider
+
+import (
+	"fmt"
+
+	"go.uber.org/fx"
+)
+
+// Provider 服务提供者
+type Provider interface {
+	// Register 注册阶段：向容器添加构造函数、替换实现、注册模板函数等全局扩展。
+	// 在创建任何组件之前执行，每个进程只执行一次（命令行命令同样执行）
+	Register(c *Container)
+	// Boot 启动阶段：全部提供者注册完毕、框架全局组件（日志、模板引擎等）初始化之后按顺序执行，
+	// 可在 lc 上追加启动/停止钩子；返回错误时应用启动失败
+	Boot(lc fx.Lifecycle) error
+}
+
+// Base 空实现，嵌入后只需实现用到的阶段
+type Base struct{}
+
+// Register 实现 Provider
+func (Base) Register(*Container) {}
+
+// Boot 实现 Provider
+func (Base) Boot(fx.Lifecycle) error { return nil }
+
+// Container 注册阶段使用的容器，收集的选项加入应用依赖图
+type Container struct {
+	options []fx.Option
+}
+
+// Provide 添加构造函数，与 fx.Provide 相同
+func (c *Container) Provide(constructors ...any) {
+	c.options = append(c.options, fx.Provide(constructors...))
+}
+
+// Supply 添加已创建的值，与 fx.Supply 相同
+func (c *Container) Supply(values ...any) {
+	c.options = append(c.options, fx.Supply(values...))
+}
+
+// Decorate 包装已有的组件（如为服务加缓存层），与 fx.Decorate 相同
+func (c *Container) Decorate(decorators ...any) {
+	c.options = append(c.options, fx.Decorate(decorators...))
+}
+
+// Populate 在 Boot 之前将依赖写入 targets（指针），供 Boot 阶段使用，与 fx.Populate 相同
+func (c *Container) Populate(targets ...any) {
+	c.options = append(c.options, fx.Populate(targets...))
+}
+
+// Option 将注册阶段收集的选项合并为一个 fx.Option
+func (c *Container) Option() fx.Option {
+	return fx.Options(c.options...)
+}
+
+// Register 依次执行 providers 的注册阶段，返回收集的选项
+func Register(providers ...Provider) fx.Option {
+	c := &Container{}
+	for _, p := range providers {
+		p.Register(c)
+	}
+	return c.Option()
+}
+
+// Boot 返回依次执行 providers 启动阶段的 fx.Invoke，应放在注册阶段的选项与框架初始化之后
+func Boot(providers ...Provider) fx.Option {
+	return fx.Invoke(func(lc fx.Lifecycle) error {
+		for _, p := range providers {
+			if err := p.Boot(lc); err != nil {
+				return fmt.Errorf("服务提供者 %T 启动失败: %w", p, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/fx"
+)
+
+// greeter 测试用组件
+type greeter struct{ prefix string }
+
+// recorder 记录各阶段执行顺序的提供者
+type recorder struct {
+	name  string
+	trace *[]string
+	g     *greeter
+}
+
+func (r *recorder) Register(c *Container) {
+	*r.trace = append(*r.trace, "register:"+r.name)
+	if r.name == "a" {
+		c.Supply("hello")
+		c.Provide(func(prefix string) *greeter { return &greeter{prefix: prefix} })
+	}
+	c.Populate(&r.g)
+}
+
+func (r *recorder) Boot(fx.Lifecycle) error {
+	*r.trace = append(*r.trace, "boot:"+r.name+":"+r.g.prefix)
+	return nil
+}
+
+// TestPhases 全部提供者注册完毕后才依次启动，后注册的提供者可使用先注册的绑定
+func TestPhases(t *testing.T) {
+	var trace []string
+	a, b := &recorder{name: "a", trace: &trace}, &recorder{name: "b", trace: &trace}
+	app := fx.New(fx.NopLogger, Register(a, b), Boot(a, b))
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := "register:a register:b boot:a:hello boot:b:hello"
+	if got := strings.Join(trace, " "); got != want {
+		t.Errorf("trace = %s", got)
+	}
+}
+
+// failing 启动失败的提供者，只实现 Boot
+type failing struct{ Base }
+
+func (failing) Boot(fx.Lifecycle) error { return errors.New("缺少配置") }
+
+func TestBootError(t *testing.T) {
+	app := fx.New(fx.NopLogger, Register(Base{}, failing{}), Boot(Base{}, failing{}))
+	if err := app.Err(); err == nil || !strings.Contains(err.Error(), "provider.failing") || !strings.Contains(err.Error(), "缺少配置") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package qrcode
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize 默认缓存的生成结果数量
+const DefaultCacheSize = 512
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	key   string
+	value any
+}
+
+// lru 生成结果的 LRU 缓存，生成出错时不缓存
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+var cache = &lru{size: DefaultCacheSize, ll: list.New(), items: make(map[string]*list.Element)}
+
+// SetCacheSize 设置缓存的生成结果数量，size <= 0 时关闭缓存
+func SetCacheSize(size int) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.size = size
+	cache.trim()
+}
+
+// cached 返回缓存的结果，未命中时调用 gen 生成并缓存
+func cached(key string, gen func() (any, error)) (any, error) {
+	cache.mu.Lock()
+	if el, ok := cache.items[key]; ok {
+		cache.ll.MoveToFront(el)
+		v := el.Value.(*cacheEntry).value
+		cache.mu.Unlock()
+		return v, nil
+	}
+	cache.mu.Unlock()
+
+	v, err := gen()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if _, ok := cache.items[key]; !ok && cache.size > 0 {
+		cache.items[key] = cache.ll.PushFront(&cacheEntry{key: key, value: v})
+		cache.trim()
+	}
+	return v, nil
+}
+
+// trim 淘汰超出容量的条目，调用方持有锁
+func (c *lru) trim() {
+	for c.ll.Len() > c.size && c.ll.Len() > 0 {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,387 @@
+// Package qrcode 生成二维码与条形码（SVG / PNG / data URI），用于支付链接、两步验证绑定、移动端深度链接等页面。
+// 相同参数的生成结果保存在 LRU 缓存中，模板中重复渲染同一个码不会重复编码：
+//
+//	svg, err := qrcode.SVG("https://example.com/pay/A100", 200)
+//	png, err := qrcode.PNG(otpURL, 256, qrcode.WithLevel(qrcode.LevelH))
+//	bar, err := qrcode.BarcodeSVG(qrcode.Code128, "SKU-0001", 300, 80)
+//
+// 模板中使用 {{ qrcode .URL 200 }}（内联 SVG）或 <img src="{{ qrcodeURI .URL 200 }}">。
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/code39"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+// MaxSize 输出尺寸（像素）上限
+const MaxSize = 4096
+
+// Level 二维码纠错级别，级别越高可被遮挡的比例越大，码也越密
+type Level string
+
+// 纠错级别
+const (
+	LevelL Level = "L" // 约 7%
+	LevelM Level = "M" // 约 15%（默认）
+	LevelQ Level = "Q" // 约 25%
+	LevelH Level = "H" // 约 30%，中间叠加 Logo 时使用
+)
+
+// Format 条形码格式
+type Format string
+
+// 条形码格式
+const (
+	Code128 Format = "code128" // 任意 ASCII，物流、SKU 常用
+	Code39  Format = "code39"  // 大写字母、数字与 -.$/+% 空格
+	EAN     Format = "ean"     // 7/8 位为 EAN-8，12/13 位为 EAN-13（不含校验位时自动补全）
+)
+
+var (
+	// ErrInvalidSize 尺寸不在 (0, MaxSize] 内或小于码的模块数
+	ErrInvalidSize = errors.New("无效的尺寸")
+	// ErrUnknownFormat 不支持的条形码格式
+	ErrUnknownFormat = errors.New("不支持的条形码格式")
+)
+
+// options 生成选项
+type options struct {
+	level      Level
+	foreground string
+	background string
+	quietZone  int // -1 表示使用格式的默认值
+}
+
+// Option 生成选项
+type Option func(*options)
+
+// WithLevel 二维码纠错级别（默认 LevelM），条形码忽略
+func WithLevel(l Level) Option {
+	return func(o *options) { o.level = l }
+}
+
+// WithColors 前景色与背景色（#RRGGBB 或 #RGB，默认黑白），背景色为空时 SVG 背景透明
+func WithColors(foreground, background string) Option {
+	return func(o *options) {
+		o.foreground = foreground
+		o.background = background
+	}
+}
+
+// WithQuietZone 四周空白的模块数（二维码默认 4，条形码默认 10）
+func WithQuietZone(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.quietZone = n
+		}
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{level: LevelM, foreground: "#000000", background: "#ffffff", quietZone: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// key 缓存键的选项部分
+func (o options) key() string {
+	return string(o.level) + "|" + o.foreground + "|" + o.background + "|" + strconv.Itoa(o.quietZone)
+}
+
+// matrix 编码结果：dark[y][x] 为 true 表示深色模块，1D 条形码只有一行
+type matrix struct {
+	dark  [][]bool
+	quiet int
+}
+
+func (m matrix) width() int  { return len(m.dark[0]) + 2*m.quiet }
+func (m matrix) height() int { return len(m.dark) + 2*m.quiet }
+
+// encodeQR 编码二维码
+func encodeQR(content string, o options) (matrix, error) {
+	var level qr.ErrorCorrectionLevel
+	switch o.level {
+	case LevelL:
+		level = qr.L
+	case LevelM, "":
+		level = qr.M
+	case LevelQ:
+		level = qr.Q
+	case LevelH:
+		level = qr.H
+	default:
+		return matrix{}, fmt.Errorf("未知的纠错级别: %s", o.level)
+	}
+	code, err := qr.Encode(content, level, qr.Auto)
+	if err != nil {
+		return matrix{}, fmt.Errorf("生成二维码失败: %w", err)
+	}
+	quiet := o.quietZone
+	if quiet < 0 {
+		quiet = 4
+	}
+	return toMatrix(code, quiet), nil
+}
+
+// encodeBarcode 编码条形码，1D 码不在上下留白
+func encodeBarcode(format Format, content string, o options) (matrix, error) {
+	var (
+		code barcode.Barcode
+		err  error
+	)
+	switch format {
+	case Code128:
+		code, err = code128.Encode(content)
+	case Code39:
+		code, err = code39.Encode(content, false, true)
+	case EAN:
+		code, err = ean.Encode(content)
+	default:
+		return matrix{}, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return matrix{}, fmt.Errorf("生成条形码失败: %w", err)
+	}
+	quiet := o.quietZone
+	if quiet < 0 {
+		quiet = 10
+	}
+	m := toMatrix(code, 0)
+	// 左右留白，上下不留
+	for i, row := range m.dark {
+		padded := make([]bool, len(row)+2*quiet)
+		copy(padded[quiet:], row)
+		m.dark[i] = padded
+	}
+	return m, nil
+}
+
+// toMatrix 读取编码结果的模块
+func toMatrix(img image.Image, quiet int) matrix {
+	b := img.Bounds()
+	dark := make([][]bool, b.Dy())
+	for y := range dark {
+		dark[y] = make([]bool, b.Dx())
+		for x := range dark[y] {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			dark[y][x] = r+g+bl < 3*0x8000
+		}
+	}
+	return matrix{dark: dark, quiet: quiet}
+}
+
+// svg 输出 SVG，每行连续的深色模块合并为一个矩形路径；1D 条形码按 height 拉伸
+func (m matrix) svg(width, height int, o options) string {
+	var path strings.Builder
+	for y, row := range m.dark {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", x+m.quiet, y+m.quiet, run, run)
+			x += run
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" preserveAspectRatio="none" shape-rendering="crispEdges">`,
+		width, height, m.width(), m.height())
+	if o.background != "" {
+		fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, o.background)
+	}
+	fmt.Fprintf(&b, `<path fill="%s" d="%s"/></svg>`, o.foreground, path.String())
+	return b.String()
+}
+
+// png 输出 PNG，每个模块按整数像素缩放后居中
+func (m matrix) png(width, height int, o options) ([]byte, error) {
+	fg, err := parseColor(o.foreground)
+	if err != nil {
+		return nil, err
+	}
+	bg := color.RGBA{}
+	if o.background != "" {
+		if bg, err = parseColor(o.background); err != nil {
+			return nil, err
+		}
+	}
+	sx, sy := width/m.width(), height/m.height()
+	if sx == 0 || sy == 0 {
+		return nil, fmt.Errorf("%w: %dx%d 小于码的模块数 %dx%d", ErrInvalidSize, width, height, m.width(), m.height())
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{bg, fg})
+	ox, oy := (width-sx*m.width())/2+m.quiet*sx, (height-sy*m.height())/2+m.quiet*sy
+	for y, row := range m.dark {
+		for x, d := range row {
+			if !d {
+				continue
+			}
+			for py := oy + y*sy; py < oy+(y+1)*sy; py++ {
+				for px := ox + x*sx; px < ox+(x+1)*sx; px++ {
+					img.SetColorIndex(px, py, 1)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parseColor 解析 #RRGGBB / #RGB
+func parseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return color.RGBA{}, fmt.Errorf("无效的颜色: %s", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// checkSize 校验输出尺寸
+func checkSize(sizes ...int) error {
+	for _, s := range sizes {
+		if s <= 0 || s > MaxSize {
+			return fmt.Errorf("%w: %d", ErrInvalidSize, s)
+		}
+	}
+	return nil
+}
+
+// validColors SVG 直接输出颜色值，需先校验
+func validColors(o options) error {
+	if _, err := parseColor(o.foreground); err != nil {
+		return err
+	}
+	if o.background != "" {
+		if _, err := parseColor(o.background); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SVG 生成 size×size 的二维码 SVG
+func SVG(content string, size int, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	v, err := cached("svg|"+o.key()+"|"+strconv.Itoa(size)+"|"+content, func() (any, error) {
+		if err := checkSize(size); err != nil {
+			return nil, err
+		}
+		if err := validColors(o); err != nil {
+			return nil, err
+		}
+		m, err := encodeQR(content, o)
+		if err != nil {
+			return nil, err
+		}
+		return m.svg(size, size, o), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// PNG 生成 size×size 的二维码 PNG，size 至少为模块数（含空白）
+func PNG(content string, size int, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	v, err := cached("png|"+o.key()+"|"+strconv.Itoa(size)+"|"+content, func() (any, error) {
+		if err := checkSize(size); err != nil {
+			return nil, err
+		}
+		m, err := encodeQR(content, o)
+		if err != nil {
+			return nil, err
+		}
+		return m.png(size, size, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// DataURI 生成二维码 SVG 的 data URI，可直接用作 <img src>
+func DataURI(content string, size int, opts ...Option) (string, error) {
+	svg, err := SVG(content, size, opts...)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)), nil
+}
+
+// BarcodeSVG 生成 width×height 的条形码 SVG
+func BarcodeSVG(format Format, content string, width, height int, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	key := fmt.Sprintf("barsvg|%s|%s|%d|%d|%s", format, o.key(), width, height, content)
+	v, err := cached(key, func() (any, error) {
+		if err := checkSize(width, height); err != nil {
+			return nil, err
+		}
+		if err := validColors(o); err != nil {
+			return nil, err
+		}
+		m, err := encodeBarcode(format, content, o)
+		if err != nil {
+			return nil, err
+		}
+		return m.svg(width, height, o), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// BarcodePNG 生成 width×height 的条形码 PNG，width 至少为模块数（含空白）
+func BarcodePNG(format Format, content string, width, height int, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	key := fmt.Sprintf("barpng|%s|%s|%d|%d|%s", format, o.key(), width, height, content)
+	v, err := cached(key, func() (any, error) {
+		if err := checkSize(width, height); err != nil {
+			return nil, err
+		}
+		m, err := encodeBarcode(format, content, o)
+		if err != nil {
+			return nil, err
+		}
+		return m.png(width, height, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// BarcodeDataURI 生成条形码 SVG 的 data URI
+func BarcodeDataURI(format Format, content string, width, height int, opts ...Option) (string, error) {
+	svg, err := BarcodeSVG(format, content, width, height, opts...)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)), nil
+}
@@ -0,0 +1,138 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	svg, err := SVG("https://example.com/pay/A100", 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 该内容以 M 级别编码为版本 3（29×29），加两侧各 4 模块空白为 37
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" viewBox="0 0 37 37"`) {
+		t.Errorf("svg = %.120s", svg)
+	}
+	// 左上角定位图案第一行为 7 个连续的深色模块
+	if !strings.Contains(svg, `d="M4 4h7v1h-7z`) {
+		t.Errorf("缺少定位图案: %.300s", svg)
+	}
+	// H 级别编码为版本 4（33×33），不留空白
+	high, _ := SVG("https://example.com/pay/A100", 200, WithLevel(LevelH), WithQuietZone(0))
+	if !strings.Contains(high, `viewBox="0 0 33 33"`) || !strings.Contains(high, `d="M0 0h7v1h-7z`) {
+		t.Errorf("H 级别应生成更大的码: %.120s", high)
+	}
+	if _, err := SVG("x", 0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("size 0 err = %v", err)
+	}
+	if _, err := SVG("x", 100, WithColors("red", "")); err == nil {
+		t.Error("无效的颜色应返回错误")
+	}
+	transparent, _ := SVG("x", 100, WithColors("#333", ""))
+	if strings.Contains(transparent, "<rect") || !strings.Contains(transparent, `fill="#333"`) {
+		t.Errorf("透明背景 svg = %.200s", transparent)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	data, err := PNG("otpauth://totp/app:alice?secret=JBSWY3DPEHPK3PXP", 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 256 || b.Dy() != 256 {
+		t.Fatalf("bounds = %v", b)
+	}
+	dark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r < 0x8000
+	}
+	if dark(0, 0) {
+		t.Error("空白区应为背景色")
+	}
+	// 定位图案的左上角位于空白之后
+	found := false
+	for i := 0; i < 64 && !found; i++ {
+		found = dark(i, i)
+	}
+	if !found {
+		t.Error("未找到定位图案")
+	}
+	if _, err := PNG("otpauth://totp/app:alice?secret=JBSWY3DPEHPK3PXP", 20); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("尺寸小于模块数 err = %v", err)
+	}
+}
+
+func TestBarcode(t *testing.T) {
+	svg, err := BarcodeSVG(Code128, "SKU-0001", 300, 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(svg, `width="300" height="80"`) || !regexp.MustCompile(`viewBox="0 0 \d+ 1"`).MatchString(svg) ||
+		!strings.Contains(svg, `M10 0h2v1h-2z`) {
+		t.Errorf("svg = %.200s", svg)
+	}
+	// EAN-13 不含校验位时自动补全
+	if _, err := BarcodeSVG(EAN, "590123412345", 200, 60); err != nil {
+		t.Errorf("EAN-13: %v", err)
+	}
+	if _, err := BarcodeSVG(EAN, "abc", 200, 60); err == nil {
+		t.Error("无效的 EAN 应返回错误")
+	}
+	if _, err := BarcodeSVG("upc", "1", 200, 60); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("未知格式 err = %v", err)
+	}
+	data, err := BarcodePNG(Code39, "ABC-123", 400, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img, err := png.Decode(bytes.NewReader(data)); err != nil || img.Bounds().Dx() != 400 || img.Bounds().Dy() != 50 {
+		t.Errorf("png = %v, %v", img.Bounds(), err)
+	}
+}
+
+func TestDataURI(t *testing.T) {
+	uri, err := DataURI("hello", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, "data:image/svg+xml;base64,"))
+	svg, _ := SVG("hello", 100)
+	if err != nil || string(raw) != svg {
+		t.Errorf("data URI 内容与 SVG 不一致: %v", err)
+	}
+	if uri, _ := BarcodeDataURI(Code128, "A", 100, 40); !strings.HasPrefix(uri, "data:image/svg+xml;base64,") {
+		t.Errorf("barcode uri = %s", uri)
+	}
+}
+
+func TestCache(t *testing.T) {
+	SetCacheSize(2)
+	defer SetCacheSize(DefaultCacheSize)
+	a, _ := SVG("a", 100)
+	_, _ = SVG("b", 100)
+	if v, _ := SVG("a", 100); v != a || cache.ll.Len() != 2 {
+		t.Errorf("缓存条目 = %d", cache.ll.Len())
+	}
+	_, _ = SVG("c", 100)
+	if _, ok := cache.items["svg|"+newOptions(nil).key()+"|100|b"]; ok {
+		t.Error("最久未使用的条目应被淘汰")
+	}
+	_, _ = SVG("x", -1)
+	if cache.ll.Len() != 2 {
+		t.Error("生成出错时不应缓存")
+	}
+	SetCacheSize(0)
+	if cache.ll.Len() != 0 {
+		t.Error("关闭缓存后应清空")
+	}
+}
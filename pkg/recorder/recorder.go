@@ -0,0 +1,359 @@
+// Package recorder 请求录制与重放：Middleware 将完整请求（方法、URL、请求头、请求体）与响应状态写为 HAR 文件，
+// request:replay 命令将其重放到本地实例，用于复现依赖复杂请求体的线上问题：
+//
+//	r.Use(recorder.Middleware("storage/records", recorder.WithPaths("/api/orders"), recorder.WithMinStatus(500)))
+//
+//	app request:replay --target http://127.0.0.1:8080 -H "Authorization: Bearer dev-token" storage/records
+//
+// 每个文件是只含一个条目的 HAR 1.2 日志，也可导入浏览器开发者工具查看。请求体按原样写入，
+// 生产环境只应在排查问题时按路径临时开启，并用 WithRedactHeaders 脱敏认证头。
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// Ext HAR 文件扩展名
+const Ext = ".har"
+
+// HAR HAR 1.2 文件（http://www.softwareishard.com/blog/har-12-spec/）中录制用到的部分
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log HAR 日志
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator 生成 HAR 的程序
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry 一次请求与响应
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"` // 耗时（毫秒）
+	Request         Request   `json:"request"`
+	Response        Response  `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         Timings   `json:"timings"`
+	Comment         string    `json:"comment,omitempty"` // 请求 ID 等说明
+}
+
+// NameValue 请求头、查询参数等键值对
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Request 录制的请求
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// PostData 请求体
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"` // 非 UTF-8 内容为 base64
+}
+
+// Response 录制的响应（不含响应体）
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// Content 响应内容信息
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// Timings 各阶段耗时，录制时只有 wait（处理耗时）
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// recordConfig 录制中间件配置
+type recordConfig struct {
+	paths     []string
+	minStatus int
+	redact    map[string]bool
+}
+
+// Option 录制中间件配置选项
+type Option func(*recordConfig)
+
+// WithPaths 只录制这些路径前缀下的请求（默认全部）
+func WithPaths(prefixes ...string) Option {
+	return func(c *recordConfig) { c.paths = prefixes }
+}
+
+// WithMinStatus 只录制响应状态码不小于 status 的请求，如 500 只录制服务端错误（默认全部）
+func WithMinStatus(status int) Option {
+	return func(c *recordConfig) { c.minStatus = status }
+}
+
+// WithRedactHeaders 写入文件前将这些请求头的值替换为 config.Mask（默认 Authorization、Cookie、Proxy-Authorization），
+// 重放时不发送被脱敏的请求头，可用 -H 重新指定
+func WithRedactHeaders(names ...string) Option {
+	return func(c *recordConfig) {
+		c.redact = make(map[string]bool, len(names))
+		for _, name := range names {
+			c.redact[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// FromConfig 按 record.* 配置创建录制中间件
+func FromConfig(cfg *config.RecordConfig) gin.HandlerFunc {
+	return Middleware(cfg.Dir,
+		WithPaths(cfg.Paths...),
+		WithMinStatus(cfg.MinStatus),
+		WithRedactHeaders(cfg.RedactHeaders...),
+	)
+}
+
+// Middleware 返回将请求录制到 dir 的中间件。应挂在 Recovery 与 ErrorHandler 之前，以记录最终的响应状态；
+// 请求体经 request.RawBody 读取，超过 server.max_body_size 时不录制请求体
+func Middleware(dir string, opts ...Option) gin.HandlerFunc {
+	cfg := &recordConfig{}
+	WithRedactHeaders("Authorization", "Cookie", "Proxy-Authorization")(cfg)
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.match(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		body, bodyErr := request.RawBody(c)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < cfg.minStatus {
+			return
+		}
+		entry := cfg.entry(c, start, body, bodyErr)
+		if err := write(dir, start, request.RequestID(c), entry); err != nil && logger.SugarLogger != nil {
+			logger.SugarLogger.Warnw("录制请求失败", "path", c.Request.URL.Path, "error", err)
+		}
+	}
+}
+
+// match 路径是否需要录制
+func (cfg *recordConfig) match(path string) bool {
+	if len(cfg.paths) == 0 {
+		return true
+	}
+	for _, prefix := range cfg.paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// entry 生成 HAR 条目
+func (cfg *recordConfig) entry(c *gin.Context, start time.Time, body []byte, bodyErr error) Entry {
+	r := c.Request
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	elapsed := float64(time.Since(start).Microseconds()) / 1000
+
+	req := Request{
+		Method:      r.Method,
+		URL:         scheme + "://" + r.Host + r.URL.RequestURI(),
+		HTTPVersion: r.Proto,
+		Cookies:     []NameValue{},
+		Headers:     cfg.headers(r.Header),
+		QueryString: pairs(r.URL.Query()),
+		HeadersSize: -1,
+		BodySize:    r.ContentLength,
+	}
+	switch {
+	case bodyErr != nil:
+		req.BodySize = -1
+	case len(body) > 0:
+		req.PostData = &PostData{MimeType: r.Header.Get("Content-Type"), Text: string(body)}
+		if !utf8.Valid(body) {
+			req.PostData.Text, req.PostData.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+		}
+		req.BodySize = int64(len(body))
+	}
+
+	e := Entry{
+		StartedDateTime: start,
+		Time:            elapsed,
+		Request:         req,
+		Response: Response{
+			Status:      c.Writer.Status(),
+			StatusText:  http.StatusText(c.Writer.Status()),
+			HTTPVersion: r.Proto,
+			Cookies:     []NameValue{},
+			Headers:     pairs(c.Writer.Header()),
+			Content:     Content{Size: max(c.Writer.Size(), 0), MimeType: c.Writer.Header().Get("Content-Type")},
+			HeadersSize: -1,
+			BodySize:    c.Writer.Size(),
+		},
+		Timings: Timings{Wait: elapsed},
+	}
+	if id := request.RequestID(c); id != "" {
+		e.Comment = "request_id=" + id
+	}
+	if bodyErr != nil {
+		e.Comment = strings.TrimSpace(e.Comment + " 请求体未录制: " + bodyErr.Error())
+	}
+	return e
+}
+
+// headers 转换请求头，脱敏 redact 中的请求头
+func (cfg *recordConfig) headers(h http.Header) []NameValue {
+	list := pairs(h)
+	for i := range list {
+		if cfg.redact[list[i].Name] {
+			list[i].Value = config.Mask
+		}
+	}
+	return list
+}
+
+// pairs 将 map[string][]string 转换为按名称排序的键值对
+func pairs(m map[string][]string) []NameValue {
+	list := []NameValue{}
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		for _, v := range m[name] {
+			list = append(list, NameValue{Name: name, Value: v})
+		}
+	}
+	return list
+}
+
+// write 将条目写为 dir/<时间>-<请求 ID>.har
+func write(dir string, start time.Time, id string, e Entry) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if id == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	data, err := json.MarshalIndent(HAR{Log: Log{
+		Version: "1.2",
+		Creator: Creator{Name: "go-framework", Version: "1.0"},
+		Entries: []Entry{e},
+	}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := start.Format("20060102-150405.000") + "-" + id + Ext
+	return os.WriteFile(filepath.Join(dir, name), data, 0o600)
+}
+
+// Load 读取 HAR 文件
+func Load(path string) (*HAR, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var h HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return &h, nil
+}
+
+// hopHeaders 重放时不转发的请求头（由客户端按实际连接重新生成）
+var hopHeaders = map[string]bool{
+	"Host": true, "Content-Length": true, "Connection": true, "Keep-Alive": true,
+	"Transfer-Encoding": true, "Upgrade": true, "Te": true, "Trailer": true, "Accept-Encoding": true,
+}
+
+// Replay 将录制的请求发送到 target（如 http://127.0.0.1:8080），保留路径、查询参数、请求头与请求体；
+// 被脱敏的请求头不发送，header 中的请求头覆盖录制的同名请求头
+func Replay(ctx context.Context, client *http.Client, target string, e *Entry, header http.Header) (*http.Response, error) {
+	base, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("无效的目标地址 %s: %w", target, err)
+	}
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的录制地址 %s: %w", e.Request.URL, err)
+	}
+	u.Scheme, u.Host = base.Scheme, base.Host
+
+	var body io.Reader
+	if pd := e.Request.PostData; pd != nil {
+		data := []byte(pd.Text)
+		if pd.Encoding == "base64" {
+			if data, err = base64.StdEncoding.DecodeString(pd.Text); err != nil {
+				return nil, fmt.Errorf("解码请求体失败: %w", err)
+			}
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, e.Request.Method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range e.Request.Headers {
+		if hopHeaders[http.CanonicalHeaderKey(h.Name)] || h.Value == config.Mask {
+			continue
+		}
+		req.Header.Add(h.Name, h.Value)
+	}
+	for name, values := range header {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	return client.Do(req)
+}
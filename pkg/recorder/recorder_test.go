@@ -0,0 +1,80 @@
+package recorder
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// TestRecordReplay 录制的请求可完整重放：请求体、查询参数与请求头保留，脱敏的请求头不发送
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(dir, WithPaths("/api/"), WithMinStatus(500)))
+	r.POST("/api/orders", func(c *gin.Context) {
+		body, _ := request.RawBody(c)
+		c.Set(request.KeyRequestID, "req-1")
+		c.String(http.StatusInternalServerError, "failed: %s", body)
+	})
+	r.POST("/api/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/other", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	for _, path := range []string{"/api/orders?src=app", "/api/ok", "/other"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"sku":"A1","qty":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("X-Client", "ios")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if len(files) != 1 || !strings.HasSuffix(files[0], "-req-1"+Ext) {
+		t.Fatalf("只应录制 /api/ 下的 5xx 请求，得到 %v", files)
+	}
+	h, err := Load(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &h.Log.Entries[0]
+	if e.Request.PostData == nil || e.Request.PostData.Text != `{"sku":"A1","qty":2}` || e.Response.Status != http.StatusInternalServerError {
+		t.Fatalf("entry = %+v", e)
+	}
+	for _, hv := range e.Request.Headers {
+		if hv.Name == "Authorization" && hv.Value != config.Mask {
+			t.Errorf("Authorization 应脱敏，得到 %s", hv.Value)
+		}
+	}
+
+	var got *http.Request
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		got, gotBody = req, string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	resp, err := Replay(context.Background(), srv.Client(), srv.URL, e, http.Header{"X-Client": {"replay"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || got.Method != http.MethodPost || got.URL.RequestURI() != "/api/orders?src=app" {
+		t.Errorf("replay = %d %s %s", resp.StatusCode, got.Method, got.URL)
+	}
+	if gotBody != `{"sku":"A1","qty":2}` || got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("body = %s header = %v", gotBody, got.Header)
+	}
+	if got.Header.Get("Authorization") != "" || got.Header.Get("X-Client") != "replay" {
+		t.Errorf("脱敏的请求头不应发送，-H 应覆盖录制值: %v", got.Header)
+	}
+}
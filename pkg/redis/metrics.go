@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Stats 客户端统计
+type Stats struct {
+	Commands   int64         `json:"commands"`    // 执行的命令数（管道中的每条命令各计一次）
+	Errors     int64         `json:"errors"`      // 失败的命令数，不含键不存在（Nil）
+	Dials      int64         `json:"dials"`       // 建立的连接数
+	DialErrors int64         `json:"dial_errors"` // 连接失败数
+	Latency    time.Duration `json:"latency"`     // 命令累计耗时
+	Hits       uint32        `json:"hits"`        // 从连接池取到空闲连接的次数
+	Misses     uint32        `json:"misses"`      // 连接池无空闲连接的次数
+	Timeouts   uint32        `json:"timeouts"`    // 等待连接超时的次数
+	TotalConns uint32        `json:"total_conns"` // 当前连接数
+	IdleConns  uint32        `json:"idle_conns"`  // 当前空闲连接数
+	StaleConns uint32        `json:"stale_conns"` // 被移除的失效连接数
+}
+
+// metrics 全部客户端共用的计数
+var metrics struct {
+	commands   atomic.Int64
+	errors     atomic.Int64
+	dials      atomic.Int64
+	dialErrors atomic.Int64
+	latency    atomic.Int64
+}
+
+// ReadStats 返回命令计数与 client 的连接池状态，client 为 nil 时只含命令计数
+func ReadStats(client Client) Stats {
+	s := Stats{
+		Commands:   metrics.commands.Load(),
+		Errors:     metrics.errors.Load(),
+		Dials:      metrics.dials.Load(),
+		DialErrors: metrics.dialErrors.Load(),
+		Latency:    time.Duration(metrics.latency.Load()),
+	}
+	if client != nil {
+		p := client.PoolStats()
+		s.Hits, s.Misses, s.Timeouts = p.Hits, p.Misses, p.Timeouts
+		s.TotalConns, s.IdleConns, s.StaleConns = p.TotalConns, p.IdleConns, p.StaleConns
+	}
+	return s
+}
+
+// metricsHook 统计命令与连接的钩子
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next goredis.DialHook) goredis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		metrics.dials.Add(1)
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			metrics.dialErrors.Add(1)
+		}
+		return conn, err
+	}
+}
+
+func (metricsHook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		record(1, time.Since(start), err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		errs := make([]error, len(cmds))
+		for i, cmd := range cmds {
+			errs[i] = cmd.Err()
+		}
+		record(len(cmds), time.Since(start), errs...)
+		return err
+	}
+}
+
+// record 记录命令数、耗时与失败数
+func record(n int, d time.Duration, errs ...error) {
+	metrics.commands.Add(int64(n))
+	metrics.latency.Add(int64(d))
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			metrics.errors.Add(1)
+		}
+	}
+}
+
+func init() {
+	expvar.Publish("redis", expvar.Func(func() any { return ReadStats(Default()) }))
+}
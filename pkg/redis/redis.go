@@ -0,0 +1,129 @@
+// Package redis 按 redis.* 配置创建进程内共享的 go-redis 客户端，支持单机、哨兵与集群模式，
+// 需要 Redis 的组件（如 redis 驱动的分布式锁）共用同一个连接池。
+//
+//	client := redis.Default() // 由 bootstrap 在有组件依赖时创建，应用停止时关闭
+//	err := client.Set(ctx, "k", "v", time.Minute).Err()
+//
+// 客户端命令数、错误数与连接池状态发布到 /debug/vars 中的 redis（见 ReadStats）。
+package redis
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 部署模式
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+// Nil 键不存在时命令返回的错误，与 go-redis 的 redis.Nil 相同
+const Nil = goredis.Nil
+
+// Client 共享客户端，单机、哨兵与集群模式下均实现该接口
+type Client = goredis.UniversalClient
+
+// ErrUnknownMode 未知的部署模式
+var ErrUnknownMode = errors.New("redis: 未知的部署模式")
+
+// New 按配置创建客户端并挂载统计钩子。创建时不建立连接，首次执行命令时才连接：
+//   - standalone：连接 host:port
+//   - sentinel：经 addrs 中的哨兵发现 master_name 的主节点，故障转移后自动切换
+//   - cluster：以 addrs 中的节点发现集群拓扑，集群模式不支持 db
+func New(cfg *config.RedisConfig) (Client, error) {
+	addrs := cfg.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))}
+	}
+	var tlsConfig *tls.Config
+	if cfg.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	seconds := func(n int) time.Duration { return time.Duration(n) * time.Second }
+
+	var client Client
+	switch cfg.Mode {
+	case "", ModeStandalone:
+		client = goredis.NewClient(&goredis.Options{
+			Addr:         addrs[0],
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  seconds(cfg.DialTimeout),
+			ReadTimeout:  seconds(cfg.ReadTimeout),
+			WriteTimeout: seconds(cfg.WriteTimeout),
+			TLSConfig:    tlsConfig,
+		})
+	case ModeSentinel:
+		if cfg.MasterName == "" || len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("redis: 哨兵模式需要配置 master_name 与 addrs")
+		}
+		client = goredis.NewFailoverClient(&goredis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+			DialTimeout:      seconds(cfg.DialTimeout),
+			ReadTimeout:      seconds(cfg.ReadTimeout),
+			WriteTimeout:     seconds(cfg.WriteTimeout),
+			TLSConfig:        tlsConfig,
+		})
+	case ModeCluster:
+		if len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("redis: 集群模式需要配置 addrs")
+		}
+		client = goredis.NewClusterClient(&goredis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  seconds(cfg.DialTimeout),
+			ReadTimeout:  seconds(cfg.ReadTimeout),
+			WriteTimeout: seconds(cfg.WriteTimeout),
+			TLSConfig:    tlsConfig,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %q（可选 standalone、sentinel、cluster）", ErrUnknownMode, cfg.Mode)
+	}
+	client.AddHook(metricsHook{})
+	return client, nil
+}
+
+// holder 包装接口值以存入 atomic.Pointer
+type holder struct{ client Client }
+
+var defaultClient atomic.Pointer[holder]
+
+// SetDefault 设置全局客户端，传入 nil 时清除
+func SetDefault(c Client) {
+	if c == nil {
+		defaultClient.Store(nil)
+		return
+	}
+	defaultClient.Store(&holder{client: c})
+}
+
+// Default 返回全局客户端，未设置时为 nil
+func Default() Client {
+	if h := defaultClient.Load(); h != nil {
+		return h.client
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// fakeServer 只实现测试所需命令的 RESP2 服务端：PING 返回 PONG，GET 返回空值，FAIL 返回错误，其余返回 OK
+func fakeServer(t *testing.T) *config.RedisConfig {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return &config.RedisConfig{Host: "127.0.0.1", Port: addr.Port}
+}
+
+func serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			_, _ = r.ReadString('\n') // $len
+			arg, _ := r.ReadString('\n')
+			args[i] = strings.TrimSpace(arg)
+		}
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "HELLO":
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		case "PING":
+			reply = "+PONG\r\n"
+		case "GET":
+			reply = "$-1\r\n"
+		case "FAIL":
+			reply = "-ERR failed\r\n"
+		default:
+			reply = "+OK\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		cfg     config.RedisConfig
+		cluster bool
+	}{
+		{cfg: config.RedisConfig{Host: "127.0.0.1", Port: 6379}},
+		{cfg: config.RedisConfig{Mode: ModeSentinel, MasterName: "mymaster", Addrs: []string{"127.0.0.1:26379"}}},
+		{cfg: config.RedisConfig{Mode: ModeCluster, Addrs: []string{"127.0.0.1:7000", "127.0.0.1:7001"}}, cluster: true},
+	}
+	for _, tc := range cases {
+		client, err := New(&tc.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.cfg.Mode, err)
+		}
+		if _, ok := client.(*goredis.ClusterClient); ok != tc.cluster {
+			t.Errorf("%s: client = %T", tc.cfg.Mode, client)
+		}
+		_ = client.Close()
+	}
+
+	if _, err := New(&config.RedisConfig{Mode: "replica"}); !errors.Is(err, ErrUnknownMode) {
+		t.Errorf("未知模式 err = %v", err)
+	}
+	if _, err := New(&config.RedisConfig{Mode: ModeSentinel, Addrs: []string{"127.0.0.1:26379"}}); err == nil {
+		t.Error("哨兵模式缺少 master_name 应返回错误")
+	}
+	if _, err := New(&config.RedisConfig{Mode: ModeCluster}); err == nil {
+		t.Error("集群模式缺少 addrs 应返回错误")
+	}
+}
+
+func TestStats(t *testing.T) {
+	client, err := New(fakeServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	SetDefault(client)
+	defer SetDefault(nil)
+
+	before := ReadStats(Default())
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get(ctx, "missing").Err(); !errors.Is(err, Nil) {
+		t.Errorf("GET err = %v", err)
+	}
+	if err := client.Do(ctx, "FAIL").Err(); err == nil {
+		t.Error("FAIL 应返回错误")
+	}
+	_, _ = client.Pipelined(ctx, func(p goredis.Pipeliner) error {
+		p.Set(ctx, "a", "1", 0)
+		p.Set(ctx, "b", "2", 0)
+		return nil
+	})
+
+	s := ReadStats(Default())
+	if s.Commands-before.Commands != 5 || s.Errors-before.Errors != 1 || s.Dials-before.Dials < 1 || s.Latency <= before.Latency {
+		t.Errorf("stats = %+v before = %+v", s, before)
+	}
+	if s.TotalConns != 1 {
+		t.Errorf("连接池 = %+v", s)
+	}
+
+	// 连接失败计入 dial_errors
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	down, _ := New(&config.RedisConfig{Host: "127.0.0.1", Port: port})
+	defer down.Close()
+	_ = down.Ping(ctx).Err()
+	if after := ReadStats(nil); after.DialErrors <= s.DialErrors {
+		t.Errorf("dial_errors = %d", after.DialErrors)
+	}
+}
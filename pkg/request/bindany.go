@@ -0,0 +1,405 @@
+package request
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+// BindAny 按 Content-Type 自动选择 JSON、XML、表单或 multipart 解析请求，绑定到 dst（结构体指针）后自动校验。
+//
+// 参数名与字段宽松匹配：忽略大小写以及 _、- 分隔符，user_name、userName、UserName、user-name
+// 均可绑定到 json/form/xml 标签或字段名为其中任一写法的字段，适合同时服务多种客户端的接口。
+// 嵌套结构体在 JSON 中使用子对象，在表单与 XML 中使用 . 分隔的路径（address.city）。
+// 表单与 XML 中同名参数出现多次时绑定到切片字段；multipart 文件绑定到 *multipart.FileHeader 或其切片。
+//
+// 类型不匹配时返回校验错误（AppError，400），其原始错误为 validator.FieldErrors，逐字段给出参数名与原因：
+//
+//	var in struct {
+//	    UserName string `json:"user_name" validate:"required"`
+//	    Age      int
+//	}
+//	if err := request.BindAny(c, &in); err != nil {
+//	    return err
+//	}
+func BindAny(c *gin.Context, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.NewInternalServerError(fmt.Sprintf("BindAny 需要结构体指针，得到 %T", dst), nil)
+	}
+
+	var (
+		fes validator.FieldErrors
+		err error
+	)
+	switch {
+	case IsJSON(c):
+		fes, err = bindJSONBody(c, rv.Elem())
+	case IsXML(c):
+		fes, err = bindXMLBody(c, rv.Elem())
+	case IsMultipartForm(c):
+		var form *multipart.Form
+		if form, err = c.MultipartForm(); err != nil {
+			return errors.NewBadRequest("解析 multipart 表单失败", err)
+		}
+		values := c.Request.URL.Query()
+		for k, v := range form.Value {
+			values[k] = append(values[k], v...)
+		}
+		fes = bindValues(rv.Elem(), values, form.File)
+	default:
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			if _, err := RawBody(c); err != nil {
+				return err
+			}
+		}
+		if err := c.Request.ParseForm(); err != nil {
+			return errors.NewBadRequest("解析表单失败", err)
+		}
+		fes = bindValues(rv.Elem(), c.Request.Form, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if len(fes) > 0 {
+		return errors.NewValidationError(fes.Error(), fes)
+	}
+	if err := validator.Validate(dst); err != nil {
+		return errors.NewValidationError(err.Error(), err)
+	}
+	return nil
+}
+
+// bindJSONBody 解析 JSON 对象并逐字段绑定
+func bindJSONBody(c *gin.Context, v reflect.Value) (validator.FieldErrors, error) {
+	body, err := RawBody(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, errors.NewValidationError("JSON 格式错误: "+err.Error(), err)
+	}
+	return bindJSON(v, obj, "", ""), nil
+}
+
+// bindJSON 将 JSON 对象的各键绑定到结构体字段，子对象对应的结构体字段递归宽松匹配
+func bindJSON(v reflect.Value, obj map[string]json.RawMessage, path, keyPath string) validator.FieldErrors {
+	var fes validator.FieldErrors
+	for _, key := range sortedKeys(obj) {
+		f, ok := lookupField(v.Type(), key)
+		if !ok {
+			continue
+		}
+		raw := obj[key]
+		fv := fieldByIndex(v, f.Index)
+		fieldPath, fieldKey := joinPath(path, f.Name), joinPath(keyPath, key)
+
+		if isNestedStruct(fv.Type()) && bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			var sub map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &sub); err == nil {
+				fes = append(fes, bindJSON(deref(fv), sub, fieldPath, fieldKey)...)
+				continue
+			}
+		}
+		if err := json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+			fes = append(fes, typeError(fieldPath, fieldKey, fv.Type()))
+		}
+	}
+	return fes
+}
+
+// bindXMLBody 将 XML 根元素下的叶子元素展开为 . 分隔路径的参数后按表单规则绑定
+func bindXMLBody(c *gin.Context, v reflect.Value) (validator.FieldErrors, error) {
+	body, err := RawBody(c)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]string)
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	var (
+		stack []string
+		text  strings.Builder
+		leaf  bool
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewValidationError("XML 格式错误: "+err.Error(), err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			text.Reset()
+			leaf = true
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			// 跳过根元素，只收集没有子元素的叶子
+			if leaf && len(stack) > 1 {
+				key := strings.Join(stack[1:], ".")
+				values[key] = append(values[key], strings.TrimSpace(text.String()))
+			}
+			stack = stack[:len(stack)-1]
+			leaf = false
+		}
+	}
+	return bindValues(v, values, nil), nil
+}
+
+// bindValues 将表单参数（键可为 . 分隔的嵌套路径）与上传文件绑定到结构体字段
+func bindValues(v reflect.Value, values map[string][]string, files map[string][]*multipart.FileHeader) validator.FieldErrors {
+	var fes validator.FieldErrors
+	for _, key := range sortedKeys(values) {
+		fv, fieldPath, ok := resolvePath(v, key)
+		if !ok {
+			continue
+		}
+		if err := setValues(fv, values[key]); err != nil {
+			fes = append(fes, typeError(fieldPath, key, fv.Type()))
+		}
+	}
+	for _, key := range sortedKeys(files) {
+		fv, _, ok := resolvePath(v, key)
+		if !ok {
+			continue
+		}
+		switch fv.Type() {
+		case fileHeaderType:
+			fv.Set(reflect.ValueOf(files[key][0]))
+		case fileHeadersType:
+			fv.Set(reflect.ValueOf(files[key]))
+		}
+	}
+	return fes
+}
+
+// resolvePath 按 . 分隔的参数名逐级宽松匹配字段，途经的 nil 指针会被分配
+func resolvePath(v reflect.Value, key string) (reflect.Value, string, bool) {
+	var path string
+	segments := strings.Split(key, ".")
+	for i, seg := range segments {
+		f, ok := lookupField(v.Type(), seg)
+		if !ok {
+			return reflect.Value{}, "", false
+		}
+		v = fieldByIndex(v, f.Index)
+		path = joinPath(path, f.Name)
+		if i < len(segments)-1 {
+			if !isNestedStruct(v.Type()) {
+				return reflect.Value{}, "", false
+			}
+			v = deref(v)
+		}
+	}
+	return v, path, true
+}
+
+var (
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// setValues 将字符串参数写入字段：切片逐个转换，其余取第一个值
+func setValues(fv reflect.Value, vals []string) error {
+	if len(vals) == 0 {
+		return nil
+	}
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 && !implementsText(fv.Type()) {
+		s := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setScalar(s.Index(i), val); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+		return nil
+	}
+	return setScalar(fv, vals[0])
+}
+
+// setScalar 将单个字符串转换为字段类型，支持基础类型、指针与 encoding.TextUnmarshaler（如 time.Time）
+func setScalar(fv reflect.Value, val string) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setScalar(fv.Elem(), val)
+	}
+	if implementsText(fv.Type()) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+	// 数值与布尔类型的空值保持零值，与 gin 表单绑定一致
+	if val == "" && fv.Kind() != reflect.String {
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", fv.Type())
+	}
+	return nil
+}
+
+// implementsText 判断类型的指针是否实现 encoding.TextUnmarshaler
+func implementsText(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isNestedStruct 判断字段是否为需要逐字段宽松匹配的结构体（或其指针），自定义解码的类型（如 time.Time）除外
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	pt := reflect.PointerTo(t)
+	return !pt.Implements(jsonUnmarshalerType) && !pt.Implements(textUnmarshalerType)
+}
+
+// deref 返回指针指向的值，nil 指针先分配
+func deref(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Pointer {
+		return v
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return v.Elem()
+}
+
+// fieldByIndex 与 reflect.Value.FieldByIndex 相同，但会分配途经的 nil 嵌入指针
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			v = deref(v)
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// typeError 类型不匹配的字段错误
+func typeError(field, key string, t reflect.Type) validator.FieldError {
+	return validator.FieldError{Field: field, Key: key, Tag: "type", Message: "类型应为 " + t.String()}
+}
+
+// fieldCache 结构体类型 -> 归一化名称 -> 字段
+var fieldCache sync.Map
+
+// lookupField 按宽松规则查找参数名对应的字段
+func lookupField(t reflect.Type, key string) (reflect.StructField, bool) {
+	fields, ok := fieldCache.Load(t)
+	if !ok {
+		fields, _ = fieldCache.LoadOrStore(t, fieldIndex(t))
+	}
+	f, ok := fields.(map[string]reflect.StructField)[normalizeName(key)]
+	return f, ok
+}
+
+// fieldIndex 建立结构体可绑定字段的名称索引：json/form/xml 标签名与字段名，含嵌入结构体提升的字段
+func fieldIndex(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || (f.Anonymous && f.Tag == "") {
+			continue
+		}
+		names := []string{f.Name}
+		skip := false
+		for _, tag := range []string{"json", "form", "xml"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				skip = true
+			} else if name != "" {
+				names = append(names, name)
+			}
+		}
+		if skip {
+			continue
+		}
+		for _, name := range names {
+			n := normalizeName(name)
+			// 外层字段优先于嵌入结构体提升的同名字段
+			if old, ok := fields[n]; !ok || len(f.Index) < len(old.Index) {
+				fields[n] = f
+			}
+		}
+	}
+	return fields
+}
+
+// normalizeName 归一化参数名：转小写并去掉 _ 与 -
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// joinPath 以 . 连接路径
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// sortedKeys 返回排序后的键，使字段错误顺序稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
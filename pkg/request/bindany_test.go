@@ -0,0 +1,118 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+type bindAddress struct {
+	CityName string `json:"city_name"`
+	Zip      int
+}
+
+type bindInput struct {
+	UserName string `json:"user_name"`
+	Age      int
+	Tags     []string
+	Active   *bool
+	Birthday time.Time
+	Address  *bindAddress
+	Avatar   *multipart.FileHeader
+	Ignored  string `json:"-"`
+}
+
+// TestBindAnyFuzzyNames JSON、表单与 XML 的 snake_case/camelCase 参数名都能绑定到同一字段
+func TestBindAnyFuzzyNames(t *testing.T) {
+	cases := map[string]struct{ contentType, body string }{
+		"json": {"application/json", `{"userName":"alice","AGE":30,"tags":["a","b"],"active":true,"birthday":"2000-01-02T00:00:00Z","address":{"cityName":"Paris","zip":75001},"ignored":"x"}`},
+		"form": {"application/x-www-form-urlencoded", "user-name=alice&age=30&tags=a&tags=b&active=true&birthday=2000-01-02T00:00:00Z&address.city_name=Paris&address.zip=75001&ignored=x"},
+		"xml":  {"application/xml", `<in><UserName>alice</UserName><age>30</age><tags>a</tags><tags>b</tags><active>true</active><birthday>2000-01-02T00:00:00Z</birthday><address><city_name>Paris</city_name><zip>75001</zip></address><ignored>x</ignored></in>`},
+	}
+	for name, tc := range cases {
+		c := newBodyCtx(tc.body)
+		c.Request.Header.Set("Content-Type", tc.contentType)
+
+		var in bindInput
+		if err := BindAny(c, &in); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if in.UserName != "alice" || in.Age != 30 || strings.Join(in.Tags, ",") != "a,b" || in.Active == nil || !*in.Active {
+			t.Errorf("%s: %+v", name, in)
+		}
+		if in.Birthday.Year() != 2000 || in.Address == nil || in.Address.CityName != "Paris" || in.Address.Zip != 75001 {
+			t.Errorf("%s: 嵌套字段 %+v %+v", name, in.Birthday, in.Address)
+		}
+		if in.Ignored != "" {
+			t.Errorf("%s: 标签为 - 的字段不应绑定", name)
+		}
+	}
+}
+
+// TestBindAnyMultipart multipart 表单绑定普通字段与上传文件
+func TestBindAnyMultipart(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("user_name", "bob")
+	fw, _ := w.CreateFormFile("avatar", "a.png")
+	fw.Write([]byte("png"))
+	w.Close()
+
+	c := newCtx("age=18")
+	c.Request = httptest.NewRequest(http.MethodPost, "/?age=18", &buf)
+	c.Request.Header.Set("Content-Type", w.FormDataContentType())
+
+	var in bindInput
+	if err := BindAny(c, &in); err != nil {
+		t.Fatal(err)
+	}
+	if in.UserName != "bob" || in.Age != 18 || in.Avatar == nil || in.Avatar.Filename != "a.png" {
+		t.Errorf("%+v", in)
+	}
+}
+
+// TestBindAnyFieldErrors 类型不匹配返回逐字段的校验错误，校验器错误同样转为校验错误
+func TestBindAnyFieldErrors(t *testing.T) {
+	c := newBodyCtx(`{"age":"old","address":{"zip":"x"}}`)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var in bindInput
+	err := BindAny(c, &in)
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != apperrors.ValidationError {
+		t.Fatalf("期望校验错误: %v", err)
+	}
+	var fes validator.FieldErrors
+	if !errors.As(err, &fes) || len(fes) != 2 {
+		t.Fatalf("期望 2 个字段错误: %v", err)
+	}
+	if fes[0].Field != "Address.Zip" || fes[0].Key != "address.zip" || fes[0].Tag != "type" {
+		t.Errorf("嵌套字段错误: %+v", fes[0])
+	}
+	if fes[1].Field != "Age" || fes[1].Message != "类型应为 int" {
+		t.Errorf("字段错误: %+v", fes[1])
+	}
+
+	validator.Register(validatorFunc(func(any) error { return errors.New("user_name 必填") }))
+	defer validator.Register(nil)
+	c = newBodyCtx("age=1")
+	if err := BindAny(c, &in); !errors.As(err, &appErr) || appErr.Code != apperrors.ValidationError {
+		t.Errorf("校验器错误应转为校验错误: %v", err)
+	}
+
+	if err := BindAny(c, in); err == nil {
+		t.Error("非指针参数应返回错误")
+	}
+}
+
+type validatorFunc func(any) error
+
+func (f validatorFunc) Validate(i any) error { return f(i) }
@@ -0,0 +1,75 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// DefaultMaxBodySize RawBody 默认读取上限（10 MB）
+const DefaultMaxBodySize int64 = 10 << 20
+
+var maxBodySize atomic.Int64
+
+// SetMaxBodySize 设置 RawBody 读取上限（字节），n <= 0 时恢复 DefaultMaxBodySize；路由初始化时按 server.max_body_size 设置
+func SetMaxBodySize(n int64) {
+	if n <= 0 {
+		n = DefaultMaxBodySize
+	}
+	maxBodySize.Store(n)
+}
+
+// MaxBodySize 返回 RawBody 读取上限
+func MaxBodySize() int64 {
+	if n := maxBodySize.Load(); n > 0 {
+		return n
+	}
+	return DefaultMaxBodySize
+}
+
+// RawBody 读取原始请求体并缓存在上下文中，同一请求内可多次调用（如签名校验、日志中间件与 Input 同时需要请求体）。
+// 每次调用后请求体都被恢复为完整内容，后续的 Bind、c.PostForm 等仍可正常读取；
+// 缓存键与 gin 的 ShouldBindBodyWith 相同，二者共用同一份数据。
+// 超过 MaxBodySize 时返回 413 AppError，请求体保持未读状态。
+//
+//	body, err := request.RawBody(c)
+//	if err != nil {
+//	    return err
+//	}
+//	if !webhook.Verify(body, c.GetHeader("X-Signature")) { ... }
+func RawBody(c *gin.Context) ([]byte, error) {
+	if v, ok := c.Get(gin.BodyBytesKey); ok {
+		if body, ok := v.([]byte); ok {
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			return body, nil
+		}
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		c.Set(gin.BodyBytesKey, []byte{})
+		return []byte{}, nil
+	}
+
+	limit := MaxBodySize()
+	original := c.Request.Body
+	body, err := io.ReadAll(io.LimitReader(original, limit+1))
+	if err != nil {
+		return nil, errors.NewBadRequest("读取请求体失败", err)
+	}
+	if int64(len(body)) > limit {
+		// 已读取的部分拼回请求体，调用方可自行决定继续流式处理
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), original), original}
+		return nil, errors.New(errors.EntityTooLarge, fmt.Sprintf("请求体超过 %d 字节", limit), nil)
+	}
+
+	c.Set(gin.BodyBytesKey, body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,77 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	apperrors "github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// newBodyCtx 创建带表单请求体的 POST 上下文
+func newBodyCtx(body string) *gin.Context {
+	c := newCtx("")
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c
+}
+
+// TestRawBody 多次读取返回同一内容，读取后表单与 ShouldBindBodyWith 仍可使用
+func TestRawBody(t *testing.T) {
+	c := newBodyCtx("name=alice&age=30")
+
+	for range 2 {
+		body, err := RawBody(c)
+		if err != nil || string(body) != "name=alice&age=30" {
+			t.Fatalf("RawBody: %q %v", body, err)
+		}
+	}
+	if got := Input(c, "name", ""); got != "alice" {
+		t.Errorf("读取原始请求体后表单应可用: %q", got)
+	}
+	if body, _ := RawBody(c); string(body) != "name=alice&age=30" {
+		t.Errorf("表单解析后仍应返回缓存内容: %q", body)
+	}
+
+	c = newBodyCtx(`{"name":"bob"}`)
+	if _, err := RawBody(c); err != nil {
+		t.Fatal(err)
+	}
+	var dst struct{ Name string }
+	if err := c.ShouldBindBodyWith(&dst, binding.JSON); err != nil || dst.Name != "bob" {
+		t.Errorf("ShouldBindBodyWith 应复用缓存: %+v %v", dst, err)
+	}
+}
+
+// TestRawBodyTooLarge 超过上限返回 413，请求体保持完整
+func TestRawBodyTooLarge(t *testing.T) {
+	SetMaxBodySize(4)
+	defer SetMaxBodySize(0)
+
+	c := newBodyCtx("0123456789")
+	_, err := RawBody(c)
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.HTTPStatus() != http.StatusRequestEntityTooLarge {
+		t.Fatalf("期望 413 错误，得到 %v", err)
+	}
+	rest, _ := io.ReadAll(c.Request.Body)
+	if string(rest) != "0123456789" {
+		t.Errorf("请求体应保持完整: %q", rest)
+	}
+	if MaxBodySize() != 4 {
+		t.Errorf("MaxBodySize %d", MaxBodySize())
+	}
+}
+
+// TestRawBodyEmpty GET 请求没有请求体时返回空切片
+func TestRawBodyEmpty(t *testing.T) {
+	body, err := RawBody(newCtx(""))
+	if err != nil || len(body) != 0 {
+		t.Errorf("空请求体: %q %v", body, err)
+	}
+}
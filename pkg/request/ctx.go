@@ -0,0 +1,168 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 框架约定的上下文键，统一替代散落在各处的字符串字面量
+const (
+	KeyCurrentUser = "current_user" // 当前登录用户（由 LoadUser 等中间件写入）
+	KeyRequestID   = "request_id"   // 请求 ID
+	KeyLocale      = "locale"       // 当前请求语言，如 zh-CN
+	KeyTheme       = "theme"        // 当前请求主题（由 middleware.Theme 写入）
+	KeyTenant      = "tenant"       // 当前请求租户 *tenant.Tenant（由 middleware.Tenant 写入）
+	KeyGeo         = "geo"          // 当前请求地区 *geoip.Location（由 middleware.GeoIP 写入）
+	KeyUserAgent   = "user_agent"   // 当前请求 User-Agent 解析结果 *UserAgent（由 ParseUserAgent 缓存）
+	KeyParams      = "route_params" // 经 router.Rules 校验转换后的参数 map[string]any
+	KeyRouteName   = "route_name"   // 当前请求匹配的命名路由（由 router 在处理器执行前写入）
+	KeyViewData    = "view_data"    // 中间件共享给视图的数据 map[string]any（由 Share 写入）
+)
+
+// Set 向 gin.Context 写入值，与 Get/MustGet 配对使用
+func Set[T any](c *gin.Context, key string, value T) {
+	c.Set(key, value)
+}
+
+// Get 从 gin.Context 按类型读取值，键不存在或类型不匹配时返回零值与 false。
+// 用于替代 c.Get("user_id").(uint) 这类可能 panic 的断言：
+//
+//	userID, ok := request.Get[uint](c, middleware.ContextKeyUserID)
+func Get[T any](c *gin.Context, key string) (T, bool) {
+	var zero T
+	v, exists := c.Get(key)
+	if !exists {
+		return zero, false
+	}
+	typed, ok := v.(T)
+	if !ok {
+		return zero, false
+	}
+	return typed, true
+}
+
+// GetOr 从 gin.Context 按类型读取值，读取失败时返回 def
+func GetOr[T any](c *gin.Context, key string, def T) T {
+	if v, ok := Get[T](c, key); ok {
+		return v
+	}
+	return def
+}
+
+// MustGet 从 gin.Context 按类型读取值，键不存在或类型不匹配时 panic（由 Recovery 中间件兜底）
+func MustGet[T any](c *gin.Context, key string) T {
+	v, exists := c.Get(key)
+	if !exists {
+		panic(fmt.Sprintf("上下文键不存在: %s", key))
+	}
+	typed, ok := v.(T)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("上下文键 %s 类型不匹配: 期望 %T，实际 %T", key, zero, v))
+	}
+	return typed
+}
+
+// HeaderRequestID 传递请求 ID 的请求/响应头
+const HeaderRequestID = "X-Request-ID"
+
+// RequestID 获取当前请求 ID，未设置时返回空字符串
+func RequestID(c *gin.Context) string {
+	return GetOr(c, KeyRequestID, "")
+}
+
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求 ID 的上下文，供 HTTP 客户端、GORM 日志、事件总线等子系统读取
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext 获取上下文中的请求 ID，未设置时返回空字符串。
+// 未开启 ContextWithFallback 时 gin.Context 不会读取请求上下文中的值，因此同时回退到字符串键 KeyRequestID，
+// 直接传入 *gin.Context（如 db.WithContext(c)）也能取到
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	id, _ := ctx.Value(KeyRequestID).(string)
+	return id
+}
+
+// Context 返回传给服务层与仓储的请求上下文：取消与截止时间来自 c.Request.Context()（客户端断开、服务端超时即取消），
+// 取值先查请求上下文，再回退到 gin.Context 的键（如 database.QueryLogKey），
+// 从而 db.WithContext(ctx) 的 SQL 日志、租户 schema、调试工具栏等照常工作：
+//
+//	user, err := users.Get(request.Context(c), id)
+//
+// 直接传入 *gin.Context 时，未开启 ContextWithFallback 的 gin 不会传递取消信号。
+// 返回值持有 gin.Context 键的快照，处理器返回后仍可安全使用；需在请求结束后继续执行的任务应使用 context.WithoutCancel
+func Context(c *gin.Context) context.Context {
+	if c.Request == nil {
+		return context.Background()
+	}
+	return requestContext{Context: c.Request.Context(), keys: maps.Clone(c.Keys)}
+}
+
+// requestContext 请求上下文，取值回退到 gin.Context 键的快照
+type requestContext struct {
+	context.Context
+	keys map[string]any
+}
+
+func (r requestContext) Value(key any) any {
+	if v := r.Context.Value(key); v != nil {
+		return v
+	}
+	if k, ok := key.(string); ok {
+		return r.keys[k]
+	}
+	return nil
+}
+
+// Theme 获取当前请求主题，未设置时返回空字符串
+func Theme(c *gin.Context) string {
+	return GetOr(c, KeyTheme, "")
+}
+
+// RouteName 获取当前请求匹配的路由名，未经命名路由处理（如中间件中提前返回、404）时返回空字符串
+func RouteName(c *gin.Context) string {
+	return GetOr(c, KeyRouteName, "")
+}
+
+// ReservedViewKeys 由 template.WithContext 注入的视图数据键，不能通过 Share 共享
+var ReservedViewKeys = []string{"CurrentUser", "Theme", "Locale", "Tenant", "Geo", "Device", "Nav", "Old", "Errors", "CSRF", "Gate"}
+
+// Share 向当前请求的视图数据追加键值，由中间件调用（见 middleware.ViewData），渲染时经 template.WithContext 合并，
+// 处理器传入的同名键优先；同一键多次共享时后者覆盖前者。key 为保留键时 panic（编程错误）
+//
+//	request.Share(c, "Flash", flash)
+func Share(c *gin.Context, key string, value any) {
+	if slices.Contains(ReservedViewKeys, key) {
+		panic(fmt.Sprintf("视图数据键 %s 为保留键", key))
+	}
+	data, ok := Get[map[string]any](c, KeyViewData)
+	if !ok {
+		data = make(map[string]any)
+		c.Set(KeyViewData, data)
+	}
+	data[key] = value
+}
+
+// Shared 返回当前请求共享给视图的数据，未共享时返回 nil；调用方不得修改
+func Shared(c *gin.Context) map[string]any {
+	data, _ := Get[map[string]any](c, KeyViewData)
+	return data
+}
+
+// Locale 获取当前请求语言，未设置时返回空字符串
+func Locale(c *gin.Context) string {
+	return GetOr(c, KeyLocale, "")
+}
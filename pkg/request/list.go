@@ -0,0 +1,151 @@
+package request
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// SortField 排序字段
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery 列表接口的统一查询参数：
+//
+//	GET /users?q=张&sort=-created_at,name&fields=id,name&filter[role]=admin&page=2&per_page=20
+//
+// 排序、过滤与返回字段均须在白名单内（见 ParseListQuery 的选项），可直接用于拼接查询（如 database.Paginate）
+type ListQuery struct {
+	Search  string            // 搜索关键字（q）
+	Sort    []SortField       // 排序（sort，"-" 前缀表示降序）
+	Fields  []string          // 返回字段（fields），为空表示全部
+	Filters map[string]string // 等值过滤（filter[字段]=值）
+	Page    int               // 页码，从 1 开始
+	PerPage int               // 每页条数
+}
+
+// Offset 当前页的偏移量
+func (q *ListQuery) Offset() int {
+	return (q.Page - 1) * q.PerPage
+}
+
+// listConfig 列表查询白名单与默认值
+type listConfig struct {
+	sortable    []string
+	filterable  []string
+	fields      []string
+	defaultSort string
+	perPage     int
+	maxPerPage  int
+}
+
+// ListOption 列表查询选项
+type ListOption func(*listConfig)
+
+// WithListSortable 允许排序的字段，未设置时不允许客户端指定排序
+func WithListSortable(fields ...string) ListOption {
+	return func(c *listConfig) { c.sortable = append(c.sortable, fields...) }
+}
+
+// WithListFilterable 允许过滤的字段，未设置时不允许客户端指定过滤
+func WithListFilterable(fields ...string) ListOption {
+	return func(c *listConfig) { c.filterable = append(c.filterable, fields...) }
+}
+
+// WithListFields 允许客户端选择的返回字段，未设置时忽略 fields 参数
+func WithListFields(fields ...string) ListOption {
+	return func(c *listConfig) { c.fields = append(c.fields, fields...) }
+}
+
+// WithListDefaultSort 未指定 sort 时的默认排序，格式同 sort 参数，如 "-id"
+func WithListDefaultSort(sort string) ListOption {
+	return func(c *listConfig) { c.defaultSort = sort }
+}
+
+// WithListPerPage 每页默认条数与上限（默认 20、100）
+func WithListPerPage(def, max int) ListOption {
+	return func(c *listConfig) { c.perPage, c.maxPerPage = def, max }
+}
+
+// ParseListQuery 解析并校验列表查询参数，排序、过滤或返回字段不在白名单内时返回校验错误：
+//
+//	q, err := request.ParseListQuery(c,
+//	    request.WithListSortable("id", "name", "created_at"),
+//	    request.WithListFilterable("role"),
+//	    request.WithListDefaultSort("-id"),
+//	)
+func ParseListQuery(c *gin.Context, opts ...ListOption) (*ListQuery, error) {
+	cfg := &listConfig{perPage: 20, maxPerPage: 100}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	q := &ListQuery{
+		Search:  strings.TrimSpace(c.Query("q")),
+		Filters: make(map[string]string),
+		Page:    1,
+		PerPage: cfg.perPage,
+	}
+
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errors.NewValidationError("page 须为正整数", err)
+		}
+		q.Page = n
+	}
+	if v := c.Query("per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errors.NewValidationError("per_page 须为正整数", err)
+		}
+		q.PerPage = min(n, cfg.maxPerPage)
+	}
+
+	sort := c.Query("sort")
+	explicit := sort != ""
+	if !explicit {
+		sort = cfg.defaultSort
+	}
+	for _, s := range splitList(sort) {
+		f := SortField{Field: strings.TrimPrefix(s, "-"), Desc: strings.HasPrefix(s, "-")}
+		if explicit && !slices.Contains(cfg.sortable, f.Field) {
+			return nil, errors.NewValidationError(fmt.Sprintf("不支持按 %s 排序", f.Field), nil)
+		}
+		q.Sort = append(q.Sort, f)
+	}
+
+	if len(cfg.fields) > 0 {
+		for _, f := range splitList(c.Query("fields")) {
+			if !slices.Contains(cfg.fields, f) {
+				return nil, errors.NewValidationError(fmt.Sprintf("不支持的字段 %s", f), nil)
+			}
+			q.Fields = append(q.Fields, f)
+		}
+	}
+
+	for k, v := range c.QueryMap("filter") {
+		if !slices.Contains(cfg.filterable, k) {
+			return nil, errors.NewValidationError(fmt.Sprintf("不支持按 %s 过滤", k), nil)
+		}
+		q.Filters[k] = v
+	}
+	return q, nil
+}
+
+// splitList 按逗号拆分并去除空白与空项
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,63 @@
+package request
+
+import (
+	"reflect"
+	"testing"
+)
+
+var listOptions = []ListOption{
+	WithListSortable("id", "name"),
+	WithListFilterable("role"),
+	WithListFields("id", "name"),
+	WithListDefaultSort("-id"),
+	WithListPerPage(10, 50),
+}
+
+// TestParseListQuery 解析搜索、排序、字段、过滤与分页参数
+func TestParseListQuery(t *testing.T) {
+	q, err := ParseListQuery(newCtx("q=+张+&sort=name,-id&fields=id&filter[role]=admin&page=3&per_page=500"), listOptions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ListQuery{
+		Search:  "张",
+		Sort:    []SortField{{Field: "name"}, {Field: "id", Desc: true}},
+		Fields:  []string{"id"},
+		Filters: map[string]string{"role": "admin"},
+		Page:    3,
+		PerPage: 50,
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("ParseListQuery = %+v", q)
+	}
+	if q.Offset() != 100 {
+		t.Errorf("Offset = %d", q.Offset())
+	}
+
+	q, err = ParseListQuery(newCtx(""), listOptions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Page != 1 || q.PerPage != 10 || !reflect.DeepEqual(q.Sort, []SortField{{Field: "id", Desc: true}}) {
+		t.Errorf("默认值 %+v", q)
+	}
+}
+
+// TestParseListQueryRejects 白名单外的排序、字段、过滤与非法分页返回错误
+func TestParseListQueryRejects(t *testing.T) {
+	for _, target := range []string{
+		"sort=password",
+		"fields=password",
+		"filter[password]=x",
+		"page=0",
+		"per_page=abc",
+	} {
+		if _, err := ParseListQuery(newCtx(target), listOptions...); err == nil {
+			t.Errorf("%s 应返回错误", target)
+		}
+	}
+	// 未配置字段白名单时忽略 fields 参数
+	if q, err := ParseListQuery(newCtx("fields=password")); err != nil || q.Fields != nil {
+		t.Errorf("未配置字段白名单: %+v %v", q, err)
+	}
+}
@@ -64,21 +64,9 @@ func IsMultipartForm(c *gin.Context) bool {
 	return strings.Contains(contentType, "multipart/form-data")
 }
 
-// IsMobile 判断是否为移动设备访问
+// IsMobile 判断是否为移动设备（手机或平板）访问，设备类型见 ParseUserAgent
 func IsMobile(c *gin.Context) bool {
-	userAgent := strings.ToLower(c.GetHeader("User-Agent"))
-	mobileKeywords := []string{
-		"mobile", "android", "iphone", "ipad", "ipod",
-		"blackberry", "windows phone", "webos",
-	}
-
-	for _, keyword := range mobileKeywords {
-		if strings.Contains(userAgent, keyword) {
-			return true
-		}
-	}
-
-	return false
+	return ParseUserAgent(c).IsMobile()
 }
 
 // GetClientIP 获取客户端真实 IP 地址。
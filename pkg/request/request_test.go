@@ -1,6 +1,8 @@
 package request
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -91,3 +93,71 @@ func TestFileAbsent(t *testing.T) {
 		t.Errorf("无文件时 Files 应返回 nil, 得到 %v", fs)
 	}
 }
+
+func TestCtxGetTyped(t *testing.T) {
+	c := newCtx("")
+	Set(c, "user_id", uint(42))
+
+	if id, ok := Get[uint](c, "user_id"); !ok || id != 42 {
+		t.Errorf("Get[uint]: 期望 42, 得到 %v (ok=%v)", id, ok)
+	}
+	// 类型不匹配返回零值与 false，而非 panic
+	if id, ok := Get[int](c, "user_id"); ok || id != 0 {
+		t.Errorf("Get[int]: 类型不匹配应返回 0,false, 得到 %v,%v", id, ok)
+	}
+	if got := GetOr(c, KeyLocale, "zh-CN"); got != "zh-CN" {
+		t.Errorf("GetOr: 期望默认 zh-CN, 得到 %q", got)
+	}
+}
+
+func TestCtxMustGetPanics(t *testing.T) {
+	c := newCtx("")
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGet 键不存在时应 panic")
+		}
+	}()
+	MustGet[string](c, KeyRequestID)
+}
+
+func TestContext(t *testing.T) {
+	c := newCtx("")
+	parent, cancel := context.WithCancel(WithRequestID(context.Background(), "req-1"))
+	c.Request = c.Request.WithContext(parent)
+	c.Set("query_log", "log")
+
+	ctx := Context(c)
+	if RequestIDFromContext(ctx) != "req-1" || ctx.Value("query_log") != "log" {
+		t.Errorf("应读取请求上下文与 gin 键的值")
+	}
+	// 处理器返回后 gin.Context 被复用，已取得的上下文不受影响
+	c.Set("query_log", "other")
+	if ctx.Value("query_log") != "log" {
+		t.Error("应持有 gin 键的快照")
+	}
+
+	cancel()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Error("请求取消时上下文应随之取消")
+	}
+}
+
+func TestShare(t *testing.T) {
+	c := newCtx("")
+	if Shared(c) != nil {
+		t.Error("未共享时应返回 nil")
+	}
+	Share(c, "Flash", "已保存")
+	Share(c, "Flash", "已更新")
+	Share(c, "Count", 3)
+	if got := Shared(c); got["Flash"] != "已更新" || got["Count"] != 3 {
+		t.Errorf("Shared = %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("共享保留键应 panic")
+		}
+	}()
+	Share(c, "CurrentUser", nil)
+}
@@ -0,0 +1,183 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceClass 设备类型
+type DeviceClass string
+
+const (
+	DeviceDesktop DeviceClass = "desktop" // 桌面浏览器
+	DeviceMobile  DeviceClass = "mobile"  // 手机
+	DeviceTablet  DeviceClass = "tablet"  // 平板
+	DeviceBot     DeviceClass = "bot"     // 爬虫与命令行工具
+	DeviceUnknown DeviceClass = "unknown" // 未携带 User-Agent
+)
+
+// UserAgent User-Agent 解析结果，无法识别的部分为空字符串
+type UserAgent struct {
+	Raw       string      `json:"raw"`
+	Browser   string      `json:"browser"`    // 如 Chrome、Safari、Firefox、Edge、WeChat
+	Version   string      `json:"version"`    // 浏览器版本，如 120.0.6099.109
+	OS        string      `json:"os"`         // 如 Windows、macOS、iOS、Android、Linux
+	OSVersion string      `json:"os_version"` // 系统版本，如 17.1、10.15.7
+	Device    DeviceClass `json:"device"`
+}
+
+// IsMobile 是否为手机或平板（与 request.IsMobile 一致，触屏设备均视为移动端）
+func (ua *UserAgent) IsMobile() bool {
+	return ua.Device == DeviceMobile || ua.Device == DeviceTablet
+}
+
+// IsTablet 是否为平板
+func (ua *UserAgent) IsTablet() bool { return ua.Device == DeviceTablet }
+
+// IsDesktop 是否为桌面浏览器
+func (ua *UserAgent) IsDesktop() bool { return ua.Device == DeviceDesktop }
+
+// IsBot 是否为爬虫或命令行工具
+func (ua *UserAgent) IsBot() bool { return ua.Device == DeviceBot }
+
+// ParseUserAgent 解析当前请求的 User-Agent，结果缓存在上下文中，同一请求内多次调用只解析一次。
+// 模板中可通过 .Device 读取（见 template.WithContext）：
+//
+//	ua := request.ParseUserAgent(c)
+//	if ua.IsBot() { ... }
+//	logger.Info("login", zap.String("browser", ua.Browser+" "+ua.Version), zap.String("os", ua.OS))
+func ParseUserAgent(c *gin.Context) *UserAgent {
+	if ua, ok := Get[*UserAgent](c, KeyUserAgent); ok {
+		return ua
+	}
+	ua := ParseUserAgentString(c.GetHeader("User-Agent"))
+	c.Set(KeyUserAgent, ua)
+	return ua
+}
+
+// browserTokens 按顺序匹配的浏览器标识，requires 为同时需要出现的标识。
+// Edge、Opera 等基于 Chromium 的浏览器需排在 Chrome 之前，Chrome 排在 Safari 之前
+var browserTokens = []struct{ token, name, requires string }{
+	{"MicroMessenger/", "WeChat", ""},
+	{"Edg/", "Edge", ""},
+	{"EdgA/", "Edge", ""},
+	{"EdgiOS/", "Edge", ""},
+	{"Edge/", "Edge", ""},
+	{"OPR/", "Opera", ""},
+	{"SamsungBrowser/", "Samsung Internet", ""},
+	{"UCBrowser/", "UC Browser", ""},
+	{"YaBrowser/", "Yandex", ""},
+	{"FxiOS/", "Firefox", ""},
+	{"Firefox/", "Firefox", ""},
+	{"CriOS/", "Chrome", ""},
+	{"Chrome/", "Chrome", ""},
+	{"Version/", "Safari", "Safari/"},
+	{"MSIE ", "IE", ""},
+	{"rv:", "IE", "Trident/"}, // IE 11：Trident/7.0; rv:11.0
+}
+
+// botKeywords 爬虫与工具的 User-Agent 关键词（小写）
+var botKeywords = []string{
+	"bot", "crawler", "spider", "slurp", "headlesschrome", "lighthouse",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client", "okhttp", "postman",
+}
+
+// ParseUserAgentString 解析 User-Agent 字符串，基于常见关键词识别，不依赖外部规则库
+func ParseUserAgentString(s string) *UserAgent {
+	ua := &UserAgent{Raw: s}
+	if strings.TrimSpace(s) == "" {
+		ua.Device = DeviceUnknown
+		return ua
+	}
+	lower := strings.ToLower(s)
+
+	ua.OS, ua.OSVersion = parseOS(s)
+	for _, b := range browserTokens {
+		if b.requires != "" && !strings.Contains(s, b.requires) {
+			continue
+		}
+		if v, ok := tokenVersion(s, b.token); ok {
+			ua.Browser, ua.Version = b.name, v
+			break
+		}
+	}
+
+	switch {
+	case containsAny(lower, botKeywords...):
+		ua.Device = DeviceBot
+	case containsAny(lower, "ipad", "tablet", "kindle", "silk/", "playbook") ||
+		(strings.Contains(lower, "android") && !strings.Contains(lower, "mobile")):
+		ua.Device = DeviceTablet
+	case containsAny(lower, "mobi", "iphone", "ipod", "android", "windows phone", "blackberry", "webos", "opera mini"):
+		ua.Device = DeviceMobile
+	default:
+		ua.Device = DeviceDesktop
+	}
+	return ua
+}
+
+// parseOS 识别操作系统与版本
+func parseOS(s string) (string, string) {
+	switch {
+	case strings.Contains(s, "Windows Phone"):
+		v, _ := tokenVersion(s, "Windows Phone ")
+		return "Windows Phone", v
+	case strings.Contains(s, "Windows NT"):
+		v, _ := tokenVersion(s, "Windows NT ")
+		return "Windows", windowsVersions[v]
+	case strings.Contains(s, "iPhone") || strings.Contains(s, "iPad") || strings.Contains(s, "iPod"):
+		v, ok := tokenVersion(s, "iPhone OS ")
+		if !ok {
+			v, _ = tokenVersion(s, "CPU OS ")
+		}
+		return "iOS", strings.ReplaceAll(v, "_", ".")
+	case strings.Contains(s, "HarmonyOS"):
+		v, _ := tokenVersion(s, "HarmonyOS ")
+		return "HarmonyOS", v
+	case strings.Contains(s, "Android"):
+		v, _ := tokenVersion(s, "Android ")
+		return "Android", v
+	case strings.Contains(s, "CrOS"):
+		return "Chrome OS", ""
+	case strings.Contains(s, "Mac OS X"):
+		v, _ := tokenVersion(s, "Mac OS X ")
+		return "macOS", strings.ReplaceAll(v, "_", ".")
+	case strings.Contains(s, "Linux"):
+		return "Linux", ""
+	}
+	return "", ""
+}
+
+// windowsVersions Windows NT 内核版本对应的系统版本（Windows 11 的 UA 仍为 NT 10.0）
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// tokenVersion 返回 token 之后的版本号（到空格、分号或括号为止）
+func tokenVersion(s, token string) (string, bool) {
+	i := strings.Index(s, token)
+	if i < 0 {
+		return "", false
+	}
+	rest := s[i+len(token):]
+	if j := strings.IndexAny(rest, " ;()"); j >= 0 {
+		rest = rest[:j]
+	}
+	return rest, true
+}
+
+// containsAny 判断 s 是否包含任一关键词
+func containsAny(s string, keywords ...string) bool {
+	for _, k := range keywords {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
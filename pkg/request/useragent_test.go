@@ -0,0 +1,58 @@
+package request
+
+import "testing"
+
+func TestParseUserAgentString(t *testing.T) {
+	tests := []struct {
+		ua                              string
+		browser, version, os, osVersion string
+		device                          DeviceClass
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36",
+			"Chrome", "120.0.6099.109", "Windows", "10", DeviceDesktop},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			"Edge", "120.0.2210.91", "Windows", "10", DeviceDesktop},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			"Safari", "17.1", "macOS", "10.15.7", DeviceDesktop},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			"Safari", "17.1", "iOS", "17.1", DeviceMobile},
+		{"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/119.0.6045.169 Mobile/15E148 Safari/604.1",
+			"Chrome", "119.0.6045.169", "iOS", "16.6", DeviceTablet},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.43 Mobile Safari/537.36",
+			"Chrome", "120.0.6099.43", "Android", "14", DeviceMobile},
+		{"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36",
+			"Samsung Internet", "23.0", "Android", "13", DeviceTablet},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			"Firefox", "121.0", "Linux", "", DeviceDesktop},
+		{"Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko",
+			"IE", "11.0", "Windows", "7", DeviceDesktop},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 MicroMessenger/8.0.40(0x18002831) NetType/WIFI Language/zh_CN",
+			"WeChat", "8.0.40", "iOS", "16.0", DeviceMobile},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			"", "", "", "", DeviceBot},
+		{"curl/8.4.0", "", "", "", "", DeviceBot},
+		{"", "", "", "", "", DeviceUnknown},
+	}
+	for _, tt := range tests {
+		got := ParseUserAgentString(tt.ua)
+		if got.Browser != tt.browser || got.Version != tt.version || got.OS != tt.os || got.OSVersion != tt.osVersion || got.Device != tt.device {
+			t.Errorf("%q:\n got  %s %s / %s %s / %s\n want %s %s / %s %s / %s", tt.ua,
+				got.Browser, got.Version, got.OS, got.OSVersion, got.Device,
+				tt.browser, tt.version, tt.os, tt.osVersion, tt.device)
+		}
+	}
+}
+
+// TestParseUserAgentCached 同一请求只解析一次，IsMobile 将平板视为移动设备
+func TestParseUserAgentCached(t *testing.T) {
+	c := newCtx("")
+	c.Request.Header.Set("User-Agent", "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X)")
+
+	ua := ParseUserAgent(c)
+	if ParseUserAgent(c) != ua {
+		t.Error("同一请求应返回缓存的解析结果")
+	}
+	if !IsMobile(c) || !ua.IsTablet() || ua.IsDesktop() {
+		t.Errorf("iPad 应为平板与移动设备: %+v", ua)
+	}
+}
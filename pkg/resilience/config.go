@@ -0,0 +1,44 @@
+package resilience
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/gorm"
+)
+
+// NewFromConfig 按 resilience.* 配置创建探测器：添加 dependencies 中的内置探测（db 使用 db 连接，redis 使用全局 Redis 配置），
+// 并为 disable_features 中的依赖注册功能开关降级策略
+func NewFromConfig(cfg *config.ResilienceConfig, redisCfg *config.RedisConfig, db *gorm.DB, bus *eventbus.EventBus) *Monitor {
+	m := New(bus,
+		WithInterval(time.Duration(cfg.Interval)*time.Second),
+		WithTimeout(time.Duration(cfg.Timeout)*time.Second),
+		WithThreshold(cfg.Threshold),
+	)
+	for _, name := range cfg.Dependencies {
+		switch name {
+		case "db":
+			if db != nil {
+				m.Add(name, PingDB(db))
+			}
+		case "redis":
+			m.Add(name, PingRedis(redisCfg))
+		}
+	}
+	for name, features := range cfg.DisableFeatures {
+		m.OnDown(name, DisableFeatures(features...))
+	}
+	return m
+}
+
+func init() {
+	// 依赖状态发布到 /debug/vars 中的 resilience
+	expvar.Publish("resilience", expvar.Func(func() any {
+		if m := Default(); m != nil {
+			return m.Status()
+		}
+		return nil
+	}))
+}
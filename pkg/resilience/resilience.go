@@ -0,0 +1,274 @@
+// Package resilience 依赖故障时的降级：定时探测数据库、Redis 等依赖，连续失败达到阈值时标记为不可用并触发
+// dependency.down 事件，恢复后触发 dependency.up 事件；按依赖注册的降级策略经事件总线协调执行与撤销。
+//
+//	m := resilience.New(eventbus.Default(), resilience.WithInterval(5*time.Second))
+//	m.Add("redis", resilience.PingRedis(&cfg.Redis))
+//	m.OnDown("redis", resilience.SessionFallback(), resilience.DisableFeatures("chat"))
+//	m.OnDown("db", resilience.DisableFeatures("checkout", "comments"))
+//	m.Start(ctx)
+//
+// 内置策略：SessionFallback（会话切换到 Cookie 存储）、DisableFeatures（关闭功能开关），
+// 配合 StalePages 中间件在依赖不可用时返回页面最近一次的成功响应。
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/gorm"
+)
+
+const (
+	// EventDown 依赖被标记为不可用，参数为 *Change
+	EventDown = "dependency.down"
+	// EventUp 依赖恢复可用，参数为 *Change
+	EventUp = "dependency.up"
+)
+
+// Probe 依赖探测函数，返回错误表示本次探测失败
+type Probe func(ctx context.Context) error
+
+// Change 依赖状态变更
+type Change struct {
+	Name  string
+	Down  bool
+	Err   error     // 最近一次探测失败的错误，恢复时为 nil
+	Since time.Time // 进入当前状态的时间
+}
+
+// Status 依赖当前状态
+type Status struct {
+	Name     string    `json:"name"`
+	Down     bool      `json:"down"`
+	Since    time.Time `json:"since"`
+	Failures int       `json:"failures"` // 连续失败次数
+	Error    string    `json:"error,omitempty"`
+}
+
+// dependency 被探测的依赖
+type dependency struct {
+	name     string
+	probe    Probe
+	down     bool
+	since    time.Time
+	failures int
+	err      error
+}
+
+// Monitor 依赖探测与降级协调器
+type Monitor struct {
+	bus       *eventbus.EventBus
+	clock     clock.Clock
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+
+	mu   sync.RWMutex
+	deps []*dependency
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Option 配置选项
+type Option func(*Monitor)
+
+// WithInterval 设置探测间隔（默认 10 秒）
+func WithInterval(d time.Duration) Option {
+	return func(m *Monitor) { m.interval = d }
+}
+
+// WithTimeout 设置单次探测的超时时间（默认 2 秒）
+func WithTimeout(d time.Duration) Option {
+	return func(m *Monitor) { m.timeout = d }
+}
+
+// WithThreshold 设置标记为不可用所需的连续失败次数（默认 2），一次成功即恢复
+func WithThreshold(n int) Option {
+	return func(m *Monitor) { m.threshold = max(n, 1) }
+}
+
+// WithClock 设置记录状态时间使用的时钟（默认全局时钟）
+func WithClock(clk clock.Clock) Option {
+	return func(m *Monitor) { m.clock = clk }
+}
+
+// New 创建探测器，bus 为 nil 时使用全局事件总线
+func New(bus *eventbus.EventBus, opts ...Option) *Monitor {
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	m := &Monitor{bus: bus, clock: clock.Default(), interval: 10 * time.Second, timeout: 2 * time.Second, threshold: 2}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Add 添加被探测的依赖，同名依赖会被替换
+func (m *Monitor) Add(name string, probe Probe) *Monitor {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dep := &dependency{name: name, probe: probe, since: m.clock.Now()}
+	for i, d := range m.deps {
+		if d.name == name {
+			m.deps[i] = dep
+			return m
+		}
+	}
+	m.deps = append(m.deps, dep)
+	return m
+}
+
+// Check 探测全部依赖一次，状态变化时触发 EventDown / EventUp；由 Start 定时调用，也可在测试中直接调用
+func (m *Monitor) Check(ctx context.Context) {
+	m.mu.RLock()
+	deps := append([]*dependency(nil), m.deps...)
+	m.mu.RUnlock()
+
+	for _, dep := range deps {
+		pctx, cancel := context.WithTimeout(ctx, m.timeout)
+		err := dep.probe(pctx)
+		cancel()
+		if change := m.record(dep, err); change != nil {
+			event := EventUp
+			if change.Down {
+				event = EventDown
+			}
+			_ = m.bus.EmitCtx(ctx, event, change)
+		}
+	}
+}
+
+// record 记录探测结果，状态变化时返回变更
+func (m *Monitor) record(dep *dependency, err error) *Change {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dep.err = err
+	if err == nil {
+		dep.failures = 0
+		if !dep.down {
+			return nil
+		}
+		dep.down, dep.since = false, m.clock.Now()
+		return &Change{Name: dep.name, Since: dep.since}
+	}
+	dep.failures++
+	if dep.down || dep.failures < m.threshold {
+		return nil
+	}
+	dep.down, dep.since = true, m.clock.Now()
+	return &Change{Name: dep.name, Down: true, Err: err, Since: dep.since}
+}
+
+// Down 依赖是否被标记为不可用，未添加的依赖视为可用
+func (m *Monitor) Down(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, d := range m.deps {
+		if d.name == name {
+			return d.down
+		}
+	}
+	return false
+}
+
+// Status 返回全部依赖的当前状态（按添加顺序）
+func (m *Monitor) Status() []Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]Status, len(m.deps))
+	for i, d := range m.deps {
+		list[i] = Status{Name: d.name, Down: d.down, Since: d.since, Failures: d.failures}
+		if d.err != nil {
+			list[i].Error = d.err.Error()
+		}
+	}
+	return list
+}
+
+// Start 立即探测一次，之后按间隔定时探测，直到 ctx 取消或调用 Stop
+func (m *Monitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止定时探测并等待进行中的探测结束
+func (m *Monitor) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+}
+
+// PingDB 返回数据库探测函数
+func PingDB(db *gorm.DB) Probe {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// PingRedis 返回 Redis 探测函数，每次探测新建连接，不受连接池中失效连接的影响
+func PingRedis(cfg *config.RedisConfig) Probe {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	return func(ctx context.Context) error {
+		conn, err := redis.DialContext(ctx, "tcp", addr, redis.DialPassword(cfg.Password), redis.DialDatabase(cfg.DB))
+		if err != nil {
+			return fmt.Errorf("连接 %s 失败: %w", addr, err)
+		}
+		defer conn.Close()
+		_, err = redis.DoContext(conn, ctx, "PING")
+		return err
+	}
+}
+
+// ErrUnavailable 依赖不可用时降级中间件返回的错误
+var ErrUnavailable = errors.New("resilience: 依赖不可用")
+
+var defaultMonitor atomic.Pointer[Monitor]
+
+// SetDefault 设置全局探测器，供 Down、StalePages 使用；传入 nil 时清除
+func SetDefault(m *Monitor) {
+	defaultMonitor.Store(m)
+}
+
+// Default 返回全局探测器，未设置时为 nil
+func Default() *Monitor {
+	return defaultMonitor.Load()
+}
+
+// Down 全局探测器中依赖是否不可用，未设置全局探测器时视为可用
+func Down(name string) bool {
+	if m := Default(); m != nil {
+		return m.Down(name)
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// flaky 可切换成败的探测函数
+type flaky struct{ fail atomic.Bool }
+
+func (f *flaky) probe(context.Context) error {
+	if f.fail.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestMonitor(t *testing.T) {
+	bus := eventbus.New()
+	var events []string
+	bus.On("dependency.*", func(args ...interface{}) {
+		meta, change := args[0].(eventbus.EventMeta), args[1].(*Change)
+		events = append(events, meta.Name+":"+change.Name)
+	})
+
+	db := &flaky{}
+	m := New(bus, WithThreshold(2)).Add("db", db.probe)
+	m.Check(context.Background())
+
+	db.fail.Store(true)
+	m.Check(context.Background())
+	if m.Down("db") || len(events) != 0 {
+		t.Fatal("未达到连续失败阈值时不应标记为不可用")
+	}
+	m.Check(context.Background())
+	m.Check(context.Background())
+	if !m.Down("db") || len(events) != 1 || events[0] != "dependency.down:db" {
+		t.Fatalf("down = %v events = %v", m.Down("db"), events)
+	}
+	if st := m.Status()[0]; st.Failures != 3 || st.Error != "connection refused" {
+		t.Errorf("status = %+v", st)
+	}
+
+	db.fail.Store(false)
+	m.Check(context.Background())
+	if m.Down("db") || len(events) != 2 || events[1] != "dependency.up:db" {
+		t.Errorf("down = %v events = %v", m.Down("db"), events)
+	}
+	if m.Down("unknown") {
+		t.Error("未添加的依赖应视为可用")
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	bus := eventbus.New()
+	db, redis := &flaky{}, &flaky{}
+	m := New(bus, WithThreshold(1)).Add("db", db.probe).Add("redis", redis.probe)
+
+	var order []string
+	trace := func(name string) Strategy {
+		return Strategy{
+			Name:    name,
+			Degrade: func(context.Context, *Change) { order = append(order, "degrade:"+name) },
+			Recover: func(context.Context, *Change) { order = append(order, "recover:"+name) },
+		}
+	}
+	m.OnDown("db", trace("a"), trace("b"), DisableFeatures("checkout", "comments"))
+	m.OnDown("redis", DisableFeatures("comments"))
+
+	db.fail.Store(true)
+	redis.fail.Store(true)
+	m.Check(context.Background())
+	if FeatureEnabled("checkout") || FeatureEnabled("comments") || !FeatureEnabled("search") {
+		t.Error("依赖不可用时应关闭功能")
+	}
+
+	// 多个依赖关闭同一功能时，全部恢复后才重新开启
+	db.fail.Store(false)
+	m.Check(context.Background())
+	if !FeatureEnabled("checkout") || FeatureEnabled("comments") {
+		t.Error("db 恢复后 comments 仍应被 redis 关闭")
+	}
+	redis.fail.Store(false)
+	m.Check(context.Background())
+	if !FeatureEnabled("comments") {
+		t.Error("全部恢复后应重新开启")
+	}
+	if got := len(order); got != 4 || order[0] != "degrade:a" || order[1] != "degrade:b" || order[2] != "recover:b" || order[3] != "recover:a" {
+		t.Errorf("执行顺序 = %v", order)
+	}
+
+	// 注册时依赖已不可用，立即降级
+	db.fail.Store(true)
+	m.Check(context.Background())
+	m.OnDown("db", SessionFallback())
+	if !session.Degraded() {
+		t.Error("注册时依赖已不可用应立即执行 Degrade")
+	}
+	db.fail.Store(false)
+	m.Check(context.Background())
+	if session.Degraded() {
+		t.Error("恢复后应撤销会话降级")
+	}
+}
+
+func TestRequireFeature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/checkout", RequireFeature("pay"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkout", nil))
+		return w
+	}
+	if w := do(); w.Code != http.StatusOK {
+		t.Errorf("status = %d", w.Code)
+	}
+	s := DisableFeatures("pay")
+	s.Degrade(context.Background(), nil)
+	if w := do(); w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("关闭后 status = %d", w.Code)
+	}
+	s.Recover(context.Background(), nil)
+	if w := do(); w.Code != http.StatusOK {
+		t.Errorf("恢复后 status = %d", w.Code)
+	}
+}
+
+func TestStalePages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := &flaky{}
+	m := New(nil, WithThreshold(1)).Add("db", db.probe)
+	SetDefault(m)
+	defer SetDefault(nil)
+
+	var calls atomic.Int32
+	r := gin.New()
+	r.GET("/posts/:id", StalePages(cache.NewMemory(), time.Hour, "db"), func(c *gin.Context) {
+		calls.Add(1)
+		if Down("db") {
+			c.String(http.StatusInternalServerError, "数据库错误")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte("<h1>post "+c.Param("id")+"</h1>"))
+	})
+	do := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	do("/posts/1")
+	db.fail.Store(true)
+	m.Check(context.Background())
+
+	w := do("/posts/1")
+	if w.Code != http.StatusOK || w.Body.String() != "<h1>post 1</h1>" || w.Header().Get("X-Cache") != "STALE" ||
+		w.Header().Get("Content-Type") != "text/html; charset=utf-8" || calls.Load() != 1 {
+		t.Errorf("快照 status = %d body = %q calls = %d", w.Code, w.Body.String(), calls.Load())
+	}
+	// 没有快照的页面照常执行处理器
+	if w := do("/posts/2"); w.Code != http.StatusInternalServerError {
+		t.Errorf("无快照 status = %d", w.Code)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	m := NewFromConfig(&config.ResilienceConfig{
+		Dependencies:    []string{"db", "redis"},
+		Interval:        5,
+		Timeout:         1,
+		Threshold:       3,
+		DisableFeatures: map[string][]string{"redis": {"chat"}},
+	}, &config.RedisConfig{Host: "127.0.0.1", Port: 1}, nil, eventbus.New())
+	// 未提供数据库连接时不探测 db
+	if st := m.Status(); len(st) != 1 || st[0].Name != "redis" {
+		t.Fatalf("status = %+v", st)
+	}
+	if m.interval != 5*time.Second || m.timeout != time.Second || m.threshold != 3 {
+		t.Errorf("monitor = %+v", m)
+	}
+	for range 3 {
+		m.Check(context.Background())
+	}
+	if !m.Down("redis") || FeatureEnabled("chat") {
+		t.Error("Redis 不可达时应关闭 chat")
+	}
+	m.deps[0].probe = func(context.Context) error { return nil }
+	m.Check(context.Background())
+	if !FeatureEnabled("chat") {
+		t.Error("恢复后应重新开启 chat")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	var n atomic.Int32
+	m := New(nil, WithInterval(time.Millisecond)).Add("x", func(context.Context) error {
+		n.Add(1)
+		return nil
+	})
+	m.Start(context.Background())
+	for n.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	m.Stop()
+	stopped := n.Load()
+	time.Sleep(5 * time.Millisecond)
+	if n.Load() != stopped {
+		t.Error("Stop 后仍在探测")
+	}
+}
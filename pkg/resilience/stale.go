@@ -0,0 +1,92 @@
+package resilience
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+)
+
+// staleKeyPrefix 页面快照的缓存键前缀
+const staleKeyPrefix = "stale:"
+
+// snapshot 页面最近一次的成功响应
+type snapshot struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// StalePages 页面快照中间件：依赖正常时保存 GET 请求最近一次的 200 响应（未设置 Set-Cookie 的），
+// deps 中任一依赖不可用（见全局探测器 Default）时直接返回快照（X-Cache: STALE），没有快照时照常执行处理器。
+// 快照在所有用户间共享，只应挂载在公开页面上；store 为 nil 时使用 cache.Default()，应选择不依赖 deps 的后端：
+//
+//	rb.GET("/posts/:slug", posts.Show, resilience.StalePages(nil, 24*time.Hour, "db"))
+func StalePages(store cache.Store, ttl time.Duration, deps ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		s := store
+		if s == nil {
+			s = cache.Default()
+		}
+		ctx := c.Request.Context()
+		key := staleKeyPrefix + c.Request.URL.RequestURI()
+
+		// 依赖不可用时不更新快照，避免用降级期间的页面覆盖正常页面
+		if slices.ContainsFunc(deps, Down) {
+			var snap snapshot
+			if raw, ok, _ := s.Get(ctx, key); ok && json.Unmarshal(raw, &snap) == nil {
+				header := c.Writer.Header()
+				for k, v := range snap.Header {
+					header[k] = v
+				}
+				c.Header("X-Cache", "STALE")
+				c.Data(http.StatusOK, header.Get("Content-Type"), snap.Body)
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if w.Status() != http.StatusOK || w.Header().Get("Set-Cookie") != "" || len(c.Errors) > 0 {
+			return
+		}
+		header := w.Header().Clone()
+		header.Del("Content-Length")
+		if raw, err := json.Marshal(snapshot{Header: header, Body: w.body.Bytes()}); err == nil {
+			_ = s.Set(ctx, key, raw, ttl)
+		}
+	}
+}
+
+// captureWriter 在写出响应的同时保留一份响应体副本
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Unwrap 返回被包装的写入器
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,116 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/response"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// Strategy 降级策略：依赖不可用时执行 Degrade，恢复后执行 Recover（可为 nil）
+type Strategy struct {
+	Name    string
+	Degrade func(ctx context.Context, change *Change)
+	Recover func(ctx context.Context, change *Change)
+}
+
+// OnDown 为依赖注册降级策略：订阅 EventDown / EventUp，按注册顺序执行 Degrade，恢复时按相反顺序执行 Recover。
+// 注册时依赖已不可用的，立即执行 Degrade
+func (m *Monitor) OnDown(name string, strategies ...Strategy) {
+	m.bus.OnE(EventDown, func(ctx context.Context, args ...interface{}) error {
+		if change, ok := args[0].(*Change); ok && change.Name == name {
+			for _, s := range strategies {
+				if s.Degrade != nil {
+					s.Degrade(ctx, change)
+				}
+			}
+		}
+		return nil
+	})
+	m.bus.OnE(EventUp, func(ctx context.Context, args ...interface{}) error {
+		if change, ok := args[0].(*Change); ok && change.Name == name {
+			for i := len(strategies) - 1; i >= 0; i-- {
+				if s := strategies[i]; s.Recover != nil {
+					s.Recover(ctx, change)
+				}
+			}
+		}
+		return nil
+	})
+
+	if m.Down(name) {
+		change := &Change{Name: name, Down: true}
+		for _, st := range m.Status() {
+			if st.Name == name {
+				change.Since = st.Since
+			}
+		}
+		for _, s := range strategies {
+			if s.Degrade != nil {
+				s.Degrade(context.Background(), change)
+			}
+		}
+	}
+}
+
+// SessionFallback 会话降级策略：依赖不可用时会话改用 Cookie 存储，需开启 session.fallback
+func SessionFallback() Strategy {
+	return Strategy{
+		Name:    "session-fallback",
+		Degrade: func(context.Context, *Change) { session.SetDegraded(true) },
+		Recover: func(context.Context, *Change) { session.SetDegraded(false) },
+	}
+}
+
+// disabled 被关闭的功能及关闭它的依赖数，多个依赖关闭同一功能时全部恢复后才重新开启
+var (
+	disabledMu sync.RWMutex
+	disabled   = make(map[string]int)
+)
+
+// DisableFeatures 功能开关降级策略：依赖不可用时关闭指定功能（见 FeatureEnabled、RequireFeature）
+func DisableFeatures(features ...string) Strategy {
+	return Strategy{
+		Name: "disable-features",
+		Degrade: func(context.Context, *Change) {
+			disabledMu.Lock()
+			defer disabledMu.Unlock()
+			for _, f := range features {
+				disabled[f]++
+			}
+		},
+		Recover: func(context.Context, *Change) {
+			disabledMu.Lock()
+			defer disabledMu.Unlock()
+			for _, f := range features {
+				if disabled[f]--; disabled[f] <= 0 {
+					delete(disabled, f)
+				}
+			}
+		},
+	}
+}
+
+// FeatureEnabled 功能是否可用（未被降级策略关闭），模板中可经 middleware.ViewData 共享给页面以隐藏入口
+func FeatureEnabled(feature string) bool {
+	disabledMu.RLock()
+	defer disabledMu.RUnlock()
+	return disabled[feature] == 0
+}
+
+// RequireFeature 功能被关闭时返回 503 的中间件：
+//
+//	r.POST("/checkout", resilience.RequireFeature("checkout"), orders.Checkout)
+func RequireFeature(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !FeatureEnabled(feature) {
+			c.Header("Retry-After", "30")
+			response.Fail(c, errors.New(errors.ServiceUnavailable, "该功能暂时不可用，请稍后再试", ErrUnavailable))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,189 @@
+// Package resource 提供 API 资源（数据转换）层：显式声明模型输出哪些字段，避免控制器直接输出 GORM 模型
+// （以及意外带出的密码哈希等敏感字段）。
+//
+//	var UserResource = resource.New(func(c *gin.Context, u *model.User) resource.Fields {
+//	    return resource.Fields{
+//	        "id":    u.ID,
+//	        "name":  u.Name,
+//	        "email": resource.When(isAdmin(c), u.Email), // 条件字段，不满足时不输出
+//	    }
+//	}).Include("posts", func(c *gin.Context, u *model.User, nested resource.Includes) (any, error) {
+//	    return PostResource.CollectionWith(c, u.Posts, nested) // 嵌套关联继续按 nested 展开
+//	})
+//
+//	data, err := UserResource.Item(c, user) // GET /users/1?include=posts.comments
+//
+// 关联只在请求以 ?include= 声明时输出，未注册的关联名被忽略。
+package resource
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IncludeParam 声明关联的查询参数
+const IncludeParam = "include"
+
+// MaxIncludeDepth 关联最大嵌套层数，超出的部分被忽略，防止客户端构造过深的查询
+const MaxIncludeDepth = 3
+
+// Fields 资源输出的字段
+type Fields map[string]any
+
+// missing When 条件不满足时的占位值，输出时移除对应字段
+type missing struct{}
+
+// When cond 为 true 时输出 value，否则不输出该字段
+func When(cond bool, value any) any {
+	if !cond {
+		return missing{}
+	}
+	return value
+}
+
+// WhenFunc 与 When 相同，但仅在 cond 为 true 时才计算值（适合开销较大的字段）
+func WhenFunc(cond bool, fn func() any) any {
+	if !cond {
+		return missing{}
+	}
+	return fn()
+}
+
+// Includes 请求声明的关联树，如 "posts.comments,profile" → {posts: {comments: {}}, profile: {}}
+type Includes map[string]Includes
+
+// ParseIncludes 解析逗号分隔、以 "." 表示嵌套的关联声明
+func ParseIncludes(s string) Includes {
+	inc := Includes{}
+	for _, path := range strings.Split(s, ",") {
+		node := inc
+		for i, name := range strings.Split(strings.TrimSpace(path), ".") {
+			if name == "" || i >= MaxIncludeDepth {
+				break
+			}
+			child, ok := node[name]
+			if !ok {
+				child = Includes{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	return inc
+}
+
+// Has 是否声明了指定关联
+func (inc Includes) Has(name string) bool {
+	_, ok := inc[name]
+	return ok
+}
+
+// Includer 输出关联数据，nested 为该关联下继续声明的嵌套关联
+type Includer[T any] func(c *gin.Context, v T, nested Includes) (any, error)
+
+// Resource 类型 T 的输出定义
+type Resource[T any] struct {
+	fields   func(c *gin.Context, v T) Fields
+	includes map[string]Includer[T]
+}
+
+// New 以字段映射函数创建资源
+func New[T any](fields func(c *gin.Context, v T) Fields) *Resource[T] {
+	return &Resource[T]{fields: fields, includes: make(map[string]Includer[T])}
+}
+
+// Include 注册可按需输出的关联，返回资源本身以便链式注册
+func (r *Resource[T]) Include(name string, fn Includer[T]) *Resource[T] {
+	r.includes[name] = fn
+	return r
+}
+
+// Item 转换单个对象，关联取自请求的 ?include= 参数
+func (r *Resource[T]) Item(c *gin.Context, v T) (Fields, error) {
+	return r.ItemWith(c, v, ParseIncludes(c.Query(IncludeParam)))
+}
+
+// ItemWith 按给定关联树转换单个对象，用于在 Includer 中输出嵌套资源
+func (r *Resource[T]) ItemWith(c *gin.Context, v T, inc Includes) (Fields, error) {
+	out := r.fields(c, v)
+	for k, val := range out {
+		if _, skip := val.(missing); skip {
+			delete(out, k)
+		}
+	}
+	for name, nested := range inc {
+		fn, ok := r.includes[name]
+		if !ok {
+			continue
+		}
+		val, err := fn(c, v, nested)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = val
+	}
+	return out, nil
+}
+
+// Collection 转换对象列表，关联取自请求的 ?include= 参数
+func (r *Resource[T]) Collection(c *gin.Context, items []T) ([]Fields, error) {
+	return r.CollectionWith(c, items, ParseIncludes(c.Query(IncludeParam)))
+}
+
+// CollectionWith 按给定关联树转换对象列表
+func (r *Resource[T]) CollectionWith(c *gin.Context, items []T, inc Includes) ([]Fields, error) {
+	out := make([]Fields, 0, len(items))
+	for _, v := range items {
+		f, err := r.ItemWith(c, v, inc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// Paginate 转换一页数据并附带分页信息：
+//
+//	{"items": [...], "meta": {"page": 2, "per_page": 20, "total": 53, "last_page": 3}}
+func (r *Resource[T]) Paginate(c *gin.Context, items []T, page Page) (*Paginated, error) {
+	list, err := r.Collection(c, items)
+	if err != nil {
+		return nil, err
+	}
+	return &Paginated{Items: list, Meta: page.meta()}, nil
+}
+
+// Page 分页参数与总数
+type Page struct {
+	Page    int   // 当前页，从 1 开始
+	PerPage int   // 每页条数
+	Total   int64 // 总条数
+}
+
+// meta 计算分页信息
+func (p Page) meta() PageMeta {
+	m := PageMeta{Page: p.Page, PerPage: p.PerPage, Total: p.Total}
+	if p.PerPage > 0 {
+		m.LastPage = int((p.Total + int64(p.PerPage) - 1) / int64(p.PerPage))
+	}
+	if m.LastPage < 1 {
+		m.LastPage = 1
+	}
+	return m
+}
+
+// Paginated 分页输出
+type Paginated struct {
+	Items []Fields `json:"items"`
+	Meta  PageMeta `json:"meta"`
+}
+
+// PageMeta 分页信息
+type PageMeta struct {
+	Page     int   `json:"page"`
+	PerPage  int   `json:"per_page"`
+	Total    int64 `json:"total"`
+	LastPage int   `json:"last_page"`
+}
@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type comment struct{ Body string }
+
+type post struct {
+	Title    string
+	Comments []comment
+}
+
+type user struct {
+	ID       uint
+	Name     string
+	Password string
+	Posts    []post
+}
+
+var commentResource = New(func(_ *gin.Context, c comment) Fields {
+	return Fields{"body": c.Body}
+})
+
+var postResource = New(func(_ *gin.Context, p post) Fields {
+	return Fields{"title": p.Title}
+}).Include("comments", func(c *gin.Context, p post, nested Includes) (any, error) {
+	return commentResource.CollectionWith(c, p.Comments, nested)
+})
+
+var userResource = New(func(c *gin.Context, u *user) Fields {
+	return Fields{
+		"id":    u.ID,
+		"name":  u.Name,
+		"admin": When(c.Query("admin") == "1", true),
+	}
+}).Include("posts", func(c *gin.Context, u *user, nested Includes) (any, error) {
+	return postResource.CollectionWith(c, u.Posts, nested)
+})
+
+func newContext(target string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+var alice = &user{ID: 1, Name: "alice", Password: "hash", Posts: []post{{Title: "p1", Comments: []comment{{Body: "c1"}}}}}
+
+// TestItem 只输出声明的字段，条件字段与关联按请求输出
+func TestItem(t *testing.T) {
+	tests := map[string]string{
+		"/":                              `{"id":1,"name":"alice"}`,
+		"/?admin=1":                      `{"admin":true,"id":1,"name":"alice"}`,
+		"/?include=posts":                `{"id":1,"name":"alice","posts":[{"title":"p1"}]}`,
+		"/?include=posts.comments,nope":  `{"id":1,"name":"alice","posts":[{"comments":[{"body":"c1"}],"title":"p1"}]}`,
+		"/?include=posts.comments.x.y.z": `{"id":1,"name":"alice","posts":[{"comments":[{"body":"c1"}],"title":"p1"}]}`,
+	}
+	for target, want := range tests {
+		got, err := userResource.Item(newContext(target), alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := toJSON(t, got); s != want {
+			t.Errorf("%s: %s, want %s", target, s, want)
+		}
+	}
+}
+
+// TestIncluderError 关联输出失败时返回错误
+func TestIncluderError(t *testing.T) {
+	boom := errors.New("boom")
+	r := New(func(_ *gin.Context, u *user) Fields { return Fields{"id": u.ID} }).
+		Include("posts", func(*gin.Context, *user, Includes) (any, error) { return nil, boom })
+	if _, err := r.Collection(newContext("/?include=posts"), []*user{alice}); !errors.Is(err, boom) {
+		t.Errorf("期望关联错误，得到 %v", err)
+	}
+}
+
+// TestParseIncludes 解析嵌套关联并限制深度
+func TestParseIncludes(t *testing.T) {
+	got := ParseIncludes(" posts.comments , profile,,a.b.c.d")
+	want := Includes{
+		"posts":   {"comments": {}},
+		"profile": {},
+		"a":       {"b": {"c": {}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseIncludes = %v", got)
+	}
+}
+
+// TestPaginate 分页输出附带分页信息
+func TestPaginate(t *testing.T) {
+	got, err := userResource.Paginate(newContext("/"), []*user{alice}, Page{Page: 2, PerPage: 20, Total: 41})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"items":[{"id":1,"name":"alice"}],"meta":{"page":2,"per_page":20,"total":41,"last_page":3}}`
+	if s := toJSON(t, got); s != want {
+		t.Errorf("%s, want %s", s, want)
+	}
+	if m := (Page{Page: 1, PerPage: 20}).meta(); m.LastPage != 1 {
+		t.Errorf("空列表 last_page = %d", m.LastPage)
+	}
+}
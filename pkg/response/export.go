@@ -0,0 +1,53 @@
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/export"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// 导出文件的 MIME 类型
+const (
+	MIMECSV  = "text/csv; charset=utf-8"
+	MIMEXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ExportCSV 以附件形式流式输出 CSV，表头按请求语言（request.Locale）翻译：
+//
+//	return response.ExportCSV(c, "用户.csv", export.New(export.Query[model.User](db.WithContext(c)), columns...))
+//
+// 开始输出前出错时返回 500 业务错误；已开始输出后出错（如查询中断、客户端断开）只能中断输出，
+// 错误记录到 c.Errors，客户端收到不完整的文件
+func ExportCSV(c *gin.Context, filename string, e export.Exporter, opts ...export.CSVOption) error {
+	attachment(c, filename, MIMECSV)
+	return exportResult(c, export.CSV(c.Request.Context(), c.Writer, e, request.Locale(c), opts...))
+}
+
+// ExportXLSX 以附件形式流式输出 XLSX，用法与出错处理同 ExportCSV
+func ExportXLSX(c *gin.Context, filename string, e export.Exporter, opts ...export.XLSXOption) error {
+	attachment(c, filename, MIMEXLSX)
+	return exportResult(c, export.XLSX(c.Request.Context(), c.Writer, e, request.Locale(c), opts...))
+}
+
+// attachment 设置附件下载的响应头
+func attachment(c *gin.Context, filename, contentType string) {
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", ContentDisposition("attachment", filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+}
+
+// exportResult 处理导出错误：尚未输出时撤销附件头并返回 500 错误，已输出时记录错误并中断
+func exportResult(c *gin.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		return errors.NewInternalServerError("导出失败", err)
+	}
+	_ = c.Error(err)
+	c.Abort()
+	return nil
+}
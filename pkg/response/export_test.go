@@ -0,0 +1,38 @@
+package response
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/export"
+)
+
+// TestExportCSV 附件响应头与内容；开始输出前出错时撤销附件头并返回 500 错误
+func TestExportCSV(t *testing.T) {
+	table := export.New(export.Values([]string{"a", "b"}), export.Col("名称", func(s string) any { return s }))
+	c, w := newContext(http.MethodGet, "/", "")
+	if err := ExportCSV(c, "用户.csv", table); err != nil {
+		t.Fatal(err)
+	}
+	if w.Header().Get("Content-Type") != MIMECSV || !strings.Contains(w.Header().Get("Content-Disposition"), "attachment") {
+		t.Errorf("响应头 %v", w.Header())
+	}
+	if got := w.Body.String(); got != "\ufeff名称\na\nb\n" {
+		t.Errorf("内容 %q", got)
+	}
+
+	failing := export.New(func(yield func(string, error) bool) { yield("", stderrors.New("db down")) },
+		export.Col("名称", func(s string) any { return s }))
+	c, w = newContext(http.MethodGet, "/", "")
+	err := ExportXLSX(c, "用户.xlsx", failing)
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.HTTPStatus() != http.StatusInternalServerError {
+		t.Fatalf("应返回 500 错误: %v", err)
+	}
+	if w.Header().Get("Content-Disposition") != "" {
+		t.Error("出错时应撤销附件头")
+	}
+}
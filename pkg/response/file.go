@@ -0,0 +1,157 @@
+package response
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// Download 以附件形式下载文件，支持 Range 分段（断点续传）与 If-Modified-Since 协商：
+//
+//	return response.Download(c, "storage/reports/2024.xlsx", "2024年报表.xlsx")
+//
+// filename 为空时取文件名；文件不存在或为目录时返回 404 错误
+func Download(c *gin.Context, path, filename string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.NewNotFound("文件不存在", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return errors.NewNotFound("文件不存在", err)
+	}
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	c.Header("Content-Disposition", ContentDisposition("attachment", filename))
+	// 按下载文件名的扩展名推断 Content-Type
+	http.ServeContent(writer(c), c.Request, filename, info.ModTime(), f)
+	return nil
+}
+
+// Stream 以内联形式输出流内容（如在浏览器中预览 PDF、播放音视频），调用方负责关闭 reader。
+// reader 实现 io.ReadSeeker（如 *os.File、*bytes.Reader）时支持 Range 分段，否则整体输出
+func Stream(c *gin.Context, reader io.Reader, contentType string) error {
+	c.Header("Content-Disposition", "inline")
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		http.ServeContent(writer(c), c.Request, "", time.Time{}, rs)
+		return nil
+	}
+	c.Status(http.StatusOK)
+	_, err := io.Copy(writer(c), reader)
+	return err
+}
+
+// MIMEPDF PDF 文档的 MIME 类型
+const MIMEPDF = "application/pdf"
+
+// PDF 输出生成的 PDF 文档（见 pdf.Render），inline 为 true 时在浏览器中直接打开，否则作为附件下载；
+// 文件名用于浏览器保存时的默认名称
+//
+//	doc, err := pdf.Render(c, "reports/invoice", data)
+//	if err != nil {
+//	    return err
+//	}
+//	response.PDF(c, "发票.pdf", doc, true)
+func PDF(c *gin.Context, filename string, doc []byte, inline bool) {
+	kind := "attachment"
+	if inline {
+		kind = "inline"
+	}
+	c.Header("Content-Disposition", ContentDisposition(kind, filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, MIMEPDF, doc)
+}
+
+// ContentDisposition 生成 Content-Disposition 头，非 ASCII 文件名按 RFC 5987 编码为 filename*，
+// 同时保留以 "_" 替换后的 filename 供不支持 filename* 的旧客户端使用
+func ContentDisposition(kind, filename string) string {
+	var fallback strings.Builder
+	ascii := true
+	for _, r := range filename {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			// 控制字符（含换行）一律丢弃，避免响应头注入
+			continue
+		case r > 0x7e:
+			ascii = false
+			fallback.WriteByte('_')
+		case r == '"' || r == '\\':
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+	v := kind + `; filename="` + fallback.String() + `"`
+	if !ascii {
+		v += "; filename*=UTF-8''" + encodeRFC5987(filename)
+	}
+	return v
+}
+
+// encodeRFC5987 按 RFC 5987 attr-char 对值做百分号编码
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if isAttrChar(ch) {
+			b.WriteByte(ch)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[ch>>4])
+		b.WriteByte(hex[ch&0x0f])
+	}
+	return b.String()
+}
+
+// isAttrChar RFC 5987 中无需编码的字符
+func isAttrChar(ch byte) bool {
+	switch {
+	case 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z', '0' <= ch && ch <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", ch) >= 0
+}
+
+// sendfileWriter 将 ReadFrom 交给底层 net/http 写入器，使文件内容经 sendfile 直接写入连接
+type sendfileWriter struct {
+	gin.ResponseWriter
+	rf io.ReaderFrom
+}
+
+// ReadFrom 实现 io.ReaderFrom（gin 的写入器未实现，io.Copy 只能逐块拷贝）
+func (w *sendfileWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.WriteHeaderNow()
+	return w.rf.ReadFrom(r)
+}
+
+// writer 返回输出文件使用的写入器：c.Writer 直接包装 net/http 写入器时启用 sendfile；
+// 被中间件（缓存、压缩、日志等）再次包装时原样返回，保证内容经过这些中间件处理
+func writer(c *gin.Context) http.ResponseWriter {
+	u, ok := c.Writer.(interface{ Unwrap() http.ResponseWriter })
+	if !ok {
+		return c.Writer
+	}
+	raw := u.Unwrap()
+	if _, wrapped := raw.(gin.ResponseWriter); wrapped {
+		return c.Writer
+	}
+	rf, ok := raw.(io.ReaderFrom)
+	if !ok {
+		return c.Writer
+	}
+	return &sendfileWriter{ResponseWriter: c.Writer, rf: rf}
+}
@@ -0,0 +1,121 @@
+package response
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestContentDisposition ASCII 文件名直接引用，中文文件名按 RFC 5987 编码
+func TestContentDisposition(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":      `attachment; filename="report.pdf"`,
+		`a"b\c.txt`:       `attachment; filename="a_b_c.txt"`,
+		"报表 2024.xlsx":    `attachment; filename="__ 2024.xlsx"; filename*=UTF-8''%E6%8A%A5%E8%A1%A8%202024.xlsx`,
+		"x\r\nSet: y.txt": `attachment; filename="xSet: y.txt"`,
+	}
+	for name, want := range tests {
+		if got := ContentDisposition("attachment", name); got != want {
+			t.Errorf("%q: %s, want %s", name, got, want)
+		}
+	}
+}
+
+// TestDownload 经真实 HTTP 服务下载（走 sendfile），支持 Range 分段，文件不存在返回错误
+func TestDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.GET("/file", func(c *gin.Context) {
+		if err := Download(c, path, "数据.txt"); err != nil {
+			t.Error(err)
+		}
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		if err := Download(c, path+".missing", ""); err == nil {
+			t.Error("文件不存在时应返回错误")
+		}
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "0123456789" || !strings.Contains(resp.Header.Get("Content-Disposition"), "filename*=UTF-8''%E6%95%B0%E6%8D%AE.txt") {
+		t.Errorf("下载 %q %q", body, resp.Header.Get("Content-Disposition"))
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/file", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "234" {
+		t.Errorf("Range 请求 %d %q", resp.StatusCode, body)
+	}
+
+	resp, err = http.Get(srv.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+// TestStream 可 Seek 的内容支持 Range，普通 Reader 整体输出
+func TestStream(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/", "")
+	c.Request.Header.Set("Range", "bytes=0-1")
+	if err := Stream(c, bytes.NewReader([]byte("hello")), "video/mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusPartialContent || w.Body.String() != "he" || w.Header().Get("Content-Type") != "video/mp4" {
+		t.Errorf("Range 输出 %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	if err := Stream(c, io.MultiReader(strings.NewReader("he"), strings.NewReader("llo")), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || w.Body.String() != "hello" || w.Header().Get("Content-Disposition") != "inline" {
+		t.Errorf("流输出 %d %q", w.Code, w.Body.String())
+	}
+}
+
+// TestPDF 内联或附件输出 PDF 文档
+func TestPDF(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/", "")
+	PDF(c, "invoice.pdf", []byte("%PDF-1.7"), true)
+	if w.Code != http.StatusOK || w.Body.String() != "%PDF-1.7" || w.Header().Get("Content-Type") != MIMEPDF {
+		t.Errorf("PDF 输出 %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `inline; filename="invoice.pdf"` {
+		t.Errorf("Content-Disposition = %s", got)
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	PDF(c, "invoice.pdf", []byte("%PDF-1.7"), false)
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="invoice.pdf"` {
+		t.Errorf("Content-Disposition = %s", got)
+	}
+}
@@ -0,0 +1,109 @@
+package response
+
+import (
+	"net/http"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+)
+
+// Envelope 将错误码、消息与数据包装为响应体，默认为 Response 结构
+type Envelope func(code int, message string, data any) any
+
+// defaultEnvelope 默认响应包装：{"code": ..., "message": ..., "data": ...}
+func defaultEnvelope(code int, message string, data any) any {
+	return Response{Code: code, Message: message, Data: data}
+}
+
+var currentEnvelope atomic.Value // Envelope
+
+// SetEnvelope 替换 Success、SuccessD、Fail、JSONP 与 Pretty 使用的响应包装，传入 nil 恢复默认。
+// 如不需要包装、直接输出数据：
+//
+//	response.SetEnvelope(func(_ int, _ string, data any) any { return data })
+func SetEnvelope(fn Envelope) {
+	if fn == nil {
+		fn = defaultEnvelope
+	}
+	currentEnvelope.Store(fn)
+}
+
+// envelope 按当前包装构建响应体
+func envelope(code int, message string, data any) any {
+	if fn, ok := currentEnvelope.Load().(Envelope); ok {
+		return fn(code, message, data)
+	}
+	return defaultEnvelope(code, message, data)
+}
+
+// CallbackParam JSONP 回调函数名的默认查询参数
+const CallbackParam = "callback"
+
+// maxCallbackLen 回调函数名最大长度
+const maxCallbackLen = 128
+
+// callbackPattern 合法的回调函数名：JS 标识符，允许以 "." 访问成员（如 jQuery.cb_1）
+var callbackPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)*$`)
+
+// ValidCallback 回调函数名是否合法，防止通过回调参数注入脚本
+func ValidCallback(name string) bool {
+	return len(name) <= maxCallbackLen && callbackPattern.MatchString(name)
+}
+
+// JSONP 以 JSONP 形式输出成功响应，回调函数名取自查询参数（默认 callback）：
+//
+//	response.JSONP(c, data)              // GET /api/x?callback=cb → /**/cb({...});
+//	response.JSONP(c, data, "jsonp")     // 自定义参数名
+//
+// 未携带回调参数时退化为普通 JSON，回调名不合法时返回 400
+func JSONP(c *gin.Context, data any, param ...string) {
+	name := CallbackParam
+	if len(param) > 0 && param[0] != "" {
+		name = param[0]
+	}
+	callback := c.Query(name)
+	if callback == "" {
+		Success(c, data)
+		return
+	}
+	if !ValidCallback(callback) {
+		Fail(c, errors.NewBadRequest("无效的回调函数名", nil))
+		return
+	}
+
+	body, err := jsonx.Marshal(envelope(errors.Success, "", data))
+	if err != nil {
+		Fail(c, errors.NewInternalServerError("响应编码失败", err))
+		return
+	}
+	// 前置注释防止响应被当作其他类型内容解析（Rosetta Flash），nosniff 禁止浏览器猜测类型
+	c.Header("X-Content-Type-Options", "nosniff")
+	buf := make([]byte, 0, len(body)+len(callback)+8)
+	buf = append(buf, "/**/"...)
+	buf = append(buf, callback...)
+	buf = append(buf, '(')
+	buf = append(buf, body...)
+	buf = append(buf, ");"...)
+	c.Data(http.StatusOK, "application/javascript; charset=utf-8", buf)
+}
+
+// Pretty 以缩进格式输出成功响应，便于调试时直接阅读；生产接口请使用 Success
+func Pretty(c *gin.Context, data any) {
+	body, err := jsonx.MarshalIndent(envelope(errors.Success, "", data), "", "    ")
+	if err != nil {
+		Fail(c, errors.NewInternalServerError("响应编码失败", err))
+		return
+	}
+	c.Data(http.StatusOK, binding.MIMEJSON+"; charset=utf-8", body)
+}
+
+// Raw 原样输出响应体（不做包装与编码），用于转发上游服务返回的内容：
+//
+//	response.Raw(c, resp.Header.Get("Content-Type"), body)
+func Raw(c *gin.Context, contentType string, data []byte) {
+	c.Data(http.StatusOK, contentType, data)
+}
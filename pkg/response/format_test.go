@@ -0,0 +1,69 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestJSONP 合法回调包装输出，非法回调拒绝，无回调退化为 JSON
+func TestJSONP(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/?callback=jQuery.cb_1", "")
+	JSONP(c, 1)
+	if got := w.Body.String(); got != `/**/jQuery.cb_1({"code":200,"message":"","data":1});` {
+		t.Errorf("JSONP 输出 %q", got)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/javascript") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	for _, cb := range []string{"alert(1)//", "a-b", "1cb", "a..b", strings.Repeat("a", 129)} {
+		c, w := newContext(http.MethodGet, "/?cb="+cb, "")
+		JSONP(c, 1, "cb")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("回调 %q 应被拒绝，得到 %d", cb, w.Code)
+		}
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	JSONP(c, 1)
+	if got := w.Body.String(); got != `{"code":200,"message":"","data":1}` {
+		t.Errorf("无回调时应输出 JSON，得到 %q", got)
+	}
+}
+
+// TestSetEnvelope 自定义包装作用于 Success 与 Fail，nil 恢复默认
+func TestSetEnvelope(t *testing.T) {
+	SetEnvelope(func(_ int, _ string, data any) any { return gin.H{"result": data} })
+	defer SetEnvelope(nil)
+
+	c, w := newContext(http.MethodGet, "/", "")
+	Success(c, 1)
+	if got := w.Body.String(); got != `{"result":1}` {
+		t.Errorf("自定义包装输出 %q", got)
+	}
+
+	SetEnvelope(nil)
+	c, w = newContext(http.MethodGet, "/", "")
+	Success(c, 1)
+	if got := w.Body.String(); got != `{"code":200,"message":"","data":1}` {
+		t.Errorf("默认包装输出 %q", got)
+	}
+}
+
+// TestPrettyAndRaw 缩进输出与原样输出
+func TestPrettyAndRaw(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/", "")
+	Pretty(c, 1)
+	if !strings.Contains(w.Body.String(), "\n    \"code\": 200") {
+		t.Errorf("应缩进输出: %q", w.Body.String())
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	Raw(c, "application/xml", []byte("<a/>"))
+	if w.Body.String() != "<a/>" || w.Header().Get("Content-Type") != "application/xml" {
+		t.Errorf("原样输出 %q %q", w.Header().Get("Content-Type"), w.Body.String())
+	}
+}
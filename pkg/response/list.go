@@ -0,0 +1,78 @@
+package response
+
+import (
+	"bufio"
+	"bytes"
+	"iter"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+)
+
+// streamBufferSize 流式输出的写缓冲大小
+const streamBufferSize = 32 << 10
+
+// streamToken 编码包装时代替数据的占位符，输出时在此处写入逐个编码的列表
+const streamToken = `"__response_stream__"`
+
+// streamPlaceholder 编码为 streamToken 的占位数据
+type streamPlaceholder struct{}
+
+// MarshalJSON 实现 json.Marshaler
+func (streamPlaceholder) MarshalJSON() ([]byte, error) {
+	return []byte(streamToken), nil
+}
+
+// StreamList 以流式编码输出列表数据的成功响应：先写出响应包装中数据之前的部分，再逐个编码 items 的元素，
+// 整个列表无需先收集为切片、也不会整体编码到内存，适合导出、全量同步等超大列表：
+//
+//	response.StreamList(c, slices.Values(rows))
+//	response.StreamList(c, func(yield func(model.Order) bool) {
+//	    // 分批查询，逐条 yield
+//	})
+//
+// 输出与 Success(c, []T{...}) 相同（遵循 SetEnvelope），始终为 JSON，不参与内容协商。
+// 响应头写出后元素编码失败只能中断输出（客户端收到不完整的 JSON），错误记录到 c.Errors
+func StreamList[T any](c *gin.Context, items iter.Seq[T]) {
+	head, err := jsonx.Marshal(envelope(errors.Success, "", streamPlaceholder{}))
+	if err != nil {
+		Fail(c, errors.NewInternalServerError("响应编码失败", err))
+		return
+	}
+	// 自定义包装未包含数据时 tail 为空，只输出包装本身
+	var tail []byte
+	if i := bytes.Index(head, []byte(streamToken)); i >= 0 {
+		head, tail = head[:i], head[i+len(streamToken):]
+	} else {
+		items = nil
+	}
+
+	c.Header("Content-Type", binding.MIMEJSON+"; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := bufio.NewWriterSize(c.Writer, streamBufferSize)
+	defer w.Flush()
+
+	_, _ = w.Write(head)
+	if items == nil {
+		return
+	}
+	_ = w.WriteByte('[')
+	enc := jsonx.NewEncoder(w)
+	first := true
+	for item := range items {
+		if !first {
+			_ = w.WriteByte(',')
+		}
+		first = false
+		if err := enc.Encode(item); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+	}
+	_ = w.WriteByte(']')
+	_, _ = w.Write(tail)
+}
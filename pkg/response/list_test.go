@@ -0,0 +1,38 @@
+package response
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestStreamList 流式输出与 Success 输出的 JSON 一致，遵循自定义包装
+func TestStreamList(t *testing.T) {
+	items := []gin.H{{"id": 1}, {"id": 2}}
+
+	c, w := newContext(http.MethodGet, "/", "")
+	StreamList(c, slices.Values(items))
+	want := "{\"code\":200,\"message\":\"\",\"data\":[{\"id\":1}\n,{\"id\":2}\n]}"
+	if got := w.Body.String(); got != want {
+		t.Errorf("流式输出 %q，期望 %q", got, want)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	StreamList(c, slices.Values([]int{}))
+	if got := w.Body.String(); got != `{"code":200,"message":"","data":[]}` {
+		t.Errorf("空列表输出 %q", got)
+	}
+
+	SetEnvelope(func(_ int, _ string, data any) any { return data })
+	defer SetEnvelope(nil)
+	c, w = newContext(http.MethodGet, "/", "")
+	StreamList(c, slices.Values([]int{1, 2}))
+	if got := w.Body.String(); got != "[1\n,2\n]" {
+		t.Errorf("无包装时输出 %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+package response
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+	"github.com/ugorji/go/codec"
+)
+
+// 统一响应支持的输出格式
+const (
+	FormatJSON    = "json"
+	FormatXML     = "xml"
+	FormatMsgpack = "msgpack"
+)
+
+// formatMIMEs 各格式可接受的 MIME 类型，JSON 排在最前（同等优先级时作为默认格式）
+var formatMIMEs = []struct {
+	format string
+	mimes  []string
+}{
+	{FormatJSON, []string{binding.MIMEJSON}},
+	{FormatXML, []string{binding.MIMEXML, binding.MIMEXML2}},
+	{FormatMsgpack, []string{binding.MIMEMSGPACK, binding.MIMEMSGPACK2}},
+}
+
+var negotiation atomic.Bool
+
+// SetNegotiation 开启后 Success、SuccessD 与 Fail 按请求 Accept 头选择 JSON、XML 或 MessagePack 输出，
+// 关闭时（默认）始终输出 JSON。路由初始化时按 server.content_negotiation 配置设置
+func SetNegotiation(enabled bool) {
+	negotiation.Store(enabled)
+}
+
+// NegotiateFormat 按 Accept 头（含 q 权重）选择输出格式，未声明或无可接受格式时返回 FormatJSON
+func NegotiateFormat(c *gin.Context) string {
+	accept := c.GetHeader("Accept")
+	if accept == "" {
+		return FormatJSON
+	}
+	best, bestQ, bestExact := FormatJSON, -1.0, false
+	for _, f := range formatMIMEs {
+		q, exact := acceptQuality(accept, f.mimes)
+		// 权重更高，或权重相同但为精确匹配（而非通配）时胜出
+		if q > bestQ || (q == bestQ && exact && !bestExact) {
+			best, bestQ, bestExact = f.format, q, exact
+		}
+	}
+	if bestQ <= 0 {
+		return FormatJSON
+	}
+	return best
+}
+
+// acceptQuality 返回 Accept 头对给定 MIME 类型的最高权重，exact 表示由精确类型（非通配）匹配
+func acceptQuality(accept string, mimes []string) (q float64, exact bool) {
+	q = -1
+	for _, part := range strings.Split(accept, ",") {
+		typ, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		weight := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					weight = f
+				}
+			}
+		}
+		for _, m := range mimes {
+			isExact := typ == m
+			if !isExact && typ != "*/*" && typ != m[:strings.IndexByte(m, '/')]+"/*" {
+				continue
+			}
+			if weight > q || (weight == q && isExact) {
+				q, exact = weight, isExact
+			}
+		}
+	}
+	return q, exact
+}
+
+// XML 以 XML 输出成功响应
+func XML(c *gin.Context, data any) {
+	writeFormat(c, FormatXML, http.StatusOK, envelope(errors.Success, "", data))
+}
+
+// Msgpack 以 MessagePack 输出成功响应
+func Msgpack(c *gin.Context, data any) {
+	writeFormat(c, FormatMsgpack, http.StatusOK, envelope(errors.Success, "", data))
+}
+
+// write 输出统一响应，开启内容协商时按 Accept 头选择格式
+func write(c *gin.Context, status int, body any) {
+	format := FormatJSON
+	if negotiation.Load() {
+		format = NegotiateFormat(c)
+	}
+	writeFormat(c, format, status, body)
+}
+
+// writeFormat 按指定格式输出，编码失败（如 XML 不支持 map 类型的数据）时回退为 JSON
+func writeFormat(c *gin.Context, format string, status int, body any) {
+	switch format {
+	case FormatXML:
+		if b, err := xml.Marshal(body); err == nil {
+			c.Data(status, binding.MIMEXML+"; charset=utf-8", append([]byte(xml.Header), b...))
+			return
+		}
+	case FormatMsgpack:
+		var buf bytes.Buffer
+		if err := codec.NewEncoder(&buf, msgpackHandle).Encode(body); err == nil {
+			c.Data(status, binding.MIMEMSGPACK2, buf.Bytes())
+			return
+		}
+	}
+	writeJSON(c, status, body)
+}
+
+// writeJSON 使用当前 JSON 引擎（jsonx）编码输出，编码失败时返回 500 并记录到 c.Errors
+func writeJSON(c *gin.Context, status int, body any) {
+	b, err := jsonx.Marshal(body)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, binding.MIMEJSON+"; charset=utf-8", b)
+}
+
+// msgpackHandle MessagePack 编码配置，结构体字段名取自 json 标签，与 JSON 输出一致
+var msgpackHandle = &codec.MsgpackHandle{}
@@ -0,0 +1,86 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/ugorji/go/codec"
+)
+
+// TestNegotiateFormat 按 q 权重选择格式，同权重时精确类型优于通配，默认 JSON
+func TestNegotiateFormat(t *testing.T) {
+	tests := map[string]string{
+		"":                      FormatJSON,
+		"*/*":                   FormatJSON,
+		"application/xml":       FormatXML,
+		"text/xml, */*;q=0.1":   FormatXML,
+		"application/x-msgpack": FormatMsgpack,
+		"application/json;q=0.5, application/xml": FormatXML,
+		"application/xml;q=0.5, application/json": FormatJSON,
+		"*/*, application/msgpack":                FormatMsgpack,
+		"text/html":                               FormatJSON,
+	}
+	for accept, want := range tests {
+		c, _ := newContext(http.MethodGet, "/", "")
+		c.Request.Header.Set("Accept", accept)
+		if got := NegotiateFormat(c); got != want {
+			t.Errorf("Accept %q: %s, want %s", accept, got, want)
+		}
+	}
+}
+
+// TestNegotiation 开启协商后 Success 与 Fail 按 Accept 输出，XML 无法编码时回退 JSON
+func TestNegotiation(t *testing.T) {
+	SetNegotiation(true)
+	defer SetNegotiation(false)
+
+	c, w := newContext(http.MethodGet, "/", "")
+	c.Request.Header.Set("Accept", "application/xml")
+	Fail(c, errors.NewNotFound("用户不存在", nil))
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "<response><code>404</code>") {
+		t.Errorf("XML 输出 %d %s", w.Code, w.Body.String())
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	c.Request.Header.Set("Accept", "application/xml")
+	Success(c, map[string]any{"id": 1})
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("map 数据应回退为 JSON，得到 %q", ct)
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	c.Request.Header.Set("Accept", "application/x-msgpack")
+	Success(c, map[string]any{"id": 1})
+	var got map[string]any
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got["code"] != uint64(200) && got["code"] != int64(200) {
+		t.Errorf("MessagePack 输出 %v", got)
+	}
+
+	SetNegotiation(false)
+	c, w = newContext(http.MethodGet, "/", "")
+	c.Request.Header.Set("Accept", "application/xml")
+	Success(c, 1)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("关闭协商时应输出 JSON，得到 %q", ct)
+	}
+}
+
+// TestExplicitFormats XML 与 Msgpack 显式输出
+func TestExplicitFormats(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/", "")
+	XML(c, "ok")
+	if !strings.HasSuffix(w.Body.String(), "<response><code>200</code><message></message><data>ok</data></response>") {
+		t.Errorf("XML 输出 %s", w.Body.String())
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	Msgpack(c, "ok")
+	if w.Header().Get("Content-Type") != "application/msgpack" || w.Body.Len() == 0 {
+		t.Errorf("MessagePack 输出 %q %d", w.Header().Get("Content-Type"), w.Body.Len())
+	}
+}
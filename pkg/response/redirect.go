@@ -0,0 +1,149 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	playground "github.com/go-playground/validator/v10"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+// URLBuilder 按路由名称与参数生成 URL
+type URLBuilder func(name string, params ...map[string]any) (string, error)
+
+// urlBuilder 由 router 包在初始化时注册（router 依赖 response，不能反向导入）
+var urlBuilder URLBuilder
+
+// SetURLBuilder 设置 RedirectRoute 使用的 URL 生成函数，router 包已自动注册 router.BuildUrl
+func SetURLBuilder(fn URLBuilder) {
+	urlBuilder = fn
+}
+
+// RedirectRoute 重定向到命名路由，路由不存在或缺少参数时返回错误：
+//
+//	return response.RedirectRoute(c, "user@show", map[string]any{"id": user.ID})
+func RedirectRoute(c *gin.Context, name string, params map[string]any, status ...int) error {
+	if urlBuilder == nil {
+		return fmt.Errorf("未注册路由 URL 生成函数")
+	}
+	target, err := urlBuilder(name, params)
+	if err != nil {
+		return err
+	}
+	Redirect(c, target, status...)
+	return nil
+}
+
+// RedirectBack 重定向回来源页面（Referer），来源缺失或不是本站地址时重定向到 fallback（默认 "/"），
+// 避免被构造的 Referer 利用为开放重定向
+func RedirectBack(c *gin.Context, fallback ...string) {
+	target := "/"
+	if len(fallback) > 0 && fallback[0] != "" {
+		target = fallback[0]
+	}
+	if back := sameOriginReferer(c); back != "" {
+		target = back
+	}
+	Redirect(c, target, http.StatusFound)
+}
+
+// RedirectWith 闪存一条消息后重定向，目标页面通过 session.GetFlash(c, flashKey) 读取：
+//
+//	response.RedirectWith(c, "/users", "success", "保存成功")
+func RedirectWith(c *gin.Context, target, flashKey string, msg any) error {
+	if err := session.SetFlash(c, flashKey, msg); err != nil {
+		return err
+	}
+	Redirect(c, target, http.StatusFound)
+	return nil
+}
+
+// WithInput 闪存本次提交的表单内容（排除密码类字段），重定向后的页面可回填：
+// 模板中 {{ .Old.Get "email" }}（经 template.WithContext 注入），或 session.OldInput(c)
+//
+//	if err := validate(form); err != nil {
+//	    response.WithInput(c)
+//	    return response.RedirectWith(c, "/register", "error", err.Error())
+//	}
+func WithInput(c *gin.Context) error {
+	if err := c.Request.ParseForm(); err != nil {
+		return err
+	}
+	input := url.Values{}
+	for k, v := range c.Request.PostForm {
+		if isSensitiveField(k) {
+			continue
+		}
+		input[k] = v
+	}
+	return session.FlashInput(c, input)
+}
+
+// WithErrors 闪存字段校验错误，重定向后的页面中表单辅助函数（{{ input . "email" }} 等）
+// 会在对应字段旁展示错误，也可通过 {{ index .Errors "email" }} 读取。
+// 支持 request.BindAny 返回的 validator.FieldErrors（以请求参数名为键）与
+// go-playground 的 ValidationErrors（以字段名为键）；其他错误不闪存，请配合 RedirectWith 展示
+//
+//	if err := request.BindAny(c, &form); err != nil {
+//	    response.WithInput(c)
+//	    response.WithErrors(c, err)
+//	    return response.RedirectWith(c, "/register", "error", "请检查表单")
+//	}
+func WithErrors(c *gin.Context, err error) error {
+	errs := map[string]string{}
+	var fes validator.FieldErrors
+	var verrs playground.ValidationErrors
+	switch {
+	case errors.As(err, &fes):
+		for _, fe := range fes {
+			key := fe.Key
+			if key == "" {
+				key = fe.Field
+			}
+			if _, exists := errs[key]; !exists {
+				errs[key] = fe.Message
+			}
+		}
+	case errors.As(err, &verrs):
+		for _, fe := range verrs {
+			if _, exists := errs[fe.Field()]; !exists {
+				errs[fe.Field()] = fe.Error()
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return session.FlashErrors(c, errs)
+}
+
+// sameOriginReferer 返回同源的 Referer（仅保留路径与查询），否则返回空字符串
+func sameOriginReferer(c *gin.Context) string {
+	ref := c.Request.Referer()
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Host != c.Request.Host {
+		return ""
+	}
+	back := u.EscapedPath()
+	if back == "" {
+		back = "/"
+	}
+	if u.RawQuery != "" {
+		back += "?" + u.RawQuery
+	}
+	return back
+}
+
+// isSensitiveField 密码、令牌类字段不回填
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "password") || strings.Contains(name, "token") || name == "_csrf"
+}
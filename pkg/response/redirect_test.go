@@ -0,0 +1,134 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/validator"
+)
+
+func newContext(method, target string, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return c, w
+}
+
+// TestRedirectBack 仅跟随同源 Referer
+func TestRedirectBack(t *testing.T) {
+	cases := []struct {
+		referer, fallback, want string
+	}{
+		{"http://example.com/users?page=2", "", "/users?page=2"},
+		{"http://evil.com/phish", "", "/"},
+		{"http://evil.com/phish", "/home", "/home"},
+		{"", "/home", "/home"},
+	}
+	for _, tc := range cases {
+		c, w := newContext(http.MethodPost, "http://example.com/users", "")
+		c.Request.Header.Set("Referer", tc.referer)
+		RedirectBack(c, tc.fallback)
+		if got := w.Header().Get("Location"); c.Writer.Status() != http.StatusFound || got != tc.want {
+			t.Errorf("Referer %q: %d %q, want %q", tc.referer, c.Writer.Status(), got, tc.want)
+		}
+	}
+}
+
+// TestRedirectRoute 通过注册的 URL 生成函数重定向到命名路由
+func TestRedirectRoute(t *testing.T) {
+	prev := urlBuilder
+	defer SetURLBuilder(prev)
+	SetURLBuilder(func(name string, params ...map[string]any) (string, error) {
+		return "/users/" + params[0]["id"].(string), nil
+	})
+
+	c, w := newContext(http.MethodPost, "/users", "")
+	if err := RedirectRoute(c, "user@show", map[string]any{"id": "7"}, http.StatusSeeOther); err != nil {
+		t.Fatal(err)
+	}
+	if c.Writer.Status() != http.StatusSeeOther || w.Header().Get("Location") != "/users/7" {
+		t.Errorf("%d %q", c.Writer.Status(), w.Header().Get("Location"))
+	}
+}
+
+// TestRedirectWithInput 闪存消息与表单输入（不含密码），下一次请求可读取
+func TestRedirectWithInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(session.Start(&config.SessionConfig{Store: "cookie", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}, nil, nil))
+	r.POST("/register", func(c *gin.Context) {
+		if err := WithInput(c); err != nil {
+			t.Error(err)
+		}
+		if err := RedirectWith(c, "/register", "error", "邮箱已被注册"); err != nil {
+			t.Error(err)
+		}
+	})
+	r.GET("/register", func(c *gin.Context) {
+		msg, _ := session.GetFlash(c, "error")
+		old := session.OldInput(c)
+		c.String(http.StatusOK, "%v|%s|%s", msg, old.Get("email"), old.Get("password"))
+	})
+
+	w := httptest.NewRecorder()
+	form := url.Values{"email": {"a@example.com"}, "password": {"secret"}}
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/register" {
+		t.Fatalf("%d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// 每次保存会话都会写出 Set-Cookie，与浏览器一致以最后一个为准
+	req = httptest.NewRequest(http.MethodGet, "/register", nil)
+	cookies := w.Result().Cookies()
+	req.AddCookie(cookies[len(cookies)-1])
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != "邮箱已被注册|a@example.com|" {
+		t.Errorf("重定向后页面 = %q", w.Body.String())
+	}
+}
+
+// TestWithErrors 闪存 BindAny 的字段错误，以请求参数名为键
+func TestWithErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(session.Start(&config.SessionConfig{Store: "cookie", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}, nil, nil))
+	r.POST("/register", func(c *gin.Context) {
+		err := errors.NewValidationError("invalid", validator.FieldErrors{
+			{Field: "Email", Key: "email", Tag: "email", Message: "邮箱格式不正确"},
+			{Field: "Age", Tag: "type", Message: "须为整数"},
+		})
+		if err := WithErrors(c, err); err != nil {
+			t.Error(err)
+		}
+		Redirect(c, "/register", http.StatusFound)
+	})
+	r.GET("/register", func(c *gin.Context) {
+		errs := session.Errors(c)
+		c.String(http.StatusOK, "%s|%s", errs["email"], errs["Age"])
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/register", nil))
+	req := httptest.NewRequest(http.MethodGet, "/register", nil)
+	cookies := w.Result().Cookies()
+	req.AddCookie(cookies[len(cookies)-1])
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != "邮箱格式不正确|须为整数" {
+		t.Errorf("重定向后页面 = %q", w.Body.String())
+	}
+}
@@ -1,50 +1,37 @@
 package response
 
 import (
+	"encoding/xml"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
 )
 
 // Response 统一响应结构
 type Response struct {
-	Code    int    `json:"code"`    // 错误码
-	Message string `json:"message"` // 响应消息
-	Data    any    `json:"data"`    // 响应数据
+	XMLName xml.Name `json:"-" xml:"response"`
+	Code    int      `json:"code" xml:"code"`       // 错误码
+	Message string   `json:"message" xml:"message"` // 响应消息
+	Data    any      `json:"data" xml:"data"`       // 响应数据
 }
 
 // Success 成功响应
 func Success(c *gin.Context, data any) {
-	resp := Response{
-		Code:    errors.Success,
-		Message: "",
-		Data:    data,
-	}
-	c.JSON(http.StatusOK, resp)
+	write(c, http.StatusOK, envelope(errors.Success, "", data))
 }
 
 // SuccessWithDetail 带详细信息的成功响应
 func SuccessD(c *gin.Context, detail string, data any) {
-	resp := Response{
-		Code:    errors.Success,
-		Message: detail,
-		Data:    data,
-	}
-	c.JSON(http.StatusOK, resp)
+	write(c, http.StatusOK, envelope(errors.Success, detail, data))
 }
 
-// Fail 失败响应
+// Fail 失败响应，消息按当前请求语言（request.Locale）翻译
 func Fail(c *gin.Context, err *errors.AppError) {
-	// 构建响应
-	resp := Response{
-		Code:    err.Code,
-		Message: err.Message,
-		Data:    err.Detail,
-	}
-
-	// 返回响应
-	c.JSON(err.HTTPStatus(), resp)
+	write(c, err.HTTPStatus(), envelope(err.Code, err.Localize(request.Locale(c)), err.Detail))
 	c.Abort()
 }
 
@@ -67,6 +54,26 @@ func Redirect(c *gin.Context, url string, status ...int) {
 	c.Abort()
 }
 
+// Deprecation 为响应写入 API 弃用相关头。
+//
+//   - Deprecation（RFC 9745）：since 非零时写入结构化日期 "@<unix 秒>"；
+//     弃用时间未知（零值）时退化为早期草案的 "true"，兼容旧客户端
+//   - Sunset（RFC 8594）：sunset 非零时写入下线时间
+//   - Link：link 非空时追加 rel="deprecation" 链接，不覆盖已有 Link 头（如分页链接）
+func Deprecation(c *gin.Context, since, sunset time.Time, link string) {
+	if since.IsZero() {
+		c.Header("Deprecation", "true")
+	} else {
+		c.Header("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+	}
+	if !sunset.IsZero() {
+		c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+	if link != "" {
+		c.Writer.Header().Add("Link", "<"+link+">; rel=\"deprecation\"")
+	}
+}
+
 func BadRequest(c *gin.Context) {
 	Fail(c, errors.NewBadRequest("无效请求", nil))
 }
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// TestFailLocalized 错误消息按请求语言输出，未设置语言时保持默认中文
+func TestFailLocalized(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/", "")
+	Fail(c, errors.NewNotFound("用户不存在", nil))
+	if !strings.Contains(w.Body.String(), `"message":"资源不存在"`) {
+		t.Errorf("默认语言: %s", w.Body.String())
+	}
+
+	c, w = newContext(http.MethodGet, "/", "")
+	request.Set(c, request.KeyLocale, "en")
+	Fail(c, errors.NewNotFound("用户不存在", nil))
+	if !strings.Contains(w.Body.String(), `"message":"Not found"`) {
+		t.Errorf("en: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,263 @@
+// Package routegen 根据控制器方法上的注释生成 Annotation(rb) 路由注册代码，适用于控制器较多、手写注册繁琐的场景。
+//
+// 在类型注释中声明路由组（可选），在方法注释中声明路由：
+//
+//	// UserController 用户管理
+//	// @group /users
+//	type UserController struct{ controller.Base }
+//
+//	// Show 用户详情
+//	// @route GET /:id name=user@show id=int|min:1
+//	func (u *UserController) Show(c *gin.Context) error { ... }
+//
+// @route 格式为 "方法 路径 [name=路由名] [参数=规则...]"，除 name 外的 key=value 均作为 router.Rules。
+// 执行 `app route:gen app/controller` 后在目录下生成 routes_gen.go，已手写 Annotation 的控制器会报错提示冲突。
+package routegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OutputFile 生成的文件名
+const OutputFile = "routes_gen.go"
+
+// methods 支持的请求方法（与 RouteBuilder 的注册方法对应）
+var methods = map[string]string{
+	"GET": "GET", "POST": "POST", "PUT": "PUT", "DELETE": "DELETE",
+	"PATCH": "PATCH", "HEAD": "HEAD", "OPTIONS": "OPTIONS", "ANY": "ANY",
+}
+
+// Route 从注释解析出的路由声明
+type Route struct {
+	Method  string
+	Path    string
+	Name    string
+	Handler string
+	Rules   map[string]string
+}
+
+// Controller 带路由声明的控制器
+type Controller struct {
+	Type     string
+	Receiver string
+	Group    string
+	Routes   []Route
+}
+
+// Parse 解析目录下（不含测试与已生成文件）带 @route 注释的控制器，按类型名排序
+func Parse(dir string) (pkg string, controllers []*Controller, err error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	byType := make(map[string]*Controller)
+	groups := make(map[string]string)
+	annotated := make(map[string]bool)
+	for _, path := range files {
+		base := filepath.Base(path)
+		if strings.HasSuffix(base, "_test.go") || base == OutputFile {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					doc := ts.Doc
+					if doc == nil && len(d.Specs) == 1 {
+						doc = d.Doc
+					}
+					if g, ok := directive(doc, "@group"); ok {
+						groups[ts.Name.Name] = g
+					}
+				}
+			case *ast.FuncDecl:
+				typ, recv := receiver(d)
+				if typ == "" {
+					continue
+				}
+				if d.Name.Name == "Annotation" {
+					annotated[typ] = true
+					continue
+				}
+				for _, line := range directives(d.Doc, "@route") {
+					route, err := parseRoute(line)
+					if err != nil {
+						return "", nil, fmt.Errorf("%s: %s.%s: %w", fset.Position(d.Pos()), typ, d.Name.Name, err)
+					}
+					route.Handler = d.Name.Name
+					ctl, ok := byType[typ]
+					if !ok {
+						ctl = &Controller{Type: typ, Receiver: recv}
+						byType[typ] = ctl
+					}
+					ctl.Routes = append(ctl.Routes, route)
+				}
+			}
+		}
+	}
+
+	for typ, ctl := range byType {
+		if annotated[typ] {
+			return "", nil, fmt.Errorf("%s 已手写 Annotation 方法，不能再使用 @route 注释生成", typ)
+		}
+		ctl.Group = groups[typ]
+		controllers = append(controllers, ctl)
+	}
+	sort.Slice(controllers, func(i, j int) bool { return controllers[i].Type < controllers[j].Type })
+	return pkg, controllers, nil
+}
+
+// Generate 生成路由注册代码，无 @route 声明时返回 nil
+func Generate(dir string) ([]byte, error) {
+	pkg, controllers, err := Parse(dir)
+	if err != nil || len(controllers) == 0 {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by route:gen; DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	b.WriteString("import \"github.com/gorilla-go/go-framework/pkg/router\"\n")
+	for _, ctl := range controllers {
+		recv := ctl.Receiver
+		if recv == "" || recv == "_" {
+			recv = "ctl"
+		}
+		fmt.Fprintf(&b, "\n// Annotation 由 @route 注释生成\nfunc (%s *%s) Annotation(rb *router.RouteBuilder) {\n", recv, ctl.Type)
+		target := "rb"
+		if ctl.Group != "" {
+			fmt.Fprintf(&b, "g := rb.Group(%s)\n", strconv.Quote(ctl.Group))
+			target = "g"
+		}
+		for _, r := range ctl.Routes {
+			fmt.Fprintf(&b, "%s.%s(%s, %s.%s, %s", target, r.Method, strconv.Quote(r.Path), recv, r.Handler, strconv.Quote(r.Name))
+			if len(r.Rules) > 0 {
+				keys := make([]string, 0, len(r.Rules))
+				for k := range r.Rules {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				b.WriteString(", router.Rules{")
+				for i, k := range keys {
+					if i > 0 {
+						b.WriteString(", ")
+					}
+					fmt.Fprintf(&b, "%s: %s", strconv.Quote(k), strconv.Quote(r.Rules[k]))
+				}
+				b.WriteString("}")
+			}
+			b.WriteString(")\n")
+		}
+		b.WriteString("}\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// Write 生成并写入 dir/routes_gen.go，无 @route 声明时删除旧的生成文件；返回写入的文件路径（未写入时为空）
+func Write(dir string) (string, error) {
+	src, err := Generate(dir)
+	if err != nil {
+		return "", err
+	}
+	out := filepath.Join(dir, OutputFile)
+	if src == nil {
+		if err := os.Remove(out); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		return "", nil
+	}
+	return out, os.WriteFile(out, src, 0644)
+}
+
+// parseRoute 解析 "GET /:id name=user@show id=int|min:1"
+func parseRoute(line string) (Route, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return Route{}, fmt.Errorf("@route 格式应为 \"方法 路径 [name=路由名] [参数=规则...]\": %q", line)
+	}
+	method, ok := methods[strings.ToUpper(fields[0])]
+	if !ok {
+		return Route{}, fmt.Errorf("不支持的请求方法: %s", fields[0])
+	}
+	r := Route{Method: method, Path: fields[1]}
+	for _, f := range fields[2:] {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || k == "" {
+			return Route{}, fmt.Errorf("无效的选项: %q", f)
+		}
+		if k == "name" {
+			r.Name = v
+			continue
+		}
+		if r.Rules == nil {
+			r.Rules = make(map[string]string)
+		}
+		r.Rules[k] = v
+	}
+	return r, nil
+}
+
+// receiver 返回方法接收者的类型名与变量名，非方法时类型名为空
+func receiver(fn *ast.FuncDecl) (typ, name string) {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return "", ""
+	}
+	field := fn.Recv.List[0]
+	expr := field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	if len(field.Names) > 0 {
+		name = field.Names[0].Name
+	}
+	return ident.Name, name
+}
+
+// directive 返回注释中第一条指定指令的内容
+func directive(doc *ast.CommentGroup, name string) (string, bool) {
+	list := directives(doc, name)
+	if len(list) == 0 {
+		return "", false
+	}
+	return list[0], true
+}
+
+// directives 返回注释中所有指定指令的内容，如 "// @route GET /" → "GET /"
+func directives(doc *ast.CommentGroup, name string) []string {
+	if doc == nil {
+		return nil
+	}
+	var list []string
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if rest, ok := strings.CutPrefix(text, name+" "); ok {
+			list = append(list, strings.TrimSpace(rest))
+		}
+	}
+	return list
+}
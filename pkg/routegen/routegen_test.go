@@ -0,0 +1,112 @@
+package routegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const controllerSrc = `package controller
+
+import "github.com/gin-gonic/gin"
+
+// UserController 用户
+// @group /users
+type UserController struct{}
+
+// List 用户列表
+// @route GET / name=user@list page=int|min:1
+func (u *UserController) List(c *gin.Context) error { return nil }
+
+// Show 用户详情
+// @route GET /:id name=user@show id=int|min:1
+// @route HEAD /:id name=user@head
+func (u *UserController) Show(c *gin.Context) error { return nil }
+
+// helper 无 @route 注释的方法不生成
+func (u *UserController) helper() {}
+
+type PingController struct{}
+
+// @route get /ping
+func (PingController) Ping(c *gin.Context) error { return nil }
+`
+
+func writeFile(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGenerate 由 @group/@route 注释生成 Annotation 方法
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "user.go", controllerSrc)
+
+	out, err := Write(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _ := os.ReadFile(out)
+	got := string(src)
+	for _, want := range []string{
+		"// Code generated by route:gen; DO NOT EDIT.",
+		"func (ctl *PingController) Annotation(rb *router.RouteBuilder) {\n\trb.GET(\"/ping\", ctl.Ping, \"\")",
+		"func (u *UserController) Annotation(rb *router.RouteBuilder) {\n\tg := rb.Group(\"/users\")",
+		`g.GET("/", u.List, "user@list", router.Rules{"page": "int|min:1"})`,
+		`g.GET("/:id", u.Show, "user@show", router.Rules{"id": "int|min:1"})`,
+		`g.HEAD("/:id", u.Show, "user@head")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("生成代码缺少 %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "helper") {
+		t.Error("不应为无注释方法生成路由")
+	}
+
+	// 重复生成时忽略已生成文件，结果一致
+	again, err := Generate(dir)
+	if err != nil || string(again) != got {
+		t.Errorf("重复生成结果不一致: %v", err)
+	}
+}
+
+// TestGenerateErrors 手写 Annotation 冲突与注释格式错误
+func TestGenerateErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "user.go", controllerSrc+`
+func (u *UserController) Annotation(rb any) {}
+`)
+	if _, err := Generate(dir); err == nil || !strings.Contains(err.Error(), "UserController") {
+		t.Errorf("期望 Annotation 冲突错误，得到 %v", err)
+	}
+
+	dir = t.TempDir()
+	writeFile(t, dir, "bad.go", `package controller
+
+type C struct{}
+
+// @route FETCH /x
+func (c *C) X() {}
+`)
+	if _, err := Generate(dir); err == nil || !strings.Contains(err.Error(), "FETCH") {
+		t.Errorf("期望请求方法错误，得到 %v", err)
+	}
+}
+
+// TestWriteRemovesStaleFile 无 @route 声明时删除旧的生成文件
+func TestWriteRemovesStaleFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "c.go", "package controller\n")
+	writeFile(t, dir, OutputFile, "package controller\n")
+	out, err := Write(dir)
+	if err != nil || out != "" {
+		t.Fatalf("Write = %q, %v", out, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, OutputFile)); !os.IsNotExist(err) {
+		t.Error("旧的生成文件应被删除")
+	}
+}
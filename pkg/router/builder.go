@@ -1,14 +1,16 @@
 package router
 
 import (
-	stderrors "errors"
+	"compress/gzip"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla-go/go-framework/pkg/config"
-	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
 	"github.com/gorilla-go/go-framework/pkg/request"
 	"github.com/gorilla-go/go-framework/pkg/response"
 )
@@ -16,34 +18,14 @@ import (
 // HandlerFunc 支持直接返回 error 的 handler 类型
 type HandlerFunc func(*gin.Context) error
 
-// wrapH 将 HandlerFunc 包装为标准 gin.HandlerFunc，统一在 router 层处理错误。
-//
-// 错误分流：
-//   - *errors.AppError（业务可预期错误）：始终走统一 JSON 响应（response.Fail）。
-//   - 其他非预期错误：API/AJAX 请求返回 JSON；页面请求则按 PHP 风格渲染错误页
-//     （debug 模式显示错误详情，生产模式显示通用 500 页），让错误直接显示在页面上。
+// wrapH 将 HandlerFunc 包装为标准 gin.HandlerFunc，返回的错误交由 middleware.WriteError 统一转换为响应：
+// 业务错误（*errors.AppError）输出统一 JSON（页面请求存在 errors/<状态码> 模板时渲染错误页），
+// 其他非预期错误在 API/AJAX 请求时返回 JSON，页面请求则渲染错误页（debug 模式显示详情）。
 func wrapH(f HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		err := f(c)
-		if err == nil {
-			return
-		}
-
-		var appErr *errors.AppError
-		if stderrors.As(err, &appErr) {
-			response.Fail(c, appErr)
-			return
-		}
-
-		// 页面（非 AJAX/JSON）请求：渲染 HTML 错误页，行为与 panic / 模板错误一致
-		if !request.IsAjax(c) {
-			errors.RenderError(c.Writer, err, "", config.MustFetch().IsDebug())
-			c.Abort()
-			return
+		if err := f(c); err != nil {
+			middleware.WriteError(c, err)
 		}
-
-		// API 请求：保持统一 JSON 错误响应
-		response.Fail(c, errors.NewInternalServerError(err.Error(), err))
 	}
 }
 
@@ -52,6 +34,7 @@ type RouteBuilder struct {
 	router   *gin.Engine
 	group    *gin.RouterGroup
 	basePath string
+	layout   string // 之后注册的路由使用的默认布局，为空时使用 template.default_layout
 }
 
 // Route 路由信息
@@ -59,14 +42,126 @@ type Route struct {
 	Name   string
 	Path   string
 	Method string
+	Layout string // 默认布局（RouteBuilder.WithLayout 设置），为空时使用 template.default_layout
+
+	// 响应压缩级别（Route.NoCompress、Route.Compress 设置），compressSet 为 false 时使用 middleware.Compress 的默认级别
+	compressLevel int
+	compressSet   bool
+
+	// 注册时预先解析的路径段，BuildUrl 据此拼接，避免每次调用都拆分/替换字符串
+	segments []routeSegment
+	params   int // 参数段数量，为 0 时 BuildUrl 直接返回 Path
+}
+
+// routeSegment 路径段：param 非空时为参数段（":id" → "id"），否则为字面量 literal
+type routeSegment struct {
+	literal string
+	param   string
+}
+
+// newRoute 创建路由信息并解析路径段
+func newRoute(name, path, method, layout string) *Route {
+	r := &Route{Name: name, Path: path, Method: method, Layout: layout}
+	for _, seg := range strings.Split(path, "/") {
+		if param, ok := strings.CutPrefix(seg, ":"); ok {
+			r.segments = append(r.segments, routeSegment{param: param})
+			r.params++
+		} else {
+			r.segments = append(r.segments, routeSegment{literal: seg})
+		}
+	}
+	return r
 }
 
-// 全局路由注册表
+// build 按参数拼接 URL
+func (r *Route) build(params map[string]any) (string, error) {
+	if r.params == 0 {
+		return r.Path, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(r.Path) + 8*r.params)
+	var missing []string
+	for i, seg := range r.segments {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		if seg.param == "" {
+			b.WriteString(seg.literal)
+			continue
+		}
+		value, exists := params[seg.param]
+		if !exists {
+			missing = append(missing, seg.param)
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			b.WriteString(v)
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		case int64:
+			b.WriteString(strconv.FormatInt(v, 10))
+		case uint:
+			b.WriteString(strconv.FormatUint(uint64(v), 10))
+		case uint64:
+			b.WriteString(strconv.FormatUint(v, 10))
+		default:
+			fmt.Fprintf(&b, "%v", v)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("缺少路径参数: %s", strings.Join(missing, ", "))
+	}
+	return b.String(), nil
+}
+
+// 全局路由注册表。
+// 路由只在启动时注册，之后每次生成 URL 都要读取：注册写入 routes 并使快照失效，
+// 读取走 atomic.Pointer 快照，首次读取（或注册后的首次读取）时才在锁内重建，稳定后读路径完全无锁
 var (
-	routes      = make(map[string]*Route)
-	routesMutex sync.RWMutex
+	routes         = make(map[string]*Route)
+	routesMutex    sync.Mutex
+	routesSnapshot atomic.Pointer[map[string]*Route]
 )
 
+// addRoute 注册路由信息并使快照失效
+func addRoute(r *Route) {
+	routesMutex.Lock()
+	routes[r.Name] = r
+	routesSnapshot.Store(nil)
+	routesMutex.Unlock()
+}
+
+// routeSnapshot 返回当前路由表快照，调用方不得修改
+func routeSnapshot() map[string]*Route {
+	if snap := routesSnapshot.Load(); snap != nil {
+		return *snap
+	}
+	routesMutex.Lock()
+	defer routesMutex.Unlock()
+	if snap := routesSnapshot.Load(); snap != nil {
+		return *snap
+	}
+	snap := make(map[string]*Route, len(routes))
+	for name, r := range routes {
+		snap[name] = r
+	}
+	routesSnapshot.Store(&snap)
+	return snap
+}
+
+// Routes 返回已注册的命名路由（按名称排序）
+func Routes() []Route {
+	snap := routeSnapshot()
+	list := make([]Route, 0, len(snap))
+	for _, r := range snap {
+		list = append(list, *r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
 // NewRouteBuilder 创建路由构建器
 func NewRouteBuilder(router *gin.Engine) *RouteBuilder {
 	return &RouteBuilder{
@@ -90,88 +185,122 @@ func (rb *RouteBuilder) Group(path string, middleware ...gin.HandlerFunc) *Route
 		router:   rb.router,
 		group:    group,
 		basePath: newBasePath,
+		layout:   rb.layout,
+	}
+}
+
+// WithLayout 返回使用指定默认布局的路由构建器，通过它注册的路由（含其下的路由组）
+// 在 template.RenderThemeL / controller.Base.View 渲染时使用该布局，而不是 template.default_layout：
+//
+//	admin := rb.Group("/admin", middleware.RoleMiddleware("admin")).WithLayout("admin")
+//	admin.GET("/dashboard", a.Dashboard, "admin@dashboard") // 渲染时使用 layouts/admin
+func (rb *RouteBuilder) WithLayout(layout string) *RouteBuilder {
+	cp := *rb
+	cp.layout = layout
+	return &cp
+}
+
+// Register 注册控制器路由；控制器实现 ILayoutController 时，其路由使用 Layout() 返回的默认布局
+func (rb *RouteBuilder) Register(controllers ...IController) {
+	for _, c := range controllers {
+		b := rb
+		if lc, ok := c.(ILayoutController); ok {
+			b = rb.WithLayout(lc.Layout())
+		}
+		c.Annotation(b)
 	}
 }
 
 // GET 注册GET请求路由，name参数用于在模板中使用route函数生成URL
-func (rb *RouteBuilder) GET(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("GET", path, name, handler)
+func (rb *RouteBuilder) GET(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("GET", path, name, handler, rules...)
 }
 
 // POST 注册POST请求路由，name参数用于在模板中使用route函数生成URL
-func (rb *RouteBuilder) POST(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("POST", path, name, handler)
+func (rb *RouteBuilder) POST(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("POST", path, name, handler, rules...)
 }
 
 // PUT 注册PUT请求路由，name参数用于在模板中使用route函数生成URL
-func (rb *RouteBuilder) PUT(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("PUT", path, name, handler)
+func (rb *RouteBuilder) PUT(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("PUT", path, name, handler, rules...)
 }
 
 // DELETE 注册DELETE请求路由，name参数用于在模板中使用route函数生成URL
-func (rb *RouteBuilder) DELETE(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("DELETE", path, name, handler)
+func (rb *RouteBuilder) DELETE(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("DELETE", path, name, handler, rules...)
 }
 
 // PATCH 注册PATCH请求路由
-func (rb *RouteBuilder) PATCH(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("PATCH", path, name, handler)
+func (rb *RouteBuilder) PATCH(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("PATCH", path, name, handler, rules...)
 }
 
 // HEAD 注册HEAD请求路由
-func (rb *RouteBuilder) HEAD(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("HEAD", path, name, handler)
+func (rb *RouteBuilder) HEAD(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("HEAD", path, name, handler, rules...)
 }
 
 // OPTIONS 注册OPTIONS请求路由
-func (rb *RouteBuilder) OPTIONS(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("OPTIONS", path, name, handler)
+func (rb *RouteBuilder) OPTIONS(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("OPTIONS", path, name, handler, rules...)
 }
 
 // ANY 注册所有HTTP方法路由
-func (rb *RouteBuilder) ANY(path string, handler HandlerFunc, name string) {
-	rb.registerRoute("ANY", path, name, handler)
+func (rb *RouteBuilder) ANY(path string, handler HandlerFunc, name string, rules ...Rules) *Route {
+	return rb.registerRoute("ANY", path, name, handler, rules...)
 }
 
-// 注册路由，内部函数
-func (rb *RouteBuilder) registerRoute(method, path, name string, handler HandlerFunc) {
+// 注册路由，内部函数。传入 rules 时在处理器前插入参数校验中间件，返回登记到路由表的路由信息
+func (rb *RouteBuilder) registerRoute(method, path, name string, handler HandlerFunc, rules ...Rules) *Route {
 	if name == "" {
 		name = fmt.Sprintf("%s:%s", method, path)
 	}
 
-	wrapped := wrapH(handler)
+	handlers := make([]gin.HandlerFunc, 0, 2)
+	merged := make(Rules)
+	for _, r := range rules {
+		for k, v := range r {
+			merged[k] = v
+		}
+	}
+	if len(merged) > 0 {
+		handlers = append(handlers, paramsMiddleware(compileRules(rb.basePath+path, merged)))
+	}
+	// 处理器执行前写入路由名，供模板按路由选择布局等（request.RouteName）
+	h := wrapH(handler)
+	handlers = append(handlers, func(c *gin.Context) {
+		c.Set(request.KeyRouteName, name)
+		h(c)
+	})
 
 	// 注册到Gin
 	target := rb.getRouteTarget()
 	switch method {
 	case "GET":
-		target.GET(path, wrapped)
+		target.GET(path, handlers...)
 	case "POST":
-		target.POST(path, wrapped)
+		target.POST(path, handlers...)
 	case "PUT":
-		target.PUT(path, wrapped)
+		target.PUT(path, handlers...)
 	case "DELETE":
-		target.DELETE(path, wrapped)
+		target.DELETE(path, handlers...)
 	case "PATCH":
-		target.PATCH(path, wrapped)
+		target.PATCH(path, handlers...)
 	case "HEAD":
-		target.HEAD(path, wrapped)
+		target.HEAD(path, handlers...)
 	case "OPTIONS":
-		target.OPTIONS(path, wrapped)
+		target.OPTIONS(path, handlers...)
 	case "ANY":
-		target.Any(path, wrapped)
+		target.Any(path, handlers...)
 	}
 
 	// 记录路由信息
 	fullPath := rb.basePath + path
 
-	routesMutex.Lock()
-	routes[name] = &Route{
-		Name:   name,
-		Path:   fullPath,
-		Method: method,
-	}
-	routesMutex.Unlock()
+	route := newRoute(name, fullPath, method, rb.layout)
+	addRoute(route)
+	return route
 }
 
 // getRouteTarget 获取路由注册目标（路由组或根路由）
@@ -182,42 +311,52 @@ func (rb *RouteBuilder) getRouteTarget() gin.IRoutes {
 	return rb.router
 }
 
-// BuildUrl 根据路由名称和参数生成URL，路由不存在或缺少参数时返回错误
-func BuildUrl(name string, params ...map[string]any) (string, error) {
-	routesMutex.RLock()
-	route, exists := routes[name]
-	routesMutex.RUnlock()
+func init() {
+	response.SetURLBuilder(BuildUrl)
+}
 
-	if !exists {
-		return "", fmt.Errorf("路由不存在: %s", name)
-	}
+// NoCompress 该路由的响应不压缩（如已压缩的导出文件、需要准确 Content-Length 的下载），
+// 由 middleware.Compress 经 RouteCompression 读取：
+//
+//	rb.GET("/export", r.Export, "report@export").NoCompress()
+//	rb.GET("/api/feed", f.List, "feed@list").Compress(gzip.BestSpeed)
+func (r *Route) NoCompress() *Route {
+	return r.Compress(gzip.NoCompression)
+}
 
-	path := route.Path
+// Compress 设置该路由的 gzip 压缩级别，如对大体积、高频的接口使用 gzip.BestSpeed 降低 CPU 占用
+func (r *Route) Compress(level int) *Route {
+	r.compressLevel, r.compressSet = level, true
+	return r
+}
 
-	// 替换路径参数：按路径段精确匹配，避免 :id 误匹配 :idx 这类前缀冲突
-	if len(params) > 0 && len(params[0]) > 0 {
-		segments := strings.Split(path, "/")
-		for i, seg := range segments {
-			if name, ok := strings.CutPrefix(seg, ":"); ok {
-				if value, exists := params[0][name]; exists {
-					segments[i] = fmt.Sprintf("%v", value)
-				}
-			}
-		}
-		path = strings.Join(segments, "/")
+// RouteCompression 返回命名路由注册时设置的压缩级别，路由不存在或未设置时 ok 为 false
+func RouteCompression(name string) (level int, ok bool) {
+	if r, exists := routeSnapshot()[name]; exists && r.compressSet {
+		return r.compressLevel, true
 	}
+	return 0, false
+}
 
-	// 检查是否还有未替换的参数
-	if strings.Contains(path, ":") {
-		var missing []string
-		parts := strings.SplitSeq(path, "/")
-		for part := range parts {
-			if after, ok := strings.CutPrefix(part, ":"); ok {
-				missing = append(missing, after)
-			}
-		}
-		return "", fmt.Errorf("缺少路径参数: %s", strings.Join(missing, ", "))
+// RouteLayout 返回命名路由的默认布局，路由不存在或未设置布局时返回空字符串
+func RouteLayout(name string) string {
+	if r, ok := routeSnapshot()[name]; ok {
+		return r.Layout
+	}
+	return ""
+}
+
+// BuildUrl 根据路由名称和参数生成URL，路由不存在或缺少参数时返回错误。
+// 路径段在注册时已解析，无参数路由直接返回注册路径
+func BuildUrl(name string, params ...map[string]any) (string, error) {
+	route, exists := routeSnapshot()[name]
+	if !exists {
+		return "", fmt.Errorf("路由不存在: %s", name)
 	}
 
-	return path, nil
+	var p map[string]any
+	if len(params) > 0 {
+		p = params[0]
+	}
+	return route.build(p)
 }
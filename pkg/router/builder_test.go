@@ -1,6 +1,7 @@
 package router
 
 import (
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,9 +10,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	apperrors "github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/request"
 )
 
 // TestMain 切换到仓库根目录，使 config.MustFetch() 能定位到 config/config.yaml
@@ -88,3 +92,270 @@ func TestWrapHAppErrorAlwaysJSON(t *testing.T) {
 		t.Errorf("AppError 应返回 JSON，得到 Content-Type=%q", ct)
 	}
 }
+
+// TestVersionGroupDeprecationHeaders 版本路由组应带版本前缀，并为弃用版本写入 Deprecation/Sunset 头
+func TestVersionGroupDeprecationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	rb := NewRouteBuilder(r)
+
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	rb.Version("v1", WithSunset(sunset)).GET("/ping", func(c *gin.Context) error {
+		c.String(http.StatusOK, middleware.GetAPIVersion(c))
+		return nil
+	}, "test@v1ping")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/ping", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "v1" {
+		t.Fatalf("期望 200 且版本为 v1，得到 %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("弃用版本应返回 Deprecation: true")
+	}
+	if got := w.Header().Get("Sunset"); got != sunset.Format(http.TimeFormat) {
+		t.Errorf("Sunset 头不符，得到 %q", got)
+	}
+	if url, _ := BuildUrl("test@v1ping"); url != "/v1/ping" {
+		t.Errorf("命名路由应包含版本前缀，得到 %q", url)
+	}
+}
+
+// TestVersionGroupDeprecatedLink Deprecated/DeprecatedSince/WithDeprecationLink 的响应头，Link 头应追加而非覆盖
+func TestVersionGroupDeprecatedLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	rb := NewRouteBuilder(r)
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rb.Version("V2", DeprecatedSince(since), WithDeprecationLink("https://example.com/migrate")).
+		GET("/ping", func(c *gin.Context) error {
+			c.String(http.StatusOK, middleware.GetAPIVersion(c))
+			return nil
+		}, "test@v2ping")
+	rb.Version("v3", Deprecated()).GET("/ping", func(c *gin.Context) error { return nil }, "test@v3ping")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/ping", nil))
+	if w.Body.String() != "v2" {
+		t.Errorf("版本应规范为小写 v2，得到 %q", w.Body.String())
+	}
+	if got := w.Header().Get("Deprecation"); got != "@1767225600" {
+		t.Errorf("Deprecation 应为结构化日期，得到 %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `<https://example.com/migrate>; rel="deprecation"` {
+		t.Errorf("Link 头不符，得到 %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v3/ping", nil))
+	if got := w.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("未指定弃用时间时期望 true，得到 %q", got)
+	}
+}
+
+// TestVersionGroupWithGlobalResolver 全局 APIVersion 与路径版本组组合时，路径版本优先
+func TestVersionGroupWithGlobalResolver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.APIVersion("v2", "v2"))
+	rb := NewRouteBuilder(r)
+	rb.Version("v1").GET("/users", func(c *gin.Context) error {
+		c.String(http.StatusOK, middleware.GetAPIVersion(c))
+		return nil
+	}, "test@v1users")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	req.Header.Set("Accept", "application/vnd.app.v2+json")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "v1" {
+		t.Errorf("期望 200 且版本为 v1，得到 %d %q", w.Code, w.Body.String())
+	}
+}
+
+// registerBuildUrlRoutes 注册 BuildUrl 测试用路由
+func registerBuildUrlRoutes() {
+	gin.SetMode(gin.TestMode)
+	rb := NewRouteBuilder(gin.New()).Group("/blog")
+	noop := func(c *gin.Context) error { return nil }
+	rb.GET("/posts", noop, "test.posts")
+	rb.GET("/posts/:id/comments/:idx", noop, "test.comment")
+}
+
+func TestBuildUrl(t *testing.T) {
+	registerBuildUrlRoutes()
+
+	tests := []struct {
+		name    string
+		params  []map[string]any
+		want    string
+		wantErr string
+	}{
+		{"test.posts", nil, "/blog/posts", ""},
+		{"test.posts", []map[string]any{{"id": 1}}, "/blog/posts", ""},
+		{"test.comment", []map[string]any{{"id": 7, "idx": "a:b"}}, "/blog/posts/7/comments/a:b", ""},
+		{"test.comment", []map[string]any{{"id": uint(7), "idx": 2.5}}, "/blog/posts/7/comments/2.5", ""},
+		{"test.comment", []map[string]any{{"idx": 1}}, "", "缺少路径参数: id"},
+		{"test.comment", nil, "", "缺少路径参数: id, idx"},
+		{"test.missing", nil, "", "路由不存在: test.missing"},
+	}
+	for _, tt := range tests {
+		got, err := BuildUrl(tt.name, tt.params...)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("BuildUrl(%s, %v) error = %v, want %q", tt.name, tt.params, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("BuildUrl(%s, %v) = %q, %v; want %q", tt.name, tt.params, got, err, tt.want)
+		}
+	}
+}
+
+func BenchmarkBuildUrl(b *testing.B) {
+	registerBuildUrlRoutes()
+	params := map[string]any{"id": 42, "idx": "latest"}
+
+	b.Run("static", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = BuildUrl("test.posts")
+		}
+	})
+	b.Run("params", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = BuildUrl("test.comment", params)
+		}
+	})
+}
+
+func TestRouteRegistrySnapshot(t *testing.T) {
+	registerBuildUrlRoutes()
+	if _, err := BuildUrl("test.posts"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 快照建立后新注册的路由应立即可见
+	addRoute(newRoute("test.late", "/late", "GET", ""))
+	if got, err := BuildUrl("test.late"); err != nil || got != "/late" {
+		t.Fatalf("BuildUrl(test.late) = %q, %v", got, err)
+	}
+
+	var names []string
+	for _, r := range Routes() {
+		if strings.HasPrefix(r.Name, "test.") {
+			names = append(names, r.Name)
+		}
+	}
+	if strings.Join(names, ",") != "test.comment,test.late,test.posts" {
+		t.Errorf("Routes() = %v", names)
+	}
+
+	// 并发注册与读取（配合 go test -race）
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			addRoute(newRoute(fmt.Sprintf("test.concurrent.%d", i), "/c", "GET", ""))
+		}
+		close(done)
+	}()
+	for i := 0; i < 1000; i++ {
+		_, _ = BuildUrl("test.posts")
+	}
+	<-done
+	if _, err := BuildUrl("test.concurrent.99"); err != nil {
+		t.Error(err)
+	}
+}
+
+// layoutController 实现 ILayoutController 的测试控制器
+type layoutController struct{}
+
+func (layoutController) Layout() string { return "console" }
+
+func (layoutController) Annotation(rb *RouteBuilder) {
+	rb.GET("/console", func(c *gin.Context) error { return nil }, "test.layout.console")
+}
+
+// TestRouteLayout 路由组、控制器的默认布局，以及处理器中可读取的路由名
+func TestRouteLayout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	rb := NewRouteBuilder(r)
+
+	rb.GET("/home", func(c *gin.Context) error {
+		c.String(http.StatusOK, request.RouteName(c))
+		return nil
+	}, "test.layout.home")
+	admin := rb.Group("/admin").WithLayout("admin")
+	admin.GET("/dash", func(c *gin.Context) error { return nil }, "test.layout.dash")
+	admin.Group("/users").GET("", func(c *gin.Context) error { return nil }, "test.layout.users")
+	rb.Register(layoutController{})
+	rb.GET("/after", func(c *gin.Context) error { return nil }, "test.layout.after")
+
+	cases := map[string]string{
+		"test.layout.home":    "",
+		"test.layout.dash":    "admin",
+		"test.layout.users":   "admin", // 子路由组继承布局
+		"test.layout.console": "console",
+		"test.layout.after":   "", // WithLayout 不影响原构建器
+		"test.layout.missing": "",
+	}
+	for name, want := range cases {
+		if got := RouteLayout(name); got != want {
+			t.Errorf("RouteLayout(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/home", nil))
+	if w.Body.String() != "test.layout.home" {
+		t.Errorf("处理器中的路由名 = %q", w.Body.String())
+	}
+}
+
+// TestRouteCompression 注册时的压缩设置经路由表被压缩中间件读取
+func TestRouteCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Compress(middleware.WithCompressRoute(RouteCompression)))
+	rb := NewRouteBuilder(r)
+	page := func(c *gin.Context) error {
+		c.String(http.StatusOK, strings.Repeat("hello ", 100))
+		return nil
+	}
+	rb.GET("/page", page, "test.compress.page")
+	rb.GET("/raw", page, "test.compress.raw").NoCompress()
+	rb.GET("/fast", page, "test.compress.fast").Compress(gzip.BestSpeed)
+
+	if _, ok := RouteCompression("test.compress.page"); ok {
+		t.Error("未设置压缩级别的路由应使用中间件默认级别")
+	}
+	if level, ok := RouteCompression("test.compress.fast"); !ok || level != gzip.BestSpeed {
+		t.Errorf("RouteCompression = %d, %v", level, ok)
+	}
+	for path, want := range map[string]string{"/page": "gzip", "/raw": "", "/fast": "gzip"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Header().Get("Content-Encoding"); got != want {
+			t.Errorf("%s Content-Encoding = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func BenchmarkBuildUrlParallel(b *testing.B) {
+	registerBuildUrlRoutes()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = BuildUrl("test.posts")
+		}
+	})
+}
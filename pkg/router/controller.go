@@ -5,6 +5,14 @@ type IController interface {
 	Annotation(rb *RouteBuilder)
 }
 
+// ILayoutController 控制器实现该接口时，其全部路由使用 Layout() 返回的默认布局（见 RouteBuilder.WithLayout）
+//
+//	func (a *AdminController) Layout() string { return "admin" }
+type ILayoutController interface {
+	IController
+	Layout() string
+}
+
 // Controllers 已注册的控制器列表
 var Controllers = []IController{}
 
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resourceAction 资源路由约定：控制器方法名 → 请求方法、相对路径与路由名后缀
+type resourceAction struct {
+	method string
+	name   string
+	path   string
+}
+
+// resourceActions 资源路由表（/new 与 /:id 共存时 gin 优先匹配静态段）
+var resourceActions = []resourceAction{
+	{"GET", "Index", ""},
+	{"GET", "New", "/new"},
+	{"POST", "Create", ""},
+	{"GET", "Show", "/:id"},
+	{"GET", "Edit", "/:id/edit"},
+	{"PUT", "Update", "/:id"},
+	{"DELETE", "Destroy", "/:id"},
+}
+
+// Resource 按方法命名约定注册 RESTful 资源路由，省去逐条书写 GET/POST：
+//
+//	Index   GET    /users           user@index
+//	New     GET    /users/new       user@new
+//	Create  POST   /users           user@create
+//	Show    GET    /users/:id       user@show
+//	Edit    GET    /users/:id/edit  user@edit
+//	Update  PUT    /users/:id       user@update
+//	Destroy DELETE /users/:id       user@destroy
+//
+// 控制器只需实现其中部分方法，签名须为 func(*gin.Context) error，未实现或签名不符的方法被跳过。
+// rules 只作用于带 :id 的成员路由：
+//
+//	rb.Resource("/users", u, "user", router.Rules{"id": "int|min:1"})
+func (rb *RouteBuilder) Resource(path string, ctl any, name string, rules ...Rules) {
+	v := reflect.ValueOf(ctl)
+	path = strings.TrimSuffix(path, "/")
+	for _, action := range resourceActions {
+		m := v.MethodByName(action.name)
+		if !m.IsValid() {
+			continue
+		}
+		handler, ok := m.Interface().(func(*gin.Context) error)
+		if !ok {
+			continue
+		}
+		routePath := path + action.path
+		if routePath == "" {
+			routePath = "/"
+		}
+		routeName := name + "@" + strings.ToLower(action.name)
+		if strings.Contains(action.path, ":id") {
+			rb.registerRoute(action.method, routePath, routeName, handler, rules...)
+		} else {
+			rb.registerRoute(action.method, routePath, routeName, handler)
+		}
+	}
+}
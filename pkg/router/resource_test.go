@@ -0,0 +1,70 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type articleResource struct{ calls []string }
+
+func (a *articleResource) Index(c *gin.Context) error {
+	a.calls = append(a.calls, "index")
+	return nil
+}
+
+func (a *articleResource) Show(c *gin.Context) error {
+	id, _ := Param[int](c, "id")
+	a.calls = append(a.calls, "show:"+c.Param("id"))
+	if id == 0 {
+		a.calls = append(a.calls, "unconverted")
+	}
+	return nil
+}
+
+func (a *articleResource) Destroy(c *gin.Context) error {
+	a.calls = append(a.calls, "destroy")
+	return nil
+}
+
+// Edit 签名不符，应被跳过
+func (a *articleResource) Edit(c *gin.Context) {}
+
+// TestResource 按方法命名约定注册资源路由
+func TestResource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	ctl := &articleResource{}
+	NewRouteBuilder(engine).Group("/admin").Resource("/articles", ctl, "test.article", Rules{"id": "int|min:1"})
+
+	cases := []struct {
+		method, url string
+		code        int
+	}{
+		{http.MethodGet, "/admin/articles", http.StatusOK},
+		{http.MethodGet, "/admin/articles/5", http.StatusOK},
+		{http.MethodGet, "/admin/articles/x", http.StatusBadRequest},
+		{http.MethodDelete, "/admin/articles/5", http.StatusOK},
+		{http.MethodPost, "/admin/articles", http.StatusNotFound},
+		{http.MethodGet, "/admin/articles/5/edit", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(tc.method, tc.url, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: 期望 %d，得到 %d", tc.method, tc.url, tc.code, w.Code)
+		}
+	}
+	if got := len(ctl.calls); got != 3 || ctl.calls[1] != "show:5" {
+		t.Errorf("calls = %v", ctl.calls)
+	}
+
+	if url, err := BuildUrl("test.article@show", map[string]any{"id": 5}); err != nil || url != "/admin/articles/5" {
+		t.Errorf("BuildUrl = %q, %v", url, err)
+	}
+	if _, err := BuildUrl("test.article@edit"); err == nil {
+		t.Error("签名不符的 Edit 不应注册")
+	}
+}
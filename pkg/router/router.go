@@ -2,16 +2,32 @@ package router
 
 import (
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/captcha"
 	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/errorrate"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/geoip"
+	"github.com/gorilla-go/go-framework/pkg/image"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
 	"github.com/gorilla-go/go-framework/pkg/logger"
 	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/notify"
+	"github.com/gorilla-go/go-framework/pkg/recorder"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/response"
+	"github.com/gorilla-go/go-framework/pkg/seo"
+	"github.com/gorilla-go/go-framework/pkg/tenant"
 )
 
 type Router struct {
 	Controllers []IController
 	Cfg         *config.Config
+	ErrorRate   *errorrate.Monitor // 路由错误率监控，为 nil 时不统计
 }
 
 // Route 设置路由
@@ -25,6 +41,20 @@ func (router *Router) Route() *gin.Engine {
 	// 设置运行模式
 	gin.SetMode(cfg.Server.Mode)
 
+	// 统一响应的内容协商（XML、MessagePack）
+	response.SetNegotiation(cfg.Server.ContentNegotiation)
+
+	// 统一响应使用的 JSON 引擎
+	if err := jsonx.Use(cfg.Server.JSONEngine); err != nil {
+		logger.Fatalf("JSON 引擎配置错误: %v", err)
+	}
+
+	// request.RawBody 读取上限
+	request.SetMaxBodySize(cfg.Server.MaxBodySize)
+
+	// 开发错误页的编辑器链接
+	errors.SetEditor(cfg.Server.Editor)
+
 	// 创建路由
 	r := gin.New()
 
@@ -33,35 +63,170 @@ func (router *Router) Route() *gin.Engine {
 		logger.Fatalf("配置可信代理失败: %v", err)
 	}
 
+	// 错误率统计挂在最外层，Recovery 恢复 panic 后写出的 500 同样计入
+	if router.ErrorRate != nil {
+		r.Use(router.ErrorRate.Middleware())
+	}
+
+	// 请求录制同样挂在 Recovery 与 ErrorHandler 之前，以记录最终的响应状态
+	if cfg.Record.Enabled {
+		r.Use(recorder.FromConfig(&cfg.Record))
+	}
+
 	// 添加全局中间件
 	r.Use(
 		middleware.Recovery(),
+		middleware.RequestID(),
 		middleware.Logger(cfg.IsDebug()),
 		middleware.SessionStart(
 			&router.Cfg.Session,
 			&router.Cfg.Redis,
 			&router.Cfg.Database,
 		),
+		middleware.ErrorHandler(),
 	)
 
+	// 会话索引：已被撤销（强制下线）的会话在下一次请求时被清空
+	if cfg.Session.Index {
+		r.Use(middleware.SessionTrack())
+	}
+
+	// 开发模式调试工具栏
+	if cfg.Server.DebugToolbar && cfg.IsDebug() {
+		r.Use(middleware.DebugToolbar())
+	}
+
 	// 根据配置启用全局限流
 	if cfg.Server.EnableRateLimit {
 		r.Use(middleware.RateLimitMiddleware(
 			middleware.WithRate(cfg.Server.RateLimit),
 			middleware.WithBurst(cfg.Server.RateBurst),
+			middleware.WithRateLimitName("global"),
 		))
 	}
 
+	// SSE、WebSocket 长连接不受请求截止时间与并发限制约束
+	var streams []string
+	if cfg.Notify.Enabled {
+		streams = append(streams, cfg.Notify.Path)
+	}
+	if cfg.Broadcast.Enabled {
+		streams = append(streams, cfg.Broadcast.Path)
+	}
+	isStream := func(c *gin.Context) bool { return slices.Contains(streams, c.Request.URL.Path) }
+
+	// 请求截止时间：在 WriteTimeout 断开连接前取消下游调用
+	if cfg.Server.RequestDeadline && cfg.Server.WriteTimeout > 0 {
+		r.Use(middleware.Deadline(
+			middleware.DeadlineFor(time.Duration(cfg.Server.WriteTimeout)*time.Second),
+			middleware.WithDeadlineSkipper(isStream),
+		))
+	}
+
+	// 并发限制：超出的请求排队，排队超时返回 503
+	if cfg.Server.MaxInFlight > 0 {
+		r.Use(middleware.MaxInFlight(
+			cfg.Server.MaxInFlight,
+			time.Duration(cfg.Server.QueueTimeout)*time.Second,
+			middleware.WithMaxInFlightName("global"),
+			middleware.WithMaxInFlightSkipper(isStream),
+		))
+	}
+
+	// gzip 压缩响应：注册在 MinifyHTML 之前，路由可在注册时以 NoCompress、Compress(level) 单独设置
+	if cfg.Server.Compress {
+		r.Use(middleware.Compress(
+			middleware.WithCompressLevel(cfg.Server.CompressLevel),
+			middleware.WithCompressExclude(cfg.Server.CompressExcept...),
+			middleware.WithCompressSkipper(isStream),
+			middleware.WithCompressRoute(RouteCompression),
+		))
+	}
+
+	// 生产环境压缩 HTML 输出
+	if cfg.Server.MinifyHTML && !cfg.IsDebug() {
+		r.Use(middleware.MinifyHTML())
+	}
+
 	// 静态文件
 	r.Static("/static", cfg.Static.Path)
 
+	// 本地存储：url 为站内路径时提供静态访问
+	for _, disk := range cfg.Storage.Disks {
+		if disk.Driver == "local" && strings.HasPrefix(disk.URL, "/") {
+			r.Static(disk.URL, disk.Root)
+		}
+	}
+
+	// 图片处理：与静态文件一样注册在地区解析、租户等中间件之前
+	if cfg.Image.Enabled {
+		srv, err := image.NewServerFromConfig(&cfg.Image, &cfg.Redis)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		image.SetDefault(srv)
+		srv.Mount(r)
+	}
+
+	// IP 地区解析：注册在静态文件之后，静态资源无需查询
+	if cfg.GeoIP.Database != "" {
+		db, err := geoip.Open(cfg.GeoIP.Database)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		r.Use(middleware.GeoIP(
+			geoip.NewCached(db, cfg.GeoIP.CacheSize),
+			middleware.WithGeoAllow(cfg.GeoIP.Allow...),
+			middleware.WithGeoDeny(cfg.GeoIP.Deny...),
+		))
+	}
+
+	// 多租户：注册在静态文件之后，静态资源无需解析租户
+	if cfg.Tenant.Enabled {
+		r.Use(middleware.Tenant(
+			tenant.NewResolverFromConfig(&cfg.Tenant),
+			tenant.NewStoreFromConfig(&cfg.Tenant),
+			middleware.WithTenantRequired(cfg.Tenant.Required),
+		))
+	}
+
+	// CSRF 防护：注册在静态文件之后，静态资源无需校验
+	if cfg.Server.CSRF {
+		r.Use(middleware.CSRF(middleware.WithCSRFExcept(cfg.Server.CSRFExcept...)))
+	}
+
+	// 通知提示：闪存的通知与 SSE 路由以 .Toasts 共享给视图
+	if cfg.Notify.Enabled {
+		r.Use(notify.ViewData(cfg.Notify.Path))
+	}
+
+	// 验证码：生成接口按 IP 限流
+	if cfg.Captcha.Enabled {
+		m, err := captcha.NewFromConfig(&cfg.Captcha, &cfg.Redis, &cfg.Session)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		captcha.SetDefault(m)
+		var limit []gin.HandlerFunc
+		if cfg.Captcha.RateLimit > 0 {
+			limit = append(limit, middleware.IPRateLimitMiddleware(
+				middleware.WithRate(cfg.Captcha.RateLimit),
+				middleware.WithRateLimitName("captcha"),
+			))
+		}
+		m.Mount(r, limit...)
+	}
+
+	// robots.txt 与站点地图
+	if cfg.SEO.Enabled {
+		seo.Mount(r, cfg)
+	}
+
 	// 创建路由构建器
 	rb := NewRouteBuilder(r)
 
 	// 注册控制器路由
-	for _, controller := range router.Controllers {
-		controller.Annotation(rb)
-	}
+	rb.Register(router.Controllers...)
 
 	// 404处理：根据 Accept 头返回 JSON 或纯文本
 	r.NoRoute(func(c *gin.Context) {
@@ -0,0 +1,230 @@
+package router
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// Rules 路由参数校验规则，键为参数名，值为以 "|" 分隔的规则：
+//
+//	rb.GET("/users/:id", u.Show, "user@show", router.Rules{"id": "int|min:1", "tab": "in:posts,likes"})
+//
+// 参数名与路径参数（:id）同名时校验路径参数，否则校验同名查询参数。
+// 支持的规则：
+//   - 类型：int、uint、float、bool（转换后的值写入上下文，未声明类型时按字符串保存）
+//   - required：必须存在且非空（路径参数总是存在）
+//   - min:N / max:N：数值类型比较取值，字符串比较长度（按字符计）
+//   - in:a,b,c：取值必须在列表中
+//   - alpha / alphanum：只允许字母 / 字母与数字
+//
+// 校验失败返回 400，处理器中通过 Param / ParamOr 读取已转换的值，无需再 strconv
+type Rules map[string]string
+
+// paramKind 参数类型
+type paramKind int
+
+const (
+	kindString paramKind = iota
+	kindInt
+	kindUint
+	kindFloat
+	kindBool
+)
+
+// paramRule 解析后的单个参数规则
+type paramRule struct {
+	name     string
+	path     bool // 是否为路径参数
+	kind     paramKind
+	required bool
+	min, max *float64
+	in       []string
+	alpha    bool
+	alphanum bool
+}
+
+// compileRules 在注册时解析规则，规则书写错误属于编程错误，直接 panic（与 gin 注册冲突路由的行为一致）
+func compileRules(path string, rules Rules) []paramRule {
+	pathParams := make(map[string]bool)
+	for _, seg := range strings.Split(path, "/") {
+		if p, ok := strings.CutPrefix(seg, ":"); ok {
+			pathParams[p] = true
+		}
+	}
+
+	compiled := make([]paramRule, 0, len(rules))
+	for name, spec := range rules {
+		r := paramRule{name: name, path: pathParams[name]}
+		for _, part := range strings.Split(spec, "|") {
+			rule, arg, _ := strings.Cut(strings.TrimSpace(part), ":")
+			switch rule {
+			case "":
+			case "string":
+				r.kind = kindString
+			case "int":
+				r.kind = kindInt
+			case "uint":
+				r.kind = kindUint
+			case "float":
+				r.kind = kindFloat
+			case "bool":
+				r.kind = kindBool
+			case "required":
+				r.required = true
+			case "min", "max":
+				n, err := strconv.ParseFloat(arg, 64)
+				if err != nil {
+					panic(fmt.Sprintf("路由 %s 参数 %s 的规则 %q 无效: %v", path, name, part, err))
+				}
+				if rule == "min" {
+					r.min = &n
+				} else {
+					r.max = &n
+				}
+			case "in":
+				r.in = strings.Split(arg, ",")
+			case "alpha":
+				r.alpha = true
+			case "alphanum":
+				r.alphanum = true
+			default:
+				panic(fmt.Sprintf("路由 %s 参数 %s 的规则 %q 不受支持", path, name, part))
+			}
+		}
+		compiled = append(compiled, r)
+	}
+	return compiled
+}
+
+// check 校验并转换参数值
+func (r *paramRule) check(raw string) (any, error) {
+	if len(r.in) > 0 && !contains(r.in, raw) {
+		return nil, fmt.Errorf("参数 %s 必须是 %s 之一", r.name, strings.Join(r.in, ", "))
+	}
+	if r.alpha && strings.IndexFunc(raw, func(c rune) bool { return !unicode.IsLetter(c) }) >= 0 {
+		return nil, fmt.Errorf("参数 %s 只能包含字母", r.name)
+	}
+	if r.alphanum && strings.IndexFunc(raw, func(c rune) bool { return !unicode.IsLetter(c) && !unicode.IsDigit(c) }) >= 0 {
+		return nil, fmt.Errorf("参数 %s 只能包含字母和数字", r.name)
+	}
+
+	var (
+		value any
+		num   float64
+	)
+	switch r.kind {
+	case kindInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s 必须是整数", r.name)
+		}
+		value, num = n, float64(n)
+	case kindUint:
+		n, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s 必须是非负整数", r.name)
+		}
+		value, num = uint(n), float64(n)
+	case kindFloat:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s 必须是数字", r.name)
+		}
+		value, num = n, n
+	case kindBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s 必须是布尔值", r.name)
+		}
+		return b, nil
+	default:
+		value, num = raw, float64(len([]rune(raw)))
+	}
+
+	if r.min != nil && num < *r.min {
+		if r.kind == kindString {
+			return nil, fmt.Errorf("参数 %s 长度不能小于 %v", r.name, *r.min)
+		}
+		return nil, fmt.Errorf("参数 %s 不能小于 %v", r.name, *r.min)
+	}
+	if r.max != nil && num > *r.max {
+		if r.kind == kindString {
+			return nil, fmt.Errorf("参数 %s 长度不能大于 %v", r.name, *r.max)
+		}
+		return nil, fmt.Errorf("参数 %s 不能大于 %v", r.name, *r.max)
+	}
+	return value, nil
+}
+
+// contains 判断字符串是否在列表中
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// paramsMiddleware 根据规则生成校验中间件，校验通过的值以 map 形式写入上下文
+func paramsMiddleware(rules []paramRule) gin.HandlerFunc {
+	return wrapH(func(c *gin.Context) error {
+		values := make(map[string]any, len(rules))
+		for i := range rules {
+			r := &rules[i]
+			var (
+				raw    string
+				exists bool
+			)
+			if r.path {
+				raw, exists = c.Param(r.name), true
+			} else {
+				raw, exists = c.GetQuery(r.name)
+			}
+			if !exists || raw == "" {
+				if r.required || r.path {
+					return errors.NewBadRequest(fmt.Sprintf("缺少参数 %s", r.name), nil)
+				}
+				continue
+			}
+			v, err := r.check(raw)
+			if err != nil {
+				return errors.NewBadRequest(err.Error(), err)
+			}
+			values[r.name] = v
+		}
+		c.Set(request.KeyParams, values)
+		c.Next()
+		return nil
+	})
+}
+
+// Param 读取经 Rules 校验转换后的参数，参数未声明、未传入或类型不匹配时返回零值与 false：
+//
+//	id, _ := router.Param[int](c, "id")
+func Param[T any](c *gin.Context, name string) (T, bool) {
+	var zero T
+	values, ok := request.Get[map[string]any](c, request.KeyParams)
+	if !ok {
+		return zero, false
+	}
+	v, ok := values[name].(T)
+	if !ok {
+		return zero, false
+	}
+	return v, true
+}
+
+// ParamOr 读取经 Rules 校验转换后的参数，读取失败时返回 def（用于可选查询参数）
+func ParamOr[T any](c *gin.Context, name string, def T) T {
+	if v, ok := Param[T](c, name); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRulesValidateAndConvert 路径/查询参数按规则校验，处理器读取已转换的值
+func TestRulesValidateAndConvert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	rb := NewRouteBuilder(engine)
+
+	var (
+		gotID   int
+		gotTab  string
+		gotPage int
+	)
+	rb.GET("/users/:id", func(c *gin.Context) error {
+		gotID, _ = Param[int](c, "id")
+		gotTab = ParamOr(c, "tab", "posts")
+		gotPage = ParamOr(c, "page", 1)
+		c.Status(http.StatusOK)
+		return nil
+	}, "test.rules.user", Rules{"id": "int|min:1", "tab": "in:posts,likes", "page": "int|min:1|max:100"})
+
+	cases := []struct {
+		url  string
+		code int
+	}{
+		{"/users/42", http.StatusOK},
+		{"/users/42?tab=likes&page=3", http.StatusOK},
+		{"/users/abc", http.StatusBadRequest},
+		{"/users/0", http.StatusBadRequest},
+		{"/users/1?tab=other", http.StatusBadRequest},
+		{"/users/1?page=101", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		gotID, gotTab, gotPage = 0, "", 0
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		req.Header.Set("Accept", "application/json")
+		engine.ServeHTTP(w, req)
+		if w.Code != tc.code {
+			t.Errorf("%s: 期望 %d，得到 %d (%s)", tc.url, tc.code, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/7?tab=likes&page=3", nil))
+	if gotID != 7 || gotTab != "likes" || gotPage != 3 {
+		t.Errorf("id=%d tab=%q page=%d", gotID, gotTab, gotPage)
+	}
+
+	// 可选查询参数缺省时使用默认值
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if gotTab != "posts" || gotPage != 1 {
+		t.Errorf("缺省值 tab=%q page=%d", gotTab, gotPage)
+	}
+}
+
+// TestRulesRequiredAndStringLength required 与字符串长度规则
+func TestRulesRequiredAndStringLength(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	rb := NewRouteBuilder(engine)
+	rb.GET("/search", func(c *gin.Context) error {
+		c.String(http.StatusOK, ParamOr(c, "q", ""))
+		return nil
+	}, "test.rules.search", Rules{"q": "required|alphanum|min:2|max:5"})
+
+	cases := map[string]int{
+		"/search":          http.StatusBadRequest,
+		"/search?q=":       http.StatusBadRequest,
+		"/search?q=a":      http.StatusBadRequest,
+		"/search?q=abcdef": http.StatusBadRequest,
+		"/search?q=a-b":    http.StatusBadRequest,
+		"/search?q=go":     http.StatusOK,
+		"/search?q=中文":     http.StatusOK,
+	}
+	for url, code := range cases {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != code {
+			t.Errorf("%s: 期望 %d，得到 %d", url, code, w.Code)
+		}
+	}
+}
+
+// TestRulesInvalidSpecPanics 规则书写错误在注册时即 panic
+func TestRulesInvalidSpecPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("未知规则应 panic")
+		}
+	}()
+	compileRules("/x/:id", Rules{"id": "integer"})
+}
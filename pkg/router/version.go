@@ -0,0 +1,83 @@
+package router
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/response"
+)
+
+// versionConfig 版本路由组配置
+type versionConfig struct {
+	deprecated bool
+	since      time.Time
+	sunset     time.Time
+	link       string
+	middleware []gin.HandlerFunc
+}
+
+// VersionOption 版本路由组配置选项
+type VersionOption func(*versionConfig)
+
+// Deprecated 标记该版本已弃用，组内所有响应携带 Deprecation 头
+func Deprecated() VersionOption {
+	return func(c *versionConfig) { c.deprecated = true }
+}
+
+// DeprecatedSince 标记该版本自指定时间起弃用，Deprecation 头写入 RFC 9745 结构化日期
+func DeprecatedSince(t time.Time) VersionOption {
+	return func(c *versionConfig) {
+		c.deprecated = true
+		c.since = t
+	}
+}
+
+// WithSunset 设置该版本的下线时间（Sunset 头），隐含 Deprecated
+func WithSunset(t time.Time) VersionOption {
+	return func(c *versionConfig) {
+		c.deprecated = true
+		c.sunset = t
+	}
+}
+
+// WithDeprecationLink 设置弃用说明/迁移文档地址（Link 头），隐含 Deprecated
+func WithDeprecationLink(url string) VersionOption {
+	return func(c *versionConfig) {
+		c.deprecated = true
+		c.link = url
+	}
+}
+
+// WithVersionMiddleware 为该版本路由组追加中间件
+func WithVersionMiddleware(middleware ...gin.HandlerFunc) VersionOption {
+	return func(c *versionConfig) { c.middleware = append(c.middleware, middleware...) }
+}
+
+// Version 创建 API 版本路由组，路径前缀为 "/<version>"（版本号统一规范为小写 v 前缀，如 "V1" → "v1"）。
+// 组内请求的 API 版本会写入 Context（middleware.GetAPIVersion 可读取），路径版本优先于请求头协商，
+// 并可通过选项为整个版本统一声明弃用信息，无需逐个控制器修改。
+//
+//	v1 := rb.Version("v1", router.WithSunset(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)))
+//	v1.GET("/users", u.List, "v1@users")
+//	v2 := rb.Version("v2")
+//	v2.GET("/users", u.ListV2, "v2@users")
+func (rb *RouteBuilder) Version(version string, opts ...VersionOption) *RouteBuilder {
+	version = middleware.NormalizeVersion(version)
+	cfg := &versionConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	handlers := []gin.HandlerFunc{func(c *gin.Context) {
+		c.Set(middleware.ContextKeyAPIVersion, version)
+		c.Header("API-Version", version)
+		if cfg.deprecated {
+			response.Deprecation(c, cfg.since, cfg.sunset, cfg.link)
+		}
+		c.Next()
+	}}
+	handlers = append(handlers, cfg.middleware...)
+
+	return rb.Group("/"+version, handlers...)
+}
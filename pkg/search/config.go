@@ -0,0 +1,50 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"gorm.io/gorm"
+)
+
+// NewFromConfig 按 search.* 配置创建检索引擎，db 仅 mysql 驱动使用
+func NewFromConfig(cfg *config.SearchConfig, db *gorm.DB) (Engine, error) {
+	timeout := DefaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	clientOpts := []ClientOption{WithIndexPrefix(cfg.Prefix), WithHTTPClient(httpclient.New(timeout))}
+
+	switch cfg.Driver {
+	case "", "memory":
+		return NewMemory(), nil
+	case "mysql":
+		if db == nil {
+			return nil, errors.New("mysql 检索驱动需要数据库连接")
+		}
+		var opts []MySQLOption
+		if cfg.BooleanMode {
+			opts = append(opts, WithMySQLBooleanMode())
+		}
+		m := NewMySQL(db, opts...)
+		for table, columns := range cfg.Columns {
+			m.Columns(table, columns...)
+		}
+		return m, nil
+	case "meilisearch":
+		if cfg.URL == "" {
+			return nil, errors.New("meilisearch 检索驱动未配置 search.url")
+		}
+		return NewMeilisearch(cfg.URL, cfg.APIKey, clientOpts...), nil
+	case "elasticsearch":
+		if cfg.URL == "" {
+			return nil, errors.New("elasticsearch 检索驱动未配置 search.url")
+		}
+		return NewElasticsearch(cfg.URL, cfg.APIKey, cfg.Username, cfg.Password, clientOpts...), nil
+	default:
+		return nil, fmt.Errorf("未知的检索驱动: %s", cfg.Driver)
+	}
+}
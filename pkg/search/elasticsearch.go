@@ -0,0 +1,167 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+)
+
+// Elasticsearch 基于 Elasticsearch（兼容 OpenSearch）的检索引擎。写入与删除使用 _bulk 接口，
+// 过滤以 term 查询实现，过滤字段应映射为 keyword 等非分词类型
+type Elasticsearch struct {
+	remote
+}
+
+// NewElasticsearch 创建 Elasticsearch 检索引擎，apiKey 不为空时以 API Key 鉴权，否则 username 不为空时以 Basic 鉴权
+func NewElasticsearch(baseURL, apiKey, username, password string, opts ...ClientOption) *Elasticsearch {
+	e := &Elasticsearch{remote: newRemote(baseURL, opts)}
+	switch {
+	case apiKey != "":
+		e.header.Set("Authorization", "ApiKey "+apiKey)
+	case username != "":
+		e.header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	}
+	return e
+}
+
+// Index 实现 Engine
+func (e *Elasticsearch) Index(ctx context.Context, index string, docs ...Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := jsonx.NewEncoder(&buf)
+	for _, d := range docs {
+		if err := enc.Encode(map[string]any{"index": map[string]string{"_index": e.index(index), "_id": d.ID}}); err != nil {
+			return err
+		}
+		fields := d.Fields
+		if fields == nil {
+			fields = map[string]any{}
+		}
+		if err := enc.Encode(fields); err != nil {
+			return err
+		}
+	}
+	return e.bulk(ctx, buf.Bytes())
+}
+
+// Delete 实现 Engine
+func (e *Elasticsearch) Delete(ctx context.Context, index string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := jsonx.NewEncoder(&buf)
+	for _, id := range ids {
+		if err := enc.Encode(map[string]any{"delete": map[string]string{"_index": e.index(index), "_id": id}}); err != nil {
+			return err
+		}
+	}
+	return e.bulk(ctx, buf.Bytes())
+}
+
+// esBulkResponse _bulk 响应，部分失败时 errors 为 true，失败原因在各条目中
+type esBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulk 执行 _bulk 请求，返回第一个失败条目的原因（删除不存在的文档不视为失败）
+func (e *Elasticsearch) bulk(ctx context.Context, ndjson []byte) error {
+	var resp esBulkResponse
+	if err := e.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", ndjson, &resp); err != nil {
+		return err
+	}
+	if !resp.Errors {
+		return nil
+	}
+	for _, item := range resp.Items {
+		for action, r := range item {
+			if r.Error != nil {
+				return fmt.Errorf("%s 文档 %s 失败: %s: %s", action, r.ID, r.Error.Type, r.Error.Reason)
+			}
+		}
+	}
+	return errors.New("批量写入索引失败")
+}
+
+// esSearchResponse _search 响应
+type esSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID        string              `json:"_id"`
+			Score     float64             `json:"_score"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Search 实现 Engine
+func (e *Elasticsearch) Search(ctx context.Context, q Query) (*Result, error) {
+	must := map[string]any{"match_all": map[string]any{}}
+	if q.Text != "" {
+		match := map[string]any{"query": q.Text}
+		if len(q.Fields) > 0 {
+			match["fields"] = q.Fields
+		}
+		must = map[string]any{"multi_match": match}
+	}
+	filters := make([]any, 0, len(q.Filters))
+	for k, v := range q.Filters {
+		filters = append(filters, map[string]any{"term": map[string]any{k: v}})
+	}
+	highlightFields := map[string]any{"*": map[string]any{}}
+	if len(q.Fields) > 0 {
+		highlightFields = make(map[string]any, len(q.Fields))
+		for _, f := range q.Fields {
+			highlightFields[f] = map[string]any{}
+		}
+	}
+	body := map[string]any{
+		"from":             q.Offset,
+		"size":             q.limit(),
+		"track_total_hits": true,
+		"query":            map[string]any{"bool": map[string]any{"must": must, "filter": filters}},
+		"highlight": map[string]any{
+			"pre_tags":  []string{HighlightPre},
+			"post_tags": []string{HighlightPost},
+			"fields":    highlightFields,
+		},
+	}
+
+	var resp esSearchResponse
+	path := "/" + url.PathEscape(e.index(q.Index)) + "/_search"
+	if err := e.do(ctx, http.MethodPost, path, "application/json", body, &resp); err != nil {
+		return nil, err
+	}
+	res := &Result{Total: resp.Hits.Total.Value, Hits: make([]Hit, 0, len(resp.Hits.Hits))}
+	for _, h := range resp.Hits.Hits {
+		hit := Hit{ID: h.ID, Score: h.Score}
+		for field, fragments := range h.Highlight {
+			if hit.Highlights == nil {
+				hit.Highlights = make(map[string]string)
+			}
+			hit.Highlights[field] = strings.Join(fragments, " … ")
+		}
+		res.Hits = append(res.Hits, hit)
+	}
+	return res, nil
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// snippetLength 高亮片段的最大长度（字符数），超出时截取首个命中词附近的内容
+const snippetLength = 160
+
+// terms 将检索文本拆分为检索词，去掉 MySQL 布尔模式的运算符
+func terms(text string) []string {
+	fields := strings.Fields(text)
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.Trim(f, `+-~<>()"*`); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// lowerRunes 逐字符转为小写，保持与原文的下标对应
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// highlight 以高亮标记包裹 text 中检索词的出现（不区分大小写），返回片段与是否命中
+func highlight(text string, words []string) (string, bool) {
+	runes := []rune(text)
+	lower := lowerRunes(text)
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, w := range words {
+		lw := lowerRunes(w)
+		for i := 0; len(lw) > 0 && i+len(lw) <= len(lower); i++ {
+			if string(lower[i:i+len(lw)]) != string(lw) {
+				continue
+			}
+			for j := i; j < i+len(lw); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+			i += len(lw) - 1
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > snippetLength {
+		start = max(0, first-snippetLength/4)
+		end = min(len(runes), start+snippetLength)
+	}
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			b.WriteString(HighlightPre)
+		}
+		b.WriteRune(runes[i])
+		if marked[i] && (i == end-1 || !marked[i+1]) {
+			b.WriteString(HighlightPost)
+		}
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Meilisearch 基于 Meilisearch 的检索引擎。文档以 id 为主键写入，过滤的字段须在索引设置中声明为
+// filterableAttributes；写入与删除为异步任务，返回时尚未生效
+type Meilisearch struct {
+	remote
+}
+
+// NewMeilisearch 创建 Meilisearch 检索引擎，apiKey 为空时不鉴权
+func NewMeilisearch(baseURL, apiKey string, opts ...ClientOption) *Meilisearch {
+	m := &Meilisearch{remote: newRemote(baseURL, opts)}
+	if apiKey != "" {
+		m.header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return m
+}
+
+// indexPath 返回索引下的 API 路径
+func (m *Meilisearch) indexPath(index, path string) string {
+	return "/indexes/" + url.PathEscape(m.index(index)) + path
+}
+
+// Index 实现 Engine
+func (m *Meilisearch) Index(ctx context.Context, index string, docs ...Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	body := make([]map[string]any, len(docs))
+	for i, d := range docs {
+		doc := make(map[string]any, len(d.Fields)+1)
+		for k, v := range d.Fields {
+			doc[k] = v
+		}
+		doc["id"] = d.ID
+		body[i] = doc
+	}
+	return m.do(ctx, http.MethodPost, m.indexPath(index, "/documents?primaryKey=id"), "application/json", body, nil)
+}
+
+// Delete 实现 Engine
+func (m *Meilisearch) Delete(ctx context.Context, index string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, m.indexPath(index, "/documents/delete-batch"), "application/json", ids, nil)
+}
+
+// meiliResponse 检索响应
+type meiliResponse struct {
+	Hits               []map[string]any `json:"hits"`
+	EstimatedTotalHits int64            `json:"estimatedTotalHits"`
+}
+
+// Search 实现 Engine
+func (m *Meilisearch) Search(ctx context.Context, q Query) (*Result, error) {
+	body := map[string]any{
+		"q":                     q.Text,
+		"offset":                q.Offset,
+		"limit":                 q.limit(),
+		"attributesToHighlight": []string{"*"},
+		"highlightPreTag":       HighlightPre,
+		"highlightPostTag":      HighlightPost,
+		"showRankingScore":      true,
+	}
+	if len(q.Fields) > 0 {
+		body["attributesToSearchOn"] = q.Fields
+		body["attributesToHighlight"] = q.Fields
+	}
+	if len(q.Filters) > 0 {
+		filters := make([]string, 0, len(q.Filters))
+		for k, v := range q.Filters {
+			filters = append(filters, k+" = "+meiliValue(v))
+		}
+		body["filter"] = filters
+	}
+
+	var resp meiliResponse
+	if err := m.do(ctx, http.MethodPost, m.indexPath(q.Index, "/search"), "application/json", body, &resp); err != nil {
+		return nil, err
+	}
+	res := &Result{Total: resp.EstimatedTotalHits, Hits: make([]Hit, 0, len(resp.Hits))}
+	for _, doc := range resp.Hits {
+		hit := Hit{ID: fmt.Sprint(doc["id"]), Score: numberOf(doc["_rankingScore"])}
+		formatted, _ := doc["_formatted"].(map[string]any)
+		for field, v := range formatted {
+			if s, ok := v.(string); ok && strings.Contains(s, HighlightPre) {
+				if hit.Highlights == nil {
+					hit.Highlights = make(map[string]string)
+				}
+				hit.Highlights[field] = s
+			}
+		}
+		res.Hits = append(res.Hits, hit)
+	}
+	return res, nil
+}
+
+// meiliValue 将过滤值格式化为 Meilisearch 过滤表达式中的值，字符串加引号并转义
+func meiliValue(v any) string {
+	switch x := v.(type) {
+	case bool:
+		return strconv.FormatBool(x)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(x)
+	}
+	return strconv.Quote(fmt.Sprint(v))
+}
+
+// numberOf 读取以 UseNumber 解码的数值
+func numberOf(v any) float64 {
+	if n, ok := v.(interface{ Float64() (float64, error) }); ok {
+		f, _ := n.Float64()
+		return f
+	}
+	f, _ := v.(float64)
+	return f
+}
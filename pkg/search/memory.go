@@ -0,0 +1,112 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Memory 进程内检索引擎：逐条匹配全部检索词（不区分大小写），按命中次数排序。
+// 适用于开发与测试，数据随进程退出丢失
+type Memory struct {
+	mu      sync.RWMutex
+	indexes map[string]map[string]map[string]any // 索引 → 文档 ID → 字段
+}
+
+// NewMemory 创建内存检索引擎
+func NewMemory() *Memory {
+	return &Memory{indexes: make(map[string]map[string]map[string]any)}
+}
+
+// Index 实现 Engine
+func (m *Memory) Index(_ context.Context, index string, docs ...Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	idx, ok := m.indexes[index]
+	if !ok {
+		idx = make(map[string]map[string]any)
+		m.indexes[index] = idx
+	}
+	for _, d := range docs {
+		idx[d.ID] = d.Fields
+	}
+	return nil
+}
+
+// Delete 实现 Engine
+func (m *Memory) Delete(_ context.Context, index string, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.indexes[index], id)
+	}
+	return nil
+}
+
+// Search 实现 Engine
+func (m *Memory) Search(_ context.Context, q Query) (*Result, error) {
+	words := terms(q.Text)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var hits []Hit
+	for id, fields := range m.indexes[q.Index] {
+		if !matchFilters(fields, q.Filters) {
+			continue
+		}
+		hit := Hit{ID: id}
+		matched := make(map[string]bool, len(words))
+		for name, v := range fields {
+			if len(q.Fields) > 0 && !slices.Contains(q.Fields, name) {
+				continue
+			}
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			lower := strings.ToLower(s)
+			for _, w := range words {
+				if n := strings.Count(lower, strings.ToLower(w)); n > 0 {
+					matched[w] = true
+					hit.Score += float64(n)
+				}
+			}
+			if snippet, ok := highlight(s, words); ok {
+				if hit.Highlights == nil {
+					hit.Highlights = make(map[string]string)
+				}
+				hit.Highlights[name] = snippet
+			}
+		}
+		// 无检索词时返回全部（仅按过滤条件）
+		if len(matched) == len(words) {
+			hits = append(hits, hit)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	res := &Result{Total: int64(len(hits))}
+	if q.Offset < len(hits) {
+		res.Hits = hits[q.Offset:min(len(hits), q.Offset+q.limit())]
+	}
+	return res, nil
+}
+
+// matchFilters 文档字段是否等于全部过滤值（按字符串形式比较，兼容数字类型差异）
+func matchFilters(fields, filters map[string]any) bool {
+	for k, want := range filters {
+		got, ok := fields[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlScoreColumn 查询结果中相关度的列别名
+const mysqlScoreColumn = "_search_score"
+
+// MySQL 基于 MySQL FULLTEXT 索引的检索引擎：索引名即表名，数据表本身就是索引，Index / Delete 无需操作。
+// 检索的列须已建立 FULLTEXT 索引（中文内容建议使用 WITH PARSER ngram），并通过 Columns 注册：
+//
+//	ALTER TABLE article ADD FULLTEXT INDEX ft_article (title, body) WITH PARSER ngram;
+//
+//	engine := search.NewMySQL(db).Columns("article", "title", "body")
+type MySQL struct {
+	db      *gorm.DB
+	key     string
+	boolean bool
+
+	mu      sync.RWMutex
+	columns map[string][]string
+}
+
+// MySQLOption MySQL 检索引擎选项
+type MySQLOption func(*MySQL)
+
+// WithMySQLKey 文档 ID 对应的列，默认 id
+func WithMySQLKey(column string) MySQLOption {
+	return func(m *MySQL) { m.key = column }
+}
+
+// WithMySQLBooleanMode 以 IN BOOLEAN MODE 检索，支持 +必须 -排除 "短语" 前缀* 等运算符；默认为自然语言模式
+func WithMySQLBooleanMode() MySQLOption {
+	return func(m *MySQL) { m.boolean = true }
+}
+
+// NewMySQL 创建 MySQL FULLTEXT 检索引擎
+func NewMySQL(db *gorm.DB, opts ...MySQLOption) *MySQL {
+	m := &MySQL{db: db, key: "id", columns: make(map[string][]string)}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Columns 注册表上 FULLTEXT 索引包含的列（顺序须与索引定义一致），检索时未指定 Query.Fields 则匹配这些列
+func (m *MySQL) Columns(table string, columns ...string) *MySQL {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.columns[table] = columns
+	return m
+}
+
+// Index 实现 Engine，数据已在表中，无需操作
+func (m *MySQL) Index(context.Context, string, ...Document) error { return nil }
+
+// Delete 实现 Engine，数据已在表中，无需操作
+func (m *MySQL) Delete(context.Context, string, ...string) error { return nil }
+
+// Search 实现 Engine：按 MATCH ... AGAINST 的相关度排序，高亮由返回的列内容生成
+func (m *MySQL) Search(ctx context.Context, q Query) (*Result, error) {
+	columns := q.Fields
+	if len(columns) == 0 {
+		m.mu.RLock()
+		columns = m.columns[q.Index]
+		m.mu.RUnlock()
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: 表 %s 未注册 FULLTEXT 列", ErrNoFields, q.Index)
+	}
+
+	var total int64
+	if err := m.query(m.db.WithContext(ctx), q, columns).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	res := &Result{Total: total}
+	if total == 0 {
+		return res, nil
+	}
+
+	var rows []map[string]any
+	if err := m.page(m.query(m.db.WithContext(ctx), q, columns), q, columns).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	words := terms(q.Text)
+	res.Hits = make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hit := Hit{ID: fmt.Sprint(row[m.key]), Score: toFloat(row[mysqlScoreColumn])}
+		for _, col := range columns {
+			if snippet, ok := highlight(toString(row[col]), words); ok {
+				if hit.Highlights == nil {
+					hit.Highlights = make(map[string]string)
+				}
+				hit.Highlights[col] = snippet
+			}
+		}
+		res.Hits = append(res.Hits, hit)
+	}
+	return res, nil
+}
+
+// match 返回 MATCH (列) AGAINST (? 模式) 表达式
+func (m *MySQL) match(tx *gorm.DB, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = tx.Statement.Quote(col)
+	}
+	mode := "IN NATURAL LANGUAGE MODE"
+	if m.boolean {
+		mode = "IN BOOLEAN MODE"
+	}
+	return fmt.Sprintf("MATCH (%s) AGAINST (? %s)", strings.Join(quoted, ", "), mode)
+}
+
+// query 构建匹配与过滤条件（计数与分页查询共用）
+func (m *MySQL) query(tx *gorm.DB, q Query, columns []string) *gorm.DB {
+	tx = tx.Table(q.Index).Where(m.match(tx, columns), q.Text)
+	for k, v := range q.Filters {
+		tx = tx.Where(clause.Eq{Column: clause.Column{Name: k}, Value: v})
+	}
+	return tx
+}
+
+// page 选择 ID、检索列与相关度，按相关度降序分页
+func (m *MySQL) page(tx *gorm.DB, q Query, columns []string) *gorm.DB {
+	selects := make([]string, 0, len(columns)+2)
+	selects = append(selects, tx.Statement.Quote(m.key))
+	for _, col := range columns {
+		selects = append(selects, tx.Statement.Quote(col))
+	}
+	selects = append(selects, m.match(tx, columns)+" AS "+mysqlScoreColumn)
+	return tx.Select(strings.Join(selects, ", "), q.Text).
+		Order(mysqlScoreColumn + " DESC").
+		Offset(q.Offset).Limit(q.limit())
+}
+
+// toFloat 将驱动返回的数值转为 float64
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case []byte:
+		var f float64
+		_, _ = fmt.Sscan(string(n), &f)
+		return f
+	}
+	return 0
+}
+
+// toString 将驱动返回的文本列转为字符串
+func toString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,36 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// TestMySQLQuery 生成 MATCH ... AGAINST 检索与过滤条件，按相关度排序分页
+func TestMySQLQuery(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "u:p@tcp(127.0.0.1:1)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMySQL(db, WithMySQLBooleanMode()).Columns("article", "title", "body")
+	q := Query{Index: "article", Text: "+go", Filters: map[string]any{"status": "published"}, Offset: 20, Limit: 10}
+
+	var rows []map[string]any
+	stmt := m.page(m.query(db, q, m.columns["article"]), q, m.columns["article"]).Find(&rows).Statement
+	want := "SELECT `id`, `title`, `body`, MATCH (`title`, `body`) AGAINST (? IN BOOLEAN MODE) AS _search_score FROM `article` " +
+		"WHERE MATCH (`title`, `body`) AGAINST (? IN BOOLEAN MODE) AND `status` = ? ORDER BY _search_score DESC LIMIT ? OFFSET ?"
+	if got := stmt.SQL.String(); got != want {
+		t.Errorf("SQL:\n%s\n期望:\n%s", got, want)
+	}
+	if len(stmt.Vars) != 5 || stmt.Vars[0] != "+go" || stmt.Vars[2] != "published" {
+		t.Errorf("参数 %v", stmt.Vars)
+	}
+
+	if _, err := m.Search(context.Background(), Query{Index: "comment", Text: "go"}); !errors.Is(err, ErrNoFields) {
+		t.Errorf("未注册列的表应返回 ErrNoFields: %v", err)
+	}
+}
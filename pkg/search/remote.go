@@ -0,0 +1,96 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+)
+
+// DefaultTimeout 请求检索服务的默认超时
+const DefaultTimeout = 10 * time.Second
+
+// remote 通过 HTTP API 访问的检索服务（Meilisearch、Elasticsearch）的公共部分
+type remote struct {
+	baseURL string
+	prefix  string
+	client  *http.Client
+	header  http.Header
+}
+
+// ClientOption 远程检索服务选项
+type ClientOption func(*remote)
+
+// WithIndexPrefix 为索引名添加前缀，多个应用或环境共用同一检索服务时区分索引
+func WithIndexPrefix(prefix string) ClientOption {
+	return func(r *remote) { r.prefix = prefix }
+}
+
+// WithHTTPClient 设置请求检索服务的 HTTP 客户端，默认为传递请求 ID、超时 DefaultTimeout 的客户端
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(r *remote) { r.client = client }
+}
+
+// newRemote 创建远程服务的公共部分
+func newRemote(baseURL string, opts []ClientOption) remote {
+	r := remote{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  httpclient.New(DefaultTimeout),
+		header:  make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// index 返回带前缀的索引名
+func (r *remote) index(name string) string {
+	return r.prefix + name
+}
+
+// do 发送请求，body 为 []byte 时原样发送（如 NDJSON），否则编码为 JSON；响应为 2xx 时解码到 out（可为 nil）
+func (r *remote) do(ctx context.Context, method, path, contentType string, body, out any) error {
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		payload = b
+	default:
+		var err error
+		if payload, err = jsonx.Marshal(b); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	dec := jsonx.NewDecoder(resp.Body)
+	// 数值型文档 ID 保持原样（如 12 而非 1.2e+01）
+	dec.UseNumber()
+	return dec.Decode(out)
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recordServer 记录收到的请求并返回固定响应
+func recordServer(t *testing.T, response string, got *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*got = append(*got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Authorization")+"\n"+string(body))
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestMeilisearch 写入带 id 的文档、前缀索引名、过滤表达式与高亮解析
+func TestMeilisearch(t *testing.T) {
+	var reqs []string
+	srv := recordServer(t, `{"hits":[{"id":12,"title":"Go","_formatted":{"id":"12","title":"<em>Go</em>"},"_rankingScore":0.9}],"estimatedTotalHits":5}`, &reqs)
+	m := NewMeilisearch(srv.URL, "key", WithIndexPrefix("test_"))
+	ctx := context.Background()
+
+	if err := m.Index(ctx, "article", Document{ID: "12", Fields: map[string]any{"title": "Go"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(reqs[0], "POST /indexes/test_article/documents?primaryKey=id Bearer key") || !strings.Contains(reqs[0], `"id":"12"`) {
+		t.Errorf("写入请求 %q", reqs[0])
+	}
+
+	res, err := m.Search(ctx, Query{Index: "article", Text: "go", Filters: map[string]any{"status": `a"b`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]any
+	_ = json.Unmarshal([]byte(reqs[1][strings.IndexByte(reqs[1], '\n')+1:]), &body)
+	if f, _ := body["filter"].([]any); len(f) != 1 || f[0] != `status = "a\"b"` {
+		t.Errorf("过滤表达式 %v", body["filter"])
+	}
+	if res.Total != 5 || len(res.Hits) != 1 || res.Hits[0].ID != "12" || res.Hits[0].Score != 0.9 {
+		t.Fatalf("结果 %+v", res)
+	}
+	if h := res.Hits[0].Highlights; len(h) != 1 || h["title"] != "<em>Go</em>" {
+		t.Errorf("高亮 %v", h)
+	}
+}
+
+// TestElasticsearch _bulk 写入与删除、部分失败报错、检索结果解析
+func TestElasticsearch(t *testing.T) {
+	var reqs []string
+	srv := recordServer(t, `{"errors":false,"items":[]}`, &reqs)
+	e := NewElasticsearch(srv.URL, "", "elastic", "secret")
+	ctx := context.Background()
+
+	_ = e.Index(ctx, "article", Document{ID: "1", Fields: map[string]any{"title": "Go"}})
+	_ = e.Delete(ctx, "article", "2")
+	want := "POST /_bulk Basic ZWxhc3RpYzpzZWNyZXQ=\n" +
+		"{\"index\":{\"_id\":\"1\",\"_index\":\"article\"}}\n{\"title\":\"Go\"}\n"
+	if reqs[0] != want {
+		t.Errorf("写入请求 %q", reqs[0])
+	}
+	if !strings.Contains(reqs[1], `{"delete":{"_id":"2","_index":"article"}}`) {
+		t.Errorf("删除请求 %q", reqs[1])
+	}
+
+	srv = recordServer(t, `{"errors":true,"items":[{"index":{"_id":"1","status":400,"error":{"type":"mapper_parsing_exception","reason":"bad"}}}]}`, &reqs)
+	e = NewElasticsearch(srv.URL, "k", "", "")
+	if err := e.Index(ctx, "article", Document{ID: "1"}); err == nil || !strings.Contains(err.Error(), "mapper_parsing_exception") {
+		t.Errorf("部分失败应返回错误: %v", err)
+	}
+
+	srv = recordServer(t, `{"hits":{"total":{"value":7},"hits":[{"_id":"3","_score":1.5,"highlight":{"body":["a <em>go</em>","b <em>go</em>"]}}]}}`, &reqs)
+	e = NewElasticsearch(srv.URL, "k", "", "")
+	res, err := e.Search(ctx, Query{Index: "article", Text: "go", Fields: []string{"body"}, Filters: map[string]any{"status": "published"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := reqs[len(reqs)-1]
+	if !strings.HasPrefix(last, "POST /article/_search ApiKey k") || !strings.Contains(last, `"term":{"status":"published"}`) {
+		t.Errorf("检索请求 %q", last)
+	}
+	if res.Total != 7 || res.Hits[0].ID != "3" || res.Hits[0].Highlights["body"] != "a <em>go</em> … b <em>go</em>" {
+		t.Errorf("结果 %+v", res)
+	}
+}
@@ -0,0 +1,189 @@
+// Package search 提供全文检索集成：模型实现 Searchable 声明索引内容，检索引擎（内存、MySQL FULLTEXT、
+// Meilisearch、Elasticsearch）只返回命中的 ID 与高亮片段，数据仍以数据库为准，由 Hydrate 按命中顺序加载模型。
+//
+//	func (a *Article) SearchIndex() string { return "article" }
+//	func (a *Article) SearchKey() string   { return strconv.FormatUint(uint64(a.ID), 10) }
+//	func (a *Article) SearchDocument() map[string]any {
+//	    return map[string]any{"title": a.Title, "body": a.Body, "status": a.Status}
+//	}
+//
+//	res, err := search.Search(ctx, search.Query{Index: "article", Text: "golang 并发", Filters: map[string]any{"status": "published"}, Limit: 20})
+//	articles, err := search.Hydrate[*model.Article](db.WithContext(ctx), res)
+//	res.Hits[0].SafeHighlight("title") // 模板中输出 <em> 标记的高亮
+//
+// 开启 search.sync（需同时开启 database.model_events）后，模型的创建、更新、删除自动同步到索引（见 Sync）；
+// 已有数据通过 Import 分批导入。引擎按 search.* 配置创建（见 NewFromConfig），由 bootstrap 设为全局实例。
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"sync/atomic"
+)
+
+// 高亮片段中包裹命中词的标记
+const (
+	HighlightPre  = "<em>"
+	HighlightPost = "</em>"
+)
+
+var (
+	// ErrNotConfigured 全局检索引擎未初始化
+	ErrNotConfigured = errors.New("检索引擎未初始化")
+	// ErrNoFields 检索时无法确定要匹配的字段（如 MySQL 驱动未注册 FULLTEXT 列）
+	ErrNoFields = errors.New("未指定检索字段")
+)
+
+// Searchable 可被检索的模型
+type Searchable interface {
+	// SearchIndex 索引名，MySQL 驱动中即表名
+	SearchIndex() string
+	// SearchKey 文档 ID，通常为主键的字符串形式
+	SearchKey() string
+	// SearchDocument 写入索引的字段，用于过滤的字段也应包含在内
+	SearchDocument() map[string]any
+}
+
+// Document 索引中的一条文档
+type Document struct {
+	ID     string
+	Fields map[string]any
+}
+
+// DocumentOf 由模型构建文档
+func DocumentOf(s Searchable) Document {
+	return Document{ID: s.SearchKey(), Fields: s.SearchDocument()}
+}
+
+// Query 检索条件
+type Query struct {
+	Index   string
+	Text    string
+	Fields  []string       // 匹配与高亮的字段，为空时由引擎决定（全部字段或已注册的 FULLTEXT 列）
+	Filters map[string]any // 字段等值过滤
+	Offset  int
+	Limit   int // 为 0 时使用 DefaultLimit
+}
+
+// DefaultLimit 未指定 Limit 时返回的命中数
+const DefaultLimit = 20
+
+// limit 返回有效的 Limit
+func (q Query) limit() int {
+	if q.Limit <= 0 {
+		return DefaultLimit
+	}
+	return q.Limit
+}
+
+// Hit 一条命中
+type Hit struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+	// Highlights 字段 → 以 HighlightPre / HighlightPost 标记命中词的片段，内容未经 HTML 转义
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// SafeHighlight 返回字段的高亮片段，除高亮标记外的内容均经 HTML 转义，可直接输出到模板；字段无高亮时返回空
+func (h Hit) SafeHighlight(field string) template.HTML {
+	escaped := html.EscapeString(h.Highlights[field])
+	escaped = strings.ReplaceAll(escaped, html.EscapeString(HighlightPre), HighlightPre)
+	escaped = strings.ReplaceAll(escaped, html.EscapeString(HighlightPost), HighlightPost)
+	return template.HTML(escaped)
+}
+
+// Result 检索结果
+type Result struct {
+	Hits  []Hit `json:"hits"`
+	Total int64 `json:"total"` // 匹配的总数（部分引擎为估算值），用于分页
+}
+
+// IDs 按命中顺序返回文档 ID
+func (r *Result) IDs() []string {
+	ids := make([]string, len(r.Hits))
+	for i, h := range r.Hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+// Hit 按 ID 查找命中（用于在模型列表中取对应的高亮）
+func (r *Result) Hit(id string) (Hit, bool) {
+	for _, h := range r.Hits {
+		if h.ID == id {
+			return h, true
+		}
+	}
+	return Hit{}, false
+}
+
+// Engine 检索引擎
+type Engine interface {
+	// Index 写入或覆盖文档
+	Index(ctx context.Context, index string, docs ...Document) error
+	// Delete 删除文档，不存在时不报错
+	Delete(ctx context.Context, index string, ids ...string) error
+	// Search 检索，命中按相关度降序
+	Search(ctx context.Context, q Query) (*Result, error)
+}
+
+// Save 将模型写入各自的索引
+func Save(ctx context.Context, engine Engine, models ...Searchable) error {
+	for index, docs := range groupByIndex(models, DocumentOf) {
+		if err := engine.Index(ctx, index, docs...); err != nil {
+			return fmt.Errorf("写入索引 %s 失败: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// Remove 从各自的索引中删除模型
+func Remove(ctx context.Context, engine Engine, models ...Searchable) error {
+	for index, ids := range groupByIndex(models, Searchable.SearchKey) {
+		if err := engine.Delete(ctx, index, ids...); err != nil {
+			return fmt.Errorf("删除索引 %s 的文档失败: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// groupByIndex 按索引名分组
+func groupByIndex[V any](models []Searchable, fn func(Searchable) V) map[string][]V {
+	groups := make(map[string][]V)
+	for _, m := range models {
+		groups[m.SearchIndex()] = append(groups[m.SearchIndex()], fn(m))
+	}
+	return groups
+}
+
+var defaultEngine atomic.Pointer[Engine]
+
+// SetDefault 设置全局检索引擎（由 bootstrap 调用），传入 nil 清除
+func SetDefault(e Engine) {
+	if e == nil {
+		defaultEngine.Store(nil)
+		return
+	}
+	defaultEngine.Store(&e)
+}
+
+// Default 返回全局检索引擎，未初始化时返回 nil
+func Default() Engine {
+	if e := defaultEngine.Load(); e != nil {
+		return *e
+	}
+	return nil
+}
+
+// Search 使用全局检索引擎检索
+func Search(ctx context.Context, q Query) (*Result, error) {
+	e := Default()
+	if e == nil {
+		return nil, ErrNotConfigured
+	}
+	return e.Search(ctx, q)
+}
@@ -0,0 +1,128 @@
+package search
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type article struct {
+	ID     uint
+	Title  string
+	Body   string
+	Status string
+}
+
+func (a *article) SearchIndex() string { return "article" }
+func (a *article) SearchKey() string   { return strconv.FormatUint(uint64(a.ID), 10) }
+func (a *article) SearchDocument() map[string]any {
+	return map[string]any{"title": a.Title, "body": a.Body, "status": a.Status}
+}
+
+// TestMemorySearch 全部检索词命中、过滤、按命中次数排序、分页与高亮
+func TestMemorySearch(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	_ = Save(ctx, m,
+		&article{ID: 1, Title: "Go 并发", Body: "goroutine 与 channel", Status: "published"},
+		&article{ID: 2, Title: "Go 入门", Body: "Go 的 goroutine 很轻量，goroutine 调度", Status: "published"},
+		&article{ID: 3, Title: "Go 草稿", Body: "goroutine", Status: "draft"},
+		&article{ID: 4, Title: "Rust", Body: "所有权", Status: "published"},
+	)
+
+	res, err := m.Search(ctx, Query{Index: "article", Text: "go GOROUTINE", Filters: map[string]any{"status": "published"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := res.IDs(); res.Total != 2 || len(ids) != 2 || ids[0] != "2" || ids[1] != "1" {
+		t.Fatalf("命中 %v (total %d)，期望 [2 1]", ids, res.Total)
+	}
+	if got := res.Hits[1].Highlights["body"]; got != "<em>goroutine</em> 与 channel" {
+		t.Errorf("高亮 %q", got)
+	}
+
+	res, _ = m.Search(ctx, Query{Index: "article", Text: "goroutine", Offset: 1, Limit: 1})
+	if res.Total != 3 || len(res.Hits) != 1 {
+		t.Errorf("分页: total=%d hits=%v", res.Total, res.IDs())
+	}
+
+	_ = Remove(ctx, m, &article{ID: 2})
+	if res, _ := m.Search(ctx, Query{Index: "article", Text: "轻量"}); res.Total != 0 {
+		t.Errorf("删除后不应命中: %v", res.IDs())
+	}
+}
+
+// TestHighlight 不区分大小写、合并相邻命中、长文本截取片段；SafeHighlight 只保留高亮标记
+func TestHighlight(t *testing.T) {
+	if got, _ := highlight("Hello World hello", []string{"hello", "wor"}); got != "<em>Hello</em> <em>Wor</em>ld <em>hello</em>" {
+		t.Errorf("highlight = %q", got)
+	}
+	if _, ok := highlight("abc", []string{"x"}); ok {
+		t.Error("未命中时应返回 false")
+	}
+	long := strings.Repeat("字", 200) + "关键" + strings.Repeat("字", 200)
+	got, _ := highlight(long, []string{"关键"})
+	if len([]rune(got)) > snippetLength+len(HighlightPre)+len(HighlightPost)+2 || got[:len("…")] != "…" {
+		t.Errorf("长文本应截取片段: %q", got)
+	}
+
+	h := Hit{Highlights: map[string]string{"title": `<script>x</script> <em>go</em>`}}
+	if got := h.SafeHighlight("title"); got != "&lt;script&gt;x&lt;/script&gt; <em>go</em>" {
+		t.Errorf("SafeHighlight = %q", got)
+	}
+}
+
+// TestSyncAndHydrate 模型事件同步索引，Hydrate 按命中顺序加载模型并跳过已删除的记录
+func TestSyncAndHydrate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&article{}); err != nil {
+		t.Fatal(err)
+	}
+	bus := eventbus.New()
+	if err := db.Use(database.NewModelEvents(bus)); err != nil {
+		t.Fatal(err)
+	}
+	m := NewMemory()
+	Sync(bus, m, WithSyncReload(db))
+
+	ctx := context.Background()
+	db.Create([]*article{{Title: "Go 并发"}, {Title: "Go 泛型"}})
+	db.Model(&article{ID: 2}).Update("title", "Go 泛型与并发")
+
+	res, _ := m.Search(ctx, Query{Index: "article", Text: "并发"})
+	if ids := res.IDs(); len(ids) != 2 {
+		t.Fatalf("同步后命中 %v", ids)
+	}
+	if doc := m.indexes["article"]["2"]; doc["status"] != "" || doc["title"] != "Go 泛型与并发" {
+		t.Errorf("单列更新应重新加载完整模型: %v", doc)
+	}
+
+	// 仅以条件删除时事件中没有文档 ID，索引未同步，Hydrate 跳过数据库中已不存在的命中
+	db.Where("id = ?", 1).Delete(&article{})
+	articles, err := Hydrate[*article](db, res)
+	if err != nil || len(articles) != 1 || articles[0].ID != 2 {
+		t.Errorf("Hydrate = %v, %v", articles, err)
+	}
+
+	db.Delete(&article{ID: 2})
+	if res, _ := m.Search(ctx, Query{Index: "article", Text: "泛型"}); res.Total != 0 {
+		t.Errorf("删除后应从索引移除: %v", res.IDs())
+	}
+
+	db.Create([]*article{{Title: "a"}, {Title: "b"}, {Title: "c"}})
+	fresh := NewMemory()
+	n, err := Import[*article](ctx, db, fresh, 2)
+	if err != nil || n != 3 || len(fresh.indexes["article"]) != 3 {
+		t.Errorf("Import = %d, %v", n, err)
+	}
+}
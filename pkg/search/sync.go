@@ -0,0 +1,174 @@
+package search
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gorilla-go/go-framework/pkg/concurrent"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// syncConfig 索引同步配置
+type syncConfig struct {
+	pool *concurrent.Pool
+	db   *gorm.DB
+}
+
+// SyncOption 索引同步选项
+type SyncOption func(*syncConfig)
+
+// WithSyncQueue 通过工作池异步写入索引，不阻塞数据库写操作（工作池队列已满或已停止时同步执行）
+func WithSyncQueue(pool *concurrent.Pool) SyncOption {
+	return func(c *syncConfig) { c.pool = pool }
+}
+
+// WithSyncReload 更新时按主键重新加载模型后再写入索引。以 map 或单列方式更新时事件中的模型并不完整，
+// 直接写入会丢失索引中的其他字段；注意事务内的更新在提交前读取不到，仍使用事件中的模型
+func WithSyncReload(db *gorm.DB) SyncOption {
+	return func(c *syncConfig) { c.db = db }
+}
+
+// Sync 订阅模型生命周期事件（需启用 database.model_events），将实现 Searchable 的模型在创建、更新后写入索引，
+// 删除后从索引移除。删除须以带主键的模型实例执行（db.Delete(&article)），仅传主键条件时无法得知文档 ID。
+// 事件在事务提交前触发，需要严格一致时改为通过 outbox 在提交后调用 Save / Remove
+func Sync(bus *eventbus.EventBus, engine Engine, opts ...SyncOption) *eventbus.Subscription {
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	cfg := &syncConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return bus.OnE("model.*", func(ctx context.Context, args ...interface{}) error {
+		if len(args) == 0 {
+			return nil
+		}
+		e, ok := args[0].(*database.ModelEvent)
+		if !ok {
+			return nil
+		}
+		models := searchables(e.Model)
+		if len(models) == 0 {
+			return nil
+		}
+		apply := func(ctx context.Context) error {
+			if e.Action == "deleted" {
+				return Remove(ctx, engine, models...)
+			}
+			if e.Action == "updated" && cfg.db != nil {
+				models = reload(ctx, cfg.db, models)
+			}
+			return Save(ctx, engine, models...)
+		}
+		if cfg.pool != nil && cfg.pool.Go(apply) == nil {
+			return nil
+		}
+		return apply(ctx)
+	})
+}
+
+// searchables 取出事件模型中实现 Searchable 且已有文档 ID 的实例，支持单个模型与模型切片
+func searchables(model any) []Searchable {
+	if s, ok := model.(Searchable); ok {
+		if hasKey(s) {
+			return []Searchable{s}
+		}
+		return nil
+	}
+	v := reflect.ValueOf(model)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+	var out []Searchable
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() != reflect.Pointer && elem.CanAddr() {
+			elem = elem.Addr()
+		}
+		if s, ok := elem.Interface().(Searchable); ok && hasKey(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// hasKey 模型是否已有文档 ID（零值主键视为没有）
+func hasKey(s Searchable) bool {
+	key := s.SearchKey()
+	return key != "" && key != "0"
+}
+
+// reload 按主键重新加载模型，读取失败（如事务未提交）时保留原模型
+func reload(ctx context.Context, db *gorm.DB, models []Searchable) []Searchable {
+	out := make([]Searchable, len(models))
+	for i, m := range models {
+		out[i] = m
+		t := reflect.TypeOf(m)
+		if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		fresh := reflect.New(t.Elem()).Interface()
+		err := db.WithContext(ctx).Where(clause.IN{Column: clause.PrimaryColumn, Values: []any{m.SearchKey()}}).Take(fresh).Error
+		if err == nil {
+			out[i] = fresh.(Searchable)
+		}
+	}
+	return out
+}
+
+// Import 分批读取模型 T 的全部记录写入索引（首次启用检索或重建索引时使用），返回写入的文档数；
+// batchSize 为 0 时使用 database.DefaultBatchSize。db 可带条件以只导入部分记录：
+//
+//	n, err := search.Import[*model.Article](ctx, db.Where("status = ?", "published"), engine, 0)
+func Import[T Searchable](ctx context.Context, db *gorm.DB, engine Engine, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = database.DefaultBatchSize
+	}
+	var rows []T
+	count := 0
+	err := db.WithContext(ctx).FindInBatches(&rows, batchSize, func(tx *gorm.DB, _ int) error {
+		models := make([]Searchable, len(rows))
+		for i, r := range rows {
+			models[i] = r
+		}
+		if err := Save(ctx, engine, models...); err != nil {
+			return err
+		}
+		count += len(rows)
+		return nil
+	}).Error
+	return count, err
+}
+
+// Hydrate 按命中的文档 ID 从数据库加载模型，结果保持命中顺序；索引中存在但数据库中已删除的文档被跳过。
+// db 可带预加载等条件：search.Hydrate[*model.Article](db.Preload("Author"), res)
+func Hydrate[T Searchable](db *gorm.DB, res *Result) ([]T, error) {
+	if res == nil || len(res.Hits) == 0 {
+		return nil, nil
+	}
+	ids := make([]any, len(res.Hits))
+	for i, h := range res.Hits {
+		ids[i] = h.ID
+	}
+	var rows []T
+	if err := db.Where(clause.IN{Column: clause.PrimaryColumn, Values: ids}).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]T, len(rows))
+	for _, r := range rows {
+		byKey[r.SearchKey()] = r
+	}
+	out := make([]T, 0, len(rows))
+	for _, h := range res.Hits {
+		if r, ok := byKey[h.ID]; ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
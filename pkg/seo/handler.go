@@ -0,0 +1,150 @@
+package seo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// Mount 注册 /robots.txt、/sitemap.xml、/sitemap.xml.gz 与 /sitemaps/sitemap-N.xml.gz
+func Mount(r gin.IRoutes, cfg *config.Config) {
+	r.GET("/robots.txt", Robots(cfg))
+	h := &sitemapHandler{baseURL: cfg.SEO.BaseURL, ttl: time.Duration(cfg.SEO.SitemapTTL) * time.Second}
+	r.GET("/sitemap.xml", h.root(false))
+	r.GET("/sitemap.xml.gz", h.root(true))
+	r.GET("/sitemaps/:file", h.chunk)
+}
+
+// Robots 返回 robots.txt 处理器：按 seo.allow / seo.disallow 输出规则并声明站点地图地址；
+// seo.block_non_production 开启时，非生产环境禁止抓取全站，避免预发布站点被收录
+func Robots(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b strings.Builder
+		b.WriteString("User-agent: *\n")
+		if cfg.SEO.BlockNonProduction && !cfg.IsProduction() {
+			b.WriteString("Disallow: /\n")
+		} else {
+			for _, p := range cfg.SEO.Allow {
+				fmt.Fprintf(&b, "Allow: %s\n", p)
+			}
+			for _, p := range cfg.SEO.Disallow {
+				fmt.Fprintf(&b, "Disallow: %s\n", p)
+			}
+			if len(cfg.SEO.Disallow) == 0 {
+				b.WriteString("Disallow:\n")
+			}
+		}
+		fmt.Fprintf(&b, "\nSitemap: %s\n", absURL(baseURL(c, cfg.SEO.BaseURL), "/sitemap.xml"))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(b.String()))
+	}
+}
+
+// sitemapHandler 站点地图处理器，构建结果缓存 ttl
+type sitemapHandler struct {
+	baseURL string
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	sitemap *Sitemap
+	builtAt time.Time
+}
+
+// load 返回缓存的站点地图，过期时重新构建
+func (h *sitemapHandler) load(ctx context.Context) (*Sitemap, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sitemap != nil && time.Since(h.builtAt) < h.ttl {
+		return h.sitemap, nil
+	}
+	s, err := Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.sitemap, h.builtAt = s, time.Now()
+	return s, nil
+}
+
+// root 输出 /sitemap.xml：URL 不超过单文件上限时为 <urlset>，否则为指向各 gzip 分片的 <sitemapindex>
+func (h *sitemapHandler) root(compress bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, err := h.load(c.Request.Context())
+		if err != nil {
+			_ = c.Error(err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		base := baseURL(c, h.baseURL)
+		write(c, compress, func(w io.Writer) error {
+			if s.Chunks() > 1 {
+				return s.WriteIndex(w, base)
+			}
+			return s.WriteURLSet(w, base, 1)
+		})
+	}
+}
+
+// chunk 输出 /sitemaps/sitemap-N.xml.gz
+func (h *sitemapHandler) chunk(c *gin.Context) {
+	name, ok := strings.CutSuffix(strings.TrimPrefix(c.Param("file"), "sitemap-"), ".xml.gz")
+	n, err := strconv.Atoi(name)
+	if !ok || err != nil || n < 1 {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	s, err := h.load(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if n > s.Chunks() {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	base := baseURL(c, h.baseURL)
+	write(c, true, func(w io.Writer) error { return s.WriteURLSet(w, base, n) })
+}
+
+// write 输出 XML，compress 时以 gzip 文件输出
+func write(c *gin.Context, compress bool, fn func(io.Writer) error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	err := fn(w)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	contentType := "application/xml; charset=utf-8"
+	if compress {
+		contentType = "application/gzip"
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// baseURL 返回站点地址：优先使用配置，否则取当前请求的协议与主机
+func baseURL(c *gin.Context, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	return request.GetScheme(c) + "://" + request.GetHost(c)
+}
@@ -0,0 +1,106 @@
+package seo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// TestSitemapChunks 超过单文件上限时拆分分片，索引指向各 gzip 分片
+func TestSitemapChunks(t *testing.T) {
+	defer func(n int) { MaxURLsPerSitemap = n }(MaxURLsPerSitemap)
+	MaxURLsPerSitemap = 2
+
+	s := &Sitemap{}
+	mod := time.Date(2024, 6, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	s.Add(
+		URL{Loc: "/", Priority: 1, ChangeFreq: Daily},
+		URL{Loc: "/about"},
+		URL{Loc: "/posts/1", LastMod: mod},
+		URL{Loc: "https://cdn.example.com/page"},
+		URL{Loc: "posts/2", Priority: 0.55},
+	)
+	if s.Chunks() != 3 {
+		t.Fatalf("分片数 %d", s.Chunks())
+	}
+
+	var index bytes.Buffer
+	if err := s.WriteIndex(&index, "https://example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<sitemapindex", "<loc>https://example.com/sitemaps/sitemap-3.xml.gz</loc>", "<lastmod>2024-06-01T00:00:00Z</lastmod>"} {
+		if !strings.Contains(index.String(), want) {
+			t.Errorf("索引缺少 %s:\n%s", want, index.String())
+		}
+	}
+
+	var first, last bytes.Buffer
+	s.WriteURLSet(&first, "https://example.com", 1)
+	s.WriteURLSet(&last, "https://example.com", 3)
+	for _, want := range []string{"<loc>https://example.com/</loc>", "<changefreq>daily</changefreq>", "<priority>1.0</priority>", "<loc>https://example.com/about</loc>"} {
+		if !strings.Contains(first.String(), want) {
+			t.Errorf("分片 1 缺少 %s:\n%s", want, first.String())
+		}
+	}
+	if strings.Count(last.String(), "<url>") != 1 || !strings.Contains(last.String(), "<loc>https://example.com/posts/2</loc>") {
+		t.Errorf("分片 3:\n%s", last.String())
+	}
+}
+
+func TestMount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func(ps []Provider) { providers = ps }(providers)
+	providers = nil
+	Register(func(_ context.Context, s *Sitemap) error {
+		s.Add(URL{Loc: "/"}, URL{Loc: "/posts"})
+		return nil
+	})
+
+	cfg := &config.Config{}
+	cfg.App.Env = config.EnvProduction
+	cfg.SEO = config.SEOConfig{Disallow: []string{"/admin"}, BlockNonProduction: true, SitemapTTL: 60}
+	r := gin.New()
+	Mount(r, cfg)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+		return w
+	}
+
+	if body := get("/robots.txt").Body.String(); !strings.Contains(body, "Disallow: /admin\n") || !strings.Contains(body, "Sitemap: http://example.com/sitemap.xml") {
+		t.Errorf("robots.txt:\n%s", body)
+	}
+	if body := get("/sitemap.xml").Body.String(); !strings.Contains(body, "<urlset") || !strings.Contains(body, "<loc>http://example.com/posts</loc>") {
+		t.Errorf("sitemap.xml:\n%s", body)
+	}
+	for _, path := range []string{"/sitemap.xml.gz", "/sitemaps/sitemap-1.xml.gz"} {
+		w := get(path)
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		body, _ := io.ReadAll(gz)
+		if w.Header().Get("Content-Type") != "application/gzip" || !strings.Contains(string(body), "<urlset") {
+			t.Errorf("%s: %s\n%s", path, w.Header().Get("Content-Type"), body)
+		}
+	}
+	for _, path := range []string{"/sitemaps/sitemap-2.xml.gz", "/sitemaps/other.xml"} {
+		if code := get(path).Code; code != http.StatusNotFound {
+			t.Errorf("%s: %d", path, code)
+		}
+	}
+
+	cfg.App.Env = config.EnvStaging
+	if body := get("/robots.txt").Body.String(); !strings.Contains(body, "Disallow: /\n") || strings.Contains(body, "/admin") {
+		t.Errorf("预发布环境应禁止抓取全站:\n%s", body)
+	}
+}
@@ -0,0 +1,205 @@
+// Package seo 提供 robots.txt 与 sitemap.xml：控制器或模型通过 Register 向站点地图提供 URL，
+// 超过 MaxURLsPerSitemap 时自动拆分为 gzip 压缩的分片并以 sitemap 索引输出。
+//
+//	func init() {
+//	    seo.Register(func(ctx context.Context, s *seo.Sitemap) error {
+//	        var posts []model.Post
+//	        if err := db.WithContext(ctx).Select("id", "updated_at").Find(&posts).Error; err != nil {
+//	            return err
+//	        }
+//	        for _, p := range posts {
+//	            loc, _ := router.BuildUrl("post.show", map[string]any{"id": p.ID})
+//	            s.Add(seo.URL{Loc: loc, LastMod: p.UpdatedAt, ChangeFreq: seo.Weekly})
+//	        }
+//	        return nil
+//	    })
+//	}
+//
+// 配置 seo.enabled 后由路由注册 /robots.txt、/sitemap.xml、/sitemap.xml.gz 与 /sitemaps/sitemap-N.xml.gz。
+package seo
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxURLsPerSitemap 单个 sitemap 文件的 URL 上限（协议规定为 50,000）
+var MaxURLsPerSitemap = 50000
+
+// xmlns sitemap 协议命名空间
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// ChangeFreq 页面更新频率
+type ChangeFreq string
+
+const (
+	Always  ChangeFreq = "always"
+	Hourly  ChangeFreq = "hourly"
+	Daily   ChangeFreq = "daily"
+	Weekly  ChangeFreq = "weekly"
+	Monthly ChangeFreq = "monthly"
+	Yearly  ChangeFreq = "yearly"
+	Never   ChangeFreq = "never"
+)
+
+// URL 站点地图条目，Loc 为相对路径时输出时拼接站点地址
+type URL struct {
+	Loc        string
+	LastMod    time.Time  // 零值时不输出
+	ChangeFreq ChangeFreq // 为空时不输出
+	Priority   float64    // 0.0-1.0，0 时不输出
+}
+
+// Sitemap 站点地图构建器
+type Sitemap struct {
+	urls []URL
+}
+
+// Add 追加 URL
+func (s *Sitemap) Add(urls ...URL) {
+	s.urls = append(s.urls, urls...)
+}
+
+// Len 返回 URL 数量
+func (s *Sitemap) Len() int {
+	return len(s.urls)
+}
+
+// Chunks 返回按 MaxURLsPerSitemap 拆分后的分片数，至少为 1
+func (s *Sitemap) Chunks() int {
+	return max(1, (len(s.urls)+MaxURLsPerSitemap-1)/MaxURLsPerSitemap)
+}
+
+// chunk 返回第 n 个分片（从 1 开始）的 URL
+func (s *Sitemap) chunk(n int) []URL {
+	start := (n - 1) * MaxURLsPerSitemap
+	end := min(start+MaxURLsPerSitemap, len(s.urls))
+	if start < 0 || start >= end {
+		return nil
+	}
+	return s.urls[start:end]
+}
+
+// lastMod 返回分片中最晚的修改时间
+func lastMod(urls []URL) time.Time {
+	var t time.Time
+	for _, u := range urls {
+		if u.LastMod.After(t) {
+			t = u.LastMod
+		}
+	}
+	return t
+}
+
+type xmlURLSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []xmlURL `xml:"url"`
+}
+
+type xmlURL struct {
+	Loc        string     `xml:"loc"`
+	LastMod    string     `xml:"lastmod,omitempty"`
+	ChangeFreq ChangeFreq `xml:"changefreq,omitempty"`
+	Priority   string     `xml:"priority,omitempty"`
+}
+
+type xmlIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []xmlSitemap `xml:"sitemap"`
+}
+
+type xmlSitemap struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// WriteURLSet 输出第 n 个分片（从 1 开始）的 <urlset>，baseURL 用于补全相对路径
+func (s *Sitemap) WriteURLSet(w io.Writer, baseURL string, n int) error {
+	set := xmlURLSet{Xmlns: xmlns}
+	for _, u := range s.chunk(n) {
+		x := xmlURL{Loc: absURL(baseURL, u.Loc), LastMod: formatTime(u.LastMod), ChangeFreq: u.ChangeFreq}
+		if u.Priority > 0 {
+			x.Priority = fmt.Sprintf("%.1f", min(u.Priority, 1))
+		}
+		set.URLs = append(set.URLs, x)
+	}
+	return writeXML(w, set)
+}
+
+// WriteIndex 输出 <sitemapindex>，第 n 个分片位于 baseURL/sitemaps/sitemap-n.xml.gz
+func (s *Sitemap) WriteIndex(w io.Writer, baseURL string) error {
+	index := xmlIndex{Xmlns: xmlns}
+	for n := 1; n <= s.Chunks(); n++ {
+		index.Sitemaps = append(index.Sitemaps, xmlSitemap{
+			Loc:     absURL(baseURL, ChunkPath(n)),
+			LastMod: formatTime(lastMod(s.chunk(n))),
+		})
+	}
+	return writeXML(w, index)
+}
+
+// ChunkPath 返回第 n 个分片的路径
+func ChunkPath(n int) string {
+	return fmt.Sprintf("/sitemaps/sitemap-%d.xml.gz", n)
+}
+
+// writeXML 输出带 XML 声明的文档
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// absURL 相对路径拼接站点地址，已是绝对地址时原样返回
+func absURL(baseURL, loc string) string {
+	if strings.HasPrefix(loc, "http://") || strings.HasPrefix(loc, "https://") {
+		return loc
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(loc, "/")
+}
+
+// formatTime 以 W3C Datetime 格式输出，零值为空
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Provider 向站点地图添加 URL
+type Provider func(ctx context.Context, s *Sitemap) error
+
+var (
+	mu        sync.RWMutex
+	providers []Provider
+)
+
+// Register 注册 URL 提供者，构建站点地图时按注册顺序调用
+func Register(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers = append(providers, p)
+}
+
+// Build 调用全部提供者构建站点地图，任一提供者出错时返回错误
+func Build(ctx context.Context) (*Sitemap, error) {
+	mu.RLock()
+	ps := append([]Provider(nil), providers...)
+	mu.RUnlock()
+
+	s := &Sitemap{}
+	for _, p := range ps {
+		if err := p(ctx, s); err != nil {
+			return nil, fmt.Errorf("构建站点地图失败: %w", err)
+		}
+	}
+	return s, nil
+}
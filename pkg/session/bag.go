@@ -0,0 +1,91 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bagKeyPrefix 会话中保存集合数据的键名前缀
+const bagKeyPrefix = "_bag:"
+
+// BagOf 保存在会话中的类型化集合（购物车、最近浏览、待提示消息等），元素以 JSON 编码为字符串存入会话，
+// 自定义类型无需 gob.Register。通过 Bag 获取：
+//
+//	cart := session.Bag[CartItem](c, "cart")
+//	cart.Add(CartItem{SKU: "A1", Qty: 2})
+//	cart.Remove(func(it CartItem) bool { return it.SKU == "A1" })
+//	items, n := cart.All(), cart.Count()
+type BagOf[T any] struct {
+	c   *gin.Context
+	key string
+}
+
+// Bag 返回当前会话中名为 name 的集合，需挂载会话中间件
+func Bag[T any](c *gin.Context, name string) *BagOf[T] {
+	return &BagOf[T]{c: c, key: bagKeyPrefix + name}
+}
+
+// All 返回集合中的全部元素；集合不存在或数据无法解码为 T（如结构体已不兼容）时返回空切片
+func (b *BagOf[T]) All() []T {
+	items := []T{}
+	if data, ok := Get(b.c).Get(b.key).(string); ok {
+		if err := json.Unmarshal([]byte(data), &items); err != nil {
+			return []T{}
+		}
+	}
+	return items
+}
+
+// Count 返回集合中的元素数
+func (b *BagOf[T]) Count() int {
+	return len(b.All())
+}
+
+// Add 追加元素并保存会话
+func (b *BagOf[T]) Add(items ...T) error {
+	return b.save(append(b.All(), items...))
+}
+
+// Remove 删除 match 返回 true 的元素并保存会话，返回删除的元素数
+func (b *BagOf[T]) Remove(match func(T) bool) (int, error) {
+	all := b.All()
+	kept := all[:0]
+	for _, item := range all {
+		if !match(item) {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(all) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, b.save(kept)
+}
+
+// Clear 清空集合并保存会话
+func (b *BagOf[T]) Clear() error {
+	return Delete(b.c, b.key)
+}
+
+// Pull 返回全部元素并清空集合，用于只展示一次的数据（闪存式读取）
+func (b *BagOf[T]) Pull() ([]T, error) {
+	items := b.All()
+	if Get(b.c).Get(b.key) == nil {
+		return items, nil
+	}
+	return items, b.Clear()
+}
+
+// save 以 JSON 编码保存全部元素，集合为空时删除会话键
+func (b *BagOf[T]) save(items []T) error {
+	if len(items) == 0 {
+		return b.Clear()
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("编码会话集合 %s 失败: %w", b.key[len(bagKeyPrefix):], err)
+	}
+	return Set(b.c, b.key, string(data))
+}
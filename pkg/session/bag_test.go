@@ -0,0 +1,77 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+type cartItem struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+// TestBag 自定义类型的集合经 Cookie 存储跨请求保存，无需 gob 注册
+func TestBag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.SessionConfig{Store: "cookie", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}
+	r := gin.New()
+	r.Use(Start(cfg, nil, nil))
+	r.GET("/add", func(c *gin.Context) {
+		if err := Bag[cartItem](c, "cart").Add(cartItem{SKU: c.Query("sku"), Qty: 1}); err != nil {
+			t.Error(err)
+		}
+	})
+	r.GET("/remove", func(c *gin.Context) {
+		n, err := Bag[cartItem](c, "cart").Remove(func(it cartItem) bool { return it.SKU == c.Query("sku") })
+		if err != nil {
+			t.Error(err)
+		}
+		c.String(http.StatusOK, "%d", n)
+	})
+	r.GET("/all", func(c *gin.Context) {
+		cart := Bag[cartItem](c, "cart")
+		c.String(http.StatusOK, "%d %v", cart.Count(), cart.All())
+	})
+	r.GET("/pull", func(c *gin.Context) {
+		items, err := Bag[cartItem](c, "cart").Pull()
+		if err != nil {
+			t.Error(err)
+		}
+		c.String(http.StatusOK, "%v", items)
+	})
+
+	var cookies []*http.Cookie
+	do := func(path string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for _, ck := range cookies {
+			req.AddCookie(ck)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Result().Cookies(); len(got) > 0 {
+			cookies = got
+		}
+		return w.Body.String()
+	}
+
+	do("/add?sku=A1")
+	do("/add?sku=B2")
+	do("/add?sku=A1")
+	if got, want := do("/all"), fmt.Sprint(3, []cartItem{{"A1", 1}, {"B2", 1}, {"A1", 1}}); got != want {
+		t.Fatalf("all = %q, want %q", got, want)
+	}
+	if got := do("/remove?sku=A1"); got != "2" {
+		t.Errorf("removed = %s", got)
+	}
+	if got := do("/pull"); got != "[{B2 1}]" {
+		t.Errorf("pull = %s", got)
+	}
+	if got := do("/all"); got != "0 []" {
+		t.Errorf("Pull 后集合应为空，得到 %s", got)
+	}
+}
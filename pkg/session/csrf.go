@@ -0,0 +1,38 @@
+package session
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyCSRF 会话中保存 CSRF 令牌的键名，也是表单中令牌字段的名称
+const KeyCSRF = "_csrf"
+
+// CSRFToken 返回当前会话的 CSRF 令牌，首次调用时生成并保存；未挂载会话中间件时返回空字符串。
+// 模板中 {{ formOpen . ... }} 会自动输出令牌隐藏字段
+func CSRFToken(c *gin.Context) string {
+	if _, ok := c.Get(sessions.DefaultKey); !ok {
+		return ""
+	}
+	s := Get(c)
+	if token, ok := s.Get(KeyCSRF).(string); ok && token != "" {
+		return token
+	}
+	token := newSessionID()
+	s.Set(KeyCSRF, token)
+	if err := s.Save(); err != nil {
+		return ""
+	}
+	return token
+}
+
+// VerifyCSRF 校验令牌是否与当前会话的 CSRF 令牌一致，会话中没有令牌时校验失败
+func VerifyCSRF(c *gin.Context, token string) bool {
+	if _, ok := c.Get(sessions.DefaultKey); !ok || token == "" {
+		return false
+	}
+	expected, _ := Get(c).Get(KeyCSRF).(string)
+	return expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-contrib/sessions"
+	gsessions "github.com/gorilla/sessions"
+)
+
+// degraded 会话降级开关，开启时 FallbackStore 使用后备存储
+var degraded atomic.Bool
+
+// SetDegraded 开启或关闭会话降级，通常由 resilience.SessionFallback 在存储后端（如 Redis）不可用时调用
+func SetDegraded(on bool) {
+	degraded.Store(on)
+}
+
+// Degraded 会话是否处于降级状态
+func Degraded() bool {
+	return degraded.Load()
+}
+
+// FallbackStore 可降级的会话存储：正常时使用主存储，降级时（见 SetDegraded）新请求改用后备存储（通常为 Cookie 存储）。
+// 两个存储的会话互不相通，切换时用户的会话会重新开始，但登录、闪存等功能在后端故障期间仍可使用
+type FallbackStore struct {
+	primary  sessions.Store
+	fallback sessions.Store
+}
+
+// NewFallbackStore 创建可降级的会话存储
+func NewFallbackStore(primary, fallback sessions.Store) *FallbackStore {
+	return &FallbackStore{primary: primary, fallback: fallback}
+}
+
+// current 当前使用的存储
+func (s *FallbackStore) current() sessions.Store {
+	if Degraded() {
+		return s.fallback
+	}
+	return s.primary
+}
+
+// Get 实现 sessions.Store
+func (s *FallbackStore) Get(r *http.Request, name string) (*gsessions.Session, error) {
+	return s.current().Get(r, name)
+}
+
+// New 实现 sessions.Store
+func (s *FallbackStore) New(r *http.Request, name string) (*gsessions.Session, error) {
+	return s.current().New(r, name)
+}
+
+// Save 实现 sessions.Store，保存到创建该会话的存储
+func (s *FallbackStore) Save(r *http.Request, w http.ResponseWriter, session *gsessions.Session) error {
+	if store := session.Store(); store != nil && store != gsessions.Store(s) {
+		return store.Save(r, w, session)
+	}
+	return s.current().Save(r, w, session)
+}
+
+// Options 实现 sessions.Store，同时设置两个存储
+func (s *FallbackStore) Options(options sessions.Options) {
+	s.primary.Options(options)
+	s.fallback.Options(options)
+}
@@ -0,0 +1,190 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+)
+
+// KeySessionID 会话中保存登录会话 ID 的键名（Login 写入，与存储自身的会话 ID 无关，cookie 存储同样适用）
+const KeySessionID = "_sid"
+
+// Info 一个登录会话
+type Info struct {
+	ID        string    `json:"id"`
+	UserID    uint      `json:"user_id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Index 按用户 ID 索引的登录会话表。
+// 会话被撤销后，Track 中间件在该会话下一次请求时清空其数据，使之回到未登录状态
+type Index interface {
+	// Add 登记会话，ExpiresAt 为零时按索引的默认有效期计算
+	Add(ctx context.Context, info Info) error
+	// Exists 会话是否仍有效（未撤销且未过期）
+	Exists(ctx context.Context, userID uint, id string) (bool, error)
+	// List 列出用户的有效会话，按创建时间排序
+	List(ctx context.Context, userID uint) ([]Info, error)
+	// Revoke 撤销用户的指定会话，会话不存在（或已撤销）时返回 false
+	Revoke(ctx context.Context, userID uint, id string) (bool, error)
+	// RevokeAll 撤销用户的全部会话（在所有设备上强制下线），返回撤销数量
+	RevokeAll(ctx context.Context, userID uint) (int, error)
+}
+
+// indexHolder 包装 Index，使 atomic.Value 始终存储同一具体类型
+type indexHolder struct{ Index }
+
+var currentIndex atomic.Value
+
+// SetIndex 设置全局会话索引（由 bootstrap 在启用 session.index 时调用），传入 nil 关闭索引
+func SetIndex(idx Index) {
+	currentIndex.Store(indexHolder{idx})
+}
+
+// DefaultIndex 返回全局会话索引，未启用时返回 nil
+func DefaultIndex() Index {
+	h, _ := currentIndex.Load().(indexHolder)
+	return h.Index
+}
+
+// NewIndex 按会话存储选择索引后端：redis、gorm 存储下与会话共用同一后端（多实例共享），其余存储使用进程内索引
+func NewIndex(opts StoreOptions) (Index, error) {
+	ttl := time.Duration(opts.Session.MaxAge) * time.Minute
+	switch opts.Session.Store {
+	case "redis":
+		if opts.Redis == nil {
+			return nil, fmt.Errorf("会话索引初始化失败: Redis 配置为空")
+		}
+		return NewRedisIndex(newRedisPool(opts.Redis), ttl), nil
+	case "gorm":
+		if opts.Database == nil {
+			return nil, fmt.Errorf("会话索引初始化失败: 数据库配置为空")
+		}
+		db, err := database.Init(opts.Database)
+		if err != nil {
+			return nil, fmt.Errorf("会话索引初始化失败: %w", err)
+		}
+		return NewGormIndex(db, ttl), nil
+	default:
+		return NewMemoryIndex(ttl), nil
+	}
+}
+
+// Login 将当前会话登记为 userID 的登录会话：写入用户 ID 与会话 ID，并在启用索引时登记到索引。
+// 登录前已有的会话 ID 会先被撤销，避免会话固定
+func Login(c *gin.Context, userID uint) error {
+	s := Get(c)
+	idx := DefaultIndex()
+	if old, ok := s.Get(KeySessionID).(string); ok && idx != nil {
+		if prev, ok := s.Get(auth.SessionKeyUserID).(uint); ok {
+			_, _ = idx.Revoke(c.Request.Context(), prev, old)
+		}
+	}
+
+	id := newSessionID()
+	s.Set(auth.SessionKeyUserID, userID)
+	s.Set(KeySessionID, id)
+	if err := s.Save(); err != nil {
+		return fmt.Errorf("保存会话失败: %w", err)
+	}
+	if idx == nil {
+		return nil
+	}
+	return idx.Add(c.Request.Context(), Info{
+		ID:        id,
+		UserID:    userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		CreatedAt: clock.Now(),
+	})
+}
+
+// Logout 撤销当前登录会话并清空会话数据
+func Logout(c *gin.Context) error {
+	s := Get(c)
+	if idx := DefaultIndex(); idx != nil {
+		userID, _ := s.Get(auth.SessionKeyUserID).(uint)
+		if id, ok := s.Get(KeySessionID).(string); ok && userID > 0 {
+			if _, err := idx.Revoke(c.Request.Context(), userID, id); err != nil {
+				return err
+			}
+		}
+	}
+	return Clear(c)
+}
+
+// ID 返回当前登录会话 ID，未通过 Login 登录时返回空字符串
+func ID(c *gin.Context) string {
+	id, _ := Get(c).Get(KeySessionID).(string)
+	return id
+}
+
+// Track 会话撤销检查中间件（需位于会话中间件之后）：当前会话已被撤销或过期时清空会话数据。
+// 未启用索引或会话不是通过 Login 登记的不做检查；索引不可用时放行并记录日志
+func Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idx := DefaultIndex()
+		if idx == nil {
+			c.Next()
+			return
+		}
+		if _, ok := c.Get(sessions.DefaultKey); !ok {
+			c.Next()
+			return
+		}
+
+		s := Get(c)
+		userID, _ := s.Get(auth.SessionKeyUserID).(uint)
+		id, _ := s.Get(KeySessionID).(string)
+		if userID > 0 && id != "" {
+			valid, err := idx.Exists(c.Request.Context(), userID, id)
+			switch {
+			case err != nil:
+				if logger.SugarLogger != nil {
+					logger.SugarLogger.Warnw("检查会话索引失败", "user_id", userID, "error", err)
+				}
+			case !valid:
+				s.Clear()
+				_ = s.Save()
+			}
+		}
+		c.Next()
+	}
+}
+
+// newSessionID 生成 32 位十六进制随机会话 ID
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// expiresAt 计算会话过期时间，ttl 为 0 时永不过期（返回零值）
+func expiresAt(info Info, ttl time.Duration) time.Time {
+	if !info.ExpiresAt.IsZero() || ttl <= 0 {
+		return info.ExpiresAt
+	}
+	created := info.CreatedAt
+	if created.IsZero() {
+		created = clock.Now()
+	}
+	return created.Add(ttl)
+}
+
+// expired 判断会话是否已过期
+func expired(info Info) bool {
+	return !info.ExpiresAt.IsZero() && clock.Now().After(info.ExpiresAt)
+}
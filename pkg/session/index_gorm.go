@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"gorm.io/gorm"
+)
+
+// SessionRecord 会话索引记录
+type SessionRecord struct {
+	ID        string     `gorm:"primaryKey;size:64"`
+	UserID    uint       `gorm:"index;not null"`
+	IP        string     `gorm:"size:64"`
+	UserAgent string     `gorm:"size:512"`
+	CreatedAt time.Time  `gorm:"autoCreateTime:false"`
+	ExpiresAt *time.Time `gorm:"index"` // NULL 表示不过期
+}
+
+// TableName 表名
+func (SessionRecord) TableName() string {
+	return "session_index"
+}
+
+// GormIndex 基于数据库的会话索引，与 gorm 会话存储共用数据库
+type GormIndex struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewGormIndex 创建数据库会话索引，使用前需调用 Migrate 建表
+func NewGormIndex(db *gorm.DB, ttl time.Duration) *GormIndex {
+	return &GormIndex{db: db, ttl: ttl}
+}
+
+// Migrate 创建或更新会话索引表
+func (g *GormIndex) Migrate() error {
+	return g.db.AutoMigrate(&SessionRecord{})
+}
+
+// Add 实现 Index
+func (g *GormIndex) Add(ctx context.Context, info Info) error {
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = clock.Now()
+	}
+	rec := SessionRecord{
+		ID:        info.ID,
+		UserID:    info.UserID,
+		IP:        info.IP,
+		UserAgent: info.UserAgent,
+		CreatedAt: info.CreatedAt,
+	}
+	if exp := expiresAt(info, g.ttl); !exp.IsZero() {
+		rec.ExpiresAt = &exp
+	}
+	return g.db.WithContext(ctx).Create(&rec).Error
+}
+
+// Exists 实现 Index
+func (g *GormIndex) Exists(ctx context.Context, userID uint, id string) (bool, error) {
+	var n int64
+	err := g.valid(ctx, userID).Where("id = ?", id).Count(&n).Error
+	return n > 0, err
+}
+
+// List 实现 Index，顺带清理该用户已过期的会话
+func (g *GormIndex) List(ctx context.Context, userID uint) ([]Info, error) {
+	g.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at IS NOT NULL AND expires_at < ?", userID, clock.Now()).
+		Delete(&SessionRecord{})
+
+	var recs []SessionRecord
+	if err := g.valid(ctx, userID).Order("created_at, id").Find(&recs).Error; err != nil {
+		return nil, err
+	}
+	list := make([]Info, len(recs))
+	for i, r := range recs {
+		list[i] = Info{ID: r.ID, UserID: r.UserID, IP: r.IP, UserAgent: r.UserAgent, CreatedAt: r.CreatedAt}
+		if r.ExpiresAt != nil {
+			list[i].ExpiresAt = *r.ExpiresAt
+		}
+	}
+	return list, nil
+}
+
+// Revoke 实现 Index
+func (g *GormIndex) Revoke(ctx context.Context, userID uint, id string) (bool, error) {
+	res := g.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&SessionRecord{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// RevokeAll 实现 Index
+func (g *GormIndex) RevokeAll(ctx context.Context, userID uint) (int, error) {
+	res := g.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&SessionRecord{})
+	return int(res.RowsAffected), res.Error
+}
+
+// valid 用户未过期会话的查询
+func (g *GormIndex) valid(ctx context.Context, userID uint) *gorm.DB {
+	return g.db.WithContext(ctx).Model(&SessionRecord{}).
+		Where("user_id = ? AND (expires_at IS NULL OR expires_at >= ?)", userID, clock.Now())
+}
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+)
+
+// MemoryIndex 进程内会话索引，适用于单实例部署与测试
+type MemoryIndex struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	users map[uint]map[string]Info
+}
+
+// NewMemoryIndex 创建进程内会话索引，ttl 为会话默认有效期（0 表示不过期）
+func NewMemoryIndex(ttl time.Duration) *MemoryIndex {
+	return &MemoryIndex{ttl: ttl, users: make(map[uint]map[string]Info)}
+}
+
+// Add 实现 Index
+func (m *MemoryIndex) Add(_ context.Context, info Info) error {
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = clock.Now()
+	}
+	info.ExpiresAt = expiresAt(info, m.ttl)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.users[info.UserID] == nil {
+		m.users[info.UserID] = make(map[string]Info)
+	}
+	m.users[info.UserID][info.ID] = info
+	return nil
+}
+
+// Exists 实现 Index
+func (m *MemoryIndex) Exists(_ context.Context, userID uint, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	info, ok := m.users[userID][id]
+	return ok && !expired(info), nil
+}
+
+// List 实现 Index，顺带清理已过期的会话
+func (m *MemoryIndex) List(_ context.Context, userID uint) ([]Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Info, 0, len(m.users[userID]))
+	for id, info := range m.users[userID] {
+		if expired(info) {
+			delete(m.users[userID], id)
+			continue
+		}
+		list = append(list, info)
+	}
+	sortInfos(list)
+	return list, nil
+}
+
+// Revoke 实现 Index
+func (m *MemoryIndex) Revoke(_ context.Context, userID uint, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[userID][id]; !ok {
+		return false, nil
+	}
+	delete(m.users[userID], id)
+	return true, nil
+}
+
+// RevokeAll 实现 Index
+func (m *MemoryIndex) RevokeAll(_ context.Context, userID uint) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := len(m.users[userID])
+	delete(m.users, userID)
+	return n, nil
+}
+
+// sortInfos 按创建时间排序，同一时刻按 ID 排序
+func sortInfos(list []Info) {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// redisIndexPrefix 会话索引键前缀，每个用户一个 Hash：字段为会话 ID，值为 Info 的 JSON
+const redisIndexPrefix = "session_index:"
+
+// RedisIndex 基于 Redis 的会话索引，与 redis 会话存储共用 Redis
+type RedisIndex struct {
+	pool *redis.Pool
+	ttl  time.Duration
+}
+
+// NewRedisIndex 创建 Redis 会话索引
+func NewRedisIndex(pool *redis.Pool, ttl time.Duration) *RedisIndex {
+	return &RedisIndex{pool: pool, ttl: ttl}
+}
+
+// newRedisPool 按全局 Redis 配置创建连接池
+func newRedisPool(cfg *config.RedisConfig) *redis.Pool {
+	addr := cfg.Host + ":" + strconv.Itoa(cfg.Port)
+	return &redis.Pool{
+		MaxIdle:     5,
+		IdleTimeout: 240 * time.Second,
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", addr, redis.DialPassword(cfg.Password), redis.DialDatabase(cfg.DB))
+		},
+	}
+}
+
+func redisIndexKey(userID uint) string {
+	return redisIndexPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Add 实现 Index，用户键的过期时间随最新登记的会话顺延
+func (r *RedisIndex) Add(ctx context.Context, info Info) error {
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = clock.Now()
+	}
+	info.ExpiresAt = expiresAt(info, r.ttl)
+	b, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	key := redisIndexKey(info.UserID)
+	if _, err := conn.Do("HSET", key, info.ID, b); err != nil {
+		return err
+	}
+	if r.ttl > 0 {
+		_, err = conn.Do("EXPIRE", key, int(r.ttl.Seconds()))
+	}
+	return err
+}
+
+// Exists 实现 Index
+func (r *RedisIndex) Exists(ctx context.Context, userID uint, id string) (bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	b, err := redis.Bytes(conn.Do("HGET", redisIndexKey(userID), id))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var info Info
+	if err := json.Unmarshal(b, &info); err != nil {
+		return false, err
+	}
+	return !expired(info), nil
+}
+
+// List 实现 Index，顺带清理已过期的会话
+func (r *RedisIndex) List(ctx context.Context, userID uint) ([]Info, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	key := redisIndexKey(userID)
+	values, err := redis.StringMap(conn.Do("HGETALL", key))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Info, 0, len(values))
+	for id, v := range values {
+		var info Info
+		if err := json.Unmarshal([]byte(v), &info); err != nil || expired(info) {
+			_, _ = conn.Do("HDEL", key, id)
+			continue
+		}
+		list = append(list, info)
+	}
+	sortInfos(list)
+	return list, nil
+}
+
+// Revoke 实现 Index
+func (r *RedisIndex) Revoke(ctx context.Context, userID uint, id string) (bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	n, err := redis.Int(conn.Do("HDEL", redisIndexKey(userID), id))
+	return n > 0, err
+}
+
+// RevokeAll 实现 Index
+func (r *RedisIndex) RevokeAll(ctx context.Context, userID uint) (int, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	key := redisIndexKey(userID)
+	n, err := redis.Int(conn.Do("HLEN", key))
+	if err != nil {
+		return 0, err
+	}
+	_, err = conn.Do("DEL", key)
+	return n, err
+}
+
+// Close 关闭连接池
+func (r *RedisIndex) Close() error {
+	return r.pool.Close()
+}
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla-go/go-framework/pkg/auth"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// testIndex 各索引实现共用的行为测试
+func testIndex(t *testing.T, idx Index, fake *clock.Fake) {
+	ctx := context.Background()
+	for _, id := range []string{"a", "b"} {
+		if err := idx.Add(ctx, Info{ID: id, UserID: 1, IP: "127.0.0.1"}); err != nil {
+			t.Fatal(err)
+		}
+		fake.Advance(time.Second)
+	}
+	_ = idx.Add(ctx, Info{ID: "c", UserID: 2})
+
+	list, err := idx.List(ctx, 1)
+	if err != nil || len(list) != 2 || list[0].ID != "a" || list[1].ID != "b" {
+		t.Fatalf("List = %v, %v", list, err)
+	}
+	if ok, _ := idx.Exists(ctx, 1, "a"); !ok {
+		t.Error("会话 a 应有效")
+	}
+	if ok, _ := idx.Exists(ctx, 2, "a"); ok {
+		t.Error("会话 a 不属于用户 2")
+	}
+
+	if ok, err := idx.Revoke(ctx, 1, "a"); err != nil || !ok {
+		t.Fatalf("Revoke = %v, %v", ok, err)
+	}
+	if ok, _ := idx.Revoke(ctx, 1, "a"); ok {
+		t.Error("重复撤销应返回 false")
+	}
+	if ok, _ := idx.Revoke(ctx, 2, "b"); ok {
+		t.Error("不应撤销其他用户的会话")
+	}
+	if ok, _ := idx.Exists(ctx, 1, "a"); ok {
+		t.Error("撤销后会话 a 应失效")
+	}
+	if n, err := idx.RevokeAll(ctx, 1); err != nil || n != 1 {
+		t.Errorf("RevokeAll = %d, %v", n, err)
+	}
+	if list, _ := idx.List(ctx, 1); len(list) != 0 {
+		t.Errorf("撤销全部后仍有会话 %v", list)
+	}
+
+	// 超过有效期的会话失效
+	fake.Advance(2 * time.Hour)
+	if ok, _ := idx.Exists(ctx, 2, "c"); ok {
+		t.Error("过期会话应失效")
+	}
+	if list, _ := idx.List(ctx, 2); len(list) != 0 {
+		t.Errorf("过期会话不应列出 %v", list)
+	}
+}
+
+func useFakeClock(t *testing.T) *clock.Fake {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	clock.SetDefault(fake)
+	t.Cleanup(func() { clock.SetDefault(nil) })
+	return fake
+}
+
+func TestMemoryIndex(t *testing.T) {
+	fake := useFakeClock(t)
+	testIndex(t, NewMemoryIndex(time.Hour), fake)
+}
+
+func TestGormIndex(t *testing.T) {
+	fake := useFakeClock(t)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := NewGormIndex(db, time.Hour)
+	if err := idx.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	testIndex(t, idx, fake)
+}
+
+func TestRedisIndex(t *testing.T) {
+	fake := useFakeClock(t)
+	mr := miniredis.RunT(t)
+	idx := NewRedisIndex(&redis.Pool{Dial: func() (redis.Conn, error) { return redis.Dial("tcp", mr.Addr()) }}, time.Hour)
+	defer idx.Close()
+	testIndex(t, idx, fake)
+}
+
+// TestLoginTrackRevoke 登录登记会话，撤销后下一次请求会话被清空
+func TestLoginTrackRevoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idx := NewMemoryIndex(time.Hour)
+	SetIndex(idx)
+	defer SetIndex(nil)
+
+	cfg := &config.SessionConfig{Store: "memory", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}
+	r := gin.New()
+	r.Use(Start(cfg, nil, nil), Track())
+	r.POST("/login", func(c *gin.Context) {
+		if err := Login(c, 7); err != nil {
+			t.Error(err)
+		}
+	})
+	r.GET("/me", func(c *gin.Context) {
+		id, _ := Get(c).Get(auth.SessionKeyUserID).(uint)
+		c.JSON(http.StatusOK, gin.H{"user_id": id, "sid": ID(c)})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	cookie := w.Header().Get("Set-Cookie")
+
+	me := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Cookie", cookie)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	list, _ := idx.List(context.Background(), 7)
+	if len(list) != 1 {
+		t.Fatalf("登录后应登记 1 个会话，得到 %v", list)
+	}
+	if body := me(); body != `{"sid":"`+list[0].ID+`","user_id":7}` {
+		t.Fatalf("登录后 /me = %s", body)
+	}
+
+	_, _ = idx.RevokeAll(context.Background(), 7)
+	if body := me(); body != `{"sid":"","user_id":0}` {
+		t.Errorf("撤销后会话应被清空，得到 %s", body)
+	}
+}
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"encoding/gob"
+	"net/url"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// flashKeyOldInput 保存上一次提交表单内容的闪存键
+const flashKeyOldInput = "_old_input"
+
+// flashKeyErrors 保存上一次提交表单校验错误的闪存键
+const flashKeyErrors = "_errors"
+
+// 本次请求已读取的旧输入、校验错误在 gin.Context 中的缓存键（闪存只能读取一次）
+const (
+	ctxKeyOldInput = "session_old_input"
+	ctxKeyErrors   = "session_errors"
+)
+
+func init() {
+	// cookie/redis 等存储以 gob 编码会话数据，接口值中的自定义类型需要注册
+	gob.Register(url.Values{})
+	gob.Register(map[string]string{})
+}
+
+// FlashInput 将表单内容闪存到会话，供重定向后的页面回填（POST-重定向-GET）
+func FlashInput(c *gin.Context, input url.Values) error {
+	return SetFlash(c, flashKeyOldInput, input)
+}
+
+// OldInput 读取上一次请求闪存的表单内容，同一请求内可多次调用；
+// 未挂载会话中间件或没有旧输入时返回空的 url.Values
+func OldInput(c *gin.Context) url.Values {
+	if v, ok := c.Get(ctxKeyOldInput); ok {
+		return v.(url.Values)
+	}
+	old := url.Values{}
+	// 仅在存在旧输入时读取闪存，避免每次渲染都保存会话（写出 Set-Cookie）
+	if _, ok := c.Get(sessions.DefaultKey); ok && Get(c).Get(flashKeyOldInput) != nil {
+		if v, _ := GetFlash(c, flashKeyOldInput); v != nil {
+			if values, ok := v.(url.Values); ok {
+				old = values
+			}
+		}
+	}
+	c.Set(ctxKeyOldInput, old)
+	return old
+}
+
+// FlashErrors 将字段校验错误（参数名 → 错误描述）闪存到会话，供重定向后的页面在字段旁展示
+func FlashErrors(c *gin.Context, errs map[string]string) error {
+	return SetFlash(c, flashKeyErrors, errs)
+}
+
+// Errors 读取上一次请求闪存的字段校验错误，同一请求内可多次调用；
+// 未挂载会话中间件或没有错误时返回空 map
+func Errors(c *gin.Context) map[string]string {
+	if v, ok := c.Get(ctxKeyErrors); ok {
+		return v.(map[string]string)
+	}
+	errs := map[string]string{}
+	if _, ok := c.Get(sessions.DefaultKey); ok && Get(c).Get(flashKeyErrors) != nil {
+		if v, _ := GetFlash(c, flashKeyErrors); v != nil {
+			if m, ok := v.(map[string]string); ok {
+				errs = m
+			}
+		}
+	}
+	c.Set(ctxKeyErrors, errs)
+	return errs
+}
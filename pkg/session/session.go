@@ -3,70 +3,21 @@ package session
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
-	gormsession "github.com/gin-contrib/sessions/gorm"
-	"github.com/gin-contrib/sessions/memstore"
-	"github.com/gin-contrib/sessions/redis"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla-go/go-framework/pkg/config"
-	"github.com/gorilla-go/go-framework/pkg/database"
 )
 
-// Start 启动会话中间件
+// Start 启动会话中间件，存储按 session.store 从已注册的存储中选取（见 RegisterStore）
 func Start(sessionConfig *config.SessionConfig, redisConfig *config.RedisConfig, dbConfig *config.DatabaseConfig) gin.HandlerFunc {
-	// 创建存储
-	var store sessions.Store
-	var err error
-
-	// 根据配置选择存储类型
-	switch sessionConfig.Store {
-	case "redis":
-		// 使用全局Redis配置
-		redisAddr := redisConfig.Host + ":" + strconv.Itoa(redisConfig.Port)
-
-		// 动态设置连接池大小（默认 10，最小 5，最大 100）
-		poolSize := 10
-		if redisConfig.PoolSize > 0 {
-			poolSize = redisConfig.PoolSize
-			if poolSize < 5 {
-				poolSize = 5
-			} else if poolSize > 100 {
-				poolSize = 100
-			}
-		}
-
-		// redis.NewStore 参数: size, network, address, username, password, keyPairs
-		store, err = redis.NewStore(poolSize, "tcp", redisAddr, "", redisConfig.Password, []byte(sessionConfig.Secret))
-		if err != nil {
-			panic(fmt.Sprintf("Redis 会话存储初始化失败: %v", err))
-		}
-
-	case "gorm":
-		// 使用GORM数据库存储
-		if dbConfig == nil {
-			panic("GORM 会话存储初始化失败: 数据库配置为空")
-		}
-
-		// 初始化数据库连接
-		gormDB, err := database.Init(dbConfig)
-		if err != nil {
-			panic(fmt.Sprintf("GORM 会话存储初始化失败: %v", err))
-		}
-
-		// NewStore 参数: db, expiredSessionCleanup, keyPairs
-		// expiredSessionCleanup: 是否启用过期会话自动清理
-		store = gormsession.NewStore(gormDB, true, []byte(sessionConfig.Secret))
-
-	case "memory":
-		// 使用内存存储
-		store = memstore.NewStore([]byte(sessionConfig.Secret))
-
-	default:
-		// 默认使用Cookie存储
-		store = cookie.NewStore([]byte(sessionConfig.Secret))
+	store, err := NewStore(StoreOptions{Session: sessionConfig, Redis: redisConfig, Database: dbConfig})
+	if err != nil {
+		panic(err.Error())
+	}
+	if sessionConfig.Fallback && sessionConfig.Store != "" && sessionConfig.Store != "cookie" {
+		store = NewFallbackStore(store, cookie.NewStore([]byte(sessionConfig.Secret)))
 	}
 
 	// 解析 SameSite
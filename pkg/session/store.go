@@ -0,0 +1,126 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	gormsession "github.com/gin-contrib/sessions/gorm"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-contrib/sessions/redis"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/database"
+)
+
+// StoreOptions 创建会话存储时可用的配置
+type StoreOptions struct {
+	Session  *config.SessionConfig
+	Redis    *config.RedisConfig
+	Database *config.DatabaseConfig
+}
+
+// StoreFactory 会话存储工厂，按配置创建 sessions.Store
+type StoreFactory func(opts StoreOptions) (sessions.Store, error)
+
+var (
+	storesMu sync.RWMutex
+	stores   = make(map[string]StoreFactory)
+)
+
+// RegisterStore 注册会话存储，之后可在配置中以 session.store: name 选用；同名注册会覆盖（可替换内置存储）。
+// 通常在 init 中调用：
+//
+//	func init() {
+//	    session.RegisterStore("etcd", func(opts session.StoreOptions) (sessions.Store, error) {
+//	        return newEtcdStore(opts.Session.Secret)
+//	    })
+//	}
+func RegisterStore(name string, factory StoreFactory) {
+	if factory == nil {
+		panic("session: 存储工厂不能为 nil: " + name)
+	}
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	stores[name] = factory
+}
+
+// Stores 返回已注册的存储名称（已排序）
+func Stores() []string {
+	storesMu.RLock()
+	defer storesMu.RUnlock()
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewStore 按配置创建会话存储，store 为空时使用 cookie
+func NewStore(opts StoreOptions) (sessions.Store, error) {
+	name := opts.Session.Store
+	if name == "" {
+		name = "cookie"
+	}
+	storesMu.RLock()
+	factory, ok := stores[name]
+	storesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("未注册的会话存储: %q（已注册: %v）", name, Stores())
+	}
+	store, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s 会话存储初始化失败: %w", name, err)
+	}
+	return store, nil
+}
+
+func init() {
+	RegisterStore("cookie", func(opts StoreOptions) (sessions.Store, error) {
+		return cookie.NewStore([]byte(opts.Session.Secret)), nil
+	})
+	RegisterStore("memory", func(opts StoreOptions) (sessions.Store, error) {
+		return memstore.NewStore([]byte(opts.Session.Secret)), nil
+	})
+	RegisterStore("redis", newRedisStore)
+	RegisterStore("gorm", newGormStore)
+}
+
+// newRedisStore 使用全局 Redis 配置创建存储
+func newRedisStore(opts StoreOptions) (sessions.Store, error) {
+	if opts.Redis == nil {
+		return nil, fmt.Errorf("Redis 配置为空")
+	}
+	redisAddr := opts.Redis.Host + ":" + strconv.Itoa(opts.Redis.Port)
+
+	// 动态设置连接池大小（默认 10，最小 5，最大 100）
+	poolSize := 10
+	if opts.Redis.PoolSize > 0 {
+		poolSize = opts.Redis.PoolSize
+		if poolSize < 5 {
+			poolSize = 5
+		} else if poolSize > 100 {
+			poolSize = 100
+		}
+	}
+
+	// redis.NewStore 参数: size, network, address, username, password, keyPairs
+	return redis.NewStore(poolSize, "tcp", redisAddr, "", opts.Redis.Password, []byte(opts.Session.Secret))
+}
+
+// newGormStore 使用全局数据库连接创建存储
+func newGormStore(opts StoreOptions) (sessions.Store, error) {
+	if opts.Database == nil {
+		return nil, fmt.Errorf("数据库配置为空")
+	}
+	gormDB, err := database.Init(opts.Database)
+	if err != nil {
+		return nil, err
+	}
+	// NewStore 参数: db, expiredSessionCleanup, keyPairs
+	// expiredSessionCleanup: 是否启用过期会话自动清理
+	return gormsession.NewStore(gormDB, true, []byte(opts.Session.Secret)), nil
+}
@@ -0,0 +1,23 @@
+//go:build memcached
+
+package session
+
+import (
+	"fmt"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memcached"
+)
+
+// Memcached 存储依赖 gomemcache，按需以 `go build -tags memcached` 启用，避免默认构建引入额外依赖
+func init() {
+	RegisterStore("memcached", func(opts StoreOptions) (sessions.Store, error) {
+		cfg := opts.Session.Memcached
+		if len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("未配置 session.memcached.addrs")
+		}
+		client := memcache.New(cfg.Addrs...)
+		return memcached.NewStore(client, cfg.Prefix, []byte(opts.Session.Secret)), nil
+	})
+}
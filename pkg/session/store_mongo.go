@@ -0,0 +1,32 @@
+//go:build mongo
+
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/mongo/mongodriver"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB 存储依赖 mongo-driver，按需以 `go build -tags mongo` 启用，避免默认构建引入额外依赖
+func init() {
+	RegisterStore("mongo", func(opts StoreOptions) (sessions.Store, error) {
+		cfg := opts.Session.Mongo
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI))
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Ping(ctx, nil); err != nil {
+			return nil, err
+		}
+		collection := client.Database(cfg.Database).Collection(cfg.Collection)
+		// maxAge 单位为秒，ensureTTL 为集合创建 TTL 索引以自动清理过期会话
+		return mongodriver.NewStore(collection, opts.Session.MaxAge*60, true, []byte(opts.Session.Secret)), nil
+	})
+}
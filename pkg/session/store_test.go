@@ -0,0 +1,105 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// TestRegisterStore 自定义存储可通过配置选用
+func TestRegisterStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	used := false
+	RegisterStore("custom", func(opts StoreOptions) (sessions.Store, error) {
+		used = true
+		return memstore.NewStore([]byte(opts.Session.Secret)), nil
+	})
+	if !slices.Contains(Stores(), "custom") || !slices.Contains(Stores(), "redis") {
+		t.Fatalf("已注册存储 %v", Stores())
+	}
+
+	cfg := &config.SessionConfig{Store: "custom", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/"}
+	r := gin.New()
+	r.Use(Start(cfg, nil, nil))
+	r.GET("/", func(c *gin.Context) {
+		if err := Set(c, "k", "v"); err != nil {
+			t.Error(err)
+		}
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !used || w.Header().Get("Set-Cookie") == "" {
+		t.Errorf("自定义存储未生效: used=%v cookie=%q", used, w.Header().Get("Set-Cookie"))
+	}
+}
+
+// TestNewStoreUnknown 未注册的存储返回错误
+func TestNewStoreUnknown(t *testing.T) {
+	if _, err := NewStore(StoreOptions{Session: &config.SessionConfig{Store: "nope"}}); err == nil {
+		t.Error("期望未注册存储报错")
+	}
+	if _, err := NewStore(StoreOptions{Session: &config.SessionConfig{}}); err != nil {
+		t.Errorf("空存储名应使用 cookie: %v", err)
+	}
+	if _, err := NewStore(StoreOptions{Session: &config.SessionConfig{Store: "redis"}}); err == nil {
+		t.Error("缺少 Redis 配置应报错")
+	}
+}
+
+// TestFallbackStore 降级时改用 Cookie 存储，恢复后回到主存储
+func TestFallbackStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer SetDegraded(false)
+
+	cfg := &config.SessionConfig{Store: "memory", Name: "sid", Secret: "secret", MaxAge: 60, Path: "/", Fallback: true}
+	r := gin.New()
+	r.Use(Start(cfg, nil, nil))
+	r.GET("/set", func(c *gin.Context) {
+		if err := Set(c, "k", c.Query("v")); err != nil {
+			t.Error(err)
+		}
+	})
+	r.GET("/get", func(c *gin.Context) {
+		v, _ := GetValue(c, "k").(string)
+		c.String(http.StatusOK, v)
+	})
+
+	// do 携带 cookie 发起请求，返回响应体与新的 cookie
+	do := func(path string, cookie *http.Cookie) (string, *http.Cookie) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if cookies := w.Result().Cookies(); len(cookies) > 0 {
+			cookie = cookies[0]
+		}
+		return w.Body.String(), cookie
+	}
+
+	_, primary := do("/set?v=primary", nil)
+	if body, _ := do("/get", primary); body != "primary" {
+		t.Fatalf("主存储读取 = %q", body)
+	}
+
+	SetDegraded(true)
+	if body, _ := do("/get", primary); body != "" {
+		t.Errorf("降级后不应读到主存储的会话: %q", body)
+	}
+	_, fallback := do("/set?v=cookie", primary)
+	if body, _ := do("/get", fallback); body != "cookie" {
+		t.Errorf("降级后 Cookie 存储读取 = %q", body)
+	}
+
+	SetDegraded(false)
+	if body, _ := do("/get", primary); body != "primary" {
+		t.Errorf("恢复后主存储读取 = %q", body)
+	}
+}
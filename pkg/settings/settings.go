@@ -0,0 +1,262 @@
+// Package settings 提供基于数据库表的运行时配置（键值存储），修改后无需重新部署即可生效。
+//
+// 值以字符串保存，读取经由缓存，写入/删除时同步失效缓存并触发 settings.changed 事件：
+//
+//	s := settings.New(db, eventbus.Default())
+//	_ = s.Set(ctx, "site.title", "Go Framework")
+//	title := s.GetString(ctx, "site.title", "默认标题")
+//
+// 模板中：{{ setting "site.title" "默认标题" }}
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EventChanged 配置项变更事件，参数为 *Change
+const EventChanged = "settings.changed"
+
+// cacheKeyPrefix 缓存键前缀
+const cacheKeyPrefix = "settings:"
+
+// Setting 配置项记录
+type Setting struct {
+	Key       string    `gorm:"primaryKey;size:191" json:"key"`
+	Value     string    `gorm:"type:text" json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (Setting) TableName() string {
+	return "settings"
+}
+
+// Change 配置项变更事件载荷
+type Change struct {
+	Key     string
+	Old     string // 变更前的值，新增时为空
+	New     string // 变更后的值，删除时为空
+	Deleted bool
+}
+
+// Store 配置存储
+type Store struct {
+	db    *gorm.DB
+	bus   *eventbus.EventBus
+	cache cache.Store
+	ttl   time.Duration
+}
+
+// Option 配置存储选项
+type Option func(*Store)
+
+// WithCache 指定缓存后端（默认 cache.Default()），多实例部署时应使用共享缓存
+func WithCache(store cache.Store) Option {
+	return func(s *Store) { s.cache = store }
+}
+
+// WithCacheTTL 缓存有效期（默认 10 分钟），0 表示不过期（依赖写入时的主动失效）
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Store) { s.ttl = ttl }
+}
+
+// New 创建配置存储，bus 为 nil 时使用全局事件总线
+func New(db *gorm.DB, bus *eventbus.EventBus, opts ...Option) *Store {
+	if bus == nil {
+		bus = eventbus.Default()
+	}
+	s := &Store{db: db, bus: bus, cache: cache.Default(), ttl: 10 * time.Minute}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Migrate 创建或更新配置表
+func (s *Store) Migrate() error {
+	return s.db.AutoMigrate(&Setting{})
+}
+
+// Get 读取配置项原始值，不存在时 ok 为 false
+func (s *Store) Get(ctx context.Context, key string) (string, bool, error) {
+	if raw, ok, _ := s.cache.Get(ctx, cacheKeyPrefix+key); ok {
+		return string(raw), true, nil
+	}
+
+	value, ok, err := s.load(ctx, key)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	_ = s.cache.Set(ctx, cacheKeyPrefix+key, []byte(value), s.ttl)
+	return value, true, nil
+}
+
+// load 从数据库读取配置项，不经过缓存
+func (s *Store) load(ctx context.Context, key string) (string, bool, error) {
+	var setting Setting
+	err := s.db.WithContext(ctx).Where(keyEq(key)).Take(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("读取配置项 %s 失败: %w", key, err)
+	}
+	return setting.Value, true, nil
+}
+
+// Set 写入配置项，字符串原样保存，其他类型以 JSON 保存（可用 GetJSON 读取）
+func (s *Store) Set(ctx context.Context, key string, value any) error {
+	str, err := encode(value)
+	if err != nil {
+		return fmt.Errorf("编码配置项 %s 失败: %w", key, err)
+	}
+	// 旧值以数据库为准，缓存可能已过时
+	old, _, err := s.load(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&Setting{Key: key, Value: str}).Error
+	if err != nil {
+		return fmt.Errorf("保存配置项 %s 失败: %w", key, err)
+	}
+	_ = s.cache.Delete(ctx, cacheKeyPrefix+key)
+
+	if old != str {
+		_ = s.bus.EmitCtx(ctx, EventChanged, &Change{Key: key, Old: old, New: str})
+	}
+	return nil
+}
+
+// Delete 删除配置项
+func (s *Store) Delete(ctx context.Context, key string) error {
+	old, ok, err := s.load(ctx, key)
+	if err != nil || !ok {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Where(keyEq(key)).Delete(&Setting{}).Error; err != nil {
+		return fmt.Errorf("删除配置项 %s 失败: %w", key, err)
+	}
+	_ = s.cache.Delete(ctx, cacheKeyPrefix+key)
+	_ = s.bus.EmitCtx(ctx, EventChanged, &Change{Key: key, Old: old, Deleted: true})
+	return nil
+}
+
+// All 读取全部配置项（不经过缓存），供后台管理页面使用
+func (s *Store) All(ctx context.Context) (map[string]string, error) {
+	var list []Setting
+	if err := s.db.WithContext(ctx).Order(clause.OrderByColumn{Column: clause.Column{Name: "key"}}).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("读取配置项失败: %w", err)
+	}
+	all := make(map[string]string, len(list))
+	for _, item := range list {
+		all[item.Key] = item.Value
+	}
+	return all, nil
+}
+
+// GetString 读取字符串配置，不存在或读取失败时返回 def
+func (s *Store) GetString(ctx context.Context, key, def string) string {
+	if v, ok, err := s.Get(ctx, key); err == nil && ok {
+		return v
+	}
+	return def
+}
+
+// GetInt 读取整数配置，不存在或无法解析时返回 def
+func (s *Store) GetInt(ctx context.Context, key string, def int) int {
+	if v, ok, err := s.Get(ctx, key); err == nil && ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// GetBool 读取布尔配置（支持 1/0、true/false 等 strconv.ParseBool 格式），不存在或无法解析时返回 def
+func (s *Store) GetBool(ctx context.Context, key string, def bool) bool {
+	if v, ok, err := s.Get(ctx, key); err == nil && ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// GetJSON 将 JSON 配置解码到 v，不存在时返回 false 且不修改 v
+func (s *Store) GetJSON(ctx context.Context, key string, v any) (bool, error) {
+	raw, ok, err := s.Get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(raw), v); err != nil {
+		return false, fmt.Errorf("解析配置项 %s 失败: %w", key, err)
+	}
+	return true, nil
+}
+
+// keyEq 按键查询的条件（key 在 MySQL 中为保留字，交由方言加引号）
+func keyEq(key string) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: "key"}, Value: key}
+}
+
+// encode 将配置值编码为字符串
+func encode(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	b, err := json.Marshal(value)
+	return string(b), err
+}
+
+var (
+	defaultStore *Store
+	defaultMu    sync.RWMutex
+)
+
+// Default 获取全局配置存储，未初始化时返回 nil
+func Default() *Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultStore
+}
+
+// SetDefault 设置全局配置存储，供模板函数 {{ setting }} 使用
+func SetDefault(s *Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultStore = s
+}
+
+// Value 读取全局配置存储中的字符串配置，未初始化、不存在时返回 def（未传入时为空字符串），
+// 即模板函数 {{ setting "site.title" "默认标题" }} 的实现
+func Value(key string, def ...string) string {
+	fallback := ""
+	if len(def) > 0 {
+		fallback = def[0]
+	}
+	s := Default()
+	if s == nil {
+		return fallback
+	}
+	return s.GetString(context.Background(), key, fallback)
+}
@@ -0,0 +1,120 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestStore(t *testing.T, bus *eventbus.EventBus) (*Store, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	s := New(db, bus, WithCache(cache.NewMemory()))
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	return s, db
+}
+
+func TestTypedGetters(t *testing.T) {
+	s, _ := newTestStore(t, eventbus.New())
+	ctx := context.Background()
+
+	_ = s.Set(ctx, "site.title", "Go Framework")
+	_ = s.Set(ctx, "posts.per_page", 20)
+	_ = s.Set(ctx, "site.open", true)
+	_ = s.Set(ctx, "site.links", map[string]string{"github": "https://github.com"})
+
+	if got := s.GetString(ctx, "site.title", ""); got != "Go Framework" {
+		t.Errorf("GetString = %q", got)
+	}
+	if got := s.GetInt(ctx, "posts.per_page", 10); got != 20 {
+		t.Errorf("GetInt = %d", got)
+	}
+	if got := s.GetInt(ctx, "site.title", 10); got != 10 {
+		t.Errorf("GetInt on non-number = %d, want default", got)
+	}
+	if !s.GetBool(ctx, "site.open", false) {
+		t.Error("GetBool = false")
+	}
+	if got := s.GetString(ctx, "missing", "def"); got != "def" {
+		t.Errorf("GetString missing = %q", got)
+	}
+	var links map[string]string
+	if ok, err := s.GetJSON(ctx, "site.links", &links); !ok || err != nil || links["github"] == "" {
+		t.Errorf("GetJSON = %v, %v, %v", links, ok, err)
+	}
+
+	all, err := s.All(ctx)
+	if err != nil || len(all) != 4 {
+		t.Errorf("All = %v, %v", all, err)
+	}
+}
+
+func TestCacheInvalidationAndEvents(t *testing.T) {
+	bus := eventbus.New()
+	s, db := newTestStore(t, bus)
+	ctx := context.Background()
+
+	var changes []*Change
+	bus.On(EventChanged, func(args ...interface{}) {
+		changes = append(changes, args[0].(*Change))
+	})
+
+	_ = s.Set(ctx, "site.title", "A")
+	_ = s.GetString(ctx, "site.title", "") // 写入缓存
+
+	// 绕过 Store 直接改表时，缓存仍返回旧值
+	db.Model(&Setting{}).Where(keyEq("site.title")).Update("value", "B")
+	if got := s.GetString(ctx, "site.title", ""); got != "A" {
+		t.Errorf("expected cached value, got %q", got)
+	}
+
+	// 通过 Store 写入会失效缓存
+	_ = s.Set(ctx, "site.title", "C")
+	if got := s.GetString(ctx, "site.title", ""); got != "C" {
+		t.Errorf("after Set got %q", got)
+	}
+	_ = s.Set(ctx, "site.title", "C") // 值未变化不触发事件
+	_ = s.Delete(ctx, "site.title")
+	if _, ok, _ := s.Get(ctx, "site.title"); ok {
+		t.Error("value still present after Delete")
+	}
+
+	if len(changes) != 3 {
+		t.Fatalf("got %d change events, want 3: %+v", len(changes), changes)
+	}
+	if c := changes[1]; c.Old != "B" || c.New != "C" {
+		t.Errorf("update change = %+v", c)
+	}
+	if c := changes[2]; !c.Deleted || c.Old != "C" {
+		t.Errorf("delete change = %+v", c)
+	}
+}
+
+func TestValue(t *testing.T) {
+	SetDefault(nil)
+	if got := Value("site.title", "默认"); got != "默认" {
+		t.Errorf("Value without store = %q", got)
+	}
+
+	s, _ := newTestStore(t, eventbus.New())
+	SetDefault(s)
+	defer SetDefault(nil)
+	_ = s.Set(context.Background(), "site.title", "Go")
+	if got := Value("site.title"); got != "Go" {
+		t.Errorf("Value = %q", got)
+	}
+}
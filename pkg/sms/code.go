@@ -0,0 +1,256 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCooldown 发送冷却中，具体等待时间见 *CooldownError
+	ErrCooldown = errors.New("验证码发送过于频繁")
+	// ErrDailyLimit 手机号当日的验证码发送次数已达上限
+	ErrDailyLimit = errors.New("今日验证码发送次数已达上限")
+	// ErrCodeInvalid 验证码错误
+	ErrCodeInvalid = errors.New("验证码错误")
+	// ErrCodeExpired 验证码不存在或已过期
+	ErrCodeExpired = errors.New("验证码已过期，请重新获取")
+	// ErrTooManyAttempts 错误次数过多，验证码已失效
+	ErrTooManyAttempts = errors.New("验证码错误次数过多，请重新获取")
+)
+
+// CooldownError 发送冷却中，Wait 后可再次发送
+type CooldownError struct {
+	Wait time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("%s，请 %d 秒后重试", ErrCooldown, int((e.Wait+time.Second-1)/time.Second))
+}
+
+// Is 使 errors.Is(err, ErrCooldown) 成立
+func (e *CooldownError) Is(target error) bool {
+	return target == ErrCooldown
+}
+
+// CodeEvent 验证码事件载荷
+type CodeEvent struct {
+	Phone   string
+	Purpose string // 用途，如 login、register
+	Err     error  // EventCodeRejected 的原因
+}
+
+// codeConfig 验证码规则
+type codeConfig struct {
+	template    string
+	length      int
+	ttl         time.Duration
+	cooldown    time.Duration
+	maxAttempts int
+	dailyLimit  int
+}
+
+func defaultCodeConfig() codeConfig {
+	return codeConfig{
+		template:    "verify",
+		length:      6,
+		ttl:         5 * time.Minute,
+		cooldown:    time.Minute,
+		maxAttempts: 5,
+		dailyLimit:  10,
+	}
+}
+
+// WithCodeTemplate 发送验证码使用的模板（默认 verify），模板参数为 code（验证码）与 minutes（有效分钟数）
+func WithCodeTemplate(name string) Option {
+	return func(s *Sender) {
+		if name != "" {
+			s.code.template = name
+		}
+	}
+}
+
+// WithCodeLength 验证码位数（默认 6）
+func WithCodeLength(n int) Option {
+	return func(s *Sender) {
+		if n > 0 {
+			s.code.length = n
+		}
+	}
+}
+
+// WithCodeTTL 验证码有效期（默认 5 分钟），重新发送后之前的验证码失效
+func WithCodeTTL(d time.Duration) Option {
+	return func(s *Sender) {
+		if d > 0 {
+			s.code.ttl = d
+		}
+	}
+}
+
+// WithCodeCooldown 同一手机号同一用途两次发送的最小间隔（默认 1 分钟），0 表示不限制
+func WithCodeCooldown(d time.Duration) Option {
+	return func(s *Sender) {
+		if d >= 0 {
+			s.code.cooldown = d
+		}
+	}
+}
+
+// WithCodeMaxAttempts 每个验证码允许的错误次数（默认 5），超过后验证码失效
+func WithCodeMaxAttempts(n int) Option {
+	return func(s *Sender) {
+		if n > 0 {
+			s.code.maxAttempts = n
+		}
+	}
+}
+
+// WithCodeDailyLimit 同一手机号每日（按发送器时钟的自然日）最多发送的验证码数（默认 10），0 表示不限制
+func WithCodeDailyLimit(n int) Option {
+	return func(s *Sender) {
+		if n >= 0 {
+			s.code.dailyLimit = n
+		}
+	}
+}
+
+// codeState 缓存中的验证码状态，只保存验证码的摘要
+type codeState struct {
+	Hash     string `json:"h"`
+	Attempts int    `json:"n"`
+	Expires  int64  `json:"e"` // Unix 纳秒
+}
+
+// 缓存键
+func codeKey(phone, purpose string) string     { return "sms:code:" + purpose + ":" + phone }
+func cooldownKey(phone, purpose string) string { return "sms:cooldown:" + purpose + ":" + phone }
+func dailyKey(phone, day string) string        { return "sms:daily:" + day + ":" + phone }
+
+// SendCode 生成并发送验证码，purpose 区分用途（如 login、register），不同用途的验证码互不影响。
+// 冷却中返回 *CooldownError，当日次数已满返回 ErrDailyLimit；缓存的读写并非原子操作，
+// 并发请求可能在边界上多发一条，接口前应配合限流中间件
+func (s *Sender) SendCode(ctx context.Context, phone, purpose string) error {
+	now := s.clock.Now()
+	if s.code.cooldown > 0 {
+		if raw, ok, err := s.cache.Get(ctx, cooldownKey(phone, purpose)); err != nil {
+			return err
+		} else if ok {
+			until, _ := strconv.ParseInt(string(raw), 10, 64)
+			if wait := s.remaining(until); wait > 0 {
+				return s.reject(ctx, phone, purpose, &CooldownError{Wait: wait})
+			}
+		}
+	}
+	day := now.Format("20060102")
+	sent := 0
+	if s.code.dailyLimit > 0 {
+		raw, _, err := s.cache.Get(ctx, dailyKey(phone, day))
+		if err != nil {
+			return err
+		}
+		sent, _ = strconv.Atoi(string(raw))
+		if sent >= s.code.dailyLimit {
+			return s.reject(ctx, phone, purpose, ErrDailyLimit)
+		}
+	}
+
+	code, err := randomDigits(s.code.length)
+	if err != nil {
+		return err
+	}
+	minutes := strconv.Itoa(int((s.code.ttl + time.Minute - 1) / time.Minute))
+	if err := s.Send(ctx, phone, s.code.template, map[string]string{"code": code, "minutes": minutes}); err != nil {
+		return err
+	}
+
+	state, _ := json.Marshal(codeState{Hash: codeHash(phone, purpose, code), Expires: now.Add(s.code.ttl).UnixNano()})
+	if err := s.cache.Set(ctx, codeKey(phone, purpose), state, s.code.ttl); err != nil {
+		return err
+	}
+	if s.code.cooldown > 0 {
+		until := strconv.FormatInt(now.Add(s.code.cooldown).UnixNano(), 10)
+		if err := s.cache.Set(ctx, cooldownKey(phone, purpose), []byte(until), s.code.cooldown); err != nil {
+			return err
+		}
+	}
+	if s.code.dailyLimit > 0 {
+		if err := s.cache.Set(ctx, dailyKey(phone, day), []byte(strconv.Itoa(sent+1)), 24*time.Hour); err != nil {
+			return err
+		}
+	}
+	_ = s.bus.EmitCtx(ctx, EventCodeIssued, &CodeEvent{Phone: phone, Purpose: purpose})
+	return nil
+}
+
+// VerifyCode 校验验证码，通过后验证码失效；错误时返回 ErrCodeInvalid，
+// 错误次数超过上限返回 ErrTooManyAttempts，未发送或已过期返回 ErrCodeExpired
+func (s *Sender) VerifyCode(ctx context.Context, phone, purpose, code string) error {
+	key := codeKey(phone, purpose)
+	raw, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	var state codeState
+	if !ok || json.Unmarshal(raw, &state) != nil || s.remaining(state.Expires) <= 0 {
+		return s.reject(ctx, phone, purpose, ErrCodeExpired)
+	}
+	if state.Attempts >= s.code.maxAttempts {
+		return s.reject(ctx, phone, purpose, ErrTooManyAttempts)
+	}
+
+	if !hmac.Equal([]byte(state.Hash), []byte(codeHash(phone, purpose, strings.TrimSpace(code)))) {
+		state.Attempts++
+		if state.Attempts >= s.code.maxAttempts {
+			if err := s.cache.Delete(ctx, key); err != nil {
+				return err
+			}
+			return s.reject(ctx, phone, purpose, ErrTooManyAttempts)
+		}
+		next, _ := json.Marshal(state)
+		if err := s.cache.Set(ctx, key, next, s.remaining(state.Expires)); err != nil {
+			return err
+		}
+		return s.reject(ctx, phone, purpose, ErrCodeInvalid)
+	}
+
+	if err := s.cache.Delete(ctx, key); err != nil {
+		return err
+	}
+	_ = s.bus.EmitCtx(ctx, EventCodeVerified, &CodeEvent{Phone: phone, Purpose: purpose})
+	return nil
+}
+
+// reject 触发 EventCodeRejected 并返回原因
+func (s *Sender) reject(ctx context.Context, phone, purpose string, reason error) error {
+	_ = s.bus.EmitCtx(ctx, EventCodeRejected, &CodeEvent{Phone: phone, Purpose: purpose, Err: reason})
+	return reason
+}
+
+// codeHash 验证码摘要，绑定手机号与用途
+func codeHash(phone, purpose, code string) string {
+	sum := sha256.Sum256([]byte(purpose + "\x00" + phone + "\x00" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomDigits 生成 n 位随机数字
+func randomDigits(n int) (string, error) {
+	buf := make([]byte, n)
+	for i := range buf {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = byte('0' + d.Int64())
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,56 @@
+package sms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+// NewFromConfig 按 sms.* 配置创建发送器，redis 存储使用全局 Redis 配置，bus 为 nil 时使用全局事件总线
+func NewFromConfig(cfg *config.SMSConfig, redisCfg *config.RedisConfig, bus *eventbus.EventBus) (*Sender, error) {
+	var driver Driver
+	switch cfg.Driver {
+	case "", "log":
+		driver = Log{}
+	case "aliyun":
+		a := cfg.Aliyun
+		if a.AccessKeyID == "" || a.AccessKeySecret == "" || a.SignName == "" {
+			return nil, errors.New("aliyun 短信驱动需要配置 sms.aliyun.access_key_id、access_key_secret 与 sign_name")
+		}
+		driver = NewAliyun(a.AccessKeyID, a.AccessKeySecret, a.SignName, WithAliyunRegion(a.Region), WithAliyunEndpoint(a.Endpoint))
+	case "twilio":
+		t := cfg.Twilio
+		if t.AccountSID == "" || t.AuthToken == "" || t.From == "" {
+			return nil, errors.New("twilio 短信驱动需要配置 sms.twilio.account_sid、auth_token 与 from")
+		}
+		driver = NewTwilio(t.AccountSID, t.AuthToken, t.From)
+	default:
+		return nil, fmt.Errorf("未知的短信驱动: %s", cfg.Driver)
+	}
+
+	opts := []Option{
+		WithEventBus(bus),
+		WithCodeTemplate(cfg.Code.Template),
+		WithCodeLength(cfg.Code.Length),
+		WithCodeTTL(time.Duration(cfg.Code.TTL) * time.Second),
+		WithCodeCooldown(time.Duration(cfg.Code.Cooldown) * time.Second),
+		WithCodeMaxAttempts(cfg.Code.MaxAttempts),
+		WithCodeDailyLimit(cfg.Code.DailyLimit),
+	}
+	switch cfg.Store {
+	case "", "memory":
+		opts = append(opts, WithCache(cache.NewMemory()))
+	case "redis":
+		opts = append(opts, WithCache(cache.NewRedisFromConfig(redisCfg, "")))
+	default:
+		return nil, fmt.Errorf("未知的短信验证码存储: %s", cfg.Store)
+	}
+	for name, t := range cfg.Templates {
+		opts = append(opts, WithTemplate(name, Template{Code: t.Code, Content: t.Content}))
+	}
+	return New(driver, opts...), nil
+}
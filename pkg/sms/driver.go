@@ -0,0 +1,243 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+)
+
+// DefaultTimeout 请求短信服务商的默认超时
+const DefaultTimeout = 10 * time.Second
+
+// Log 只将短信写入日志的驱动，用于开发与测试环境
+type Log struct{}
+
+// Send 实现 Driver
+func (Log) Send(_ context.Context, msg *Message) error {
+	logger.Infof("[sms] to=%s template=%s content=%q", msg.To, msg.Template, msg.Content)
+	return nil
+}
+
+// Aliyun 阿里云短信服务（dysmsapi SendSms），使用模板编号与签名发送
+type Aliyun struct {
+	accessKeyID     string
+	accessKeySecret string
+	signName        string
+	endpoint        string
+	region          string
+	client          *http.Client
+}
+
+// AliyunOption 阿里云驱动选项
+type AliyunOption func(*Aliyun)
+
+// WithAliyunEndpoint 接口地址（默认 https://dysmsapi.aliyuncs.com）
+func WithAliyunEndpoint(endpoint string) AliyunOption {
+	return func(a *Aliyun) {
+		if endpoint != "" {
+			a.endpoint = strings.TrimRight(endpoint, "/")
+		}
+	}
+}
+
+// WithAliyunRegion 地域（默认 cn-hangzhou）
+func WithAliyunRegion(region string) AliyunOption {
+	return func(a *Aliyun) {
+		if region != "" {
+			a.region = region
+		}
+	}
+}
+
+// WithAliyunHTTPClient 请求使用的 HTTP 客户端，默认为传递请求 ID、超时 DefaultTimeout 的客户端
+func WithAliyunHTTPClient(client *http.Client) AliyunOption {
+	return func(a *Aliyun) { a.client = client }
+}
+
+// NewAliyun 创建阿里云短信驱动，signName 为审核通过的短信签名
+func NewAliyun(accessKeyID, accessKeySecret, signName string, opts ...AliyunOption) *Aliyun {
+	a := &Aliyun{
+		accessKeyID:     accessKeyID,
+		accessKeySecret: accessKeySecret,
+		signName:        signName,
+		endpoint:        "https://dysmsapi.aliyuncs.com",
+		region:          "cn-hangzhou",
+		client:          httpclient.New(DefaultTimeout),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Send 实现 Driver
+func (a *Aliyun) Send(ctx context.Context, msg *Message) error {
+	if msg.Code == "" {
+		return fmt.Errorf("阿里云短信模板 %s 未配置模板编号", msg.Template)
+	}
+	params := msg.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+	templateParam, err := jsonx.Marshal(params)
+	if err != nil {
+		return err
+	}
+	q := url.Values{
+		"AccessKeyId":      {a.accessKeyID},
+		"Action":           {"SendSms"},
+		"Format":           {"JSON"},
+		"PhoneNumbers":     {strings.TrimPrefix(msg.To, "+")},
+		"RegionId":         {a.region},
+		"SignName":         {a.signName},
+		"SignatureMethod":  {"HMAC-SHA1"},
+		"SignatureNonce":   {nonce()},
+		"SignatureVersion": {"1.0"},
+		"TemplateCode":     {msg.Code},
+		"TemplateParam":    {string(templateParam)},
+		"Timestamp":        {time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+		"Version":          {"2017-05-25"},
+	}
+	query := canonicalQuery(q)
+	mac := hmac.New(sha1.New, []byte(a.accessKeySecret+"&"))
+	mac.Write([]byte("GET&%2F&" + percentEncode(query)))
+	query = "Signature=" + percentEncode(base64.StdEncoding.EncodeToString(mac.Sum(nil))) + "&" + query
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.endpoint+"/?"+query, nil)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Code      string `json:"Code"`
+		Message   string `json:"Message"`
+		RequestID string `json:"RequestId"`
+	}
+	if err := doJSON(a.client, req, &resp); err != nil {
+		return fmt.Errorf("aliyun: %w", err)
+	}
+	if resp.Code != "OK" {
+		return fmt.Errorf("aliyun: %s: %s (RequestId %s)", resp.Code, resp.Message, resp.RequestID)
+	}
+	return nil
+}
+
+// canonicalQuery 按参数名排序并编码的查询串（阿里云 RPC 签名规则）
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = percentEncode(k) + "=" + percentEncode(q.Get(k))
+	}
+	return strings.Join(parts, "&")
+}
+
+// percentEncode RFC 3986 编码：空格为 %20，保留 ~
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	return strings.NewReplacer("+", "%20", "*", "%2A", "%7E", "~").Replace(s)
+}
+
+// nonce 签名随机数
+func nonce() string {
+	n, _ := randomDigits(16)
+	return n
+}
+
+// Twilio Twilio 短信服务，发送模板渲染后的正文
+type Twilio struct {
+	accountSID string
+	authToken  string
+	from       string
+	endpoint   string
+	client     *http.Client
+}
+
+// TwilioOption Twilio 驱动选项
+type TwilioOption func(*Twilio)
+
+// WithTwilioEndpoint 接口地址（默认 https://api.twilio.com）
+func WithTwilioEndpoint(endpoint string) TwilioOption {
+	return func(t *Twilio) {
+		if endpoint != "" {
+			t.endpoint = strings.TrimRight(endpoint, "/")
+		}
+	}
+}
+
+// WithTwilioHTTPClient 请求使用的 HTTP 客户端，默认为传递请求 ID、超时 DefaultTimeout 的客户端
+func WithTwilioHTTPClient(client *http.Client) TwilioOption {
+	return func(t *Twilio) { t.client = client }
+}
+
+// NewTwilio 创建 Twilio 驱动，from 为发送号码（E.164）或以 MG 开头的 Messaging Service SID
+func NewTwilio(accountSID, authToken, from string, opts ...TwilioOption) *Twilio {
+	t := &Twilio{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		endpoint:   "https://api.twilio.com",
+		client:     httpclient.New(DefaultTimeout),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Send 实现 Driver
+func (t *Twilio) Send(ctx context.Context, msg *Message) error {
+	form := url.Values{"To": {msg.To}, "Body": {msg.Content}}
+	if strings.HasPrefix(t.from, "MG") {
+		form.Set("MessagingServiceSid", t.from)
+	} else {
+		form.Set("From", t.from)
+	}
+	u := t.endpoint + "/2010-04-01/Accounts/" + url.PathEscape(t.accountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := doJSON(t.client, req, nil); err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	return nil
+}
+
+// doJSON 发送请求，响应为 2xx 时将 JSON 解码到 out（可为 nil），否则返回包含响应内容的错误
+func doJSON(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body[:min(len(body), 1<<10)]))
+	}
+	if out == nil {
+		return nil
+	}
+	return jsonx.Unmarshal(body, out)
+}
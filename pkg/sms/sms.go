@@ -0,0 +1,201 @@
+// Package sms 提供短信发送抽象与短信验证码：发送经由可替换的驱动（log、阿里云、Twilio），
+// 正文使用按名称注册的模板；验证码的签发与校验带发送冷却、每日上限与尝试次数限制，状态保存在缓存中。
+// 发送与验证码的每一步都会在事件总线上触发事件，可订阅后写入审计日志：
+//
+//	_ = sms.Send(ctx, "13800000000", "order_shipped", map[string]string{"no": order.No})
+//
+//	err := sms.SendCode(ctx, phone, "login") // 冷却中返回 *sms.CooldownError
+//	err = sms.VerifyCode(ctx, phone, "login", form.Code)
+//
+//	eventbus.On("sms.**", func(args ...interface{}) { ... }) // 含 sms.code.* 事件
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+// 事件名称
+const (
+	// EventSent 短信发送成功，参数为 *Delivery
+	EventSent = "sms.sent"
+	// EventFailed 短信发送失败，参数为 *Delivery
+	EventFailed = "sms.failed"
+	// EventCodeIssued 验证码已发送，参数为 *CodeEvent
+	EventCodeIssued = "sms.code.issued"
+	// EventCodeVerified 验证码校验通过，参数为 *CodeEvent
+	EventCodeVerified = "sms.code.verified"
+	// EventCodeRejected 验证码校验未通过或发送被限制，参数为 *CodeEvent（Err 为原因）
+	EventCodeRejected = "sms.code.rejected"
+)
+
+var (
+	// ErrNotConfigured 未设置全局发送器
+	ErrNotConfigured = errors.New("短信发送器未配置")
+	// ErrUnknownTemplate 模板未注册
+	ErrUnknownTemplate = errors.New("短信模板未注册")
+)
+
+// Template 短信模板。Code 为服务商审核通过的模板编号（阿里云），Content 为模板正文，
+// 其中的 ${name} 以参数替换，用于直接发送正文的服务商（Twilio、log）
+type Template struct {
+	Code    string
+	Content string
+}
+
+// Message 待发送的短信
+type Message struct {
+	To       string            // 手机号，国际号码使用 E.164 格式，如 +8613800000000
+	Template string            // 模板名称
+	Code     string            // 服务商模板编号
+	Content  string            // 以参数渲染后的正文
+	Params   map[string]string // 模板参数
+}
+
+// Driver 短信服务商驱动
+type Driver interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Delivery 发送事件载荷
+type Delivery struct {
+	Message *Message
+	Err     error // 发送失败的原因，成功时为 nil
+}
+
+// Sender 短信发送器
+type Sender struct {
+	driver    Driver
+	templates map[string]Template
+	bus       *eventbus.EventBus
+	cache     cache.Store
+	clock     clock.Clock
+	code      codeConfig
+}
+
+// Option 发送器选项
+type Option func(*Sender)
+
+// WithTemplate 注册模板
+func WithTemplate(name string, tpl Template) Option {
+	return func(s *Sender) { s.templates[name] = tpl }
+}
+
+// WithEventBus 发送与验证码事件使用的事件总线（默认全局事件总线）
+func WithEventBus(bus *eventbus.EventBus) Option {
+	return func(s *Sender) { s.bus = bus }
+}
+
+// WithCache 验证码状态的存储（默认 cache.Default()），多实例部署时使用 Redis
+func WithCache(store cache.Store) Option {
+	return func(s *Sender) { s.cache = store }
+}
+
+// WithClock 验证码有效期与冷却计算使用的时钟（默认全局时钟）
+func WithClock(clk clock.Clock) Option {
+	return func(s *Sender) { s.clock = clk }
+}
+
+// New 创建发送器
+func New(driver Driver, opts ...Option) *Sender {
+	s := &Sender{
+		driver:    driver,
+		templates: make(map[string]Template),
+		code:      defaultCodeConfig(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.bus == nil {
+		s.bus = eventbus.Default()
+	}
+	if s.cache == nil {
+		s.cache = cache.Default()
+	}
+	if s.clock == nil {
+		s.clock = clock.Default()
+	}
+	return s
+}
+
+// Send 以模板发送短信，params 为模板参数
+func (s *Sender) Send(ctx context.Context, to, template string, params map[string]string) error {
+	tpl, ok := s.templates[template]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, template)
+	}
+	msg := &Message{To: to, Template: template, Code: tpl.Code, Content: Render(tpl.Content, params), Params: params}
+	err := s.driver.Send(ctx, msg)
+	if err != nil {
+		err = fmt.Errorf("发送短信失败: %w", err)
+		_ = s.bus.EmitCtx(ctx, EventFailed, &Delivery{Message: msg, Err: err})
+		return err
+	}
+	_ = s.bus.EmitCtx(ctx, EventSent, &Delivery{Message: msg})
+	return nil
+}
+
+// placeholder 模板正文中的 ${name} 占位符
+var placeholder = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// Render 以参数替换正文中的 ${name}，参数缺失时保留原样
+func Render(content string, params map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(content, func(m string) string {
+		if v, ok := params[m[2:len(m)-1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+var defaultSender atomic.Pointer[Sender]
+
+// SetDefault 设置全局发送器（由 bootstrap 按 sms.* 配置调用）
+func SetDefault(s *Sender) {
+	defaultSender.Store(s)
+}
+
+// Default 返回全局发送器，未设置时返回 nil
+func Default() *Sender {
+	return defaultSender.Load()
+}
+
+// Send 使用全局发送器以模板发送短信
+func Send(ctx context.Context, to, template string, params map[string]string) error {
+	s := Default()
+	if s == nil {
+		return ErrNotConfigured
+	}
+	return s.Send(ctx, to, template, params)
+}
+
+// SendCode 使用全局发送器发送验证码
+func SendCode(ctx context.Context, phone, purpose string) error {
+	s := Default()
+	if s == nil {
+		return ErrNotConfigured
+	}
+	return s.SendCode(ctx, phone, purpose)
+}
+
+// VerifyCode 使用全局发送器校验验证码
+func VerifyCode(ctx context.Context, phone, purpose, code string) error {
+	s := Default()
+	if s == nil {
+		return ErrNotConfigured
+	}
+	return s.VerifyCode(ctx, phone, purpose, code)
+}
+
+// remaining 距 deadline（Unix 纳秒）的时间
+func (s *Sender) remaining(deadline int64) time.Duration {
+	return time.Duration(deadline - s.clock.Now().UnixNano())
+}
@@ -0,0 +1,260 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+)
+
+// fakeDriver 记录发送的短信，err 非 nil 时发送失败
+type fakeDriver struct {
+	mu   sync.Mutex
+	sent []*Message
+	err  error
+}
+
+func (d *fakeDriver) Send(_ context.Context, msg *Message) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.sent = append(d.sent, msg)
+	return nil
+}
+
+func (d *fakeDriver) last(t *testing.T) *Message {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.sent) == 0 {
+		t.Fatal("没有发送短信")
+	}
+	return d.sent[len(d.sent)-1]
+}
+
+// record 订阅 sms.** 并按顺序记录事件名称
+func record(bus *eventbus.EventBus) *[]string {
+	var events []string
+	bus.OnE("sms.**", func(ctx context.Context, _ ...interface{}) error {
+		meta, _ := eventbus.EventFromContext(ctx)
+		events = append(events, meta.Name)
+		return nil
+	})
+	return &events
+}
+
+var digits = regexp.MustCompile(`\d+`)
+
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	bus := eventbus.New()
+	events := record(bus)
+	d := &fakeDriver{}
+	s := New(d, WithEventBus(bus), WithTemplate("shipped", Template{Code: "SMS_1", Content: "订单 ${no} 已发货，${missing}"}))
+
+	if err := s.Send(ctx, "13800000000", "shipped", map[string]string{"no": "A100"}); err != nil {
+		t.Fatal(err)
+	}
+	msg := d.last(t)
+	if msg.To != "13800000000" || msg.Code != "SMS_1" || msg.Content != "订单 A100 已发货，${missing}" {
+		t.Errorf("message = %+v", msg)
+	}
+
+	if err := s.Send(ctx, "13800000000", "nope", nil); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("未注册模板 err = %v", err)
+	}
+
+	d.err = errors.New("boom")
+	if err := s.Send(ctx, "13800000000", "shipped", nil); err == nil || !errors.Is(err, d.err) {
+		t.Errorf("发送失败 err = %v", err)
+	}
+	if got := strings.Join(*events, ","); got != EventSent+","+EventFailed {
+		t.Errorf("events = %s", got)
+	}
+}
+
+func TestCode(t *testing.T) {
+	ctx := context.Background()
+	bus := eventbus.New()
+	events := record(bus)
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	d := &fakeDriver{}
+	s := New(d,
+		WithEventBus(bus), WithCache(cache.NewMemory()), WithClock(clk),
+		WithTemplate("verify", Template{Content: "验证码 ${code}，${minutes} 分钟内有效"}),
+		WithCodeTTL(2*time.Minute), WithCodeMaxAttempts(2), WithCodeDailyLimit(3),
+	)
+	code := func() string { return digits.FindString(d.last(t).Content) }
+
+	if err := s.SendCode(ctx, "138", "login"); err != nil {
+		t.Fatal(err)
+	}
+	if c := code(); len(c) != 6 || !strings.Contains(d.last(t).Content, "2 分钟") {
+		t.Errorf("content = %q", d.last(t).Content)
+	}
+
+	// 冷却
+	clk.Advance(20 * time.Second)
+	err := s.SendCode(ctx, "138", "login")
+	var cd *CooldownError
+	if !errors.As(err, &cd) || !errors.Is(err, ErrCooldown) || cd.Wait != 40*time.Second {
+		t.Fatalf("冷却 err = %v", err)
+	}
+	if err := s.SendCode(ctx, "138", "register"); err != nil {
+		t.Errorf("不同用途不受冷却影响: %v", err)
+	}
+
+	// 错误次数
+	clk.Advance(time.Minute)
+	if err := s.SendCode(ctx, "138", "login"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyCode(ctx, "138", "login", "x"); !errors.Is(err, ErrCodeInvalid) {
+		t.Errorf("错误验证码 err = %v", err)
+	}
+	if err := s.VerifyCode(ctx, "138", "login", "x"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("超过错误次数 err = %v", err)
+	}
+	if err := s.VerifyCode(ctx, "138", "login", code()); !errors.Is(err, ErrCodeExpired) {
+		t.Errorf("失效后 err = %v", err)
+	}
+
+	// 每日上限：login 两次 + register 一次
+	clk.Advance(time.Minute)
+	if err := s.SendCode(ctx, "138", "login"); !errors.Is(err, ErrDailyLimit) {
+		t.Fatalf("每日上限 err = %v", err)
+	}
+
+	// 校验通过后失效，过期后失效
+	if err := s.SendCode(ctx, "139", "login"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyCode(ctx, "139", "login", " "+code()+" "); err != nil {
+		t.Errorf("正确验证码 err = %v", err)
+	}
+	if err := s.VerifyCode(ctx, "139", "login", code()); !errors.Is(err, ErrCodeExpired) {
+		t.Errorf("重复使用 err = %v", err)
+	}
+	clk.Advance(time.Minute)
+	if err := s.SendCode(ctx, "139", "login"); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(2*time.Minute + time.Second)
+	if err := s.VerifyCode(ctx, "139", "login", code()); !errors.Is(err, ErrCodeExpired) {
+		t.Errorf("过期 err = %v", err)
+	}
+
+	want := []string{
+		EventSent, EventCodeIssued, EventCodeRejected, EventSent, EventCodeIssued,
+		EventSent, EventCodeIssued, EventCodeRejected, EventCodeRejected, EventCodeRejected,
+		EventCodeRejected,
+		EventSent, EventCodeIssued, EventCodeVerified, EventCodeRejected,
+		EventSent, EventCodeIssued, EventCodeRejected,
+	}
+	if got := strings.Join(*events, ","); got != strings.Join(want, ",") {
+		t.Errorf("events = %s", got)
+	}
+}
+
+func TestAliyun(t *testing.T) {
+	code := "OK"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		sig := q.Get("Signature")
+		q.Del("Signature")
+		mac := hmac.New(sha1.New, []byte("secret&"))
+		mac.Write([]byte("GET&%2F&" + percentEncode(canonicalQuery(q))))
+		if sig != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("签名不匹配: %s", sig)
+		}
+		if q.Get("AccessKeyId") != "id" || q.Get("PhoneNumbers") != "8613800000000" || q.Get("SignName") != "测试" ||
+			q.Get("TemplateCode") != "SMS_1" || q.Get("TemplateParam") != `{"code":"1234"}` {
+			t.Errorf("query = %v", q)
+		}
+		w.Write([]byte(`{"Code":"` + code + `","Message":"m","RequestId":"r"}`))
+	}))
+	defer srv.Close()
+
+	a := NewAliyun("id", "secret", "测试", WithAliyunEndpoint(srv.URL+"/"))
+	msg := &Message{To: "+8613800000000", Template: "verify", Code: "SMS_1", Params: map[string]string{"code": "1234"}}
+	if err := a.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	code = "isv.BUSINESS_LIMIT_CONTROL"
+	if err := a.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), code) {
+		t.Errorf("err = %v", err)
+	}
+	if err := a.Send(context.Background(), &Message{Template: "verify"}); err == nil {
+		t.Error("缺少模板编号应返回错误")
+	}
+}
+
+func TestTwilio(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/2010-04-01/Accounts/AC1/Messages.json" || user != "AC1" || pass != "token" {
+			t.Errorf("path = %s auth = %s:%s", r.URL.Path, user, pass)
+		}
+		if r.FormValue("To") == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21211}`))
+			return
+		}
+		if r.FormValue("Body") != "hi" || r.FormValue("MessagingServiceSid") != "MG1" || r.FormValue("From") != "" {
+			t.Errorf("form = %v", r.PostForm)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM1"}`))
+	}))
+	defer srv.Close()
+
+	tw := NewTwilio("AC1", "token", "MG1", WithTwilioEndpoint(srv.URL))
+	if err := tw.Send(context.Background(), &Message{To: "+15550001", Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Send(context.Background(), &Message{To: "bad", Content: "hi"}); err == nil || !strings.Contains(err.Error(), "21211") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	cfg := &config.SMSConfig{
+		Driver:    "twilio",
+		Twilio:    config.SMSTwilioConfig{AccountSID: "AC1", AuthToken: "t", From: "+1555"},
+		Templates: map[string]config.SMSTemplateConfig{"verify": {Content: "${code}"}},
+		Code:      config.SMSCodeConfig{Length: 4, TTL: 60, Cooldown: 0},
+	}
+	s, err := NewFromConfig(cfg, &config.RedisConfig{}, eventbus.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.driver.(*Twilio); !ok || s.code.length != 4 || s.code.ttl != time.Minute || s.code.cooldown != 0 || s.templates["verify"].Content != "${code}" {
+		t.Errorf("sender = %+v", s)
+	}
+
+	for _, bad := range []*config.SMSConfig{
+		{Driver: "aliyun"},
+		{Driver: "twilio"},
+		{Driver: "carrier-pigeon"},
+		{Store: "file"},
+	} {
+		if _, err := NewFromConfig(bad, &config.RedisConfig{}, nil); err == nil {
+			t.Errorf("%+v 应返回错误", bad)
+		}
+	}
+}
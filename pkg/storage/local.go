@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Local 本地磁盘存储
+type Local struct {
+	root    string
+	baseURL string
+}
+
+// NewLocal 创建本地存储，baseURL 为公开访问地址前缀（如 /uploads），目录不存在时在首次写入时创建
+func NewLocal(root, baseURL string) *Local {
+	return &Local{root: root, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// fullPath 返回文件的本地路径
+func (l *Local) fullPath(p string) (string, error) {
+	p, err := cleanPath(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(p)), nil
+}
+
+// Put 实现 Filesystem，先写临时文件再重命名，读取方不会读到写了一半的文件
+func (l *Local) Put(_ context.Context, p string, r io.Reader, _ ...PutOption) error {
+	full, err := l.fullPath(p)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(full), ".upload-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		// CreateTemp 以 0600 创建，与普通文件保持一致以便 Web 服务器读取
+		if err = os.Chmod(f.Name(), 0o644); err == nil {
+			err = os.Rename(f.Name(), full)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// Get 实现 Filesystem
+func (l *Local) Get(_ context.Context, p string) (io.ReadCloser, error) {
+	full, err := l.fullPath(p)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(full)
+}
+
+// Delete 实现 Filesystem
+func (l *Local) Delete(_ context.Context, p string) error {
+	full, err := l.fullPath(p)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL 实现 Filesystem
+func (l *Local) URL(p string) string {
+	return l.baseURL + "/" + (&url.URL{Path: strings.TrimPrefix(p, "/")}).EscapedPath()
+}
+
+// TemporaryURL 实现 Filesystem，本地存储不支持临时地址
+func (l *Local) TemporaryURL(context.Context, string, time.Duration) (string, error) {
+	return "", ErrUnsupported
+}
+
+// List 实现 Filesystem，从 prefix 所在的目录开始遍历
+func (l *Local) List(_ context.Context, prefix string) ([]Object, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	dir := "."
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		var err error
+		if dir, err = cleanPath(prefix[:i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var objects []Object
+	start := filepath.Join(l.root, filepath.FromSlash(dir))
+	err := filepath.WalkDir(start, func(full string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, full)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !strings.HasPrefix(rel, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Path: path.Clean(rel), Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+	return objects, err
+}
@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// 分片上传的分片大小：S3 要求除最后一片外不小于 5 MB
+const (
+	MinPartSize     = 5 << 20
+	DefaultPartSize = 8 << 20
+)
+
+// maxPresignExpires 临时地址的最长有效期（SigV4 上限）
+const maxPresignExpires = 7 * 24 * time.Hour
+
+// S3 S3 兼容对象存储（AWS S3、MinIO、阿里云 OSS 等），请求以 SigV4 签名
+type S3 struct {
+	client    *http.Client
+	signer    signer
+	endpoint  *url.URL
+	bucket    string
+	root      string
+	pathStyle bool
+	baseURL   string
+	partSize  int
+}
+
+// NewS3 按配置创建 S3 存储，region 为空时为 us-east-1，endpoint 为空时使用 AWS 地址
+func NewS3(cfg config.DiskConfig) (*S3, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("未配置 bucket")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("无效的 endpoint: %q", endpoint)
+	}
+	partSize := DefaultPartSize
+	if cfg.PartSize > 0 {
+		partSize = int(max(cfg.PartSize, MinPartSize))
+	}
+	return &S3{
+		client:    &http.Client{},
+		signer:    signer{accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, region: region},
+		endpoint:  u,
+		bucket:    cfg.Bucket,
+		root:      strings.Trim(cfg.Root, "/"),
+		pathStyle: cfg.PathStyle,
+		baseURL:   strings.TrimSuffix(cfg.URL, "/"),
+		partSize:  partSize,
+	}, nil
+}
+
+// key 返回对象键（拼接 root 前缀）
+func (s *S3) key(p string) (string, error) {
+	p, err := cleanPath(p)
+	if err != nil {
+		return "", err
+	}
+	if s.root != "" {
+		p = s.root + "/" + p
+	}
+	return p, nil
+}
+
+// objectURL 返回对象地址，key 为空时为存储桶地址
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	p := "/" + key
+	if s.pathStyle {
+		p = "/" + s.bucket + strings.TrimSuffix(p, "/")
+		if key != "" {
+			p = "/" + s.bucket + "/" + key
+		}
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = p
+	u.RawPath = encodePath(p)
+	return &u
+}
+
+// encodePath 逐段编码路径，使发出的路径与签名使用的规范路径一致
+func encodePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// s3Error 服务端返回的错误
+type s3Error struct {
+	Status  int    `xml:"-"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	return fmt.Sprintf("对象存储请求失败（%d %s）: %s", e.Status, e.Code, e.Message)
+}
+
+// Is 使 errors.Is(err, fs.ErrNotExist) 可判断对象不存在
+func (e *s3Error) Is(target error) bool {
+	return target == fs.ErrNotExist && (e.Status == http.StatusNotFound || e.Code == "NoSuchKey")
+}
+
+// do 发送签名请求，非 2xx 响应转为 *s3Error
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, body []byte, header http.Header) (*http.Response, error) {
+	u := s.objectURL(key)
+	u.RawQuery = canonicalQuery(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	s.signer.sign(req, hashHex(body), clock.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		e := &s3Error{Status: resp.StatusCode}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = xml.Unmarshal(b, e)
+		return nil, e
+	}
+	return resp, nil
+}
+
+// doXML 发送请求并解析 XML 响应
+func (s *S3) doXML(ctx context.Context, method, key string, query url.Values, body []byte, v any) error {
+	resp, err := s.do(ctx, method, key, query, body, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	// CompleteMultipartUpload 可能以 200 状态返回错误
+	if bytes.Contains(b, []byte("<Error>")) {
+		e := &s3Error{Status: resp.StatusCode}
+		_ = xml.Unmarshal(b, e)
+		return e
+	}
+	return xml.Unmarshal(b, v)
+}
+
+// Put 实现 Filesystem：不超过一个分片时直接上传，否则以分片上传流式写入，失败时取消分片上传
+func (s *S3) Put(ctx context.Context, p string, r io.Reader, opts ...PutOption) error {
+	key, err := s.key(p)
+	if err != nil {
+		return err
+	}
+	var o PutOptions
+	for _, fn := range opts {
+		fn(&o)
+	}
+	header := http.Header{}
+	if o.ContentType != "" {
+		header.Set("Content-Type", o.ContentType)
+	}
+
+	buf := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		resp, err := s.do(ctx, http.MethodPut, key, nil, buf[:n], header)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, header)
+	if err != nil {
+		return err
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if err := s.uploadParts(ctx, key, initiated.UploadID, r, buf, n); err != nil {
+		abort, _ := s.do(context.WithoutCancel(ctx), http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadID}}, nil, nil)
+		if abort != nil {
+			abort.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+// completedPart 已上传的分片
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts 依次上传分片（首个分片已读入 buf[:n]）并完成分片上传
+func (s *S3) uploadParts(ctx context.Context, key, uploadID string, r io.Reader, buf []byte, n int) error {
+	var parts []completedPart
+	for number := 1; n > 0; number++ {
+		q := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+		resp, err := s.do(ctx, http.MethodPut, key, q, buf[:n], nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		parts = append(parts, completedPart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+
+		n, err = io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	var result struct{}
+	return s.doXML(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body, &result)
+}
+
+// Get 实现 Filesystem
+func (s *S3) Get(ctx context.Context, p string) (io.ReadCloser, error) {
+	key, err := s.key(p)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete 实现 Filesystem
+func (s *S3) Delete(ctx context.Context, p string) error {
+	key, err := s.key(p)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// URL 实现 Filesystem，配置了 url（如 CDN）时以其为前缀，否则为存储桶中的对象地址
+func (s *S3) URL(p string) string {
+	key, err := s.key(p)
+	if err != nil {
+		return ""
+	}
+	if s.baseURL != "" {
+		return s.baseURL + "/" + encodePath(key)
+	}
+	return s.objectURL(key).String()
+}
+
+// TemporaryURL 实现 Filesystem，返回预签名的下载地址，有效期最长 7 天
+func (s *S3) TemporaryURL(_ context.Context, p string, expires time.Duration) (string, error) {
+	key, err := s.key(p)
+	if err != nil {
+		return "", err
+	}
+	if expires <= 0 || expires > maxPresignExpires {
+		return "", fmt.Errorf("临时地址有效期须在 0 到 %s 之间", maxPresignExpires)
+	}
+	return s.signer.presign(http.MethodGet, s.objectURL(key), expires, clock.Now()), nil
+}
+
+// List 实现 Filesystem，分页读取全部结果
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if s.root != "" {
+		prefix = s.root + "/" + prefix
+	}
+	var objects []Object
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := s.doXML(ctx, http.MethodGet, "", q, nil, &result); err != nil {
+			return nil, err
+		}
+		for _, c := range result.Contents {
+			rel := c.Key
+			if s.root != "" {
+				rel = strings.TrimPrefix(rel, s.root+"/")
+			}
+			objects = append(objects, Object{Path: path.Clean(rel), Size: c.Size, LastModified: c.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
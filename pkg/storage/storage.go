@@ -0,0 +1,192 @@
+// Package storage 提供统一的文件存储接口，内置本地磁盘与 S3 兼容对象存储（AWS S3、MinIO、阿里云 OSS）驱动，
+// 读写均为流式，大文件上传到对象存储时自动分片，内存占用不超过一个分片。
+//
+//	disk, _ := storage.Disk("s3")
+//	path, err := storage.PutFile(ctx, disk, "avatars", fileHeader) // 表单上传的文件
+//	url, err := disk.TemporaryURL(ctx, path, 10*time.Minute)     // 私有文件的临时地址
+//
+// 存储按 storage.disks 配置创建（见 NewManager），由 bootstrap 以 fx 提供 *storage.Manager 并设为全局实例。
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+var (
+	// ErrInvalidPath 路径为空、为绝对路径或逃出存储根目录
+	ErrInvalidPath = errors.New("无效的存储路径")
+	// ErrUnsupported 驱动不支持该操作（如本地存储的临时地址）
+	ErrUnsupported = errors.New("存储驱动不支持该操作")
+)
+
+// Object 存储中的文件
+type Object struct {
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// PutOptions 写入选项
+type PutOptions struct {
+	ContentType string
+}
+
+// PutOption 写入选项函数
+type PutOption func(*PutOptions)
+
+// WithContentType 设置文件的 MIME 类型（对象存储在下载时作为 Content-Type 返回）
+func WithContentType(contentType string) PutOption {
+	return func(o *PutOptions) { o.ContentType = contentType }
+}
+
+// Filesystem 文件存储，path 为以 / 分隔的相对路径；文件不存在时 Get 返回的错误满足 errors.Is(err, fs.ErrNotExist)
+type Filesystem interface {
+	// Put 从 r 流式写入文件，已存在时覆盖
+	Put(ctx context.Context, path string, r io.Reader, opts ...PutOption) error
+	// Get 读取文件，调用方负责关闭
+	Get(ctx context.Context, path string) (io.ReadCloser, error)
+	// Delete 删除文件，不存在时不报错
+	Delete(ctx context.Context, path string) error
+	// URL 返回公开访问地址
+	URL(path string) string
+	// TemporaryURL 返回有效期为 expires 的临时访问地址（私有文件分享），不支持时返回 ErrUnsupported
+	TemporaryURL(ctx context.Context, path string, expires time.Duration) (string, error)
+	// List 列出路径以 prefix 开头的文件，按路径排序
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// cleanPath 规范化相对路径，拒绝绝对路径与 ..
+func cleanPath(p string) (string, error) {
+	p = path.Clean(strings.TrimPrefix(p, "/"))
+	if p == "." || !filepath.IsLocal(filepath.FromSlash(p)) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, p)
+	}
+	return p, nil
+}
+
+// Driver 存储驱动工厂，按配置创建 Filesystem
+type Driver func(cfg config.DiskConfig) (Filesystem, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Driver{
+		"local": func(cfg config.DiskConfig) (Filesystem, error) { return NewLocal(cfg.Root, cfg.URL), nil },
+		"s3":    func(cfg config.DiskConfig) (Filesystem, error) { return NewS3(cfg) },
+		"oss":   func(cfg config.DiskConfig) (Filesystem, error) { return NewS3(cfg) },
+	}
+)
+
+// RegisterDriver 注册存储驱动，之后可在配置中以 driver: name 选用；同名注册会覆盖内置驱动
+func RegisterDriver(name string, driver Driver) {
+	if driver == nil {
+		panic("storage: 驱动不能为 nil: " + name)
+	}
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[name] = driver
+}
+
+// Manager 按名称管理多个存储
+type Manager struct {
+	disks       map[string]Filesystem
+	defaultName string
+}
+
+// NewManager 按 storage.disks 创建全部存储
+func NewManager(cfg *config.StorageConfig) (*Manager, error) {
+	m := &Manager{disks: make(map[string]Filesystem, len(cfg.Disks)), defaultName: cfg.Default}
+	for name, dc := range cfg.Disks {
+		driversMu.RLock()
+		driver, ok := drivers[dc.Driver]
+		driversMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("存储 %s 使用了未注册的驱动: %q", name, dc.Driver)
+		}
+		fs, err := driver(dc)
+		if err != nil {
+			return nil, fmt.Errorf("存储 %s 初始化失败: %w", name, err)
+		}
+		m.disks[name] = fs
+	}
+	return m, nil
+}
+
+// Disk 返回指定名称的存储，name 为空时返回默认存储
+func (m *Manager) Disk(name string) (Filesystem, error) {
+	if name == "" {
+		name = m.defaultName
+	}
+	fs, ok := m.disks[name]
+	if !ok {
+		return nil, fmt.Errorf("未配置的存储: %q", name)
+	}
+	return fs, nil
+}
+
+// Names 返回已配置的存储名称（已排序）
+func (m *Manager) Names() []string {
+	names := make([]string, 0, len(m.disks))
+	for name := range m.disks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var defaultManager atomic.Pointer[Manager]
+
+// SetDefault 设置全局存储管理器（由 bootstrap 调用）
+func SetDefault(m *Manager) {
+	defaultManager.Store(m)
+}
+
+// Default 返回全局存储管理器，未初始化时返回 nil
+func Default() *Manager {
+	return defaultManager.Load()
+}
+
+// Disk 从全局存储管理器获取存储，name 为空时返回默认存储
+func Disk(name string) (Filesystem, error) {
+	m := Default()
+	if m == nil {
+		return nil, errors.New("存储未初始化")
+	}
+	return m.Disk(name)
+}
+
+// PutFile 将表单上传的文件流式写入 dir 目录，以随机文件名保存（保留扩展名），返回存储路径
+func PutFile(ctx context.Context, fs Filesystem, dir string, fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	name := hex.EncodeToString(b) + strings.ToLower(path.Ext(fh.Filename))
+	p := path.Join(strings.Trim(dir, "/"), name)
+	var opts []PutOption
+	if ct := fh.Header.Get("Content-Type"); ct != "" {
+		opts = append(opts, WithContentType(ct))
+	}
+	if err := fs.Put(ctx, p, f, opts...); err != nil {
+		return "", err
+	}
+	return p, nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"a/b.txt", "a/b.txt", true},
+		{"/a//b/../c.txt", "a/c.txt", true},
+		{"", "", false},
+		{"../etc/passwd", "", false},
+		{"a/../../b", "", false},
+	}
+	for _, tt := range tests {
+		got, err := cleanPath(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("cleanPath(%q) = %q, %v", tt.in, got, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("cleanPath(%q) 错误应为 ErrInvalidPath: %v", tt.in, err)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	l := NewLocal(root, "/uploads/")
+
+	if err := l.Put(ctx, "a/1.txt", strings.NewReader("one")); err != nil {
+		t.Fatal(err)
+	}
+	l.Put(ctx, "a/2.txt", strings.NewReader("two"))
+	l.Put(ctx, "b/3.txt", strings.NewReader("three"))
+	if info, err := os.Stat(filepath.Join(root, "a", "1.txt")); err != nil || info.Mode().Perm() != 0o644 {
+		t.Errorf("写入的文件: %v, %v", info, err)
+	}
+
+	rc, err := l.Get(ctx, "a/1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(b) != "one" {
+		t.Errorf("Get = %q", b)
+	}
+	if _, err := l.Get(ctx, "missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("不存在的文件: %v", err)
+	}
+	if _, err := l.Get(ctx, "../x"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("越界路径: %v", err)
+	}
+
+	objects, err := l.List(ctx, "a/")
+	if err != nil || len(objects) != 2 || objects[0].Path != "a/1.txt" || objects[1].Size != 3 {
+		t.Errorf("List(a/) = %+v, %v", objects, err)
+	}
+	if objects, _ := l.List(ctx, ""); len(objects) != 3 {
+		t.Errorf("List() = %+v", objects)
+	}
+
+	if got := l.URL("a/hello world.txt"); got != "/uploads/a/hello%20world.txt" {
+		t.Errorf("URL = %q", got)
+	}
+	if _, err := l.TemporaryURL(ctx, "a/1.txt", 0); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("TemporaryURL: %v", err)
+	}
+
+	if err := l.Delete(ctx, "a/1.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Delete(ctx, "a/1.txt"); err != nil {
+		t.Errorf("删除不存在的文件不应报错: %v", err)
+	}
+}
+
+func TestManager(t *testing.T) {
+	m, err := NewManager(&config.StorageConfig{
+		Default: "local",
+		Disks:   map[string]config.DiskConfig{"local": {Driver: "local", Root: t.TempDir()}, "s3": {Driver: "s3", Bucket: "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs, err := m.Disk(""); err != nil || fs.(*Local) == nil {
+		t.Errorf("默认存储: %v", err)
+	}
+	if _, err := m.Disk("s3"); err != nil {
+		t.Errorf("s3: %v", err)
+	}
+	if _, err := m.Disk("missing"); err == nil {
+		t.Error("未配置的存储应返回错误")
+	}
+	if names := m.Names(); len(names) != 2 || names[0] != "local" {
+		t.Errorf("Names = %v", names)
+	}
+
+	if _, err := NewManager(&config.StorageConfig{Disks: map[string]config.DiskConfig{"x": {Driver: "ftp"}}}); err == nil {
+		t.Error("未注册的驱动应返回错误")
+	}
+	if _, err := NewManager(&config.StorageConfig{Disks: map[string]config.DiskConfig{"x": {Driver: "s3"}}}); err == nil {
+		t.Error("s3 未配置 bucket 应返回错误")
+	}
+}
+
+func TestPutFile(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile("file", "Photo.JPG")
+	part.Write([]byte("jpeg-data"))
+	w.Close()
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocal(t.TempDir(), "/uploads")
+	p, err := PutFile(context.Background(), l, "/avatars/", req.MultipartForm.File["file"][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(p, "avatars/") || !strings.HasSuffix(p, ".jpg") || len(p) != len("avatars/")+32+4 {
+		t.Errorf("PutFile = %q", p)
+	}
+	rc, err := l.Get(context.Background(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if b, _ := io.ReadAll(rc); string(b) != "jpeg-data" {
+		t.Errorf("内容 = %q", b)
+	}
+}
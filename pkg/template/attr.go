@@ -0,0 +1,126 @@
+package template
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// attrNameRegex 合法的属性名（不含空白、引号、= 等会破坏标签结构的字符）
+var attrNameRegex = regexp.MustCompile(`^[a-zA-Z_:][-a-zA-Z0-9_:.]*$`)
+
+// urlAttrs 值为 URL 的属性，仅允许 http、https、mailto、tel 与相对地址
+var urlAttrs = []string{"href", "src", "action", "formaction", "poster", "cite", "background", "srcset"}
+
+// Attr 按键值对输出 HTML 属性：值经转义；值为 true 时输出无值属性、false 或 nil 时省略；
+// URL 属性中的 javascript: 等不安全协议替换为 #ZgotmplZ（与 html/template 一致）；
+// 属性名非法或为事件属性（on*）时返回错误，避免把数据当作脚本执行
+//
+// 模板使用示例:
+// <div {{ attr "data-id" .ID "data-name" .Name "hidden" .Hidden }}> <!-- 输出: <div data-id="5" data-name="Tom &amp; Jerry"> -->
+// <a {{ attr "href" .Link }}>链接</a> <!-- .Link 为 "javascript:alert(1)" 时输出: <a href="#ZgotmplZ"> -->
+func Attr(pairs ...any) (template.HTMLAttr, error) {
+	list, err := parseAttrs(pairs)
+	if err != nil {
+		return "", err
+	}
+	out := make(attrs, 0, len(list))
+	for _, a := range list {
+		name := strings.ToLower(a.name)
+		if !attrNameRegex.MatchString(a.name) {
+			return "", fmt.Errorf("非法的属性名: %q", a.name)
+		}
+		if strings.HasPrefix(name, "on") {
+			return "", fmt.Errorf("不允许通过 attr 输出事件属性: %s", a.name)
+		}
+		if a.value == nil {
+			continue
+		}
+		if slices.Contains(urlAttrs, name) {
+			if _, isBool := a.value.(bool); !isBool {
+				a.value = safeURLValue(fmt.Sprint(a.value))
+			}
+		}
+		out = append(out, a)
+	}
+	var b strings.Builder
+	out.write(&b)
+	return template.HTMLAttr(strings.TrimPrefix(b.String(), " ")), nil
+}
+
+// safeURLValue 不安全协议的地址替换为 #ZgotmplZ
+func safeURLValue(s string) string {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "#ZgotmplZ"
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto", "tel":
+		return s
+	}
+	return "#ZgotmplZ"
+}
+
+// ClassNames 拼接 class 列表：字符串直接加入（可含多个以空格分隔的 class），
+// map 中值非空（判断同 empty）的键按字母顺序加入，字符串切片逐个加入；重复的 class 只保留第一个
+//
+// 模板使用示例:
+// <li class="{{ classNames "nav-item" (map "active" .Active "disabled" .Disabled) }}"> <!-- .Active 为 true 时输出: "nav-item active" -->
+// <button class="{{ classNames "btn" .ExtraClasses (ternary .Primary "btn-primary" "") }}">
+func ClassNames(args ...any) string {
+	var classes []string
+	add := func(s string) {
+		for _, c := range strings.Fields(s) {
+			if !slices.Contains(classes, c) {
+				classes = append(classes, c)
+			}
+		}
+	}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+		case string:
+			add(v)
+		case []string:
+			for _, s := range v {
+				add(s)
+			}
+		case map[string]bool:
+			for _, k := range sortedKeys(v) {
+				if v[k] {
+					add(k)
+				}
+			}
+		case map[string]any:
+			for _, k := range sortedKeys(v) {
+				if !Empty(v[k]) {
+					add(k)
+				}
+			}
+		default:
+			if rv := reflect.ValueOf(arg); rv.Kind() == reflect.Slice {
+				for i := range rv.Len() {
+					add(fmt.Sprint(rv.Index(i).Interface()))
+				}
+			} else if !Empty(arg) {
+				add(fmt.Sprint(arg))
+			}
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+// sortedKeys 返回排序后的键，保证输出稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestAttrHelpers attr 转义属性值并拦截不安全的属性，classNames 按条件拼接 class，coalesce 取第一个非空值
+func TestAttrHelpers(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"attr.html": `<div {{ attr "data-id" .ID "title" .Title "hidden" false "disabled" true "data-x" nil }}></div>` +
+			`<a {{ attr "href" .Link }}>x</a>` +
+			`<li class="{{ classNames "item" (map "active" .Active "disabled" .Disabled) "item" }}"></li>` +
+			`<p>{{ coalesce .Nickname .Name "匿名" }}|{{ .Name | coalesce .Nickname }}</p>`,
+	})
+	out, err := tm.RenderToString("attr", gin.H{
+		"ID": 5, "Title": `Tom & "Jerry"`, "Link": "javascript:alert(1)",
+		"Active": true, "Disabled": false, "Nickname": "", "Name": "张三",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<div data-id="5" title="Tom &amp; &#34;Jerry&#34;" disabled></div>`,
+		`<a href="#ZgotmplZ">x</a>`,
+		`<li class="item active"></li>`,
+		`<p>张三|张三</p>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("输出缺少 %s\n%s", want, out)
+		}
+	}
+}
+
+func TestAttrRejectsUnsafeNames(t *testing.T) {
+	for _, pairs := range [][]any{
+		{"onclick", "alert(1)"},
+		{"ONLOAD", "x"},
+		{`x" onmouseover="y`, "1"},
+		{"data-id"},
+	} {
+		if _, err := Attr(pairs...); err == nil {
+			t.Errorf("Attr(%v) 应返回错误", pairs)
+		}
+	}
+	if got, _ := Attr("href", "/posts?a=1&b=2", "src", "https://cdn.example.com/a.png"); got != `href="/posts?a=1&amp;b=2" src="https://cdn.example.com/a.png"` {
+		t.Errorf("Attr = %s", got)
+	}
+}
+
+func TestClassNames(t *testing.T) {
+	tests := []struct {
+		args []any
+		want string
+	}{
+		{[]any{"btn  btn-lg", nil, ""}, "btn btn-lg"},
+		{[]any{map[string]bool{"b": true, "a": true, "c": false}}, "a b"},
+		{[]any{[]string{"x", "y"}, "x"}, "x y"},
+		{[]any{map[string]any{"on": 1, "off": 0}}, "on"},
+	}
+	for _, tt := range tests {
+		if got := ClassNames(tt.args...); got != tt.want {
+			t.Errorf("ClassNames(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package template
+
+import (
+	"container/list"
+	"html/template"
+	"sync"
+
+	"github.com/gorilla-go/go-framework/pkg/logger"
+)
+
+// LoadStats 模板缓存统计
+type LoadStats struct {
+	Entries    int     `json:"entries"`     // 当前缓存条目数
+	Bytes      int64   `json:"bytes"`       // 当前缓存大小（按模板源文件大小估算）
+	MaxEntries int     `json:"max_entries"` // 条目上限，0 表示不限
+	MaxBytes   int64   `json:"max_bytes"`   // 大小上限，0 表示不限
+	Hits       uint64  `json:"hits"`        // 命中次数
+	Misses     uint64  `json:"misses"`      // 未命中次数（需重新解析）
+	Evictions  uint64  `json:"evictions"`   // 淘汰次数
+	HitRate    float64 `json:"hit_rate"`    // 命中率
+}
+
+// templateCacheEntry 缓存条目
+type templateCacheEntry struct {
+	key  string
+	tmpl *template.Template
+	size int64
+}
+
+// templateCache 已解析模板的 LRU 缓存。
+// 每个 布局+页面 组合都是一个独立条目，按条目数与字节数双重上限淘汰最久未使用的条目。
+type templateCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	ll         *list.List
+	items      map[string]*list.Element
+	bytes      int64
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+}
+
+func newTemplateCache(maxEntries int, maxBytes int64) *templateCache {
+	return &templateCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// get 读取缓存并标记为最近使用
+func (c *templateCache) get(key string) (*template.Template, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		c.hits++
+		return el.Value.(*templateCacheEntry).tmpl, true
+	}
+	c.misses++
+	return nil, false
+}
+
+// add 写入缓存，超出上限时淘汰最久未使用的条目
+func (c *templateCache) add(key string, tmpl *template.Template, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*templateCacheEntry)
+		c.bytes += size - entry.size
+		entry.tmpl, entry.size = tmpl, size
+		c.ll.MoveToFront(el)
+	} else {
+		c.items[key] = c.ll.PushFront(&templateCacheEntry{key: key, tmpl: tmpl, size: size})
+		c.bytes += size
+	}
+
+	for c.ll.Len() > 1 && c.overLimit() {
+		el := c.ll.Back()
+		entry := el.Value.(*templateCacheEntry)
+		c.ll.Remove(el)
+		delete(c.items, entry.key)
+		c.bytes -= entry.size
+		c.evictions++
+		if logger.SugarLogger != nil {
+			logger.Debugf("模板缓存淘汰: %s（%d 字节，剩余 %d 条/%d 字节）", entry.key, entry.size, c.ll.Len(), c.bytes)
+		}
+	}
+}
+
+func (c *templateCache) overLimit() bool {
+	return (c.maxEntries > 0 && c.ll.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes)
+}
+
+// keys 按最近使用顺序返回缓存键
+func (c *templateCache) keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, c.ll.Len())
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(*templateCacheEntry).key)
+	}
+	return keys
+}
+
+// clear 清空缓存（保留统计计数）
+func (c *templateCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+// stats 返回缓存统计
+func (c *templateCache) stats() LoadStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := LoadStats{
+		Entries:    c.ll.Len(),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		s.HitRate = float64(c.hits) / float64(total)
+	}
+	return s
+}
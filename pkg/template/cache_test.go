@@ -0,0 +1,62 @@
+package template
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestTemplateCacheLRU(t *testing.T) {
+	c := newTemplateCache(2, 0)
+	tmpl := template.New("x")
+
+	c.add("a", tmpl, 10)
+	c.add("b", tmpl, 10)
+	c.get("a") // a 变为最近使用
+	c.add("c", tmpl, 10)
+
+	if _, ok := c.get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("a should still be cached")
+	}
+
+	s := c.stats()
+	if s.Entries != 2 || s.Bytes != 20 || s.Evictions != 1 || s.Hits != 2 || s.Misses != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestTemplateCacheMaxBytes(t *testing.T) {
+	c := newTemplateCache(0, 25)
+	tmpl := template.New("x")
+	c.add("a", tmpl, 10)
+	c.add("b", tmpl, 10)
+	c.add("c", tmpl, 10)
+
+	if got := c.keys(); len(got) != 2 || got[0] != "c" || got[1] != "b" {
+		t.Errorf("keys = %v, want [c b]", got)
+	}
+	// 单个条目超过上限时仍保留，避免每次都重新解析
+	c.add("huge", tmpl, 100)
+	if s := c.stats(); s.Entries != 1 || s.Bytes != 100 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestManagerLoadStats(t *testing.T) {
+	tm := newTestManager(t, map[string]string{"a.html": "A", "b.html": "B"})
+	for _, name := range []string{"a", "a", "b"} {
+		if _, err := tm.RenderToString(name, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := tm.GetLoadStats()
+	if s.Entries != 2 || s.Hits != 1 || s.Misses != 2 || s.Bytes != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+	tm.ClearCache()
+	if s := tm.GetLoadStats(); s.Entries != 0 || s.Bytes != 0 {
+		t.Errorf("after clear: %+v", s)
+	}
+}
@@ -0,0 +1,41 @@
+package template
+
+import (
+	stderrors "errors"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// Compile 逐个解析主目录与额外根目录（含主题目录）下的全部模板文件，返回所有语法错误（errors.Join），
+// 用于部署前检查（见 doctor 命令）；只检查语法与函数名，不执行模板
+func (tm *TemplateManager) Compile() error {
+	var errs []error
+	for _, root := range append([]string{tm.templatesDir}, tm.roots...) {
+		if _, err := os.Stat(root); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, "."+tm.extension) {
+				return nil
+			}
+			if _, err := template.New(filepath.Base(path)).Funcs(tm.funcMap).ParseFiles(path); err != nil {
+				rel, _ := filepath.Rel(root, path)
+				errs = append(errs, errors.NewParseError(strings.TrimSuffix(filepath.ToSlash(rel), "."+tm.extension), err))
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,32 @@
+package template
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// ErrorPageDir 错误页模板目录，按状态码命名，如 errors/404
+const ErrorPageDir = "errors"
+
+func init() {
+	middleware.SetErrorPageRenderer(RenderErrorPage)
+}
+
+// RenderErrorPage 按当前主题与默认布局渲染 errors/<状态码> 模板并写出响应，
+// 模板管理器未初始化或模板不存在时返回 false。模板数据含 Status、StatusText、Code、Message、Detail
+func RenderErrorPage(c *gin.Context, status int, data map[string]any) bool {
+	if tmplManager == nil {
+		return false
+	}
+	var buf bytes.Buffer
+	name := ErrorPageDir + "/" + strconv.Itoa(status)
+	if err := tmplManager.RenderTheme(&buf, request.Theme(c), name, WithContext(c, data), tmplManager.defaultLayout); err != nil {
+		return false
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+	return true
+}
@@ -0,0 +1,36 @@
+package template
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRenderErrorPage 渲染 errors/<状态码> 模板，模板不存在时返回 false
+func TestRenderErrorPage(t *testing.T) {
+	prev := tmplManager
+	defer func() { tmplManager = prev }()
+	tmplManager = newTestManager(t, map[string]string{
+		"layouts/main.html": `<main>{{ template "content" . }}</main>`,
+		"errors/404.html":   `{{ define "content" }}{{ .Status }} {{ .Message }}{{ end }}`,
+	})
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if !RenderErrorPage(c, http.StatusNotFound, map[string]any{"Status": 404, "Message": "资源不存在"}) {
+		t.Fatal("应渲染 404 错误页")
+	}
+	if w.Code != http.StatusNotFound || w.Body.String() != "<main>404 资源不存在</main>" {
+		t.Errorf("%d %q", w.Code, w.Body.String())
+	}
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if RenderErrorPage(c, http.StatusForbidden, map[string]any{}) {
+		t.Error("无 403 模板时应返回 false")
+	}
+}
@@ -0,0 +1,365 @@
+package template
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/session"
+)
+
+// 表单字段校验失败时追加的 class 与错误信息容器的 class，默认与 Bootstrap 一致
+var (
+	FormErrorClass    = "is-invalid"
+	FormFeedbackClass = "invalid-feedback"
+)
+
+// Option select 的选项
+type Option struct {
+	Value string
+	Label string
+}
+
+// csrfToken 延迟生成的 CSRF 令牌，由 WithContext 以 .CSRF 注入：
+// 仅在页面实际使用（formOpen 或 {{ .CSRF }}）时才生成并写入会话，其他页面不会因此写出 Set-Cookie
+type csrfToken struct {
+	c *gin.Context
+}
+
+// String 返回当前会话的 CSRF 令牌，可用于 <meta name="csrf-token" content="{{ .CSRF }}">
+func (t csrfToken) String() string {
+	return session.CSRFToken(t.c)
+}
+
+// formData 表单辅助函数从视图数据中读取的请求级数据
+type formData struct {
+	old    url.Values
+	errors map[string]string
+	csrf   fmt.Stringer
+}
+
+// formView 从视图数据中取出 WithContext 注入的 .Old、.Errors 与 .CSRF
+func formView(data any) formData {
+	var m map[string]any
+	switch d := data.(type) {
+	case gin.H:
+		m = d
+	case map[string]any:
+		m = d
+	}
+	var fd formData
+	fd.old, _ = m["Old"].(url.Values)
+	fd.errors, _ = m["Errors"].(map[string]string)
+	switch v := m["CSRF"].(type) {
+	case fmt.Stringer:
+		fd.csrf = v
+	case string:
+		fd.csrf = staticToken(v)
+	}
+	return fd
+}
+
+// staticToken 直接写入视图数据的令牌字符串
+type staticToken string
+
+func (t staticToken) String() string { return string(t) }
+
+// submitted 是否存在上次提交的表单输入（此时未出现在输入中的复选框视为未勾选）
+func (fd formData) submitted() bool {
+	return len(fd.old) > 0
+}
+
+// attr HTML 属性，值为 bool 时 true 输出无值属性（如 required），false 不输出
+type attr struct {
+	name  string
+	value any
+}
+
+// attrs 按键值对解析属性列表
+type attrs []attr
+
+func parseAttrs(pairs []any) (attrs, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("属性须成对传入，实际为 %d 个参数", len(pairs))
+	}
+	list := make(attrs, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		name, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("属性名须为字符串: %v", pairs[i])
+		}
+		list = append(list, attr{name: name, value: pairs[i+1]})
+	}
+	return list, nil
+}
+
+// get 返回属性值
+func (as attrs) get(name string) (any, bool) {
+	for _, a := range as {
+		if a.name == name {
+			return a.value, true
+		}
+	}
+	return nil, false
+}
+
+// str 返回字符串形式的属性值
+func (as attrs) str(name string) string {
+	v, ok := as.get(name)
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// flag 返回布尔属性是否开启，非 bool 值视为开启
+func (as attrs) flag(name string) bool {
+	v, ok := as.get(name)
+	if !ok {
+		return false
+	}
+	b, isBool := v.(bool)
+	return !isBool || b
+}
+
+// without 去掉由辅助函数自行处理的属性
+func (as attrs) without(names ...string) attrs {
+	out := make(attrs, 0, len(as))
+	for _, a := range as {
+		if !slices.Contains(names, a.name) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// invalid 字段有校验错误时追加错误 class 与 aria-invalid
+func (as attrs) invalid(msg string) attrs {
+	if msg == "" {
+		return as
+	}
+	out := make(attrs, 0, len(as)+2)
+	hasClass := false
+	for _, a := range as {
+		if a.name == "class" {
+			a.value = strings.TrimSpace(fmt.Sprint(a.value) + " " + FormErrorClass)
+			hasClass = true
+		}
+		out = append(out, a)
+	}
+	if !hasClass {
+		out = append(out, attr{name: "class", value: FormErrorClass})
+	}
+	return append(out, attr{name: "aria-invalid", value: "true"})
+}
+
+// write 输出属性（值已转义）
+func (as attrs) write(b *strings.Builder) {
+	for _, a := range as {
+		if v, ok := a.value.(bool); ok {
+			if v {
+				b.WriteString(" " + template.HTMLEscapeString(a.name))
+			}
+			continue
+		}
+		fmt.Fprintf(b, ` %s="%s"`, template.HTMLEscapeString(a.name), template.HTMLEscapeString(fmt.Sprint(a.value)))
+	}
+}
+
+// feedback 输出字段的校验错误信息
+func feedback(b *strings.Builder, msg string) {
+	if msg != "" {
+		fmt.Fprintf(b, `<div class="%s">%s</div>`, template.HTMLEscapeString(FormFeedbackClass), template.HTMLEscapeString(msg))
+	}
+}
+
+// hidden 输出隐藏字段
+func hidden(b *strings.Builder, name, value string) {
+	fmt.Fprintf(b, `<input type="hidden" name="%s" value="%s">`, template.HTMLEscapeString(name), template.HTMLEscapeString(value))
+}
+
+// FormOpen 输出 <form> 开始标签。method 默认为 POST，PUT、PATCH、DELETE 以 POST 提交并附带 _method 字段
+// （由 middleware.MethodOverride 还原）；非 GET 表单自动附带 CSRF 令牌字段；multipart 为 true 时可上传文件。
+//
+// 模板使用示例:
+// {{ formOpen . "/posts/1" "method" "PUT" "class" "form" }}
+// <!-- 输出: <form action="/posts/1" method="POST" class="form"><input type="hidden" name="_method" value="PUT"><input type="hidden" name="_csrf" value="..."> -->
+func FormOpen(data any, action string, pairs ...any) (template.HTML, error) {
+	as, err := parseAttrs(pairs)
+	if err != nil {
+		return "", err
+	}
+	method := strings.ToUpper(as.str("method"))
+	if method == "" {
+		method = "POST"
+	}
+	formMethod := method
+	if method != "GET" {
+		formMethod = "POST"
+	}
+
+	var b strings.Builder
+	b.WriteString(`<form`)
+	list := attrs{{name: "action", value: action}, {name: "method", value: formMethod}}
+	if as.flag("multipart") {
+		list = append(list, attr{name: "enctype", value: "multipart/form-data"})
+	}
+	append(list, as.without("method", "multipart")...).write(&b)
+	b.WriteString(`>`)
+
+	if method != "GET" && method != "POST" {
+		hidden(&b, "_method", method)
+	}
+	if fd := formView(data); method != "GET" && fd.csrf != nil {
+		if token := fd.csrf.String(); token != "" {
+			hidden(&b, session.KeyCSRF, token)
+		}
+	}
+	return template.HTML(b.String()), nil
+}
+
+// FormClose 输出 </form>
+func FormClose() template.HTML {
+	return "</form>"
+}
+
+// Input 输出 <input>：type 默认为 text，值优先取上次提交的输入（password 除外），否则取 value 属性；
+// 字段有校验错误时追加 FormErrorClass 并在其后输出错误信息。
+//
+// 模板使用示例:
+// {{ input . "email" "type" "email" "class" "form-control" "value" .User.Email "required" true }}
+// <!-- 校验失败后输出: <input type="email" name="email" value="a@" class="form-control is-invalid" required aria-invalid="true"><div class="invalid-feedback">邮箱格式不正确</div> -->
+func Input(data any, name string, pairs ...any) (template.HTML, error) {
+	as, err := parseAttrs(pairs)
+	if err != nil {
+		return "", err
+	}
+	fd := formView(data)
+	typ := as.str("type")
+	if typ == "" {
+		typ = "text"
+	}
+	value := as.str("value")
+	if _, ok := fd.old[name]; ok && typ != "password" {
+		value = fd.old.Get(name)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<input`)
+	list := attrs{{name: "type", value: typ}, {name: "name", value: name}}
+	if value != "" {
+		list = append(list, attr{name: "value", value: value})
+	}
+	msg := fd.errors[name]
+	append(list, as.without("type", "name", "value")...).invalid(msg).write(&b)
+	b.WriteString(`>`)
+	feedback(&b, msg)
+	return template.HTML(b.String()), nil
+}
+
+// Select 输出 <select>，options 可以是 []string（值与文本相同）、[]Option 或 map[string]string（值 → 文本，按值排序）；
+// 选中项优先取上次提交的输入，否则取 value 属性（multiple 时可为 []string）。
+//
+// 模板使用示例:
+// {{ select . "role" .Roles "class" "form-select" "value" .User.Role }}
+// <!-- 输出: <select name="role" class="form-select"><option value="admin" selected>管理员</option>...</select> -->
+func Select(data any, name string, options any, pairs ...any) (template.HTML, error) {
+	as, err := parseAttrs(pairs)
+	if err != nil {
+		return "", err
+	}
+	opts, err := toOptions(options)
+	if err != nil {
+		return "", err
+	}
+	fd := formView(data)
+	var selected []string
+	if v, ok := fd.old[name]; ok {
+		selected = v
+	} else if v, ok := as.get("value"); ok {
+		switch sv := v.(type) {
+		case []string:
+			selected = sv
+		default:
+			selected = []string{fmt.Sprint(v)}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`<select`)
+	msg := fd.errors[name]
+	append(attrs{{name: "name", value: name}}, as.without("name", "value")...).invalid(msg).write(&b)
+	b.WriteString(`>`)
+	for _, o := range opts {
+		fmt.Fprintf(&b, `<option value="%s"`, template.HTMLEscapeString(o.Value))
+		if slices.Contains(selected, o.Value) {
+			b.WriteString(` selected`)
+		}
+		fmt.Fprintf(&b, `>%s</option>`, template.HTMLEscapeString(o.Label))
+	}
+	b.WriteString(`</select>`)
+	feedback(&b, msg)
+	return template.HTML(b.String()), nil
+}
+
+// toOptions 统一 select 选项的格式
+func toOptions(options any) ([]Option, error) {
+	switch o := options.(type) {
+	case nil:
+		return nil, nil
+	case []Option:
+		return o, nil
+	case []string:
+		opts := make([]Option, len(o))
+		for i, v := range o {
+			opts[i] = Option{Value: v, Label: v}
+		}
+		return opts, nil
+	case map[string]string:
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		opts := make([]Option, len(keys))
+		for i, k := range keys {
+			opts[i] = Option{Value: k, Label: o[k]}
+		}
+		return opts, nil
+	}
+	return nil, fmt.Errorf("不支持的 select 选项类型: %T", options)
+}
+
+// Checkbox 输出复选框，value 默认为 1；存在上次提交的输入时按输入判断是否勾选，否则取 checked 属性。
+//
+// 模板使用示例:
+// <label>{{ checkbox . "remember" "checked" true }} 记住我</label>
+// <!-- 输出: <input type="checkbox" name="remember" value="1" checked> -->
+func Checkbox(data any, name string, pairs ...any) (template.HTML, error) {
+	as, err := parseAttrs(pairs)
+	if err != nil {
+		return "", err
+	}
+	fd := formView(data)
+	value := as.str("value")
+	if value == "" {
+		value = "1"
+	}
+	checked := as.flag("checked")
+	if fd.submitted() {
+		checked = slices.Contains(fd.old[name], value)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<input`)
+	list := attrs{{name: "type", value: "checkbox"}, {name: "name", value: name}, {name: "value", value: value}, {name: "checked", value: checked}}
+	msg := fd.errors[name]
+	append(list, as.without("type", "name", "value", "checked")...).invalid(msg).write(&b)
+	b.WriteString(`>`)
+	feedback(&b, msg)
+	return template.HTML(b.String()), nil
+}
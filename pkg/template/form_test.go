@@ -0,0 +1,70 @@
+package template
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestFormHelpers 表单辅助函数回填旧输入、展示校验错误、伪造请求方法并输出 CSRF 令牌
+func TestFormHelpers(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"form.html": `{{ formOpen . "/users/1" "method" "PUT" "class" "form" }}` +
+			`{{ input . "email" "type" "email" "class" "form-control" "value" "old@example.com" "required" true }}` +
+			`{{ input . "password" "type" "password" }}` +
+			`{{ select . "role" .Roles "value" "user" }}` +
+			`{{ checkbox . "remember" "checked" true }}` +
+			`{{ formClose }}`,
+	})
+	data := gin.H{
+		"Old":    url.Values{"email": {`a@"x`}, "password": {"secret"}, "role": {"admin"}},
+		"Errors": map[string]string{"email": "邮箱格式不正确"},
+		"CSRF":   "tok",
+		"Roles":  map[string]string{"user": "用户", "admin": "管理员"},
+	}
+	out, err := tm.RenderToString("form", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<form action="/users/1" method="POST" class="form"><input type="hidden" name="_method" value="PUT"><input type="hidden" name="_csrf" value="tok">`,
+		`<input type="email" name="email" value="a@&#34;x" class="form-control is-invalid" required aria-invalid="true"><div class="invalid-feedback">邮箱格式不正确</div>`,
+		`<input type="password" name="password">`,
+		`<select name="role"><option value="admin" selected>管理员</option><option value="user">用户</option></select>`,
+		// 已提交的表单中未出现的复选框视为未勾选
+		`<input type="checkbox" name="remember" value="1">`,
+		`</form>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("缺少 %s\n输出: %s", want, out)
+		}
+	}
+}
+
+// TestFormHelpersDefaults 没有旧输入时使用 value/checked 属性，GET 表单不输出 CSRF 令牌
+func TestFormHelpersDefaults(t *testing.T) {
+	data := gin.H{"CSRF": "tok"}
+
+	html, err := FormOpen(data, "/search", "method", "get")
+	if err != nil || string(html) != `<form action="/search" method="GET">` {
+		t.Errorf("GET 表单: %s %v", html, err)
+	}
+	html, _ = FormOpen(data, "/upload", "multipart", true)
+	if string(html) != `<form action="/upload" method="POST" enctype="multipart/form-data"><input type="hidden" name="_csrf" value="tok">` {
+		t.Errorf("上传表单: %s", html)
+	}
+	html, _ = Checkbox(data, "remember", "checked", true)
+	if string(html) != `<input type="checkbox" name="remember" value="1" checked>` {
+		t.Errorf("默认勾选: %s", html)
+	}
+	html, _ = Select(data, "tags", []string{"go", "js"}, "multiple", true, "value", []string{"go", "js"})
+	if string(html) != `<select name="tags" multiple><option value="go" selected>go</option><option value="js" selected>js</option></select>` {
+		t.Errorf("多选: %s", html)
+	}
+	if _, err := Input(data, "name", "class"); err == nil {
+		t.Error("属性未成对传入时应返回错误")
+	}
+}
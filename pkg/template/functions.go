@@ -2,17 +2,26 @@
 package template
 
 import (
-	"encoding/json"
 	"fmt"
 	"html/template"
 	"math"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/captcha"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/gate"
+	"github.com/gorilla-go/go-framework/pkg/image"
+	"github.com/gorilla-go/go-framework/pkg/jsonx"
+	"github.com/gorilla-go/go-framework/pkg/qrcode"
+	"github.com/gorilla-go/go-framework/pkg/request"
 	"github.com/gorilla-go/go-framework/pkg/router"
+	"github.com/gorilla-go/go-framework/pkg/settings"
 )
 
 // 预编译的正则表达式，避免重复编译
@@ -28,9 +37,41 @@ func initRegex() {
 	})
 }
 
-// 最常用的模板函数集合
-// FuncMap 返回可用于HTML模板的函数映射
+// 应用注册的模板函数（见 AddFuncs）
+var (
+	extraFuncs   template.FuncMap
+	extraFuncsMu sync.RWMutex
+)
+
+// AddFuncs 注册应用自定义模板函数，同名时覆盖内置函数（render、include、asset 等绑定到管理器的函数除外）。
+// 只对之后创建的模板管理器生效，应在模板引擎初始化前调用，如服务提供者的 Register 阶段（见 pkg/provider）
+//
+//	template.AddFuncs(map[string]any{"money": billing.Format})
+func AddFuncs(funcs map[string]any) {
+	extraFuncsMu.Lock()
+	defer extraFuncsMu.Unlock()
+	if extraFuncs == nil {
+		extraFuncs = make(template.FuncMap, len(funcs))
+	}
+	for name, fn := range funcs {
+		extraFuncs[name] = fn
+	}
+}
+
+// FuncMap 返回可用于HTML模板的函数映射，含内置函数与 AddFuncs 注册的函数
 func FuncMap() template.FuncMap {
+	funcs := builtinFuncs()
+	extraFuncsMu.RLock()
+	defer extraFuncsMu.RUnlock()
+	for name, fn := range extraFuncs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// 最常用的模板函数集合
+// builtinFuncs 返回内置模板函数
+func builtinFuncs() template.FuncMap {
 	return template.FuncMap{
 		// 字符串处理（最常用）
 		"trim":      strings.TrimSpace,
@@ -79,29 +120,71 @@ func FuncMap() template.FuncMap {
 		"mapSet":  MapSet,
 
 		// 条件处理（最常用）
-		"default": Default,
-		"ternary": Ternary,
-		"eq":      Eq,
-		"ne":      Ne,
-		"lt":      Lt,
-		"lte":     Lte,
-		"gt":      Gt,
-		"gte":     Gte,
+		"default":  Default,
+		"coalesce": Coalesce,
+		"ternary":  Ternary,
+		"eq":       Eq,
+		"ne":       Ne,
+		"lt":       Lt,
+		"lte":      Lte,
+		"gt":       Gt,
+		"gte":      Gte,
 
 		// 安全处理（最常用）
 		"safeHTML": SafeHTML,
 		"safeJS":   SafeJS,
 		"safeCSS":  SafeCSS,
 		"safeURL":  SafeURL,
+		"safeAttr": SafeAttr,
+
+		// HTML 属性与 class 列表（值经转义，事件属性被拒绝）
+		"attr":       Attr,
+		"classNames": ClassNames,
 
 		// URL处理
-		"url": Route, // 简单URL生成函数
+		"url":      Route,    // 简单URL生成函数
+		"imageURL": ImageURL, // 图片缩放、裁剪的签名地址
+
+		// 图形验证码（captcha 包，未启用时输出空内容）
+		"captcha": Captcha,
+
+		// 二维码与条形码（qrcode 包，结果带缓存）
+		"qrcode":    QRCode,
+		"qrcodeURI": QRCodeURI,
+		"barcode":   Barcode,
 
-		// 块处理
+		// 设备判断（参数为 WithContext 注入的 .Device）
+		"isMobile": IsMobile,
+
+		// 对象级授权（gate 包，最后一个参数为视图数据）
+		"can": Can,
+
+		// 运行时配置（settings 包，未启用时返回默认值）
+		"setting": settings.Value,
+
+		// 块处理（NewTemplateManager 会将其重新绑定到所属管理器）
 		"render": func(templatePath, blockName string, data any) template.HTML {
 			return RenderBlock(templatePath, blockName, data)
 		},
 
+		// 局部模板（NewTemplateManager 会将其重新绑定到所属管理器）
+		"include": Include,
+
+		// 菜单与面包屑（NewTemplateManager 会将其重新绑定到所属管理器）
+		"menu": func(name string, data ...any) template.HTML {
+			return getManager().menu(name, data...)
+		},
+		"breadcrumbs": func(data ...any) template.HTML {
+			return getManager().breadcrumbs(data...)
+		},
+
+		// 表单（第一个参数为 WithContext 注入后的视图数据，用于回填旧输入、展示校验错误与输出 CSRF 令牌）
+		"formOpen":  FormOpen,
+		"formClose": FormClose,
+		"input":     Input,
+		"select":    Select,
+		"checkbox":  Checkbox,
+
 		// 错误处理
 		"panic": Panic,
 
@@ -110,6 +193,41 @@ func FuncMap() template.FuncMap {
 	}
 }
 
+// ========== 设备判断函数 ==========
+
+// IsMobile 判断是否为手机或平板，用于按设备切换布局与资源
+//
+// 模板使用示例:
+// {{ if isMobile .Device }}<link rel="stylesheet" href="{{ asset "css/mobile.css" }}">{{ end }}
+// {{ .Device.Browser }} {{ .Device.Version }} / {{ .Device.OS }} <!-- 输出: "Chrome 120.0 / Android" -->
+func IsMobile(ua *request.UserAgent) bool {
+	return ua != nil && ua.IsMobile()
+}
+
+// ========== 授权函数 ==========
+
+// Can 判断当前用户能否对对象执行操作（策略见 pkg/gate）。模板函数在各请求间共享，
+// 需传入 WithContext 注入的视图数据以获取当前请求，range 内使用 $；未传入时返回 false
+//
+// 模板使用示例:
+// {{ if can "update" .Article $ }}<a href="{{ url "article@edit" (map "id" .Article.ID) }}">编辑</a>{{ end }}
+// {{ range .Comments }}{{ if can "delete" . $ }}<button>删除</button>{{ end }}{{ end }}
+func Can(ability string, subject any, data ...any) bool {
+	if len(data) == 0 {
+		return false
+	}
+	var checker gate.Checker
+	switch d := data[0].(type) {
+	case gate.Checker:
+		checker = d
+	case gin.H:
+		checker, _ = d["Gate"].(gate.Checker)
+	case map[string]any:
+		checker, _ = d["Gate"].(gate.Checker)
+	}
+	return checker.Allows(ability, subject)
+}
+
 // ========== 字符串处理函数 ==========
 
 // Substr 返回字符串的子串
@@ -524,7 +642,7 @@ func Round(a any, precision int) float64 {
 // 模板使用示例:
 // {{ now }} <!-- 输出: 当前时间对象 -->
 func Now() time.Time {
-	return time.Now()
+	return clock.Now()
 }
 
 // FormatDateTime 格式化时间
@@ -608,8 +726,7 @@ func DateFormat(t time.Time, format string) string {
 // 模板使用示例:
 // {{ humanizeTime .CreateTime }} <!-- 根据与当前时间的差距输出，如 "3小时前"、"昨天"、"2个月前" -->
 func HumanizeTime(t time.Time) string {
-	now := time.Now()
-	diff := now.Sub(t)
+	diff := clock.Since(t)
 
 	if diff < time.Minute {
 		return "刚刚"
@@ -832,6 +949,21 @@ func Default(value, defaultValue any) any {
 	return value
 }
 
+// Coalesce 返回第一个非空值（空值判断同 empty），全部为空时返回 nil。
+// 管道传入的值作为最后一个参数，即优先级最低的候选
+//
+// 模板使用示例:
+// {{ coalesce .User.Nickname .User.Name "匿名" }} <!-- 昵称为空时输出用户名，都为空时输出 "匿名" -->
+// {{ .Site.Title | coalesce .Page.Title }} <!-- 页面标题为空时使用站点标题 -->
+func Coalesce(values ...any) any {
+	for _, v := range values {
+		if !Empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
 // Ternary 三元运算符
 //
 // 模板使用示例:
@@ -925,6 +1057,14 @@ func SafeURL(s string) template.URL {
 	return template.URL(s)
 }
 
+// SafeAttr 安全HTML属性，内容原样输出；属性值来自用户输入时应使用 attr
+//
+// 模板使用示例:
+// <div {{ safeAttr `data-toggle="modal"` }}> <!-- 输出: <div data-toggle="modal"> -->
+func SafeAttr(s string) template.HTMLAttr {
+	return template.HTMLAttr(s)
+}
+
 // ========== 辅助函数 ==========
 
 // toFloat64 将任意数值类型转换为float64
@@ -1035,6 +1175,88 @@ func Route(name string, params ...map[string]any) template.URL {
 	return template.URL(url)
 }
 
+// ImageURL 生成图片处理服务（image.enabled）的签名地址，参数为成对的 w、h、fit、fm、q；
+// 未启用图片处理时返回源路径
+//
+// 模板使用示例:
+// <img src="{{ imageURL "avatars/1.jpg" "w" 96 "h" 96 "fit" "cover" }}"> <!-- 输出: /img/avatars/1.jpg?fit=cover&h=96&w=96&s=... -->
+func ImageURL(path string, pairs ...any) (template.URL, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("imageURL 参数须成对传入，实际为 %d 个参数", len(pairs))
+	}
+	q := url.Values{}
+	for i := 0; i < len(pairs); i += 2 {
+		q.Set(fmt.Sprint(pairs[i]), fmt.Sprint(pairs[i+1]))
+	}
+	opts, err := image.ParseOptions(q)
+	if err != nil {
+		return "", err
+	}
+	return template.URL(image.URL(path, opts)), nil
+}
+
+// Captcha 输出图形验证码：隐藏的 captcha_id 字段与点击刷新的图片，提交后由 captcha.VerifyRequest 校验；
+// 参数为成对的 img 属性（同 attr）；未启用验证码（captcha.enabled）时输出空内容
+//
+// 模板使用示例:
+// {{ captcha "class" "captcha" }} <!-- 输出: <input type="hidden" name="captcha_id" value="..."><img src="/captcha/....png" alt="验证码" title="看不清？点击刷新" ... class="captcha"> -->
+func Captcha(pairs ...any) (template.HTML, error) {
+	m := captcha.Default()
+	if m == nil {
+		return "", nil
+	}
+	extra, err := Attr(pairs...)
+	if err != nil {
+		return "", err
+	}
+	id := captcha.NewID()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<input type="hidden" name="%s" value="%s">`, captcha.FieldID, id)
+	fmt.Fprintf(&b, `<img src="%s" alt="验证码" title="看不清？点击刷新" style="cursor:pointer" onclick="this.src=this.src.split('?')[0]+'?'+Date.now()"`,
+		template.HTMLEscapeString(m.URL(id)))
+	if extra != "" {
+		b.WriteString(" " + string(extra))
+	}
+	b.WriteString(">")
+	return template.HTML(b.String()), nil
+}
+
+// QRCode 输出内联 SVG 二维码，size 为边长（像素），可选纠错级别 L/M/Q/H（默认 M）
+//
+// 模板使用示例:
+// {{ qrcode .PayURL 200 }} <!-- 输出: <svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" ...> -->
+// {{ qrcode .OTPURL 160 "H" }}
+func QRCode(content string, size int, level ...string) (template.HTML, error) {
+	svg, err := qrcode.SVG(content, size, qrLevel(level)...)
+	return template.HTML(svg), err
+}
+
+// QRCodeURI 输出二维码 SVG 的 data URI，用于 <img src> 或 CSS 背景
+//
+// 模板使用示例:
+// <img src="{{ qrcodeURI .DeepLink 200 }}" alt="扫码打开 App"> <!-- 输出: data:image/svg+xml;base64,... -->
+func QRCodeURI(content string, size int, level ...string) (template.URL, error) {
+	uri, err := qrcode.DataURI(content, size, qrLevel(level)...)
+	return template.URL(uri), err
+}
+
+// qrLevel 模板参数中的纠错级别
+func qrLevel(level []string) []qrcode.Option {
+	if len(level) == 0 {
+		return nil
+	}
+	return []qrcode.Option{qrcode.WithLevel(qrcode.Level(strings.ToUpper(level[0])))}
+}
+
+// Barcode 输出内联 SVG 条形码，format 为 code128、code39 或 ean
+//
+// 模板使用示例:
+// {{ barcode "code128" .Order.No 300 80 }} <!-- 输出: <svg xmlns="http://www.w3.org/2000/svg" width="300" height="80" ...> -->
+func Barcode(format, content string, width, height int) (template.HTML, error) {
+	svg, err := qrcode.BarcodeSVG(qrcode.Format(format), content, width, height)
+	return template.HTML(svg), err
+}
+
 // ========== Map处理函数 ==========
 
 // MapGet 从map中获取指定键的值
@@ -1233,7 +1455,7 @@ func dumpValue(v reflect.Value, indent int) string {
 	case reflect.Struct:
 		// 先尝试使用 JSON 序列化（更可读）
 		if v.CanInterface() {
-			if jsonBytes, err := json.MarshalIndent(v.Interface(), indentStr, "  "); err == nil {
+			if jsonBytes, err := jsonx.MarshalIndent(v.Interface(), indentStr, "  "); err == nil {
 				return string(jsonBytes)
 			}
 		}
@@ -1274,7 +1496,7 @@ func dumpValue(v reflect.Value, indent int) string {
 
 		// 先尝试使用 JSON 序列化
 		if v.CanInterface() {
-			if jsonBytes, err := json.MarshalIndent(v.Interface(), indentStr, "  "); err == nil {
+			if jsonBytes, err := jsonx.MarshalIndent(v.Interface(), indentStr, "  "); err == nil {
 				return string(jsonBytes)
 			}
 		}
@@ -1299,7 +1521,7 @@ func dumpValue(v reflect.Value, indent int) string {
 
 		// 先尝试使用 JSON 序列化
 		if v.CanInterface() {
-			if jsonBytes, err := json.MarshalIndent(v.Interface(), indentStr, "  "); err == nil {
+			if jsonBytes, err := jsonx.MarshalIndent(v.Interface(), indentStr, "  "); err == nil {
 				return string(jsonBytes)
 			}
 		}
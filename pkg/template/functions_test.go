@@ -0,0 +1,134 @@
+package template
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/captcha"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/gate"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// TestHumanizeTime 相对时间以全局时钟为基准
+func TestHumanizeTime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock.SetDefault(clock.NewFake(now))
+	defer clock.SetDefault(nil)
+
+	cases := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{30 * time.Second, "刚刚"},
+		{5 * time.Minute, "5分钟前"},
+		{3 * time.Hour, "3小时前"},
+		{30 * time.Hour, "昨天"},
+		{60 * time.Hour, "前天"},
+		{10 * 24 * time.Hour, "10天前"},
+		{90 * 24 * time.Hour, "3个月前"},
+		{800 * 24 * time.Hour, "2年前"},
+	}
+	for _, tc := range cases {
+		if got := HumanizeTime(now.Add(-tc.ago)); got != tc.want {
+			t.Errorf("HumanizeTime(-%v) = %q, want %q", tc.ago, got, tc.want)
+		}
+	}
+	if !Now().Equal(now) {
+		t.Errorf("Now = %v", Now())
+	}
+}
+
+// TestCan 模板中按视图数据中的请求判断策略
+func TestCan(t *testing.T) {
+	type post struct{ Author string }
+	g := gate.New()
+	gate.DefineOn(g, "update", func(u string, p *post) bool { return p.Author == u })
+	gate.SetDefault(g)
+	defer gate.SetDefault(gate.New())
+
+	tm := newTestManager(t, map[string]string{
+		"posts.html": `{{ range .Posts }}{{ if can "update" . $ }}Y{{ else }}N{{ end }}{{ end }}{{ if can "update" (index .Posts 0) }}!{{ end }}`,
+	})
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	request.Set(c, request.KeyCurrentUser, "tom")
+
+	posts := []*post{{Author: "tom"}, {Author: "amy"}}
+	out, err := tm.RenderToString("posts", WithContext(c, gin.H{"Posts": posts}))
+	if err != nil || out != "YN" {
+		t.Errorf("out = %q, %v（未传入视图数据时应返回 false）", out, err)
+	}
+}
+
+// TestCaptcha 输出隐藏的 ID 字段与图片，未启用时输出空内容
+func TestCaptcha(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"login.html": `{{ captcha "class" "captcha" }}`,
+	})
+	captcha.SetDefault(nil)
+	if out, err := tm.RenderToString("login", nil); err != nil || out != "" {
+		t.Errorf("未启用时 out = %q, %v", out, err)
+	}
+
+	captcha.SetDefault(captcha.New(captcha.NewSessionStore(), captcha.WithPrefix("/verify")))
+	defer captcha.SetDefault(nil)
+	out, err := tm.RenderToString("login", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`^<input type="hidden" name="captcha_id" value="(\w{20})"><img src="/verify/(\w{20})\.png" alt="验证码" .*onclick=".*" class="captcha">$`).FindStringSubmatch(out)
+	if m == nil || m[1] != m[2] {
+		t.Errorf("out = %s", out)
+	}
+	if _, err := Captcha("onload", "x"); err == nil {
+		t.Error("事件属性应被拒绝")
+	}
+}
+
+// TestQRCode 二维码输出内联 SVG 与 data URI，条形码输出内联 SVG
+func TestQRCode(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"pay.html": `{{ qrcode .URL 120 "h" }}|<img src="{{ qrcodeURI .URL 120 }}">|{{ barcode "code128" .No 300 80 }}`,
+		"bad.html": `{{ barcode "upc" .No 300 80 }}`,
+	})
+	out, err := tm.RenderToString("pay", gin.H{"URL": "https://example.com/pay?id=1&t=2", "No": "A-100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(out, "|")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], `<svg xmlns="http://www.w3.org/2000/svg" width="120" height="120"`) ||
+		!strings.HasPrefix(parts[1], `<img src="data:image/svg&#43;xml;base64,`) ||
+		!strings.HasPrefix(parts[2], `<svg xmlns="http://www.w3.org/2000/svg" width="300" height="80"`) {
+		t.Errorf("out = %s", out)
+	}
+	if _, err := tm.RenderToString("bad", gin.H{"No": "1"}); err == nil {
+		t.Error("不支持的条形码格式应渲染失败")
+	}
+}
+
+// TestAddFuncs 注册的函数对之后创建的管理器生效，可覆盖内置函数，不能覆盖绑定到管理器的函数
+func TestAddFuncs(t *testing.T) {
+	t.Cleanup(func() { extraFuncs = nil })
+	AddFuncs(map[string]any{
+		"shout":   func(s string) string { return strings.ToUpper(s) + "!" },
+		"trim":    func(s string) string { return "[" + s + "]" },
+		"include": func(string) string { return "overridden" },
+	})
+	tm := newTestManager(t, map[string]string{
+		"page.html":        `{{ shout .Name }} {{ trim " x " }} {{ include "partials/hi" . }}`,
+		"partials/hi.html": `hi`,
+	})
+	out, err := tm.RenderToString("page", gin.H{"Name": "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "GO! [ x ] hi" {
+		t.Errorf("out = %q", out)
+	}
+}
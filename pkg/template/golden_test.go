@@ -0,0 +1,36 @@
+package template_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/template"
+	"github.com/gorilla-go/go-framework/pkg/testkit/golden"
+)
+
+// fixtures 测试模板目录，包初始化时（TestMain 切换工作目录之前）解析为绝对路径
+var fixtures, _ = filepath.Abs(filepath.Join("testdata", "templates"))
+
+// TestFunctionsGolden 以固定数据渲染覆盖常用模板函数的页面，与 testdata/golden/functions.golden 比较
+func TestFunctionsGolden(t *testing.T) {
+	tm := template.NewTemplateManager(config.TemplateConfig{
+		Path:          fixtures,
+		LayoutDir:     "layouts",
+		Extension:     "html",
+		DefaultLayout: "main",
+	}, false)
+
+	data := map[string]any{
+		"Title":     "模板函数",
+		"Name":      "Gopher",
+		"Intro":     "Hello, template world",
+		"Multiline": "line1\nline2",
+		"Created":   time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		"Tags":      []string{"go", "web", "fx"},
+		"Missing":   "",
+		"Count":     7,
+	}
+	golden.AssertTemplate(t, tm, "functions", data, "main")
+}
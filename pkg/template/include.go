@@ -0,0 +1,73 @@
+package template
+
+import (
+	"fmt"
+	"html/template"
+	"path"
+
+	"github.com/gorilla-go/go-framework/pkg/errors"
+)
+
+// Include 渲染局部模板并返回 HTML：模板按需解析并缓存，只接收传入的数据（不传时为 nil），
+// 不会看到调用处的整个 dot；模板不存在或执行失败时返回错误，使页面渲染失败并指出出错的局部模板。
+// 名称找不到时在局部模板目录（template.partial_dir，默认 partials）下查找。
+//
+// 模板使用示例:
+// {{ range .Posts }}{{ include "partials/card" (map "item" . "compact" true) }}{{ end }}
+// {{ include "card" (map "item" .Post) }} <!-- 找不到 card 模板时使用 partials/card -->
+func (tm *TemplateManager) Include(name string, data ...any) (template.HTML, error) {
+	return tm.include(tm.theme, name, data...)
+}
+
+// includeFunc 返回绑定主题的 include 模板函数，局部模板与页面在同一主题目录链中查找
+func (tm *TemplateManager) includeFunc(theme string) func(name string, data ...any) (template.HTML, error) {
+	return func(name string, data ...any) (template.HTML, error) {
+		return tm.include(theme, name, data...)
+	}
+}
+
+// include 以指定主题渲染局部模板
+func (tm *TemplateManager) include(theme, name string, data ...any) (template.HTML, error) {
+	if len(data) > 1 {
+		return "", fmt.Errorf("include %s: 最多传入一个数据参数，多个值请使用 map 组合", name)
+	}
+	var d any
+	if len(data) == 1 {
+		d = data[0]
+	}
+	if err := errors.ValidateTemplateName(name); err != nil {
+		return "", err
+	}
+	tmpl, err := tm.loadTemplate(theme, tm.partialName(theme, name))
+	if err != nil {
+		return "", fmt.Errorf("include %s: %w", name, err)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	// 直接返回执行错误（含局部模板的文件名与行号），由调用处的页面渲染错误包装
+	if err := tmpl.Execute(buf, d); err != nil {
+		return "", fmt.Errorf("include %s: %w", name, err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// partialName 解析 include 的模板名：名称本身存在时原样使用，否则尝试局部模板目录下的同名模板
+func (tm *TemplateManager) partialName(theme, name string) string {
+	key := theme + "@" + name
+	if resolved, ok := tm.partials.Load(key); ok {
+		return resolved.(string)
+	}
+	resolved := name
+	dirs := tm.searchDirs(theme)
+	if _, ok := findFile(dirs, name+"."+tm.extension); !ok {
+		alt := path.Join(tm.partialDir, name)
+		if _, ok := findFile(dirs, alt+"."+tm.extension); ok {
+			resolved = alt
+		}
+	}
+	if !tm.isDevelopment() {
+		tm.partials.Store(key, resolved)
+	}
+	return resolved
+}
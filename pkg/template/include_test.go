@@ -0,0 +1,58 @@
+package template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestInclude 局部模板只接收传入的数据，输出不被二次转义，短名称回退到 partials 目录
+func TestInclude(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"list.html":            `<ul>{{ range .Posts }}{{ include "partials/card" (map "item" .) }}{{ end }}</ul>{{ include "footer" }}`,
+		"partials/card.html":   `<li>{{ .item }}</li>`,
+		"partials/footer.html": `<footer>{{ if . }}data{{ else }}nil{{ end }}</footer>`,
+		"leak.html":            `{{ include "partials/title" (map "x" 1) }}`,
+		"partials/title.html":  `{{ .Title }}`,
+		"missing.html":         `{{ include "partials/nope" }}`,
+	})
+
+	out, err := tm.RenderToString("list", gin.H{"Posts": []string{"a<b", "c"}, "Title": "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != `<ul><li>a&lt;b</li><li>c</li></ul><footer>nil</footer>` {
+		t.Errorf("out = %q", out)
+	}
+
+	// 未传入的字段不可见（missingkey=error）
+	if _, err := tm.RenderToString("leak", gin.H{"Title": "t"}); err == nil || !strings.Contains(errors.Unwrap(err).Error(), "Title") {
+		t.Errorf("局部模板不应看到调用处的数据: %v", err)
+	}
+	if _, err := tm.RenderToString("missing", nil); err == nil || !strings.Contains(errors.Unwrap(err).Error(), "nope") {
+		t.Errorf("局部模板不存在应返回错误: %v", err)
+	}
+	if _, err := tm.Include("partials/card", 1, 2); err == nil {
+		t.Error("多个数据参数应返回错误")
+	}
+}
+
+// TestIncludeTheme 局部模板与页面在同一主题目录链中查找
+func TestIncludeTheme(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"page.html":                       `{{ include "badge" }}`,
+		"partials/badge.html":             `default`,
+		"themes/dark/partials/badge.html": `dark`,
+	})
+	for theme, want := range map[string]string{"": "default", "dark": "dark"} {
+		var b strings.Builder
+		if err := tm.RenderTheme(&b, theme, "page", nil); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != want {
+			t.Errorf("主题 %q: %q", theme, b.String())
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package template
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// cssCommentRegex CSS 注释
+var cssCommentRegex = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// cssRule 一条可内联的 CSS 规则
+type cssRule struct {
+	selector    []simpleSelector // 后代选择器链，如 "table .cell" → [table .cell]
+	specificity int
+	order       int
+	decls       [][2]string
+}
+
+// simpleSelector 简单复合选择器：tag#id.class1.class2
+type simpleSelector struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// InlineCSS 将 <style> 中的规则内联到元素的 style 属性，用于邮件模板（多数邮件客户端会忽略 <style>）。
+//
+// 支持标签、#id、.class 及其组合和后代选择器；含伪类、属性选择器的规则以及 @media 等 @ 规则
+// 无法内联，保留在 <style> 中。元素原有的 style 属性优先级最高。
+func InlineCSS(document string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		return "", err
+	}
+
+	var styles []*html.Node
+	var elements []*html.Node
+	walkElements(doc, func(n *html.Node) {
+		if n.Data == "style" {
+			styles = append(styles, n)
+		} else {
+			elements = append(elements, n)
+		}
+	})
+
+	var rules []cssRule
+	for _, s := range styles {
+		var css strings.Builder
+		for c := s.FirstChild; c != nil; c = c.NextSibling {
+			css.WriteString(c.Data)
+		}
+		inlinable, residual := parseCSS(css.String(), len(rules))
+		rules = append(rules, inlinable...)
+
+		if strings.TrimSpace(residual) == "" {
+			s.Parent.RemoveChild(s)
+		} else {
+			for c := s.FirstChild; c != nil; c = s.FirstChild {
+				s.RemoveChild(c)
+			}
+			s.AppendChild(&html.Node{Type: html.TextNode, Data: residual})
+		}
+	}
+
+	// 按特异性、源码顺序排序，后应用的覆盖先应用的
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].specificity != rules[j].specificity {
+			return rules[i].specificity < rules[j].specificity
+		}
+		return rules[i].order < rules[j].order
+	})
+
+	for _, n := range elements {
+		var props []string
+		values := map[string]string{}
+		set := func(prop, value string) {
+			if _, ok := values[prop]; !ok {
+				props = append(props, prop)
+			}
+			values[prop] = value
+		}
+		for _, r := range rules {
+			if matchSelector(n, r.selector) {
+				for _, d := range r.decls {
+					set(d[0], d[1])
+				}
+			}
+		}
+		if len(props) == 0 {
+			continue
+		}
+		for _, d := range parseDeclarations(getAttr(n, "style")) {
+			set(d[0], d[1])
+		}
+		var style strings.Builder
+		for i, p := range props {
+			if i > 0 {
+				style.WriteString(" ")
+			}
+			style.WriteString(p + ": " + values[p] + ";")
+		}
+		setAttr(n, "style", style.String())
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// parseCSS 拆分样式表，返回可内联规则与需保留在 <style> 中的剩余内容
+func parseCSS(css string, orderBase int) ([]cssRule, string) {
+	css = cssCommentRegex.ReplaceAllString(css, "")
+	var rules []cssRule
+	var residual strings.Builder
+
+	for len(strings.TrimSpace(css)) > 0 {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+		// 找到与之配对的右括号（@media 等规则内部含嵌套括号）
+		depth, end := 0, -1
+		for i := open; i < len(css); i++ {
+			if css[i] == '{' {
+				depth++
+			} else if css[i] == '}' {
+				depth--
+				if depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			break
+		}
+		prelude := strings.TrimSpace(css[:open])
+		body := css[open+1 : end]
+		block := css[:end+1]
+		css = css[end+1:]
+
+		if strings.HasPrefix(prelude, "@") {
+			residual.WriteString(strings.TrimSpace(block) + "\n")
+			continue
+		}
+		decls := parseDeclarations(body)
+		for _, sel := range strings.Split(prelude, ",") {
+			sel = strings.TrimSpace(sel)
+			chain, spec, ok := parseSelector(sel)
+			if !ok {
+				residual.WriteString(sel + " {" + body + "}\n")
+				continue
+			}
+			rules = append(rules, cssRule{selector: chain, specificity: spec, order: orderBase + len(rules), decls: decls})
+		}
+	}
+	return rules, residual.String()
+}
+
+// parseSelector 解析后代选择器链，遇到不支持的语法返回 false
+func parseSelector(sel string) ([]simpleSelector, int, bool) {
+	if sel == "" || strings.ContainsAny(sel, ":[>+~*") {
+		return nil, 0, false
+	}
+	var chain []simpleSelector
+	spec := 0
+	for _, part := range strings.Fields(sel) {
+		s := simpleSelector{}
+		token := ""
+		kind := byte(0)
+		flush := func() {
+			switch kind {
+			case 0:
+				s.tag = strings.ToLower(token)
+				if token != "" {
+					spec++
+				}
+			case '#':
+				s.id = token
+				spec += 100
+			case '.':
+				s.classes = append(s.classes, token)
+				spec += 10
+			}
+		}
+		for i := 0; i < len(part); i++ {
+			if part[i] == '#' || part[i] == '.' {
+				flush()
+				kind, token = part[i], ""
+				continue
+			}
+			token += string(part[i])
+		}
+		flush()
+		chain = append(chain, s)
+	}
+	return chain, spec, true
+}
+
+// matchSelector 判断元素是否匹配后代选择器链
+func matchSelector(n *html.Node, chain []simpleSelector) bool {
+	if len(chain) == 0 || !matchSimple(n, chain[len(chain)-1]) {
+		return false
+	}
+	rest := chain[:len(chain)-1]
+	for p := n.Parent; p != nil && len(rest) > 0; p = p.Parent {
+		if p.Type == html.ElementNode && matchSimple(p, rest[len(rest)-1]) {
+			rest = rest[:len(rest)-1]
+		}
+	}
+	return len(rest) == 0
+}
+
+func matchSimple(n *html.Node, s simpleSelector) bool {
+	if s.tag != "" && n.Data != s.tag {
+		return false
+	}
+	if s.id != "" && getAttr(n, "id") != s.id {
+		return false
+	}
+	if len(s.classes) > 0 {
+		classes := strings.Fields(getAttr(n, "class"))
+		for _, want := range s.classes {
+			found := false
+			for _, c := range classes {
+				if c == want {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseDeclarations 解析 "color: red; margin: 0" 形式的声明
+func parseDeclarations(s string) [][2]string {
+	var decls [][2]string
+	for _, d := range strings.Split(s, ";") {
+		prop, value, ok := strings.Cut(d, ":")
+		if !ok {
+			continue
+		}
+		prop, value = strings.ToLower(strings.TrimSpace(prop)), strings.TrimSpace(value)
+		if prop != "" && value != "" {
+			decls = append(decls, [2]string{prop, value})
+		}
+	}
+	return decls
+}
+
+func walkElements(n *html.Node, fn func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			fn(c)
+		}
+		walkElements(c, fn)
+	}
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
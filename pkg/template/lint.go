@@ -0,0 +1,212 @@
+package template
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template/parse"
+)
+
+// LintIssue 模板检查发现的问题
+type LintIssue struct {
+	Template string // 模板名（相对根目录，不含扩展名）
+	Location string // 出错位置，如 home.html:12:8，语法错误时为空
+	Message  string
+}
+
+// String 输出 "模板名 位置: 问题" 形式
+func (i LintIssue) String() string {
+	if i.Location == "" {
+		return fmt.Sprintf("%s: %s", i.Template, i.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", i.Template, i.Location, i.Message)
+}
+
+// LintOption 模板检查选项
+type LintOption func(*linter)
+
+// WithLintRoutes 设置已注册的路由名，{{ url "name" }} 引用未注册的路由时报告；不设置时不检查路由
+func WithLintRoutes(names ...string) LintOption {
+	return func(l *linter) {
+		l.routes = make(map[string]bool, len(names))
+		for _, name := range names {
+			l.routes[name] = true
+		}
+	}
+}
+
+// linter 一次检查的状态
+type linter struct {
+	tm     *TemplateManager
+	routes map[string]bool               // 为 nil 时不检查路由名
+	blocks map[string]*template.Template // render 引用的模板文件解析结果，解析失败时为 nil
+	issues []LintIssue
+}
+
+// Lint 检查主目录与额外根目录（含主题目录）下的全部模板：语法错误（含未定义的函数、define/end 不配对），
+// 以及字符串常量引用的路由名（url）、局部模板（include）与模板块（render）是否存在。
+// 只分析模板源码，不执行模板；用于 CI（见 templates:lint 命令），返回的问题按文件顺序排列
+func (tm *TemplateManager) Lint(opts ...LintOption) []LintIssue {
+	l := &linter{tm: tm, blocks: make(map[string]*template.Template)}
+	for _, opt := range opts {
+		opt(l)
+	}
+	for _, root := range append([]string{tm.templatesDir}, tm.roots...) {
+		if _, err := os.Stat(root); err != nil {
+			l.issues = append(l.issues, LintIssue{Template: root, Message: err.Error()})
+			continue
+		}
+		err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(file, "."+tm.extension) {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, file)
+			l.lintFile(file, strings.TrimSuffix(filepath.ToSlash(rel), "."+tm.extension))
+			return nil
+		})
+		if err != nil {
+			l.issues = append(l.issues, LintIssue{Template: root, Message: err.Error()})
+		}
+	}
+	return l.issues
+}
+
+// lintFile 解析单个模板文件并检查其中的引用，主题目录下的文件按所在主题查找 include 的局部模板
+func (l *linter) lintFile(file, name string) {
+	theme := ""
+	if rest, ok := strings.CutPrefix(name, l.tm.themeDir+"/"); ok {
+		theme, _, _ = strings.Cut(rest, "/")
+	}
+	tmpl, err := template.New(filepath.Base(file)).Funcs(l.tm.funcsFor(theme)).ParseFiles(file)
+	if err != nil {
+		l.issues = append(l.issues, LintIssue{Template: name, Message: err.Error()})
+		return
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			l.walk(name, theme, t.Tree, t.Tree.Root)
+		}
+	}
+}
+
+// walk 遍历语法树，检查每个命令
+func (l *linter) walk(name, theme string, tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			l.walk(name, theme, tree, c)
+		}
+	case *parse.ActionNode:
+		l.walk(name, theme, tree, n.Pipe)
+	case *parse.IfNode:
+		l.walkBranch(name, theme, tree, &n.BranchNode)
+	case *parse.RangeNode:
+		l.walkBranch(name, theme, tree, &n.BranchNode)
+	case *parse.WithNode:
+		l.walkBranch(name, theme, tree, &n.BranchNode)
+	case *parse.TemplateNode:
+		l.walk(name, theme, tree, n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			l.checkCommand(name, theme, tree, cmd)
+			for _, arg := range cmd.Args {
+				l.walk(name, theme, tree, arg)
+			}
+		}
+	case *parse.ChainNode:
+		l.walk(name, theme, tree, n.Node)
+	}
+}
+
+// walkBranch 遍历 if / range / with 的条件与分支
+func (l *linter) walkBranch(name, theme string, tree *parse.Tree, n *parse.BranchNode) {
+	l.walk(name, theme, tree, n.Pipe)
+	l.walk(name, theme, tree, n.List)
+	l.walk(name, theme, tree, n.ElseList)
+}
+
+// checkCommand 检查以字符串常量调用 url、include、render 时引用的目标是否存在
+func (l *linter) checkCommand(name, theme string, tree *parse.Tree, cmd *parse.CommandNode) {
+	fn, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return
+	}
+	args := stringArgs(cmd.Args[1:])
+	report := func(format string, a ...any) {
+		location, _ := tree.ErrorContext(cmd)
+		l.issues = append(l.issues, LintIssue{Template: name, Location: location, Message: fmt.Sprintf(format, a...)})
+	}
+	switch fn.Ident {
+	case "url":
+		if l.routes != nil && args[0] != "" && !l.routes[args[0]] {
+			report("路由 %q 未注册", args[0])
+		}
+	case "include":
+		if args[0] != "" && !l.partialExists(theme, args[0]) {
+			report("include 的模板 %q 不存在", args[0])
+		}
+	case "render":
+		if args[0] == "" || args[1] == "" {
+			return
+		}
+		tmpl, found := l.blockSource(args[0])
+		switch {
+		case !found:
+			report("render 的模板 %q 不存在", args[0])
+		case tmpl != nil && tmpl.Lookup(args[1]) == nil:
+			report("模板 %q 中未定义块 %q", args[0], args[1])
+		}
+	}
+}
+
+// stringArgs 返回前两个参数的字符串常量值，非常量参数为空字符串
+func stringArgs(nodes []parse.Node) [2]string {
+	var out [2]string
+	for i := 0; i < len(nodes) && i < len(out); i++ {
+		if s, ok := nodes[i].(*parse.StringNode); ok {
+			out[i] = s.Text
+		}
+	}
+	return out
+}
+
+// partialExists include 的模板是否存在（名称本身或局部模板目录下的同名模板），查找规则同 partialName
+func (l *linter) partialExists(theme, name string) bool {
+	dirs := l.tm.searchDirs(theme)
+	if _, ok := findFile(dirs, name+"."+l.tm.extension); ok {
+		return true
+	}
+	_, ok := findFile(dirs, path.Join(l.tm.partialDir, name)+"."+l.tm.extension)
+	return ok
+}
+
+// blockSource 解析 render 引用的模板文件（按默认主题查找，同 RenderBlock），结果按名称缓存；
+// 文件存在但有语法错误时返回 nil（错误已在检查该文件时报告）
+func (l *linter) blockSource(name string) (*template.Template, bool) {
+	if tmpl, ok := l.blocks[name]; ok {
+		return tmpl, true
+	}
+	file, ok := findFile(l.tm.searchDirs(l.tm.theme), name+"."+l.tm.extension)
+	if !ok {
+		return nil, false
+	}
+	tmpl, err := template.New(filepath.Base(file)).Funcs(l.tm.funcsFor(l.tm.theme)).ParseFiles(file)
+	if err != nil {
+		tmpl = nil
+	}
+	l.blocks[name] = tmpl
+	return tmpl, true
+}
@@ -0,0 +1,57 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLint 报告语法错误与不存在的路由、局部模板、模板块引用
+func TestLint(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"layouts/main.html":  `<main>{{ block "content" . }}{{ end }}</main>`,
+		"partials/card.html": `{{ define "card" }}<div>{{ .item }}</div>{{ end }}`,
+		"widgets.html":       `{{ define "sidebar" }}side{{ end }}`,
+		"index.html": `{{ define "content" }}
+<a href="{{ url "home" }}">首页</a>
+<a href="{{ url "missing@route" }}">失效</a>
+{{ include "card" (map "item" .) }}
+{{ if .Ok }}{{ include "partials/nope" }}{{ end }}
+{{ render "widgets" "sidebar" . }}
+{{ render "widgets" "footer" . }}
+{{ render "nowhere" "x" . }}
+{{ url .Dynamic }}
+{{ end }}`,
+		"broken.html":  `{{ define "content" }}unclosed`,
+		"unknown.html": `{{ nosuchFunc . }}`,
+	})
+
+	var got []string
+	for _, issue := range tm.Lint(WithLintRoutes("home")) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`index index.html:3:`, `路由 "missing@route" 未注册`,
+		`index index.html:5:`, `include 的模板 "partials/nope" 不存在`,
+		`index index.html:7:`, `模板 "widgets" 中未定义块 "footer"`,
+		`index index.html:8:`, `render 的模板 "nowhere" 不存在`,
+	}
+	all := strings.Join(got, "\n")
+	for _, w := range want {
+		if !strings.Contains(all, w) {
+			t.Errorf("缺少问题 %q，实际:\n%s", w, all)
+		}
+	}
+	if !strings.Contains(all, "broken: ") || !strings.Contains(all, "unknown: ") || !strings.Contains(all, "nosuchFunc") {
+		t.Errorf("应报告语法错误与未定义的函数:\n%s", all)
+	}
+	if len(got) != 6 {
+		t.Errorf("问题数 = %d，期望 6:\n%s", len(got), all)
+	}
+
+	// 未设置路由名时不检查 url
+	for _, issue := range tm.Lint() {
+		if strings.Contains(issue.Message, "路由") {
+			t.Errorf("未设置路由时不应检查路由: %s", issue)
+		}
+	}
+}
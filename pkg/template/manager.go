@@ -6,51 +6,103 @@ import (
 	"html/template"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/gorilla-go/go-framework/pkg/config"
 	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/router"
 )
 
 // Manager 模板管理器接口
 type Manager interface {
 	Render(w io.Writer, name string, data any, layout ...string) error
 	RenderWithDefaultLayout(w io.Writer, name string, data any) error
+	RenderToString(name string, data any, layout ...string) (string, error)
+	RenderTheme(w io.Writer, theme, name string, data any, layout ...string) error
+	RenderEmail(name string, data any, layout ...string) (string, error)
 	RenderMultiple(w io.Writer, data any, names ...string) error
 	RenderBlock(templatePath, blockName string, data any) template.HTML
+	Include(name string, data ...any) (template.HTML, error)
 	ClearCache()
 	SetDevelopmentMode(isDev bool)
 	GetTemplateNames() []string
+	GetLoadStats() LoadStats
+}
+
+// RenderRecorder 响应写入器实现该接口时，每次页面渲染完成后收到回调（模板名、是否命中缓存、耗时），
+// 供调试工具栏等统计使用
+type RenderRecorder interface {
+	RecordRender(name string, cached bool, elapsed time.Duration)
 }
 
 // TemplateManager 模板管理器实现
 type TemplateManager struct {
-	templatesDir    string
-	layoutsDir      string
+	templatesDir    string   // 主模板目录
+	roots           []string // 额外模板根目录，按顺序在主目录之后查找（如第三方包自带模板）
+	layoutDir       string   // 布局目录（相对各根目录）
+	partialDir      string   // 局部模板目录（相对各根目录），供 include 按短名称查找
+	themeDir        string   // 主题目录（相对各根目录）
+	theme           string   // 默认主题
+	themeFallbacks  []string // 主题回退链
+	assetPrefix     string   // 静态资源 URL 前缀
 	extension       string
-	templates       map[string]*template.Template
+	cache           *templateCache
 	funcMap         template.FuncMap
 	mutex           sync.RWMutex
 	defaultLayout   string
 	developmentMode bool
+	strict          bool     // 访问不存在的变量时报错，开发模式下始终启用
+	partials        sync.Map // include 短名称的解析结果（主题@名称 → 模板名），开发模式下不缓存
 }
 
 // NewTemplateManager 创建一个新的模板管理器
 func NewTemplateManager(cfg config.TemplateConfig, isDevelopment bool) *TemplateManager {
-	return &TemplateManager{
+	layoutDir := cfg.LayoutDir
+	if layoutDir == "" {
+		layoutDir = "layouts"
+	}
+	partialDir := cfg.PartialDir
+	if partialDir == "" {
+		partialDir = "partials"
+	}
+	themeDir := cfg.ThemeDir
+	if themeDir == "" {
+		themeDir = "themes"
+	}
+	tm := &TemplateManager{
 		templatesDir:    cfg.Path,
-		layoutsDir:      filepath.Join(cfg.Path, cfg.LayoutDir),
+		roots:           cfg.Roots,
+		layoutDir:       layoutDir,
+		partialDir:      partialDir,
+		themeDir:        themeDir,
+		theme:           cfg.Theme,
+		themeFallbacks:  cfg.ThemeFallbacks,
+		assetPrefix:     strings.TrimSuffix(cfg.AssetPrefix, "/"),
 		extension:       cfg.Extension,
-		templates:       make(map[string]*template.Template),
+		cache:           newTemplateCache(cfg.CacheMaxEntries, cfg.CacheMaxBytes),
 		funcMap:         FuncMap(),
 		defaultLayout:   cfg.DefaultLayout,
 		developmentMode: isDevelopment,
+		strict:          cfg.Strict,
 	}
+	// 模板内的 {{ render }} 绑定到当前管理器，而不是经由全局实例，
+	// 保证多个管理器（如邮件模板与页面模板）各自使用自己的目录与缓存
+	tm.funcMap["render"] = tm.RenderBlock
+	tm.funcMap["include"] = tm.includeFunc("")
+	tm.funcMap["asset"] = tm.assetFunc("")
+	tm.funcMap["vite"] = ViteTags
+	tm.funcMap["menu"] = tm.menu
+	tm.funcMap["breadcrumbs"] = tm.breadcrumbs
+	return tm
 }
 
-
 // SetDevelopmentMode 设置开发模式
 func (tm *TemplateManager) SetDevelopmentMode(isDev bool) {
 	tm.mutex.Lock()
@@ -58,47 +110,138 @@ func (tm *TemplateManager) SetDevelopmentMode(isDev bool) {
 	tm.developmentMode = isDev
 }
 
-// GetTemplateNames 获取所有已加载的模板名称
-func (tm *TemplateManager) GetTemplateNames() []string {
+// missingKeyOption 返回模板的 missingkey 选项：严格模式或开发模式下访问不存在的 map 键时执行失败，
+// 由 errors.NewRenderError 转换为指出变量名与模板行号的 MISSING_KEY 错误；否则输出空值
+func (tm *TemplateManager) missingKeyOption() string {
+	if tm.strict || tm.isDevelopment() {
+		return "missingkey=error"
+	}
+	return "missingkey=default"
+}
+
+// isDevelopment 是否为开发模式
+func (tm *TemplateManager) isDevelopment() bool {
 	tm.mutex.RLock()
 	defer tm.mutex.RUnlock()
+	return tm.developmentMode
+}
+
+// GetTemplateNames 获取所有已缓存的模板名称（按最近使用排序）
+func (tm *TemplateManager) GetTemplateNames() []string {
+	return tm.cache.keys()
+}
+
+// GetLoadStats 获取模板缓存统计（条目数、大小、命中/未命中/淘汰次数）
+func (tm *TemplateManager) GetLoadStats() LoadStats {
+	return tm.cache.stats()
+}
+
+// searchDirs 返回模板查找目录链：
+//
+//	<root>/<themeDir>/<theme>  （对每个根目录，依次为当前主题及其回退主题）
+//	<root>                     （主目录，其后为额外根目录）
+//
+// 靠前的目录优先，用于主题覆盖默认模板、应用模板覆盖第三方包模板
+func (tm *TemplateManager) searchDirs(theme string) []string {
+	roots := append([]string{tm.templatesDir}, tm.roots...)
+	var dirs []string
+	seen := map[string]bool{}
+	for _, t := range append([]string{theme}, tm.themeFallbacks...) {
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		for _, root := range roots {
+			dirs = append(dirs, filepath.Join(root, tm.themeDir, t))
+		}
+	}
+	return append(dirs, roots...)
+}
+
+// resolveFile 在查找目录链中定位模板文件，均不存在时返回主目录下的路径（由解析阶段报告文件不存在）
+func (tm *TemplateManager) resolveFile(dirs []string, rel string) string {
+	if path, ok := findFile(dirs, rel); ok {
+		return path
+	}
+	return filepath.Join(tm.templatesDir, rel)
+}
 
-	names := make([]string, 0, len(tm.templates))
-	for name := range tm.templates {
-		names = append(names, name)
+// findFile 返回查找目录链中第一个存在的文件
+func findFile(dirs []string, rel string) (string, bool) {
+	for _, dir := range dirs {
+		path := filepath.Join(dir, rel)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
 	}
-	return names
+	return "", false
+}
+
+// assetFunc 生成模板函数 {{ asset "css/app.css" }}：
+// 无主题时输出 <prefix>/css/app.css，有主题时输出 <prefix>/themes/<theme>/css/app.css
+func (tm *TemplateManager) assetFunc(theme string) func(path string) string {
+	return func(path string) string {
+		path = strings.TrimPrefix(path, "/")
+		if theme == "" {
+			return tm.assetPrefix + "/" + path
+		}
+		return tm.assetPrefix + "/" + tm.themeDir + "/" + theme + "/" + path
+	}
+}
+
+// funcsFor 返回主题对应的函数映射（asset 按主题加前缀）
+func (tm *TemplateManager) funcsFor(theme string) template.FuncMap {
+	if theme == "" {
+		return tm.funcMap
+	}
+	funcs := make(template.FuncMap, len(tm.funcMap))
+	for k, v := range tm.funcMap {
+		funcs[k] = v
+	}
+	funcs["asset"] = tm.assetFunc(theme)
+	funcs["include"] = tm.includeFunc(theme)
+	return funcs
+}
+
+// layoutName 返回布局模板名（相对各根目录）
+func (tm *TemplateManager) layoutName(layout string) string {
+	return filepath.ToSlash(filepath.Join(tm.layoutDir, layout))
+}
+
+// loadTemplate 加载模板（内部方法），theme 为空时只查找根目录
+func (tm *TemplateManager) loadTemplate(theme string, names ...string) (*template.Template, error) {
+	tmpl, _, err := tm.loadTemplateCached(theme, names...)
+	return tmpl, err
 }
 
-// loadTemplate 加载模板（内部方法）
-func (tm *TemplateManager) loadTemplate(names ...string) (*template.Template, error) {
+// loadTemplateCached 加载模板并返回是否命中缓存
+func (tm *TemplateManager) loadTemplateCached(theme string, names ...string) (*template.Template, bool, error) {
 	var tmpl *template.Template
 	var err error
 	var ok bool
 
-	// 生成缓存键，包含所有模板名称
+	// 生成缓存键，包含主题与所有模板名称
 	cacheKey := strings.Join(names, ":")
+	if theme != "" {
+		cacheKey = theme + "@" + cacheKey
+	}
 
 	// 开发模式下不使用缓存，每次都重新加载模板
-	if !tm.developmentMode {
+	if !tm.isDevelopment() {
 		// 尝试从缓存中获取模板
-		tm.mutex.RLock()
-		tmpl, ok = tm.templates[cacheKey]
-		tm.mutex.RUnlock()
-
-		// 如果在缓存中找到，直接返回
-		if ok {
-			return tmpl, nil
+		if tmpl, ok = tm.cache.get(cacheKey); ok {
+			return tmpl, true, nil
 		}
 	}
 
 	// 如果没有指定任何模板，返回错误
 	if len(names) == 0 {
-		return nil, errors.NewTemplateError("VALIDATION_ERROR", "没有指定任何模板文件", "", errors.ErrInvalidTemplateName)
+		return nil, false, errors.NewTemplateError("VALIDATION_ERROR", "没有指定任何模板文件", "", errors.ErrInvalidTemplateName)
 	}
 
 	// 需要加载的所有模板文件路径
 	var allTemplateFiles []string
+	dirs := tm.searchDirs(theme)
 
 	// 处理所有指定的模板
 	for _, name := range names {
@@ -107,55 +250,94 @@ func (tm *TemplateManager) loadTemplate(names ...string) (*template.Template, er
 		}
 		// 验证模板名称
 		if err := errors.ValidateTemplateName(name); err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		allTemplateFiles = append(allTemplateFiles, filepath.Join(tm.templatesDir, name+"."+tm.extension))
+		allTemplateFiles = append(allTemplateFiles, tm.resolveFile(dirs, name+"."+tm.extension))
 	}
 
 	if len(allTemplateFiles) == 0 {
-		return nil, errors.NewTemplateError("VALIDATION_ERROR", "没有找到有效的模板文件", "", errors.ErrInvalidTemplateName)
+		return nil, false, errors.NewTemplateError("VALIDATION_ERROR", "没有找到有效的模板文件", "", errors.ErrInvalidTemplateName)
 	}
 
 	// 确定主模板名称（基础模板）- 使用第一个模板作为基础
 	baseTemplateName := filepath.Base(allTemplateFiles[0])
 
 	// 创建带函数的基础模板
-	tmpl = template.New(baseTemplateName).Funcs(tm.funcMap).Option("missingkey=error")
+	tmpl = template.New(baseTemplateName).Funcs(tm.funcsFor(theme)).Option(tm.missingKeyOption())
 
 	// 解析所有模板文件
 	tmpl, err = tmpl.ParseFiles(allTemplateFiles...)
 	if err != nil {
-		return nil, errors.NewParseError(strings.Join(names, ":"), err)
+		return nil, false, errors.NewParseError(strings.Join(names, ":"), err)
 	}
 
-	// 非开发模式下缓存模板
-	if !tm.developmentMode {
-		tm.mutex.Lock()
-		tm.templates[cacheKey] = tmpl
-		tm.mutex.Unlock()
+	// 非开发模式下缓存模板，以源文件总大小估算占用
+	if !tm.isDevelopment() {
+		var size int64
+		for _, f := range allTemplateFiles {
+			if info, err := os.Stat(f); err == nil {
+				size += info.Size()
+			}
+		}
+		tm.cache.add(cacheKey, tmpl, size)
 	}
 
-	return tmpl, nil
+	return tmpl, false, nil
+}
+
+// maxPooledBufferSize 放回缓冲池的最大容量，超大页面的缓冲区直接丢弃，避免长期占用内存
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool 渲染缓冲区池
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
 }
 
-// executeTemplate 内部方法：使用缓冲区执行模板，避免部分渲染
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// executeTemplate 内部方法：先完整渲染到池化缓冲区，成功后再一次性写出。
+// 模板执行中途出错时响应尚未写出任何内容，错误页可以正常设置状态码，不会出现半截页面 + 200。
 func (tm *TemplateManager) executeTemplate(w io.Writer, tmpl *template.Template, data any, templateName string) error {
-	// 先渲染到缓冲区
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := tmpl.Execute(buf, data); err != nil {
 		return errors.NewRenderError(templateName, err)
 	}
 
-	// 渲染成功后设置 Content-Type
-	tm.ensureContentType(w)
+	// 渲染成功后设置 Content-Type / Content-Length
+	tm.ensureHeaders(w, buf.Len())
 
-	// 将缓冲区内容写入响应
 	_, err := buf.WriteTo(w)
 	return err
 }
 
 // Render 渲染模板，支持可选布局参数
 func (tm *TemplateManager) Render(w io.Writer, name string, data any, layout ...string) error {
+	return tm.render(w, tm.theme, name, data, layout...)
+}
+
+// RenderTheme 使用指定主题渲染模板，theme 为空时使用配置的默认主题。
+// 模板按 themes/<theme>/ → 回退主题 → 根目录 的顺序查找
+func (tm *TemplateManager) RenderTheme(w io.Writer, theme, name string, data any, layout ...string) error {
+	if theme == "" {
+		theme = tm.theme
+	}
+	return tm.render(w, theme, name, data, layout...)
+}
+
+// render 按指定主题渲染模板
+func (tm *TemplateManager) render(w io.Writer, theme, name string, data any, layout ...string) error {
 	// 验证模板名称
 	if err := errors.ValidateTemplateName(name); err != nil {
 		return err
@@ -168,20 +350,47 @@ func (tm *TemplateManager) Render(w io.Writer, name string, data any, layout ...
 		if err := errors.ValidateLayoutName(layout[0]); err != nil {
 			return err
 		}
-		templateNames = append(templateNames, filepath.Join("layouts", layout[0]))
+		templateNames = append(templateNames, tm.layoutName(layout[0]))
 	}
 
 	// 添加内容模板
 	templateNames = append(templateNames, name)
 
 	// 加载并渲染模板
-	tmpl, err := tm.loadTemplate(templateNames...)
+	start := time.Now()
+	tmpl, cached, err := tm.loadTemplateCached(theme, templateNames...)
 	if err != nil {
 		return err
 	}
 
 	// 使用缓冲区执行模板
-	return tm.executeTemplate(w, tmpl, data, name)
+	err = tm.executeTemplate(w, tmpl, data, name)
+	recordRender(w, strings.Join(templateNames, ":"), cached, time.Since(start))
+	return err
+}
+
+// recordRender 沿 Unwrap 链通知所有实现 RenderRecorder 的写入器：
+// 中间件包装的写入器与 gin.ResponseWriter 均可 Unwrap，直至传入 ServeHTTP 的原始写入器（testkit 借此获知渲染的模板）
+func recordRender(w io.Writer, name string, cached bool, elapsed time.Duration) {
+	for w != nil {
+		if r, ok := w.(RenderRecorder); ok {
+			r.RecordRender(name, cached, elapsed)
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = u.Unwrap()
+	}
+}
+
+// LayoutFor 返回当前请求使用的默认布局：路由（或其所在路由组、控制器）通过 router.RouteBuilder.WithLayout
+// 设置了布局时使用该布局，否则使用 template.default_layout
+func (tm *TemplateManager) LayoutFor(c *gin.Context) string {
+	if layout := router.RouteLayout(request.RouteName(c)); layout != "" {
+		return layout
+	}
+	return tm.defaultLayout
 }
 
 // RenderWithDefaultLayout 使用默认布局渲染模板
@@ -189,28 +398,46 @@ func (tm *TemplateManager) RenderWithDefaultLayout(w io.Writer, name string, dat
 	return tm.Render(w, name, data, tm.defaultLayout)
 }
 
-// ensureContentType 确保设置了 Content-Type（仅对 http.ResponseWriter 有效）
-func (tm *TemplateManager) ensureContentType(w io.Writer) {
-	// 尝试将 w 转换为 http.ResponseWriter
-	type headerWriter interface {
-		Header() http.Header
-		WriteHeader(int)
+// RenderToString 渲染模板并以字符串返回，不写入响应，供邮件、通知与测试使用
+func (tm *TemplateManager) RenderToString(name string, data any, layout ...string) (string, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := tm.Render(buf, name, data, layout...); err != nil {
+		return "", err
 	}
+	return buf.String(), nil
+}
 
-	if hw, ok := w.(headerWriter); ok {
-		// 检查是否已设置 Content-Type
-		if hw.Header().Get("Content-Type") == "" {
-			// 设置默认的 HTML Content-Type
-			hw.Header().Set("Content-Type", "text/html; charset=utf-8")
-			// 设置状态码（如果尚未设置）
-			hw.WriteHeader(http.StatusOK)
-		}
+// RenderEmail 渲染邮件模板：在 RenderToString 的基础上将 <style> 规则内联到元素 style 属性
+func (tm *TemplateManager) RenderEmail(name string, data any, layout ...string) (string, error) {
+	out, err := tm.RenderToString(name, data, layout...)
+	if err != nil {
+		return "", err
+	}
+	inlined, err := InlineCSS(out)
+	if err != nil {
+		return "", errors.NewRenderError(name, err)
 	}
+	return inlined, nil
+}
+
+// ensureHeaders 设置 Content-Type（未设置时）与 Content-Length（仅对 http.ResponseWriter 有效）。
+// 状态码由调用方决定（如 c.Status(201)），首次写入时默认 200
+func (tm *TemplateManager) ensureHeaders(w io.Writer, size int) {
+	hw, ok := w.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	header := hw.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/html; charset=utf-8")
+	}
+	header.Set("Content-Length", strconv.Itoa(size))
 }
 
 // RenderMultiple 渲染多个模板
 func (tm *TemplateManager) RenderMultiple(w io.Writer, data any, names ...string) error {
-	tmpl, err := tm.loadTemplate(names...)
+	tmpl, err := tm.loadTemplate(tm.theme, names...)
 	if err != nil {
 		return err
 	}
@@ -229,14 +456,15 @@ func (tm *TemplateManager) RenderBlock(templatePath, blockName string, data any)
 		return tm.renderBlockError(errors.NewTemplateError("VALIDATION_ERROR", "块名称不能为空", templatePath, nil))
 	}
 
-	var buf strings.Builder
-	tmpl, err := tm.loadTemplate(templatePath)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	tmpl, err := tm.loadTemplate(tm.theme, templatePath)
 	if err != nil {
 		return tm.renderBlockError(err)
 	}
 
 	if block := tmpl.Lookup(blockName); block != nil {
-		if err := block.Execute(&buf, data); err != nil {
+		if err := block.Execute(buf, data); err != nil {
 			return tm.renderBlockError(errors.NewRenderError(templatePath, err))
 		}
 		return template.HTML(buf.String())
@@ -246,7 +474,7 @@ func (tm *TemplateManager) RenderBlock(templatePath, blockName string, data any)
 
 // renderBlockError 渲染块错误信息
 func (tm *TemplateManager) renderBlockError(err error) template.HTML {
-	if !tm.developmentMode {
+	if !tm.isDevelopment() {
 		// 生产模式下返回空内容或占位符
 		return template.HTML(`<div class="template-error-placeholder"></div>`)
 	}
@@ -260,7 +488,6 @@ func (tm *TemplateManager) renderBlockError(err error) template.HTML {
 
 // ClearCache 清除模板缓存
 func (tm *TemplateManager) ClearCache() {
-	tm.mutex.Lock()
-	defer tm.mutex.Unlock()
-	tm.templates = make(map[string]*template.Template)
+	tm.cache.clear()
+	tm.partials.Clear()
 }
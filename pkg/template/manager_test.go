@@ -0,0 +1,350 @@
+package template
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/router"
+)
+
+// TestMain 切换到仓库根目录，使 config.MustFetch() 能定位到 config/config.yaml
+// （模板渲染错误解析依赖全局配置中的模板扩展名）。
+func TestMain(m *testing.M) {
+	dir, _ := os.Getwd()
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "config", "config.yaml")); err == nil {
+			_ = os.Chdir(dir)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	os.Exit(m.Run())
+}
+
+// newTestManager 在临时目录中写入模板文件并创建管理器
+func newTestManager(t *testing.T, files map[string]string) *TemplateManager {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewTemplateManager(config.TemplateConfig{
+		Path:          dir,
+		LayoutDir:     "layouts",
+		Extension:     "html",
+		DefaultLayout: "main",
+		Strict:        true,
+	}, false)
+}
+
+func TestRenderToString(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"layouts/main.html": `<main>{{ template "content" . }}</main>`,
+		"hello.html":        `{{ define "content" }}Hello {{ .Name }}{{ end }}`,
+	})
+
+	out, err := tm.RenderToString("hello", map[string]any{"Name": "Go"}, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "<main>Hello Go</main>" {
+		t.Errorf("out = %q", out)
+	}
+
+	if _, err := tm.RenderToString("missing", nil); err == nil {
+		t.Error("expected error for missing template")
+	}
+}
+
+func TestRenderEmailInlinesCSS(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"welcome.html": `<html><head><style>
+p { color: red; margin: 0 }
+.note { color: blue }
+td .cell { padding: 4px }
+a:hover { color: green }
+@media (max-width: 600px) { p { font-size: 12px } }
+</style></head><body>
+<p>Hi {{ .Name }}</p><p class="note" style="margin: 2px">n</p>
+<table><tr><td><span class="cell">c</span></td></tr></table>
+</body></html>`,
+	})
+
+	out, err := tm.RenderEmail("welcome", map[string]any{"Name": "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<p style="color: red; margin: 0;">Hi Go</p>`,
+		`<p class="note" style="color: blue; margin: 2px;">n</p>`,
+		`<span class="cell" style="padding: 4px;">c</span>`,
+		`a:hover { color: green }`,
+		`@media (max-width: 600px)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderBuffersUntilSuccess(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"ok.html":     `<p>{{ .Name }}</p>`,
+		"broken.html": `<p>before</p>{{ .Missing.Field }}`,
+	})
+
+	w := httptest.NewRecorder()
+	if err := tm.Render(w, "ok", map[string]any{"Name": "Go"}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Body.String() != "<p>Go</p>" || w.Header().Get("Content-Length") != "9" {
+		t.Errorf("body=%q Content-Length=%q", w.Body.String(), w.Header().Get("Content-Length"))
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("Content-Type = %q", w.Header().Get("Content-Type"))
+	}
+
+	// 渲染中途失败时不应写出任何内容
+	w = httptest.NewRecorder()
+	if err := tm.Render(w, "broken", map[string]any{}); err == nil {
+		t.Fatal("expected render error")
+	}
+	if w.Body.Len() != 0 || w.Header().Get("Content-Length") != "" {
+		t.Errorf("partial output written: %q", w.Body.String())
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	tm := NewTemplateManager(config.TemplateConfig{Path: b.TempDir(), Extension: "html"}, false)
+	os.WriteFile(filepath.Join(tm.templatesDir, "list.html"), []byte(`{{ range . }}<li>{{ . }}</li>{{ end }}`), 0o644)
+	items := make([]int, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tm.Render(io.Discard, "list", items)
+	}
+}
+
+func TestRenderHelperUsesOwningManager(t *testing.T) {
+	// 全局管理器未初始化，{{ render }} 必须使用所属管理器而不是全局实例
+	tm := newTestManager(t, map[string]string{
+		"page.html":   `<div>{{ render "widget" "box" . }}</div>`,
+		"widget.html": `{{ define "box" }}[{{ .Name }}]{{ end }}`,
+	})
+	out, err := tm.RenderToString("page", map[string]any{"Name": "w"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "<div>[w]</div>" {
+		t.Errorf("out = %q", out)
+	}
+}
+
+func TestLayoutDirAndRoots(t *testing.T) {
+	vendor := t.TempDir()
+	os.MkdirAll(filepath.Join(vendor, "shared"), 0o755)
+	os.WriteFile(filepath.Join(vendor, "shared", "footer.html"), []byte(`vendor-footer`), 0o644)
+	os.WriteFile(filepath.Join(vendor, "page.html"), []byte(`vendor-page`), 0o644)
+
+	tm := newTestManager(t, map[string]string{
+		"views/layouts/base.html": `<body>{{ template "content" . }}</body>`,
+		"views/page.html":         `{{ define "content" }}app-page{{ end }}`,
+	})
+	app := tm.templatesDir
+	tm = NewTemplateManager(config.TemplateConfig{
+		Path:      filepath.Join(app, "views"),
+		LayoutDir: "layouts",
+		Extension: "html",
+		Roots:     []string{vendor},
+	}, false)
+
+	// 配置的布局目录生效
+	out, err := tm.RenderToString("page", nil, "base")
+	if err != nil || out != "<body>app-page</body>" {
+		t.Fatalf("out = %q, err = %v", out, err)
+	}
+	// 主目录缺少的模板回退到额外根目录
+	out, err = tm.RenderToString("shared/footer", nil)
+	if err != nil || out != "vendor-footer" {
+		t.Errorf("fallback out = %q, err = %v", out, err)
+	}
+}
+
+func TestThemeFallbackChain(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"themes/dark/title.html":  `dark-title`,
+		"themes/base/title.html":  `base-title`,
+		"themes/base/footer.html": `base-footer`,
+		"title.html":              `default-title`,
+		"footer.html":             `default-footer`,
+		"body.html":               `default-body`,
+	})
+	tm.theme, tm.themeFallbacks = "dark", []string{"base"}
+
+	for name, want := range map[string]string{
+		"title":  "dark-title",
+		"footer": "base-footer",
+		"body":   "default-body",
+	} {
+		out, err := tm.RenderToString(name, nil)
+		if err != nil || out != want {
+			t.Errorf("%s: out = %q, err = %v; want %q", name, out, err, want)
+		}
+	}
+}
+
+func TestRenderThemeAndAssets(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"themes/brand/page.html": `brand {{ asset "css/app.css" }}`,
+		"page.html":              `default {{ asset "/css/app.css" }}`,
+	})
+	tm.assetPrefix = "/static"
+
+	var buf strings.Builder
+	if err := tm.RenderTheme(&buf, "brand", "page", nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "brand /static/themes/brand/css/app.css" {
+		t.Errorf("themed = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := tm.RenderTheme(&buf, "", "page", nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "default /static/css/app.css" {
+		t.Errorf("default = %q", buf.String())
+	}
+
+	// 同名模板按主题分别缓存
+	if n := tm.GetLoadStats().Entries; n != 2 {
+		t.Errorf("cache entries = %d, want 2", n)
+	}
+}
+
+// TestLayoutFor 按当前路由的布局渲染，未设置时使用默认布局
+func TestLayoutFor(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"layouts/main.html":  `<main>{{ template "content" . }}</main>`,
+		"layouts/admin.html": `<admin>{{ template "content" . }}</admin>`,
+		"page.html":          `{{ define "content" }}page{{ end }}`,
+	})
+	gin.SetMode(gin.TestMode)
+	rb := router.NewRouteBuilder(gin.New())
+	rb.WithLayout("admin").GET("/admin/page", func(c *gin.Context) error { return nil }, "test.layoutfor.admin")
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if got := tm.LayoutFor(c); got != "main" {
+		t.Errorf("未匹配路由时应使用默认布局，得到 %q", got)
+	}
+	request.Set(c, request.KeyRouteName, "test.layoutfor.admin")
+	out, err := tm.RenderToString("page", nil, tm.LayoutFor(c))
+	if err != nil || out != "<admin>page</admin>" {
+		t.Errorf("应使用路由布局: %q, %v", out, err)
+	}
+}
+
+// TestWithContextSharedData 合并中间件共享的视图数据，处理器传入的同名键优先
+func TestWithContextSharedData(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"page.html": `{{ .Site }}|{{ .Title }}|{{ .Locale }}`,
+	})
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	request.Set(c, request.KeyLocale, "zh-CN")
+	request.Share(c, "Site", "demo")
+	request.Share(c, "Title", "共享标题")
+
+	out, err := tm.RenderToString("page", WithContext(c, gin.H{"Title": "首页"}))
+	if err != nil || out != "demo|首页|zh-CN" {
+		t.Errorf("out = %q, %v", out, err)
+	}
+}
+
+// TestCompile 检查全部模板语法，汇总每个出错的模板
+func TestCompile(t *testing.T) {
+	tm := newTestManager(t, map[string]string{
+		"index.html":         `{{ define "content" }}{{ .Title | upper }}{{ end }}`,
+		"layouts/main.html":  `<main>{{ block "content" . }}{{ end }}</main>`,
+		"broken.html":        `{{ if .Title }}unclosed`,
+		"themes/dark/x.html": `{{ undefinedFunc . }}`,
+	})
+	err := tm.Compile()
+	if err == nil {
+		t.Fatal("期望返回语法错误")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "broken") || !strings.Contains(msg, "themes/dark/x") {
+		t.Errorf("应报告全部出错模板: %v", err)
+	}
+	if strings.Contains(msg, "index") || strings.Contains(msg, "layouts/main") {
+		t.Errorf("正确的模板不应报错: %v", err)
+	}
+
+	ok := newTestManager(t, map[string]string{"index.html": `{{ .Title }}`})
+	if err := ok.Compile(); err != nil {
+		t.Errorf("无错误时应返回 nil: %v", err)
+	}
+}
+
+// TestStrictMissingKey 严格模式下访问不存在的变量返回指出变量与行号的 MISSING_KEY 错误
+func TestStrictMissingKey(t *testing.T) {
+	files := map[string]string{
+		"layouts/main.html":  `<main>{{ template "content" . }}</main>`,
+		"hello.html":         "{{ define \"content\" }}\nHello {{ .Nmae }}{{ end }}",
+		"field.html":         `{{ define "content" }}{{ .User.Nmae }}{{ end }}`,
+		"card.html":          `{{ define "content" }}{{ include "partials/card" (map "item" .) }}{{ end }}`,
+		"partials/card.html": "<div>\n{{ .titel }}</div>",
+	}
+	tm := newTestManager(t, files)
+
+	_, err := tm.RenderToString("hello", map[string]any{"Name": "Go"}, "main")
+	if !errors.IsTemplateMissingKeyError(err) {
+		t.Fatalf("期望 MISSING_KEY 错误，实际: %v", err)
+	}
+	te := err.(*errors.TemplateError)
+	if !strings.Contains(te.Message, ".Nmae") || te.LineNumber != 2 || !strings.HasSuffix(te.FileName, "hello.html") {
+		t.Errorf("错误应指出变量与位置: %+v", te)
+	}
+
+	_, err = tm.RenderToString("field", map[string]any{"User": struct{ Name string }{"Go"}}, "main")
+	if !errors.IsTemplateMissingKeyError(err) || !strings.Contains(err.Error(), ".User.Nmae") {
+		t.Errorf("不存在的字段应为 MISSING_KEY 错误: %v", err)
+	}
+
+	// 局部模板中的错误指向局部模板
+	_, err = tm.RenderToString("card", map[string]any{}, "main")
+	if te, ok := err.(*errors.TemplateError); !ok || te.Type != "MISSING_KEY" || te.LineNumber != 2 || !strings.Contains(te.Message, ".titel") {
+		t.Errorf("应指出局部模板中的变量与行号: %v", err)
+	}
+
+	// 非严格模式输出空值，开发模式下始终严格
+	lenient := NewTemplateManager(config.TemplateConfig{Path: tm.templatesDir, LayoutDir: "layouts", Extension: "html"}, false)
+	out, err := lenient.RenderToString("hello", map[string]any{}, "main")
+	if err != nil || out != "<main>\nHello </main>" {
+		t.Errorf("非严格模式应输出空值: %q, %v", out, err)
+	}
+	lenient.SetDevelopmentMode(true)
+	if _, err := lenient.RenderToString("hello", map[string]any{}, "main"); !errors.IsTemplateMissingKeyError(err) {
+		t.Errorf("开发模式下应严格检查: %v", err)
+	}
+}
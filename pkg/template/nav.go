@@ -0,0 +1,92 @@
+package template
+
+import (
+	"html/template"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/nav"
+)
+
+// 菜单与面包屑的分部模板，按 nav/menu-<菜单名> → nav/menu → 内置标记 的顺序查找
+const (
+	MenuPartial        = "nav/menu"
+	BreadcrumbsPartial = "nav/breadcrumbs"
+)
+
+// defaultNavTemplates 未提供分部模板时使用的内置标记
+var defaultNavTemplates = template.Must(template.New("nav").Parse(`
+{{- define "nav/items" -}}
+<ul>{{ range . }}<li{{ if .Active }} class="active"{{ end }}><a href="{{ .URL }}"{{ if .Active }} aria-current="page"{{ end }}>{{ .Label }}</a>{{ if .Children }}{{ template "nav/items" .Children }}{{ end }}</li>{{ end }}</ul>
+{{- end -}}
+{{- define "nav/menu" -}}
+<nav class="menu menu-{{ .Name }}">{{ template "nav/items" .Items }}</nav>
+{{- end -}}
+{{- define "nav/breadcrumbs" -}}
+<nav aria-label="breadcrumb"><ol class="breadcrumb">{{ range .Items }}<li class="breadcrumb-item{{ if .Active }} active{{ end }}">{{ if or .Active (not .URL) }}<span{{ if .Active }} aria-current="page"{{ end }}>{{ .Label }}</span>{{ else }}<a href="{{ .URL }}">{{ .Label }}</a>{{ end }}</li>{{ end }}</ol></nav>
+{{- end -}}`))
+
+// navView 从视图数据中取出 WithContext 注入的 .Nav，data 也可以直接是 *nav.View
+func navView(data []any) *nav.View {
+	if len(data) == 0 {
+		return nil
+	}
+	switch d := data[0].(type) {
+	case *nav.View:
+		return d
+	case gin.H:
+		v, _ := d["Nav"].(*nav.View)
+		return v
+	case map[string]any:
+		v, _ := d["Nav"].(*nav.View)
+		return v
+	}
+	return nil
+}
+
+// menu 渲染命名菜单。模板函数在各请求间共享，需传入视图数据以获取当前请求：
+//
+//	{{ menu "main" . }}
+func (tm *TemplateManager) menu(name string, data ...any) template.HTML {
+	items := navView(data).Menu(name)
+	if len(items) == 0 {
+		return ""
+	}
+	return tm.renderPartial(map[string]any{"Name": name, "Items": items}, MenuPartial+"-"+name, MenuPartial)
+}
+
+// breadcrumbs 渲染当前请求的面包屑：{{ breadcrumbs . }}
+func (tm *TemplateManager) breadcrumbs(data ...any) template.HTML {
+	items := navView(data).Breadcrumbs()
+	if len(items) == 0 {
+		return ""
+	}
+	return tm.renderPartial(map[string]any{"Items": items}, BreadcrumbsPartial)
+}
+
+// renderPartial 依次查找模板目录中的分部模板，均不存在时使用内置标记（以最后一个名称查找）
+func (tm *TemplateManager) renderPartial(data any, names ...string) template.HTML {
+	dirs := tm.searchDirs(tm.theme)
+	for _, name := range names {
+		if _, err := os.Stat(tm.resolveFile(dirs, name+"."+tm.extension)); err != nil {
+			continue
+		}
+		tmpl, err := tm.loadTemplate(tm.theme, name)
+		if err != nil {
+			return tm.renderBlockError(err)
+		}
+		buf := getBuffer()
+		defer putBuffer(buf)
+		if err := tmpl.Execute(buf, data); err != nil {
+			return tm.renderBlockError(err)
+		}
+		return template.HTML(buf.String())
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := defaultNavTemplates.ExecuteTemplate(buf, names[len(names)-1], data); err != nil {
+		return tm.renderBlockError(err)
+	}
+	return template.HTML(buf.String())
+}
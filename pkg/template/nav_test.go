@@ -0,0 +1,39 @@
+package template
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/nav"
+)
+
+// TestMenuPartials 菜单优先使用 nav/menu-<名称> 分部模板，面包屑无模板时使用内置标记
+func TestMenuPartials(t *testing.T) {
+	nav.Define("tmpl-side", nav.Item{Label: "设置", URL: "/settings"})
+	nav.Define("tmpl-top", nav.Item{Label: "首页", URL: "/"}, nav.Item{Label: "文档", URL: "/docs"})
+	tm := newTestManager(t, map[string]string{
+		"nav/menu-tmpl-side.html": `<aside>{{ range .Items }}[{{ .Label }}{{ if .Active }}*{{ end }}]{{ end }}</aside>`,
+		"page.html":               `{{ menu "tmpl-side" . }}|{{ menu "tmpl-top" . }}|{{ breadcrumbs . }}`,
+	})
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/docs", nil)
+	out, err := tm.RenderToString("page", WithContext(c, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(out, "|")
+	if parts[0] != "<aside>[设置]</aside>" {
+		t.Errorf("自定义分部模板: %s", parts[0])
+	}
+	if !strings.Contains(parts[1], `<nav class="menu menu-tmpl-top">`) || !strings.Contains(parts[1], `<li class="active"><a href="/docs" aria-current="page">文档</a>`) {
+		t.Errorf("内置菜单标记: %s", parts[1])
+	}
+	if !strings.Contains(parts[2], `<span aria-current="page">文档</span>`) {
+		t.Errorf("内置面包屑标记: %s", parts[2])
+	}
+}
@@ -5,9 +5,18 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/auth"
 	"github.com/gorilla-go/go-framework/pkg/config"
 	"github.com/gorilla-go/go-framework/pkg/errors"
+	"github.com/gorilla-go/go-framework/pkg/gate"
+	"github.com/gorilla-go/go-framework/pkg/geoip"
 	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"github.com/gorilla-go/go-framework/pkg/nav"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"github.com/gorilla-go/go-framework/pkg/session"
+	"github.com/gorilla-go/go-framework/pkg/tenant"
 	"go.uber.org/zap"
 )
 
@@ -20,6 +29,15 @@ func InitTemplateManager(cfg config.TemplateConfig, isDevelopment bool) Manager
 	return tmplManager
 }
 
+// GetManager 获取全局模板管理器，未初始化时返回 nil。
+// 包级 Render/RenderL/RenderBlock 等函数均是对它的薄封装，新代码可直接依赖 Manager 接口
+func GetManager() Manager {
+	if tmplManager == nil {
+		return nil
+	}
+	return tmplManager
+}
+
 // getManager 获取全局管理器实例
 func getManager() *TemplateManager {
 	if tmplManager == nil {
@@ -40,23 +58,117 @@ func getManager() *TemplateManager {
 func Render(w http.ResponseWriter, name string, data any, layout ...string) {
 	err := getManager().Render(w, name, data, layout...)
 	if err != nil {
-		handleHTTPError(w, err)
+		handleHTTPError(w, err, nil)
 	}
 }
 
-// RenderL 使用默认布局渲染模板（推荐在 Controller 中使用）
+// RenderL 使用 template.default_layout 渲染模板；需要按路由组 / 控制器选择布局时使用 RenderThemeL
 func RenderL(w http.ResponseWriter, name string, data any) {
 	err := getManager().RenderWithDefaultLayout(w, name, data)
 	if err != nil {
-		handleHTTPError(w, err)
+		handleHTTPError(w, err, nil)
 	}
 }
 
+// RenderTheme 按当前请求主题渲染模板（主题由 middleware.Theme 解析，未设置时使用配置的默认主题）
+func RenderTheme(c *gin.Context, name string, data any, layout ...string) {
+	err := getManager().RenderTheme(c.Writer, request.Theme(c), name, WithContext(c, data), layout...)
+	if err != nil {
+		handleHTTPError(c.Writer, err, middleware.ErrorContext(c))
+	}
+}
+
+// RenderThemeL 按当前请求主题、使用当前路由的默认布局渲染模板（见 LayoutFor）
+func RenderThemeL(c *gin.Context, name string, data any) {
+	RenderTheme(c, name, data, getManager().LayoutFor(c))
+}
+
+// RenderToString 渲染模板并以字符串返回，出错时返回错误而不是写出错误页
+//
+//	body, err := template.RenderToString("mail/welcome", data, "mail")
+func RenderToString(name string, data any, layout ...string) (string, error) {
+	return getManager().RenderToString(name, data, layout...)
+}
+
+// RenderEmail 渲染邮件模板并内联 CSS，适用于邮件/通知正文
+func RenderEmail(name string, data any, layout ...string) (string, error) {
+	return getManager().RenderEmail(name, data, layout...)
+}
+
 // RenderBlock 动态加载指定模板文件中的特定块并渲染
 func RenderBlock(templatePath, blockName string, data any) template.HTML {
 	return getManager().RenderBlock(templatePath, blockName, data)
 }
 
+// Include 渲染局部模板，只向其传入 data（见 TemplateManager.Include）
+func Include(name string, data ...any) (template.HTML, error) {
+	return getManager().Include(name, data...)
+}
+
+// WithContext 向视图数据注入请求级公共数据，当前包括 CurrentUser（由 middleware.LoadUser 解析）、
+// Theme（由 middleware.Theme 解析）、Tenant（由 middleware.Tenant 解析）、Geo（由 middleware.GeoIP 解析）、
+// Device（request.ParseUserAgent 的解析结果）、Nav（当前请求的菜单与面包屑，供 menu/breadcrumbs 函数使用）
+// Old（response.WithInput 闪存的上次表单输入）、Errors（response.WithErrors 闪存的字段校验错误）
+// 与 CSRF（当前会话的 CSRF 令牌，首次输出时生成），后三项供 formOpen/input/select/checkbox 函数使用；
+// Locale（middleware.Locale 解析的当前语言）、Gate（绑定当前请求的策略判断，供 can 函数使用），以及中间件通过 request.Share / middleware.ViewData 共享的数据。
+// 以上键名为保留键（request.ReservedViewKeys），中间件不能共享同名数据。
+// 仅对 map 类型数据生效且不覆盖已存在的键，其他类型原样返回。
+//
+//	template.RenderL(c.Writer, "index", template.WithContext(c, gin.H{"Title": "首页"}))
+func WithContext(c *gin.Context, data any) any {
+	var m map[string]any
+	switch d := data.(type) {
+	case gin.H:
+		m = d
+	case map[string]any:
+		m = d
+	case nil:
+		m = map[string]any{}
+	default:
+		return data
+	}
+
+	if _, exists := m["CurrentUser"]; !exists {
+		m["CurrentUser"] = auth.User(c)
+	}
+	if _, exists := m["Theme"]; !exists {
+		m["Theme"] = request.Theme(c)
+	}
+	if _, exists := m["Tenant"]; !exists {
+		m["Tenant"] = tenant.Current(c)
+	}
+	if _, exists := m["Geo"]; !exists {
+		m["Geo"] = geoip.Current(c)
+	}
+	if _, exists := m["Device"]; !exists {
+		m["Device"] = request.ParseUserAgent(c)
+	}
+	if _, exists := m["Nav"]; !exists {
+		m["Nav"] = nav.For(c)
+	}
+	if _, exists := m["Old"]; !exists {
+		m["Old"] = session.OldInput(c)
+	}
+	if _, exists := m["Errors"]; !exists {
+		m["Errors"] = session.Errors(c)
+	}
+	if _, exists := m["CSRF"]; !exists {
+		m["CSRF"] = csrfToken{c: c}
+	}
+	if _, exists := m["Locale"]; !exists {
+		m["Locale"] = request.Locale(c)
+	}
+	if _, exists := m["Gate"]; !exists {
+		m["Gate"] = gate.For(c)
+	}
+	for k, v := range request.Shared(c) {
+		if _, exists := m[k]; !exists {
+			m[k] = v
+		}
+	}
+	return m
+}
+
 // ==================== 工具函数 ====================
 
 // ClearCache 清除模板缓存
@@ -64,13 +176,19 @@ func ClearCache() {
 	getManager().ClearCache()
 }
 
+// GetLoadStats 获取模板缓存统计
+func GetLoadStats() LoadStats {
+	return getManager().GetLoadStats()
+}
+
 // ==================== HTTP 错误处理（内部函数）====================
 
-func handleHTTPError(w http.ResponseWriter, err error) {
+// handleHTTPError 输出模板错误页，rc 为开发错误页展示的请求上下文（无 gin.Context 时为 nil）
+func handleHTTPError(w http.ResponseWriter, err error, rc *errors.RequestContext) {
 	tm := getManager()
-	isDev := tm.developmentMode
+	isDev := tm.isDevelopment()
 	if !isDev {
 		logger.Error("模板渲染错误", zap.Error(err))
 	}
-	errors.RenderError(w, err, string(debug.Stack()), isDev)
+	errors.RenderErrorWithContext(w, err, string(debug.Stack()), isDev, rc)
 }
@@ -0,0 +1,210 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// ViteProxyPrefix 开发服务器同源代理前缀，启用 vite.proxy 时 Vite 的 base 需配置为 "/__vite/"
+const ViteProxyPrefix = "/__vite"
+
+// viteChunk manifest.json 中的条目
+type viteChunk struct {
+	File    string   `json:"file"`
+	Src     string   `json:"src"`
+	IsEntry bool     `json:"isEntry"`
+	CSS     []string `json:"css"`
+	Imports []string `json:"imports"`
+}
+
+// Vite Vite 资源集成：开发模式输出开发服务器脚本（含 HMR 客户端），生产模式读取 manifest 输出带哈希的构建产物
+type Vite struct {
+	dev       bool
+	devServer string
+	proxy     bool
+	manifest  string
+	base      string
+
+	mu       sync.Mutex
+	chunks   map[string]viteChunk
+	loadedAt time.Time
+}
+
+var (
+	viteInstance *Vite
+	viteMu       sync.RWMutex
+)
+
+// NewVite 创建 Vite 集成，isDevelopment 且配置了 dev_server 时使用开发服务器
+func NewVite(cfg config.ViteConfig, isDevelopment bool) *Vite {
+	base := cfg.Base
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return &Vite{
+		dev:       isDevelopment && cfg.DevServer != "",
+		devServer: strings.TrimSuffix(cfg.DevServer, "/"),
+		proxy:     cfg.Proxy,
+		manifest:  cfg.Manifest,
+		base:      base,
+	}
+}
+
+// InitVite 初始化全局 Vite 集成，供模板函数 {{ vite }} 使用
+func InitVite(cfg config.ViteConfig, isDevelopment bool) *Vite {
+	v := NewVite(cfg, isDevelopment)
+	viteMu.Lock()
+	viteInstance = v
+	viteMu.Unlock()
+	return v
+}
+
+// GetVite 获取全局 Vite 集成，未初始化时返回 nil
+func GetVite() *Vite {
+	viteMu.RLock()
+	defer viteMu.RUnlock()
+	return viteInstance
+}
+
+// ViteTags 模板函数 {{ vite "src/main.ts" }} 的实现
+func ViteTags(entries ...string) (template.HTML, error) {
+	v := GetVite()
+	if v == nil {
+		return "", fmt.Errorf("vite 未初始化")
+	}
+	return v.Tags(entries...)
+}
+
+// IsDev 是否使用开发服务器
+func (v *Vite) IsDev() bool {
+	return v.dev
+}
+
+// ProxyEnabled 是否需要注册开发服务器代理路由
+func (v *Vite) ProxyEnabled() bool {
+	return v.dev && v.proxy
+}
+
+// Tags 生成入口对应的 <script>/<link> 标签
+func (v *Vite) Tags(entries ...string) (template.HTML, error) {
+	if v.dev {
+		return v.devTags(entries), nil
+	}
+	return v.buildTags(entries)
+}
+
+// devTags 开发模式：HMR 客户端 + 入口源码，由开发服务器实时编译
+func (v *Vite) devTags(entries []string) template.HTML {
+	origin := v.devServer
+	if v.proxy {
+		origin = ViteProxyPrefix
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<script type="module" src="%s/@vite/client"></script>`, origin)
+	for _, entry := range entries {
+		src := origin + "/" + strings.TrimPrefix(entry, "/")
+		if strings.HasSuffix(entry, ".css") {
+			fmt.Fprintf(&b, `<link rel="stylesheet" href="%s">`, template.HTMLEscapeString(src))
+		} else {
+			fmt.Fprintf(&b, `<script type="module" src="%s"></script>`, template.HTMLEscapeString(src))
+		}
+	}
+	return template.HTML(b.String())
+}
+
+// buildTags 生产模式：按 manifest 输出入口文件、依赖预加载与样式
+func (v *Vite) buildTags(entries []string) (template.HTML, error) {
+	chunks, err := v.loadManifest()
+	if err != nil {
+		return "", err
+	}
+
+	var styles, preloads, scripts strings.Builder
+	seen := map[string]bool{}
+	var walk func(key string, entry bool) error
+	walk = func(key string, entry bool) error {
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		chunk, ok := chunks[key]
+		if !ok {
+			return fmt.Errorf("vite manifest 中不存在入口: %s", key)
+		}
+		for _, css := range chunk.CSS {
+			if !seen[css] {
+				seen[css] = true
+				fmt.Fprintf(&styles, `<link rel="stylesheet" href="%s">`, template.HTMLEscapeString(v.base+css))
+			}
+		}
+		for _, imp := range chunk.Imports {
+			if err := walk(imp, false); err != nil {
+				return err
+			}
+		}
+		href := template.HTMLEscapeString(v.base + chunk.File)
+		switch {
+		case strings.HasSuffix(chunk.File, ".css"):
+			fmt.Fprintf(&styles, `<link rel="stylesheet" href="%s">`, href)
+		case entry:
+			fmt.Fprintf(&scripts, `<script type="module" src="%s"></script>`, href)
+		default:
+			fmt.Fprintf(&preloads, `<link rel="modulepreload" href="%s">`, href)
+		}
+		return nil
+	}
+	for _, entry := range entries {
+		if err := walk(strings.TrimPrefix(entry, "/"), true); err != nil {
+			return "", err
+		}
+	}
+	return template.HTML(styles.String() + preloads.String() + scripts.String()), nil
+}
+
+// loadManifest 读取 manifest.json，文件更新后自动重新加载
+func (v *Vite) loadManifest() (map[string]viteChunk, error) {
+	info, err := os.Stat(v.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("读取 vite manifest 失败（是否已执行 npm run build？）: %w", err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.chunks != nil && !info.ModTime().After(v.loadedAt) {
+		return v.chunks, nil
+	}
+	data, err := os.ReadFile(v.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("读取 vite manifest 失败: %w", err)
+	}
+	chunks := map[string]viteChunk{}
+	if err := json.Unmarshal(data, &chunks); err != nil {
+		return nil, fmt.Errorf("解析 vite manifest 失败: %w", err)
+	}
+	v.chunks, v.loadedAt = chunks, info.ModTime()
+	return chunks, nil
+}
+
+// ProxyHandler 将 /__vite/* 请求（含 HMR WebSocket）转发到开发服务器，路径保持不变
+func (v *Vite) ProxyHandler() gin.HandlerFunc {
+	target, err := url.Parse(v.devServer)
+	if err != nil {
+		return func(c *gin.Context) {
+			c.String(502, "vite dev_server 配置无效: %v", err)
+		}
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	return func(c *gin.Context) {
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+}
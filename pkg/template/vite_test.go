@@ -0,0 +1,98 @@
+package template
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+func TestViteDevTags(t *testing.T) {
+	v := NewVite(config.ViteConfig{DevServer: "http://localhost:5173/", Base: "/static"}, true)
+	out, err := v.Tags("src/main.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<script type="module" src="http://localhost:5173/@vite/client"></script>` +
+		`<script type="module" src="http://localhost:5173/src/main.ts"></script>`
+	if string(out) != want {
+		t.Fatalf("got %s", out)
+	}
+
+	v = NewVite(config.ViteConfig{DevServer: "http://localhost:5173", Proxy: true}, true)
+	out, _ = v.Tags("src/app.css")
+	if !strings.Contains(string(out), `src="/__vite/@vite/client"`) || !strings.Contains(string(out), `<link rel="stylesheet" href="/__vite/src/app.css">`) {
+		t.Fatalf("got %s", out)
+	}
+	if !v.ProxyEnabled() {
+		t.Fatal("proxy should be enabled in development")
+	}
+
+	// 非开发模式即使配置了 dev_server 也使用构建产物
+	if NewVite(config.ViteConfig{DevServer: "http://localhost:5173", Proxy: true}, false).ProxyEnabled() {
+		t.Fatal("proxy should be disabled in production")
+	}
+}
+
+func TestViteManifestTags(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	err := os.WriteFile(manifest, []byte(`{
+		"src/main.ts": {"file": "assets/main-a1b2.js", "src": "src/main.ts", "isEntry": true,
+			"css": ["assets/main-c3d4.css"], "imports": ["_vendor-e5f6.js"]},
+		"_vendor-e5f6.js": {"file": "assets/vendor-e5f6.js", "css": ["assets/vendor-0000.css"]},
+		"src/admin.css": {"file": "assets/admin-9999.css", "src": "src/admin.css", "isEntry": true}
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVite(config.ViteConfig{Manifest: manifest, Base: "/static"}, false)
+	out, err := v.Tags("src/main.ts", "src/admin.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<link rel="stylesheet" href="/static/assets/main-c3d4.css">` +
+		`<link rel="stylesheet" href="/static/assets/vendor-0000.css">` +
+		`<link rel="stylesheet" href="/static/assets/admin-9999.css">` +
+		`<link rel="modulepreload" href="/static/assets/vendor-e5f6.js">` +
+		`<script type="module" src="/static/assets/main-a1b2.js"></script>`
+	if string(out) != want {
+		t.Fatalf("got %s", out)
+	}
+
+	if _, err := v.Tags("src/missing.ts"); err == nil {
+		t.Fatal("expected error for unknown entry")
+	}
+}
+
+func TestViteProxyHandler(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("upstream " + r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v := NewVite(config.ViteConfig{DevServer: upstream.URL, Proxy: true}, true)
+	r.Any(ViteProxyPrefix+"/*path", v.ProxyHandler())
+
+	// ReverseProxy 依赖 http.CloseNotifier，httptest.ResponseRecorder 未实现，需经由真实服务器请求
+	app := httptest.NewServer(r)
+	defer app.Close()
+
+	resp, err := http.Get(app.URL + "/__vite/src/main.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "upstream /__vite/src/main.ts" {
+		t.Fatalf("got %q", body)
+	}
+}
@@ -0,0 +1,41 @@
+package tenant
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/cache"
+)
+
+// scopedCache 按上下文租户为键加前缀的缓存
+type scopedCache struct {
+	store cache.Store
+}
+
+// NewCache 包装缓存后端，所有键按上下文中的租户加上 "tenant:<id>:" 前缀，实现租户间缓存隔离。
+// 上下文中无租户时键不变
+//
+//	store := tenant.NewCache(cache.Default())
+//	store.Set(c, "stats", data, time.Minute)
+func NewCache(store cache.Store) cache.Store {
+	return &scopedCache{store: store}
+}
+
+// Get 实现 cache.Store
+func (s *scopedCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.store.Get(ctx, Key(ctx, key))
+}
+
+// Set 实现 cache.Store
+func (s *scopedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.store.Set(ctx, Key(ctx, key), value, ttl)
+}
+
+// Delete 实现 cache.Store
+func (s *scopedCache) Delete(ctx context.Context, keys ...string) error {
+	scoped := make([]string, len(keys))
+	for i, k := range keys {
+		scoped[i] = Key(ctx, k)
+	}
+	return s.store.Delete(ctx, scoped...)
+}
@@ -0,0 +1,113 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"gorm.io/gorm"
+)
+
+// SchemaPlugin 共享连接下按租户切换库/schema 的 GORM 插件：
+// 以 db.WithContext(ctx) 执行的模型操作，若上下文中租户设置了 Schema，表名改写为 "<schema>.<table>"。
+// 已显式指定库名（表名含 "."）或通过 db.Table 指定表达式的语句不做改写，Raw/Exec 原样执行。
+type SchemaPlugin struct{}
+
+// NewSchemaPlugin 创建 schema 切换插件
+func NewSchemaPlugin() *SchemaPlugin {
+	return &SchemaPlugin{}
+}
+
+// Name 实现 gorm.Plugin
+func (p *SchemaPlugin) Name() string {
+	return "tenant_schema"
+}
+
+// Initialize 实现 gorm.Plugin，在各操作的首个回调前改写表名
+func (p *SchemaPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("*").Register("tenant:schema_create", p.qualify); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("*").Register("tenant:schema_query", p.qualify); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("*").Register("tenant:schema_update", p.qualify); err != nil {
+		return err
+	}
+	return cb.Delete().Before("*").Register("tenant:schema_delete", p.qualify)
+}
+
+// qualify 为表名加上租户 schema 前缀
+func (p *SchemaPlugin) qualify(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.TableExpr != nil || stmt.Table == "" || strings.Contains(stmt.Table, ".") {
+		return
+	}
+	t := FromContext(stmt.Context)
+	if t == nil || t.Schema == "" || t.DB != nil {
+		return
+	}
+	stmt.Table = t.Schema + "." + stmt.Table
+}
+
+// Manager 按租户提供数据库连接：租户配置了独立连接（Tenant.DB）时使用独立连接（首次使用时建立并复用），
+// 否则使用默认连接（配合 SchemaPlugin 切换 schema）。
+type Manager struct {
+	base  *gorm.DB
+	open  func(t *Tenant) (*gorm.DB, error)
+	mu    sync.Mutex
+	conns map[string]*gorm.DB
+}
+
+// NewManager 创建租户连接管理器
+func NewManager(base *gorm.DB) *Manager {
+	return &Manager{
+		base:  base,
+		open:  func(t *Tenant) (*gorm.DB, error) { return database.Open(t.DB) },
+		conns: make(map[string]*gorm.DB),
+	}
+}
+
+// DB 返回上下文中租户对应的连接（已绑定 ctx），无租户时返回默认连接
+//
+//	db, err := ctl.Tenants.DB(c)
+func (m *Manager) DB(ctx context.Context) (*gorm.DB, error) {
+	t := FromContext(ctx)
+	if t == nil || t.DB == nil {
+		return m.base.WithContext(ctx), nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	conn, ok := m.conns[t.ID]
+	if !ok {
+		var err error
+		if conn, err = m.open(t); err != nil {
+			return nil, fmt.Errorf("连接租户 %s 数据库失败: %w", t.ID, err)
+		}
+		m.conns[t.ID] = conn
+	}
+	return conn.WithContext(ctx), nil
+}
+
+// Close 关闭所有租户独立连接（默认连接由调用方管理）
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for id, conn := range m.conns {
+		if sqlDB, err := conn.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		delete(m.conns, id)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("关闭租户数据库连接失败: %v", errs)
+	}
+	return nil
+}
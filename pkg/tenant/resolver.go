@@ -0,0 +1,74 @@
+package tenant
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+)
+
+// Resolver 从请求中解析租户 ID，无法解析时返回空字符串
+type Resolver func(c *gin.Context) string
+
+// FromSubdomain 按子域名解析：baseDomain 为 example.com 时，acme.example.com → acme。
+// 主域名本身与 www 不视为租户
+func FromSubdomain(baseDomain string) Resolver {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	return func(c *gin.Context) string {
+		host := c.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+		sub, ok := strings.CutSuffix(host, suffix)
+		if !ok || sub == "" || sub == "www" || strings.Contains(sub, ".") {
+			return ""
+		}
+		return sub
+	}
+}
+
+// FromHeader 按请求头解析，如 X-Tenant-ID（适用于 API 网关已完成租户识别的场景）
+func FromHeader(name string) Resolver {
+	return func(c *gin.Context) string {
+		return strings.TrimSpace(c.GetHeader(name))
+	}
+}
+
+// FromPath 按路由参数解析，路由需声明为 /:tenant/...：
+//
+//	g := rb.Group("/:tenant", middleware.Tenant(tenant.FromPath("tenant"), store))
+func FromPath(param string) Resolver {
+	return func(c *gin.Context) string {
+		return c.Param(param)
+	}
+}
+
+// FirstOf 依次尝试多个解析器，返回第一个非空结果
+func FirstOf(resolvers ...Resolver) Resolver {
+	return func(c *gin.Context) string {
+		for _, r := range resolvers {
+			if id := r(c); id != "" {
+				return id
+			}
+		}
+		return ""
+	}
+}
+
+// NewResolverFromConfig 按配置的 tenant.resolver（逗号分隔，按顺序尝试）创建解析器，未知策略被忽略
+func NewResolverFromConfig(cfg *config.TenantConfig) Resolver {
+	var resolvers []Resolver
+	for _, name := range strings.Split(cfg.Resolver, ",") {
+		switch strings.TrimSpace(name) {
+		case "subdomain":
+			resolvers = append(resolvers, FromSubdomain(cfg.BaseDomain))
+		case "header":
+			resolvers = append(resolvers, FromHeader(cfg.Header))
+		case "path":
+			resolvers = append(resolvers, FromPath(cfg.PathParam))
+		}
+	}
+	return FirstOf(resolvers...)
+}
@@ -0,0 +1,128 @@
+// Package tenant 提供多租户支持：租户解析、请求上下文、GORM 库/schema 切换以及缓存、会话键隔离。
+//
+// 典型用法：
+//
+//	r.Use(middleware.Tenant(tenant.FromSubdomain("example.com"), tenant.NewStaticStore(tenants...)))
+//
+//	func (ctl *PostController) List(c *gin.Context) error {
+//	    t := tenant.Current(c)
+//	    db := ctl.Tenants.DB(c) // 租户独立库，或共享库下自动加 schema 前缀
+//	    ...
+//	}
+package tenant
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/request"
+)
+
+// ErrNotFound 租户不存在
+var ErrNotFound = errors.New("租户不存在")
+
+// Tenant 租户
+type Tenant struct {
+	ID     string
+	Name   string
+	Schema string                 // 共享连接下使用的库/schema，为空时直接使用默认库
+	DB     *config.DatabaseConfig // 独立数据库连接配置，设置后优先于 Schema
+	Meta   map[string]any         // 应用自定义数据，如套餐、配额
+}
+
+// Store 租户查找
+type Store interface {
+	Find(ctx context.Context, id string) (*Tenant, error)
+}
+
+// StaticStore 基于固定列表的租户存储，并发安全
+type StaticStore struct {
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+}
+
+// NewStaticStore 创建固定列表的租户存储
+func NewStaticStore(tenants ...*Tenant) *StaticStore {
+	s := &StaticStore{tenants: make(map[string]*Tenant, len(tenants))}
+	for _, t := range tenants {
+		s.tenants[t.ID] = t
+	}
+	return s
+}
+
+// NewStoreFromConfig 以配置中的 tenant.tenants 创建租户存储
+func NewStoreFromConfig(cfg *config.TenantConfig) *StaticStore {
+	tenants := make([]*Tenant, 0, len(cfg.Tenants))
+	for _, e := range cfg.Tenants {
+		tenants = append(tenants, &Tenant{ID: e.ID, Name: e.Name, Schema: e.Schema, DB: e.Database})
+	}
+	return NewStaticStore(tenants...)
+}
+
+// Find 实现 Store
+func (s *StaticStore) Find(_ context.Context, id string) (*Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if t, ok := s.tenants[id]; ok {
+		return t, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Add 添加或替换租户
+func (s *StaticStore) Add(t *Tenant) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants[t.ID] = t
+}
+
+type contextKey struct{}
+
+// WithTenant 返回携带租户的上下文，用于后台任务等非 HTTP 场景
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext 获取上下文中的租户，兼容 gin.Context 与请求上下文，不存在时返回 nil
+func FromContext(ctx context.Context) *Tenant {
+	if ctx == nil {
+		return nil
+	}
+	if t, ok := ctx.Value(contextKey{}).(*Tenant); ok {
+		return t
+	}
+	// gin.Context 未开启 ContextWithFallback 时只能按字符串键读取 c.Keys
+	t, _ := ctx.Value(request.KeyTenant).(*Tenant)
+	return t
+}
+
+// Current 获取当前请求的租户（由 middleware.Tenant 写入），不存在时返回 nil
+func Current(c *gin.Context) *Tenant {
+	return request.GetOr[*Tenant](c, request.KeyTenant, nil)
+}
+
+// ID 获取上下文中的租户 ID，不存在时返回空字符串
+func ID(ctx context.Context) string {
+	if t := FromContext(ctx); t != nil {
+		return t.ID
+	}
+	return ""
+}
+
+// Key 为缓存、会话等键加上租户前缀（"tenant:<id>:<key>"），无租户时原样返回
+func Key(ctx context.Context, key string) string {
+	if id := ID(ctx); id != "" {
+		return "tenant:" + id + ":" + key
+	}
+	return key
+}
+
+// SessionKey 返回当前租户作用域下的会话键，避免同一会话在不同租户间串值
+//
+//	session.Set(c, tenant.SessionKey(c, "cart"), cart)
+func SessionKey(c *gin.Context, key string) string {
+	return Key(c, key)
+}
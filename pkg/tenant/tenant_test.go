@@ -0,0 +1,164 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/pkg/cache"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestResolvers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolve := func(r Resolver, host, path string, header map[string]string) string {
+		var got string
+		e := gin.New()
+		e.GET("/", func(c *gin.Context) { got = r(c) })
+		e.GET("/t/:tenant/home", func(c *gin.Context) { got = r(c) })
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = host
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	sub := FromSubdomain("example.com")
+	tests := []struct {
+		name   string
+		r      Resolver
+		host   string
+		path   string
+		header map[string]string
+		want   string
+	}{
+		{"subdomain", sub, "Acme.Example.com:8080", "/", nil, "acme"},
+		{"apex", sub, "example.com", "/", nil, ""},
+		{"www", sub, "www.example.com", "/", nil, ""},
+		{"nested", sub, "a.b.example.com", "/", nil, ""},
+		{"other domain", sub, "acme.evil.com", "/", nil, ""},
+		{"header", FromHeader("X-Tenant-ID"), "x", "/", map[string]string{"X-Tenant-ID": " beta "}, "beta"},
+		{"path", FromPath("tenant"), "x", "/t/gamma/home", nil, "gamma"},
+		{"first of", NewResolverFromConfig(&config.TenantConfig{
+			Resolver: "header, subdomain", Header: "X-Tenant-ID", BaseDomain: "example.com",
+		}), "acme.example.com", "/", nil, "acme"},
+	}
+	for _, tt := range tests {
+		if got := resolve(tt.r, tt.host, tt.path, tt.header); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStoreAndKeys(t *testing.T) {
+	store := NewStoreFromConfig(&config.TenantConfig{Tenants: []config.TenantEntry{{ID: "acme", Schema: "tenant_acme"}}})
+	acme, err := store.Find(context.Background(), "acme")
+	if err != nil || acme.Schema != "tenant_acme" {
+		t.Fatalf("Find(acme) = %+v, %v", acme, err)
+	}
+	if _, err := store.Find(context.Background(), "nope"); err != ErrNotFound {
+		t.Fatalf("Find(nope) error = %v", err)
+	}
+
+	ctx := WithTenant(context.Background(), acme)
+	if Key(ctx, "cart") != "tenant:acme:cart" || Key(context.Background(), "cart") != "cart" {
+		t.Errorf("unexpected keys %q / %q", Key(ctx, "cart"), Key(context.Background(), "cart"))
+	}
+
+	mem := cache.NewMemory()
+	scoped := NewCache(mem)
+	_ = scoped.Set(ctx, "stats", []byte("a"), time.Minute)
+	if _, ok, _ := mem.Get(ctx, "tenant:acme:stats"); !ok {
+		t.Error("scoped cache did not prefix key")
+	}
+	if _, ok, _ := scoped.Get(context.Background(), "stats"); ok {
+		t.Error("value leaked outside tenant scope")
+	}
+	_ = scoped.Delete(ctx, "stats")
+	if _, ok, _ := mem.Get(ctx, "tenant:acme:stats"); ok {
+		t.Error("scoped delete did not remove key")
+	}
+}
+
+type note struct {
+	ID   uint
+	Body string
+}
+
+func newSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 单连接保证 ATTACH 的内存库对后续语句可见
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestSchemaPlugin(t *testing.T) {
+	db := newSQLite(t)
+	for _, sql := range []string{
+		"ATTACH DATABASE ':memory:' AS tenant_acme",
+		"CREATE TABLE notes (id integer primary key, body text)",
+		"CREATE TABLE tenant_acme.notes (id integer primary key, body text)",
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Use(NewSchemaPlugin()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithTenant(context.Background(), &Tenant{ID: "acme", Schema: "tenant_acme"})
+	if err := db.WithContext(ctx).Create(&note{Body: "tenant"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&note{Body: "shared"})
+
+	var tenantNotes, sharedNotes []note
+	db.WithContext(ctx).Find(&tenantNotes)
+	db.Find(&sharedNotes)
+	if len(tenantNotes) != 1 || tenantNotes[0].Body != "tenant" {
+		t.Errorf("tenant notes = %+v", tenantNotes)
+	}
+	if len(sharedNotes) != 1 || sharedNotes[0].Body != "shared" {
+		t.Errorf("shared notes = %+v", sharedNotes)
+	}
+}
+
+func TestManagerSeparateConnections(t *testing.T) {
+	base := newSQLite(t)
+	m := NewManager(base)
+	opened := 0
+	m.open = func(t *Tenant) (*gorm.DB, error) {
+		opened++
+		return gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	}
+	defer m.Close()
+
+	db, err := m.DB(context.Background())
+	if err != nil || db.Statement.ConnPool != base.Statement.ConnPool {
+		t.Fatalf("expected base connection without tenant, err=%v", err)
+	}
+
+	ctx := WithTenant(context.Background(), &Tenant{ID: "big", DB: &config.DatabaseConfig{Driver: "sqlite"}})
+	first, err := m.DB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := m.DB(ctx)
+	if opened != 1 || first.Statement.ConnPool != second.Statement.ConnPool || first.Statement.ConnPool == base.Statement.ConnPool {
+		t.Errorf("expected one dedicated connection reused, opened=%d", opened)
+	}
+}
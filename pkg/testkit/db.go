@@ -0,0 +1,94 @@
+package testkit
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"gorm.io/gorm"
+)
+
+var (
+	sharedDB     *gorm.DB
+	sharedDBErr  error
+	sharedDBOnce sync.Once
+)
+
+// TestDB 返回包裹在事务中的测试数据库连接，测试结束时回滚，测试之间互不可见、无需清理数据。
+// 底层为进程内共享的内存 SQLite（单连接），models 在开启事务前迁移：
+//
+//	func TestUserRepository(t *testing.T) {
+//	    db := testkit.TestDB(t, &model.User{})
+//	    repo := repository.NewUserRepository(db)
+//	    ...
+//	}
+//
+// 被测代码内的 db.Transaction 在事务中以 SAVEPOINT 嵌套执行。同一时刻只有一个事务持有连接，
+// 并行测试会依次等待
+func TestDB(t testing.TB, models ...any) *gorm.DB {
+	t.Helper()
+	sharedDBOnce.Do(func() {
+		sharedDB, sharedDBErr = database.Open(&config.DatabaseConfig{
+			Driver:       "sqlite",
+			DBName:       "file:testkit_shared?mode=memory&cache=shared",
+			MaxIdleConns: 1,
+			MaxOpenConns: 1,
+		})
+	})
+	if sharedDBErr != nil {
+		t.Fatalf("testkit: 打开测试数据库失败: %v", sharedDBErr)
+	}
+	if len(models) > 0 {
+		if err := sharedDB.AutoMigrate(models...); err != nil {
+			t.Fatalf("testkit: 迁移测试数据库失败: %v", err)
+		}
+	}
+
+	tx := sharedDB.Begin()
+	if tx.Error != nil {
+		t.Fatalf("testkit: 开启测试事务失败: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// TestDBFile 返回测试专用的 SQLite 文件数据库（位于测试临时目录，测试结束时关闭并删除），
+// 适用于需要真实提交事务或并行执行的测试
+func TestDBFile(t testing.TB, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := database.Open(&config.DatabaseConfig{
+		Driver:       "sqlite",
+		DBName:       filepath.Join(t.TempDir(), "test.db"),
+		MaxIdleConns: 1,
+		MaxOpenConns: 1,
+	})
+	if err != nil {
+		t.Fatalf("testkit: 打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("testkit: 迁移测试数据库失败: %v", err)
+		}
+	}
+	return db
+}
+
+// WithDB 使用指定的数据库连接替代测试应用默认的独立内存库，由调用方负责其生命周期
+func WithDB(db *gorm.DB) Option {
+	return func(o *options) { o.db = db }
+}
+
+// WithTestDB 将 TestDB 的事务连接注入依赖图，测试结束时回滚，应用内的所有写入都不会保留：
+//
+//	app := testkit.New(t, testkit.WithTestDB(t), testkit.WithMigrate(&model.User{}))
+func WithTestDB(t testing.TB) Option {
+	t.Helper()
+	return WithDB(TestDB(t))
+}
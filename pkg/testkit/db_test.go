@@ -0,0 +1,66 @@
+package testkit
+
+import (
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type txNote struct {
+	ID   uint
+	Body string
+}
+
+// TestTestDBRollsBack 每个测试的写入在结束时回滚
+func TestTestDBRollsBack(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		t.Run("isolated", func(t *testing.T) {
+			db := TestDB(t, &txNote{})
+			var count int64
+			db.Model(&txNote{}).Count(&count)
+			if count != 0 {
+				t.Fatalf("应看不到其他测试的写入，count = %d", count)
+			}
+			if err := db.Create(&txNote{Body: "a"}).Error; err != nil {
+				t.Fatal(err)
+			}
+			// 被测代码中的嵌套事务以 SAVEPOINT 执行
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Create(&txNote{Body: "b"}).Error
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			db.Model(&txNote{}).Count(&count)
+			if count != 2 {
+				t.Errorf("count = %d", count)
+			}
+		})
+	}
+}
+
+// TestTestDBFile 每个测试使用独立的 SQLite 文件
+func TestTestDBFile(t *testing.T) {
+	db := TestDBFile(t, &txNote{})
+	if err := db.Create(&txNote{Body: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var n txNote
+	if err := db.First(&n).Error; err != nil || n.Body != "a" {
+		t.Errorf("First = %+v, %v", n, err)
+	}
+}
+
+// TestWithTestDB 应用依赖图使用事务连接
+func TestWithTestDB(t *testing.T) {
+	app := New(t, WithTestDB(t), WithMigrate(&txNote{}))
+	app.DB.Create(&txNote{Body: "in tx"})
+	app.GET("/demo/api/users/1").AssertStatus(http.StatusOK)
+
+	var count int64
+	app.DB.Model(&txNote{}).Count(&count)
+	if count != 1 {
+		t.Errorf("count = %d", count)
+	}
+}
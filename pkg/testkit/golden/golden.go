@@ -0,0 +1,111 @@
+// Package golden 提供模板渲染的 golden 文件测试：以固定数据渲染模板，与 testdata/golden 下的期望输出比较。
+// 比较前统一规范化空白，模板缩进、空行的调整不会导致测试失败。
+//
+//	func TestProfilePage(t *testing.T) {
+//	    golden.AssertTemplate(t, manager, "user/profile", fixture, "main")
+//	}
+//
+// 模板或函数有意变更后执行 `go test ./... -update` 重新生成 golden 文件，并在代码评审中检查其 diff。
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gorilla-go/go-framework/pkg/template"
+)
+
+// update 为 true 时用实际输出覆盖 golden 文件
+var update = flag.Bool("update", false, "用实际输出重新生成 golden 文件")
+
+// dir golden 文件目录，在包初始化时确定为被测包下的 testdata/golden
+// （早于 TestMain 等切换工作目录的逻辑）
+var dir = func() string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, "testdata", "golden")
+}()
+
+// spaces 连续的空格与制表符
+var spaces = regexp.MustCompile(`[ \t]+`)
+
+// Path 返回 golden 文件的绝对路径，name 可含子目录，如 "user/profile" → testdata/golden/user/profile.golden
+func Path(name string) string {
+	return filepath.Join(dir, filepath.FromSlash(name)+".golden")
+}
+
+// Normalize 规范化空白：统一换行符，去除每行首尾空白与空行，行内连续空白合并为一个空格
+func Normalize(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimSpace(spaces.ReplaceAllString(line, " "))
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// Assert 比较 got 与 golden 文件（均经 Normalize），-update 时写入 golden 文件
+func Assert(t testing.TB, name, got string) {
+	t.Helper()
+	path := Path(name)
+	got = Normalize(got)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden: 创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("golden: 写入 %s 失败: %v", path, err)
+		}
+		return
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("golden: %s 不存在，请使用 -update 生成", path)
+	}
+	if err != nil {
+		t.Fatalf("golden: 读取 %s 失败: %v", path, err)
+	}
+	want := Normalize(string(raw))
+	if got != want {
+		t.Errorf("golden: 输出与 %s 不一致（-update 可重新生成）\n%s", path, diff(want, got))
+	}
+}
+
+// AssertTemplate 以 data 渲染模板并与同名 golden 文件比较（layout 为空时不使用布局）
+func AssertTemplate(t testing.TB, m template.Manager, name string, data any, layout ...string) {
+	t.Helper()
+	out, err := m.RenderToString(name, data, layout...)
+	if err != nil {
+		t.Fatalf("golden: 渲染模板 %s 失败: %v", name, err)
+	}
+	Assert(t, name, out)
+}
+
+// diff 报告首个不一致的行及其上下文
+func diff(want, got string) string {
+	wl, gl := strings.Split(want, "\n"), strings.Split(got, "\n")
+	n := max(len(wl), len(gl))
+	for i := 0; i < n; i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return fmt.Sprintf("第 %d 行:\n  期望: %s\n  实际: %s", i+1, w, g)
+		}
+	}
+	return ""
+}
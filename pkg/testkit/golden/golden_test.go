@@ -0,0 +1,25 @@
+package golden
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got := Normalize("  <ul>\r\n\n\t<li>a \t  b</li>  \n</ul>")
+	if got != "<ul>\n<li>a b</li>\n</ul>\n" {
+		t.Errorf("Normalize = %q", got)
+	}
+}
+
+// TestAssert 空白差异不影响比较
+func TestAssert(t *testing.T) {
+	Assert(t, "sample", "<ul>\n    <li>a   b</li>\n\n</ul>")
+}
+
+func TestDiff(t *testing.T) {
+	d := diff("a\nb\n", "a\nc\n")
+	if !strings.Contains(d, "第 2 行") || !strings.Contains(d, "期望: b") || !strings.Contains(d, "实际: c") {
+		t.Errorf("diff = %q", d)
+	}
+}
@@ -0,0 +1,162 @@
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/testkit/golden"
+)
+
+// recorder 记录响应与渲染的模板（模板引擎沿 Unwrap 链回调 RecordRender）
+type recorder struct {
+	*httptest.ResponseRecorder
+	templates []string
+}
+
+// RecordRender 实现 template.RenderRecorder
+func (r *recorder) RecordRender(name string, _ bool, _ time.Duration) {
+	r.templates = append(r.templates, name)
+}
+
+// Response 测试响应，断言失败时标记测试失败并继续（链式调用）
+type Response struct {
+	*httptest.ResponseRecorder
+
+	// Templates 本次请求渲染的模板，格式为 "布局:模板"（无布局时仅模板名）
+	Templates []string
+
+	t    testing.TB
+	data any
+	json bool
+}
+
+// AssertStatus 断言状态码
+func (r *Response) AssertStatus(code int) *Response {
+	r.t.Helper()
+	if r.Code != code {
+		r.t.Errorf("期望状态码 %d，得到 %d，响应: %s", code, r.Code, truncate(r.Body.String()))
+	}
+	return r
+}
+
+// AssertHeader 断言响应头
+func (r *Response) AssertHeader(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Header().Get(key); got != value {
+		r.t.Errorf("期望响应头 %s=%q，得到 %q", key, value, got)
+	}
+	return r
+}
+
+// AssertContains 断言响应体包含指定内容
+func (r *Response) AssertContains(s string) *Response {
+	r.t.Helper()
+	if !strings.Contains(r.Body.String(), s) {
+		r.t.Errorf("响应体不包含 %q: %s", s, truncate(r.Body.String()))
+	}
+	return r
+}
+
+// AssertTemplate 断言本次请求渲染了指定模板（不含布局）
+func (r *Response) AssertTemplate(name string) *Response {
+	r.t.Helper()
+	for _, rendered := range r.Templates {
+		if rendered == name || strings.HasSuffix(rendered, ":"+name) {
+			return r
+		}
+	}
+	r.t.Errorf("期望渲染模板 %q，实际渲染: %v", name, r.Templates)
+	return r
+}
+
+// AssertGolden 将响应体与 golden 文件比较（规范化空白），-update 时重新生成，见 golden 包
+func (r *Response) AssertGolden(name string) *Response {
+	r.t.Helper()
+	golden.Assert(r.t, name, r.Body.String())
+	return r
+}
+
+// AssertJSON 断言 JSON 响应中指定路径的值，路径以 "." 分隔，数组用下标（如 "data.items.0.id"），
+// want 先按 JSON 编解码再比较，因此 1 与 1.0 视为相等
+func (r *Response) AssertJSON(path string, want any) *Response {
+	r.t.Helper()
+	got, ok := r.JSONPath(path)
+	if !ok {
+		r.t.Errorf("JSON 路径 %q 不存在: %s", path, truncate(r.Body.String()))
+		return r
+	}
+	var normalized any
+	if b, err := json.Marshal(want); err == nil {
+		_ = json.Unmarshal(b, &normalized)
+	}
+	if !reflect.DeepEqual(got, normalized) {
+		r.t.Errorf("JSON 路径 %q 期望 %v，得到 %v", path, normalized, got)
+	}
+	return r
+}
+
+// JSONPath 读取 JSON 响应中指定路径的值，响应不是 JSON 或路径不存在时 ok 为 false
+func (r *Response) JSONPath(path string) (any, bool) {
+	if !r.json {
+		if err := json.Unmarshal(r.Body.Bytes(), &r.data); err != nil {
+			return nil, false
+		}
+		r.json = true
+	}
+	cur := r.data
+	if path == "" {
+		return cur, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Decode 将 JSON 响应体解码到 v
+func (r *Response) Decode(v any) error {
+	return json.Unmarshal(r.Body.Bytes(), v)
+}
+
+// marshal 编码 JSON 请求体，payload 为 nil 时无请求体
+func marshal(payload any) (io.Reader, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// truncate 截断过长的响应体，避免断言信息刷屏
+func truncate(s string) string {
+	const max = 512
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
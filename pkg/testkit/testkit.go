@@ -0,0 +1,295 @@
+// Package testkit 提供应用级集成测试工具：以测试专用的依赖替换启动完整的 FX 依赖图，
+// 直接调用路由（不监听端口），并提供请求辅助方法与响应断言。
+//
+//	func TestShowUser(t *testing.T) {
+//	    app := testkit.New(t, testkit.WithMigrate(&model.User{}))
+//	    app.DB.Create(&model.User{Name: "alice"})
+//
+//	    app.ActingAs(1, "alice", "admin").GET("/api/users/1").
+//	        AssertStatus(http.StatusOK).
+//	        AssertJSON("data.name", "alice")
+//
+//	    app.GET("/").AssertStatus(http.StatusOK).AssertTemplate("index")
+//	}
+//
+// 与 bootstrap.NewApp 相比：数据库替换为独立的内存 SQLite（不注册模型事件等插件，可用 WithTestDB 改为回滚的事务），会话改用内存存储，
+// 关闭限流、调试工具栏与 HTML 压缩，日志写入测试临时目录，且不启动 HTTP 服务。
+// 控制器为全局注册的单例，每次 New 都会重新注入其依赖，因此使用 testkit 的测试不能并行执行。
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla-go/go-framework/bootstrap"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/middleware"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// baseURL 测试请求使用的主机，仅用于 Cookie 作用域
+const baseURL = "http://testkit.local"
+
+// App 测试应用
+type App struct {
+	Engine *gin.Engine
+	Config *config.Config
+	DB     *gorm.DB
+
+	t       testing.TB
+	jar     http.CookieJar
+	headers http.Header
+}
+
+// options 测试应用选项
+type options struct {
+	configure []func(*config.Config)
+	fxOptions []fx.Option
+	models    []any
+	db        *gorm.DB
+	clock     clock.Clock
+}
+
+// Option 测试应用选项
+type Option func(*options)
+
+// WithConfig 在默认测试配置基础上修改配置
+func WithConfig(fn func(cfg *config.Config)) Option {
+	return func(o *options) { o.configure = append(o.configure, fn) }
+}
+
+// WithReplace 以给定值替换依赖图中同类型的依赖（fx.Replace），如替换服务接口为 mock：
+//
+//	testkit.WithReplace(fx.Annotate(mockUsers, fx.As(new(service.UserService))))
+func WithReplace(values ...any) Option {
+	return func(o *options) { o.fxOptions = append(o.fxOptions, fx.Replace(values...)) }
+}
+
+// WithOptions 追加任意 FX 选项（如 fx.Decorate、fx.Invoke）
+func WithOptions(opts ...fx.Option) Option {
+	return func(o *options) { o.fxOptions = append(o.fxOptions, opts...) }
+}
+
+// WithMigrate 启动前在内存数据库中创建模型对应的表
+func WithMigrate(models ...any) Option {
+	return func(o *options) { o.models = append(o.models, models...) }
+}
+
+// WithClock 以给定时钟（通常为 clock.Fake）替换全局时钟与依赖图中的 clock.Clock，测试结束时恢复：
+//
+//	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	app := testkit.New(t, testkit.WithClock(fake))
+//	token := ... // 签发令牌
+//	fake.Advance(25 * time.Hour) // 令牌过期
+func WithClock(c clock.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+var (
+	rootOnce sync.Once
+	dbSeq    atomic.Int64
+)
+
+// chdirRoot 切换到仓库根目录（含 config/config.yaml），使配置、模板与静态文件的相对路径可用
+func chdirRoot() {
+	rootOnce.Do(func() {
+		dir, _ := os.Getwd()
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "config", "config.yaml")); err == nil {
+				_ = os.Chdir(dir)
+				return
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return
+			}
+			dir = parent
+		}
+	})
+}
+
+// New 启动测试应用，测试结束时自动停止并关闭数据库
+func New(t testing.TB, opts ...Option) *App {
+	t.Helper()
+	chdirRoot()
+	gin.SetMode(gin.TestMode)
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base, err := config.Fetch()
+	if err != nil {
+		t.Fatalf("testkit: 加载配置失败: %v", err)
+	}
+	cfg := *base
+	cfg.Server.EnableRateLimit = false
+	cfg.Server.DebugToolbar = false
+	cfg.Server.MinifyHTML = false
+	cfg.Session.Store = "memory"
+	cfg.Log.Filename = filepath.Join(t.TempDir(), "app.log")
+	cfg.Log.Stdout = false
+	cfg.Database = config.DatabaseConfig{
+		Driver: "sqlite",
+		// 命名的共享内存库：同一应用的多个连接看到同一份数据，不同应用互相隔离
+		DBName:       fmt.Sprintf("file:testkit%d?mode=memory&cache=shared", dbSeq.Add(1)),
+		MaxIdleConns: 1,
+		MaxOpenConns: 1,
+	}
+	for _, fn := range o.configure {
+		fn(&cfg)
+	}
+
+	db, owned := o.db, o.db == nil
+	if owned {
+		if db, err = database.Open(&cfg.Database); err != nil {
+			t.Fatalf("testkit: 打开测试数据库失败: %v", err)
+		}
+	}
+	if len(o.models) > 0 {
+		if err := db.AutoMigrate(o.models...); err != nil {
+			t.Fatalf("testkit: 迁移测试数据库失败: %v", err)
+		}
+	}
+
+	if o.clock != nil {
+		prev := clock.Default()
+		clock.SetDefault(o.clock)
+		t.Cleanup(func() { clock.SetDefault(prev) })
+		o.fxOptions = append(o.fxOptions, fx.Replace(fx.Annotate(o.clock, fx.As(new(clock.Clock)))))
+	}
+
+	a := &App{Config: &cfg, DB: db, t: t, headers: make(http.Header)}
+	a.jar, _ = cookiejar.New(nil)
+
+	fxOpts := []fx.Option{
+		fx.NopLogger,
+		bootstrap.Module(),
+		fx.Replace(&cfg, db),
+	}
+	fxOpts = append(fxOpts, o.fxOptions...)
+	fxOpts = append(fxOpts, fx.Populate(&a.Engine))
+
+	app := fx.New(fxOpts...)
+	if err := app.Err(); err != nil {
+		t.Fatalf("testkit: 构建依赖图失败: %v", err)
+	}
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("testkit: 启动应用失败: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = app.Stop(context.Background())
+		if !owned {
+			return
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return a
+}
+
+// clone 复制应用（共享引擎、数据库与 Cookie），请求头独立
+func (a *App) clone() *App {
+	c := *a
+	c.headers = a.headers.Clone()
+	return &c
+}
+
+// WithHeader 返回附带指定请求头的应用副本
+func (a *App) WithHeader(key, value string) *App {
+	c := a.clone()
+	c.headers.Set(key, value)
+	return c
+}
+
+// WithToken 返回以 Bearer Token 认证的应用副本
+func (a *App) WithToken(token string) *App {
+	return a.WithHeader("Authorization", "Bearer "+token)
+}
+
+// ActingAs 按测试配置的 JWT 密钥签发令牌，返回以该用户身份请求的应用副本
+func (a *App) ActingAs(userID uint, username, role string) *App {
+	a.t.Helper()
+	token, err := middleware.GenerateToken(userID, username, role, &a.Config.JWT)
+	if err != nil {
+		a.t.Fatalf("testkit: 签发令牌失败: %v", err)
+	}
+	return a.WithToken(token)
+}
+
+// GET 发送 GET 请求
+func (a *App) GET(path string) *Response {
+	a.t.Helper()
+	return a.Request(http.MethodGet, path, nil)
+}
+
+// DELETE 发送 DELETE 请求
+func (a *App) DELETE(path string) *Response {
+	a.t.Helper()
+	return a.Request(http.MethodDelete, path, nil)
+}
+
+// POSTJSON 发送 JSON 请求体的 POST 请求
+func (a *App) POSTJSON(path string, payload any) *Response {
+	a.t.Helper()
+	return a.JSON(http.MethodPost, path, payload)
+}
+
+// PUTJSON 发送 JSON 请求体的 PUT 请求
+func (a *App) PUTJSON(path string, payload any) *Response {
+	a.t.Helper()
+	return a.JSON(http.MethodPut, path, payload)
+}
+
+// POSTForm 发送表单请求
+func (a *App) POSTForm(path string, form url.Values) *Response {
+	a.t.Helper()
+	return a.WithHeader("Content-Type", "application/x-www-form-urlencoded").
+		Request(http.MethodPost, path, strings.NewReader(form.Encode()))
+}
+
+// JSON 发送 JSON 请求体的请求，并声明接受 JSON 响应
+func (a *App) JSON(method, path string, payload any) *Response {
+	a.t.Helper()
+	body, err := marshal(payload)
+	if err != nil {
+		a.t.Fatalf("testkit: 编码请求体失败: %v", err)
+	}
+	return a.WithHeader("Content-Type", "application/json").
+		WithHeader("Accept", "application/json").
+		Request(method, path, body)
+}
+
+// Request 发送请求，自动携带此前响应设置的 Cookie（会话在请求间保持）
+func (a *App) Request(method, path string, body io.Reader) *Response {
+	a.t.Helper()
+	req := httptest.NewRequest(method, baseURL+path, body)
+	for k, v := range a.headers {
+		req.Header[k] = v
+	}
+	for _, c := range a.jar.Cookies(req.URL) {
+		req.AddCookie(c)
+	}
+
+	rec := &recorder{ResponseRecorder: httptest.NewRecorder()}
+	a.Engine.ServeHTTP(rec, req)
+	a.jar.SetCookies(req.URL, rec.Result().Cookies())
+	return &Response{ResponseRecorder: rec.ResponseRecorder, Templates: rec.templates, t: a.t}
+}
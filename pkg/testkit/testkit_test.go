@@ -0,0 +1,93 @@
+package testkit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/app/service"
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"go.uber.org/fx"
+)
+
+// TestPageAndTemplate 页面请求断言状态码与渲染的模板
+func TestPageAndTemplate(t *testing.T) {
+	app := New(t)
+	app.GET("/").
+		AssertStatus(http.StatusOK).
+		AssertTemplate("index").
+		AssertContains("Go Framework")
+}
+
+// TestJSONAPI JSON 请求与 JSON 路径断言
+func TestJSONAPI(t *testing.T) {
+	app := New(t)
+
+	app.GET("/demo/api/users/1").
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.name", "张三").
+		AssertJSON("data.id", 1)
+
+	app.POSTJSON("/demo/api/users", map[string]any{"name": "王五", "email": "wangwu@example.com"}).
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.role", "user")
+
+	if v, ok := app.GET("/demo/api/users").JSONPath("data.2.name"); !ok || v != "王五" {
+		t.Errorf("列表第三项 = %v, %v", v, ok)
+	}
+}
+
+// TestActingAs 以指定用户身份访问需要认证的接口
+func TestActingAs(t *testing.T) {
+	app := New(t)
+
+	app.GET("/demo/auth/profile").AssertStatus(http.StatusUnauthorized)
+	app.ActingAs(7, "alice", "admin").GET("/demo/auth/profile").
+		AssertStatus(http.StatusOK).
+		AssertJSON("data.username", "alice")
+}
+
+type stubUsers struct{ service.UserService }
+
+func (stubUsers) Get(context.Context, uint) (*service.User, error) {
+	return &service.User{ID: 42, Name: "stub"}, nil
+}
+
+// TestWithReplace 替换依赖图中的服务实现
+func TestWithReplace(t *testing.T) {
+	app := New(t, WithReplace(fx.Annotate(stubUsers{}, fx.As(new(service.UserService)))))
+	app.GET("/demo/api/users/1").AssertJSON("data.name", "stub")
+}
+
+// TestMigrateIsolated 每个测试应用使用独立的内存数据库
+func TestMigrateIsolated(t *testing.T) {
+	type note struct {
+		ID   uint
+		Body string
+	}
+	a := New(t, WithMigrate(&note{}))
+	a.DB.Create(&note{Body: "hello"})
+
+	var count int64
+	a.DB.Model(&note{}).Count(&count)
+	if count != 1 {
+		t.Errorf("count = %d", count)
+	}
+
+	b := New(t)
+	if b.DB.Migrator().HasTable(&note{}) {
+		t.Error("不同测试应用的数据库应互相隔离")
+	}
+}
+
+// TestWithClock 拨动假时钟使令牌过期
+func TestWithClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	app := New(t, WithClock(fake))
+
+	user := app.ActingAs(7, "alice", "admin")
+	user.GET("/demo/auth/profile").AssertStatus(http.StatusOK)
+	fake.Advance(time.Duration(app.Config.JWT.Expire+1) * time.Hour)
+	user.GET("/demo/auth/profile").AssertStatus(http.StatusUnauthorized)
+}
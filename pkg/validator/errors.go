@@ -0,0 +1,33 @@
+package validator
+
+import "strings"
+
+// FieldError 单个字段的绑定或校验错误
+type FieldError struct {
+	Field   string `json:"field"`   // 结构体字段路径，嵌套字段以 . 分隔，如 Address.City
+	Key     string `json:"key"`     // 请求中的参数名，如 address.city_name
+	Tag     string `json:"tag"`     // 错误类型，如 type（类型不匹配）、required
+	Message string `json:"message"` // 错误描述
+}
+
+// Error 实现 error 接口
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors 字段错误列表，可通过 errors.As 从校验错误（AppError）中取出逐字段展示
+//
+//	var fes validator.FieldErrors
+//	if errors.As(err, &fes) {
+//	    for _, fe := range fes { ... }
+//	}
+type FieldErrors []FieldError
+
+// Error 实现 error 接口，以分号连接各字段错误
+func (es FieldErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
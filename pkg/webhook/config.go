@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"gorm.io/gorm"
+)
+
+// NewFromConfig 按 webhook.* 配置创建管理器，bus 为 nil 时使用全局事件总线
+func NewFromConfig(cfg *config.WebhookConfig, db *gorm.DB, bus *eventbus.EventBus) *Manager {
+	opts := []Option{
+		WithMaxAttempts(cfg.MaxAttempts),
+		WithInterval(time.Duration(cfg.Interval) * time.Second),
+		WithBatchSize(cfg.BatchSize),
+		WithBackoff(time.Duration(cfg.Backoff)*time.Second, time.Duration(cfg.MaxBackoff)*time.Second),
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithHTTPClient(httpclient.New(time.Duration(cfg.Timeout)*time.Second)))
+	}
+	return New(db, bus, opts...)
+}
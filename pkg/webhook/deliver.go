@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxResponseBody 请求记录中保存的响应内容上限
+const maxResponseBody = 2 << 10
+
+// envelope 请求体
+type envelope struct {
+	ID        uint64          `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Process 投递一批到期的待投递记录，返回成功的数量。
+// 每条记录先以请求次数做乐观锁领取，多个实例同时轮询时同一记录只会被一个实例请求
+func (m *Manager) Process(ctx context.Context) (int, error) {
+	var list []Delivery
+	err := m.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", StatusPending, m.clock.Now()).
+		Order("id").
+		Limit(m.cfg.batchSize).
+		Find(&list).Error
+	if err != nil {
+		return 0, fmt.Errorf("读取待投递 webhook 失败: %w", err)
+	}
+
+	done := 0
+	for i := range list {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if m.deliver(ctx, &list[i]) {
+			done++
+		}
+	}
+	return done, nil
+}
+
+// deliver 领取并请求单条投递，更新其状态并写入请求记录
+func (m *Manager) deliver(ctx context.Context, d *Delivery) bool {
+	db := m.db.WithContext(ctx)
+	// 领取期间推迟下次投递时间，进程在请求中退出时记录在租期后重新投递
+	lease := m.clock.Now().Add(m.client.Timeout + m.cfg.backoff)
+	res := db.Model(&Delivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", d.ID, StatusPending, d.Attempts).
+		Updates(map[string]any{"attempts": d.Attempts + 1, "next_attempt_at": lease})
+	if res.Error != nil {
+		logErrorf("领取 webhook 投递 %d 失败: %v", d.ID, res.Error)
+		return false
+	}
+	if res.RowsAffected == 0 {
+		return false
+	}
+	d.Attempts++
+
+	attempt := Attempt{DeliveryID: d.ID}
+	start := m.clock.Now()
+	var ep Endpoint
+	err := db.First(&ep, d.EndpointID).Error
+	final := d.Attempts >= m.cfg.maxAttempts
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !ep.Active):
+		// 端点已删除或停用，不再重试
+		err, final = errors.New("端点已删除或停用"), true
+	case err == nil:
+		attempt.StatusCode, attempt.ResponseBody, err = m.post(ctx, &ep, d)
+	}
+	attempt.Duration = m.clock.Since(start).Milliseconds()
+
+	updates := map[string]any{"response_status": attempt.StatusCode}
+	if err == nil {
+		now := m.clock.Now()
+		d.Status, d.LastError, d.DeliveredAt = StatusSuccess, "", &now
+		updates["delivered_at"] = &now
+	} else {
+		attempt.Error = err.Error()
+		d.LastError = err.Error()
+		if final {
+			d.Status = StatusFailed
+		} else {
+			updates["next_attempt_at"] = m.clock.Now().Add(m.backoff(d.Attempts))
+		}
+	}
+	d.ResponseStatus = attempt.StatusCode
+	updates["status"] = d.Status
+	updates["last_error"] = d.LastError
+
+	if uerr := db.Model(&Delivery{}).Where("id = ?", d.ID).Updates(updates).Error; uerr != nil {
+		logErrorf("更新 webhook 投递 %d 状态失败: %v", d.ID, uerr)
+	}
+	if cerr := db.Create(&attempt).Error; cerr != nil {
+		logErrorf("写入 webhook 请求记录失败: %v", cerr)
+	}
+
+	switch d.Status {
+	case StatusSuccess:
+		_ = m.bus.EmitCtx(ctx, EventDelivered, d)
+	case StatusFailed:
+		_ = m.bus.EmitCtx(ctx, EventFailed, d)
+	}
+	return err == nil
+}
+
+// post 以签名请求投递到端点，返回状态码与（截断的）响应内容，非 2xx 响应作为错误返回
+func (m *Manager) post(ctx context.Context, ep *Endpoint, d *Delivery) (int, string, error) {
+	data := json.RawMessage(d.Payload)
+	if len(data) == 0 {
+		data = json.RawMessage("null")
+	}
+	body, err := json.Marshal(envelope{ID: d.ID, Event: d.Event, CreatedAt: d.CreatedAt, Data: data})
+	if err != nil {
+		return 0, "", err
+	}
+	ts := m.clock.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-framework-webhook")
+	req.Header.Set(HeaderID, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(ep.Secret, ts, body))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	out, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(out), fmt.Errorf("端点返回 %s", resp.Status)
+	}
+	return resp.StatusCode, string(out), nil
+}
+
+// backoff 第 n 次失败后的重试间隔
+func (m *Manager) backoff(n int) time.Duration {
+	d := m.cfg.backoff
+	for i := 1; i < n && d < m.cfg.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, m.cfg.maxBackoff)
+}
+
+// Replay 将失败的投递重置为待投递并立即投递一轮；未指定 ids 时重放全部失败投递
+func (m *Manager) Replay(ctx context.Context, ids ...uint64) (int, error) {
+	query := m.db.WithContext(ctx).Model(&Delivery{}).Where("status = ?", StatusFailed)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	updates := map[string]any{"status": StatusPending, "attempts": 0, "next_attempt_at": m.clock.Now()}
+	if err := query.Updates(updates).Error; err != nil {
+		return 0, fmt.Errorf("重置失败的 webhook 投递失败: %w", err)
+	}
+	return m.Process(ctx)
+}
+
+// Start 启动后台投递循环，按轮询间隔或 Notify 唤醒时调用 Process
+func (m *Manager) Start(ctx context.Context) {
+	ctx, m.stop = context.WithCancel(ctx)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.cfg.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-m.notify:
+			}
+			if _, err := m.Process(ctx); err != nil && ctx.Err() == nil {
+				logErrorf("webhook 投递失败: %v", err)
+			}
+		}
+	}()
+}
+
+// Stop 停止后台投递循环并等待当前批次结束
+func (m *Manager) Stop() {
+	if m.stop != nil {
+		m.stop()
+	}
+	m.wg.Wait()
+}
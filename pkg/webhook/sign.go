@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 投递请求头
+const (
+	HeaderID        = "X-Webhook-Id"        // 投递 ID，重试与重放时不变，接收方可据此去重
+	HeaderEvent     = "X-Webhook-Event"     // 事件名称
+	HeaderTimestamp = "X-Webhook-Timestamp" // 签名时间（Unix 秒）
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + HMAC-SHA256(密钥, 时间戳 + "." + 请求体) 的十六进制
+)
+
+var (
+	// ErrInvalidSignature 签名缺失或不匹配
+	ErrInvalidSignature = errors.New("webhook 签名无效")
+	// ErrExpiredTimestamp 签名时间超出允许的偏差，可能为重放请求
+	ErrExpiredTimestamp = errors.New("webhook 签名已过期")
+)
+
+// Sign 计算请求签名
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequest 供接收方校验请求签名并返回请求体（请求体可再次读取）；
+// tolerance 为签名时间与当前时间允许的偏差，0 表示不校验时间
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(r.Header.Get(HeaderSignature)), []byte(Sign(secret, ts, body))) {
+		return nil, ErrInvalidSignature
+	}
+	if tolerance > 0 && time.Since(time.Unix(ts, 0)).Abs() > tolerance {
+		return nil, ErrExpiredTimestamp
+	}
+	return body, nil
+}
@@ -0,0 +1,399 @@
+// Package webhook 提供出站 Webhook：按事件类型注册接收端点，Emit 在业务事务中为订阅的端点写入投递记录，
+// 后台循环以 HMAC-SHA256 签名 POST 到端点，失败时按指数退避重试，超过最大次数后标记为 failed，
+// 可通过 Replay、管理接口或 `app webhook:replay` 命令重新投递。每次请求的状态码、耗时与错误都记录在投递日志中。
+//
+//	m := webhook.Default()
+//	_ = m.Register(ctx, &webhook.Endpoint{URL: "https://example.com/hooks", Events: []string{"order.*"}})
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//	    if err := tx.Create(&order).Error; err != nil {
+//	        return err
+//	    }
+//	    return m.Emit(tx, "order.created", order)
+//	})
+//
+// 接收方以 VerifyRequest 校验签名，见 sign.go
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/database"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"github.com/gorilla-go/go-framework/pkg/httpclient"
+	"github.com/gorilla-go/go-framework/pkg/logger"
+	"github.com/gorilla-go/go-framework/pkg/request"
+	"gorm.io/gorm"
+)
+
+// 投递状态
+const (
+	StatusPending = "pending" // 待投递或等待重试
+	StatusSuccess = "success" // 端点返回 2xx
+	StatusFailed  = "failed"  // 超过最大投递次数，等待人工重放
+)
+
+// 事件名称
+const (
+	// EventDelivered 投递成功，参数为 *Delivery
+	EventDelivered = "webhook.delivered"
+	// EventFailed 投递最终失败（不再自动重试），参数为 *Delivery
+	EventFailed = "webhook.failed"
+)
+
+// DefaultTimeout 请求端点的默认超时
+const DefaultTimeout = 10 * time.Second
+
+// ErrNotFound 端点或投递记录不存在
+var ErrNotFound = errors.New("webhook 记录不存在")
+
+// Endpoint 接收 Webhook 的端点
+type Endpoint struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL         string    `gorm:"size:1024;not null" json:"url"`
+	Secret      string    `gorm:"size:128;not null" json:"-"`              // 签名密钥，Register 时为空则自动生成
+	Events      []string  `gorm:"type:text;serializer:json" json:"events"` // 订阅的事件：完整名称、"order.*"（前缀）或 "*"（全部）
+	Description string    `gorm:"size:255" json:"description"`
+	Active      bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (Endpoint) TableName() string {
+	return "webhook_endpoint"
+}
+
+// Subscribes 端点是否订阅了 event
+func (e *Endpoint) Subscribes(event string) bool {
+	for _, p := range e.Events {
+		if p == "*" || p == event || (strings.HasSuffix(p, ".*") && strings.HasPrefix(event, p[:len(p)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery 一次事件投递（投递日志），每次请求的结果见 Attempt
+type Delivery struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	EndpointID     uint64     `gorm:"not null;index" json:"endpoint_id"`
+	Event          string     `gorm:"size:191;not null;index" json:"event"`
+	Payload        string     `gorm:"type:text" json:"payload"` // 事件数据的 JSON
+	Status         string     `gorm:"size:16;not null;index" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	ResponseStatus int        `json:"response_status"` // 最近一次请求的 HTTP 状态码，请求未完成时为 0
+	LastError      string     `gorm:"type:text" json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+// TableName 表名
+func (Delivery) TableName() string {
+	return "webhook_delivery"
+}
+
+// Attempt 一次投递请求的记录
+type Attempt struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	DeliveryID   uint64    `gorm:"not null;index" json:"delivery_id"`
+	StatusCode   int       `json:"status_code"`
+	ResponseBody string    `gorm:"type:text" json:"response_body"` // 截断到 maxResponseBody 字节
+	Error        string    `gorm:"type:text" json:"error"`
+	Duration     int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (Attempt) TableName() string {
+	return "webhook_attempt"
+}
+
+// deliveryConfig 投递配置
+type deliveryConfig struct {
+	maxAttempts int
+	interval    time.Duration
+	batchSize   int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+// Option 配置选项
+type Option func(*Manager)
+
+// WithMaxAttempts 单个投递的最大请求次数，超过后标记为 failed（默认 6）
+func WithMaxAttempts(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.cfg.maxAttempts = n
+		}
+	}
+}
+
+// WithInterval 后台轮询间隔（默认 1s）
+func WithInterval(d time.Duration) Option {
+	return func(m *Manager) {
+		if d > 0 {
+			m.cfg.interval = d
+		}
+	}
+}
+
+// WithBatchSize 每次轮询最多投递的记录数（默认 100）
+func WithBatchSize(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.cfg.batchSize = n
+		}
+	}
+}
+
+// WithBackoff 重试间隔：第 n 次失败后等待 base×2^(n-1)，不超过 max（默认 30s、1h）
+func WithBackoff(base, max time.Duration) Option {
+	return func(m *Manager) {
+		if base > 0 {
+			m.cfg.backoff = base
+		}
+		if max > 0 {
+			m.cfg.maxBackoff = max
+		}
+	}
+}
+
+// WithHTTPClient 请求端点使用的 HTTP 客户端，默认为传递请求 ID、超时 DefaultTimeout 的客户端
+func WithHTTPClient(client *http.Client) Option {
+	return func(m *Manager) { m.client = client }
+}
+
+// WithClock 计算重试时间使用的时钟（默认全局时钟）
+func WithClock(clk clock.Clock) Option {
+	return func(m *Manager) { m.clock = clk }
+}
+
+// Manager Webhook 端点与投递管理
+type Manager struct {
+	db     *gorm.DB
+	bus    *eventbus.EventBus
+	client *http.Client
+	clock  clock.Clock
+	cfg    deliveryConfig
+
+	notify chan struct{}
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建管理器，bus 为 nil 时使用全局事件总线
+func New(db *gorm.DB, bus *eventbus.EventBus, opts ...Option) *Manager {
+	m := &Manager{
+		db:     db,
+		bus:    bus,
+		cfg:    deliveryConfig{maxAttempts: 6, interval: time.Second, batchSize: 100, backoff: 30 * time.Second, maxBackoff: time.Hour},
+		notify: make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.bus == nil {
+		m.bus = eventbus.Default()
+	}
+	if m.client == nil {
+		m.client = httpclient.New(DefaultTimeout)
+	}
+	if m.clock == nil {
+		m.clock = clock.Default()
+	}
+	return m
+}
+
+// Migrate 创建/更新端点、投递与请求记录表
+func (m *Manager) Migrate() error {
+	return m.db.AutoMigrate(&Endpoint{}, &Delivery{}, &Attempt{})
+}
+
+// Register 注册端点。URL 须为 http(s) 地址且至少订阅一个事件，Secret 为空时生成随机密钥（写回 ep.Secret）
+func (m *Manager) Register(ctx context.Context, ep *Endpoint) error {
+	u, err := url.Parse(ep.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("无效的 webhook 地址: %s", ep.URL)
+	}
+	if len(ep.Events) == 0 {
+		return errors.New("webhook 端点至少需要订阅一个事件")
+	}
+	if ep.Secret == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		ep.Secret = hex.EncodeToString(buf)
+	}
+	ep.Active = true
+	if err := m.db.WithContext(ctx).Create(ep).Error; err != nil {
+		return fmt.Errorf("保存 webhook 端点失败: %w", err)
+	}
+	return nil
+}
+
+// Endpoints 列出全部端点
+func (m *Manager) Endpoints(ctx context.Context) ([]Endpoint, error) {
+	var list []Endpoint
+	if err := m.db.WithContext(ctx).Order("id").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("读取 webhook 端点失败: %w", err)
+	}
+	return list, nil
+}
+
+// Endpoint 读取端点，不存在时返回 ErrNotFound
+func (m *Manager) Endpoint(ctx context.Context, id uint64) (*Endpoint, error) {
+	var ep Endpoint
+	err := m.db.WithContext(ctx).First(&ep, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取 webhook 端点失败: %w", err)
+	}
+	return &ep, nil
+}
+
+// SetActive 启用或停用端点，停用期间不再为其生成投递，未完成的投递在下次请求时标记为 failed
+func (m *Manager) SetActive(ctx context.Context, id uint64, active bool) error {
+	res := m.db.WithContext(ctx).Model(&Endpoint{}).Where("id = ?", id).Update("active", active)
+	if res.Error != nil {
+		return fmt.Errorf("更新 webhook 端点失败: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RemoveEndpoint 删除端点，已有的投递日志保留
+func (m *Manager) RemoveEndpoint(ctx context.Context, id uint64) error {
+	res := m.db.WithContext(ctx).Delete(&Endpoint{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("删除 webhook 端点失败: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Emit 在 tx 所在事务中为订阅 event 的启用端点写入投递记录；tx 为 nil 时使用管理器自身的连接。
+// payload 序列化为 JSON 作为请求体的 data 字段，投递在事务提交后由后台循环完成
+func (m *Manager) Emit(tx *gorm.DB, event string, payload any) error {
+	if tx == nil {
+		tx = m.db
+	}
+	var endpoints []Endpoint
+	if err := tx.Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		return fmt.Errorf("读取 webhook 端点失败: %w", err)
+	}
+	var deliveries []Delivery
+	for i := range endpoints {
+		if endpoints[i].Subscribes(event) {
+			deliveries = append(deliveries, Delivery{EndpointID: endpoints[i].ID, Event: event, Status: StatusPending})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 webhook %s 数据失败: %w", event, err)
+	}
+	now := m.clock.Now()
+	for i := range deliveries {
+		deliveries[i].Payload = string(data)
+		deliveries[i].NextAttemptAt = now
+	}
+	if err := tx.Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("写入 webhook %s 投递失败: %w", event, err)
+	}
+	m.Notify()
+	return nil
+}
+
+// Listen 将事件总线上的事件转发为 Webhook：单个参数直接作为 data，多个参数作为数组
+func (m *Manager) Listen(bus *eventbus.EventBus, events ...string) {
+	for _, event := range events {
+		bus.OnE(event, func(ctx context.Context, args ...interface{}) error {
+			meta, _ := eventbus.EventFromContext(ctx)
+			var payload any = args
+			if len(args) == 1 {
+				payload = args[0]
+			}
+			return m.Emit(m.db.WithContext(ctx), meta.Name, payload)
+		})
+	}
+}
+
+// Notify 唤醒后台投递循环，无需等待下一个轮询周期
+func (m *Manager) Notify() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Deliveries 按列表查询参数分页读取投递日志，q 的过滤与排序字段由调用方以白名单限定
+func (m *Manager) Deliveries(ctx context.Context, q *request.ListQuery) ([]Delivery, int64, error) {
+	var list []Delivery
+	total, err := database.Paginate(m.db.WithContext(ctx).Model(&Delivery{}), q, &list, "event")
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取 webhook 投递失败: %w", err)
+	}
+	return list, total, nil
+}
+
+// Delivery 读取投递及其请求记录（按时间顺序），不存在时返回 ErrNotFound
+func (m *Manager) Delivery(ctx context.Context, id uint64) (*Delivery, []Attempt, error) {
+	var d Delivery
+	err := m.db.WithContext(ctx).First(&d, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取 webhook 投递失败: %w", err)
+	}
+	var attempts []Attempt
+	if err := m.db.WithContext(ctx).Where("delivery_id = ?", id).Order("id").Find(&attempts).Error; err != nil {
+		return nil, nil, fmt.Errorf("读取 webhook 请求记录失败: %w", err)
+	}
+	return &d, attempts, nil
+}
+
+var defaultManager atomic.Pointer[Manager]
+
+// SetDefault 设置全局管理器（由 bootstrap 在 webhook.enabled 为 true 时调用）
+func SetDefault(m *Manager) {
+	defaultManager.Store(m)
+}
+
+// Default 返回全局管理器，未启用时返回 nil
+func Default() *Manager {
+	return defaultManager.Load()
+}
+
+// logErrorf 写入错误日志（日志未初始化时忽略，便于在命令行与测试中使用）
+func logErrorf(format string, args ...any) {
+	if logger.SugarLogger != nil {
+		logger.Errorf(format, args...)
+	}
+}
@@ -0,0 +1,304 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla-go/go-framework/pkg/clock"
+	"github.com/gorilla-go/go-framework/pkg/config"
+	"github.com/gorilla-go/go-framework/pkg/eventbus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// receiver 记录收到的请求并校验签名，status 为响应状态码
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	secret   string
+	requests []*http.Request
+	bodies   []envelope
+	t        *testing.T
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.secret != "" {
+		body, err := VerifyRequest(req, r.secret, time.Minute)
+		if err != nil {
+			r.t.Errorf("签名校验失败: %v", err)
+		}
+		var env envelope
+		_ = json.Unmarshal(body, &env)
+		r.bodies = append(r.bodies, env)
+	}
+	r.requests = append(r.requests, req)
+	w.WriteHeader(r.status)
+	w.Write([]byte(`{"ok":false}`))
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func newTestManager(t *testing.T, opts ...Option) (*Manager, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	m := New(db, eventbus.New(), opts...)
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	return m, db
+}
+
+func TestEmitAndDeliver(t *testing.T) {
+	clk := clock.NewFake(time.Now().UTC().Truncate(time.Second))
+	m, db := newTestManager(t, WithClock(clk))
+	ctx := context.Background()
+
+	rcv := &receiver{status: http.StatusOK, secret: "s3cret", t: t}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	orders := &Endpoint{URL: srv.URL, Events: []string{"order.*"}, Secret: "s3cret"}
+	users := &Endpoint{URL: srv.URL + "/users", Events: []string{"user.created"}}
+	for _, ep := range []*Endpoint{orders, users} {
+		if err := m.Register(ctx, ep); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(users.Secret) != 48 {
+		t.Errorf("自动生成的密钥 = %q", users.Secret)
+	}
+	if err := m.Register(ctx, &Endpoint{URL: "ftp://example.com", Events: []string{"*"}}); err == nil {
+		t.Error("非 http(s) 地址应返回错误")
+	}
+
+	var delivered []*Delivery
+	m.bus.On(EventDelivered, func(args ...interface{}) { delivered = append(delivered, args[0].(*Delivery)) })
+
+	// 回滚的事务不应留下投递
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Emit(tx, "order.created", map[string]int{"id": 1}); err != nil {
+			t.Fatal(err)
+		}
+		return errors.New("rollback")
+	})
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return m.Emit(tx, "order.created", map[string]int{"id": 42})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := m.Process(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Process = %d, %v; want 1, nil", n, err)
+	}
+	if rcv.count() != 1 {
+		t.Fatalf("收到 %d 个请求", rcv.count())
+	}
+	req, env := rcv.requests[0], rcv.bodies[0]
+	if req.Header.Get(HeaderEvent) != "order.created" || req.Header.Get(HeaderID) != strconv.FormatUint(env.ID, 10) {
+		t.Errorf("headers = %v", req.Header)
+	}
+	if env.Event != "order.created" || string(env.Data) != `{"id":42}` {
+		t.Errorf("body = %+v", env)
+	}
+
+	d, attempts, err := m.Delivery(ctx, env.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Status != StatusSuccess || d.Attempts != 1 || d.ResponseStatus != 200 || d.DeliveredAt == nil {
+		t.Errorf("delivery = %+v", d)
+	}
+	if len(attempts) != 1 || attempts[0].StatusCode != 200 || attempts[0].ResponseBody != `{"ok":false}` {
+		t.Errorf("attempts = %+v", attempts)
+	}
+	if len(delivered) != 1 || delivered[0].ID != d.ID {
+		t.Errorf("delivered events = %v", delivered)
+	}
+	if _, _, err := m.Delivery(ctx, 999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("不存在的投递 err = %v", err)
+	}
+}
+
+func TestRetryAndReplay(t *testing.T) {
+	clk := clock.NewFake(time.Now().UTC().Truncate(time.Second))
+	m, _ := newTestManager(t, WithClock(clk), WithMaxAttempts(3), WithBackoff(10*time.Second, 15*time.Second))
+	ctx := context.Background()
+
+	rcv := &receiver{status: http.StatusInternalServerError, t: t}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	if err := m.Register(ctx, &Endpoint{URL: srv.URL, Events: []string{"*"}}); err != nil {
+		t.Fatal(err)
+	}
+	var failed int
+	m.bus.On(EventFailed, func(args ...interface{}) { failed++ })
+
+	if err := m.Emit(nil, "invoice.paid", "INV-1"); err != nil {
+		t.Fatal(err)
+	}
+	next := func() time.Time {
+		d, _, _ := m.Delivery(ctx, 1)
+		return d.NextAttemptAt
+	}
+
+	// 第 1 次失败后等待 10s，第 2 次失败后等待 min(20s, 15s)
+	for i, wait := range []time.Duration{10 * time.Second, 15 * time.Second} {
+		if n, err := m.Process(ctx); err != nil || n != 0 {
+			t.Fatalf("Process = %d, %v", n, err)
+		}
+		if got := next().Sub(clk.Now()); got != wait {
+			t.Errorf("第 %d 次失败后等待 %v，want %v", i+1, got, wait)
+		}
+		_, _ = m.Process(ctx) // 未到重试时间
+		if rcv.count() != i+1 {
+			t.Fatalf("收到 %d 个请求，want %d", rcv.count(), i+1)
+		}
+		clk.Advance(wait)
+	}
+
+	_, _ = m.Process(ctx)
+	d, attempts, _ := m.Delivery(ctx, 1)
+	if d.Status != StatusFailed || d.Attempts != 3 || d.ResponseStatus != 500 || failed != 1 {
+		t.Fatalf("delivery = %+v failed = %d", d, failed)
+	}
+	if len(attempts) != 3 || attempts[2].Error == "" {
+		t.Errorf("attempts = %+v", attempts)
+	}
+
+	rcv.status = http.StatusNoContent
+	n, err := m.Replay(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Replay = %d, %v", n, err)
+	}
+	if d, _, _ := m.Delivery(ctx, 1); d.Status != StatusSuccess || d.LastError != "" {
+		t.Errorf("重放后 delivery = %+v", d)
+	}
+}
+
+func TestInactiveEndpoint(t *testing.T) {
+	m, _ := newTestManager(t)
+	ctx := context.Background()
+
+	rcv := &receiver{status: http.StatusOK, t: t}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	ep := &Endpoint{URL: srv.URL, Events: []string{"order.created"}}
+	if err := m.Register(ctx, ep); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Emit(nil, "order.created", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetActive(ctx, ep.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	// 停用的端点不再生成投递，已有投递直接失败
+	if err := m.Emit(nil, "order.created", 2); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = m.Process(ctx)
+	list, _ := m.Endpoints(ctx)
+	d, _, _ := m.Delivery(ctx, 1)
+	if rcv.count() != 0 || d.Status != StatusFailed || len(list) != 1 || list[0].Active {
+		t.Errorf("requests = %d delivery = %+v endpoints = %+v", rcv.count(), d, list)
+	}
+	if _, _, err := m.Delivery(ctx, 2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("停用后仍生成了投递: %v", err)
+	}
+
+	if err := m.RemoveEndpoint(ctx, ep.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RemoveEndpoint(ctx, ep.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("重复删除 err = %v", err)
+	}
+}
+
+func TestListen(t *testing.T) {
+	m, _ := newTestManager(t)
+	ctx := context.Background()
+	if err := m.Register(ctx, &Endpoint{URL: "http://127.0.0.1:1", Events: []string{"user.*"}}); err != nil {
+		t.Fatal(err)
+	}
+	bus := eventbus.New()
+	m.Listen(bus, "user.*")
+	if err := bus.EmitCtx(ctx, "user.created", map[string]string{"name": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	d, _, err := m.Delivery(ctx, 1)
+	if err != nil || d.Event != "user.created" || d.Payload != `{"name":"alice"}` {
+		t.Errorf("delivery = %+v, %v", d, err)
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	now := time.Now().Unix()
+	newReq := func(ts int64, sig string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		r.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		r.Header.Set(HeaderSignature, sig)
+		return r
+	}
+
+	r := newReq(now, Sign("k", now, body))
+	if got, err := VerifyRequest(r, "k", time.Minute); err != nil || string(got) != string(body) {
+		t.Errorf("VerifyRequest = %s, %v", got, err)
+	}
+	if _, err := VerifyRequest(newReq(now, Sign("other", now, body)), "k", time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("错误密钥 err = %v", err)
+	}
+	old := now - 600
+	if _, err := VerifyRequest(newReq(old, Sign("k", old, body)), "k", time.Minute); !errors.Is(err, ErrExpiredTimestamp) {
+		t.Errorf("过期签名 err = %v", err)
+	}
+	if _, err := VerifyRequest(newReq(old, Sign("k", old, body)), "k", 0); err != nil {
+		t.Errorf("不校验时间 err = %v", err)
+	}
+}
+
+func TestSubscribes(t *testing.T) {
+	ep := &Endpoint{Events: []string{"order.*", "user.created"}}
+	for event, want := range map[string]bool{
+		"order.created": true, "order.item.added": true, "orders.created": false,
+		"user.created": true, "user.deleted": false,
+	} {
+		if got := ep.Subscribes(event); got != want {
+			t.Errorf("Subscribes(%q) = %v", event, got)
+		}
+	}
+	if !(&Endpoint{Events: []string{"*"}}).Subscribes("anything") {
+		t.Error("* 应订阅全部事件")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	m := NewFromConfig(&config.WebhookConfig{MaxAttempts: 2, Timeout: 3, Backoff: 5, MaxBackoff: 60}, nil, nil)
+	if m.cfg.maxAttempts != 2 || m.client.Timeout != 3*time.Second || m.cfg.backoff != 5*time.Second ||
+		m.cfg.maxBackoff != time.Minute || m.cfg.batchSize != 100 {
+		t.Errorf("cfg = %+v timeout = %v", m.cfg, m.client.Timeout)
+	}
+}
@@ -8,6 +8,8 @@ import (
 func init() {
 	router.RegisterControllers(
 		&controller.IndexController{},
+		&controller.SessionAdminController{}, // GET/DELETE /admin/sessions/:user_id[/:id]
+		&controller.WebhookAdminController{}, // /admin/webhooks/endpoints|deliveries[/:id[/replay]]
 
 		// 演示控制器
 		&controller.DemoAPIController{},   // GET/POST/DELETE /demo/api/users[/:id]
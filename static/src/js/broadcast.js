@@ -0,0 +1,151 @@
+/**
+ * 实时广播客户端
+ * 对应 pkg/broadcast 的 WebSocket 协议，断线自动重连并恢复订阅
+ *
+ *   const bc = new Broadcast();                     // 默认连接同源的 /broadcast
+ *   bc.subscribe('private-orders.42')
+ *     .on('order.shipped', data => console.log(data))
+ *     .error(message => console.warn(message));    // 无权订阅等错误
+ */
+(function(global) {
+    'use strict';
+
+    const PING_INTERVAL = 25000;
+    const MAX_RETRY_DELAY = 30000;
+
+    /**
+     * 频道订阅，on 注册事件回调
+     */
+    class Subscription {
+        constructor(client, name) {
+            this.client = client;
+            this.name = name;
+            this.handlers = {};
+            this.errorHandlers = [];
+        }
+
+        /**
+         * 监听频道事件，event 为 "*" 时接收全部事件（回调参数为 data, event）
+         */
+        on(event, fn) {
+            (this.handlers[event] = this.handlers[event] || []).push(fn);
+            return this;
+        }
+
+        /**
+         * 订阅失败（如无权订阅私有频道）时回调
+         */
+        error(fn) {
+            this.errorHandlers.push(fn);
+            return this;
+        }
+
+        /**
+         * 退订频道
+         */
+        unsubscribe() {
+            this.client.unsubscribe(this.name);
+        }
+
+        dispatch(event, data) {
+            (this.handlers[event] || []).concat(this.handlers['*'] || []).forEach(fn => fn(data, event));
+        }
+    }
+
+    /**
+     * WebSocket 连接与频道管理
+     */
+    class Broadcast {
+        /**
+         * @param {string} [url] WebSocket 地址，默认为同源的 /broadcast
+         */
+        constructor(url) {
+            const scheme = location.protocol === 'https:' ? 'wss:' : 'ws:';
+            this.url = url || scheme + '//' + location.host + '/broadcast';
+            this.channels = {};
+            this.socketId = null;
+            this.retries = 0;
+            this.closed = false;
+            this.connect();
+        }
+
+        connect() {
+            this.ws = new WebSocket(this.url);
+            this.ws.onopen = () => {
+                this.retries = 0;
+                Object.keys(this.channels).forEach(name => this.send({ type: 'subscribe', channel: name }));
+                this.pinger = setInterval(() => this.send({ type: 'ping' }), PING_INTERVAL);
+            };
+            this.ws.onmessage = e => this.handle(JSON.parse(e.data));
+            this.ws.onclose = () => {
+                clearInterval(this.pinger);
+                this.socketId = null;
+                if (this.closed) {
+                    return;
+                }
+                // 指数退避重连：1s、2s、4s……最长 30s
+                const delay = Math.min(1000 * Math.pow(2, this.retries++), MAX_RETRY_DELAY);
+                setTimeout(() => this.connect(), delay);
+            };
+        }
+
+        handle(msg) {
+            const sub = this.channels[msg.channel];
+            switch (msg.type) {
+                case 'connected':
+                    this.socketId = msg.data.socket_id;
+                    break;
+                case 'event':
+                    if (sub) {
+                        sub.dispatch(msg.event, msg.data);
+                    }
+                    break;
+                case 'error':
+                    if (sub) {
+                        sub.errorHandlers.forEach(fn => fn(msg.data));
+                        delete this.channels[msg.channel];
+                    } else {
+                        console.warn('broadcast:', msg.data);
+                    }
+                    break;
+            }
+        }
+
+        send(msg) {
+            if (this.ws.readyState === WebSocket.OPEN) {
+                this.ws.send(JSON.stringify(msg));
+            }
+        }
+
+        /**
+         * 订阅频道，重复订阅返回同一个 Subscription
+         */
+        subscribe(name) {
+            if (!this.channels[name]) {
+                this.channels[name] = new Subscription(this, name);
+                this.send({ type: 'subscribe', channel: name });
+            }
+            return this.channels[name];
+        }
+
+        /**
+         * 退订频道
+         */
+        unsubscribe(name) {
+            if (this.channels[name]) {
+                delete this.channels[name];
+                this.send({ type: 'unsubscribe', channel: name });
+            }
+        }
+
+        /**
+         * 关闭连接，不再重连
+         */
+        close() {
+            this.closed = true;
+            this.ws.close();
+        }
+    }
+
+    global.Broadcast = Broadcast;
+})(window);
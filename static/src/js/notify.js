@@ -0,0 +1,59 @@
+/**
+ * 通知提示
+ * 配合 templates/partials/toasts.html：为闪存的通知设置自动关闭，并经 SSE 接收 pkg/notify 推送的通知
+ */
+(function() {
+    'use strict';
+
+    const DISMISS_AFTER = 5000;
+    // 多实例部署时离线通知可能在多个实例上暂存，按通知 ID 去重
+    const seen = new Set();
+
+    /**
+     * 设置提示框点击关闭与定时关闭
+     */
+    function arm(toast) {
+        const dismiss = () => {
+            toast.classList.add('hide');
+            setTimeout(() => toast.remove(), 300);
+        };
+        toast.addEventListener('click', dismiss);
+        setTimeout(dismiss, DISMISS_AFTER);
+    }
+
+    /**
+     * 添加一条通知
+     */
+    function show(container, n) {
+        if (seen.has(n.id)) {
+            return;
+        }
+        seen.add(n.id);
+        const toast = document.createElement('div');
+        toast.className = 'toast toast-' + n.level;
+        toast.setAttribute('role', 'status');
+        toast.dataset.id = n.id;
+        toast.textContent = n.message;
+        container.appendChild(toast);
+        arm(toast);
+    }
+
+    document.addEventListener('DOMContentLoaded', function() {
+        const container = document.getElementById('toasts');
+        if (!container) {
+            return;
+        }
+        container.querySelectorAll('.toast').forEach(toast => {
+            seen.add(toast.dataset.id);
+            arm(toast);
+        });
+
+        const stream = container.dataset.stream;
+        if (!stream || !window.EventSource) {
+            return;
+        }
+        // 断线后浏览器按服务端的 retry 自动重连；未登录（401）时不再重连
+        const source = new EventSource(stream);
+        source.addEventListener('notify', e => show(container, JSON.parse(e.data)));
+    });
+})();
@@ -0,0 +1,24 @@
+// Vite 构建配置，与 config.yaml 中的 vite.* 保持一致：
+//   - base 对应 vite.base（生产环境构建产物 URL 前缀）
+//   - build.manifest 生成 dist/.vite/manifest.json，对应 vite.manifest
+// 模板中使用 {{ vite "src/js/main.js" }} 引入入口。
+import { defineConfig } from 'vite';
+
+export default defineConfig(({ command }) => ({
+  // 开启 vite.proxy 时开发服务器经由 /__vite 同源代理访问
+  base: command === 'serve' ? (process.env.VITE_PROXY ? '/__vite/' : '/') : '/static/',
+  server: {
+    port: 5173,
+    strictPort: true,
+    origin: process.env.VITE_PROXY ? undefined : 'http://localhost:5173',
+  },
+  build: {
+    outDir: 'dist',
+    // 与 Gulp 产物共用 dist 目录，构建时不清空
+    emptyOutDir: false,
+    manifest: true,
+    rollupOptions: {
+      input: ['src/js/main.js'],
+    },
+  },
+}));